@@ -7,6 +7,7 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/keycloak/keycloak-operator/pkg/k8sutil"
 
@@ -25,6 +26,7 @@ import (
 
 	"github.com/keycloak/keycloak-operator/pkg/apis"
 	"github.com/keycloak/keycloak-operator/pkg/controller"
+	"github.com/keycloak/keycloak-operator/pkg/webhook"
 
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	grafanav1alpha1 "github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
@@ -50,6 +52,13 @@ var (
 	metricsPort         int32 = 8383
 	operatorMetricsPort int32 = 8686
 )
+
+// Directory holding the tls.crt and tls.key the webhooks are served with
+const webhookCertDirEnv = "WEBHOOK_CERT_DIR"
+
+// How often all watched resources are reconciled again, the default of controller-runtime
+const defaultResyncPeriod = 10 * time.Hour
+
 var log = logf.Log.WithName("cmd")
 
 func printVersion() {
@@ -64,6 +73,9 @@ func main() {
 	// be added before calling pflag.Parse().
 	pflag.CommandLine.AddFlagSet(zap.FlagSet())
 
+	resyncPeriod := pflag.Duration("resync-period", defaultResyncPeriod,
+		"How often all watched resources are reconciled again. Realms, clients and users with a resyncPeriod are reconciled more often")
+
 	// Add flags registered by imported packages (e.g. glog and
 	// controller-runtime)
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
@@ -108,6 +120,13 @@ func main() {
 	options := manager.Options{
 		Namespace:          namespace,
 		MetricsBindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
+		SyncPeriod:         resyncPeriod,
+	}
+
+	// Webhooks need a serving certificate, they are only started when one is provided
+	webhookCertDir, webhooksEnabled := os.LookupEnv(webhookCertDirEnv)
+	if webhooksEnabled {
+		options.CertDir = webhookCertDir
 	}
 
 	// Add support for MultiNamespace set in WATCH_NAMESPACE (e.g ns1,ns2)
@@ -166,6 +185,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Setup all Webhooks
+	if webhooksEnabled {
+		if err := webhook.AddToManager(mgr); err != nil {
+			log.Error(err, "")
+			os.Exit(1)
+		}
+	}
+
 	// Add the Metrics Service
 	addMetrics(ctx, cfg)
 
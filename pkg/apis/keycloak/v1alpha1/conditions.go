@@ -0,0 +1,73 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Types of the conditions reported on the status of resources
+const (
+	// The resource is in the desired state in keycloak
+	ConditionReady = "Ready"
+	// The operator is still working towards the desired state, e.g. waiting for keycloak
+	ConditionReconciling = "Reconciling"
+	// The last reconcile failed and won't succeed unless something changes
+	ConditionError = "Error"
+)
+
+// Reasons of the conditions reported on the status of resources
+const (
+	ReasonReconciled          = "Reconciled"
+	ReasonActionFailed        = "ActionFailed"
+	ReasonReconcileFailed     = "ReconcileFailed"
+	ReasonKeycloakUnavailable = "KeycloakUnavailable"
+)
+
+// Condition mirrors the fields of the upstream metav1.Condition, which isn't
+// available in the Kubernetes version the operator is built against
+type Condition struct {
+	// Type of the condition, e.g. Ready.
+	Type string `json:"type"`
+	// Status of the condition, one of True, False or Unknown.
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status metav1.ConditionStatus `json:"status"`
+	// Generation of the resource the condition was set for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Last time the status of the condition changed.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	// Reason of the last transition, in CamelCase.
+	Reason string `json:"reason"`
+	// Human-readable message about the transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// SetCondition adds the condition or updates the one of the same type. The time
+// of the last transition is only moved when the status changes
+func SetCondition(conditions *[]Condition, condition Condition) {
+	for i := range *conditions {
+		existing := &(*conditions)[i]
+		if existing.Type != condition.Type {
+			continue
+		}
+		if existing.Status != condition.Status {
+			existing.Status = condition.Status
+			existing.LastTransitionTime = condition.LastTransitionTime
+		}
+		existing.ObservedGeneration = condition.ObservedGeneration
+		existing.Reason = condition.Reason
+		existing.Message = condition.Message
+		return
+	}
+	*conditions = append(*conditions, condition)
+}
+
+// FindCondition returns the condition of the given type, nil if it isn't set
+func FindCondition(conditions []Condition, conditionType string) *Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 type TLSTerminationType string
@@ -13,6 +14,13 @@ var (
 	PassthroughTLSTerminationType TLSTerminationType = "passthrough"
 )
 
+type DiscoveryProtocol string
+
+var (
+	DNSPingDiscoveryProtocol  DiscoveryProtocol = "dns.DNS_PING"
+	KubePingDiscoveryProtocol DiscoveryProtocol = "kubernetes.KUBE_PING"
+)
+
 // KeycloakSpec defines the desired state of Keycloak.
 // +k8s:openapi-gen=true
 type KeycloakSpec struct {
@@ -20,16 +28,84 @@ type KeycloakSpec struct {
 	// It can then be used for targeting purposes.
 	// +optional
 	Unmanaged bool `json:"unmanaged,omitempty"`
+	// When set to true, the realms, clients, client scopes and users of this Keycloak are not changed.
+	// The actions that would be taken are logged and counted in the status of each resource instead.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
 	// Contains configuration for external Keycloak instances. Unmanaged needs to be set to true to use this.
 	// +optional
 	External KeycloakExternal `json:"external"`
+	// Timeout in seconds for requests to the Keycloak admin API, including establishing the
+	// connection. Default is 10.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	AdminClientTimeoutSeconds int `json:"adminClientTimeoutSeconds,omitempty"`
+	// Number of times a request to the Keycloak admin API is retried, with exponential
+	// backoff, when Keycloak responds with a 5xx status. Only GET, PUT and DELETE requests
+	// are retried. Default is 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	AdminClientMaxRetries int `json:"adminClientMaxRetries,omitempty"`
+	// Number of entries requested at once when listing the clients, roles, groups and
+	// users of a realm from the Keycloak admin API. Default is 100.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	AdminClientPageSize int `json:"adminClientPageSize,omitempty"`
+	// Proxy the requests to the Keycloak admin API go through. The HTTP_PROXY, HTTPS_PROXY
+	// and NO_PROXY environment variables of the Operator are used when not set.
+	// +optional
+	AdminClientProxy *KeycloakAdminClientProxy `json:"adminClientProxy,omitempty"`
+	// Skip verifying the TLS certificates of Keycloak and of a HTTPS proxy in front of it.
+	// Default is false when adminClientCACertificate is set, true otherwise. Set it to
+	// false to verify the certificates of external instances against the system CAs.
+	// +optional
+	AdminClientInsecureSkipVerify *bool `json:"adminClientInsecureSkipVerify,omitempty"`
+	// CA certificates, PEM encoded, the TLS certificates of Keycloak are verified against
+	// in addition to the system ones.
+	// +optional
+	AdminClientCACertificate *KeycloakAdminClientCACertificate `json:"adminClientCACertificate,omitempty"`
+	// Period after which the password of the admin user created by the Operator is
+	// regenerated, e.g. "720h". The new password is set in Keycloak and written to the
+	// admin credentials Secret. Annotating the Keycloak with
+	// keycloak.org/rotate-admin-credentials rotates it right away.
+	// +optional
+	AdminCredentialsRotationPeriod *metav1.Duration `json:"adminCredentialsRotationPeriod,omitempty"`
+	// Existing Secret holding the admin credentials, e.g. one provisioned by an external
+	// secret manager. The Operator then doesn't create the credential-<name> Secret
+	// and never rotates the password.
+	// +optional
+	AdminCredentialsRef *KeycloakAdminCredentialsRef `json:"adminCredentialsRef,omitempty"`
+	// Secret holding the client ID and secret of a service account client in the master
+	// realm that the Operator authenticates with, through the client credentials grant,
+	// instead of the admin user. This lets the Operator work with only the roles it needs,
+	// see the README for them.
+	// +optional
+	AdminClientCredentialsRef *KeycloakAdminClientCredentialsRef `json:"adminClientCredentialsRef,omitempty"`
 	// A list of extensions, where each one is a URL to a JAR files that will be deployed in Keycloak.
 	// +listType=set
 	// +optional
 	Extensions []string `json:"extensions,omitempty"`
-	// Number of Keycloak instances in HA mode. Default is 1.
+	// Number of Keycloak instances in HA mode. Default is 1. Ignored when autoscaling is set.
 	// +optional
 	Instances int `json:"instances,omitempty"`
+	// When set, a HorizontalPodAutoscaler scales the Keycloak instances instead.
+	// +optional
+	Autoscaling *KeycloakAutoscaling `json:"autoscaling,omitempty"`
+	// How the Keycloak instances find each other and share their caches. The instances
+	// always form a cluster, these settings only matter with more than one of them.
+	// +optional
+	Clustering *KeycloakClustering `json:"clustering,omitempty"`
+	// Keeps the sessions and other volatile data of Keycloak in a remote Infinispan
+	// instead of the embedded caches only, so they survive restarts of all
+	// instances. Only used by Keycloak, not RH-SSO.
+	// +optional
+	RemoteCache *KeycloakRemoteCache `json:"remoteCache,omitempty"`
+	// Public URLs Keycloak generates links and tokens for, e.g. when it sits behind
+	// a proxy or a load balancer it doesn't know the address of. Only used by
+	// Keycloak, not RH-SSO. Only supported by the WildFly distribution of Keycloak, the
+	// reconcile fails once Keycloak 20 or later is detected, which only comes as Quarkus.
+	// +optional
+	Hostname *KeycloakHostname `json:"hostname,omitempty"`
 	// Controls external Ingress/Route settings.
 	// +optional
 	ExternalAccess KeycloakExternalAccess `json:"externalAccess,omitempty"`
@@ -67,6 +143,10 @@ type KeycloakSpec struct {
 	// Specify PodDisruptionBudget configuration.
 	// +optional
 	PodDisruptionBudget PodDisruptionBudgetConfig `json:"podDisruptionBudget,omitempty"`
+	// Specify ServiceMonitor configuration. The ServiceMonitor is only created when the
+	// Prometheus Operator is installed on the cluster.
+	// +optional
+	ServiceMonitor *ServiceMonitorConfig `json:"serviceMonitor,omitempty"`
 	// Resources (Requests and Limits) for KeycloakDeployment.
 	// +optional
 	KeycloakDeploymentSpec KeycloakDeploymentSpec `json:"keycloakDeploymentSpec,omitempty"`
@@ -79,16 +159,191 @@ type KeycloakSpec struct {
 	// Name of the StorageClass for Postgresql Persistent Volume Claim
 	// +optional
 	StorageClassName *string `json:"storageClassName,omitempty"`
+	// Labels added to the resources the Operator creates for this Keycloak. Labels
+	// set by the Operator itself take precedence.
+	// +optional
+	ResourceLabels map[string]string `json:"resourceLabels,omitempty"`
+	// Annotations added to the resources the Operator creates for this Keycloak.
+	// Annotations set by the Operator itself take precedence.
+	// +optional
+	ResourceAnnotations map[string]string `json:"resourceAnnotations,omitempty"`
+	// Labels added to the Keycloak and Postgresql Pods.
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+	// Annotations added to the Keycloak and Postgresql Pods.
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+	// Namespaces whose KeycloakRealms may select this Keycloak through their instanceSelector,
+	// besides the namespace of the Keycloak itself. "*" allows all namespaces, as does leaving
+	// it unset. Realms of other namespaces that select the Keycloak fail to reconcile. The
+	// Operator has to watch these namespaces, see WATCH_NAMESPACE.
+	// +optional
+	RealmNamespaces []string `json:"realmNamespaces,omitempty"`
+}
+
+type KeycloakClustering struct {
+	// JGroups discovery protocol. dns.DNS_PING, the default, looks up the instances through
+	// the headless discovery Service. kubernetes.KUBE_PING asks the Kubernetes API for the
+	// Pods instead, their service account needs to be allowed to get and list Pods, see
+	// deploy/examples/keycloak/keycloak-kube-ping.yaml.
+	// +kubebuilder:validation:Enum=dns.DNS_PING;kubernetes.KUBE_PING
+	// +optional
+	DiscoveryProtocol DiscoveryProtocol `json:"discoveryProtocol,omitempty"`
+	// Number of instances holding each entry of the distributed caches, e.g. sessions.
+	// Sessions survive the loss of one instance less than this. Default is 2.
+	// Only used by Keycloak, not RH-SSO.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	CacheOwners *int32 `json:"cacheOwners,omitempty"`
+	// Number of instances holding each entry of the authentication sessions cache.
+	// Default is 2. Only used by Keycloak, not RH-SSO.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	AuthSessionsCacheOwners *int32 `json:"authSessionsCacheOwners,omitempty"`
+	// Service account the Keycloak Pods run as, the default one of the namespace when
+	// not set.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+type KeycloakAdminClientProxy struct {
+	// URL of the proxy, e.g. http://proxy:3128 or socks5://proxy:1080.
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+	// Hosts reached without the proxy. An entry matches the host itself and, when
+	// starting with a dot, its subdomains. A single * disables the proxy.
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
+}
+
+type KeycloakAdminClientCACertificate struct {
+	// Key of a ConfigMap holding the certificates.
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// Key of a Secret holding the certificates.
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+type KeycloakAdminCredentialsRef struct {
+	// Name of the Secret in the namespace of the Keycloak CR.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Key of the username in the Secret. Default is ADMIN_USERNAME.
+	// +optional
+	UsernameKey string `json:"usernameKey,omitempty"`
+	// Key of the password in the Secret. Default is ADMIN_PASSWORD.
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+}
+
+type KeycloakAdminClientCredentialsRef struct {
+	// Name of the Secret in the namespace of the Keycloak CR.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Key of the client ID in the Secret. Default is CLIENT_ID.
+	// +optional
+	ClientIDKey string `json:"clientIdKey,omitempty"`
+	// Key of the client secret in the Secret. Default is CLIENT_SECRET.
+	// +optional
+	ClientSecretKey string `json:"clientSecretKey,omitempty"`
+}
+
+type KeycloakRemoteCache struct {
+	// Hot Rod endpoints of the Infinispan servers as host:port. The port defaults
+	// to 11222.
+	// +kubebuilder:validation:MinItems=1
+	Hosts []string `json:"hosts"`
+	// Name of a Secret in the namespace of the Keycloak CR holding the username and
+	// password keys Keycloak authenticates to Infinispan with.
+	// +optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+	// Connect to Infinispan over TLS. The server certificate has to be trusted by
+	// the JVM of the Keycloak image.
+	// +optional
+	TLSEnabled bool `json:"tlsEnabled,omitempty"`
+	// Server name sent with the TLS handshake, e.g. when Infinispan sits behind a
+	// Route or Ingress. Implies TLSEnabled.
+	// +optional
+	SNIHostName string `json:"sniHostName,omitempty"`
+}
+
+type KeycloakHostname struct {
+	// Frontend URL of Keycloak, either a full URL or a host name which stands for
+	// https://<hostname>/auth. Used for the URLs that browsers and applications are
+	// sent to, and as the issuer of tokens.
+	Hostname string `json:"hostname"`
+	// URL of the admin console and the admin REST API, if it is reachable at another
+	// address than the frontend URL, e.g. on an internal network only. Either a full
+	// URL or a host name which stands for https://<hostname>/auth.
+	// +optional
+	HostnameAdmin string `json:"hostnameAdmin,omitempty"`
+	// Use the frontend URL for the requests of applications to Keycloak as well,
+	// instead of the URL they reached Keycloak at. Without it applications on the
+	// cluster can use the internal URL for the back channel.
+	// +optional
+	Strict bool `json:"strict,omitempty"`
 }
 
 type DeploymentSpec struct {
-	// Resources (Requests and Limits) for the Pods.
+	// Resources (Requests and Limits) for the Pods. CPU and memory not set here
+	// fall back to defaults of the Operator.
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 type KeycloakDeploymentSpec struct {
 	DeploymentSpec `json:",inline"`
+	// Image of the Keycloak container, including its tag, e.g. to pull it from a mirror.
+	// If unspecified, the image the Operator was built for is used.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Pull policy of the Keycloak image.
+	// +optional
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// Secrets used to pull the images of the Keycloak Pods.
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// Node selector for the Keycloak Pods.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations of the Keycloak Pods.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity of the Keycloak Pods. If unspecified, the replicas are preferably
+	// spread across nodes and zones.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Init containers added to the Keycloak Pods, they run after the one installing
+	// the extensions.
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+	// Containers added to the Keycloak Pods next to Keycloak.
+	// +optional
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+	// Volumes added to the Keycloak Pods, e.g. to share files between Keycloak and
+	// the init containers or sidecars, or to mount themes and providers. Changes to
+	// the ConfigMaps and Secrets of these volumes roll the Keycloak Pods.
+	// +optional
+	ExtraVolumes []corev1.Volume `json:"extraVolumes,omitempty"`
+	// Volume mounts added to the Keycloak container.
+	// +optional
+	ExtraVolumeMounts []corev1.VolumeMount `json:"extraVolumeMounts,omitempty"`
+	// Timings of the liveness probe of Keycloak, settings not given keep their defaults.
+	// +optional
+	LivenessProbe *KeycloakProbe `json:"livenessProbe,omitempty"`
+	// Timings of the readiness probe of Keycloak, settings not given keep their defaults.
+	// +optional
+	ReadinessProbe *KeycloakProbe `json:"readinessProbe,omitempty"`
+	// Timings of the startup probe of Keycloak, which holds back the liveness probe until
+	// Keycloak started. Settings not given keep their defaults.
+	// +optional
+	StartupProbe *KeycloakProbe `json:"startupProbe,omitempty"`
+	// JVM settings of Keycloak, passed on to the container as environment variables.
+	// Any other variable can be set in experimental.env, which takes precedence.
+	// +optional
+	JVMOptions *KeycloakJVMOptions `json:"jvmOptions,omitempty"`
 	// Experimental section
 	// NOTE: This section might change or get removed without any notice. It may also cause
 	// the deployment to behave in an unpredictable fashion. Please use with care.
@@ -96,6 +351,42 @@ type KeycloakDeploymentSpec struct {
 	Experimental ExperimentalSpec `json:"experimental,omitempty"`
 }
 
+type KeycloakProbe struct {
+	// Number of seconds after the container has started before the probe is initiated.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+	// How often, in seconds, to perform the probe.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+	// Number of seconds after which the probe times out.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// Number of consecutive failures after which the probe is considered failed.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+type KeycloakJVMOptions struct {
+	// Initial heap size, e.g. 512m. Setting it disables the default sizing of the
+	// initial heap relative to the container memory.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+[kKmMgG]?$`
+	InitialHeapSize string `json:"initialHeapSize,omitempty"`
+	// Maximum heap size, e.g. 2g. Setting it disables the default sizing of the
+	// heap relative to the container memory.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+[kKmMgG]?$`
+	MaxHeapSize string `json:"maxHeapSize,omitempty"`
+	// Additional JVM options, e.g. garbage collector flags, appended to the defaults
+	// of the image.
+	// +optional
+	Options []string `json:"options,omitempty"`
+}
+
 type PostgresqlDeploymentSpec struct {
 	DeploymentSpec `json:",inline"`
 }
@@ -143,6 +434,9 @@ type VolumeSpec struct {
 type KeycloakExternal struct {
 	// If set to true, this Keycloak will be treated as an external instance.
 	// The unmanaged field also needs to be set to true if this field is true.
+	// Nothing is deployed for it, but the realms, clients and users selecting it are
+	// managed through its admin API, logging in with the credentials of the
+	// credential-<name> Secret or of adminCredentialsRef.
 	Enabled bool `json:"enabled,omitempty"`
 	// The URL to use for the keycloak admin API. Needs to be set if external is true.
 	// +optional
@@ -156,9 +450,8 @@ type KeycloakExternalAccess struct {
 	// TLS Termination type for the external access. Setting this field to "reencrypt" will
 	// terminate TLS on the Ingress/Route level. Setting this field to "passthrough" will
 	// send encrypted traffic to the Pod. If unspecified, defaults to "reencrypt".
-	// Note, that this setting has no effect on Ingress
-	// as Ingress TLS settings are not reconciled by this operator. In other words,
-	// Ingress TLS configuration is the same in both cases and it is up to the user
+	// Note, that this setting has no effect on Ingress. Ingress TLS settings are only
+	// reconciled when ingress.tlsSecret is set, otherwise it is up to the user
 	// to configure TLS section of the Ingress.
 	TLSTermination TLSTerminationType `json:"tlsTermination,omitempty"`
 	// If set, the Operator will use value of host for Ingress/Route host
@@ -166,17 +459,140 @@ type KeycloakExternalAccess struct {
 	// chosen name for Route
 	// +optional
 	Host string `json:"host,omitempty"`
+	// Settings specific to the Ingress, ignored on OpenShift where a Route is used.
+	// +optional
+	Ingress *KeycloakIngressConfig `json:"ingress,omitempty"`
+	// Settings specific to the Route, only used on OpenShift.
+	// +optional
+	Route *KeycloakRouteConfig `json:"route,omitempty"`
+}
+
+type KeycloakRouteConfig struct {
+	// TLS termination of the Route. Takes precedence over externalAccess.tlsTermination.
+	// Keycloak only serves HTTPS, so the Route can't terminate TLS at the edge.
+	// +optional
+	// +kubebuilder:validation:Enum=reencrypt;passthrough
+	Termination TLSTerminationType `json:"termination,omitempty"`
+	// Host of the Route. Takes precedence over externalAccess.host.
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Annotations added to the Route. They take precedence over the annotations
+	// the Operator sets.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Key of a ConfigMap holding the CA certificate the router uses to verify
+	// Keycloak, in PEM format. Only valid with reencrypt termination.
+	// +optional
+	DestinationCACertificate *corev1.ConfigMapKeySelector `json:"destinationCACertificate,omitempty"`
+}
+
+type KeycloakIngressConfig struct {
+	// Name of the IngressClass the Ingress belongs to.
+	// +optional
+	ClassName string `json:"className,omitempty"`
+	// Annotations added to the Ingress, e.g. for cert-manager. They take precedence
+	// over the annotations the Operator sets, and are removed from the Ingress once
+	// removed from here.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Host of the Ingress. Takes precedence over externalAccess.host.
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Name of the Secret holding the TLS certificate of the host. When set, the TLS
+	// section of the Ingress is managed by the Operator.
+	// +optional
+	TLSSecret string `json:"tlsSecret,omitempty"`
 }
 
 type KeycloakExternalDatabase struct {
 	// If set to true, the Operator will use an external database.
 	// pointing to Keycloak.
 	Enabled bool `json:"enabled,omitempty"`
+	// Host name or IP address of the external database. When set, the Operator
+	// manages the keycloak-db-secret from the fields below, otherwise the secret
+	// has to be provided by the user.
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Port of the external database. Defaults to 5432.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+	// Name of the database Keycloak uses. Defaults to root.
+	// +optional
+	Database string `json:"database,omitempty"`
+	// SSL mode of the connection to the database, passed on to the JDBC driver.
+	// The verify-ca and verify-full modes need sslRootCertificate to be set.
+	// +optional
+	// +kubebuilder:validation:Enum=disable;allow;prefer;require;verify-ca;verify-full
+	SSLMode string `json:"sslMode,omitempty"`
+	// CA certificate the database server certificate is verified against. It is
+	// mounted into the Keycloak pod.
+	// +optional
+	SSLRootCertificate *KeycloakExternalDatabaseCertificate `json:"sslRootCertificate,omitempty"`
+	// Name of a Secret in the namespace of the Keycloak CR holding the
+	// username and password of the database, under those keys.
+	// Required when host is set.
+	// +optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// A PEM encoded certificate, taken from either a ConfigMap or a Secret.
+type KeycloakExternalDatabaseCertificate struct {
+	// Key of a ConfigMap holding the certificate.
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// Key of a Secret holding the certificate.
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+type KeycloakAutoscaling struct {
+	// Lower limit of Keycloak instances. Default is 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+	// Upper limit of Keycloak instances, at least minReplicas.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+	// Average CPU utilization, in percent of the requested CPU, to scale at. Default is 80
+	// unless a memory target is set.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+	// Average memory utilization, in percent of the requested memory, to scale at.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TargetMemoryUtilizationPercentage *int32 `json:"targetMemoryUtilizationPercentage,omitempty"`
 }
 
 type PodDisruptionBudgetConfig struct {
 	// If set to true, the operator will create a PodDistruptionBudget for the Keycloak deployment and set its `maxUnavailable` value to 1.
 	Enabled bool `json:"enabled,omitempty"`
+	// Number or percentage of Keycloak Pods that must stay available during voluntary
+	// disruptions. Mutually exclusive with maxUnavailable.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// Number or percentage of Keycloak Pods that may be unavailable during voluntary
+	// disruptions. Default is 1 unless minAvailable is set.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+type ServiceMonitorConfig struct {
+	// If set to false, the operator won't create a ServiceMonitor and removes the one it
+	// created. Default is true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Interval at which the metrics are scraped, e.g. 30s. Defaults to the
+	// interval of Prometheus.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(ms|s|m|h)$`
+	Interval string `json:"interval,omitempty"`
+	// Labels added to the ServiceMonitor, so that the serviceMonitorSelector of
+	// Prometheus picks it up.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 type MigrateConfig struct {
@@ -212,8 +628,15 @@ type KeycloakStatus struct {
 	Ready bool `json:"ready"`
 	// A map of all the secondary resources types and names created for this CR. e.g "Deployment": [ "DeploymentName1", "DeploymentName2" ].
 	SecondaryResources map[string][]string `json:"secondaryResources,omitempty"`
-	// Version of Keycloak or RHSSO running on the cluster.
+	// Version of the operator that last reconciled the Keycloak.
 	Version string `json:"version"`
+	// Version of Keycloak or RHSSO as reported by its server info, read once it is ready.
+	// +optional
+	KeycloakVersion string `json:"keycloakVersion,omitempty"`
+	// Image of the Keycloak deployment the keycloakVersion was read from. The version is
+	// read again once the image changes.
+	// +optional
+	KeycloakVersionImage string `json:"keycloakVersionImage,omitempty"`
 	// Service IP and Port for in-cluster access to the keycloak instance.
 	InternalURL string `json:"internalURL"`
 	// The secret where the admin credentials are to be found.
@@ -227,6 +650,7 @@ var (
 	PhaseReconciling  StatusPhase = "reconciling"
 	PhaseFailing      StatusPhase = "failing"
 	PhaseInitialising StatusPhase = "initialising"
+	PhaseConflicting  StatusPhase = "conflicting"
 )
 
 // Keycloak is the Schema for the keycloaks API.
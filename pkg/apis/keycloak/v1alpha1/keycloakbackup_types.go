@@ -8,18 +8,21 @@ import (
 // +k8s:openapi-gen=true
 type KeycloakBackupSpec struct {
 	// Controls automatic restore behavior.
-	// Currently not implemented.
 	//
-	// In the future this will be used to trigger automatic restore for a given KeycloakBackup.
-	// Each backup will correspond to a single snapshot of the database (stored either in a
-	// Persistent Volume or AWS). If a user wants to restore it, all he/she needs to do is to
-	// change this flag to true.
-	// Potentially, it will be possible to restore a single backup multiple times.
+	// Each backup corresponds to a single snapshot of the database. Setting this flag to true
+	// loads the snapshot back into the database of the Keycloak instance with a Job. Only
+	// backups on a local Persistent Volume can be restored. The restore runs once, delete
+	// the restore Job to run it again.
 	// +optional
 	Restore bool `json:"restore,omitempty"`
+	// Controls how the backup is restored.
+	// +optional
+	RestoreOptions KeycloakRestoreOptions `json:"restoreOptions,omitempty"`
 	// If provided, an automatic database backup will be created on AWS S3 instead of
 	// a local Persistent Volume. If this property is not provided - a local
-	// Persistent Volume backup will be chosen.
+	// Persistent Volume backup will be chosen. The Operator can't restore backups on
+	// AWS S3, a KeycloakBackup that sets both aws and restore fails, such backups are
+	// restored by hand.
 	// +optional
 	AWS KeycloakAWSSpec `json:"aws,omitempty"`
 	// Selector for looking up Keycloak Custom Resources.
@@ -28,6 +31,24 @@ type KeycloakBackupSpec struct {
 	// Name of the StorageClass for Postgresql Backup Persistent Volume Claim
 	// +optional
 	StorageClassName *string `json:"storageClassName,omitempty"`
+	// If provided, the database backup written to the local Persistent Volume will be
+	// encrypted with GPG. The secret is in the same form as aws.encryptionKeySecretName,
+	// which applies to AWS S3 backups. Unencrypted backups are the default.
+	// Restoring an encrypted backup also needs the private key as GPG_PRIVATE_KEY.
+	// +optional
+	EncryptionKeySecretName string `json:"encryptionKeySecretName,omitempty"`
+}
+
+type KeycloakRestoreOptions struct {
+	// The restore refuses to load the backup into a database that already has tables,
+	// unless this is set to true. The existing tables are dropped in that case.
+	// +optional
+	Force bool `json:"force,omitempty"`
+	// If set to true, Keycloak is scaled down to 0 while the backup is restored and
+	// scaled back up once the restore finished. A failed restore keeps Keycloak scaled
+	// down until the restore succeeds or the KeycloakBackup is removed.
+	// +optional
+	ScaleDownKeycloak bool `json:"scaleDownKeycloak,omitempty"`
 }
 
 // KeycloakAWSSpec defines the desired state of KeycloakBackupSpec.
@@ -77,8 +98,11 @@ var (
 	BackupPhaseNone        BackupStatusPhase
 	BackupPhaseReconciling BackupStatusPhase = "reconciling"
 	BackupPhaseCreated     BackupStatusPhase = "created"
+	BackupPhaseRestoring   BackupStatusPhase = "restoring"
 	BackupPhaseRestored    BackupStatusPhase = "restored"
 	BackupPhaseFailing     BackupStatusPhase = "failing"
+	// The restore Job failed, Keycloak stays scaled down if the restore scaled it down
+	BackupPhaseRestoreFailed BackupStatusPhase = "restoreFailed"
 )
 
 // KeycloakBackupStatus defines the observed state of KeycloakBackup.
@@ -90,6 +114,9 @@ type KeycloakBackupStatus struct {
 	Message string `json:"message"`
 	// True if all resources are in a ready state and all work is done.
 	Ready bool `json:"ready"`
+	// True if the last backup that was taken is encrypted.
+	// +optional
+	Encrypted bool `json:"encrypted,omitempty"`
 	// A map of all the secondary resources types and names created for this CR. e.g "Deployment": [ "DeploymentName1", "DeploymentName2" ]
 	SecondaryResources map[string][]string `json:"secondaryResources,omitempty"`
 }
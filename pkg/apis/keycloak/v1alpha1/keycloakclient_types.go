@@ -1,25 +1,205 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // KeycloakClientSpec defines the desired state of KeycloakClient.
 // +k8s:openapi-gen=true
 type KeycloakClientSpec struct {
-	// Selector for looking up KeycloakRealm Custom Resources.
+	// Selector for looking up KeycloakRealm Custom Resources. It has to match exactly one realm,
+	// through its match labels and match expressions.
 	// +kubebuilder:validation:Required
 	RealmSelector *metav1.LabelSelector `json:"realmSelector"`
 	// Keycloak Client REST object.
 	// +kubebuilder:validation:Required
 	Client *KeycloakAPIClient `json:"client"`
-	// Client Roles
+	// Client Roles. The roles of a new client are created with a single partial
+	// import when there are many of them. Roles that already exist are then skipped
+	// rather than overwritten, they are updated one by one afterwards.
 	// +optional
 	// +listType=map
 	// +listMapKey=name
 	Roles []RoleRepresentation `json:"roles,omitempty"`
+	// Realm roles assigned to the service account of the client. When set, realm roles
+	// not listed here are removed from the service account.
+	// Requires serviceAccountsEnabled to be set on the client.
+	// +optional
+	ServiceAccountRealmRoles []string `json:"serviceAccountRealmRoles,omitempty"`
+	// Client roles assigned to the service account of the client, keyed by client ID.
+	// Only the roles of the listed clients are managed.
+	// Requires serviceAccountsEnabled to be set on the client.
+	// +optional
+	ServiceAccountClientRoles map[string][]string `json:"serviceAccountClientRoles,omitempty"`
+	// Period after which the client secret is regenerated in Keycloak, e.g. "720h".
+	// The new secret is only written to the client Secret, and takes the place of
+	// client.secret from then on.
+	// Ignored for public clients and when secretRef is set.
+	// +optional
+	SecretRotationPeriod *metav1.Duration `json:"secretRotationPeriod,omitempty"`
+	// If true, a registration access token of the client is written to the client
+	// Secret, for managing the client with the client registration API. A new one
+	// is issued along with every regenerated client secret.
+	// Ignored for public and bearer-only clients, which have no client Secret.
+	// +optional
+	RegistrationAccessToken bool `json:"registrationAccessToken,omitempty"`
+	// Key of an existing Secret in the namespace of the KeycloakClient holding the
+	// client secret. When set, it takes precedence over client.secret and the value is
+	// pushed to Keycloak whenever the two diverge.
+	// +optional
+	SecretRef *corev1.SecretKeySelector `json:"secretRef,omitempty"`
+	// Realm roles, and client roles keyed by client ID, included in the scope of the tokens issued
+	// to the client. When set, roles not listed here are removed from the scope. Ignored unless
+	// client.fullScopeAllowed is set to false, as all roles are in scope otherwise.
+	// +optional
+	ScopeMappings *RoleRepresentationComposites `json:"scopeMappings,omitempty"`
+	// Names of the client roles added to the default roles of the realm, which every user of the
+	// realm is granted. When set, the other roles of the client are removed from the default roles.
+	// +optional
+	DefaultRoles []string `json:"defaultRoles,omitempty"`
+	// SAML settings of the client, set as the matching client.attributes, which they take
+	// precedence over.
+	// +optional
+	SAML *KeycloakClientSAML `json:"saml,omitempty"`
+	// Token lifespans of the client that differ from the ones of the realm, set as the
+	// matching client.attributes, which they take precedence over. Settings left unset
+	// here fall back to the realm, as long as tokenSettings itself is set or was set on
+	// the last reconcile.
+	// +optional
+	TokenSettings *KeycloakClientTokenSettings `json:"tokenSettings,omitempty"`
+	// Consent screen settings of the client, set as the matching client.attributes, which
+	// they take precedence over. Only used when client.consentRequired is set, otherwise
+	// the consent screen texts of the client and its protocol mappers are cleared.
+	// +optional
+	Consent *KeycloakClientConsent `json:"consent,omitempty"`
+	// OpenID Connect logout settings of the client, set as the matching client.attributes,
+	// which they take precedence over. Settings left unset here are removed from the
+	// client, as long as logout itself is set.
+	// +optional
+	Logout *KeycloakClientLogout `json:"logout,omitempty"`
+	// How long the deletion of the KeycloakClient waits for the client to be removed from Keycloak,
+	// e.g. while Keycloak can't be reached. The KeycloakClient is deleted anyway afterwards, leaving
+	// the client in Keycloak. Defaults to 15m.
+	// +optional
+	DeletionGracePeriod *metav1.Duration `json:"deletionGracePeriod,omitempty"`
+	// Validation of client.redirectUris before they are sent to Keycloak, nothing is checked by default.
+	// With "lenient", each URI must be an absolute URL or a path, with a wildcard only at its end.
+	// "strict" additionally requires http or https URLs with a host and rejects a lone "*".
+	// +optional
+	// +kubebuilder:validation:Enum=lenient;strict
+	RedirectURIValidation string `json:"redirectUriValidation,omitempty"`
+	// How often the KeycloakClient is reconciled again after a successful reconcile, which corrects the
+	// changes made in Keycloak meanwhile. Defaults to the --resync-period of the operator.
+	// +optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+	// Authentication flows used by the client instead of the ones bound in the realm, e.g.
+	// to require a second factor for it. Keyed by binding, either "browser" or "direct_grant",
+	// with the alias or ID of a top level flow of the realm. Bindings left out are reset to
+	// the flows of the realm, as long as authFlowOverrides itself is set.
+	// +optional
+	AuthFlowOverrides map[string]string `json:"authFlowOverrides,omitempty"`
+}
+
+type KeycloakClientTokenSettings struct {
+	// Access Token Lifespan. Maps to access.token.lifespan.
+	// +optional
+	AccessTokenLifespan *metav1.Duration `json:"accessTokenLifespan,omitempty"`
+	// Client Session Idle. Maps to client.session.idle.timeout.
+	// +optional
+	ClientSessionIdleTimeout *metav1.Duration `json:"clientSessionIdleTimeout,omitempty"`
+	// Client Session Max. Maps to client.session.max.lifespan.
+	// +optional
+	ClientSessionMaxLifespan *metav1.Duration `json:"clientSessionMaxLifespan,omitempty"`
+	// Client Offline Session Idle. Maps to client.offline.session.idle.timeout.
+	// +optional
+	ClientOfflineSessionIdleTimeout *metav1.Duration `json:"clientOfflineSessionIdleTimeout,omitempty"`
+	// Client Offline Session Max. Maps to client.offline.session.max.lifespan.
+	// +optional
+	ClientOfflineSessionMaxLifespan *metav1.Duration `json:"clientOfflineSessionMaxLifespan,omitempty"`
+}
+
+type KeycloakClientConsent struct {
+	// Show the client on the consent screen and in the applications of the account
+	// console. Maps to display.on.consent.screen.
+	// +optional
+	DisplayOnConsentScreen *bool `json:"displayOnConsentScreen,omitempty"`
+	// Text shown for the client on the consent screen. Maps to consent.screen.text.
+	// +optional
+	ConsentScreenText string `json:"consentScreenText,omitempty"`
+}
+
+type KeycloakClientLogout struct {
+	// URL the browser is sent to in an iframe to log the user out of the client. Maps to
+	// frontchannel.logout.url, and turns on client.frontchannelLogout when set.
+	// +optional
+	FrontchannelLogoutURL string `json:"frontchannelLogoutUrl,omitempty"`
+	// Add the session ID and issuer to the front-channel logout URL. Maps to
+	// frontchannel.logout.session.required.
+	// +optional
+	FrontchannelLogoutSessionRequired *bool `json:"frontchannelLogoutSessionRequired,omitempty"`
+	// URL Keycloak posts logout tokens to when the user logs out. Maps to
+	// backchannel.logout.url.
+	// +optional
+	BackchannelLogoutURL string `json:"backchannelLogoutUrl,omitempty"`
+	// Add the session ID to the logout tokens. Maps to backchannel.logout.session.required.
+	// +optional
+	BackchannelLogoutSessionRequired *bool `json:"backchannelLogoutSessionRequired,omitempty"`
+	// Revoke the offline sessions of the user as well. Maps to
+	// backchannel.logout.revoke.offline.tokens.
+	// +optional
+	BackchannelLogoutRevokeOfflineTokens *bool `json:"backchannelLogoutRevokeOfflineTokens,omitempty"`
+}
+
+type KeycloakClientSAML struct {
+	// Secret key holding the certificate that documents signed by the client are validated
+	// with, PEM encoded or as base64 DER. Maps to saml.signing.certificate.
+	// +optional
+	SigningCertificateRef *corev1.SecretKeySelector `json:"signingCertificateRef,omitempty"`
+	// Secret key holding the certificate that assertions for the client are encrypted with,
+	// PEM encoded or as base64 DER. Maps to saml.encryption.certificate.
+	// +optional
+	EncryptionCertificateRef *corev1.SecretKeySelector `json:"encryptionCertificateRef,omitempty"`
+	// Sign documents. Maps to saml.server.signature.
+	// +optional
+	ServerSignature *bool `json:"serverSignature,omitempty"`
+	// Sign assertions. Maps to saml.assertion.signature.
+	// +optional
+	AssertionSignature *bool `json:"assertionSignature,omitempty"`
+	// Require documents to be signed by the client. Maps to saml.client.signature.
+	// +optional
+	ClientSignature *bool `json:"clientSignature,omitempty"`
+	// Encrypt assertions. Maps to saml.encrypt.
+	// +optional
+	EncryptAssertions *bool `json:"encryptAssertions,omitempty"`
+	// Always respond with the POST binding. Maps to saml.force.post.binding.
+	// +optional
+	ForcePostBinding *bool `json:"forcePostBinding,omitempty"`
+	// Include an AuthnStatement in the login response. Maps to saml.authnstatement.
+	// +optional
+	AuthnStatement *bool `json:"authnStatement,omitempty"`
+	// Signature algorithm. Maps to saml.signature.algorithm.
+	// +optional
+	// +kubebuilder:validation:Enum=RSA_SHA1;RSA_SHA256;RSA_SHA256_MGF1;RSA_SHA512;RSA_SHA512_MGF1;DSA_SHA1
+	SignatureAlgorithm string `json:"signatureAlgorithm,omitempty"`
+	// Canonicalization method of XML signatures, e.g. http://www.w3.org/2001/10/xml-exc-c14n#.
+	// Maps to saml_signature_canonicalization_method.
+	// +optional
+	SignatureCanonicalizationMethod string `json:"signatureCanonicalizationMethod,omitempty"`
+	// Name ID format of the subject. Maps to saml_name_id_format.
+	// +optional
+	// +kubebuilder:validation:Enum=username;email;transient;persistent
+	NameIDFormat string `json:"nameIdFormat,omitempty"`
+	// Ignore the name ID format requested by the client. Maps to saml_force_name_id_format.
+	// +optional
+	ForceNameIDFormat *bool `json:"forceNameIdFormat,omitempty"`
 }
 
+const (
+	RedirectURIValidationLenient = "lenient"
+	RedirectURIValidationStrict  = "strict"
+)
+
 type KeycloakAPIClient struct {
 	// Client ID. If not specified, automatically generated.
 	// +optional
@@ -30,12 +210,13 @@ type KeycloakAPIClient struct {
 	// Client name.
 	// +optional
 	Name string `json:"name,omitempty"`
-	// Surrogate Authentication Required option.
+	// Surrogate Authentication Required option. Keycloak keeps its value when it is
+	// left out.
 	// +optional
-	SurrogateAuthRequired bool `json:"surrogateAuthRequired,omitempty"`
-	// Client enabled flag.
+	SurrogateAuthRequired *bool `json:"surrogateAuthRequired,omitempty"`
+	// Client enabled flag. Keycloak enables clients when it is left out.
 	// +optional
-	Enabled bool `json:"enabled,omitempty"`
+	Enabled *bool `json:"enabled,omitempty"`
 	// What Client authentication type to use.
 	// +optional
 	ClientAuthenticatorType string `json:"clientAuthenticatorType,omitempty"`
@@ -60,18 +241,22 @@ type KeycloakAPIClient struct {
 	// A list of valid Redirection URLs.
 	// +optional
 	RedirectUris []string `json:"redirectUris,omitempty"`
-	// A list of valid Web Origins.
+	// A list of valid Web Origins, as scheme, host and optional port without a path,
+	// e.g. https://example.com. "+" allows the origins of the redirect URIs and "*"
+	// allows any origin. The order doesn't matter.
 	// +optional
 	WebOrigins []string `json:"webOrigins,omitempty"`
 	// Not Before setting.
 	// +optional
 	NotBefore int `json:"notBefore,omitempty"`
-	// True if a client supports only Bearer Tokens.
+	// True if a client supports only Bearer Tokens. Keycloak keeps its value when it
+	// is left out.
 	// +optional
-	BearerOnly bool `json:"bearerOnly,omitempty"`
-	// True if Consent Screen is required.
+	BearerOnly *bool `json:"bearerOnly,omitempty"`
+	// True if Consent Screen is required. Always sent, so that turning it off reaches
+	// Keycloak.
 	// +optional
-	ConsentRequired bool `json:"consentRequired,omitempty"`
+	ConsentRequired bool `json:"consentRequired"`
 	// True if Standard flow is enabled.
 	// +optional
 	StandardFlowEnabled bool `json:"standardFlowEnabled"`
@@ -81,15 +266,17 @@ type KeycloakAPIClient struct {
 	// True if Direct Grant is enabled.
 	// +optional
 	DirectAccessGrantsEnabled bool `json:"directAccessGrantsEnabled"`
-	// True if Service Accounts are enabled.
+	// True if Service Accounts are enabled. Keycloak keeps its value when it is left
+	// out, turning them off deletes the service account user.
 	// +optional
-	ServiceAccountsEnabled bool `json:"serviceAccountsEnabled,omitempty"`
+	ServiceAccountsEnabled *bool `json:"serviceAccountsEnabled,omitempty"`
 	// True if this is a public Client.
 	// +optional
 	PublicClient bool `json:"publicClient"`
-	// True if this client supports Front Channel logout.
+	// True if this client supports Front Channel logout. Keycloak keeps its value when
+	// it is left out.
 	// +optional
-	FrontchannelLogout bool `json:"frontchannelLogout,omitempty"`
+	FrontchannelLogout *bool `json:"frontchannelLogout,omitempty"`
 	// Protocol used for this Client.
 	// +optional
 	Protocol string `json:"protocol,omitempty"`
@@ -128,6 +315,19 @@ type KeycloakAPIClient struct {
 	// assertions for this client.
 	// +optional
 	DefaultClientScopes []string `json:"defaultClientScopes,omitempty"`
+	// True if fine-grained authorization support is enabled for this client. Keycloak
+	// keeps its value when it is left out, turning it off deletes the resource server.
+	// +optional
+	AuthorizationServicesEnabled *bool `json:"authorizationServicesEnabled,omitempty"`
+	// Authorization settings of the client. When set, they replace the existing
+	// resources, scopes, policies and permissions as a whole, including the
+	// defaults Keycloak creates when enabling authorization services.
+	// +optional
+	AuthorizationSettings *ResourceServerRepresentation `json:"authorizationSettings,omitempty"`
+	// IDs of the flows overriding the ones of the realm, keyed by binding. An empty ID
+	// removes the override.
+	// +optional
+	AuthenticationFlowBindingOverrides map[string]string `json:"authenticationFlowBindingOverrides,omitempty"`
 }
 
 type KeycloakProtocolMapper struct {
@@ -154,6 +354,124 @@ type KeycloakProtocolMapper struct {
 	Config map[string]string `json:"config,omitempty"`
 }
 
+// https://www.keycloak.org/docs-api/11.0/rest-api/index.html#_resourceserverrepresentation
+type ResourceServerRepresentation struct {
+	// True if resources can be managed remotely by the resource server.
+	// +optional
+	AllowRemoteResourceManagement bool `json:"allowRemoteResourceManagement,omitempty"`
+
+	// How permissions are combined when evaluating a request.
+	// +kubebuilder:validation:Enum=UNANIMOUS;AFFIRMATIVE;CONSENSUS
+	// +optional
+	DecisionStrategy string `json:"decisionStrategy,omitempty"`
+
+	// Policy enforcement mode.
+	// +kubebuilder:validation:Enum=ENFORCING;PERMISSIVE;DISABLED
+	// +optional
+	PolicyEnforcementMode string `json:"policyEnforcementMode,omitempty"`
+
+	// Policies and permissions.
+	// +optional
+	Policies []PolicyRepresentation `json:"policies,omitempty"`
+
+	// Protected resources.
+	// +optional
+	Resources []ResourceRepresentation `json:"resources,omitempty"`
+
+	// Authorization scopes.
+	// +optional
+	Scopes []ScopeRepresentation `json:"scopes,omitempty"`
+}
+
+// https://www.keycloak.org/docs-api/11.0/rest-api/index.html#_policyrepresentation
+type PolicyRepresentation struct {
+	// Policy configuration, e.g. the applied policies, resources and scopes of a
+	// permission as JSON arrays.
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+
+	// How the policies of a permission are combined.
+	// +kubebuilder:validation:Enum=UNANIMOUS;AFFIRMATIVE;CONSENSUS
+	// +optional
+	DecisionStrategy string `json:"decisionStrategy,omitempty"`
+
+	// Description
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Id
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Logic
+	// +kubebuilder:validation:Enum=POSITIVE;NEGATIVE
+	// +optional
+	Logic string `json:"logic,omitempty"`
+
+	// Name
+	Name string `json:"name"`
+
+	// Policy type, e.g. role, js or resource for permissions.
+	// +optional
+	Type string `json:"type,omitempty"`
+}
+
+// https://www.keycloak.org/docs-api/11.0/rest-api/index.html#_resourcerepresentation
+type ResourceRepresentation struct {
+	// Resource Attributes
+	// +optional
+	Attributes map[string][]string `json:"attributes,omitempty"`
+
+	// Display Name
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Icon URI
+	// +optional
+	IconURI string `json:"icon_uri,omitempty"`
+
+	// Id
+	// +optional
+	ID string `json:"_id,omitempty"`
+
+	// Name
+	Name string `json:"name"`
+
+	// True if the owner of the resource manages access to it.
+	// +optional
+	OwnerManagedAccess bool `json:"ownerManagedAccess,omitempty"`
+
+	// Scopes of the resource
+	// +optional
+	Scopes []ScopeRepresentation `json:"scopes,omitempty"`
+
+	// Resource type
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// URIs of the resource
+	// +optional
+	URIs []string `json:"uris,omitempty"`
+}
+
+// https://www.keycloak.org/docs-api/11.0/rest-api/index.html#_scoperepresentation
+type ScopeRepresentation struct {
+	// Display Name
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Icon URI
+	// +optional
+	IconURI string `json:"iconUri,omitempty"`
+
+	// Id
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Name
+	Name string `json:"name"`
+}
+
 // KeycloakClientStatus defines the observed state of KeycloakClient
 // +k8s:openapi-gen=true
 type KeycloakClientStatus struct {
@@ -165,6 +483,38 @@ type KeycloakClientStatus struct {
 	Ready bool `json:"ready"`
 	// A map of all the secondary resources types and names created for this CR. e.g "Deployment": [ "DeploymentName1", "DeploymentName2" ]
 	SecondaryResources map[string][]string `json:"secondaryResources,omitempty"`
+	// The roles of the client as found in Keycloak after the last reconcile, along with the action
+	// taken for each of them.
+	// +optional
+	ManagedRoles []ManagedRole `json:"managedRoles,omitempty"`
+	// The client attributes tokenSettings set on the last reconcile, which are cleared once it
+	// doesn't anymore. Lifespans set in Keycloak by other means are left alone.
+	// +optional
+	ManagedTokenSettings []string `json:"managedTokenSettings,omitempty"`
+	// Conditions of the resource following the Kubernetes conventions, i.e. Ready, Reconciling and Error.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+const (
+	ManagedRoleCreated   = "created"
+	ManagedRoleUpdated   = "updated"
+	ManagedRoleRenamed   = "renamed"
+	ManagedRoleUnchanged = "unchanged"
+	ManagedRoleDeleted   = "deleted"
+	ManagedRoleFailed    = "failed"
+)
+
+type ManagedRole struct {
+	// Role Name.
+	Name string `json:"name"`
+	// Role ID in Keycloak.
+	// +optional
+	ID string `json:"id,omitempty"`
+	// One of created, updated, renamed, unchanged, deleted or failed.
+	LastAction string `json:"lastAction"`
 }
 
 // KeycloakClient is the Schema for the keycloakclients API.
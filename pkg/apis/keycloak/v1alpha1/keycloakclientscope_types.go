@@ -0,0 +1,52 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KeycloakClientScopeSpec defines the desired state of KeycloakClientScope.
+// +k8s:openapi-gen=true
+type KeycloakClientScopeSpec struct {
+	// Selector for looking up KeycloakRealm Custom Resources.
+	// +kubebuilder:validation:Required
+	RealmSelector *metav1.LabelSelector `json:"realmSelector"`
+	// Keycloak Client Scope REST object. The scope is matched by name. When protocol
+	// mappers are set, mappers of the scope not listed here are removed.
+	// +kubebuilder:validation:Required
+	ClientScope *KeycloakAPIClientScope `json:"clientScope"`
+}
+
+// KeycloakClientScopeStatus defines the observed state of KeycloakClientScope.
+// +k8s:openapi-gen=true
+type KeycloakClientScopeStatus struct {
+	// Current phase of the operator.
+	Phase StatusPhase `json:"phase"`
+	// Human-readable message indicating details about current operator phase or error.
+	Message string `json:"message"`
+	// True if all resources are in a ready state and all work is done.
+	Ready bool `json:"ready"`
+}
+
+// KeycloakClientScope is the Schema for the keycloakclientscopes API.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KeycloakClientScope struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KeycloakClientScopeSpec   `json:"spec,omitempty"`
+	Status KeycloakClientScopeStatus `json:"status,omitempty"`
+}
+
+// KeycloakClientScopeList contains a list of KeycloakClientScope
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KeycloakClientScopeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeycloakClientScope `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeycloakClientScope{}, &KeycloakClientScopeList{})
+}
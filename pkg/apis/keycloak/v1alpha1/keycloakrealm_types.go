@@ -1,7 +1,9 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // KeycloakRealmSpec defines the desired state of KeycloakRealm.
@@ -17,11 +19,389 @@ type KeycloakRealmSpec struct {
 	// Keycloak Realm REST object.
 	// +kubebuilder:validation:Required
 	Realm *KeycloakAPIRealm `json:"realm"`
+	// Secret holding the client ID and secret of a service account client of this realm,
+	// which the Operator authenticates with to reconcile the KeycloakClients,
+	// KeycloakClientScopes and KeycloakUsers of the realm, so that they can't reach other
+	// realms. The service account needs the manage-clients, manage-users and view-realm
+	// roles of the realm-management client. The realm itself is still reconciled with the
+	// admin credentials of the Keycloak.
+	// +optional
+	AdminClientCredentialsRef *KeycloakAdminClientCredentialsRef `json:"adminClientCredentialsRef,omitempty"`
 	// A list of overrides to the default Realm behavior.
 	// +listType=atomic
 	RealmOverrides []*RedirectorIdentityProviderOverride `json:"realmOverrides,omitempty"`
+	// Realm Roles. When set, realm roles not listed here are removed from the realm,
+	// except for the roles keycloak creates by default.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Roles []RoleRepresentation `json:"roles,omitempty"`
+	// SMTP server settings read from secrets, keyed by the setting they provide, e.g.
+	// user, password, host, port or ssl. They take precedence over realm.smtpServer.
+	// +optional
+	SMTPServerSecrets map[string]corev1.SecretKeySelector `json:"smtpServerSecrets,omitempty"`
+	// Token and session timeouts. They take precedence over the corresponding realm settings.
+	// +optional
+	TokenSettings *KeycloakRealmTokenSettings `json:"tokenSettings,omitempty"`
+	// Self-service settings of the login page, e.g. whether users can register. They take
+	// precedence over the corresponding realm settings.
+	// +optional
+	LoginSettings *KeycloakRealmLoginSettings `json:"loginSettings,omitempty"`
+	// Themes of the login pages, account console, emails and admin console of the realm,
+	// e.g. custom themes deployed as extensions. Themes Keycloak doesn't have are not
+	// set and reported in a warning event. They take precedence over the realm settings.
+	// +optional
+	Themes *KeycloakRealmThemes `json:"themes,omitempty"`
+	// WebAuthn policy of the realm, used by the WebAuthn authenticator for two-factor
+	// authentication. It takes precedence over the corresponding realm settings.
+	// +optional
+	WebAuthnPolicy *KeycloakRealmWebAuthnPolicy `json:"webAuthnPolicy,omitempty"`
+	// WebAuthn policy of the realm used by the WebAuthn passwordless authenticator, e.g.
+	// for passkeys. It takes precedence over the corresponding realm settings.
+	// +optional
+	WebAuthnPasswordlessPolicy *KeycloakRealmWebAuthnPolicy `json:"webAuthnPasswordlessPolicy,omitempty"`
+	// Events and admin events recording. They take precedence over the corresponding realm settings.
+	// +optional
+	Events *KeycloakAPIRealmEventsConfig `json:"events,omitempty"`
+	// Groups of the realm, created top down along with their role mappings. When set,
+	// groups not listed here are removed from the realm.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Groups []KeycloakAPIGroup `json:"groups,omitempty"`
+	// Default groups new users join, given by their path, e.g. /parent/child. When set,
+	// groups not listed here are no longer default groups. The groups must exist.
+	// +optional
+	// +listType=set
+	DefaultGroups []string `json:"defaultGroups,omitempty"`
+	// Client scopes added to new clients of the realm as default scopes, given by name.
+	// When set, client scopes not listed here are no longer added. The scopes must exist.
+	// +optional
+	// +listType=set
+	DefaultClientScopes []string `json:"defaultClientScopes,omitempty"`
+	// Client scopes added to new clients of the realm as optional scopes, given by name.
+	// When set, client scopes not listed here are no longer added. The scopes must exist.
+	// +optional
+	// +listType=set
+	OptionalClientScopes []string `json:"optionalClientScopes,omitempty"`
+	// Realm JSON, e.g. an export of the realm, imported into the realm with a partial
+	// import once the realm exists. It is imported again whenever its content changes.
+	// +optional
+	RealmImport *KeycloakRealmImport `json:"realmImport,omitempty"`
+	// Users imported in bulk into the realm once it exists, e.g. to onboard many users at
+	// once. Users that already exist are skipped. The users are imported again whenever
+	// the content changes, the outcome for each user is shown in status.userImportResults.
+	// +optional
+	UserImport *KeycloakRealmUserImport `json:"userImport,omitempty"`
+	// Message overrides of the login, account and email themes, keyed by locale and then
+	// by message key. When set, overrides not listed here are removed from the realm.
+	// Locales need to be supported by the realm to be shown, see realm.supportedLocales.
+	// +optional
+	Localization map[string]map[string]string `json:"localization,omitempty"`
+	// Required actions of the realm, e.g. CONFIGURE_TOTP, VERIFY_EMAIL or actions of
+	// custom providers, which are registered when missing. Required actions not listed
+	// here are left as they are.
+	// +optional
+	// +listType=map
+	// +listMapKey=alias
+	RequiredActions []KeycloakAPIRequiredAction `json:"requiredActions,omitempty"`
+	// Client profiles of the realm, e.g. for FAPI compliance. When set, they replace all
+	// client profiles of the realm. Needs Keycloak 14 or later.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ClientProfiles []KeycloakAPIClientProfile `json:"clientProfiles,omitempty"`
+	// Client policies of the realm, applying client profiles to the clients that meet
+	// their conditions. When set, they replace all client policies of the realm. Needs
+	// Keycloak 14 or later.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ClientPolicies []KeycloakAPIClientPolicy `json:"clientPolicies,omitempty"`
+	// How often the KeycloakRealm is reconciled again after a successful reconcile, which corrects the
+	// changes made in Keycloak meanwhile. Defaults to the --resync-period of the operator.
+	// +optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
 }
 
+type KeycloakRealmImport struct {
+	// Key of a ConfigMap holding the realm JSON.
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// Key of a Secret holding the realm JSON, for exports that contain credentials.
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// What happens to the resources of the JSON that already exist in the realm. SkipExisting
+	// leaves them as they are, OverwriteOnChange replaces them. Default is SkipExisting.
+	// +optional
+	// +kubebuilder:validation:Enum=SkipExisting;OverwriteOnChange
+	Policy RealmImportPolicy `json:"policy,omitempty"`
+}
+
+type RealmImportPolicy string
+
+var (
+	RealmImportSkipExisting      RealmImportPolicy = "SkipExisting"
+	RealmImportOverwriteOnChange RealmImportPolicy = "OverwriteOnChange"
+)
+
+type KeycloakRealmUserImport struct {
+	// Key of a ConfigMap holding the users.
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// Key of a Secret holding the users, for lists that contain credentials.
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// Format of the users, CSV or JSON. Default is CSV. CSV starts with a header row naming
+	// the columns, out of username, email, firstName, lastName, enabled, emailVerified,
+	// realmRoles, clientRoles and groups. Only username is required, users are enabled
+	// unless told otherwise. Lists are separated by ";" and client roles are given as
+	// client:role. JSON is a list of users like those of spec.realm.users.
+	// +optional
+	// +kubebuilder:validation:Enum=CSV;JSON
+	Format UserImportFormat `json:"format,omitempty"`
+}
+
+type UserImportFormat string
+
+var (
+	UserImportCSV  UserImportFormat = "CSV"
+	UserImportJSON UserImportFormat = "JSON"
+)
+
+// Outcome of the import of a user listed in userImport
+type KeycloakRealmUserImportResult struct {
+	// Username of the user
+	Username string `json:"username"`
+	// One of Added, Skipped when the user existed already, or Failed
+	Result UserImportResult `json:"result"`
+	// Why the import failed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type UserImportResult string
+
+var (
+	UserImportAdded   UserImportResult = "Added"
+	UserImportSkipped UserImportResult = "Skipped"
+	UserImportFailed  UserImportResult = "Failed"
+)
+
+// Events config of a realm, settings that are not set are left as they are in keycloak.
+// https://www.keycloak.org/docs-api/11.0/rest-api/index.html#_realmeventsconfigrepresentation
+type KeycloakAPIRealmEventsConfig struct {
+	// Enable events recording
+	// +optional
+	EventsEnabled *bool `json:"eventsEnabled,omitempty"`
+	// Expiration of recorded events in seconds
+	// +optional
+	EventsExpiration *int64 `json:"eventsExpiration,omitempty"`
+	// Event listeners
+	// +optional
+	EventsListeners []string `json:"eventsListeners,omitempty"`
+	// Saved event types, all of them when empty
+	// +optional
+	EnabledEventTypes []string `json:"enabledEventTypes,omitempty"`
+	// Enable admin events recording
+	// +optional
+	AdminEventsEnabled *bool `json:"adminEventsEnabled,omitempty"`
+	// Include representation in admin events
+	// +optional
+	AdminEventsDetailsEnabled *bool `json:"adminEventsDetailsEnabled,omitempty"`
+}
+
+// Required action of a realm, settings that are not set are left as they are in keycloak.
+// https://www.keycloak.org/docs-api/11.0/rest-api/index.html#_requiredactionproviderrepresentation
+type KeycloakAPIRequiredAction struct {
+	// Alias, keycloak registers required actions under the ID of their provider
+	// +kubebuilder:validation:Required
+	Alias string `json:"alias"`
+	// Display name
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Enabled
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Set on new users by default
+	// +optional
+	DefaultAction *bool `json:"defaultAction,omitempty"`
+	// Priority, required actions of a user are performed in ascending order
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+	// Config
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// https://www.keycloak.org/docs-api/15.0/rest-api/index.html#_clientprofilerepresentation
+type KeycloakAPIClientProfile struct {
+	// Name
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Description
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Executors enforcing the profile
+	// +optional
+	Executors []KeycloakAPIClientPolicyExecutor `json:"executors,omitempty"`
+}
+
+type KeycloakAPIClientPolicyExecutor struct {
+	// Executor provider ID, e.g. secure-client-authenticator or pkce-enforcer
+	// +kubebuilder:validation:Required
+	Executor string `json:"executor"`
+	// Configuration of the executor, as keycloak takes it
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Configuration *runtime.RawExtension `json:"configuration,omitempty"`
+}
+
+// https://www.keycloak.org/docs-api/15.0/rest-api/index.html#_clientpolicyrepresentation
+type KeycloakAPIClientPolicy struct {
+	// Name
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Description
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Enabled
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Conditions a client has to meet for the policy to apply
+	// +optional
+	Conditions []KeycloakAPIClientPolicyCondition `json:"conditions,omitempty"`
+	// Names of the client profiles applied to the clients
+	// +optional
+	Profiles []string `json:"profiles,omitempty"`
+}
+
+type KeycloakAPIClientPolicyCondition struct {
+	// Condition provider ID, e.g. client-roles or client-access-type
+	// +kubebuilder:validation:Required
+	Condition string `json:"condition"`
+	// Configuration of the condition, as keycloak takes it
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Configuration *runtime.RawExtension `json:"configuration,omitempty"`
+}
+
+// Token and session settings of a realm. Durations are given like 5m or 10h and sent
+// to keycloak in seconds, settings that are not set are left as they are in keycloak.
+type KeycloakRealmTokenSettings struct {
+	// Access Token Lifespan
+	// +optional
+	AccessTokenLifespan *metav1.Duration `json:"accessTokenLifespan,omitempty"`
+	// SSO Session Idle
+	// +optional
+	SSOSessionIdleTimeout *metav1.Duration `json:"ssoSessionIdleTimeout,omitempty"`
+	// SSO Session Max
+	// +optional
+	SSOSessionMaxLifespan *metav1.Duration `json:"ssoSessionMaxLifespan,omitempty"`
+	// Offline Session Idle
+	// +optional
+	OfflineSessionIdleTimeout *metav1.Duration `json:"offlineSessionIdleTimeout,omitempty"`
+	// Revoke Refresh Token
+	// +optional
+	RevokeRefreshToken *bool `json:"revokeRefreshToken,omitempty"`
+	// Refresh Token Max Reuse, only used when refresh tokens are revoked
+	// +optional
+	RefreshTokenMaxReuse *int32 `json:"refreshTokenMaxReuse,omitempty"`
+}
+
+// Login settings of a realm, settings that are not set are left as they are in keycloak.
+type KeycloakRealmLoginSettings struct {
+	// User registration
+	// +optional
+	RegistrationAllowed *bool `json:"registrationAllowed,omitempty"`
+	// Email as username
+	// +optional
+	RegistrationEmailAsUsername *bool `json:"registrationEmailAsUsername,omitempty"`
+	// Edit username
+	// +optional
+	EditUsernameAllowed *bool `json:"editUsernameAllowed,omitempty"`
+	// Forgot password
+	// +optional
+	ResetPasswordAllowed *bool `json:"resetPasswordAllowed,omitempty"`
+	// Remember me
+	// +optional
+	RememberMe *bool `json:"rememberMe,omitempty"`
+	// Verify email
+	// +optional
+	VerifyEmail *bool `json:"verifyEmail,omitempty"`
+	// Login with email
+	// +optional
+	LoginWithEmailAllowed *bool `json:"loginWithEmailAllowed,omitempty"`
+}
+
+// Themes of a realm by name, themes that are not set are left as they are in keycloak.
+type KeycloakRealmThemes struct {
+	// Login Theme
+	// +optional
+	LoginTheme string `json:"loginTheme,omitempty"`
+	// Account Theme
+	// +optional
+	AccountTheme string `json:"accountTheme,omitempty"`
+	// Email Theme
+	// +optional
+	EmailTheme string `json:"emailTheme,omitempty"`
+	// Admin Console Theme
+	// +optional
+	AdminTheme string `json:"adminTheme,omitempty"`
+}
+
+// WebAuthn policy of a realm, settings that are not set are left as they are in keycloak.
+type KeycloakRealmWebAuthnPolicy struct {
+	// Relying party entity name, shown to users when they register an authenticator
+	// +optional
+	RpEntityName string `json:"rpEntityName,omitempty"`
+	// Signature algorithms accepted from authenticators, out of ES256, ES384, ES512,
+	// RS256, RS384, RS512 and RS1
+	// +optional
+	// +listType=set
+	SignatureAlgorithms []string `json:"signatureAlgorithms,omitempty"`
+	// Relying party ID, the domain of Keycloak when not set
+	// +optional
+	RpID string `json:"rpId,omitempty"`
+	// Attestation conveyance preference
+	// +optional
+	// +kubebuilder:validation:Enum="not specified";none;indirect;direct
+	AttestationConveyancePreference string `json:"attestationConveyancePreference,omitempty"`
+	// Authenticator attachment
+	// +optional
+	// +kubebuilder:validation:Enum="not specified";platform;cross-platform
+	AuthenticatorAttachment string `json:"authenticatorAttachment,omitempty"`
+	// Require resident key
+	// +optional
+	// +kubebuilder:validation:Enum="not specified";"Yes";"No"
+	RequireResidentKey string `json:"requireResidentKey,omitempty"`
+	// User verification requirement
+	// +optional
+	// +kubebuilder:validation:Enum="not specified";required;preferred;discouraged
+	UserVerificationRequirement string `json:"userVerificationRequirement,omitempty"`
+	// Timeout of the registration in seconds, 0 for none
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	CreateTimeout *int32 `json:"createTimeout,omitempty"`
+	// Avoid registering the same authenticator twice
+	// +optional
+	AvoidSameAuthenticatorRegister *bool `json:"avoidSameAuthenticatorRegister,omitempty"`
+	// AAGUIDs of the authenticator models accepted, all of them when empty
+	// +optional
+	// +listType=set
+	AcceptableAaguids []string `json:"acceptableAaguids,omitempty"`
+}
+
+const (
+	WebAuthnNotSpecified = "not specified"
+)
+
+const (
+	SslRequiredAll      = "all"
+	SslRequiredExternal = "external"
+	SslRequiredNone     = "none"
+)
+
 type KeycloakAPIRealm struct {
 	// +kubebuilder:validation:Required
 	// +optional
@@ -41,9 +421,14 @@ type KeycloakAPIRealm struct {
 	// A set of Keycloak Clients.
 	// +optional
 	Clients []*KeycloakAPIClient `json:"clients,omitempty"`
-	// A set of Identity Providers.
+	// A set of Identity Providers, matched by alias. When set, identity providers not
+	// listed here are removed from the realm.
 	// +optional
 	IdentityProviders []*KeycloakIdentityProvider `json:"identityProviders,omitempty"`
+	// A set of Identity Provider Mappers, matched by name within their identity provider.
+	// When set, mappers not listed here are removed from the identity providers.
+	// +optional
+	IdentityProviderMappers []KeycloakIdentityProviderMapper `json:"identityProviderMappers,omitempty"`
 	// A set of Event Listeners.
 	// +optional
 	EventsListeners []string `json:"eventsListeners,omitempty"`
@@ -62,9 +447,10 @@ type KeycloakAPIRealm struct {
 
 	// Client scopes
 	// +optional
-	ClientScopes []KeycloakClientScope `json:"clientScopes,omitempty"`
+	ClientScopes []KeycloakAPIClientScope `json:"clientScopes,omitempty"`
 
-	// Authentication flows
+	// Authentication flows. Once the realm exists, top level flows that are not built in
+	// are replaced as a whole when they change, and removed when not listed here.
 	// +optional
 	AuthenticationFlows []KeycloakAPIAuthenticationFlow `json:"authenticationFlows,omitempty"`
 
@@ -72,13 +458,23 @@ type KeycloakAPIRealm struct {
 	// +optional
 	AuthenticatorConfig []KeycloakAPIAuthenticatorConfig `json:"authenticatorConfig,omitempty"`
 
+	// Alias of the flow bound to browser authentication
+	// +optional
+	BrowserFlow string `json:"browserFlow,omitempty"`
+
+	// Alias of the flow bound to direct access grants
+	// +optional
+	DirectGrantFlow string `json:"directGrantFlow,omitempty"`
+
 	// Point keycloak to an external user provider to validate
-	// credentials or pull in identity information.
+	// credentials or pull in identity information. Providers are matched by display name.
+	// When set, providers not listed here are removed from the realm.
 	// +optional
 	UserFederationProviders []KeycloakAPIUserFederationProvider `json:"userFederationProviders,omitempty"`
 
 	// User federation mappers are extension points triggered by the
-	// user federation at various points.
+	// user federation at various points. Mappers are matched by name within their
+	// provider. When set, mappers not listed here are removed from the listed providers.
 	// +optional
 	UserFederationMappers []KeycloakAPIUserFederationMapper `json:"userFederationMappers,omitempty"`
 
@@ -106,11 +502,15 @@ type KeycloakAPIRealm struct {
 	// Duplicate emails
 	// +optional
 	DuplicateEmailsAllowed *bool `json:"duplicateEmailsAllowed,omitempty"`
-	// Require SSL
+	// Require SSL for all requests, for requests from external addresses only, or
+	// for none. Behind a proxy that terminates TLS keycloak only sees the address of
+	// the proxy, "external" then requires the proxy to forward the original scheme.
 	// +optional
+	// +kubebuilder:validation:Enum=all;external;none
 	SslRequired string `json:"sslRequired,omitempty"`
 
-	// Brute Force Detection
+	// Brute Force Detection. When turned off, the settings below are reset to the
+	// defaults of keycloak.
 	// +optional
 	BruteForceProtected *bool `json:"bruteForceProtected,omitempty"`
 	// Permanent Lockout
@@ -135,6 +535,50 @@ type KeycloakAPIRealm struct {
 	// +optional
 	MaxDeltaTimeSeconds *int32 `json:"maxDeltaTimeSeconds,omitempty"`
 
+	// Password Policy, policies joined by "and", e.g. length(12) and digits(1) and notUsername
+	// +optional
+	PasswordPolicy string `json:"passwordPolicy,omitempty"`
+	// OTP Type
+	// +optional
+	// +kubebuilder:validation:Enum=totp;hotp
+	OTPPolicyType string `json:"otpPolicyType,omitempty"`
+	// OTP Hash Algorithm
+	// +optional
+	// +kubebuilder:validation:Enum=HmacSHA1;HmacSHA256;HmacSHA512
+	OTPPolicyAlgorithm string `json:"otpPolicyAlgorithm,omitempty"`
+	// Number of Digits
+	// +optional
+	// +kubebuilder:validation:Enum=6;8
+	OTPPolicyDigits *int32 `json:"otpPolicyDigits,omitempty"`
+	// OTP Token Period
+	// +optional
+	OTPPolicyPeriod *int32 `json:"otpPolicyPeriod,omitempty"`
+	// Look Ahead Window
+	// +optional
+	OTPPolicyLookAheadWindow *int32 `json:"otpPolicyLookAheadWindow,omitempty"`
+	// Initial Counter, for hotp
+	// +optional
+	OTPPolicyInitialCounter *int32 `json:"otpPolicyInitialCounter,omitempty"`
+
+	// Access Token Lifespan in seconds
+	// +optional
+	AccessTokenLifespan *int32 `json:"accessTokenLifespan,omitempty"`
+	// SSO Session Idle in seconds
+	// +optional
+	SsoSessionIdleTimeout *int32 `json:"ssoSessionIdleTimeout,omitempty"`
+	// SSO Session Max in seconds
+	// +optional
+	SsoSessionMaxLifespan *int32 `json:"ssoSessionMaxLifespan,omitempty"`
+	// Offline Session Idle in seconds
+	// +optional
+	OfflineSessionIdleTimeout *int32 `json:"offlineSessionIdleTimeout,omitempty"`
+	// Revoke Refresh Token
+	// +optional
+	RevokeRefreshToken *bool `json:"revokeRefreshToken,omitempty"`
+	// Refresh Token Max Reuse
+	// +optional
+	RefreshTokenMaxReuse *int32 `json:"refreshTokenMaxReuse,omitempty"`
+
 	// Email
 	// +optional
 	SMTPServer map[string]string `json:"smtpServer,omitempty"`
@@ -151,6 +595,70 @@ type KeycloakAPIRealm struct {
 	// Email Theme
 	// +optional
 	EmailTheme string `json:"emailTheme,omitempty"`
+	// WebAuthn Policy Rp Entity Name
+	// +optional
+	WebAuthnPolicyRpEntityName string `json:"webAuthnPolicyRpEntityName,omitempty"`
+	// WebAuthn Policy Signature Algorithms
+	// +optional
+	// +listType=atomic
+	WebAuthnPolicySignatureAlgorithms []string `json:"webAuthnPolicySignatureAlgorithms,omitempty"`
+	// WebAuthn Policy Rp Id
+	// +optional
+	WebAuthnPolicyRpID string `json:"webAuthnPolicyRpId,omitempty"`
+	// WebAuthn Policy Attestation Conveyance Preference
+	// +optional
+	WebAuthnPolicyAttestationConveyancePreference string `json:"webAuthnPolicyAttestationConveyancePreference,omitempty"`
+	// WebAuthn Policy Authenticator Attachment
+	// +optional
+	WebAuthnPolicyAuthenticatorAttachment string `json:"webAuthnPolicyAuthenticatorAttachment,omitempty"`
+	// WebAuthn Policy Require Resident Key
+	// +optional
+	WebAuthnPolicyRequireResidentKey string `json:"webAuthnPolicyRequireResidentKey,omitempty"`
+	// WebAuthn Policy User Verification Requirement
+	// +optional
+	WebAuthnPolicyUserVerificationRequirement string `json:"webAuthnPolicyUserVerificationRequirement,omitempty"`
+	// WebAuthn Policy Create Timeout
+	// +optional
+	WebAuthnPolicyCreateTimeout *int32 `json:"webAuthnPolicyCreateTimeout,omitempty"`
+	// WebAuthn Policy Avoid Same Authenticator Register
+	// +optional
+	WebAuthnPolicyAvoidSameAuthenticatorRegister *bool `json:"webAuthnPolicyAvoidSameAuthenticatorRegister,omitempty"`
+	// WebAuthn Policy Acceptable AAGUIDs
+	// +optional
+	// +listType=atomic
+	WebAuthnPolicyAcceptableAaguids []string `json:"webAuthnPolicyAcceptableAaguids,omitempty"`
+	// WebAuthn Policy Rp Entity Name
+	// +optional
+	WebAuthnPolicyPasswordlessRpEntityName string `json:"webAuthnPolicyPasswordlessRpEntityName,omitempty"`
+	// WebAuthn Policy Signature Algorithms
+	// +optional
+	// +listType=atomic
+	WebAuthnPolicyPasswordlessSignatureAlgorithms []string `json:"webAuthnPolicyPasswordlessSignatureAlgorithms,omitempty"`
+	// WebAuthn Policy Rp Id
+	// +optional
+	WebAuthnPolicyPasswordlessRpID string `json:"webAuthnPolicyPasswordlessRpId,omitempty"`
+	// WebAuthn Policy Attestation Conveyance Preference
+	// +optional
+	WebAuthnPolicyPasswordlessAttestationConveyancePreference string `json:"webAuthnPolicyPasswordlessAttestationConveyancePreference,omitempty"`
+	// WebAuthn Policy Authenticator Attachment
+	// +optional
+	WebAuthnPolicyPasswordlessAuthenticatorAttachment string `json:"webAuthnPolicyPasswordlessAuthenticatorAttachment,omitempty"`
+	// WebAuthn Policy Require Resident Key
+	// +optional
+	WebAuthnPolicyPasswordlessRequireResidentKey string `json:"webAuthnPolicyPasswordlessRequireResidentKey,omitempty"`
+	// WebAuthn Policy User Verification Requirement
+	// +optional
+	WebAuthnPolicyPasswordlessUserVerificationRequirement string `json:"webAuthnPolicyPasswordlessUserVerificationRequirement,omitempty"`
+	// WebAuthn Policy Create Timeout
+	// +optional
+	WebAuthnPolicyPasswordlessCreateTimeout *int32 `json:"webAuthnPolicyPasswordlessCreateTimeout,omitempty"`
+	// WebAuthn Policy Avoid Same Authenticator Register
+	// +optional
+	WebAuthnPolicyPasswordlessAvoidSameAuthenticatorRegister *bool `json:"webAuthnPolicyPasswordlessAvoidSameAuthenticatorRegister,omitempty"`
+	// WebAuthn Policy Acceptable AAGUIDs
+	// +optional
+	// +listType=atomic
+	WebAuthnPolicyPasswordlessAcceptableAaguids []string `json:"webAuthnPolicyPasswordlessAcceptableAaguids,omitempty"`
 	// Internationalization Enabled
 	// +optional
 	InternationalizationEnabled *bool `json:"internationalizationEnabled,omitempty"`
@@ -251,6 +759,45 @@ type KeycloakAPIUserFederationProvider struct {
 	// The name of the user provider, such as "ldap", "kerberos" or a custom SPI.
 	// +optional
 	ProviderName string `json:"providerName,omitempty"`
+
+	// User federation provider config values read from secrets, e.g. bindCredential,
+	// keyed by the config value they provide. They take precedence over config.
+	// +optional
+	ConfigSecrets map[string]corev1.SecretKeySelector `json:"configSecrets,omitempty"`
+}
+
+// Result of a user federation sync
+// https://www.keycloak.org/docs-api/11.0/rest-api/index.html#_synchronizationresult
+type KeycloakAPISynchronizationResult struct {
+	// +optional
+	Added int32 `json:"added,omitempty"`
+	// +optional
+	Updated int32 `json:"updated,omitempty"`
+	// +optional
+	Removed int32 `json:"removed,omitempty"`
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+	// +optional
+	Status string `json:"status,omitempty"`
+}
+
+// Component of a realm, as user federation providers and their mappers are stored by keycloak.
+// https://www.keycloak.org/docs-api/11.0/rest-api/index.html#_componentrepresentation
+type KeycloakAPIComponent struct {
+	// +optional
+	ID string `json:"id,omitempty"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	ProviderID string `json:"providerId,omitempty"`
+	// +optional
+	ProviderType string `json:"providerType,omitempty"`
+	// +optional
+	ParentID string `json:"parentId,omitempty"`
+	// +optional
+	SubType string `json:"subType,omitempty"`
+	// +optional
+	Config map[string][]string `json:"config,omitempty"`
 }
 
 //
@@ -351,7 +898,7 @@ type RedirectorIdentityProviderOverride struct {
 	ForFlow string `json:"forFlow,omitempty"`
 }
 
-type KeycloakClientScope struct {
+type KeycloakAPIClientScope struct {
 	// +optional
 	Attributes map[string]string `json:"attributes,omitempty"`
 	// +optional
@@ -404,6 +951,30 @@ type KeycloakIdentityProvider struct {
 	// Identity Provider config.
 	// +optional
 	Config map[string]string `json:"config,omitempty"`
+	// Identity Provider config values read from Secrets in the namespace of the
+	// KeycloakRealm, keyed by config name, e.g. clientSecret. They take precedence
+	// over the values in config.
+	// +optional
+	ConfigSecrets map[string]corev1.SecretKeySelector `json:"configSecrets,omitempty"`
+}
+
+// https://www.keycloak.org/docs-api/11.0/rest-api/index.html#_identityprovidermapperrepresentation
+type KeycloakIdentityProviderMapper struct {
+	// Identity Provider Mapper ID.
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Identity Provider Mapper Name.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Alias of the Identity Provider the mapper belongs to.
+	// +kubebuilder:validation:Required
+	IdentityProviderAlias string `json:"identityProviderAlias"`
+	// Identity Provider Mapper type, e.g. hardcoded-role-idp-mapper.
+	// +kubebuilder:validation:Required
+	IdentityProviderMapper string `json:"identityProviderMapper"`
+	// Identity Provider Mapper config.
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
 }
 
 type KeycloakUserRole struct {
@@ -415,6 +986,38 @@ type KeycloakUserRole struct {
 	ContainerID string `json:"containerId,omitempty"`
 }
 
+// https://www.keycloak.org/docs-api/11.0/rest-api/index.html#_grouprepresentation
+type KeycloakAPIGroup struct {
+	// Group ID. When set, a group renamed or moved in the CR keeps its members.
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Group Name.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Group Attributes.
+	// +optional
+	Attributes map[string][]string `json:"attributes,omitempty"`
+	// Realm Roles mapped to the group. When set, other realm role mappings are removed.
+	// +optional
+	RealmRoles []string `json:"realmRoles,omitempty"`
+	// Client Roles mapped to the group, keyed by client ID. When set, other client role
+	// mappings are removed.
+	// +optional
+	ClientRoles map[string][]string `json:"clientRoles,omitempty"`
+	// Sub Groups, with the same fields as their parent.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=array
+	SubGroups []KeycloakAPIGroup `json:"subGroups,omitempty"`
+}
+
+type KeycloakUserGroup struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
 type AuthenticatorConfig struct {
 	// Authenticator Config Alias.
 	// +optional
@@ -521,6 +1124,37 @@ type KeycloakRealmStatus struct {
 	SecondaryResources map[string][]string `json:"secondaryResources,omitempty"`
 	// TODO
 	LoginURL string `json:"loginURL"`
+	// HMAC of the SMTP server settings last pushed to keycloak, which doesn't return the stored password.
+	// It is keyed by the UID of the realm, so the same settings hash differently in every realm
+	// +optional
+	SMTPServerHash string `json:"smtpServerHash,omitempty"`
+	// HMACs of the config values read from secrets last pushed to each user federation provider, keyed by display name.
+	// They are keyed by the UID of the realm, like smtpServerHash
+	// +optional
+	UserFederationSecretHashes map[string]string `json:"userFederationSecretHashes,omitempty"`
+	// Results of the last user federation syncs triggered through the keycloak.org/sync-federation annotation, keyed by display name
+	// +optional
+	UserFederationSyncResults map[string]KeycloakAPISynchronizationResult `json:"userFederationSyncResults,omitempty"`
+	// Hash of the realm JSON last imported through realmImport
+	// +optional
+	RealmImportHash string `json:"realmImportHash,omitempty"`
+	// Hash of the users last imported through userImport
+	// +optional
+	UserImportHash string `json:"userImportHash,omitempty"`
+	// Outcome of the last import of the users listed in userImport
+	// +optional
+	UserImportResults []KeycloakRealmUserImportResult `json:"userImportResults,omitempty"`
+	// Time of the last export asked for through the keycloak.org/export annotation, in RFC 3339 format
+	// +optional
+	LastExportTime string `json:"lastExportTime,omitempty"`
+	// Name of the Secret the last export was written to
+	// +optional
+	LastExportSecret string `json:"lastExportSecret,omitempty"`
+	// Conditions of the resource following the Kubernetes conventions, i.e. Ready, Reconciling and Error.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []Condition `json:"conditions,omitempty"`
 }
 
 // KeycloakRealm is the Schema for the keycloakrealms API
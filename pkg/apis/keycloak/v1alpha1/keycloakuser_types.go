@@ -22,6 +22,44 @@ type KeycloakUserSpec struct {
 	// Keycloak User REST object.
 	// +kubebuilder:validation:Required
 	User KeycloakAPIUser `json:"user"`
+	// How user.attributes are applied to the user in Keycloak. With "replace", the default,
+	// attributes not listed are removed. With "merge", only the listed attributes are set,
+	// and the ones listed without values are removed, so that attributes written by user
+	// federation and others are kept.
+	// +optional
+	// +kubebuilder:validation:Enum=replace;merge
+	AttributesPolicy string `json:"attributesPolicy,omitempty"`
+	// Email asking the user to perform actions, e.g. to verify the email address. It is sent
+	// once when the user is created, and again whenever the keycloak.org/send-actions-email
+	// annotation is added. Needs the SMTP server of the realm to be configured.
+	// +optional
+	ActionsEmail *KeycloakUserActionsEmail `json:"actionsEmail,omitempty"`
+	// How often the KeycloakUser is reconciled again after a successful reconcile, which corrects the
+	// changes made in Keycloak meanwhile. Defaults to the --resync-period of the operator.
+	// +optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+}
+
+const (
+	UserAttributesPolicyReplace = "replace"
+	UserAttributesPolicyMerge   = "merge"
+)
+
+type KeycloakUserActionsEmail struct {
+	// Required actions the email asks for, e.g. VERIFY_EMAIL or UPDATE_PASSWORD.
+	// Defaults to VERIFY_EMAIL.
+	// +optional
+	// +listType=set
+	Actions []string `json:"actions,omitempty"`
+	// Client the user is sent back to once the actions are done.
+	// +optional
+	ClientID string `json:"clientId,omitempty"`
+	// Where the user is sent once the actions are done, needs clientId.
+	// +optional
+	RedirectURI string `json:"redirectUri,omitempty"`
+	// How long the link in the email is valid, in seconds. Defaults to the setting of the realm.
+	// +optional
+	Lifespan *int32 `json:"lifespan,omitempty"`
 }
 
 // KeycloakUserStatus defines the observed state of KeycloakUser.
@@ -31,6 +69,14 @@ type KeycloakUserStatus struct {
 	Phase StatusPhase `json:"phase"`
 	// Human-readable message indicating details about current operator phase or error.
 	Message string `json:"message"`
+	// Conditions of the resource following the Kubernetes conventions, i.e. Ready, Reconciling and Error.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []Condition `json:"conditions,omitempty"`
+	// Time the last actions email was sent, in RFC 3339 format
+	// +optional
+	LastActionsEmailTime string `json:"lastActionsEmailTime,omitempty"`
 }
 
 // KeycloakUser is the Schema for the keycloakusers API.
@@ -76,10 +122,12 @@ type KeycloakAPIUser struct {
 	// A set of Required Actions.
 	// +optional
 	RequiredActions []string `json:"requiredActions,omitempty"`
-	// A set of Groups.
+	// A set of Groups, given by their path, e.g. /parent/child. When set on a KeycloakUser,
+	// the user is removed from groups not listed here.
 	// +optional
 	Groups []string `json:"groups,omitempty"`
-	// A set of Federated Identities.
+	// A set of Federated Identities. When set on a KeycloakUser, links to identity
+	// providers not listed here are removed.
 	// +optional
 	FederatedIdentities []FederatedIdentity `json:"federatedIdentities,omitempty"`
 	// A set of Credentials.
@@ -8,6 +8,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -70,6 +71,23 @@ func (in *BackupConfig) DeepCopy() *BackupConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapVolumeSpec) DeepCopyInto(out *ConfigMapVolumeSpec) {
 	*out = *in
@@ -251,6 +269,16 @@ func (in *KeycloakAPIAuthenticatorConfig) DeepCopy() *KeycloakAPIAuthenticatorCo
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeycloakAPIClient) DeepCopyInto(out *KeycloakAPIClient) {
 	*out = *in
+	if in.SurrogateAuthRequired != nil {
+		in, out := &in.SurrogateAuthRequired, &out.SurrogateAuthRequired
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
 	if in.DefaultRoles != nil {
 		in, out := &in.DefaultRoles, &out.DefaultRoles
 		*out = make([]string, len(*in))
@@ -266,6 +294,21 @@ func (in *KeycloakAPIClient) DeepCopyInto(out *KeycloakAPIClient) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.BearerOnly != nil {
+		in, out := &in.BearerOnly, &out.BearerOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ServiceAccountsEnabled != nil {
+		in, out := &in.ServiceAccountsEnabled, &out.ServiceAccountsEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FrontchannelLogout != nil {
+		in, out := &in.FrontchannelLogout, &out.FrontchannelLogout
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Attributes != nil {
 		in, out := &in.Attributes, &out.Attributes
 		*out = make(map[string]string, len(*in))
@@ -302,6 +345,23 @@ func (in *KeycloakAPIClient) DeepCopyInto(out *KeycloakAPIClient) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AuthorizationServicesEnabled != nil {
+		in, out := &in.AuthorizationServicesEnabled, &out.AuthorizationServicesEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AuthorizationSettings != nil {
+		in, out := &in.AuthorizationSettings, &out.AuthorizationSettings
+		*out = new(ResourceServerRepresentation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthenticationFlowBindingOverrides != nil {
+		in, out := &in.AuthenticationFlowBindingOverrides, &out.AuthenticationFlowBindingOverrides
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -315,6 +375,223 @@ func (in *KeycloakAPIClient) DeepCopy() *KeycloakAPIClient {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIClientPolicy) DeepCopyInto(out *KeycloakAPIClientPolicy) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]KeycloakAPIClientPolicyCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIClientPolicy.
+func (in *KeycloakAPIClientPolicy) DeepCopy() *KeycloakAPIClientPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIClientPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIClientPolicyCondition) DeepCopyInto(out *KeycloakAPIClientPolicyCondition) {
+	*out = *in
+	if in.Configuration != nil {
+		in, out := &in.Configuration, &out.Configuration
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIClientPolicyCondition.
+func (in *KeycloakAPIClientPolicyCondition) DeepCopy() *KeycloakAPIClientPolicyCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIClientPolicyCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIClientPolicyExecutor) DeepCopyInto(out *KeycloakAPIClientPolicyExecutor) {
+	*out = *in
+	if in.Configuration != nil {
+		in, out := &in.Configuration, &out.Configuration
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIClientPolicyExecutor.
+func (in *KeycloakAPIClientPolicyExecutor) DeepCopy() *KeycloakAPIClientPolicyExecutor {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIClientPolicyExecutor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIClientProfile) DeepCopyInto(out *KeycloakAPIClientProfile) {
+	*out = *in
+	if in.Executors != nil {
+		in, out := &in.Executors, &out.Executors
+		*out = make([]KeycloakAPIClientPolicyExecutor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIClientProfile.
+func (in *KeycloakAPIClientProfile) DeepCopy() *KeycloakAPIClientProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIClientProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIClientScope) DeepCopyInto(out *KeycloakAPIClientScope) {
+	*out = *in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ProtocolMappers != nil {
+		in, out := &in.ProtocolMappers, &out.ProtocolMappers
+		*out = make([]KeycloakProtocolMapper, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIClientScope.
+func (in *KeycloakAPIClientScope) DeepCopy() *KeycloakAPIClientScope {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIClientScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIComponent) DeepCopyInto(out *KeycloakAPIComponent) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIComponent.
+func (in *KeycloakAPIComponent) DeepCopy() *KeycloakAPIComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIGroup) DeepCopyInto(out *KeycloakAPIGroup) {
+	*out = *in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.RealmRoles != nil {
+		in, out := &in.RealmRoles, &out.RealmRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClientRoles != nil {
+		in, out := &in.ClientRoles, &out.ClientRoles
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.SubGroups != nil {
+		in, out := &in.SubGroups, &out.SubGroups
+		*out = make([]KeycloakAPIGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIGroup.
+func (in *KeycloakAPIGroup) DeepCopy() *KeycloakAPIGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeycloakAPIPasswordReset) DeepCopyInto(out *KeycloakAPIPasswordReset) {
 	*out = *in
@@ -367,6 +644,13 @@ func (in *KeycloakAPIRealm) DeepCopyInto(out *KeycloakAPIRealm) {
 			}
 		}
 	}
+	if in.IdentityProviderMappers != nil {
+		in, out := &in.IdentityProviderMappers, &out.IdentityProviderMappers
+		*out = make([]KeycloakIdentityProviderMapper, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.EventsListeners != nil {
 		in, out := &in.EventsListeners, &out.EventsListeners
 		*out = make([]string, len(*in))
@@ -389,7 +673,7 @@ func (in *KeycloakAPIRealm) DeepCopyInto(out *KeycloakAPIRealm) {
 	}
 	if in.ClientScopes != nil {
 		in, out := &in.ClientScopes, &out.ClientScopes
-		*out = make([]KeycloakClientScope, len(*in))
+		*out = make([]KeycloakAPIClientScope, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -502,6 +786,56 @@ func (in *KeycloakAPIRealm) DeepCopyInto(out *KeycloakAPIRealm) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.OTPPolicyDigits != nil {
+		in, out := &in.OTPPolicyDigits, &out.OTPPolicyDigits
+		*out = new(int32)
+		**out = **in
+	}
+	if in.OTPPolicyPeriod != nil {
+		in, out := &in.OTPPolicyPeriod, &out.OTPPolicyPeriod
+		*out = new(int32)
+		**out = **in
+	}
+	if in.OTPPolicyLookAheadWindow != nil {
+		in, out := &in.OTPPolicyLookAheadWindow, &out.OTPPolicyLookAheadWindow
+		*out = new(int32)
+		**out = **in
+	}
+	if in.OTPPolicyInitialCounter != nil {
+		in, out := &in.OTPPolicyInitialCounter, &out.OTPPolicyInitialCounter
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AccessTokenLifespan != nil {
+		in, out := &in.AccessTokenLifespan, &out.AccessTokenLifespan
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SsoSessionIdleTimeout != nil {
+		in, out := &in.SsoSessionIdleTimeout, &out.SsoSessionIdleTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SsoSessionMaxLifespan != nil {
+		in, out := &in.SsoSessionMaxLifespan, &out.SsoSessionMaxLifespan
+		*out = new(int32)
+		**out = **in
+	}
+	if in.OfflineSessionIdleTimeout != nil {
+		in, out := &in.OfflineSessionIdleTimeout, &out.OfflineSessionIdleTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RevokeRefreshToken != nil {
+		in, out := &in.RevokeRefreshToken, &out.RevokeRefreshToken
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RefreshTokenMaxReuse != nil {
+		in, out := &in.RefreshTokenMaxReuse, &out.RefreshTokenMaxReuse
+		*out = new(int32)
+		**out = **in
+	}
 	if in.SMTPServer != nil {
 		in, out := &in.SMTPServer, &out.SMTPServer
 		*out = make(map[string]string, len(*in))
@@ -509,20 +843,60 @@ func (in *KeycloakAPIRealm) DeepCopyInto(out *KeycloakAPIRealm) {
 			(*out)[key] = val
 		}
 	}
-	if in.InternationalizationEnabled != nil {
-		in, out := &in.InternationalizationEnabled, &out.InternationalizationEnabled
+	if in.WebAuthnPolicySignatureAlgorithms != nil {
+		in, out := &in.WebAuthnPolicySignatureAlgorithms, &out.WebAuthnPolicySignatureAlgorithms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WebAuthnPolicyCreateTimeout != nil {
+		in, out := &in.WebAuthnPolicyCreateTimeout, &out.WebAuthnPolicyCreateTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WebAuthnPolicyAvoidSameAuthenticatorRegister != nil {
+		in, out := &in.WebAuthnPolicyAvoidSameAuthenticatorRegister, &out.WebAuthnPolicyAvoidSameAuthenticatorRegister
 		*out = new(bool)
 		**out = **in
 	}
-	if in.SupportedLocales != nil {
-		in, out := &in.SupportedLocales, &out.SupportedLocales
+	if in.WebAuthnPolicyAcceptableAaguids != nil {
+		in, out := &in.WebAuthnPolicyAcceptableAaguids, &out.WebAuthnPolicyAcceptableAaguids
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Roles != nil {
-		in, out := &in.Roles, &out.Roles
-		*out = new(RolesRepresentation)
-		(*in).DeepCopyInto(*out)
+	if in.WebAuthnPolicyPasswordlessSignatureAlgorithms != nil {
+		in, out := &in.WebAuthnPolicyPasswordlessSignatureAlgorithms, &out.WebAuthnPolicyPasswordlessSignatureAlgorithms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WebAuthnPolicyPasswordlessCreateTimeout != nil {
+		in, out := &in.WebAuthnPolicyPasswordlessCreateTimeout, &out.WebAuthnPolicyPasswordlessCreateTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WebAuthnPolicyPasswordlessAvoidSameAuthenticatorRegister != nil {
+		in, out := &in.WebAuthnPolicyPasswordlessAvoidSameAuthenticatorRegister, &out.WebAuthnPolicyPasswordlessAvoidSameAuthenticatorRegister
+		*out = new(bool)
+		**out = **in
+	}
+	if in.WebAuthnPolicyPasswordlessAcceptableAaguids != nil {
+		in, out := &in.WebAuthnPolicyPasswordlessAcceptableAaguids, &out.WebAuthnPolicyPasswordlessAcceptableAaguids
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InternationalizationEnabled != nil {
+		in, out := &in.InternationalizationEnabled, &out.InternationalizationEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SupportedLocales != nil {
+		in, out := &in.SupportedLocales, &out.SupportedLocales
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = new(RolesRepresentation)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -537,6 +911,106 @@ func (in *KeycloakAPIRealm) DeepCopy() *KeycloakAPIRealm {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIRealmEventsConfig) DeepCopyInto(out *KeycloakAPIRealmEventsConfig) {
+	*out = *in
+	if in.EventsEnabled != nil {
+		in, out := &in.EventsEnabled, &out.EventsEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EventsExpiration != nil {
+		in, out := &in.EventsExpiration, &out.EventsExpiration
+		*out = new(int64)
+		**out = **in
+	}
+	if in.EventsListeners != nil {
+		in, out := &in.EventsListeners, &out.EventsListeners
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EnabledEventTypes != nil {
+		in, out := &in.EnabledEventTypes, &out.EnabledEventTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdminEventsEnabled != nil {
+		in, out := &in.AdminEventsEnabled, &out.AdminEventsEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AdminEventsDetailsEnabled != nil {
+		in, out := &in.AdminEventsDetailsEnabled, &out.AdminEventsDetailsEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIRealmEventsConfig.
+func (in *KeycloakAPIRealmEventsConfig) DeepCopy() *KeycloakAPIRealmEventsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIRealmEventsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPIRequiredAction) DeepCopyInto(out *KeycloakAPIRequiredAction) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultAction != nil {
+		in, out := &in.DefaultAction, &out.DefaultAction
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPIRequiredAction.
+func (in *KeycloakAPIRequiredAction) DeepCopy() *KeycloakAPIRequiredAction {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPIRequiredAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAPISynchronizationResult) DeepCopyInto(out *KeycloakAPISynchronizationResult) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAPISynchronizationResult.
+func (in *KeycloakAPISynchronizationResult) DeepCopy() *KeycloakAPISynchronizationResult {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAPISynchronizationResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeycloakAPIUser) DeepCopyInto(out *KeycloakAPIUser) {
 	*out = *in
@@ -651,6 +1125,13 @@ func (in *KeycloakAPIUserFederationProvider) DeepCopyInto(out *KeycloakAPIUserFe
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ConfigSecrets != nil {
+		in, out := &in.ConfigSecrets, &out.ConfigSecrets
+		*out = make(map[string]v1.SecretKeySelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -680,6 +1161,111 @@ func (in *KeycloakAWSSpec) DeepCopy() *KeycloakAWSSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAdminClientCACertificate) DeepCopyInto(out *KeycloakAdminClientCACertificate) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAdminClientCACertificate.
+func (in *KeycloakAdminClientCACertificate) DeepCopy() *KeycloakAdminClientCACertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAdminClientCACertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAdminClientCredentialsRef) DeepCopyInto(out *KeycloakAdminClientCredentialsRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAdminClientCredentialsRef.
+func (in *KeycloakAdminClientCredentialsRef) DeepCopy() *KeycloakAdminClientCredentialsRef {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAdminClientCredentialsRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAdminClientProxy) DeepCopyInto(out *KeycloakAdminClientProxy) {
+	*out = *in
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAdminClientProxy.
+func (in *KeycloakAdminClientProxy) DeepCopy() *KeycloakAdminClientProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAdminClientProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAdminCredentialsRef) DeepCopyInto(out *KeycloakAdminCredentialsRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAdminCredentialsRef.
+func (in *KeycloakAdminCredentialsRef) DeepCopy() *KeycloakAdminCredentialsRef {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAdminCredentialsRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakAutoscaling) DeepCopyInto(out *KeycloakAutoscaling) {
+	*out = *in
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetMemoryUtilizationPercentage != nil {
+		in, out := &in.TargetMemoryUtilizationPercentage, &out.TargetMemoryUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakAutoscaling.
+func (in *KeycloakAutoscaling) DeepCopy() *KeycloakAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeycloakBackup) DeepCopyInto(out *KeycloakBackup) {
 	*out = *in
@@ -744,6 +1330,7 @@ func (in *KeycloakBackupList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeycloakBackupSpec) DeepCopyInto(out *KeycloakBackupSpec) {
 	*out = *in
+	out.RestoreOptions = in.RestoreOptions
 	out.AWS = in.AWS
 	if in.InstanceSelector != nil {
 		in, out := &in.InstanceSelector, &out.InstanceSelector
@@ -827,6 +1414,27 @@ func (in *KeycloakClient) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakClientConsent) DeepCopyInto(out *KeycloakClientConsent) {
+	*out = *in
+	if in.DisplayOnConsentScreen != nil {
+		in, out := &in.DisplayOnConsentScreen, &out.DisplayOnConsentScreen
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientConsent.
+func (in *KeycloakClientConsent) DeepCopy() *KeycloakClientConsent {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakClientConsent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeycloakClientList) DeepCopyInto(out *KeycloakClientList) {
 	*out = *in
@@ -861,385 +1469,1384 @@ func (in *KeycloakClientList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakClientScope) DeepCopyInto(out *KeycloakClientScope) {
+func (in *KeycloakClientLogout) DeepCopyInto(out *KeycloakClientLogout) {
 	*out = *in
-	if in.Attributes != nil {
-		in, out := &in.Attributes, &out.Attributes
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.FrontchannelLogoutSessionRequired != nil {
+		in, out := &in.FrontchannelLogoutSessionRequired, &out.FrontchannelLogoutSessionRequired
+		*out = new(bool)
+		**out = **in
 	}
-	if in.ProtocolMappers != nil {
-		in, out := &in.ProtocolMappers, &out.ProtocolMappers
-		*out = make([]KeycloakProtocolMapper, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.BackchannelLogoutSessionRequired != nil {
+		in, out := &in.BackchannelLogoutSessionRequired, &out.BackchannelLogoutSessionRequired
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BackchannelLogoutRevokeOfflineTokens != nil {
+		in, out := &in.BackchannelLogoutRevokeOfflineTokens, &out.BackchannelLogoutRevokeOfflineTokens
+		*out = new(bool)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientScope.
-func (in *KeycloakClientScope) DeepCopy() *KeycloakClientScope {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientLogout.
+func (in *KeycloakClientLogout) DeepCopy() *KeycloakClientLogout {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakClientScope)
+	out := new(KeycloakClientLogout)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakClientSpec) DeepCopyInto(out *KeycloakClientSpec) {
+func (in *KeycloakClientSAML) DeepCopyInto(out *KeycloakClientSAML) {
 	*out = *in
-	if in.RealmSelector != nil {
-		in, out := &in.RealmSelector, &out.RealmSelector
-		*out = new(metav1.LabelSelector)
+	if in.SigningCertificateRef != nil {
+		in, out := &in.SigningCertificateRef, &out.SigningCertificateRef
+		*out = new(v1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Client != nil {
-		in, out := &in.Client, &out.Client
-		*out = new(KeycloakAPIClient)
+	if in.EncryptionCertificateRef != nil {
+		in, out := &in.EncryptionCertificateRef, &out.EncryptionCertificateRef
+		*out = new(v1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Roles != nil {
-		in, out := &in.Roles, &out.Roles
-		*out = make([]RoleRepresentation, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.ServerSignature != nil {
+		in, out := &in.ServerSignature, &out.ServerSignature
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AssertionSignature != nil {
+		in, out := &in.AssertionSignature, &out.AssertionSignature
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ClientSignature != nil {
+		in, out := &in.ClientSignature, &out.ClientSignature
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EncryptAssertions != nil {
+		in, out := &in.EncryptAssertions, &out.EncryptAssertions
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ForcePostBinding != nil {
+		in, out := &in.ForcePostBinding, &out.ForcePostBinding
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AuthnStatement != nil {
+		in, out := &in.AuthnStatement, &out.AuthnStatement
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ForceNameIDFormat != nil {
+		in, out := &in.ForceNameIDFormat, &out.ForceNameIDFormat
+		*out = new(bool)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientSpec.
-func (in *KeycloakClientSpec) DeepCopy() *KeycloakClientSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientSAML.
+func (in *KeycloakClientSAML) DeepCopy() *KeycloakClientSAML {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakClientSpec)
+	out := new(KeycloakClientSAML)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakClientStatus) DeepCopyInto(out *KeycloakClientStatus) {
+func (in *KeycloakClientScope) DeepCopyInto(out *KeycloakClientScope) {
 	*out = *in
-	if in.SecondaryResources != nil {
-		in, out := &in.SecondaryResources, &out.SecondaryResources
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientStatus.
-func (in *KeycloakClientStatus) DeepCopy() *KeycloakClientStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientScope.
+func (in *KeycloakClientScope) DeepCopy() *KeycloakClientScope {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakClientStatus)
+	out := new(KeycloakClientScope)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakCredential) DeepCopyInto(out *KeycloakCredential) {
-	*out = *in
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakCredential.
-func (in *KeycloakCredential) DeepCopy() *KeycloakCredential {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakClientScope) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(KeycloakCredential)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakDeploymentSpec) DeepCopyInto(out *KeycloakDeploymentSpec) {
+func (in *KeycloakClientScopeList) DeepCopyInto(out *KeycloakClientScopeList) {
 	*out = *in
-	in.DeploymentSpec.DeepCopyInto(&out.DeploymentSpec)
-	in.Experimental.DeepCopyInto(&out.Experimental)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeycloakClientScope, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakDeploymentSpec.
-func (in *KeycloakDeploymentSpec) DeepCopy() *KeycloakDeploymentSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientScopeList.
+func (in *KeycloakClientScopeList) DeepCopy() *KeycloakClientScopeList {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakDeploymentSpec)
+	out := new(KeycloakClientScopeList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakClientScopeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakExternal) DeepCopyInto(out *KeycloakExternal) {
+func (in *KeycloakClientScopeSpec) DeepCopyInto(out *KeycloakClientScopeSpec) {
 	*out = *in
+	if in.RealmSelector != nil {
+		in, out := &in.RealmSelector, &out.RealmSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientScope != nil {
+		in, out := &in.ClientScope, &out.ClientScope
+		*out = new(KeycloakAPIClientScope)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakExternal.
-func (in *KeycloakExternal) DeepCopy() *KeycloakExternal {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientScopeSpec.
+func (in *KeycloakClientScopeSpec) DeepCopy() *KeycloakClientScopeSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakExternal)
+	out := new(KeycloakClientScopeSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakExternalAccess) DeepCopyInto(out *KeycloakExternalAccess) {
+func (in *KeycloakClientScopeStatus) DeepCopyInto(out *KeycloakClientScopeStatus) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakExternalAccess.
-func (in *KeycloakExternalAccess) DeepCopy() *KeycloakExternalAccess {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientScopeStatus.
+func (in *KeycloakClientScopeStatus) DeepCopy() *KeycloakClientScopeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakExternalAccess)
+	out := new(KeycloakClientScopeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakExternalDatabase) DeepCopyInto(out *KeycloakExternalDatabase) {
+func (in *KeycloakClientSpec) DeepCopyInto(out *KeycloakClientSpec) {
 	*out = *in
+	if in.RealmSelector != nil {
+		in, out := &in.RealmSelector, &out.RealmSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Client != nil {
+		in, out := &in.Client, &out.Client
+		*out = new(KeycloakAPIClient)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]RoleRepresentation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServiceAccountRealmRoles != nil {
+		in, out := &in.ServiceAccountRealmRoles, &out.ServiceAccountRealmRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceAccountClientRoles != nil {
+		in, out := &in.ServiceAccountClientRoles, &out.ServiceAccountClientRoles
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.SecretRotationPeriod != nil {
+		in, out := &in.SecretRotationPeriod, &out.SecretRotationPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScopeMappings != nil {
+		in, out := &in.ScopeMappings, &out.ScopeMappings
+		*out = new(RoleRepresentationComposites)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultRoles != nil {
+		in, out := &in.DefaultRoles, &out.DefaultRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SAML != nil {
+		in, out := &in.SAML, &out.SAML
+		*out = new(KeycloakClientSAML)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TokenSettings != nil {
+		in, out := &in.TokenSettings, &out.TokenSettings
+		*out = new(KeycloakClientTokenSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Consent != nil {
+		in, out := &in.Consent, &out.Consent
+		*out = new(KeycloakClientConsent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logout != nil {
+		in, out := &in.Logout, &out.Logout
+		*out = new(KeycloakClientLogout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionGracePeriod != nil {
+		in, out := &in.DeletionGracePeriod, &out.DeletionGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.AuthFlowOverrides != nil {
+		in, out := &in.AuthFlowOverrides, &out.AuthFlowOverrides
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakExternalDatabase.
-func (in *KeycloakExternalDatabase) DeepCopy() *KeycloakExternalDatabase {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientSpec.
+func (in *KeycloakClientSpec) DeepCopy() *KeycloakClientSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakExternalDatabase)
+	out := new(KeycloakClientSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakIdentityProvider) DeepCopyInto(out *KeycloakIdentityProvider) {
+func (in *KeycloakClientStatus) DeepCopyInto(out *KeycloakClientStatus) {
 	*out = *in
-	if in.Config != nil {
-		in, out := &in.Config, &out.Config
-		*out = make(map[string]string, len(*in))
+	if in.SecondaryResources != nil {
+		in, out := &in.SecondaryResources, &out.SecondaryResources
+		*out = make(map[string][]string, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.ManagedRoles != nil {
+		in, out := &in.ManagedRoles, &out.ManagedRoles
+		*out = make([]ManagedRole, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedTokenSettings != nil {
+		in, out := &in.ManagedTokenSettings, &out.ManagedTokenSettings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakIdentityProvider.
-func (in *KeycloakIdentityProvider) DeepCopy() *KeycloakIdentityProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientStatus.
+func (in *KeycloakClientStatus) DeepCopy() *KeycloakClientStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakIdentityProvider)
+	out := new(KeycloakClientStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakList) DeepCopyInto(out *KeycloakList) {
+func (in *KeycloakClientTokenSettings) DeepCopyInto(out *KeycloakClientTokenSettings) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Keycloak, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.AccessTokenLifespan != nil {
+		in, out := &in.AccessTokenLifespan, &out.AccessTokenLifespan
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ClientSessionIdleTimeout != nil {
+		in, out := &in.ClientSessionIdleTimeout, &out.ClientSessionIdleTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ClientSessionMaxLifespan != nil {
+		in, out := &in.ClientSessionMaxLifespan, &out.ClientSessionMaxLifespan
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ClientOfflineSessionIdleTimeout != nil {
+		in, out := &in.ClientOfflineSessionIdleTimeout, &out.ClientOfflineSessionIdleTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ClientOfflineSessionMaxLifespan != nil {
+		in, out := &in.ClientOfflineSessionMaxLifespan, &out.ClientOfflineSessionMaxLifespan
+		*out = new(metav1.Duration)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakList.
-func (in *KeycloakList) DeepCopy() *KeycloakList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientTokenSettings.
+func (in *KeycloakClientTokenSettings) DeepCopy() *KeycloakClientTokenSettings {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakList)
+	out := new(KeycloakClientTokenSettings)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeycloakList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakProtocolMapper) DeepCopyInto(out *KeycloakProtocolMapper) {
+func (in *KeycloakClustering) DeepCopyInto(out *KeycloakClustering) {
 	*out = *in
-	if in.Config != nil {
-		in, out := &in.Config, &out.Config
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.CacheOwners != nil {
+		in, out := &in.CacheOwners, &out.CacheOwners
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AuthSessionsCacheOwners != nil {
+		in, out := &in.AuthSessionsCacheOwners, &out.AuthSessionsCacheOwners
+		*out = new(int32)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakProtocolMapper.
-func (in *KeycloakProtocolMapper) DeepCopy() *KeycloakProtocolMapper {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClustering.
+func (in *KeycloakClustering) DeepCopy() *KeycloakClustering {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakProtocolMapper)
+	out := new(KeycloakClustering)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakRealm) DeepCopyInto(out *KeycloakRealm) {
+func (in *KeycloakCredential) DeepCopyInto(out *KeycloakCredential) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealm.
-func (in *KeycloakRealm) DeepCopy() *KeycloakRealm {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakCredential.
+func (in *KeycloakCredential) DeepCopy() *KeycloakCredential {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakRealm)
+	out := new(KeycloakCredential)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeycloakRealm) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakRealmList) DeepCopyInto(out *KeycloakRealmList) {
+func (in *KeycloakDeploymentSpec) DeepCopyInto(out *KeycloakDeploymentSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]KeycloakRealm, len(*in))
+	in.DeploymentSpec.DeepCopyInto(&out.DeploymentSpec)
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumeMounts != nil {
+		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(KeycloakProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(KeycloakProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(KeycloakProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JVMOptions != nil {
+		in, out := &in.JVMOptions, &out.JVMOptions
+		*out = new(KeycloakJVMOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Experimental.DeepCopyInto(&out.Experimental)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmList.
-func (in *KeycloakRealmList) DeepCopy() *KeycloakRealmList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakDeploymentSpec.
+func (in *KeycloakDeploymentSpec) DeepCopy() *KeycloakDeploymentSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakRealmList)
+	out := new(KeycloakDeploymentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakExternal) DeepCopyInto(out *KeycloakExternal) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakExternal.
+func (in *KeycloakExternal) DeepCopy() *KeycloakExternal {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakExternal)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakExternalAccess) DeepCopyInto(out *KeycloakExternalAccess) {
+	*out = *in
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(KeycloakIngressConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Route != nil {
+		in, out := &in.Route, &out.Route
+		*out = new(KeycloakRouteConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakExternalAccess.
+func (in *KeycloakExternalAccess) DeepCopy() *KeycloakExternalAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakExternalAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakExternalDatabase) DeepCopyInto(out *KeycloakExternalDatabase) {
+	*out = *in
+	if in.SSLRootCertificate != nil {
+		in, out := &in.SSLRootCertificate, &out.SSLRootCertificate
+		*out = new(KeycloakExternalDatabaseCertificate)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakExternalDatabase.
+func (in *KeycloakExternalDatabase) DeepCopy() *KeycloakExternalDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakExternalDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakExternalDatabaseCertificate) DeepCopyInto(out *KeycloakExternalDatabaseCertificate) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakExternalDatabaseCertificate.
+func (in *KeycloakExternalDatabaseCertificate) DeepCopy() *KeycloakExternalDatabaseCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakExternalDatabaseCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakHostname) DeepCopyInto(out *KeycloakHostname) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakHostname.
+func (in *KeycloakHostname) DeepCopy() *KeycloakHostname {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakHostname)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakIdentityProvider) DeepCopyInto(out *KeycloakIdentityProvider) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ConfigSecrets != nil {
+		in, out := &in.ConfigSecrets, &out.ConfigSecrets
+		*out = make(map[string]v1.SecretKeySelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakIdentityProvider.
+func (in *KeycloakIdentityProvider) DeepCopy() *KeycloakIdentityProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakIdentityProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakIdentityProviderMapper) DeepCopyInto(out *KeycloakIdentityProviderMapper) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakIdentityProviderMapper.
+func (in *KeycloakIdentityProviderMapper) DeepCopy() *KeycloakIdentityProviderMapper {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakIdentityProviderMapper)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakIngressConfig) DeepCopyInto(out *KeycloakIngressConfig) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakIngressConfig.
+func (in *KeycloakIngressConfig) DeepCopy() *KeycloakIngressConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakIngressConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakJVMOptions) DeepCopyInto(out *KeycloakJVMOptions) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakJVMOptions.
+func (in *KeycloakJVMOptions) DeepCopy() *KeycloakJVMOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakJVMOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakList) DeepCopyInto(out *KeycloakList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Keycloak, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakList.
+func (in *KeycloakList) DeepCopy() *KeycloakList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeycloakRealmList) DeepCopyObject() runtime.Object {
+func (in *KeycloakList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
-	return nil
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakProbe) DeepCopyInto(out *KeycloakProbe) {
+	*out = *in
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakProbe.
+func (in *KeycloakProbe) DeepCopy() *KeycloakProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakProtocolMapper) DeepCopyInto(out *KeycloakProtocolMapper) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakProtocolMapper.
+func (in *KeycloakProtocolMapper) DeepCopy() *KeycloakProtocolMapper {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakProtocolMapper)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealm) DeepCopyInto(out *KeycloakRealm) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealm.
+func (in *KeycloakRealm) DeepCopy() *KeycloakRealm {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakRealm) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealmImport) DeepCopyInto(out *KeycloakRealmImport) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmImport.
+func (in *KeycloakRealmImport) DeepCopy() *KeycloakRealmImport {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealmImport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealmList) DeepCopyInto(out *KeycloakRealmList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeycloakRealm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmList.
+func (in *KeycloakRealmList) DeepCopy() *KeycloakRealmList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealmList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakRealmList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealmLoginSettings) DeepCopyInto(out *KeycloakRealmLoginSettings) {
+	*out = *in
+	if in.RegistrationAllowed != nil {
+		in, out := &in.RegistrationAllowed, &out.RegistrationAllowed
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RegistrationEmailAsUsername != nil {
+		in, out := &in.RegistrationEmailAsUsername, &out.RegistrationEmailAsUsername
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EditUsernameAllowed != nil {
+		in, out := &in.EditUsernameAllowed, &out.EditUsernameAllowed
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ResetPasswordAllowed != nil {
+		in, out := &in.ResetPasswordAllowed, &out.ResetPasswordAllowed
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RememberMe != nil {
+		in, out := &in.RememberMe, &out.RememberMe
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VerifyEmail != nil {
+		in, out := &in.VerifyEmail, &out.VerifyEmail
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LoginWithEmailAllowed != nil {
+		in, out := &in.LoginWithEmailAllowed, &out.LoginWithEmailAllowed
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmLoginSettings.
+func (in *KeycloakRealmLoginSettings) DeepCopy() *KeycloakRealmLoginSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealmLoginSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealmSpec) DeepCopyInto(out *KeycloakRealmSpec) {
+	*out = *in
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Realm != nil {
+		in, out := &in.Realm, &out.Realm
+		*out = new(KeycloakAPIRealm)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdminClientCredentialsRef != nil {
+		in, out := &in.AdminClientCredentialsRef, &out.AdminClientCredentialsRef
+		*out = new(KeycloakAdminClientCredentialsRef)
+		**out = **in
+	}
+	if in.RealmOverrides != nil {
+		in, out := &in.RealmOverrides, &out.RealmOverrides
+		*out = make([]*RedirectorIdentityProviderOverride, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(RedirectorIdentityProviderOverride)
+				**out = **in
+			}
+		}
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]RoleRepresentation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SMTPServerSecrets != nil {
+		in, out := &in.SMTPServerSecrets, &out.SMTPServerSecrets
+		*out = make(map[string]v1.SecretKeySelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.TokenSettings != nil {
+		in, out := &in.TokenSettings, &out.TokenSettings
+		*out = new(KeycloakRealmTokenSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LoginSettings != nil {
+		in, out := &in.LoginSettings, &out.LoginSettings
+		*out = new(KeycloakRealmLoginSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Themes != nil {
+		in, out := &in.Themes, &out.Themes
+		*out = new(KeycloakRealmThemes)
+		**out = **in
+	}
+	if in.WebAuthnPolicy != nil {
+		in, out := &in.WebAuthnPolicy, &out.WebAuthnPolicy
+		*out = new(KeycloakRealmWebAuthnPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WebAuthnPasswordlessPolicy != nil {
+		in, out := &in.WebAuthnPasswordlessPolicy, &out.WebAuthnPasswordlessPolicy
+		*out = new(KeycloakRealmWebAuthnPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = new(KeycloakAPIRealmEventsConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]KeycloakAPIGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultGroups != nil {
+		in, out := &in.DefaultGroups, &out.DefaultGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultClientScopes != nil {
+		in, out := &in.DefaultClientScopes, &out.DefaultClientScopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OptionalClientScopes != nil {
+		in, out := &in.OptionalClientScopes, &out.OptionalClientScopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RealmImport != nil {
+		in, out := &in.RealmImport, &out.RealmImport
+		*out = new(KeycloakRealmImport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UserImport != nil {
+		in, out := &in.UserImport, &out.UserImport
+		*out = new(KeycloakRealmUserImport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Localization != nil {
+		in, out := &in.Localization, &out.Localization
+		*out = make(map[string]map[string]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.RequiredActions != nil {
+		in, out := &in.RequiredActions, &out.RequiredActions
+		*out = make([]KeycloakAPIRequiredAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClientProfiles != nil {
+		in, out := &in.ClientProfiles, &out.ClientProfiles
+		*out = make([]KeycloakAPIClientProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClientPolicies != nil {
+		in, out := &in.ClientPolicies, &out.ClientPolicies
+		*out = make([]KeycloakAPIClientPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmSpec.
+func (in *KeycloakRealmSpec) DeepCopy() *KeycloakRealmSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealmSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealmStatus) DeepCopyInto(out *KeycloakRealmStatus) {
+	*out = *in
+	if in.SecondaryResources != nil {
+		in, out := &in.SecondaryResources, &out.SecondaryResources
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.UserFederationSecretHashes != nil {
+		in, out := &in.UserFederationSecretHashes, &out.UserFederationSecretHashes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UserFederationSyncResults != nil {
+		in, out := &in.UserFederationSyncResults, &out.UserFederationSyncResults
+		*out = make(map[string]KeycloakAPISynchronizationResult, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UserImportResults != nil {
+		in, out := &in.UserImportResults, &out.UserImportResults
+		*out = make([]KeycloakRealmUserImportResult, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmStatus.
+func (in *KeycloakRealmStatus) DeepCopy() *KeycloakRealmStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealmStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealmThemes) DeepCopyInto(out *KeycloakRealmThemes) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmThemes.
+func (in *KeycloakRealmThemes) DeepCopy() *KeycloakRealmThemes {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealmThemes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealmTokenSettings) DeepCopyInto(out *KeycloakRealmTokenSettings) {
+	*out = *in
+	if in.AccessTokenLifespan != nil {
+		in, out := &in.AccessTokenLifespan, &out.AccessTokenLifespan
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SSOSessionIdleTimeout != nil {
+		in, out := &in.SSOSessionIdleTimeout, &out.SSOSessionIdleTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SSOSessionMaxLifespan != nil {
+		in, out := &in.SSOSessionMaxLifespan, &out.SSOSessionMaxLifespan
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.OfflineSessionIdleTimeout != nil {
+		in, out := &in.OfflineSessionIdleTimeout, &out.OfflineSessionIdleTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RevokeRefreshToken != nil {
+		in, out := &in.RevokeRefreshToken, &out.RevokeRefreshToken
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RefreshTokenMaxReuse != nil {
+		in, out := &in.RefreshTokenMaxReuse, &out.RefreshTokenMaxReuse
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmTokenSettings.
+func (in *KeycloakRealmTokenSettings) DeepCopy() *KeycloakRealmTokenSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealmTokenSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealmUserImport) DeepCopyInto(out *KeycloakRealmUserImport) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmUserImport.
+func (in *KeycloakRealmUserImport) DeepCopy() *KeycloakRealmUserImport {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealmUserImport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealmUserImportResult) DeepCopyInto(out *KeycloakRealmUserImportResult) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmUserImportResult.
+func (in *KeycloakRealmUserImportResult) DeepCopy() *KeycloakRealmUserImportResult {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealmUserImportResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealmWebAuthnPolicy) DeepCopyInto(out *KeycloakRealmWebAuthnPolicy) {
+	*out = *in
+	if in.SignatureAlgorithms != nil {
+		in, out := &in.SignatureAlgorithms, &out.SignatureAlgorithms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CreateTimeout != nil {
+		in, out := &in.CreateTimeout, &out.CreateTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AvoidSameAuthenticatorRegister != nil {
+		in, out := &in.AvoidSameAuthenticatorRegister, &out.AvoidSameAuthenticatorRegister
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AcceptableAaguids != nil {
+		in, out := &in.AcceptableAaguids, &out.AcceptableAaguids
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmWebAuthnPolicy.
+func (in *KeycloakRealmWebAuthnPolicy) DeepCopy() *KeycloakRealmWebAuthnPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealmWebAuthnPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRemoteCache) DeepCopyInto(out *KeycloakRemoteCache) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRemoteCache.
+func (in *KeycloakRemoteCache) DeepCopy() *KeycloakRemoteCache {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRemoteCache)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakRealmSpec) DeepCopyInto(out *KeycloakRealmSpec) {
+func (in *KeycloakRestoreOptions) DeepCopyInto(out *KeycloakRestoreOptions) {
 	*out = *in
-	if in.InstanceSelector != nil {
-		in, out := &in.InstanceSelector, &out.InstanceSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Realm != nil {
-		in, out := &in.Realm, &out.Realm
-		*out = new(KeycloakAPIRealm)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.RealmOverrides != nil {
-		in, out := &in.RealmOverrides, &out.RealmOverrides
-		*out = make([]*RedirectorIdentityProviderOverride, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(RedirectorIdentityProviderOverride)
-				**out = **in
-			}
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmSpec.
-func (in *KeycloakRealmSpec) DeepCopy() *KeycloakRealmSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRestoreOptions.
+func (in *KeycloakRestoreOptions) DeepCopy() *KeycloakRestoreOptions {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakRealmSpec)
+	out := new(KeycloakRestoreOptions)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeycloakRealmStatus) DeepCopyInto(out *KeycloakRealmStatus) {
+func (in *KeycloakRouteConfig) DeepCopyInto(out *KeycloakRouteConfig) {
 	*out = *in
-	if in.SecondaryResources != nil {
-		in, out := &in.SecondaryResources, &out.SecondaryResources
-		*out = make(map[string][]string, len(*in))
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
+			(*out)[key] = val
 		}
 	}
+	if in.DestinationCACertificate != nil {
+		in, out := &in.DestinationCACertificate, &out.DestinationCACertificate
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmStatus.
-func (in *KeycloakRealmStatus) DeepCopy() *KeycloakRealmStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRouteConfig.
+func (in *KeycloakRouteConfig) DeepCopy() *KeycloakRouteConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(KeycloakRealmStatus)
+	out := new(KeycloakRouteConfig)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1248,14 +2855,69 @@ func (in *KeycloakRealmStatus) DeepCopy() *KeycloakRealmStatus {
 func (in *KeycloakSpec) DeepCopyInto(out *KeycloakSpec) {
 	*out = *in
 	out.External = in.External
+	if in.AdminClientProxy != nil {
+		in, out := &in.AdminClientProxy, &out.AdminClientProxy
+		*out = new(KeycloakAdminClientProxy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdminClientInsecureSkipVerify != nil {
+		in, out := &in.AdminClientInsecureSkipVerify, &out.AdminClientInsecureSkipVerify
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AdminClientCACertificate != nil {
+		in, out := &in.AdminClientCACertificate, &out.AdminClientCACertificate
+		*out = new(KeycloakAdminClientCACertificate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdminCredentialsRotationPeriod != nil {
+		in, out := &in.AdminCredentialsRotationPeriod, &out.AdminCredentialsRotationPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.AdminCredentialsRef != nil {
+		in, out := &in.AdminCredentialsRef, &out.AdminCredentialsRef
+		*out = new(KeycloakAdminCredentialsRef)
+		**out = **in
+	}
+	if in.AdminClientCredentialsRef != nil {
+		in, out := &in.AdminClientCredentialsRef, &out.AdminClientCredentialsRef
+		*out = new(KeycloakAdminClientCredentialsRef)
+		**out = **in
+	}
 	if in.Extensions != nil {
 		in, out := &in.Extensions, &out.Extensions
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	out.ExternalAccess = in.ExternalAccess
-	out.ExternalDatabase = in.ExternalDatabase
-	out.PodDisruptionBudget = in.PodDisruptionBudget
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(KeycloakAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Clustering != nil {
+		in, out := &in.Clustering, &out.Clustering
+		*out = new(KeycloakClustering)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteCache != nil {
+		in, out := &in.RemoteCache, &out.RemoteCache
+		*out = new(KeycloakRemoteCache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hostname != nil {
+		in, out := &in.Hostname, &out.Hostname
+		*out = new(KeycloakHostname)
+		**out = **in
+	}
+	in.ExternalAccess.DeepCopyInto(&out.ExternalAccess)
+	in.ExternalDatabase.DeepCopyInto(&out.ExternalDatabase)
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
+	if in.ServiceMonitor != nil {
+		in, out := &in.ServiceMonitor, &out.ServiceMonitor
+		*out = new(ServiceMonitorConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	in.KeycloakDeploymentSpec.DeepCopyInto(&out.KeycloakDeploymentSpec)
 	in.PostgresDeploymentSpec.DeepCopyInto(&out.PostgresDeploymentSpec)
 	out.Migration = in.Migration
@@ -1264,6 +2926,39 @@ func (in *KeycloakSpec) DeepCopyInto(out *KeycloakSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ResourceLabels != nil {
+		in, out := &in.ResourceLabels, &out.ResourceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ResourceAnnotations != nil {
+		in, out := &in.ResourceAnnotations, &out.ResourceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodLabels != nil {
+		in, out := &in.PodLabels, &out.PodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RealmNamespaces != nil {
+		in, out := &in.RealmNamespaces, &out.RealmNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1314,7 +3009,7 @@ func (in *KeycloakUser) DeepCopyInto(out *KeycloakUser) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -1336,6 +3031,48 @@ func (in *KeycloakUser) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakUserActionsEmail) DeepCopyInto(out *KeycloakUserActionsEmail) {
+	*out = *in
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Lifespan != nil {
+		in, out := &in.Lifespan, &out.Lifespan
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakUserActionsEmail.
+func (in *KeycloakUserActionsEmail) DeepCopy() *KeycloakUserActionsEmail {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakUserActionsEmail)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakUserGroup) DeepCopyInto(out *KeycloakUserGroup) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakUserGroup.
+func (in *KeycloakUserGroup) DeepCopy() *KeycloakUserGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakUserGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeycloakUserList) DeepCopyInto(out *KeycloakUserList) {
 	*out = *in
@@ -1394,6 +3131,16 @@ func (in *KeycloakUserSpec) DeepCopyInto(out *KeycloakUserSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.User.DeepCopyInto(&out.User)
+	if in.ActionsEmail != nil {
+		in, out := &in.ActionsEmail, &out.ActionsEmail
+		*out = new(KeycloakUserActionsEmail)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -1410,6 +3157,13 @@ func (in *KeycloakUserSpec) DeepCopy() *KeycloakUserSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeycloakUserStatus) DeepCopyInto(out *KeycloakUserStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -1423,6 +3177,22 @@ func (in *KeycloakUserStatus) DeepCopy() *KeycloakUserStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedRole) DeepCopyInto(out *ManagedRole) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedRole.
+func (in *ManagedRole) DeepCopy() *ManagedRole {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MigrateConfig) DeepCopyInto(out *MigrateConfig) {
 	*out = *in
@@ -1443,6 +3213,16 @@ func (in *MigrateConfig) DeepCopy() *MigrateConfig {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodDisruptionBudgetConfig) DeepCopyInto(out *PodDisruptionBudgetConfig) {
 	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 	return
 }
 
@@ -1456,6 +3236,29 @@ func (in *PodDisruptionBudgetConfig) DeepCopy() *PodDisruptionBudgetConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyRepresentation) DeepCopyInto(out *PolicyRepresentation) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRepresentation.
+func (in *PolicyRepresentation) DeepCopy() *PolicyRepresentation {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyRepresentation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresqlDeploymentSpec) DeepCopyInto(out *PostgresqlDeploymentSpec) {
 	*out = *in
@@ -1489,6 +3292,82 @@ func (in *RedirectorIdentityProviderOverride) DeepCopy() *RedirectorIdentityProv
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRepresentation) DeepCopyInto(out *ResourceRepresentation) {
+	*out = *in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]ScopeRepresentation, len(*in))
+		copy(*out, *in)
+	}
+	if in.URIs != nil {
+		in, out := &in.URIs, &out.URIs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRepresentation.
+func (in *ResourceRepresentation) DeepCopy() *ResourceRepresentation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRepresentation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceServerRepresentation) DeepCopyInto(out *ResourceServerRepresentation) {
+	*out = *in
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]PolicyRepresentation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceRepresentation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]ScopeRepresentation, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceServerRepresentation.
+func (in *ResourceServerRepresentation) DeepCopy() *ResourceServerRepresentation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceServerRepresentation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RoleRepresentation) DeepCopyInto(out *RoleRepresentation) {
 	*out = *in
@@ -1633,6 +3512,50 @@ func (in *RolesRepresentation) DeepCopy() *RolesRepresentation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScopeRepresentation) DeepCopyInto(out *ScopeRepresentation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopeRepresentation.
+func (in *ScopeRepresentation) DeepCopy() *ScopeRepresentation {
+	if in == nil {
+		return nil
+	}
+	out := new(ScopeRepresentation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMonitorConfig) DeepCopyInto(out *ServiceMonitorConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMonitorConfig.
+func (in *ServiceMonitorConfig) DeepCopy() *ServiceMonitorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMonitorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TokenResponse) DeepCopyInto(out *TokenResponse) {
 	*out = *in
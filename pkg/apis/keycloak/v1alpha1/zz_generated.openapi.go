@@ -13,22 +13,25 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.Keycloak":             schema_pkg_apis_keycloak_v1alpha1_Keycloak(ref),
-		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAWSSpec":      schema_pkg_apis_keycloak_v1alpha1_KeycloakAWSSpec(ref),
-		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakBackup":       schema_pkg_apis_keycloak_v1alpha1_KeycloakBackup(ref),
-		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakBackupSpec":   schema_pkg_apis_keycloak_v1alpha1_KeycloakBackupSpec(ref),
-		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakBackupStatus": schema_pkg_apis_keycloak_v1alpha1_KeycloakBackupStatus(ref),
-		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClient":       schema_pkg_apis_keycloak_v1alpha1_KeycloakClient(ref),
-		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientSpec":   schema_pkg_apis_keycloak_v1alpha1_KeycloakClientSpec(ref),
-		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientStatus": schema_pkg_apis_keycloak_v1alpha1_KeycloakClientStatus(ref),
-		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealm":        schema_pkg_apis_keycloak_v1alpha1_KeycloakRealm(ref),
-		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmSpec":    schema_pkg_apis_keycloak_v1alpha1_KeycloakRealmSpec(ref),
-		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmStatus":  schema_pkg_apis_keycloak_v1alpha1_KeycloakRealmStatus(ref),
-		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakSpec":         schema_pkg_apis_keycloak_v1alpha1_KeycloakSpec(ref),
-		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakStatus":       schema_pkg_apis_keycloak_v1alpha1_KeycloakStatus(ref),
-		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakUser":         schema_pkg_apis_keycloak_v1alpha1_KeycloakUser(ref),
-		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakUserSpec":     schema_pkg_apis_keycloak_v1alpha1_KeycloakUserSpec(ref),
-		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakUserStatus":   schema_pkg_apis_keycloak_v1alpha1_KeycloakUserStatus(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.Keycloak":                  schema_pkg_apis_keycloak_v1alpha1_Keycloak(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAWSSpec":           schema_pkg_apis_keycloak_v1alpha1_KeycloakAWSSpec(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakBackup":            schema_pkg_apis_keycloak_v1alpha1_KeycloakBackup(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakBackupSpec":        schema_pkg_apis_keycloak_v1alpha1_KeycloakBackupSpec(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakBackupStatus":      schema_pkg_apis_keycloak_v1alpha1_KeycloakBackupStatus(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClient":            schema_pkg_apis_keycloak_v1alpha1_KeycloakClient(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientScope":       schema_pkg_apis_keycloak_v1alpha1_KeycloakClientScope(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientScopeSpec":   schema_pkg_apis_keycloak_v1alpha1_KeycloakClientScopeSpec(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientScopeStatus": schema_pkg_apis_keycloak_v1alpha1_KeycloakClientScopeStatus(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientSpec":        schema_pkg_apis_keycloak_v1alpha1_KeycloakClientSpec(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientStatus":      schema_pkg_apis_keycloak_v1alpha1_KeycloakClientStatus(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealm":             schema_pkg_apis_keycloak_v1alpha1_KeycloakRealm(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmSpec":         schema_pkg_apis_keycloak_v1alpha1_KeycloakRealmSpec(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmStatus":       schema_pkg_apis_keycloak_v1alpha1_KeycloakRealmStatus(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakSpec":              schema_pkg_apis_keycloak_v1alpha1_KeycloakSpec(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakStatus":            schema_pkg_apis_keycloak_v1alpha1_KeycloakStatus(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakUser":              schema_pkg_apis_keycloak_v1alpha1_KeycloakUser(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakUserSpec":          schema_pkg_apis_keycloak_v1alpha1_KeycloakUserSpec(ref),
+		"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakUserStatus":        schema_pkg_apis_keycloak_v1alpha1_KeycloakUserStatus(ref),
 	}
 }
 
@@ -163,14 +166,20 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakBackupSpec(ref common.ReferenceCa
 				Properties: map[string]spec.Schema{
 					"restore": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Controls automatic restore behavior. Currently not implemented.\n\nIn the future this will be used to trigger automatic restore for a given KeycloakBackup. Each backup will correspond to a single snapshot of the database (stored either in a Persistent Volume or AWS). If a user wants to restore it, all he/she needs to do is to change this flag to true. Potentially, it will be possible to restore a single backup multiple times.",
+							Description: "Controls automatic restore behavior.\n\nEach backup corresponds to a single snapshot of the database. Setting this flag to true loads the snapshot back into the database of the Keycloak instance with a Job. Only backups on a local Persistent Volume can be restored. The restore runs once, delete the restore Job to run it again.",
 							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
+					"restoreOptions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Controls how the backup is restored.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRestoreOptions"),
+						},
+					},
 					"aws": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If provided, an automatic database backup will be created on AWS S3 instead of a local Persistent Volume. If this property is not provided - a local Persistent Volume backup will be chosen.",
+							Description: "If provided, an automatic database backup will be created on AWS S3 instead of a local Persistent Volume. If this property is not provided - a local Persistent Volume backup will be chosen. The Operator can't restore backups on AWS S3, a KeycloakBackup that sets both aws and restore fails, such backups are restored by hand.",
 							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAWSSpec"),
 						},
 					},
@@ -187,11 +196,18 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakBackupSpec(ref common.ReferenceCa
 							Format:      "",
 						},
 					},
+					"encryptionKeySecretName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If provided, the database backup written to the local Persistent Volume will be encrypted with GPG. The secret is in the same form as aws.encryptionKeySecretName, which applies to AWS S3 backups. Unencrypted backups are the default. Restoring an encrypted backup also needs the private key as GPG_PRIVATE_KEY.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAWSSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+			"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAWSSpec", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRestoreOptions", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
 	}
 }
 
@@ -223,6 +239,13 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakBackupStatus(ref common.Reference
 							Format:      "",
 						},
 					},
+					"encrypted": {
+						SchemaProps: spec.SchemaProps{
+							Description: "True if the last backup that was taken is encrypted.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"secondaryResources": {
 						SchemaProps: spec.SchemaProps{
 							Description: "A map of all the secondary resources types and names created for this CR. e.g \"Deployment\": [ \"DeploymentName1\", \"DeploymentName2\" ]",
@@ -296,6 +319,113 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakClient(ref common.ReferenceCallba
 	}
 }
 
+func schema_pkg_apis_keycloak_v1alpha1_KeycloakClientScope(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KeycloakClientScope is the Schema for the keycloakclientscopes API.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientScopeSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientScopeStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientScopeSpec", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientScopeStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_keycloak_v1alpha1_KeycloakClientScopeSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KeycloakClientScopeSpec defines the desired state of KeycloakClientScope.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"realmSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Selector for looking up KeycloakRealm Custom Resources.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"clientScope": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Keycloak Client Scope REST object. The scope is matched by name. When protocol mappers are set, mappers of the scope not listed here are removed.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIClientScope"),
+						},
+					},
+				},
+				Required: []string{"realmSelector", "clientScope"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIClientScope", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
+
+func schema_pkg_apis_keycloak_v1alpha1_KeycloakClientScopeStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KeycloakClientScopeStatus defines the observed state of KeycloakClientScope.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Current phase of the operator.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Human-readable message indicating details about current operator phase or error.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ready": {
+						SchemaProps: spec.SchemaProps{
+							Description: "True if all resources are in a ready state and all work is done.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"phase", "message", "ready"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_keycloak_v1alpha1_KeycloakClientSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -305,7 +435,7 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakClientSpec(ref common.ReferenceCa
 				Properties: map[string]spec.Schema{
 					"realmSelector": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Selector for looking up KeycloakRealm Custom Resources.",
+							Description: "Selector for looking up KeycloakRealm Custom Resources. It has to match exactly one realm, through its match labels and match expressions.",
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
 						},
 					},
@@ -325,7 +455,7 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakClientSpec(ref common.ReferenceCa
 							},
 						},
 						SchemaProps: spec.SchemaProps{
-							Description: "Client Roles",
+							Description: "Client Roles. The roles of a new client are created with a single partial import when there are many of them. Roles that already exist are then skipped rather than overwritten, they are updated one by one afterwards.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -336,12 +466,145 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakClientSpec(ref common.ReferenceCa
 							},
 						},
 					},
+					"serviceAccountRealmRoles": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Realm roles assigned to the service account of the client. When set, realm roles not listed here are removed from the service account. Requires serviceAccountsEnabled to be set on the client.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"serviceAccountClientRoles": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Client roles assigned to the service account of the client, keyed by client ID. Only the roles of the listed clients are managed. Requires serviceAccountsEnabled to be set on the client.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type: []string{"array"},
+										Items: &spec.SchemaOrArray{
+											Schema: &spec.Schema{
+												SchemaProps: spec.SchemaProps{
+													Type:   []string{"string"},
+													Format: "",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					"secretRotationPeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Period after which the client secret is regenerated in Keycloak, e.g. \"720h\". The new secret is only written to the client Secret, and takes the place of client.secret from then on. Ignored for public clients and when secretRef is set.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"registrationAccessToken": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If true, a registration access token of the client is written to the client Secret, for managing the client with the client registration API. A new one is issued along with every regenerated client secret. Ignored for public and bearer-only clients, which have no client Secret.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"secretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Key of an existing Secret in the namespace of the KeycloakClient holding the client secret. When set, it takes precedence over client.secret and the value is pushed to Keycloak whenever the two diverge.",
+							Ref:         ref("k8s.io/api/core/v1.SecretKeySelector"),
+						},
+					},
+					"scopeMappings": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Realm roles, and client roles keyed by client ID, included in the scope of the tokens issued to the client. When set, roles not listed here are removed from the scope. Ignored unless client.fullScopeAllowed is set to false, as all roles are in scope otherwise.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.RoleRepresentationComposites"),
+						},
+					},
+					"defaultRoles": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Names of the client roles added to the default roles of the realm, which every user of the realm is granted. When set, the other roles of the client are removed from the default roles.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"saml": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SAML settings of the client, set as the matching client.attributes, which they take precedence over.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientSAML"),
+						},
+					},
+					"tokenSettings": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Token lifespans of the client that differ from the ones of the realm, set as the matching client.attributes, which they take precedence over. Settings left unset here fall back to the realm, as long as tokenSettings itself is set or was set on the last reconcile.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientTokenSettings"),
+						},
+					},
+					"consent": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Consent screen settings of the client, set as the matching client.attributes, which they take precedence over. Only used when client.consentRequired is set, otherwise the consent screen texts of the client and its protocol mappers are cleared.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientConsent"),
+						},
+					},
+					"logout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OpenID Connect logout settings of the client, set as the matching client.attributes, which they take precedence over. Settings left unset here are removed from the client, as long as logout itself is set.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientLogout"),
+						},
+					},
+					"deletionGracePeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How long the deletion of the KeycloakClient waits for the client to be removed from Keycloak, e.g. while Keycloak can't be reached. The KeycloakClient is deleted anyway afterwards, leaving the client in Keycloak. Defaults to 15m.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"redirectUriValidation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Validation of client.redirectUris before they are sent to Keycloak, nothing is checked by default. With \"lenient\", each URI must be an absolute URL or a path, with a wildcard only at its end. \"strict\" additionally requires http or https URLs with a host and rejects a lone \"*\".",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resyncPeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How often the KeycloakClient is reconciled again after a successful reconcile, which corrects the changes made in Keycloak meanwhile. Defaults to the --resync-period of the operator.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"authFlowOverrides": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Authentication flows used by the client instead of the ones bound in the realm, e.g. to require a second factor for it. Keyed by binding, either \"browser\" or \"direct_grant\", with the alias or ID of a top level flow of the realm. Bindings left out are reset to the flows of the realm, as long as authFlowOverrides itself is set.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"realmSelector", "client"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIClient", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.RoleRepresentation", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+			"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIClient", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientConsent", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientLogout", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientSAML", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClientTokenSettings", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.RoleRepresentation", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.RoleRepresentationComposites", "k8s.io/api/core/v1.SecretKeySelector", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
 	}
 }
 
@@ -395,10 +658,60 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakClientStatus(ref common.Reference
 							},
 						},
 					},
+					"managedRoles": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The roles of the client as found in Keycloak after the last reconcile, along with the action taken for each of them.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.ManagedRole"),
+									},
+								},
+							},
+						},
+					},
+					"managedTokenSettings": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The client attributes tokenSettings set on the last reconcile, which are cleared once it doesn't anymore. Lifespans set in Keycloak by other means are left alone.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"conditions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"type",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions of the resource following the Kubernetes conventions, i.e. Ready, Reconciling and Error.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.Condition"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"phase", "message", "ready"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.Condition", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.ManagedRole"},
 	}
 }
 
@@ -472,30 +785,289 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakRealmSpec(ref common.ReferenceCal
 							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIRealm"),
 						},
 					},
-					"realmOverrides": {
+					"adminClientCredentialsRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Secret holding the client ID and secret of a service account client of this realm, which the Operator authenticates with to reconcile the KeycloakClients, KeycloakClientScopes and KeycloakUsers of the realm, so that they can't reach other realms. The service account needs the manage-clients, manage-users and view-realm roles of the realm-management client. The realm itself is still reconciled with the admin credentials of the Keycloak.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAdminClientCredentialsRef"),
+						},
+					},
+					"realmOverrides": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "A list of overrides to the default Realm behavior.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.RedirectorIdentityProviderOverride"),
+									},
+								},
+							},
+						},
+					},
+					"roles": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Realm Roles. When set, realm roles not listed here are removed from the realm, except for the roles keycloak creates by default.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.RoleRepresentation"),
+									},
+								},
+							},
+						},
+					},
+					"smtpServerSecrets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SMTP server settings read from secrets, keyed by the setting they provide, e.g. user, password, host, port or ssl. They take precedence over realm.smtpServer.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.SecretKeySelector"),
+									},
+								},
+							},
+						},
+					},
+					"tokenSettings": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Token and session timeouts. They take precedence over the corresponding realm settings.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmTokenSettings"),
+						},
+					},
+					"loginSettings": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Self-service settings of the login page, e.g. whether users can register. They take precedence over the corresponding realm settings.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmLoginSettings"),
+						},
+					},
+					"themes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Themes of the login pages, account console, emails and admin console of the realm, e.g. custom themes deployed as extensions. Themes Keycloak doesn't have are not set and reported in a warning event. They take precedence over the realm settings.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmThemes"),
+						},
+					},
+					"webAuthnPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WebAuthn policy of the realm, used by the WebAuthn authenticator for two-factor authentication. It takes precedence over the corresponding realm settings.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmWebAuthnPolicy"),
+						},
+					},
+					"webAuthnPasswordlessPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WebAuthn policy of the realm used by the WebAuthn passwordless authenticator, e.g. for passkeys. It takes precedence over the corresponding realm settings.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmWebAuthnPolicy"),
+						},
+					},
+					"events": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Events and admin events recording. They take precedence over the corresponding realm settings.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIRealmEventsConfig"),
+						},
+					},
+					"groups": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Groups of the realm, created top down along with their role mappings. When set, groups not listed here are removed from the realm.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIGroup"),
+									},
+								},
+							},
+						},
+					},
+					"defaultGroups": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Default groups new users join, given by their path, e.g. /parent/child. When set, groups not listed here are no longer default groups. The groups must exist.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"defaultClientScopes": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Client scopes added to new clients of the realm as default scopes, given by name. When set, client scopes not listed here are no longer added. The scopes must exist.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"optionalClientScopes": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Client scopes added to new clients of the realm as optional scopes, given by name. When set, client scopes not listed here are no longer added. The scopes must exist.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"realmImport": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Realm JSON, e.g. an export of the realm, imported into the realm with a partial import once the realm exists. It is imported again whenever its content changes.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmImport"),
+						},
+					},
+					"userImport": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Users imported in bulk into the realm once it exists, e.g. to onboard many users at once. Users that already exist are skipped. The users are imported again whenever the content changes, the outcome for each user is shown in status.userImportResults.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmUserImport"),
+						},
+					},
+					"localization": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message overrides of the login, account and email themes, keyed by locale and then by message key. When set, overrides not listed here are removed from the realm. Locales need to be supported by the realm to be shown, see realm.supportedLocales.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type: []string{"object"},
+										AdditionalProperties: &spec.SchemaOrBool{
+											Allows: true,
+											Schema: &spec.Schema{
+												SchemaProps: spec.SchemaProps{
+													Type:   []string{"string"},
+													Format: "",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					"requiredActions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"alias",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Required actions of the realm, e.g. CONFIGURE_TOTP, VERIFY_EMAIL or actions of custom providers, which are registered when missing. Required actions not listed here are left as they are.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIRequiredAction"),
+									},
+								},
+							},
+						},
+					},
+					"clientProfiles": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Client profiles of the realm, e.g. for FAPI compliance. When set, they replace all client profiles of the realm. Needs Keycloak 14 or later.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIClientProfile"),
+									},
+								},
+							},
+						},
+					},
+					"clientPolicies": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
-								"x-kubernetes-list-type": "atomic",
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
 							},
 						},
 						SchemaProps: spec.SchemaProps{
-							Description: "A list of overrides to the default Realm behavior.",
+							Description: "Client policies of the realm, applying client profiles to the clients that meet their conditions. When set, they replace all client policies of the realm. Needs Keycloak 14 or later.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.RedirectorIdentityProviderOverride"),
+										Ref: ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIClientPolicy"),
 									},
 								},
 							},
 						},
 					},
+					"resyncPeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How often the KeycloakRealm is reconciled again after a successful reconcile, which corrects the changes made in Keycloak meanwhile. Defaults to the --resync-period of the operator.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
 				},
 				Required: []string{"realm"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIRealm", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.RedirectorIdentityProviderOverride", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+			"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIClientPolicy", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIClientProfile", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIGroup", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIRealm", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIRealmEventsConfig", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIRequiredAction", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAdminClientCredentialsRef", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmImport", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmLoginSettings", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmThemes", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmTokenSettings", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmUserImport", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmWebAuthnPolicy", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.RedirectorIdentityProviderOverride", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.RoleRepresentation", "k8s.io/api/core/v1.SecretKeySelector", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
 	}
 }
 
@@ -555,10 +1127,110 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakRealmStatus(ref common.ReferenceC
 							Format: "",
 						},
 					},
+					"smtpServerHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HMAC of the SMTP server settings last pushed to keycloak, which doesn't return the stored password. It is keyed by the UID of the realm, so the same settings hash differently in every realm",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"userFederationSecretHashes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HMACs of the config values read from secrets last pushed to each user federation provider, keyed by display name. They are keyed by the UID of the realm, like smtpServerHash",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"userFederationSyncResults": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Results of the last user federation syncs triggered through the keycloak.org/sync-federation annotation, keyed by display name",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPISynchronizationResult"),
+									},
+								},
+							},
+						},
+					},
+					"realmImportHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Hash of the realm JSON last imported through realmImport",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"userImportHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Hash of the users last imported through userImport",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"userImportResults": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Outcome of the last import of the users listed in userImport",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmUserImportResult"),
+									},
+								},
+							},
+						},
+					},
+					"lastExportTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time of the last export asked for through the keycloak.org/export annotation, in RFC 3339 format",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastExportSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the Secret the last export was written to",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"conditions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"type",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions of the resource following the Kubernetes conventions, i.e. Ready, Reconciling and Error.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.Condition"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"phase", "message", "ready", "loginURL"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.Condition", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPISynchronizationResult", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRealmUserImportResult"},
 	}
 }
 
@@ -576,12 +1248,77 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakSpec(ref common.ReferenceCallback
 							Format:      "",
 						},
 					},
+					"dryRun": {
+						SchemaProps: spec.SchemaProps{
+							Description: "When set to true, the realms, clients, client scopes and users of this Keycloak are not changed. The actions that would be taken are logged and counted in the status of each resource instead.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"external": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Contains configuration for external Keycloak instances. Unmanaged needs to be set to true to use this.",
 							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakExternal"),
 						},
 					},
+					"adminClientTimeoutSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timeout in seconds for requests to the Keycloak admin API, including establishing the connection. Default is 10.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"adminClientMaxRetries": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of times a request to the Keycloak admin API is retried, with exponential backoff, when Keycloak responds with a 5xx status. Only GET, PUT and DELETE requests are retried. Default is 0.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"adminClientPageSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of entries requested at once when listing the clients, roles, groups and users of a realm from the Keycloak admin API. Default is 100.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"adminClientProxy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Proxy the requests to the Keycloak admin API go through. The HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of the Operator are used when not set.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAdminClientProxy"),
+						},
+					},
+					"adminClientInsecureSkipVerify": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Skip verifying the TLS certificates of Keycloak and of a HTTPS proxy in front of it. Default is false when adminClientCACertificate is set, true otherwise. Set it to false to verify the certificates of external instances against the system CAs.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"adminClientCACertificate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CA certificates, PEM encoded, the TLS certificates of Keycloak are verified against in addition to the system ones.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAdminClientCACertificate"),
+						},
+					},
+					"adminCredentialsRotationPeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Period after which the password of the admin user created by the Operator is regenerated, e.g. \"720h\". The new password is set in Keycloak and written to the admin credentials Secret. Annotating the Keycloak with keycloak.org/rotate-admin-credentials rotates it right away.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"adminCredentialsRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Existing Secret holding the admin credentials, e.g. one provisioned by an external secret manager. The Operator then doesn't create the credential-<name> Secret and never rotates the password.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAdminCredentialsRef"),
+						},
+					},
+					"adminClientCredentialsRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Secret holding the client ID and secret of a service account client in the master realm that the Operator authenticates with, through the client credentials grant, instead of the admin user. This lets the Operator work with only the roles it needs, see the README for them.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAdminClientCredentialsRef"),
+						},
+					},
 					"extensions": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
@@ -603,11 +1340,35 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakSpec(ref common.ReferenceCallback
 					},
 					"instances": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Number of Keycloak instances in HA mode. Default is 1.",
+							Description: "Number of Keycloak instances in HA mode. Default is 1. Ignored when autoscaling is set.",
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
 					},
+					"autoscaling": {
+						SchemaProps: spec.SchemaProps{
+							Description: "When set, a HorizontalPodAutoscaler scales the Keycloak instances instead.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAutoscaling"),
+						},
+					},
+					"clustering": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How the Keycloak instances find each other and share their caches. The instances always form a cluster, these settings only matter with more than one of them.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClustering"),
+						},
+					},
+					"remoteCache": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Keeps the sessions and other volatile data of Keycloak in a remote Infinispan instead of the embedded caches only, so they survive restarts of all instances. Only used by Keycloak, not RH-SSO.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRemoteCache"),
+						},
+					},
+					"hostname": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Public URLs Keycloak generates links and tokens for, e.g. when it sits behind a proxy or a load balancer it doesn't know the address of. Only used by Keycloak, not RH-SSO. Only supported by the WildFly distribution of Keycloak, the reconcile fails once Keycloak 20 or later is detected, which only comes as Quarkus.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakHostname"),
+						},
+					},
 					"externalAccess": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Controls external Ingress/Route settings.",
@@ -633,6 +1394,12 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakSpec(ref common.ReferenceCallback
 							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.PodDisruptionBudgetConfig"),
 						},
 					},
+					"serviceMonitor": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Specify ServiceMonitor configuration. The ServiceMonitor is only created when the Prometheus Operator is installed on the cluster.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.ServiceMonitorConfig"),
+						},
+					},
 					"keycloakDeploymentSpec": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Resources (Requests and Limits) for KeycloakDeployment.",
@@ -658,11 +1425,85 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakSpec(ref common.ReferenceCallback
 							Format:      "",
 						},
 					},
+					"resourceLabels": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Labels added to the resources the Operator creates for this Keycloak. Labels set by the Operator itself take precedence.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"resourceAnnotations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Annotations added to the resources the Operator creates for this Keycloak. Annotations set by the Operator itself take precedence.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"podLabels": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Labels added to the Keycloak and Postgresql Pods.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"podAnnotations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Annotations added to the Keycloak and Postgresql Pods.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"realmNamespaces": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespaces whose KeycloakRealms may select this Keycloak through their instanceSelector, besides the namespace of the Keycloak itself. \"*\" allows all namespaces, as does leaving it unset. Realms of other namespaces that select the Keycloak fail to reconcile. The Operator has to watch these namespaces, see WATCH_NAMESPACE.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakDeploymentSpec", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakExternal", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakExternalAccess", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakExternalDatabase", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.MigrateConfig", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.PodDisruptionBudgetConfig", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.PostgresqlDeploymentSpec"},
+			"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAdminClientCACertificate", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAdminClientCredentialsRef", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAdminClientProxy", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAdminCredentialsRef", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAutoscaling", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakClustering", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakDeploymentSpec", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakExternal", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakExternalAccess", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakExternalDatabase", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakHostname", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakRemoteCache", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.MigrateConfig", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.PodDisruptionBudgetConfig", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.PostgresqlDeploymentSpec", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.ServiceMonitorConfig", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
@@ -718,7 +1559,21 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakStatus(ref common.ReferenceCallba
 					},
 					"version": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Version of Keycloak or RHSSO running on the cluster.",
+							Description: "Version of the operator that last reconciled the Keycloak.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"keycloakVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Version of Keycloak or RHSSO as reported by its server info, read once it is ready.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"keycloakVersionImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image of the Keycloak deployment the keycloakVersion was read from. The version is read again once the image changes.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -807,12 +1662,31 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakUserSpec(ref common.ReferenceCall
 							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIUser"),
 						},
 					},
+					"attributesPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How user.attributes are applied to the user in Keycloak. With \"replace\", the default, attributes not listed are removed. With \"merge\", only the listed attributes are set, and the ones listed without values are removed, so that attributes written by user federation and others are kept.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"actionsEmail": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Email asking the user to perform actions, e.g. to verify the email address. It is sent once when the user is created, and again whenever the keycloak.org/send-actions-email annotation is added. Needs the SMTP server of the realm to be configured.",
+							Ref:         ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakUserActionsEmail"),
+						},
+					},
+					"resyncPeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How often the KeycloakUser is reconciled again after a successful reconcile, which corrects the changes made in Keycloak meanwhile. Defaults to the --resync-period of the operator.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
 				},
 				Required: []string{"user"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIUser", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+			"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakAPIUser", "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.KeycloakUserActionsEmail", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
 	}
 }
 
@@ -837,9 +1711,39 @@ func schema_pkg_apis_keycloak_v1alpha1_KeycloakUserStatus(ref common.ReferenceCa
 							Format:      "",
 						},
 					},
+					"conditions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"type",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions of the resource following the Kubernetes conventions, i.e. Ready, Reconciling and Error.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.Condition"),
+									},
+								},
+							},
+						},
+					},
+					"lastActionsEmailTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time the last actions email was sent, in RFC 3339 format",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"phase", "message"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1.Condition"},
 	}
 }
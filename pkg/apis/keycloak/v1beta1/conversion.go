@@ -0,0 +1,240 @@
+package v1beta1
+
+import (
+	"encoding/json"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// v1alpha1 is the version that is stored, the conversions all go from and to it.
+// The clientRole and containerId of its roles have no place in v1beta1, they are
+// kept in an annotation, so that converting back and forth doesn't lose them
+const RoleFieldsAnnotation = "keycloak.org/v1alpha1-role-fields"
+
+type roleFields struct {
+	ClientRole  *bool  `json:"clientRole,omitempty"`
+	ContainerID string `json:"containerId,omitempty"`
+}
+
+// ConvertTo converts this KeycloakClient to the v1alpha1 version
+func (in *KeycloakClient) ConvertTo(dst *v1alpha1.KeycloakClient) error {
+	src := in.DeepCopy()
+	fields, err := popRoleFields(&src.ObjectMeta)
+	if err != nil {
+		return err
+	}
+
+	dst.TypeMeta = metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "KeycloakClient"}
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.KeycloakClientSpec{
+		RealmSelector:             src.Spec.RealmSelector,
+		Client:                    src.Spec.Client,
+		Roles:                     rolesToV1alpha1(src.Spec.Roles, fields),
+		ServiceAccountRealmRoles:  src.Spec.ServiceAccountRealmRoles,
+		ServiceAccountClientRoles: src.Spec.ServiceAccountClientRoles,
+		SecretRotationPeriod:      src.Spec.SecretRotationPeriod,
+		RegistrationAccessToken:   src.Spec.RegistrationAccessToken,
+		SecretRef:                 src.Spec.SecretRef,
+		ScopeMappings:             src.Spec.ScopeMappings,
+		DefaultRoles:              src.Spec.DefaultRoles,
+		SAML:                      src.Spec.SAML,
+		TokenSettings:             src.Spec.TokenSettings,
+		Consent:                   src.Spec.Consent,
+		Logout:                    src.Spec.Logout,
+		DeletionGracePeriod:       src.Spec.DeletionGracePeriod,
+		RedirectURIValidation:     src.Spec.RedirectURIValidation,
+		ResyncPeriod:              src.Spec.ResyncPeriod,
+		AuthFlowOverrides:         src.Spec.AuthFlowOverrides,
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertFrom converts a v1alpha1 KeycloakClient to this version
+func (in *KeycloakClient) ConvertFrom(src *v1alpha1.KeycloakClient) error {
+	src = src.DeepCopy()
+	roles, fields := rolesFromV1alpha1(src.Spec.Roles)
+	if err := pushRoleFields(&src.ObjectMeta, fields); err != nil {
+		return err
+	}
+
+	in.TypeMeta = metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: "KeycloakClient"}
+	in.ObjectMeta = src.ObjectMeta
+	in.Spec = KeycloakClientSpec{
+		RealmSelector:             src.Spec.RealmSelector,
+		Client:                    src.Spec.Client,
+		Roles:                     roles,
+		ServiceAccountRealmRoles:  src.Spec.ServiceAccountRealmRoles,
+		ServiceAccountClientRoles: src.Spec.ServiceAccountClientRoles,
+		SecretRotationPeriod:      src.Spec.SecretRotationPeriod,
+		RegistrationAccessToken:   src.Spec.RegistrationAccessToken,
+		SecretRef:                 src.Spec.SecretRef,
+		ScopeMappings:             src.Spec.ScopeMappings,
+		DefaultRoles:              src.Spec.DefaultRoles,
+		SAML:                      src.Spec.SAML,
+		TokenSettings:             src.Spec.TokenSettings,
+		Consent:                   src.Spec.Consent,
+		Logout:                    src.Spec.Logout,
+		DeletionGracePeriod:       src.Spec.DeletionGracePeriod,
+		RedirectURIValidation:     src.Spec.RedirectURIValidation,
+		ResyncPeriod:              src.Spec.ResyncPeriod,
+		AuthFlowOverrides:         src.Spec.AuthFlowOverrides,
+	}
+	in.Status = src.Status
+	return nil
+}
+
+// ConvertTo converts this KeycloakRealm to the v1alpha1 version
+func (in *KeycloakRealm) ConvertTo(dst *v1alpha1.KeycloakRealm) error {
+	src := in.DeepCopy()
+	fields, err := popRoleFields(&src.ObjectMeta)
+	if err != nil {
+		return err
+	}
+
+	dst.TypeMeta = metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "KeycloakRealm"}
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.KeycloakRealmSpec{
+		Unmanaged:                  src.Spec.Unmanaged,
+		InstanceSelector:           src.Spec.InstanceSelector,
+		Realm:                      src.Spec.Realm,
+		RealmOverrides:             src.Spec.RealmOverrides,
+		AdminClientCredentialsRef:  src.Spec.AdminClientCredentialsRef,
+		Roles:                      rolesToV1alpha1(src.Spec.Roles, fields),
+		SMTPServerSecrets:          src.Spec.SMTPServerSecrets,
+		TokenSettings:              src.Spec.TokenSettings,
+		LoginSettings:              src.Spec.LoginSettings,
+		Themes:                     src.Spec.Themes,
+		WebAuthnPolicy:             src.Spec.WebAuthnPolicy,
+		WebAuthnPasswordlessPolicy: src.Spec.WebAuthnPasswordlessPolicy,
+		Events:                     src.Spec.Events,
+		Groups:                     src.Spec.Groups,
+		DefaultGroups:              src.Spec.DefaultGroups,
+		DefaultClientScopes:        src.Spec.DefaultClientScopes,
+		OptionalClientScopes:       src.Spec.OptionalClientScopes,
+		RealmImport:                src.Spec.RealmImport,
+		UserImport:                 src.Spec.UserImport,
+		Localization:               src.Spec.Localization,
+		RequiredActions:            src.Spec.RequiredActions,
+		ClientProfiles:             src.Spec.ClientProfiles,
+		ClientPolicies:             src.Spec.ClientPolicies,
+		ResyncPeriod:               src.Spec.ResyncPeriod,
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertFrom converts a v1alpha1 KeycloakRealm to this version
+func (in *KeycloakRealm) ConvertFrom(src *v1alpha1.KeycloakRealm) error {
+	src = src.DeepCopy()
+	roles, fields := rolesFromV1alpha1(src.Spec.Roles)
+	if err := pushRoleFields(&src.ObjectMeta, fields); err != nil {
+		return err
+	}
+
+	in.TypeMeta = metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: "KeycloakRealm"}
+	in.ObjectMeta = src.ObjectMeta
+	in.Spec = KeycloakRealmSpec{
+		Unmanaged:                  src.Spec.Unmanaged,
+		InstanceSelector:           src.Spec.InstanceSelector,
+		Realm:                      src.Spec.Realm,
+		RealmOverrides:             src.Spec.RealmOverrides,
+		AdminClientCredentialsRef:  src.Spec.AdminClientCredentialsRef,
+		Roles:                      roles,
+		SMTPServerSecrets:          src.Spec.SMTPServerSecrets,
+		TokenSettings:              src.Spec.TokenSettings,
+		LoginSettings:              src.Spec.LoginSettings,
+		Themes:                     src.Spec.Themes,
+		WebAuthnPolicy:             src.Spec.WebAuthnPolicy,
+		WebAuthnPasswordlessPolicy: src.Spec.WebAuthnPasswordlessPolicy,
+		Events:                     src.Spec.Events,
+		Groups:                     src.Spec.Groups,
+		DefaultGroups:              src.Spec.DefaultGroups,
+		DefaultClientScopes:        src.Spec.DefaultClientScopes,
+		OptionalClientScopes:       src.Spec.OptionalClientScopes,
+		RealmImport:                src.Spec.RealmImport,
+		UserImport:                 src.Spec.UserImport,
+		Localization:               src.Spec.Localization,
+		RequiredActions:            src.Spec.RequiredActions,
+		ClientProfiles:             src.Spec.ClientProfiles,
+		ClientPolicies:             src.Spec.ClientPolicies,
+		ResyncPeriod:               src.Spec.ResyncPeriod,
+	}
+	in.Status = src.Status
+	return nil
+}
+
+// Roles are unique by name, which the fields of v1alpha1 are kept by
+func rolesToV1alpha1(roles []Role, fields map[string]roleFields) []v1alpha1.RoleRepresentation {
+	if roles == nil {
+		return nil
+	}
+	converted := make([]v1alpha1.RoleRepresentation, 0, len(roles))
+	for _, role := range roles {
+		converted = append(converted, v1alpha1.RoleRepresentation{
+			ID:          role.ID,
+			Name:        role.Name,
+			Description: role.Description,
+			Composite:   role.Composite,
+			Composites:  role.Composites,
+			Attributes:  role.Attributes,
+			ClientRole:  fields[role.Name].ClientRole,
+			ContainerID: fields[role.Name].ContainerID,
+		})
+	}
+	return converted
+}
+
+func rolesFromV1alpha1(roles []v1alpha1.RoleRepresentation) ([]Role, map[string]roleFields) {
+	if roles == nil {
+		return nil, nil
+	}
+	converted := make([]Role, 0, len(roles))
+	fields := make(map[string]roleFields)
+	for _, role := range roles {
+		converted = append(converted, Role{
+			ID:          role.ID,
+			Name:        role.Name,
+			Description: role.Description,
+			Composite:   role.Composite,
+			Composites:  role.Composites,
+			Attributes:  role.Attributes,
+		})
+		if role.ClientRole != nil || role.ContainerID != "" {
+			fields[role.Name] = roleFields{ClientRole: role.ClientRole, ContainerID: role.ContainerID}
+		}
+	}
+	return converted, fields
+}
+
+func pushRoleFields(meta *metav1.ObjectMeta, fields map[string]roleFields) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	value, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = make(map[string]string)
+	}
+	meta.Annotations[RoleFieldsAnnotation] = string(value)
+	return nil
+}
+
+func popRoleFields(meta *metav1.ObjectMeta) (map[string]roleFields, error) {
+	value, ok := meta.Annotations[RoleFieldsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	fields := make(map[string]roleFields)
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return nil, err
+	}
+	delete(meta.Annotations, RoleFieldsAnnotation)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+	return fields, nil
+}
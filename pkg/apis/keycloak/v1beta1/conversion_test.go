@@ -0,0 +1,99 @@
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getAlphaClient() *v1alpha1.KeycloakClient {
+	clientRole := true
+	return &v1alpha1.KeycloakClient{
+		TypeMeta: metav1.TypeMeta{APIVersion: "keycloak.org/v1alpha1", Kind: "KeycloakClient"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Namespace:   "keycloak",
+			Annotations: map[string]string{"owner": "team"},
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "sso"}},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID:   "test",
+				WebOrigins: []string{"+"},
+			},
+			Roles: []v1alpha1.RoleRepresentation{
+				{Name: "admin", ID: "adminID", ClientRole: &clientRole, ContainerID: "clientID"},
+				{Name: "user", Description: "Users", Attributes: map[string][]string{"level": {"1"}}},
+			},
+			ServiceAccountRealmRoles: []string{"offline_access"},
+			RedirectURIValidation:    v1alpha1.RedirectURIValidationStrict,
+		},
+		Status: v1alpha1.KeycloakClientStatus{Ready: true, Message: "done"},
+	}
+}
+
+func TestConversion_Test_KeycloakClient_Round_Trip(t *testing.T) {
+	// given
+	alpha := getAlphaClient()
+
+	// when
+	beta := &KeycloakClient{}
+	assert.NoError(t, beta.ConvertFrom(alpha))
+	roundTrip := &v1alpha1.KeycloakClient{}
+	assert.NoError(t, beta.ConvertTo(roundTrip))
+
+	// then
+	assert.Equal(t, "keycloak.org/v1beta1", beta.APIVersion)
+	assert.Equal(t, []Role{
+		{Name: "admin", ID: "adminID"},
+		{Name: "user", Description: "Users", Attributes: map[string][]string{"level": {"1"}}},
+	}, beta.Spec.Roles)
+	assert.Contains(t, beta.Annotations[RoleFieldsAnnotation], `"containerId":"clientID"`)
+	assert.Equal(t, alpha.Spec.Client, beta.Spec.Client)
+	assert.Equal(t, alpha, roundTrip)
+
+	// when converting from v1beta1 first
+	beta.Annotations = nil
+	alpha = &v1alpha1.KeycloakClient{}
+	assert.NoError(t, beta.ConvertTo(alpha))
+	betaRoundTrip := &KeycloakClient{}
+	assert.NoError(t, betaRoundTrip.ConvertFrom(alpha))
+
+	// then
+	assert.Nil(t, alpha.Spec.Roles[0].ClientRole)
+	assert.Equal(t, beta, betaRoundTrip)
+}
+
+func TestConversion_Test_KeycloakRealm_Round_Trip(t *testing.T) {
+	// given
+	composite := true
+	alpha := &v1alpha1.KeycloakRealm{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "keycloak.org/v1alpha1", Kind: "KeycloakRealm"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "keycloak"},
+		Spec: v1alpha1.KeycloakRealmSpec{
+			InstanceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "sso"}},
+			Realm:            &v1alpha1.KeycloakAPIRealm{Realm: "test", Enabled: true},
+			Roles: []v1alpha1.RoleRepresentation{{
+				Name:       "manager",
+				Composite:  &composite,
+				Composites: &v1alpha1.RoleRepresentationComposites{Realm: []string{"user"}},
+			}},
+			DefaultGroups: []string{"staff"},
+			Localization:  map[string]map[string]string{"en": {"loginTitle": "Sign in"}},
+		},
+	}
+
+	// when
+	beta := &KeycloakRealm{}
+	assert.NoError(t, beta.ConvertFrom(alpha))
+	roundTrip := &v1alpha1.KeycloakRealm{}
+	assert.NoError(t, beta.ConvertTo(roundTrip))
+
+	// then
+	// no role has fields of v1alpha1 only, so nothing is annotated
+	assert.Nil(t, beta.Annotations)
+	assert.Equal(t, []string{"user"}, beta.Spec.Roles[0].Composites.Realm)
+	assert.Equal(t, alpha, roundTrip)
+}
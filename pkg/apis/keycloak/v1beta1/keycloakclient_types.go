@@ -0,0 +1,124 @@
+package v1beta1
+
+import (
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type KeycloakClientSpec struct {
+	// Selector for looking up KeycloakRealm Custom Resources. It has to match exactly one realm,
+	// through its match labels and match expressions.
+	// +kubebuilder:validation:Required
+	RealmSelector *metav1.LabelSelector `json:"realmSelector"`
+	// Keycloak Client REST object.
+	// +kubebuilder:validation:Required
+	Client *v1alpha1.KeycloakAPIClient `json:"client"`
+	// Client Roles. The roles of a new client are created with a single partial
+	// import when there are many of them. Roles that already exist are then skipped
+	// rather than overwritten, they are updated one by one afterwards.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Roles []Role `json:"roles,omitempty"`
+	// Realm roles assigned to the service account of the client. When set, realm roles
+	// not listed here are removed from the service account.
+	// Requires serviceAccountsEnabled to be set on the client.
+	// +optional
+	ServiceAccountRealmRoles []string `json:"serviceAccountRealmRoles,omitempty"`
+	// Client roles assigned to the service account of the client, keyed by client ID.
+	// Only the roles of the listed clients are managed.
+	// Requires serviceAccountsEnabled to be set on the client.
+	// +optional
+	ServiceAccountClientRoles map[string][]string `json:"serviceAccountClientRoles,omitempty"`
+	// Period after which the client secret is regenerated in Keycloak, e.g. "720h".
+	// The new secret replaces client.secret and is written to the client Secret.
+	// Ignored for public clients and when secretRef is set.
+	// +optional
+	SecretRotationPeriod *metav1.Duration `json:"secretRotationPeriod,omitempty"`
+	// If true, a registration access token of the client is written to the client
+	// Secret, for managing the client with the client registration API. A new one
+	// is issued along with every regenerated client secret.
+	// Ignored for public and bearer-only clients, which have no client Secret.
+	// +optional
+	RegistrationAccessToken bool `json:"registrationAccessToken,omitempty"`
+	// Key of an existing Secret in the namespace of the KeycloakClient holding the
+	// client secret. When set, it takes precedence over client.secret and the value is
+	// pushed to Keycloak whenever the two diverge.
+	// +optional
+	SecretRef *corev1.SecretKeySelector `json:"secretRef,omitempty"`
+	// Realm roles, and client roles keyed by client ID, included in the scope of the tokens issued
+	// to the client. When set, roles not listed here are removed from the scope. Ignored unless
+	// client.fullScopeAllowed is set to false, as all roles are in scope otherwise.
+	// +optional
+	ScopeMappings *v1alpha1.RoleRepresentationComposites `json:"scopeMappings,omitempty"`
+	// Names of the client roles added to the default roles of the realm, which every user of the
+	// realm is granted. When set, the other roles of the client are removed from the default roles.
+	// +optional
+	DefaultRoles []string `json:"defaultRoles,omitempty"`
+	// SAML settings of the client, set as the matching client.attributes, which they take
+	// precedence over.
+	// +optional
+	SAML *v1alpha1.KeycloakClientSAML `json:"saml,omitempty"`
+	// Token lifespans of the client that differ from the ones of the realm, set as the
+	// matching client.attributes, which they take precedence over. Settings left unset
+	// here fall back to the realm, as long as tokenSettings itself is set or was set on
+	// the last reconcile.
+	// +optional
+	TokenSettings *v1alpha1.KeycloakClientTokenSettings `json:"tokenSettings,omitempty"`
+	// Consent screen settings of the client, set as the matching client.attributes, which
+	// they take precedence over. Only used when client.consentRequired is set, otherwise
+	// the consent screen texts of the client and its protocol mappers are cleared.
+	// +optional
+	Consent *v1alpha1.KeycloakClientConsent `json:"consent,omitempty"`
+	// OpenID Connect logout settings of the client, set as the matching client.attributes,
+	// which they take precedence over. Settings left unset here are removed from the
+	// client, as long as logout itself is set.
+	// +optional
+	Logout *v1alpha1.KeycloakClientLogout `json:"logout,omitempty"`
+	// How long the deletion of the KeycloakClient waits for the client to be removed from Keycloak,
+	// e.g. while Keycloak can't be reached. The KeycloakClient is deleted anyway afterwards, leaving
+	// the client in Keycloak. Defaults to 15m.
+	// +optional
+	DeletionGracePeriod *metav1.Duration `json:"deletionGracePeriod,omitempty"`
+	// Validation of client.redirectUris before they are sent to Keycloak, nothing is checked by default.
+	// With "lenient", each URI must be an absolute URL or a path, with a wildcard only at its end.
+	// "strict" additionally requires http or https URLs with a host and rejects a lone "*".
+	// +optional
+	// +kubebuilder:validation:Enum=lenient;strict
+	RedirectURIValidation string `json:"redirectUriValidation,omitempty"`
+	// How often the KeycloakClient is reconciled again after a successful reconcile, which corrects the
+	// changes made in Keycloak meanwhile. Defaults to the --resync-period of the operator.
+	// +optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+	// Authentication flows used by the client instead of the ones bound in the realm, e.g.
+	// to require a second factor for it. Keyed by binding, either "browser" or "direct_grant",
+	// with the alias or ID of a top level flow of the realm. Bindings left out are reset to
+	// the flows of the realm, as long as authFlowOverrides itself is set.
+	// +optional
+	AuthFlowOverrides map[string]string `json:"authFlowOverrides,omitempty"`
+}
+
+// KeycloakClient is the Schema for the keycloakclients API.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KeycloakClient struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KeycloakClientSpec            `json:"spec,omitempty"`
+	Status v1alpha1.KeycloakClientStatus `json:"status,omitempty"`
+}
+
+// KeycloakClientList contains a list of KeycloakClient.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KeycloakClientList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeycloakClient `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeycloakClient{}, &KeycloakClientList{})
+}
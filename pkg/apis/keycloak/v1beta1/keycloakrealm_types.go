@@ -0,0 +1,176 @@
+package v1beta1
+
+import (
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KeycloakRealmSpec defines the desired state of KeycloakRealm.
+// +k8s:openapi-gen=true
+type KeycloakRealmSpec struct {
+	// When set to true, this KeycloakRealm will be marked as unmanaged and not be managed by this operator.
+	// It can then be used for targeting purposes.
+	// +optional
+	Unmanaged bool `json:"unmanaged,omitempty"`
+	// Selector for looking up Keycloak Custom Resources.
+	// +kubebuilder:validation:Required
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+	// Keycloak Realm REST object.
+	// +kubebuilder:validation:Required
+	Realm *v1alpha1.KeycloakAPIRealm `json:"realm"`
+	// Secret holding the client ID and secret of a service account client of this realm,
+	// which the Operator authenticates with to reconcile the KeycloakClients,
+	// KeycloakClientScopes and KeycloakUsers of the realm, so that they can't reach other
+	// realms. The service account needs the manage-clients, manage-users and view-realm
+	// roles of the realm-management client. The realm itself is still reconciled with the
+	// admin credentials of the Keycloak.
+	// +optional
+	AdminClientCredentialsRef *v1alpha1.KeycloakAdminClientCredentialsRef `json:"adminClientCredentialsRef,omitempty"`
+	// A list of overrides to the default Realm behavior.
+	// +listType=atomic
+	RealmOverrides []*v1alpha1.RedirectorIdentityProviderOverride `json:"realmOverrides,omitempty"`
+	// Realm Roles. When set, realm roles not listed here are removed from the realm,
+	// except for the roles keycloak creates by default.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Roles []Role `json:"roles,omitempty"`
+	// SMTP server settings read from secrets, keyed by the setting they provide, e.g.
+	// user, password, host, port or ssl. They take precedence over realm.smtpServer.
+	// +optional
+	SMTPServerSecrets map[string]corev1.SecretKeySelector `json:"smtpServerSecrets,omitempty"`
+	// Token and session timeouts. They take precedence over the corresponding realm settings.
+	// +optional
+	TokenSettings *v1alpha1.KeycloakRealmTokenSettings `json:"tokenSettings,omitempty"`
+	// Self-service settings of the login page, e.g. whether users can register. They take
+	// precedence over the corresponding realm settings.
+	// +optional
+	LoginSettings *v1alpha1.KeycloakRealmLoginSettings `json:"loginSettings,omitempty"`
+	// Themes of the login pages, account console, emails and admin console of the realm,
+	// e.g. custom themes deployed as extensions. Themes Keycloak doesn't have are not
+	// set and reported in a warning event. They take precedence over the realm settings.
+	// +optional
+	Themes *v1alpha1.KeycloakRealmThemes `json:"themes,omitempty"`
+	// WebAuthn policy of the realm, used by the WebAuthn authenticator for two-factor
+	// authentication. It takes precedence over the corresponding realm settings.
+	// +optional
+	WebAuthnPolicy *v1alpha1.KeycloakRealmWebAuthnPolicy `json:"webAuthnPolicy,omitempty"`
+	// WebAuthn policy of the realm used by the WebAuthn passwordless authenticator, e.g.
+	// for passkeys. It takes precedence over the corresponding realm settings.
+	// +optional
+	WebAuthnPasswordlessPolicy *v1alpha1.KeycloakRealmWebAuthnPolicy `json:"webAuthnPasswordlessPolicy,omitempty"`
+	// Events and admin events recording. They take precedence over the corresponding realm settings.
+	// +optional
+	Events *v1alpha1.KeycloakAPIRealmEventsConfig `json:"events,omitempty"`
+	// Groups of the realm, created top down along with their role mappings. When set,
+	// groups not listed here are removed from the realm.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Groups []v1alpha1.KeycloakAPIGroup `json:"groups,omitempty"`
+	// Default groups new users join, given by their path, e.g. /parent/child. When set,
+	// groups not listed here are no longer default groups. The groups must exist.
+	// +optional
+	// +listType=set
+	DefaultGroups []string `json:"defaultGroups,omitempty"`
+	// Client scopes added to new clients of the realm as default scopes, given by name.
+	// When set, client scopes not listed here are no longer added. The scopes must exist.
+	// +optional
+	// +listType=set
+	DefaultClientScopes []string `json:"defaultClientScopes,omitempty"`
+	// Client scopes added to new clients of the realm as optional scopes, given by name.
+	// When set, client scopes not listed here are no longer added. The scopes must exist.
+	// +optional
+	// +listType=set
+	OptionalClientScopes []string `json:"optionalClientScopes,omitempty"`
+	// Realm JSON, e.g. an export of the realm, imported into the realm with a partial
+	// import once the realm exists. It is imported again whenever its content changes.
+	// +optional
+	RealmImport *v1alpha1.KeycloakRealmImport `json:"realmImport,omitempty"`
+	// Users imported in bulk into the realm once it exists, e.g. to onboard many users at
+	// once. Users that already exist are skipped. The users are imported again whenever
+	// the content changes, the outcome for each user is shown in status.userImportResults.
+	// +optional
+	UserImport *v1alpha1.KeycloakRealmUserImport `json:"userImport,omitempty"`
+	// Message overrides of the login, account and email themes, keyed by locale and then
+	// by message key. When set, overrides not listed here are removed from the realm.
+	// Locales need to be supported by the realm to be shown, see realm.supportedLocales.
+	// +optional
+	Localization map[string]map[string]string `json:"localization,omitempty"`
+	// Required actions of the realm, e.g. CONFIGURE_TOTP, VERIFY_EMAIL or actions of
+	// custom providers, which are registered when missing. Required actions not listed
+	// here are left as they are.
+	// +optional
+	// +listType=map
+	// +listMapKey=alias
+	RequiredActions []v1alpha1.KeycloakAPIRequiredAction `json:"requiredActions,omitempty"`
+	// Client profiles of the realm, e.g. for FAPI compliance. When set, they replace all
+	// client profiles of the realm. Needs Keycloak 14 or later.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ClientProfiles []v1alpha1.KeycloakAPIClientProfile `json:"clientProfiles,omitempty"`
+	// Client policies of the realm, applying client profiles to the clients that meet
+	// their conditions. When set, they replace all client policies of the realm. Needs
+	// Keycloak 14 or later.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ClientPolicies []v1alpha1.KeycloakAPIClientPolicy `json:"clientPolicies,omitempty"`
+	// How often the KeycloakRealm is reconciled again after a successful reconcile, which corrects the
+	// changes made in Keycloak meanwhile. Defaults to the --resync-period of the operator.
+	// +optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+}
+
+// Role of a realm or a client. Unlike the representation of keycloak it leaves out
+// clientRole and containerId, which keycloak derives from where the role is defined.
+type Role struct {
+	// Id
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Name
+	Name string `json:"name"`
+
+	// Description
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Composite
+	// +optional
+	Composite *bool `json:"composite,omitempty"`
+
+	// Composites
+	// +optional
+	Composites *v1alpha1.RoleRepresentationComposites `json:"composites,omitempty"`
+
+	// Role Attributes
+	// +optional
+	Attributes map[string][]string `json:"attributes,omitempty"`
+}
+
+// KeycloakRealm is the Schema for the keycloakrealms API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KeycloakRealm struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KeycloakRealmSpec            `json:"spec,omitempty"`
+	Status v1alpha1.KeycloakRealmStatus `json:"status,omitempty"`
+}
+
+// KeycloakRealmList contains a list of KeycloakRealm
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KeycloakRealmList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeycloakRealm `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeycloakRealm{}, &KeycloakRealmList{})
+}
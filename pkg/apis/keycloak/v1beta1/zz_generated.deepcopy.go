@@ -0,0 +1,446 @@
+// +build !ignore_autogenerated
+
+// Code generated by operator-sdk. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1alpha1 "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakClient) DeepCopyInto(out *KeycloakClient) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClient.
+func (in *KeycloakClient) DeepCopy() *KeycloakClient {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakClient)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakClient) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakClientList) DeepCopyInto(out *KeycloakClientList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeycloakClient, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientList.
+func (in *KeycloakClientList) DeepCopy() *KeycloakClientList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakClientList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakClientList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakClientSpec) DeepCopyInto(out *KeycloakClientSpec) {
+	*out = *in
+	if in.RealmSelector != nil {
+		in, out := &in.RealmSelector, &out.RealmSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Client != nil {
+		in, out := &in.Client, &out.Client
+		*out = new(v1alpha1.KeycloakAPIClient)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]Role, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServiceAccountRealmRoles != nil {
+		in, out := &in.ServiceAccountRealmRoles, &out.ServiceAccountRealmRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceAccountClientRoles != nil {
+		in, out := &in.ServiceAccountClientRoles, &out.ServiceAccountClientRoles
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.SecretRotationPeriod != nil {
+		in, out := &in.SecretRotationPeriod, &out.SecretRotationPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScopeMappings != nil {
+		in, out := &in.ScopeMappings, &out.ScopeMappings
+		*out = new(v1alpha1.RoleRepresentationComposites)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultRoles != nil {
+		in, out := &in.DefaultRoles, &out.DefaultRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SAML != nil {
+		in, out := &in.SAML, &out.SAML
+		*out = new(v1alpha1.KeycloakClientSAML)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TokenSettings != nil {
+		in, out := &in.TokenSettings, &out.TokenSettings
+		*out = new(v1alpha1.KeycloakClientTokenSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Consent != nil {
+		in, out := &in.Consent, &out.Consent
+		*out = new(v1alpha1.KeycloakClientConsent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logout != nil {
+		in, out := &in.Logout, &out.Logout
+		*out = new(v1alpha1.KeycloakClientLogout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionGracePeriod != nil {
+		in, out := &in.DeletionGracePeriod, &out.DeletionGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AuthFlowOverrides != nil {
+		in, out := &in.AuthFlowOverrides, &out.AuthFlowOverrides
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakClientSpec.
+func (in *KeycloakClientSpec) DeepCopy() *KeycloakClientSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakClientSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealm) DeepCopyInto(out *KeycloakRealm) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealm.
+func (in *KeycloakRealm) DeepCopy() *KeycloakRealm {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakRealm) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealmList) DeepCopyInto(out *KeycloakRealmList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeycloakRealm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmList.
+func (in *KeycloakRealmList) DeepCopy() *KeycloakRealmList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealmList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeycloakRealmList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealmSpec) DeepCopyInto(out *KeycloakRealmSpec) {
+	*out = *in
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Realm != nil {
+		in, out := &in.Realm, &out.Realm
+		*out = new(v1alpha1.KeycloakAPIRealm)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdminClientCredentialsRef != nil {
+		in, out := &in.AdminClientCredentialsRef, &out.AdminClientCredentialsRef
+		*out = new(v1alpha1.KeycloakAdminClientCredentialsRef)
+		**out = **in
+	}
+	if in.RealmOverrides != nil {
+		in, out := &in.RealmOverrides, &out.RealmOverrides
+		*out = make([]*v1alpha1.RedirectorIdentityProviderOverride, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(v1alpha1.RedirectorIdentityProviderOverride)
+				**out = **in
+			}
+		}
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]Role, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SMTPServerSecrets != nil {
+		in, out := &in.SMTPServerSecrets, &out.SMTPServerSecrets
+		*out = make(map[string]corev1.SecretKeySelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.TokenSettings != nil {
+		in, out := &in.TokenSettings, &out.TokenSettings
+		*out = new(v1alpha1.KeycloakRealmTokenSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LoginSettings != nil {
+		in, out := &in.LoginSettings, &out.LoginSettings
+		*out = new(v1alpha1.KeycloakRealmLoginSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Themes != nil {
+		in, out := &in.Themes, &out.Themes
+		*out = new(v1alpha1.KeycloakRealmThemes)
+		**out = **in
+	}
+	if in.WebAuthnPolicy != nil {
+		in, out := &in.WebAuthnPolicy, &out.WebAuthnPolicy
+		*out = new(v1alpha1.KeycloakRealmWebAuthnPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WebAuthnPasswordlessPolicy != nil {
+		in, out := &in.WebAuthnPasswordlessPolicy, &out.WebAuthnPasswordlessPolicy
+		*out = new(v1alpha1.KeycloakRealmWebAuthnPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = new(v1alpha1.KeycloakAPIRealmEventsConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]v1alpha1.KeycloakAPIGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultGroups != nil {
+		in, out := &in.DefaultGroups, &out.DefaultGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultClientScopes != nil {
+		in, out := &in.DefaultClientScopes, &out.DefaultClientScopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OptionalClientScopes != nil {
+		in, out := &in.OptionalClientScopes, &out.OptionalClientScopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RealmImport != nil {
+		in, out := &in.RealmImport, &out.RealmImport
+		*out = new(v1alpha1.KeycloakRealmImport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UserImport != nil {
+		in, out := &in.UserImport, &out.UserImport
+		*out = new(v1alpha1.KeycloakRealmUserImport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Localization != nil {
+		in, out := &in.Localization, &out.Localization
+		*out = make(map[string]map[string]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.RequiredActions != nil {
+		in, out := &in.RequiredActions, &out.RequiredActions
+		*out = make([]v1alpha1.KeycloakAPIRequiredAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClientProfiles != nil {
+		in, out := &in.ClientProfiles, &out.ClientProfiles
+		*out = make([]v1alpha1.KeycloakAPIClientProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClientPolicies != nil {
+		in, out := &in.ClientPolicies, &out.ClientPolicies
+		*out = make([]v1alpha1.KeycloakAPIClientPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealmSpec.
+func (in *KeycloakRealmSpec) DeepCopy() *KeycloakRealmSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealmSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Role) DeepCopyInto(out *Role) {
+	*out = *in
+	if in.Composite != nil {
+		in, out := &in.Composite, &out.Composite
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Composites != nil {
+		in, out := &in.Composites, &out.Composites
+		*out = new(v1alpha1.RoleRepresentationComposites)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Role.
+func (in *Role) DeepCopy() *Role {
+	if in == nil {
+		return nil
+	}
+	out := new(Role)
+	in.DeepCopyInto(out)
+	return out
+}
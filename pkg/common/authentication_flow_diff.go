@@ -0,0 +1,128 @@
+package common
+
+import (
+	"reflect"
+	"sort"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+)
+
+// Keycloak adds executions as disabled unless told otherwise
+const defaultAuthenticationRequirement = "DISABLED"
+
+// An execution of a flow tree, flattened the way keycloak lists them
+type flatAuthenticationExecution struct {
+	Level       int32
+	Requirement string
+	Provider    string
+	SubFlow     string
+	Config      string
+	ConfigData  map[string]string
+}
+
+func SortedAuthenticationExecutions(executions []kc.KeycloakAPIAuthenticationExecution) []kc.KeycloakAPIAuthenticationExecution {
+	sorted := append([]kc.KeycloakAPIAuthenticationExecution(nil), executions...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority < sorted[j].Priority
+	})
+	return sorted
+}
+
+func AuthenticationExecutionRequirement(execution kc.KeycloakAPIAuthenticationExecution) string {
+	if execution.Requirement == "" {
+		return defaultAuthenticationRequirement
+	}
+	return execution.Requirement
+}
+
+func FindAuthenticationFlow(flows []kc.KeycloakAPIAuthenticationFlow, alias string) *kc.KeycloakAPIAuthenticationFlow {
+	for i := range flows {
+		if flows[i].Alias == alias {
+			return &flows[i]
+		}
+	}
+	return nil
+}
+
+func FindAuthenticatorConfig(configs []kc.KeycloakAPIAuthenticatorConfig, alias string) *kc.KeycloakAPIAuthenticatorConfig {
+	for i := range configs {
+		if configs[i].Alias == alias {
+			return &configs[i]
+		}
+	}
+	return nil
+}
+
+// Compare a desired flow of the realm with the executions and configs keycloak has
+// for it. Sub flows of the desired flow are looked up in the same realm
+func AuthenticationFlowMatches(flow *kc.KeycloakAPIAuthenticationFlow, realm *kc.KeycloakAPIRealm, executions []*kc.AuthenticationExecutionInfo, configs map[string]*kc.AuthenticatorConfig) bool {
+	desired := flattenDesiredExecutions(flow, realm, 0, map[string]bool{})
+
+	var existing []flatAuthenticationExecution
+	for _, info := range executions {
+		execution := flatAuthenticationExecution{
+			Level:       info.Level,
+			Requirement: info.Requirement,
+		}
+		if info.AuthenticationFlow {
+			execution.SubFlow = info.DisplayName
+		} else {
+			execution.Provider = info.ProviderID
+		}
+		if config, ok := configs[info.AuthenticationConfig]; ok {
+			execution.Config = config.Alias
+			execution.ConfigData = config.Config
+		}
+		existing = append(existing, execution)
+	}
+
+	if len(desired) != len(existing) {
+		return false
+	}
+	for i := range desired {
+		if !flatExecutionMatches(desired[i], existing[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func flattenDesiredExecutions(flow *kc.KeycloakAPIAuthenticationFlow, realm *kc.KeycloakAPIRealm, level int32, visited map[string]bool) []flatAuthenticationExecution {
+	// Guard against sub flows referencing each other, such a tree can't be built anyway
+	if visited[flow.Alias] {
+		return nil
+	}
+	visited[flow.Alias] = true
+
+	var result []flatAuthenticationExecution
+	for _, execution := range SortedAuthenticationExecutions(flow.AuthenticationExecutions) {
+		flat := flatAuthenticationExecution{
+			Level:       level,
+			Requirement: AuthenticationExecutionRequirement(execution),
+		}
+		if execution.AuthenticatorFlow {
+			flat.SubFlow = execution.FlowAlias
+		} else {
+			flat.Provider = execution.Authenticator
+		}
+		if config := FindAuthenticatorConfig(realm.AuthenticatorConfig, execution.AuthenticatorConfig); config != nil {
+			flat.Config = config.Alias
+			flat.ConfigData = config.Config
+		}
+		result = append(result, flat)
+
+		if execution.AuthenticatorFlow {
+			if subFlow := FindAuthenticationFlow(realm.AuthenticationFlows, execution.FlowAlias); subFlow != nil {
+				result = append(result, flattenDesiredExecutions(subFlow, realm, level+1, visited)...)
+			}
+		}
+	}
+	return result
+}
+
+func flatExecutionMatches(desired, existing flatAuthenticationExecution) bool {
+	if len(desired.ConfigData) == 0 && len(existing.ConfigData) == 0 {
+		desired.ConfigData, existing.ConfigData = nil, nil
+	}
+	return reflect.DeepEqual(desired, existing)
+}
@@ -0,0 +1,61 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticationFlowDiff_Test_Flow_Matches(t *testing.T) {
+	// given
+	realm := &v1alpha1.KeycloakAPIRealm{
+		AuthenticationFlows: []v1alpha1.KeycloakAPIAuthenticationFlow{
+			{
+				Alias:    "flow",
+				TopLevel: true,
+				AuthenticationExecutions: []v1alpha1.KeycloakAPIAuthenticationExecution{
+					{AuthenticatorFlow: true, FlowAlias: "forms", Requirement: "ALTERNATIVE", Priority: 20},
+					{Authenticator: "identity-provider-redirector", AuthenticatorConfig: "redirector", Priority: 10},
+				},
+			},
+			{
+				Alias: "forms",
+				AuthenticationExecutions: []v1alpha1.KeycloakAPIAuthenticationExecution{
+					{Authenticator: "auth-username-password-form", Requirement: "REQUIRED"},
+				},
+			},
+		},
+		AuthenticatorConfig: []v1alpha1.KeycloakAPIAuthenticatorConfig{
+			{Alias: "redirector", Config: map[string]string{"defaultProvider": "github"}},
+		},
+	}
+	executions := []*v1alpha1.AuthenticationExecutionInfo{
+		{ProviderID: "identity-provider-redirector", Requirement: "DISABLED", AuthenticationConfig: "configID"},
+		{AuthenticationFlow: true, DisplayName: "forms", Requirement: "ALTERNATIVE"},
+		{ProviderID: "auth-username-password-form", Requirement: "REQUIRED", Level: 1},
+	}
+	configs := map[string]*v1alpha1.AuthenticatorConfig{
+		"configID": {ID: "configID", Alias: "redirector", Config: map[string]string{"defaultProvider": "github"}},
+	}
+
+	// when
+	matches := AuthenticationFlowMatches(&realm.AuthenticationFlows[0], realm, executions, configs)
+
+	// then
+	assert.True(t, matches)
+
+	// when the config differs
+	configs["configID"].Config["defaultProvider"] = "google"
+	matches = AuthenticationFlowMatches(&realm.AuthenticationFlows[0], realm, executions, configs)
+
+	// then
+	assert.False(t, matches)
+
+	// when an execution is missing
+	configs["configID"].Config["defaultProvider"] = "github"
+	matches = AuthenticationFlowMatches(&realm.AuthenticationFlows[0], realm, executions[:2], configs)
+
+	// then
+	assert.False(t, matches)
+}
@@ -5,6 +5,7 @@ import (
 
 	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	"github.com/keycloak/keycloak-operator/pkg/model"
+	v13 "k8s.io/api/apps/v1"
 	v12 "k8s.io/api/batch/v1"
 	"k8s.io/api/batch/v1beta1"
 	v1 "k8s.io/api/core/v1"
@@ -17,6 +18,8 @@ type BackupState struct {
 	LocalPersistentVolumeClaim *v1.PersistentVolumeClaim
 	AwsJob                     *v12.Job
 	AwsPeriodicJob             *v1beta1.CronJob
+	RestoreJob                 *v12.Job
+	KeycloakDeployment         *v13.StatefulSet
 	Keycloak                   *kc.Keycloak
 }
 
@@ -51,6 +54,16 @@ func (i *BackupState) Read(context context.Context, cr *kc.KeycloakBackup, contr
 		return err
 	}
 
+	err = i.readRestoreJob(context, cr, controllerClient)
+	if err != nil {
+		return err
+	}
+
+	err = i.readKeycloakDeployment(context, cr, controllerClient)
+	if err != nil {
+		return err
+	}
+
 	return err
 }
 
@@ -127,6 +140,49 @@ func (i *BackupState) readAwsPeriodicBackupJob(context context.Context, cr *kc.K
 	return nil
 }
 
+func (i *BackupState) readRestoreJob(context context.Context, cr *kc.KeycloakBackup, controllerClient client.Client) error {
+	if !cr.Spec.Restore {
+		return nil
+	}
+
+	restoreJob := model.PostgresqlRestore(cr)
+	restoreJobSelector := model.PostgresqlRestoreSelector(cr)
+
+	err := controllerClient.Get(context, restoreJobSelector, restoreJob)
+	if err != nil {
+		if !apiErrors.IsNotFound(err) {
+			return err
+		}
+	} else {
+		i.RestoreJob = restoreJob
+		cr.UpdateStatusSecondaryResources(i.RestoreJob.Kind, i.RestoreJob.Name)
+	}
+	return nil
+}
+
+// The Keycloak StatefulSet is owned by the Keycloak CR, it is only read to tell
+// when Keycloak is down for the restore
+func (i *BackupState) readKeycloakDeployment(context context.Context, cr *kc.KeycloakBackup, controllerClient client.Client) error {
+	if !model.IsRestoreScalingDownKeycloak(cr) {
+		return nil
+	}
+
+	keycloakDeployment := &v13.StatefulSet{}
+	err := controllerClient.Get(context, model.KeycloakDeploymentSelector(i.Keycloak), keycloakDeployment)
+	if err != nil {
+		if !apiErrors.IsNotFound(err) {
+			return err
+		}
+	} else {
+		i.KeycloakDeployment = keycloakDeployment
+	}
+	return nil
+}
+
+func (i *BackupState) IsKeycloakScaledDown() bool {
+	return i.KeycloakDeployment == nil || i.KeycloakDeployment.Status.Replicas == 0
+}
+
 func (i *BackupState) IsResourcesReady() (bool, error) {
 	switch {
 	case i.AwsJob != nil:
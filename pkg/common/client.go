@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -22,7 +24,12 @@ import (
 )
 
 const (
-	authURL = "auth/realms/master/protocol/openid-connect/token"
+	defaultAdminClientTimeout = 10 * time.Second
+	adminClientRetryBackoff   = 500 * time.Millisecond
+	// Same as the default maximum keycloak applies to most lists
+	defaultAdminClientPageSize = 100
+	// Stops paging through a list that never ends, e.g. from a server ignoring first
+	maxAdminClientPages = 10000
 )
 
 type Requester interface {
@@ -30,9 +37,20 @@ type Requester interface {
 }
 
 type Client struct {
-	requester Requester
-	URL       string
-	token     string
+	requester      Requester
+	URL            string
+	token          string
+	tokenExpiresAt time.Time
+	pageSize       int
+	// Logs in as a service account client instead of the admin user
+	clientCredentials bool
+	// Realm the client logs in to, master when not set
+	tokenRealm string
+	// Keycloak serves at the root of the URL rather than under /auth, as the Quarkus
+	// distribution does by default
+	rootPath bool
+	// Server info read by the first request for it, keycloak only changes it on restarts
+	serverInfo *KeycloakServerInfo
 }
 
 // T is a generic type for keycloak spec resources
@@ -48,7 +66,7 @@ func (c *Client) create(obj T, resourcePath, resourceName string) (string, error
 
 	req, err := http.NewRequest(
 		"POST",
-		fmt.Sprintf("%s/auth/admin/%s", c.URL, resourcePath),
+		fmt.Sprintf("%s/admin/%s", c.baseURL(), resourcePath),
 		bytes.NewBuffer(jsonValue),
 	)
 	if err != nil {
@@ -84,6 +102,13 @@ func (c *Client) Endpoint() string {
 	return c.URL
 }
 
+func (c *Client) baseURL() string {
+	if c.rootPath {
+		return c.URL
+	}
+	return c.URL + "/auth"
+}
+
 func (c *Client) CreateRealm(realm *v1alpha1.KeycloakRealm) (string, error) {
 	return c.create(realm.Spec.Realm, "realms", "realm")
 }
@@ -96,6 +121,67 @@ func (c *Client) CreateClientRole(clientID string, role *v1alpha1.RoleRepresenta
 	return c.create(role, fmt.Sprintf("realms/%s/clients/%s/roles", realmName, clientID), "client role")
 }
 
+func (c *Client) CreateClientRoleComposites(clientID, roleName string, composites []v1alpha1.RoleRepresentation, realmName string) error {
+	_, err := c.create(composites, fmt.Sprintf("realms/%s/clients/%s/roles/%s/composites", realmName, clientID, roleName), "client role composites")
+	return err
+}
+
+func (c *Client) CreateRealmRole(role *v1alpha1.RoleRepresentation, realmName string) (string, error) {
+	return c.create(role, fmt.Sprintf("realms/%s/roles", realmName), "realm role")
+}
+
+func (c *Client) CreateGroup(group *v1alpha1.KeycloakAPIGroup, realmName string) (string, error) {
+	return c.create(group, fmt.Sprintf("realms/%s/groups", realmName), "group")
+}
+
+// Keycloak moves the group to the parent instead when posting an existing one
+func (c *Client) CreateChildGroup(group *v1alpha1.KeycloakAPIGroup, parentID, realmName string) (string, error) {
+	return c.create(group, fmt.Sprintf("realms/%s/groups/%s/children", realmName, parentID), "child group")
+}
+
+func (c *Client) CreateGroupRealmRoleMappings(groupID string, roles []v1alpha1.RoleRepresentation, realmName string) error {
+	_, err := c.create(roles, fmt.Sprintf("realms/%s/groups/%s/role-mappings/realm", realmName, groupID), "group realm role mappings")
+	return err
+}
+
+func (c *Client) CreateGroupClientRoleMappings(groupID, clientID string, roles []v1alpha1.RoleRepresentation, realmName string) error {
+	_, err := c.create(roles, fmt.Sprintf("realms/%s/groups/%s/role-mappings/clients/%s", realmName, groupID, clientID), "group client role mappings")
+	return err
+}
+
+func (c *Client) CreateClientRealmScopeMappings(clientID string, roles []v1alpha1.RoleRepresentation, realmName string) error {
+	_, err := c.create(roles, fmt.Sprintf("realms/%s/clients/%s/scope-mappings/realm", realmName, clientID), "client realm scope mappings")
+	return err
+}
+
+func (c *Client) CreateClientClientScopeMappings(clientID, roleClientID string, roles []v1alpha1.RoleRepresentation, realmName string) error {
+	_, err := c.create(roles, fmt.Sprintf("realms/%s/clients/%s/scope-mappings/clients/%s", realmName, clientID, roleClientID), "client client scope mappings")
+	return err
+}
+
+func (c *Client) CreateRealmRoleComposites(roleName string, composites []v1alpha1.RoleRepresentation, realmName string) error {
+	_, err := c.create(composites, fmt.Sprintf("realms/%s/roles/%s/composites", realmName, roleName), "realm role composites")
+	return err
+}
+
+func (c *Client) CreateClientProtocolMapper(clientID string, mapper *v1alpha1.KeycloakProtocolMapper, realmName string) (string, error) {
+	return c.create(mapper, fmt.Sprintf("realms/%s/clients/%s/protocol-mappers/models", realmName, clientID), "client protocol mapper")
+}
+
+func (c *Client) CreateClientScope(scope *v1alpha1.KeycloakAPIClientScope, realmName string) (string, error) {
+	return c.create(scope, fmt.Sprintf("realms/%s/client-scopes", realmName), "client scope")
+}
+
+func (c *Client) CreateClientScopeProtocolMapper(scopeID string, mapper *v1alpha1.KeycloakProtocolMapper, realmName string) (string, error) {
+	return c.create(mapper, fmt.Sprintf("realms/%s/client-scopes/%s/protocol-mappers/models", realmName, scopeID), "client scope protocol mapper")
+}
+
+// Resources, scopes and policies are created or updated by name
+func (c *Client) ImportClientAuthorizationSettings(clientID string, settings *v1alpha1.ResourceServerRepresentation, realmName string) error {
+	_, err := c.create(settings, fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/import", realmName, clientID), "client authorization settings")
+	return err
+}
+
 func (c *Client) CreateUser(user *v1alpha1.KeycloakAPIUser, realmName string) (string, error) {
 	return c.create(user, fmt.Sprintf("realms/%s/users", realmName), "user")
 }
@@ -117,6 +203,9 @@ func (c *Client) GetUserFederatedIdentities(userID string, realmName string) ([]
 	if err != nil {
 		return nil, err
 	}
+	if result == nil {
+		return nil, nil
+	}
 	return result.([]v1alpha1.FederatedIdentity), err
 }
 
@@ -139,6 +228,44 @@ func (c *Client) CreateAuthenticatorConfig(authenticatorConfig *v1alpha1.Authent
 	return c.create(authenticatorConfig, fmt.Sprintf("realms/%s/authentication/executions/%s/config", realmName, executionID), "AuthenticatorConfig")
 }
 
+// Executions of the flow are ignored by keycloak, they are added one by one
+func (c *Client) CreateAuthenticationFlow(flow *v1alpha1.KeycloakAPIAuthenticationFlow, realmName string) (string, error) {
+	return c.create(flow, fmt.Sprintf("realms/%s/authentication/flows", realmName), "authentication flow")
+}
+
+func (c *Client) CreateComponent(component *v1alpha1.KeycloakAPIComponent, realmName string) (string, error) {
+	return c.create(component, fmt.Sprintf("realms/%s/components", realmName), "component")
+}
+
+func (c *Client) CreateAuthenticationExecution(flowAlias, provider, realmName string) (string, error) {
+	execution := map[string]string{
+		"provider": provider,
+	}
+	return c.create(execution, fmt.Sprintf("realms/%s/authentication/flows/%s/executions/execution", realmName, url.PathEscape(flowAlias)), "authentication execution")
+}
+
+// The provider is only used by form flows, the admin console always sends the same one
+func (c *Client) CreateAuthenticationSubFlow(flowAlias string, subFlow *v1alpha1.KeycloakAPIAuthenticationFlow, realmName string) (string, error) {
+	execution := map[string]string{
+		"alias":       subFlow.Alias,
+		"type":        subFlow.ProviderID,
+		"provider":    "registration-page-form",
+		"description": subFlow.Description,
+	}
+	return c.create(execution, fmt.Sprintf("realms/%s/authentication/flows/%s/executions/flow", realmName, url.PathEscape(flowAlias)), "authentication sub flow")
+}
+
+// Registers the required action of a provider that keycloak doesn't have yet, the
+// alias of the new required action is the provider ID
+func (c *Client) RegisterRequiredAction(providerID, name, realmName string) error {
+	provider := map[string]string{
+		"providerId": providerID,
+		"name":       name,
+	}
+	_, err := c.create(provider, fmt.Sprintf("realms/%s/authentication/register-required-action", realmName), "required action")
+	return err
+}
+
 func (c *Client) DeleteUserClientRole(role *v1alpha1.KeycloakUserRole, realmName, clientID, userID string) error {
 	err := c.delete(
 		fmt.Sprintf("realms/%s/users/%s/role-mappings/clients/%s", realmName, userID, clientID),
@@ -157,6 +284,11 @@ func (c *Client) DeleteUserRealmRole(role *v1alpha1.KeycloakUserRole, realmName,
 	return err
 }
 
+func (c *Client) RemoveUserFromGroup(realmName, userID, groupID string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/users/%s/groups/%s", realmName, userID, groupID), "user group", nil)
+	return err
+}
+
 func (c *Client) UpdatePassword(user *v1alpha1.KeycloakAPIUser, realmName, newPass string) error {
 	passReset := &v1alpha1.KeycloakAPIPasswordReset{}
 	passReset.Type = "password"
@@ -216,9 +348,13 @@ func (c *Client) CreateIdentityProvider(identityProvider *v1alpha1.KeycloakIdent
 	return c.create(identityProvider, fmt.Sprintf("realms/%s/identity-provider/instances", realmName), "identity provider")
 }
 
+func (c *Client) CreateIdentityProviderMapper(mapper *v1alpha1.KeycloakIdentityProviderMapper, realmName string) (string, error) {
+	return c.create(mapper, fmt.Sprintf("realms/%s/identity-provider/instances/%s/mappers", realmName, mapper.IdentityProviderAlias), "identity provider mapper")
+}
+
 // Generic get function for returning a Keycloak resource
 func (c *Client) get(resourcePath, resourceName string, unMarshalFunc func(body []byte) (T, error)) (T, error) {
-	u := fmt.Sprintf("%s/auth/admin/%s", c.URL, resourcePath)
+	u := fmt.Sprintf("%s/admin/%s", c.baseURL(), resourcePath)
 	req, err := http.NewRequest(
 		"GET",
 		u,
@@ -277,6 +413,37 @@ func (c *Client) GetRealm(realmName string) (*v1alpha1.KeycloakRealm, error) {
 	return ret, err
 }
 
+func (c *Client) GetRealmEventsConfig(realmName string) (*v1alpha1.KeycloakAPIRealmEventsConfig, error) {
+	result, err := c.get(fmt.Sprintf("realms/%s/events/config", realmName), "realm events config", func(body []byte) (T, error) {
+		config := &v1alpha1.KeycloakAPIRealmEventsConfig{}
+		err := json.Unmarshal(body, config)
+		return config, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result.(*v1alpha1.KeycloakAPIRealmEventsConfig), err
+}
+
+// Message overrides of a locale of the realm, keyed by message key
+func (c *Client) GetRealmLocalizationTexts(locale, realmName string) (map[string]string, error) {
+	result, err := c.get(fmt.Sprintf("realms/%s/localization/%s", realmName, url.PathEscape(locale)), "realm localization texts", func(body []byte) (T, error) {
+		texts := map[string]string{}
+		err := json.Unmarshal(body, &texts)
+		return texts, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result.(map[string]string), err
+}
+
 func (c *Client) GetClient(clientID, realmName string) (*v1alpha1.KeycloakAPIClient, error) {
 	result, err := c.get(fmt.Sprintf("realms/%s/clients/%s", realmName, clientID), "client", func(body []byte) (T, error) {
 		client := &v1alpha1.KeycloakAPIClient{}
@@ -311,6 +478,149 @@ func (c *Client) GetClientSecret(clientID, realmName string) (string, error) {
 	return result.(string), nil
 }
 
+// Keycloak answers the regeneration with the new credential instead of a
+// Location header, so the generic create function can't be used here
+func (c *Client) RegenerateClientSecret(clientID, realmName string) (string, error) {
+	req, err := http.NewRequest(
+		"POST",
+		fmt.Sprintf("%s/admin/realms/%s/clients/%s/client-secret", c.baseURL(), realmName, clientID),
+		nil,
+	)
+	if err != nil {
+		logrus.Errorf("error creating POST client-secret request %+v", err)
+		return "", errors.Wrapf(err, "error creating POST client-secret request")
+	}
+
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	res, err := c.requester.Do(req)
+	if err != nil {
+		logrus.Errorf("error on request %+v", err)
+		return "", errors.Wrapf(err, "error performing POST client-secret request")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return "", errors.Errorf("failed to regenerate client-secret: (%d) %s", res.StatusCode, res.Status)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		logrus.Errorf("error reading response %+v", err)
+		return "", errors.Wrapf(err, "error reading client-secret POST response")
+	}
+
+	credential := map[string]string{}
+	if err := json.Unmarshal(body, &credential); err != nil {
+		return "", err
+	}
+	return credential["value"], nil
+}
+
+// Issuing a registration access token invalidates the previous one. Keycloak
+// answers with the client, which only then contains the token
+func (c *Client) RegenerateRegistrationAccessToken(clientID, realmName string) (string, error) {
+	req, err := http.NewRequest(
+		"POST",
+		fmt.Sprintf("%s/admin/realms/%s/clients/%s/registration-access-token", c.baseURL(), realmName, clientID),
+		nil,
+	)
+	if err != nil {
+		logrus.Errorf("error creating POST registration-access-token request %+v", err)
+		return "", errors.Wrapf(err, "error creating POST registration-access-token request")
+	}
+
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	res, err := c.requester.Do(req)
+	if err != nil {
+		logrus.Errorf("error on request %+v", err)
+		return "", errors.Wrapf(err, "error performing POST registration-access-token request")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return "", errors.Errorf("failed to regenerate registration-access-token: (%d) %s", res.StatusCode, res.Status)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		logrus.Errorf("error reading response %+v", err)
+		return "", errors.Wrapf(err, "error reading registration-access-token POST response")
+	}
+
+	client := struct {
+		RegistrationAccessToken string `json:"registrationAccessToken"`
+	}{}
+	if err := json.Unmarshal(body, &client); err != nil {
+		return "", err
+	}
+	return client.RegistrationAccessToken, nil
+}
+
+// The resource server itself doesn't contain the IDs of its resources, scopes and
+// policies, so they are listed separately
+func (c *Client) GetClientAuthorizationSettings(clientID, realmName string) (*v1alpha1.ResourceServerRepresentation, error) {
+	resourcePath := fmt.Sprintf("realms/%s/clients/%s/authz/resource-server", realmName, clientID)
+	result, err := c.get(resourcePath, "client authorization settings", func(body []byte) (T, error) {
+		settings := &v1alpha1.ResourceServerRepresentation{}
+		err := json.Unmarshal(body, settings)
+		return settings, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	settings := result.(*v1alpha1.ResourceServerRepresentation)
+
+	resources, err := c.list(resourcePath+"/resource?first=0&max=-1", "client authorization resources", func(body []byte) (T, error) {
+		var resources []v1alpha1.ResourceRepresentation
+		err := json.Unmarshal(body, &resources)
+		return resources, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	settings.Resources = resources.([]v1alpha1.ResourceRepresentation)
+
+	scopes, err := c.list(resourcePath+"/scope?first=0&max=-1", "client authorization scopes", func(body []byte) (T, error) {
+		var scopes []v1alpha1.ScopeRepresentation
+		err := json.Unmarshal(body, &scopes)
+		return scopes, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	settings.Scopes = scopes.([]v1alpha1.ScopeRepresentation)
+
+	policies, err := c.list(resourcePath+"/policy?first=0&max=-1", "client authorization policies", func(body []byte) (T, error) {
+		var policies []v1alpha1.PolicyRepresentation
+		err := json.Unmarshal(body, &policies)
+		return policies, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	settings.Policies = policies.([]v1alpha1.PolicyRepresentation)
+
+	return settings, nil
+}
+
+func (c *Client) GetServiceAccountUser(clientID, realmName string) (*v1alpha1.KeycloakAPIUser, error) {
+	result, err := c.get(fmt.Sprintf("realms/%s/clients/%s/service-account-user", realmName, clientID), "service account user", func(body []byte) (T, error) {
+		user := &v1alpha1.KeycloakAPIUser{}
+		err := json.Unmarshal(body, user)
+		return user, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result.(*v1alpha1.KeycloakAPIUser), nil
+}
+
 func (c *Client) GetClientInstall(clientID, realmName string) ([]byte, error) {
 	var response []byte
 	if _, err := c.get(fmt.Sprintf("realms/%s/clients/%s/installation/providers/keycloak-oidc-keycloak-json", realmName, clientID), "client-installation", func(body []byte) (T, error) {
@@ -322,6 +632,36 @@ func (c *Client) GetClientInstall(clientID, realmName string) ([]byte, error) {
 	return response, nil
 }
 
+func (c *Client) GetClientRole(clientID, roleName, realmName string) (*v1alpha1.RoleRepresentation, error) {
+	result, err := c.get(fmt.Sprintf("realms/%s/clients/%s/roles/%s", realmName, clientID, roleName), "client role", func(body []byte) (T, error) {
+		role := &v1alpha1.RoleRepresentation{}
+		err := json.Unmarshal(body, role)
+		return role, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result.(*v1alpha1.RoleRepresentation), nil
+}
+
+func (c *Client) GetRealmRole(roleName, realmName string) (*v1alpha1.RoleRepresentation, error) {
+	result, err := c.get(fmt.Sprintf("realms/%s/roles/%s", realmName, roleName), "realm role", func(body []byte) (T, error) {
+		role := &v1alpha1.RoleRepresentation{}
+		err := json.Unmarshal(body, role)
+		return role, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result.(*v1alpha1.RoleRepresentation), nil
+}
+
 func (c *Client) GetUser(userID, realmName string) (*v1alpha1.KeycloakAPIUser, error) {
 	result, err := c.get(fmt.Sprintf("realms/%s/users/%s", realmName, userID), "user", func(body []byte) (T, error) {
 		user := &v1alpha1.KeycloakAPIUser{}
@@ -338,6 +678,27 @@ func (c *Client) GetUser(userID, realmName string) (*v1alpha1.KeycloakAPIUser, e
 	return ret, err
 }
 
+// Group paths contain slashes, so only their segments are escaped
+func (c *Client) GetGroupByPath(path, realmName string) (*v1alpha1.KeycloakUserGroup, error) {
+	var segments []string
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		segments = append(segments, url.PathEscape(segment))
+	}
+
+	result, err := c.get(fmt.Sprintf("realms/%s/group-by-path/%s", realmName, strings.Join(segments, "/")), "group", func(body []byte) (T, error) {
+		group := &v1alpha1.KeycloakUserGroup{}
+		err := json.Unmarshal(body, group)
+		return group, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result.(*v1alpha1.KeycloakUserGroup), nil
+}
+
 func (c *Client) GetIdentityProvider(alias string, realmName string) (*v1alpha1.KeycloakIdentityProvider, error) {
 	result, err := c.get(fmt.Sprintf("realms/%s/identity-provider/instances/%s", realmName, alias), "identity provider", func(body []byte) (T, error) {
 		provider := &v1alpha1.KeycloakIdentityProvider{}
@@ -368,6 +729,21 @@ func (c *Client) GetAuthenticatorConfig(configID, realmName string) (*v1alpha1.A
 	return result.(*v1alpha1.AuthenticatorConfig), err
 }
 
+func (c *Client) GetRequiredAction(alias, realmName string) (*v1alpha1.KeycloakAPIRequiredAction, error) {
+	result, err := c.get(fmt.Sprintf("realms/%s/authentication/required-actions/%s", realmName, url.PathEscape(alias)), "required action", func(body []byte) (T, error) {
+		action := &v1alpha1.KeycloakAPIRequiredAction{}
+		err := json.Unmarshal(body, action)
+		return action, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result.(*v1alpha1.KeycloakAPIRequiredAction), err
+}
+
 // Generic put function for updating Keycloak resources
 func (c *Client) update(obj T, resourcePath, resourceName string) error {
 	jsonValue, err := json.Marshal(obj)
@@ -377,7 +753,7 @@ func (c *Client) update(obj T, resourcePath, resourceName string) error {
 
 	req, err := http.NewRequest(
 		"PUT",
-		fmt.Sprintf("%s/auth/admin/%s", c.URL, resourcePath),
+		fmt.Sprintf("%s/admin/%s", c.baseURL(), resourcePath),
 		bytes.NewBuffer(jsonValue),
 	)
 	if err != nil {
@@ -401,80 +777,267 @@ func (c *Client) update(obj T, resourcePath, resourceName string) error {
 	return nil
 }
 
-func (c *Client) UpdateRealm(realm *v1alpha1.KeycloakRealm) error {
-	return c.update(realm, fmt.Sprintf("realms/%s", realm.Spec.Realm.ID), "realm")
-}
-
-func (c *Client) UpdateClient(specClient *v1alpha1.KeycloakAPIClient, realmName string) error {
-	return c.update(specClient, fmt.Sprintf("realms/%s/clients/%s", realmName, specClient.ID), "client")
-}
-
-func (c *Client) UpdateClientRole(clientID string, role, oldRole *v1alpha1.RoleRepresentation, realmName string) error {
-	return c.update(role, fmt.Sprintf("realms/%s/clients/%s/roles/%s", realmName, clientID, oldRole.Name), "client role")
-}
-
-func (c *Client) UpdateUser(specUser *v1alpha1.KeycloakAPIUser, realmName string) error {
-	return c.update(specUser, fmt.Sprintf("realms/%s/users/%s", realmName, specUser.ID), "user")
-}
-
-func (c *Client) UpdateIdentityProvider(specIdentityProvider *v1alpha1.KeycloakIdentityProvider, realmName string) error {
-	return c.update(specIdentityProvider, fmt.Sprintf("realms/%s/identity-provider/instances/%s", realmName, specIdentityProvider.Alias), "identity provider")
+// Keycloak only updates the attributes present in the representation
+func (c *Client) UpdateRealm(realm *v1alpha1.KeycloakAPIRealm) error {
+	return c.update(realm, fmt.Sprintf("realms/%s", realm.Realm), "realm")
 }
 
-func (c *Client) UpdateAuthenticatorConfig(authenticatorConfig *v1alpha1.AuthenticatorConfig, realmName string) error {
-	return c.update(authenticatorConfig, fmt.Sprintf("realms/%s/authentication/config/%s", realmName, authenticatorConfig.ID), "AuthenticatorConfig")
+func (c *Client) UpdateRealmEventsConfig(config *v1alpha1.KeycloakAPIRealmEventsConfig, realmName string) error {
+	return c.update(config, fmt.Sprintf("realms/%s/events/config", realmName), "realm events config")
 }
 
-// Generic delete function for deleting Keycloak resources
-func (c *Client) delete(resourcePath, resourceName string, obj T) error {
+// Keycloak takes the text of a message override as plain text rather than JSON
+func (c *Client) UpdateRealmLocalizationText(locale, key, text, realmName string) error {
 	req, err := http.NewRequest(
-		"DELETE",
-		fmt.Sprintf("%s/auth/admin/%s", c.URL, resourcePath),
-		nil,
+		"PUT",
+		fmt.Sprintf("%s/admin/realms/%s/localization/%s/%s", c.baseURL(), realmName, url.PathEscape(locale), url.PathEscape(key)),
+		strings.NewReader(text),
 	)
-
-	if obj != nil {
-		jsonValue, err := json.Marshal(obj)
-		if err != nil {
-			return nil
-		}
-		req, err = http.NewRequest(
-			"DELETE",
-			fmt.Sprintf("%s/auth/admin/%s", c.URL, resourcePath),
-			bytes.NewBuffer(jsonValue),
-		)
-		if err != nil {
-			return nil
-		}
-		req.Header.Set("Content-Type", "application/json")
-	}
-
 	if err != nil {
-		logrus.Errorf("error creating DELETE %s request %+v", resourceName, err)
-		return errors.Wrapf(err, "error creating DELETE %s request", resourceName)
+		logrus.Errorf("error creating UPDATE realm localization text request %+v", err)
+		return errors.Wrapf(err, "error creating UPDATE realm localization text request")
 	}
 
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Add("Authorization", "Bearer "+c.token)
 	res, err := c.requester.Do(req)
 	if err != nil {
 		logrus.Errorf("error on request %+v", err)
-		return errors.Wrapf(err, "error performing DELETE %s request", resourceName)
+		return errors.Wrapf(err, "error performing UPDATE realm localization text request")
 	}
 	defer res.Body.Close()
-	if res.StatusCode == 404 {
-		logrus.Errorf("Resource %v/%v already deleted", resourcePath, resourceName)
-	}
-	if res.StatusCode != 204 && res.StatusCode != 404 {
-		return errors.Errorf("failed to DELETE %s: (%d) %s", resourceName, res.StatusCode, res.Status)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		logrus.Errorf("failed to UPDATE realm localization text %v", res.Status)
+		return errors.Errorf("failed to UPDATE realm localization text: (%d) %s", res.StatusCode, res.Status)
 	}
 
 	return nil
 }
 
-func (c *Client) DeleteRealm(realmName string) error {
-	err := c.delete(fmt.Sprintf("realms/%s", realmName), "realm", nil)
-	return err
-}
+func (c *Client) UpdateClient(specClient *v1alpha1.KeycloakAPIClient, realmName string) error {
+	return c.update(specClient, fmt.Sprintf("realms/%s/clients/%s", realmName, specClient.ID), "client")
+}
+
+func (c *Client) UpdateClientRole(clientID string, role, oldRole *v1alpha1.RoleRepresentation, realmName string) error {
+	return c.update(role, fmt.Sprintf("realms/%s/clients/%s/roles/%s", realmName, clientID, oldRole.Name), "client role")
+}
+
+// Sub groups and role mappings are left untouched
+func (c *Client) UpdateGroup(group *v1alpha1.KeycloakAPIGroup, realmName string) error {
+	return c.update(group, fmt.Sprintf("realms/%s/groups/%s", realmName, group.ID), "group")
+}
+
+func (c *Client) UpdateRealmRole(role, oldRole *v1alpha1.RoleRepresentation, realmName string) error {
+	return c.update(role, fmt.Sprintf("realms/%s/roles/%s", realmName, oldRole.Name), "realm role")
+}
+
+// Mappers without an ID in the CR were matched by name, so the ID of the
+// existing mapper is used
+func (c *Client) UpdateClientProtocolMapper(clientID string, mapper, oldMapper *v1alpha1.KeycloakProtocolMapper, realmName string) error {
+	updated := mapper.DeepCopy()
+	updated.ID = oldMapper.ID
+	return c.update(updated, fmt.Sprintf("realms/%s/clients/%s/protocol-mappers/models/%s", realmName, clientID, oldMapper.ID), "client protocol mapper")
+}
+
+// Keycloak leaves the protocol mappers untouched when updating a client scope,
+// they are managed on their own
+func (c *Client) UpdateClientScope(scope *v1alpha1.KeycloakAPIClientScope, realmName string) error {
+	return c.update(scope, fmt.Sprintf("realms/%s/client-scopes/%s", realmName, scope.ID), "client scope")
+}
+
+func (c *Client) UpdateClientScopeProtocolMapper(scopeID string, mapper, oldMapper *v1alpha1.KeycloakProtocolMapper, realmName string) error {
+	updated := mapper.DeepCopy()
+	updated.ID = oldMapper.ID
+	return c.update(updated, fmt.Sprintf("realms/%s/client-scopes/%s/protocol-mappers/models/%s", realmName, scopeID, oldMapper.ID), "client scope protocol mapper")
+}
+
+// Only the secret is sent, keycloak leaves the other attributes of the client untouched
+func (c *Client) UpdateClientSecret(clientID, secret, realmName string) error {
+	return c.update(map[string]string{"id": clientID, "secret": secret}, fmt.Sprintf("realms/%s/clients/%s", realmName, clientID), "client secret")
+}
+
+func (c *Client) AddDefaultClientScope(clientID, scopeID, realmName string) error {
+	return c.update(nil, fmt.Sprintf("realms/%s/clients/%s/default-client-scopes/%s", realmName, clientID, scopeID), "default client scope")
+}
+
+func (c *Client) AddDefaultGroup(groupID, realmName string) error {
+	return c.update(nil, fmt.Sprintf("realms/%s/default-groups/%s", realmName, groupID), "default group")
+}
+
+// Realm default client scopes are added to new clients, as default or optional scopes
+func (c *Client) AddRealmDefaultClientScope(scopeID string, optional bool, realmName string) error {
+	return c.update(nil, fmt.Sprintf("realms/%s/%s/%s", realmName, realmDefaultClientScopesPath(optional), scopeID), "realm default client scope")
+}
+
+func (c *Client) AddOptionalClientScope(clientID, scopeID, realmName string) error {
+	return c.update(nil, fmt.Sprintf("realms/%s/clients/%s/optional-client-scopes/%s", realmName, clientID, scopeID), "optional client scope")
+}
+
+func (c *Client) AddUserToGroup(realmName, userID, groupID string) error {
+	return c.update(nil, fmt.Sprintf("realms/%s/users/%s/groups/%s", realmName, userID, groupID), "user group")
+}
+
+func (c *Client) UpdateUser(specUser *v1alpha1.KeycloakAPIUser, realmName string) error {
+	return c.update(specUser, fmt.Sprintf("realms/%s/users/%s", realmName, specUser.ID), "user")
+}
+
+func (c *Client) ResetUserPassword(userID string, credential *v1alpha1.KeycloakCredential, realmName string) error {
+	return c.update(credential, fmt.Sprintf("realms/%s/users/%s/reset-password", realmName, userID), "user password")
+}
+
+// Sends the user an email with a link to perform the given required actions
+func (c *Client) SendUserActionsEmail(userID string, email *v1alpha1.KeycloakUserActionsEmail, realmName string) error {
+	actions := email.Actions
+	if len(actions) == 0 {
+		actions = []string{"VERIFY_EMAIL"}
+	}
+
+	query := url.Values{}
+	if email.ClientID != "" {
+		query.Set("client_id", email.ClientID)
+	}
+	if email.RedirectURI != "" {
+		query.Set("redirect_uri", email.RedirectURI)
+	}
+	if email.Lifespan != nil {
+		query.Set("lifespan", fmt.Sprint(*email.Lifespan))
+	}
+
+	path := fmt.Sprintf("realms/%s/users/%s/execute-actions-email", realmName, userID)
+	if len(query) > 0 {
+		path = path + "?" + query.Encode()
+	}
+	return c.update(actions, path, "user actions email")
+}
+
+func (c *Client) UpdateIdentityProvider(specIdentityProvider *v1alpha1.KeycloakIdentityProvider, realmName string) error {
+	return c.update(specIdentityProvider, fmt.Sprintf("realms/%s/identity-provider/instances/%s", realmName, specIdentityProvider.Alias), "identity provider")
+}
+
+func (c *Client) UpdateIdentityProviderMapper(mapper *v1alpha1.KeycloakIdentityProviderMapper, realmName string) error {
+	return c.update(mapper, fmt.Sprintf("realms/%s/identity-provider/instances/%s/mappers/%s", realmName, mapper.IdentityProviderAlias, mapper.ID), "identity provider mapper")
+}
+
+func (c *Client) UpdateAuthenticatorConfig(authenticatorConfig *v1alpha1.AuthenticatorConfig, realmName string) error {
+	return c.update(authenticatorConfig, fmt.Sprintf("realms/%s/authentication/config/%s", realmName, authenticatorConfig.ID), "AuthenticatorConfig")
+}
+
+// Keycloak replaces all settings of the required action with the given ones
+func (c *Client) UpdateRequiredAction(action *v1alpha1.KeycloakAPIRequiredAction, realmName string) error {
+	return c.update(action, fmt.Sprintf("realms/%s/authentication/required-actions/%s", realmName, url.PathEscape(action.Alias)), "required action")
+}
+
+// Client profiles of the realm, without the global ones
+func (c *Client) ListClientProfiles(realmName string) ([]v1alpha1.KeycloakAPIClientProfile, error) {
+	result, err := c.list(fmt.Sprintf("realms/%s/client-policies/profiles", realmName), "client profiles", func(body []byte) (T, error) {
+		rep := struct {
+			Profiles []v1alpha1.KeycloakAPIClientProfile `json:"profiles"`
+		}{}
+		err := json.Unmarshal(body, &rep)
+		return rep.Profiles, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]v1alpha1.KeycloakAPIClientProfile), err
+}
+
+// Client policies of the realm
+func (c *Client) ListClientPolicies(realmName string) ([]v1alpha1.KeycloakAPIClientPolicy, error) {
+	result, err := c.list(fmt.Sprintf("realms/%s/client-policies/policies", realmName), "client policies", func(body []byte) (T, error) {
+		rep := struct {
+			Policies []v1alpha1.KeycloakAPIClientPolicy `json:"policies"`
+		}{}
+		err := json.Unmarshal(body, &rep)
+		return rep.Policies, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]v1alpha1.KeycloakAPIClientPolicy), err
+}
+
+// Replaces all client profiles of the realm, global profiles are kept
+func (c *Client) UpdateClientProfiles(profiles []v1alpha1.KeycloakAPIClientProfile, realmName string) error {
+	if profiles == nil {
+		profiles = []v1alpha1.KeycloakAPIClientProfile{}
+	}
+	rep := map[string][]v1alpha1.KeycloakAPIClientProfile{"profiles": profiles}
+	return c.update(rep, fmt.Sprintf("realms/%s/client-policies/profiles", realmName), "client profiles")
+}
+
+// Replaces all client policies of the realm
+func (c *Client) UpdateClientPolicies(policies []v1alpha1.KeycloakAPIClientPolicy, realmName string) error {
+	if policies == nil {
+		policies = []v1alpha1.KeycloakAPIClientPolicy{}
+	}
+	rep := map[string][]v1alpha1.KeycloakAPIClientPolicy{"policies": policies}
+	return c.update(rep, fmt.Sprintf("realms/%s/client-policies/policies", realmName), "client policies")
+}
+
+func (c *Client) UpdateAuthenticationExecution(flowAlias string, execution *v1alpha1.AuthenticationExecutionInfo, realmName string) error {
+	return c.update(execution, fmt.Sprintf("realms/%s/authentication/flows/%s/executions", realmName, url.PathEscape(flowAlias)), "authentication execution")
+}
+
+// Only the given bindings are sent, keycloak leaves the other attributes of the realm untouched
+func (c *Client) UpdateRealmFlowBindings(bindings map[string]string, realmName string) error {
+	return c.update(bindings, fmt.Sprintf("realms/%s", realmName), "realm flow bindings")
+}
+
+func (c *Client) UpdateComponent(component *v1alpha1.KeycloakAPIComponent, realmName string) error {
+	return c.update(component, fmt.Sprintf("realms/%s/components/%s", realmName, component.ID), "component")
+}
+
+// Generic delete function for deleting Keycloak resources
+func (c *Client) delete(resourcePath, resourceName string, obj T) error {
+	req, err := http.NewRequest(
+		"DELETE",
+		fmt.Sprintf("%s/admin/%s", c.baseURL(), resourcePath),
+		nil,
+	)
+
+	if obj != nil {
+		jsonValue, err := json.Marshal(obj)
+		if err != nil {
+			return nil
+		}
+		req, err = http.NewRequest(
+			"DELETE",
+			fmt.Sprintf("%s/admin/%s", c.baseURL(), resourcePath),
+			bytes.NewBuffer(jsonValue),
+		)
+		if err != nil {
+			return nil
+		}
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if err != nil {
+		logrus.Errorf("error creating DELETE %s request %+v", resourceName, err)
+		return errors.Wrapf(err, "error creating DELETE %s request", resourceName)
+	}
+
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	res, err := c.requester.Do(req)
+	if err != nil {
+		logrus.Errorf("error on request %+v", err)
+		return errors.Wrapf(err, "error performing DELETE %s request", resourceName)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == 404 {
+		logrus.Errorf("Resource %v/%v already deleted", resourcePath, resourceName)
+	}
+	if res.StatusCode != 204 && res.StatusCode != 404 {
+		return errors.Errorf("failed to DELETE %s: (%d) %s", resourceName, res.StatusCode, res.Status)
+	}
+
+	return nil
+}
+
+func (c *Client) DeleteRealm(realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s", realmName), "realm", nil)
+	return err
+}
 
 func (c *Client) DeleteClient(clientID, realmName string) error {
 	err := c.delete(fmt.Sprintf("realms/%s/clients/%s", realmName, clientID), "client", nil)
@@ -486,6 +1049,101 @@ func (c *Client) DeleteClientRole(clientID, role, realmName string) error {
 	return err
 }
 
+func (c *Client) DeleteClientRoleComposites(clientID, roleName string, composites []v1alpha1.RoleRepresentation, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/clients/%s/roles/%s/composites", realmName, clientID, roleName), "client role composites", composites)
+	return err
+}
+
+func (c *Client) DeleteGroup(groupID, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/groups/%s", realmName, groupID), "group", nil)
+	return err
+}
+
+func (c *Client) DeleteGroupRealmRoleMappings(groupID string, roles []v1alpha1.RoleRepresentation, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/groups/%s/role-mappings/realm", realmName, groupID), "group realm role mappings", roles)
+	return err
+}
+
+func (c *Client) DeleteGroupClientRoleMappings(groupID, clientID string, roles []v1alpha1.RoleRepresentation, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/groups/%s/role-mappings/clients/%s", realmName, groupID, clientID), "group client role mappings", roles)
+	return err
+}
+
+func (c *Client) DeleteClientRealmScopeMappings(clientID string, roles []v1alpha1.RoleRepresentation, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/clients/%s/scope-mappings/realm", realmName, clientID), "client realm scope mappings", roles)
+	return err
+}
+
+func (c *Client) DeleteClientClientScopeMappings(clientID, roleClientID string, roles []v1alpha1.RoleRepresentation, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/clients/%s/scope-mappings/clients/%s", realmName, clientID, roleClientID), "client client scope mappings", roles)
+	return err
+}
+
+func (c *Client) DeleteRealmRole(role, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/roles/%s", realmName, role), "realm role", nil)
+	return err
+}
+
+func (c *Client) DeleteRealmLocalizationText(locale, key, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/localization/%s/%s", realmName, url.PathEscape(locale), url.PathEscape(key)), "realm localization text", nil)
+	return err
+}
+
+func (c *Client) DeleteRealmRoleComposites(roleName string, composites []v1alpha1.RoleRepresentation, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/roles/%s/composites", realmName, roleName), "realm role composites", composites)
+	return err
+}
+
+func (c *Client) DeleteClientProtocolMapper(clientID, mapperID, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/clients/%s/protocol-mappers/models/%s", realmName, clientID, mapperID), "client protocol mapper", nil)
+	return err
+}
+
+func (c *Client) DeleteClientScope(scopeID, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/client-scopes/%s", realmName, scopeID), "client scope", nil)
+	return err
+}
+
+func (c *Client) DeleteClientScopeProtocolMapper(scopeID, mapperID, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/client-scopes/%s/protocol-mappers/models/%s", realmName, scopeID, mapperID), "client scope protocol mapper", nil)
+	return err
+}
+
+func (c *Client) DeleteClientAuthorizationResource(clientID, resourceID, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/resource/%s", realmName, clientID, resourceID), "client authorization resource", nil)
+	return err
+}
+
+func (c *Client) DeleteClientAuthorizationScope(clientID, scopeID, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/scope/%s", realmName, clientID, scopeID), "client authorization scope", nil)
+	return err
+}
+
+func (c *Client) DeleteClientAuthorizationPolicy(clientID, policyID, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/clients/%s/authz/resource-server/policy/%s", realmName, clientID, policyID), "client authorization policy", nil)
+	return err
+}
+
+func (c *Client) RemoveDefaultGroup(groupID, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/default-groups/%s", realmName, groupID), "default group", nil)
+	return err
+}
+
+func (c *Client) RemoveRealmDefaultClientScope(scopeID string, optional bool, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/%s/%s", realmName, realmDefaultClientScopesPath(optional), scopeID), "realm default client scope", nil)
+	return err
+}
+
+func (c *Client) RemoveDefaultClientScope(clientID, scopeID, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/clients/%s/default-client-scopes/%s", realmName, clientID, scopeID), "default client scope", nil)
+	return err
+}
+
+func (c *Client) RemoveOptionalClientScope(clientID, scopeID, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/clients/%s/optional-client-scopes/%s", realmName, clientID, scopeID), "optional client scope", nil)
+	return err
+}
+
 func (c *Client) DeleteUser(userID, realmName string) error {
 	err := c.delete(fmt.Sprintf("realms/%s/users/%s", realmName, userID), "user", nil)
 	return err
@@ -496,16 +1154,31 @@ func (c *Client) DeleteIdentityProvider(alias string, realmName string) error {
 	return err
 }
 
+func (c *Client) DeleteIdentityProviderMapper(alias, mapperID, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/identity-provider/instances/%s/mappers/%s", realmName, alias, mapperID), "identity provider mapper", nil)
+	return err
+}
+
 func (c *Client) DeleteAuthenticatorConfig(configID, realmName string) error {
 	err := c.delete(fmt.Sprintf("realms/%s/authentication/config/%s", realmName, configID), "AuthenticatorConfig", nil)
 	return err
 }
 
+func (c *Client) DeleteAuthenticationFlow(flowID, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/authentication/flows/%s", realmName, flowID), "authentication flow", nil)
+	return err
+}
+
+func (c *Client) DeleteComponent(componentID, realmName string) error {
+	err := c.delete(fmt.Sprintf("realms/%s/components/%s", realmName, componentID), "component", nil)
+	return err
+}
+
 // Generic list function for listing Keycloak resources
 func (c *Client) list(resourcePath, resourceName string, unMarshalListFunc func(body []byte) (T, error)) (T, error) {
 	req, err := http.NewRequest(
 		"GET",
-		fmt.Sprintf("%s/auth/admin/%s", c.URL, resourcePath),
+		fmt.Sprintf("%s/admin/%s", c.baseURL(), resourcePath),
 		nil,
 	)
 	if err != nil {
@@ -539,6 +1212,59 @@ func (c *Client) list(resourcePath, resourceName string, unMarshalListFunc func(
 	return objs, nil
 }
 
+// listPaged requests a list page by page until a page comes back incomplete, so
+// that no list is cut off at the default maximum of keycloak. The page function
+// decodes and collects a single page and returns the number of entries in it.
+// A page starting with the same entry as the one before means the server ignores
+// the paging parameters, so paging stops there without collecting it again
+func (c *Client) listPaged(resourcePath, resourceName string, page func(body []byte) (int, error)) error {
+	separator := "?"
+	if strings.Contains(resourcePath, "?") {
+		separator = "&"
+	}
+
+	pageSize := c.getPageSize()
+	var previousFirst json.RawMessage
+	for pages := 0; pages < maxAdminClientPages; pages++ {
+		count := 0
+		repeated := false
+		var pageErr error
+		_, err := c.list(fmt.Sprintf("%s%sfirst=%v&max=%v", resourcePath, separator, pages*pageSize, pageSize), resourceName, func(body []byte) (T, error) {
+			var entries []json.RawMessage
+			pageErr = json.Unmarshal(body, &entries)
+			if pageErr != nil {
+				return nil, pageErr
+			}
+			if len(entries) > 0 && previousFirst != nil && bytes.Equal(entries[0], previousFirst) {
+				repeated = true
+				return nil, nil
+			}
+			if len(entries) > 0 {
+				previousFirst = entries[0]
+			}
+			count, pageErr = page(body)
+			return nil, pageErr
+		})
+		if err != nil {
+			return err
+		}
+		if pageErr != nil {
+			return errors.Wrapf(pageErr, "error decoding list %s response", resourceName)
+		}
+		if repeated || count < pageSize {
+			return nil
+		}
+	}
+	return errors.Errorf("list %s has more than %v pages", resourceName, maxAdminClientPages)
+}
+
+func (c *Client) getPageSize() int {
+	if c.pageSize > 0 {
+		return c.pageSize
+	}
+	return defaultAdminClientPageSize
+}
+
 func (c *Client) ListRealms() ([]*v1alpha1.KeycloakRealm, error) {
 	result, err := c.list("realms", "realm", func(body []byte) (T, error) {
 		var realms []*v1alpha1.KeycloakRealm
@@ -549,179 +1275,690 @@ func (c *Client) ListRealms() ([]*v1alpha1.KeycloakRealm, error) {
 	if !ok {
 		return nil, err
 	}
-	return resultAsRealm, err
-}
-
-func (c *Client) ListClients(realmName string) ([]*v1alpha1.KeycloakAPIClient, error) {
-	result, err := c.list(fmt.Sprintf("realms/%s/clients", realmName), "clients", func(body []byte) (T, error) {
-		var clients []*v1alpha1.KeycloakAPIClient
-		err := json.Unmarshal(body, &clients)
-		return clients, err
-	})
+	return resultAsRealm, err
+}
+
+func (c *Client) ListClients(realmName string) ([]*v1alpha1.KeycloakAPIClient, error) {
+	res := []*v1alpha1.KeycloakAPIClient{}
+	err := c.listPaged(fmt.Sprintf("realms/%s/clients", realmName), "clients", func(body []byte) (int, error) {
+		var clients []*v1alpha1.KeycloakAPIClient
+		err := json.Unmarshal(body, &clients)
+		res = append(res, clients...)
+		return len(clients), err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// The brief representation that keycloak lists by default leaves out the attributes
+func (c *Client) ListClientRoles(clientID, realmName string) ([]v1alpha1.RoleRepresentation, error) {
+	res := []v1alpha1.RoleRepresentation{}
+	err := c.listPaged(fmt.Sprintf("realms/%s/clients/%s/roles?briefRepresentation=false", realmName, clientID), "client roles", func(body []byte) (int, error) {
+		var roles []v1alpha1.RoleRepresentation
+		err := json.Unmarshal(body, &roles)
+		res = append(res, roles...)
+		return len(roles), err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *Client) ListClientRoleComposites(clientID, roleName, realmName string) ([]v1alpha1.RoleRepresentation, error) {
+	result, err := c.list(fmt.Sprintf("realms/%s/clients/%s/roles/%s/composites", realmName, clientID, roleName), "client role composites", func(body []byte) (T, error) {
+		var roles []v1alpha1.RoleRepresentation
+		err := json.Unmarshal(body, &roles)
+		return roles, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.([]v1alpha1.RoleRepresentation)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list client role composites response")
+	}
+
+	return res, nil
+}
+
+func (c *Client) ListClientScopeMappings(clientID, realmName string) (*MappingsRepresentation, error) {
+	result, err := c.list(fmt.Sprintf("realms/%s/clients/%s/scope-mappings", realmName, clientID), "client scope mappings", func(body []byte) (T, error) {
+		var mappings MappingsRepresentation
+		err := json.Unmarshal(body, &mappings)
+		return &mappings, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.(*MappingsRepresentation)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list client scope mappings response")
+	}
+
+	return res, nil
+}
+
+func (c *Client) ListRealmRoles(realmName string) ([]v1alpha1.RoleRepresentation, error) {
+	res := []v1alpha1.RoleRepresentation{}
+	err := c.listPaged(fmt.Sprintf("realms/%s/roles", realmName), "realm roles", func(body []byte) (int, error) {
+		var roles []v1alpha1.RoleRepresentation
+		err := json.Unmarshal(body, &roles)
+		res = append(res, roles...)
+		return len(roles), err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Locales of the realm that have message overrides
+func (c *Client) ListRealmLocalizationLocales(realmName string) ([]string, error) {
+	result, err := c.list(fmt.Sprintf("realms/%s/localization", realmName), "realm localization locales", func(body []byte) (T, error) {
+		var locales []string
+		err := json.Unmarshal(body, &locales)
+		return locales, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.([]string)
+	if !ok {
+		return nil, errors.Errorf("error decoding list realm localization locales response")
+	}
+
+	return res, nil
+}
+
+func (c *Client) ListRealmRoleComposites(roleName, realmName string) ([]v1alpha1.RoleRepresentation, error) {
+	result, err := c.list(fmt.Sprintf("realms/%s/roles/%s/composites", realmName, roleName), "realm role composites", func(body []byte) (T, error) {
+		var roles []v1alpha1.RoleRepresentation
+		err := json.Unmarshal(body, &roles)
+		return roles, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.([]v1alpha1.RoleRepresentation)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list realm role composites response")
+	}
+
+	return res, nil
+}
+
+func (c *Client) ListClientScopes(realmName string) ([]v1alpha1.KeycloakAPIClientScope, error) {
+	return c.listClientScopes(fmt.Sprintf("realms/%s/client-scopes", realmName), "client scopes")
+}
+
+func (c *Client) ListDefaultClientScopes(clientID, realmName string) ([]v1alpha1.KeycloakAPIClientScope, error) {
+	return c.listClientScopes(fmt.Sprintf("realms/%s/clients/%s/default-client-scopes", realmName, clientID), "default client scopes")
+}
+
+func (c *Client) ListOptionalClientScopes(clientID, realmName string) ([]v1alpha1.KeycloakAPIClientScope, error) {
+	return c.listClientScopes(fmt.Sprintf("realms/%s/clients/%s/optional-client-scopes", realmName, clientID), "optional client scopes")
+}
+
+func (c *Client) ListRealmDefaultClientScopes(optional bool, realmName string) ([]v1alpha1.KeycloakAPIClientScope, error) {
+	return c.listClientScopes(fmt.Sprintf("realms/%s/%s", realmName, realmDefaultClientScopesPath(optional)), "realm default client scopes")
+}
+
+func realmDefaultClientScopesPath(optional bool) string {
+	if optional {
+		return "default-optional-client-scopes"
+	}
+	return "default-default-client-scopes"
+}
+
+func (c *Client) listClientScopes(resourcePath, resourceName string) ([]v1alpha1.KeycloakAPIClientScope, error) {
+	result, err := c.list(resourcePath, resourceName, func(body []byte) (T, error) {
+		var scopes []v1alpha1.KeycloakAPIClientScope
+		err := json.Unmarshal(body, &scopes)
+		return scopes, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := result.([]v1alpha1.KeycloakAPIClientScope)
+
+	if !ok {
+		return nil, errors.Errorf("error decoding list %s response", resourceName)
+	}
+
+	return res, nil
+}
+
+func (c *Client) ListUsers(realmName string) ([]*v1alpha1.KeycloakAPIUser, error) {
+	res := []*v1alpha1.KeycloakAPIUser{}
+	err := c.listPaged(fmt.Sprintf("realms/%s/users", realmName), "users", func(body []byte) (int, error) {
+		var users []*v1alpha1.KeycloakAPIUser
+		err := json.Unmarshal(body, &users)
+		res = append(res, users...)
+		return len(users), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *Client) ListIdentityProviders(realmName string) ([]*v1alpha1.KeycloakIdentityProvider, error) {
+	result, err := c.list(fmt.Sprintf("realms/%s/identity-provider/instances", realmName), "identity providers", func(body []byte) (T, error) {
+		var providers []*v1alpha1.KeycloakIdentityProvider
+		err := json.Unmarshal(body, &providers)
+		return providers, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]*v1alpha1.KeycloakIdentityProvider), err
+}
+
+func (c *Client) ListIdentityProviderMappers(alias, realmName string) ([]v1alpha1.KeycloakIdentityProviderMapper, error) {
+	result, err := c.list(fmt.Sprintf("realms/%s/identity-provider/instances/%s/mappers", realmName, alias), "identity provider mappers", func(body []byte) (T, error) {
+		var mappers []v1alpha1.KeycloakIdentityProviderMapper
+		err := json.Unmarshal(body, &mappers)
+		return mappers, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]v1alpha1.KeycloakIdentityProviderMapper), err
+}
+
+func (c *Client) ListUserClientRoles(realmName, clientID, userID string) ([]*v1alpha1.KeycloakUserRole, error) {
+	objects, err := c.list("realms/"+realmName+"/users/"+userID+"/role-mappings/clients/"+clientID, "userClientRoles", func(body []byte) (t T, e error) {
+		var userClientRoles []*v1alpha1.KeycloakUserRole
+		err := json.Unmarshal(body, &userClientRoles)
+		return userClientRoles, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if objects == nil {
+		return nil, nil
+	}
+	return objects.([]*v1alpha1.KeycloakUserRole), err
+}
+
+func (c *Client) ListAvailableUserClientRoles(realmName, clientID, userID string) ([]*v1alpha1.KeycloakUserRole, error) {
+	objects, err := c.list("realms/"+realmName+"/users/"+userID+"/role-mappings/clients/"+clientID+"/available", "userClientRoles", func(body []byte) (t T, e error) {
+		var userClientRoles []*v1alpha1.KeycloakUserRole
+		err := json.Unmarshal(body, &userClientRoles)
+		return userClientRoles, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if objects == nil {
+		return nil, nil
+	}
+	return objects.([]*v1alpha1.KeycloakUserRole), err
+}
+
+func (c *Client) ListUserRealmRoles(realmName, userID string) ([]*v1alpha1.KeycloakUserRole, error) {
+	objects, err := c.list("realms/"+realmName+"/users/"+userID+"/role-mappings/realm", "userRealmRoles", func(body []byte) (t T, e error) {
+		var userRealmRoles []*v1alpha1.KeycloakUserRole
+		err := json.Unmarshal(body, &userRealmRoles)
+		return userRealmRoles, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if objects == nil {
+		return nil, nil
+	}
+	return objects.([]*v1alpha1.KeycloakUserRole), err
+}
+
+func (c *Client) ListAvailableUserRealmRoles(realmName, userID string) ([]*v1alpha1.KeycloakUserRole, error) {
+	objects, err := c.list("realms/"+realmName+"/users/"+userID+"/role-mappings/realm/available", "userClientRoles", func(body []byte) (t T, e error) {
+		var userRealmRoles []*v1alpha1.KeycloakUserRole
+		err := json.Unmarshal(body, &userRealmRoles)
+		return userRealmRoles, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if objects == nil {
+		return nil, nil
+	}
+	return objects.([]*v1alpha1.KeycloakUserRole), err
+}
+
+// The full representation includes the sub groups along with the attributes
+// and role mappings of every group. Only the top level groups are paged
+func (c *Client) ListGroups(realmName string) ([]*v1alpha1.KeycloakAPIGroup, error) {
+	var res []*v1alpha1.KeycloakAPIGroup
+	err := c.listPaged(fmt.Sprintf("realms/%s/groups?briefRepresentation=false", realmName), "groups", func(body []byte) (int, error) {
+		var groups []*v1alpha1.KeycloakAPIGroup
+		err := json.Unmarshal(body, &groups)
+		res = append(res, groups...)
+		return len(groups), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *Client) ListDefaultGroups(realmName string) ([]*v1alpha1.KeycloakUserGroup, error) {
+	objects, err := c.list(fmt.Sprintf("realms/%s/default-groups", realmName), "default groups", func(body []byte) (t T, e error) {
+		var groups []*v1alpha1.KeycloakUserGroup
+		err := json.Unmarshal(body, &groups)
+		return groups, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if objects == nil {
+		return nil, nil
+	}
+	return objects.([]*v1alpha1.KeycloakUserGroup), err
+}
+
+func (c *Client) ListUserGroups(realmName, userID string) ([]*v1alpha1.KeycloakUserGroup, error) {
+	objects, err := c.list("realms/"+realmName+"/users/"+userID+"/groups", "userGroups", func(body []byte) (t T, e error) {
+		var userGroups []*v1alpha1.KeycloakUserGroup
+		err := json.Unmarshal(body, &userGroups)
+		return userGroups, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if objects == nil {
+		return nil, nil
+	}
+	return objects.([]*v1alpha1.KeycloakUserGroup), err
+}
+
+func (c *Client) ListAuthenticationExecutionsForFlow(flowAlias, realmName string) ([]*v1alpha1.AuthenticationExecutionInfo, error) {
+	result, err := c.list(fmt.Sprintf("realms/%s/authentication/flows/%s/executions", realmName, flowAlias), "AuthenticationExecution", func(body []byte) (T, error) {
+		var authenticationExecutions []*v1alpha1.AuthenticationExecutionInfo
+		err := json.Unmarshal(body, &authenticationExecutions)
+		return authenticationExecutions, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]*v1alpha1.AuthenticationExecutionInfo), err
+}
+
+// Only top level flows are listed
+func (c *Client) ListAuthenticationFlows(realmName string) ([]*v1alpha1.KeycloakAPIAuthenticationFlow, error) {
+	result, err := c.list(fmt.Sprintf("realms/%s/authentication/flows", realmName), "authentication flows", func(body []byte) (T, error) {
+		var flows []*v1alpha1.KeycloakAPIAuthenticationFlow
+		err := json.Unmarshal(body, &flows)
+		return flows, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]*v1alpha1.KeycloakAPIAuthenticationFlow), err
+}
+
+// The version keycloak reports, which is the one of the product for builds of RHSSO,
+// along with its themes. Read once by each client
+func (c *Client) GetServerInfo() (*KeycloakServerInfo, error) {
+	if c.serverInfo != nil {
+		return c.serverInfo, nil
+	}
+
+	result, err := c.get("serverinfo", "server info", func(body []byte) (T, error) {
+		serverInfo := struct {
+			SystemInfo struct {
+				Version string `json:"version"`
+			} `json:"systemInfo"`
+			ProfileInfo struct {
+				Name string `json:"name"`
+			} `json:"profileInfo"`
+			Themes map[string][]struct {
+				Name string `json:"name"`
+			} `json:"themes"`
+		}{}
+		err := json.Unmarshal(body, &serverInfo)
+		if err != nil {
+			return nil, err
+		}
+
+		themes := make(map[string][]string)
+		for themeType, list := range serverInfo.Themes {
+			for _, theme := range list {
+				themes[themeType] = append(themes[themeType], theme.Name)
+			}
+		}
+		return &KeycloakServerInfo{
+			Version: serverInfo.SystemInfo.Version,
+			Profile: serverInfo.ProfileInfo.Name,
+			Themes:  themes,
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	c.serverInfo = result.(*KeycloakServerInfo)
+	return c.serverInfo, err
+}
+
+// Names of the themes deployed to keycloak, keyed by their type, e.g. login or email
+func (c *Client) ListThemes() (map[string][]string, error) {
+	info, err := c.GetServerInfo()
+	if err != nil || info == nil {
+		return nil, err
+	}
+	return info.Themes, nil
+}
+
+func (c *Client) ListRequiredActions(realmName string) ([]v1alpha1.KeycloakAPIRequiredAction, error) {
+	result, err := c.list(fmt.Sprintf("realms/%s/authentication/required-actions", realmName), "required actions", func(body []byte) (T, error) {
+		var actions []v1alpha1.KeycloakAPIRequiredAction
+		err := json.Unmarshal(body, &actions)
+		return actions, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]v1alpha1.KeycloakAPIRequiredAction), err
+}
+
+// Components of the given type whose parent has the given ID
+func (c *Client) ListComponents(parentID, providerType, realmName string) ([]v1alpha1.KeycloakAPIComponent, error) {
+	query := url.Values{}
+	query.Set("parent", parentID)
+	query.Set("type", providerType)
+	result, err := c.list(fmt.Sprintf("realms/%s/components?%s", realmName, query.Encode()), "components", func(body []byte) (T, error) {
+		var components []v1alpha1.KeycloakAPIComponent
+		err := json.Unmarshal(body, &components)
+		return components, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]v1alpha1.KeycloakAPIComponent), err
+}
+
+// Trigger a sync of a user federation provider, all users when full is set and the
+// changed ones otherwise
+func (c *Client) SyncUserFederationProvider(providerID string, full bool, realmName string) (*v1alpha1.KeycloakAPISynchronizationResult, error) {
+	action := "triggerChangedUsersSync"
+	if full {
+		action = "triggerFullSync"
+	}
+
+	req, err := http.NewRequest(
+		"POST",
+		fmt.Sprintf("%s/admin/realms/%s/user-storage/%s/sync?action=%s", c.baseURL(), realmName, providerID, action),
+		nil,
+	)
+	if err != nil {
+		logrus.Errorf("error creating SYNC user federation provider request %+v", err)
+		return nil, errors.Wrapf(err, "error creating SYNC user federation provider request")
+	}
 
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	res, err := c.requester.Do(req)
 	if err != nil {
-		return nil, err
+		logrus.Errorf("error on request %+v", err)
+		return nil, errors.Wrapf(err, "error performing SYNC user federation provider request")
 	}
+	defer res.Body.Close()
 
-	res, ok := result.([]*v1alpha1.KeycloakAPIClient)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, errors.Errorf("failed to SYNC user federation provider: (%d) %s", res.StatusCode, res.Status)
+	}
 
-	if !ok {
-		return nil, errors.Errorf("error decoding list clients response")
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		logrus.Errorf("error reading response %+v", err)
+		return nil, errors.Wrapf(err, "error reading user federation provider SYNC response")
 	}
 
-	return res, nil
+	result := &v1alpha1.KeycloakAPISynchronizationResult{}
+	err = json.Unmarshal(body, result)
+	return result, err
 }
 
-func (c *Client) ListClientRoles(clientID, realmName string) ([]v1alpha1.RoleRepresentation, error) {
-	result, err := c.list(fmt.Sprintf("realms/%s/clients/%s/roles", realmName, clientID), "client roles", func(body []byte) (T, error) {
-		var roles []v1alpha1.RoleRepresentation
-		err := json.Unmarshal(body, &roles)
-		return roles, err
-	})
+// https://www.keycloak.org/docs-api/11.0/rest-api/index.html#_mappingsrepresentation
+type MappingsRepresentation struct {
+	ClientMappings map[string]ClientMappingsRepresentation `json:"clientMappings,omitempty"`
+	RealmMappings  []v1alpha1.RoleRepresentation            `json:"realmMappings,omitempty"`
+}
 
-	if err != nil {
-		return nil, err
-	}
+// https://www.keycloak.org/docs-api/11.0/rest-api/index.html#_clientmappingsrepresentation
+type ClientMappingsRepresentation struct {
+	ID       string                        `json:"id,omitempty"`
+	Client   string                        `json:"client,omitempty"`
+	Mappings []v1alpha1.RoleRepresentation `json:"mappings,omitempty"`
+}
 
-	res, ok := result.([]v1alpha1.RoleRepresentation)
+// https://www.keycloak.org/docs-api/11.0/rest-api/index.html#_partialimportrepresentation
+type PartialImportRepresentation struct {
+	// One of FAIL, SKIP or OVERWRITE
+	IfResourceExists string                        `json:"ifResourceExists"`
+	Roles            *v1alpha1.RolesRepresentation `json:"roles,omitempty"`
+	Users            []*v1alpha1.KeycloakAPIUser   `json:"users,omitempty"`
+}
 
-	if !ok {
-		return nil, errors.Errorf("error decoding list client roles response")
-	}
+// Outcome of a partial import, with one result per imported resource
+type PartialImportResponse struct {
+	Added       int                   `json:"added"`
+	Skipped     int                   `json:"skipped"`
+	Overwritten int                   `json:"overwritten"`
+	Results     []PartialImportResult `json:"results,omitempty"`
+}
 
-	return res, nil
+type PartialImportResult struct {
+	// One of ADDED, SKIPPED or OVERWRITTEN
+	Action string `json:"action"`
+	// e.g. USER, REALM_ROLE or CLIENT
+	ResourceType string `json:"resourceType"`
+	ResourceName string `json:"resourceName"`
+	ID           string `json:"id,omitempty"`
 }
 
-func (c *Client) ListUsers(realmName string) ([]*v1alpha1.KeycloakAPIUser, error) {
-	result, err := c.list(fmt.Sprintf("realms/%s/users", realmName), "users", func(body []byte) (T, error) {
-		var users []*v1alpha1.KeycloakAPIUser
-		err := json.Unmarshal(body, &users)
-		return users, err
-	})
+// Imports many resources into an existing realm in a single request
+func (c *Client) PartialImport(rep *PartialImportRepresentation, realmName string) error {
+	jsonValue, err := json.Marshal(rep)
 	if err != nil {
-		return nil, err
+		logrus.Errorf("error %+v marshalling object", err)
+		return errors.Wrapf(err, "error marshalling partial import")
 	}
-	return result.([]*v1alpha1.KeycloakAPIUser), err
+	return c.postPartialImport(jsonValue, realmName, nil)
 }
 
-func (c *Client) ListIdentityProviders(realmName string) ([]*v1alpha1.KeycloakIdentityProvider, error) {
-	result, err := c.list(fmt.Sprintf("realms/%s/identity-provider/instances", realmName), "identity providers", func(body []byte) (T, error) {
-		var providers []*v1alpha1.KeycloakIdentityProvider
-		err := json.Unmarshal(body, &providers)
-		return providers, err
+// Imports users along with their roles and groups in a single request. Users that
+// already exist are skipped. Keycloak imports all of them or none
+func (c *Client) ImportUsers(users []*v1alpha1.KeycloakAPIUser, realmName string) (*PartialImportResponse, error) {
+	jsonValue, err := json.Marshal(&PartialImportRepresentation{
+		IfResourceExists: "SKIP",
+		Users:            users,
 	})
+	if err != nil {
+		logrus.Errorf("error %+v marshalling object", err)
+		return nil, errors.Wrapf(err, "error marshalling user import")
+	}
+
+	response := &PartialImportResponse{}
+	err = c.postPartialImport(jsonValue, realmName, response)
 	if err != nil {
 		return nil, err
 	}
-	return result.([]*v1alpha1.KeycloakIdentityProvider), err
+	return response, nil
 }
 
-func (c *Client) ListUserClientRoles(realmName, clientID, userID string) ([]*v1alpha1.KeycloakUserRole, error) {
-	objects, err := c.list("realms/"+realmName+"/users/"+userID+"/role-mappings/clients/"+clientID, "userClientRoles", func(body []byte) (t T, e error) {
-		var userClientRoles []*v1alpha1.KeycloakUserRole
-		err := json.Unmarshal(body, &userClientRoles)
-		return userClientRoles, err
-	})
+// Imports the resources of a realm JSON, e.g. an export, into an existing realm.
+// Keycloak ignores the parts of the JSON a partial import doesn't cover
+func (c *Client) ImportRealm(realmJSON []byte, ifResourceExists string, realmName string) error {
+	var rep map[string]interface{}
+	err := json.Unmarshal(realmJSON, &rep)
 	if err != nil {
-		return nil, err
+		return errors.Wrapf(err, "error parsing realm JSON")
 	}
-	if objects == nil {
-		return nil, nil
+	rep["ifResourceExists"] = ifResourceExists
+
+	jsonValue, err := json.Marshal(rep)
+	if err != nil {
+		logrus.Errorf("error %+v marshalling object", err)
+		return errors.Wrapf(err, "error marshalling realm import")
 	}
-	return objects.([]*v1alpha1.KeycloakUserRole), err
+	return c.postPartialImport(jsonValue, realmName, nil)
 }
 
-func (c *Client) ListAvailableUserClientRoles(realmName, clientID, userID string) ([]*v1alpha1.KeycloakUserRole, error) {
-	objects, err := c.list("realms/"+realmName+"/users/"+userID+"/role-mappings/clients/"+clientID+"/available", "userClientRoles", func(body []byte) (t T, e error) {
-		var userClientRoles []*v1alpha1.KeycloakUserRole
-		err := json.Unmarshal(body, &userClientRoles)
-		return userClientRoles, err
-	})
+// The results of the import are only read when a response is given
+func (c *Client) postPartialImport(jsonValue []byte, realmName string, response *PartialImportResponse) error {
+	req, err := http.NewRequest(
+		"POST",
+		fmt.Sprintf("%s/admin/realms/%s/partialImport", c.baseURL(), realmName),
+		bytes.NewBuffer(jsonValue),
+	)
 	if err != nil {
-		return nil, err
+		logrus.Errorf("error creating POST partial import request %+v", err)
+		return errors.Wrapf(err, "error creating POST partial import request")
 	}
-	if objects == nil {
-		return nil, nil
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	res, err := c.requester.Do(req)
+	if err != nil {
+		logrus.Errorf("error on request %+v", err)
+		return errors.Wrapf(err, "error performing POST partial import request")
 	}
-	return objects.([]*v1alpha1.KeycloakUserRole), err
-}
+	defer res.Body.Close()
 
-func (c *Client) ListUserRealmRoles(realmName, userID string) ([]*v1alpha1.KeycloakUserRole, error) {
-	objects, err := c.list("realms/"+realmName+"/users/"+userID+"/role-mappings/realm", "userRealmRoles", func(body []byte) (t T, e error) {
-		var userRealmRoles []*v1alpha1.KeycloakUserRole
-		err := json.Unmarshal(body, &userRealmRoles)
-		return userRealmRoles, err
-	})
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return errors.Errorf("failed to import: (%d) %s", res.StatusCode, res.Status)
+	}
+	if response == nil {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return nil, err
+		return errors.Wrapf(err, "error reading partial import response")
 	}
-	if objects == nil {
-		return nil, nil
+	err = json.Unmarshal(body, response)
+	if err != nil {
+		return errors.Wrapf(err, "error parsing partial import response")
 	}
-	return objects.([]*v1alpha1.KeycloakUserRole), err
+	return nil
 }
 
-func (c *Client) ListAvailableUserRealmRoles(realmName, userID string) ([]*v1alpha1.KeycloakUserRole, error) {
-	objects, err := c.list("realms/"+realmName+"/users/"+userID+"/role-mappings/realm/available", "userClientRoles", func(body []byte) (t T, e error) {
-		var userRealmRoles []*v1alpha1.KeycloakUserRole
-		err := json.Unmarshal(body, &userRealmRoles)
-		return userRealmRoles, err
-	})
+// Exports the representation of a realm. Clients and groups along with roles are
+// only part of it when asked for, secrets are masked by keycloak either way
+func (c *Client) ExportRealm(realmName string, exportClients, exportGroupsAndRoles bool) ([]byte, error) {
+	req, err := http.NewRequest(
+		"POST",
+		fmt.Sprintf("%s/admin/realms/%s/partial-export?exportClients=%t&exportGroupsAndRoles=%t", c.baseURL(), realmName, exportClients, exportGroupsAndRoles),
+		nil,
+	)
 	if err != nil {
-		return nil, err
+		logrus.Errorf("error creating POST partial export request %+v", err)
+		return nil, errors.Wrapf(err, "error creating POST partial export request")
 	}
-	if objects == nil {
-		return nil, nil
+
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	res, err := c.requester.Do(req)
+	if err != nil {
+		logrus.Errorf("error on request %+v", err)
+		return nil, errors.Wrapf(err, "error performing POST partial export request")
 	}
-	return objects.([]*v1alpha1.KeycloakUserRole), err
-}
+	defer res.Body.Close()
 
-func (c *Client) ListAuthenticationExecutionsForFlow(flowAlias, realmName string) ([]*v1alpha1.AuthenticationExecutionInfo, error) {
-	result, err := c.list(fmt.Sprintf("realms/%s/authentication/flows/%s/executions", realmName, flowAlias), "AuthenticationExecution", func(body []byte) (T, error) {
-		var authenticationExecutions []*v1alpha1.AuthenticationExecutionInfo
-		err := json.Unmarshal(body, &authenticationExecutions)
-		return authenticationExecutions, err
-	})
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, errors.Errorf("failed to export: (%d) %s", res.StatusCode, res.Status)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return nil, err
+		logrus.Errorf("error reading response %+v", err)
+		return nil, errors.Wrapf(err, "error reading partial export response")
 	}
-	return result.([]*v1alpha1.AuthenticationExecutionInfo), err
+	return body, nil
 }
 
 func (c *Client) Ping() error {
-	u := c.URL + "/auth/"
+	u := c.baseURL() + "/"
 	req, err := http.NewRequest("GET", u, nil)
 	if err != nil {
 		logrus.Errorf("error creating ping request %+v", err)
 		return errors.Wrap(err, "error creating ping request")
 	}
 
-	res, err := c.requester.Do(req)
+	// Don't retry, so that an unavailable Keycloak fails the reconcile fast and
+	// gets requeued instead
+	res, err := withoutRetries(c.requester).Do(req)
 	if err != nil {
 		logrus.Errorf("error on request %+v", err)
-		return errors.Wrapf(err, "error performing ping request")
+		return NewTransientError(errors.Wrapf(err, "error performing ping request"))
 	}
+	defer res.Body.Close()
 
 	logrus.Debugf("response status: %v, %v", res.StatusCode, res.Status)
 	if res.StatusCode != 200 {
-		return errors.Errorf("failed to ping, response status code: %v", res.StatusCode)
+		err = errors.Errorf("failed to ping, response status code: %v", res.StatusCode)
+		if isTransientStatus(res.StatusCode) {
+			return NewTransientError(err)
+		}
+		return err
 	}
-	defer res.Body.Close()
 
 	return nil
 }
 
+// Token endpoint of the realm the client logs in to, relative to the URL of keycloak
+func (c *Client) tokenPath() string {
+	realm := c.tokenRealm
+	if realm == "" {
+		realm = "master"
+	}
+	path := fmt.Sprintf("realms/%s/protocol/openid-connect/token", realm)
+	if c.rootPath {
+		return path
+	}
+	return "auth/" + path
+}
+
 // login requests a new auth token from Keycloak
 func (c *Client) login(user, pass string) error {
 	form := url.Values{}
-	form.Add("username", user)
-	form.Add("password", pass)
-	form.Add("client_id", "admin-cli")
-	form.Add("grant_type", "password")
+	if c.clientCredentials {
+		form.Add("client_id", user)
+		form.Add("client_secret", pass)
+		form.Add("grant_type", "client_credentials")
+	} else {
+		form.Add("username", user)
+		form.Add("password", pass)
+		form.Add("client_id", "admin-cli")
+		form.Add("grant_type", "password")
+	}
 
 	req, err := http.NewRequest(
 		"POST",
-		fmt.Sprintf("%s/%s", c.URL, authURL),
+		fmt.Sprintf("%s/%s", c.URL, c.tokenPath()),
 		strings.NewReader(form.Encode()),
 	)
 	if err != nil {
@@ -732,9 +1969,18 @@ func (c *Client) login(user, pass string) error {
 	res, err := c.requester.Do(req)
 	if err != nil {
 		logrus.Errorf("error on request %+v", err)
-		return errors.Wrap(err, "error performing token request")
+		return NewTransientError(errors.Wrap(err, "error performing token request"))
 	}
 	defer res.Body.Close()
+	if isTransientStatus(res.StatusCode) {
+		return NewTransientError(errors.Errorf("failed to request token, response status code: %v", res.StatusCode))
+	}
+	// A token endpoint that isn't found may have moved, e.g. with keycloak changing
+	// its distribution, so where keycloak serves is detected again
+	if res.StatusCode == http.StatusNotFound {
+		forgetCachedRootPath(c.URL)
+	}
+
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		logrus.Errorf("error reading response %+v", err)
@@ -753,17 +1999,163 @@ func (c *Client) login(user, pass string) error {
 	}
 
 	c.token = tokenRes.AccessToken
+	c.tokenExpiresAt = time.Now().Add(time.Duration(tokenRes.ExpiresIn) * time.Second)
 
 	return nil
 }
 
-// defaultRequester returns a default client for requesting http endpoints
-func defaultRequester() Requester {
+// requesterFor returns a client for requesting the endpoints of the given Keycloak,
+// honouring its admin client timeout and retry settings
+func requesterFor(kc v1alpha1.Keycloak, caCertificates *x509.CertPool) Requester {
+	timeout := defaultAdminClientTimeout
+	if kc.Spec.AdminClientTimeoutSeconds > 0 {
+		timeout = time.Duration(kc.Spec.AdminClientTimeoutSeconds) * time.Second
+	}
+
 	transport := http.DefaultTransport.(*http.Transport).Clone()
-	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} // nolint
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: adminClientInsecureSkipVerify(kc), RootCAs: caCertificates} // nolint
+	transport.DialContext = (&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.Proxy = adminClientProxy(kc)
+
+	c := &http.Client{Transport: transport, Timeout: timeout}
+	if kc.Spec.AdminClientMaxRetries <= 0 {
+		return c
+	}
+	return &retryingRequester{
+		requester:  c,
+		maxRetries: kc.Spec.AdminClientMaxRetries,
+		backoff:    adminClientRetryBackoff,
+	}
+}
+
+func adminClientInsecureSkipVerify(kc v1alpha1.Keycloak) bool {
+	if kc.Spec.AdminClientInsecureSkipVerify != nil {
+		return *kc.Spec.AdminClientInsecureSkipVerify
+	}
+	return kc.Spec.AdminClientCACertificate == nil
+}
+
+// The configured CA certificates are trusted along with the system ones
+func adminClientCACertificates(kc v1alpha1.Keycloak, secretClient kubernetes.Interface) (*x509.CertPool, error) {
+	ref := kc.Spec.AdminClientCACertificate
+	if ref == nil {
+		return nil, nil
+	}
+
+	var certificates []byte
+	switch {
+	case ref.SecretKeyRef != nil:
+		secret, err := secretClient.CoreV1().Secrets(kc.Namespace).Get(context.TODO(), ref.SecretKeyRef.Name, v12.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the admin client CA certificate")
+		}
+		certificates = secret.Data[ref.SecretKeyRef.Key]
+	case ref.ConfigMapKeyRef != nil:
+		configMap, err := secretClient.CoreV1().ConfigMaps(kc.Namespace).Get(context.TODO(), ref.ConfigMapKeyRef.Name, v12.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the admin client CA certificate")
+		}
+		certificates = []byte(configMap.Data[ref.ConfigMapKeyRef.Key])
+	default:
+		return nil, errors.Errorf("adminClientCACertificate needs a configMapKeyRef or a secretKeyRef")
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(certificates) {
+		return nil, errors.Errorf("no PEM encoded certificate found in the admin client CA certificate")
+	}
+	return pool, nil
+}
+
+// The proxy of the CR takes precedence over the environment of the operator
+func adminClientProxy(kc v1alpha1.Keycloak) func(*http.Request) (*url.URL, error) {
+	proxy := kc.Spec.AdminClientProxy
+	if proxy == nil {
+		return http.ProxyFromEnvironment
+	}
+
+	proxyURL, err := url.Parse(proxy.URL)
+	return func(req *http.Request) (*url.URL, error) {
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid admin client proxy %v", proxy.URL)
+		}
+		if isNoProxyHost(req.URL.Hostname(), proxy.NoProxy) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}
+}
+
+func isNoProxyHost(host string, noProxy []string) bool {
+	host = strings.ToLower(host)
+	for _, entry := range noProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "*":
+			return true
+		case strings.HasPrefix(entry, "."):
+			if host == entry[1:] || strings.HasSuffix(host, entry) {
+				return true
+			}
+		case host == entry:
+			return true
+		}
+	}
+	return false
+}
+
+// retryingRequester retries requests that failed with a 5xx status, doubling
+// the backoff after every attempt. Only idempotent requests are retried, a POST
+// may have created the resource before keycloak failed
+type retryingRequester struct {
+	requester  Requester
+	maxRetries int
+	backoff    time.Duration
+}
 
-	c := &http.Client{Transport: transport, Timeout: time.Second * 10}
-	return c
+func withoutRetries(requester Requester) Requester {
+	if retrying, ok := requester.(*retryingRequester); ok {
+		return retrying.requester
+	}
+	return requester
+}
+
+func (r *retryingRequester) Do(req *http.Request) (*http.Response, error) {
+	backoff := r.backoff
+	for attempt := 0; ; attempt++ {
+		res, err := r.requester.Do(req)
+		if err != nil || res.StatusCode < 500 || attempt >= r.maxRetries || !isIdempotent(req.Method) {
+			return res, err
+		}
+
+		// The body of the request has been consumed and must be restored for the next attempt
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return res, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return res, nil
+			}
+			req.Body = body
+		}
+		res.Body.Close()
+
+		logrus.Warnf("request %v %v failed with status %v, retrying in %v", req.Method, req.URL, res.StatusCode, backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
 }
 
 //go:generate moq -out keycloakClient_moq.go . KeycloakInterface
@@ -775,14 +2167,24 @@ type KeycloakInterface interface {
 
 	CreateRealm(realm *v1alpha1.KeycloakRealm) (string, error)
 	GetRealm(realmName string) (*v1alpha1.KeycloakRealm, error)
-	UpdateRealm(specRealm *v1alpha1.KeycloakRealm) error
+	UpdateRealm(realm *v1alpha1.KeycloakAPIRealm) error
+	GetRealmEventsConfig(realmName string) (*v1alpha1.KeycloakAPIRealmEventsConfig, error)
+	UpdateRealmEventsConfig(config *v1alpha1.KeycloakAPIRealmEventsConfig, realmName string) error
+	ListRealmLocalizationLocales(realmName string) ([]string, error)
+	GetRealmLocalizationTexts(locale, realmName string) (map[string]string, error)
+	UpdateRealmLocalizationText(locale, key, text, realmName string) error
+	DeleteRealmLocalizationText(locale, key, realmName string) error
 	DeleteRealm(realmName string) error
 	ListRealms() ([]*v1alpha1.KeycloakRealm, error)
 
 	CreateClient(client *v1alpha1.KeycloakAPIClient, realmName string) (string, error)
 	GetClient(clientID, realmName string) (*v1alpha1.KeycloakAPIClient, error)
 	GetClientSecret(clientID, realmName string) (string, error)
+	RegenerateClientSecret(clientID, realmName string) (string, error)
+	RegenerateRegistrationAccessToken(clientID, realmName string) (string, error)
+	UpdateClientSecret(clientID, secret, realmName string) error
 	GetClientInstall(clientID, realmName string) ([]byte, error)
+	GetServiceAccountUser(clientID, realmName string) (*v1alpha1.KeycloakAPIUser, error)
 	UpdateClient(specClient *v1alpha1.KeycloakAPIClient, realmName string) error
 	DeleteClient(clientID, realmName string) error
 	ListClients(realmName string) ([]*v1alpha1.KeycloakAPIClient, error)
@@ -790,6 +2192,51 @@ type KeycloakInterface interface {
 	CreateClientRole(clientID string, role *v1alpha1.RoleRepresentation, realmName string) (string, error)
 	UpdateClientRole(clientID string, role, oldRole *v1alpha1.RoleRepresentation, realmName string) error
 	DeleteClientRole(clientID, role, realmName string) error
+	GetClientRole(clientID, roleName, realmName string) (*v1alpha1.RoleRepresentation, error)
+	ListClientRoleComposites(clientID, roleName, realmName string) ([]v1alpha1.RoleRepresentation, error)
+	CreateClientRoleComposites(clientID, roleName string, composites []v1alpha1.RoleRepresentation, realmName string) error
+	DeleteClientRoleComposites(clientID, roleName string, composites []v1alpha1.RoleRepresentation, realmName string) error
+
+	ListRealmRoles(realmName string) ([]v1alpha1.RoleRepresentation, error)
+	CreateRealmRole(role *v1alpha1.RoleRepresentation, realmName string) (string, error)
+	UpdateRealmRole(role, oldRole *v1alpha1.RoleRepresentation, realmName string) error
+	DeleteRealmRole(role, realmName string) error
+	ListRealmRoleComposites(roleName, realmName string) ([]v1alpha1.RoleRepresentation, error)
+	CreateRealmRoleComposites(roleName string, composites []v1alpha1.RoleRepresentation, realmName string) error
+	DeleteRealmRoleComposites(roleName string, composites []v1alpha1.RoleRepresentation, realmName string) error
+
+	PartialImport(rep *PartialImportRepresentation, realmName string) error
+	ImportRealm(realmJSON []byte, ifResourceExists string, realmName string) error
+	ImportUsers(users []*v1alpha1.KeycloakAPIUser, realmName string) (*PartialImportResponse, error)
+	ExportRealm(realmName string, exportClients, exportGroupsAndRoles bool) ([]byte, error)
+	CreateClientProtocolMapper(clientID string, mapper *v1alpha1.KeycloakProtocolMapper, realmName string) (string, error)
+	UpdateClientProtocolMapper(clientID string, mapper, oldMapper *v1alpha1.KeycloakProtocolMapper, realmName string) error
+	DeleteClientProtocolMapper(clientID, mapperID, realmName string) error
+
+	GetClientAuthorizationSettings(clientID, realmName string) (*v1alpha1.ResourceServerRepresentation, error)
+	ImportClientAuthorizationSettings(clientID string, settings *v1alpha1.ResourceServerRepresentation, realmName string) error
+	DeleteClientAuthorizationResource(clientID, resourceID, realmName string) error
+	DeleteClientAuthorizationScope(clientID, scopeID, realmName string) error
+	DeleteClientAuthorizationPolicy(clientID, policyID, realmName string) error
+
+	ListDefaultClientScopes(clientID, realmName string) ([]v1alpha1.KeycloakAPIClientScope, error)
+	AddDefaultClientScope(clientID, scopeID, realmName string) error
+	RemoveDefaultClientScope(clientID, scopeID, realmName string) error
+	ListOptionalClientScopes(clientID, realmName string) ([]v1alpha1.KeycloakAPIClientScope, error)
+	AddOptionalClientScope(clientID, scopeID, realmName string) error
+	RemoveOptionalClientScope(clientID, scopeID, realmName string) error
+
+	GetRealmRole(roleName, realmName string) (*v1alpha1.RoleRepresentation, error)
+	ListClientScopes(realmName string) ([]v1alpha1.KeycloakAPIClientScope, error)
+	ListRealmDefaultClientScopes(optional bool, realmName string) ([]v1alpha1.KeycloakAPIClientScope, error)
+	AddRealmDefaultClientScope(scopeID string, optional bool, realmName string) error
+	RemoveRealmDefaultClientScope(scopeID string, optional bool, realmName string) error
+	CreateClientScope(scope *v1alpha1.KeycloakAPIClientScope, realmName string) (string, error)
+	UpdateClientScope(scope *v1alpha1.KeycloakAPIClientScope, realmName string) error
+	DeleteClientScope(scopeID, realmName string) error
+	CreateClientScopeProtocolMapper(scopeID string, mapper *v1alpha1.KeycloakProtocolMapper, realmName string) (string, error)
+	UpdateClientScopeProtocolMapper(scopeID string, mapper, oldMapper *v1alpha1.KeycloakProtocolMapper, realmName string) error
+	DeleteClientScopeProtocolMapper(scopeID, mapperID, realmName string) error
 
 	CreateUser(user *v1alpha1.KeycloakAPIUser, realmName string) (string, error)
 	CreateFederatedIdentity(fid v1alpha1.FederatedIdentity, userID string, realmName string) (string, error)
@@ -800,6 +2247,8 @@ type KeycloakInterface interface {
 	FindUserByUsername(name, realm string) (*v1alpha1.KeycloakAPIUser, error)
 	GetUser(userID, realmName string) (*v1alpha1.KeycloakAPIUser, error)
 	UpdateUser(specUser *v1alpha1.KeycloakAPIUser, realmName string) error
+	ResetUserPassword(userID string, credential *v1alpha1.KeycloakCredential, realmName string) error
+	SendUserActionsEmail(userID string, email *v1alpha1.KeycloakUserActionsEmail, realmName string) error
 	DeleteUser(userID, realmName string) error
 	ListUsers(realmName string) ([]*v1alpha1.KeycloakAPIUser, error)
 
@@ -808,6 +2257,10 @@ type KeycloakInterface interface {
 	UpdateIdentityProvider(specIdentityProvider *v1alpha1.KeycloakIdentityProvider, realmName string) error
 	DeleteIdentityProvider(alias, realmName string) error
 	ListIdentityProviders(realmName string) ([]*v1alpha1.KeycloakIdentityProvider, error)
+	ListIdentityProviderMappers(alias, realmName string) ([]v1alpha1.KeycloakIdentityProviderMapper, error)
+	CreateIdentityProviderMapper(mapper *v1alpha1.KeycloakIdentityProviderMapper, realmName string) (string, error)
+	UpdateIdentityProviderMapper(mapper *v1alpha1.KeycloakIdentityProviderMapper, realmName string) error
+	DeleteIdentityProviderMapper(alias, mapperID, realmName string) error
 
 	CreateUserClientRole(role *v1alpha1.KeycloakUserRole, realmName, clientID, userID string) (string, error)
 	ListUserClientRoles(realmName, clientID, userID string) ([]*v1alpha1.KeycloakUserRole, error)
@@ -819,7 +2272,53 @@ type KeycloakInterface interface {
 	ListAvailableUserRealmRoles(realmName, userID string) ([]*v1alpha1.KeycloakUserRole, error)
 	DeleteUserRealmRole(role *v1alpha1.KeycloakUserRole, realmName, userID string) error
 
+	GetGroupByPath(path, realmName string) (*v1alpha1.KeycloakUserGroup, error)
+	ListUserGroups(realmName, userID string) ([]*v1alpha1.KeycloakUserGroup, error)
+	AddUserToGroup(realmName, userID, groupID string) error
+	RemoveUserFromGroup(realmName, userID, groupID string) error
+	ListGroups(realmName string) ([]*v1alpha1.KeycloakAPIGroup, error)
+	CreateGroup(group *v1alpha1.KeycloakAPIGroup, realmName string) (string, error)
+	CreateChildGroup(group *v1alpha1.KeycloakAPIGroup, parentID, realmName string) (string, error)
+	UpdateGroup(group *v1alpha1.KeycloakAPIGroup, realmName string) error
+	DeleteGroup(groupID, realmName string) error
+	CreateGroupRealmRoleMappings(groupID string, roles []v1alpha1.RoleRepresentation, realmName string) error
+	DeleteGroupRealmRoleMappings(groupID string, roles []v1alpha1.RoleRepresentation, realmName string) error
+	CreateGroupClientRoleMappings(groupID, clientID string, roles []v1alpha1.RoleRepresentation, realmName string) error
+	DeleteGroupClientRoleMappings(groupID, clientID string, roles []v1alpha1.RoleRepresentation, realmName string) error
+	ListClientScopeMappings(clientID, realmName string) (*MappingsRepresentation, error)
+	CreateClientRealmScopeMappings(clientID string, roles []v1alpha1.RoleRepresentation, realmName string) error
+	DeleteClientRealmScopeMappings(clientID string, roles []v1alpha1.RoleRepresentation, realmName string) error
+	CreateClientClientScopeMappings(clientID, roleClientID string, roles []v1alpha1.RoleRepresentation, realmName string) error
+	DeleteClientClientScopeMappings(clientID, roleClientID string, roles []v1alpha1.RoleRepresentation, realmName string) error
+	ListDefaultGroups(realmName string) ([]*v1alpha1.KeycloakUserGroup, error)
+	AddDefaultGroup(groupID, realmName string) error
+	RemoveDefaultGroup(groupID, realmName string) error
+
 	ListAuthenticationExecutionsForFlow(flowAlias, realmName string) ([]*v1alpha1.AuthenticationExecutionInfo, error)
+	UpdateAuthenticationExecution(flowAlias string, execution *v1alpha1.AuthenticationExecutionInfo, realmName string) error
+	CreateAuthenticationExecution(flowAlias, provider, realmName string) (string, error)
+	CreateAuthenticationSubFlow(flowAlias string, subFlow *v1alpha1.KeycloakAPIAuthenticationFlow, realmName string) (string, error)
+
+	ListAuthenticationFlows(realmName string) ([]*v1alpha1.KeycloakAPIAuthenticationFlow, error)
+	ListRequiredActions(realmName string) ([]v1alpha1.KeycloakAPIRequiredAction, error)
+	ListThemes() (map[string][]string, error)
+	GetServerInfo() (*KeycloakServerInfo, error)
+	GetRequiredAction(alias, realmName string) (*v1alpha1.KeycloakAPIRequiredAction, error)
+	RegisterRequiredAction(providerID, name, realmName string) error
+	UpdateRequiredAction(action *v1alpha1.KeycloakAPIRequiredAction, realmName string) error
+	ListClientProfiles(realmName string) ([]v1alpha1.KeycloakAPIClientProfile, error)
+	ListClientPolicies(realmName string) ([]v1alpha1.KeycloakAPIClientPolicy, error)
+	UpdateClientProfiles(profiles []v1alpha1.KeycloakAPIClientProfile, realmName string) error
+	UpdateClientPolicies(policies []v1alpha1.KeycloakAPIClientPolicy, realmName string) error
+	CreateAuthenticationFlow(flow *v1alpha1.KeycloakAPIAuthenticationFlow, realmName string) (string, error)
+	DeleteAuthenticationFlow(flowID, realmName string) error
+	UpdateRealmFlowBindings(bindings map[string]string, realmName string) error
+
+	ListComponents(parentID, providerType, realmName string) ([]v1alpha1.KeycloakAPIComponent, error)
+	CreateComponent(component *v1alpha1.KeycloakAPIComponent, realmName string) (string, error)
+	UpdateComponent(component *v1alpha1.KeycloakAPIComponent, realmName string) error
+	DeleteComponent(componentID, realmName string) error
+	SyncUserFederationProvider(providerID string, full bool, realmName string) (*v1alpha1.KeycloakAPISynchronizationResult, error)
 
 	CreateAuthenticatorConfig(authenticatorConfig *v1alpha1.AuthenticatorConfig, realmName, executionID string) (string, error)
 	GetAuthenticatorConfig(configID, realmName string) (*v1alpha1.AuthenticatorConfig, error)
@@ -832,6 +2331,7 @@ type KeycloakInterface interface {
 //KeycloakClientFactory interface
 type KeycloakClientFactory interface {
 	AuthenticatedClient(kc v1alpha1.Keycloak) (KeycloakInterface, error)
+	AuthenticatedRealmClient(kc v1alpha1.Keycloak, realm v1alpha1.KeycloakRealm) (KeycloakInterface, error)
 }
 
 type LocalConfigKeycloakFactory struct {
@@ -849,27 +2349,109 @@ func (i *LocalConfigKeycloakFactory) AuthenticatedClient(kc v1alpha1.Keycloak) (
 		return nil, err
 	}
 
-	var credentialSecret, endpoint string
+	credentialSecret := kc.Status.CredentialSecret
 	if kc.Spec.External.Enabled {
-		credentialSecret = "credential-" + kc.Name
-		endpoint = kc.Spec.External.URL
-	} else {
-		credentialSecret = kc.Status.CredentialSecret
-		endpoint = kc.Status.InternalURL
+		credentialSecret = model.KeycloakAdminSecretSelector(&kc).Name
+	}
+	client, requester, err := newAdminClient(kc, secretClient)
+	if err != nil {
+		return nil, err
+	}
+
+	if kc.Spec.AdminClientCredentialsRef != nil {
+		err = client.authenticateWithClientCredentials(requester, secretClient, kc.Namespace, kc.Spec.AdminClientCredentialsRef)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
 	}
 
 	adminCreds, err := secretClient.CoreV1().Secrets(kc.Namespace).Get(context.TODO(), credentialSecret, v12.GetOptions{})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get the admin credentials")
 	}
-	user := string(adminCreds.Data[model.AdminUsernameProperty])
-	pass := string(adminCreds.Data[model.AdminPasswordProperty])
-	client := &Client{
-		URL:       endpoint,
-		requester: defaultRequester(),
+	user := string(adminCreds.Data[model.AdminUsernameKey(&kc)])
+	pass := string(adminCreds.Data[model.AdminPasswordKey(&kc)])
+	requester.user = user
+	requester.pass = pass
+	if err := client.authenticate(user, pass); err != nil {
+		// Midway through a rotation of the admin password, keycloak may already have
+		// the new one while the Secret doesn't have it as the current one yet
+		pending := string(adminCreds.Data[model.AdminPasswordPendingProperty])
+		if pending == "" || IsTransientError(err) {
+			return nil, err
+		}
+		if err := client.authenticate(user, pending); err != nil {
+			return nil, err
+		}
+		requester.pass = pending
+	}
+	return client, nil
+}
+
+// AuthenticatedRealmClient returns a client authenticated with the admin client of the
+// realm itself when the realm has one, so that it can't reach any other realm. Otherwise
+// it is the same as AuthenticatedClient
+func (i *LocalConfigKeycloakFactory) AuthenticatedRealmClient(kc v1alpha1.Keycloak, realm v1alpha1.KeycloakRealm) (KeycloakInterface, error) {
+	if realm.Spec.AdminClientCredentialsRef == nil {
+		return i.AuthenticatedClient(kc)
+	}
+
+	config, err := config2.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	secretClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	client, requester, err := newAdminClient(kc, secretClient)
+	if err != nil {
+		return nil, err
 	}
-	if err := client.login(user, pass); err != nil {
+	client.tokenRealm = realm.Spec.Realm.Realm
+
+	err = client.authenticateWithClientCredentials(requester, secretClient, realm.Namespace, realm.Spec.AdminClientCredentialsRef)
+	if err != nil {
 		return nil, err
 	}
 	return client, nil
 }
+
+// The client isn't authenticated yet, the requester logs in again with the
+// credentials it is given when keycloak refuses the token
+func newAdminClient(kc v1alpha1.Keycloak, secretClient kubernetes.Interface) (*Client, *reauthenticatingRequester, error) {
+	endpoint := kc.Status.InternalURL
+	if kc.Spec.External.Enabled {
+		endpoint = kc.Spec.External.URL
+	}
+	caCertificates, err := adminClientCACertificates(kc, secretClient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client := &Client{
+		URL:      endpoint,
+		pageSize: kc.Spec.AdminClientPageSize,
+	}
+	requester := &reauthenticatingRequester{
+		requester: requesterFor(kc, caCertificates),
+		client:    client,
+	}
+	client.rootPath = servesAtRoot(requester.requester, endpoint)
+	client.requester = requester
+	return client, requester, nil
+}
+
+func (c *Client) authenticateWithClientCredentials(requester *reauthenticatingRequester, secretClient kubernetes.Interface, namespace string, ref *v1alpha1.KeycloakAdminClientCredentialsRef) error {
+	clientCreds, err := secretClient.CoreV1().Secrets(namespace).Get(context.TODO(), ref.Name, v12.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get the admin client credentials")
+	}
+	c.clientCredentials = true
+	requester.user = string(clientCreds.Data[model.AdminClientIDKey(ref)])
+	requester.pass = string(clientCreds.Data[model.AdminClientSecretKey(ref)])
+	return c.authenticate(requester.user, requester.pass)
+}
@@ -0,0 +1,62 @@
+package common
+
+import (
+	"strconv"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+)
+
+const (
+	displayOnConsentScreenAttribute = "display.on.consent.screen"
+	consentScreenTextAttribute      = "consent.screen.text"
+)
+
+// Sets the consent settings as client attributes. Without consent the texts of the
+// client and its mappers are dropped, so that they don't show up once consent is
+// required again
+func setConsentAttributes(cr *kc.KeycloakClient) {
+	if !cr.Spec.Client.ConsentRequired {
+		for index := range cr.Spec.Client.ProtocolMappers {
+			cr.Spec.Client.ProtocolMappers[index].ConsentRequired = false
+			cr.Spec.Client.ProtocolMappers[index].ConsentText = ""
+		}
+		if _, ok := cr.Spec.Client.Attributes[consentScreenTextAttribute]; ok {
+			cr.Spec.Client.Attributes[consentScreenTextAttribute] = ""
+		}
+		return
+	}
+
+	if cr.Spec.Consent == nil {
+		return
+	}
+	if cr.Spec.Client.Attributes == nil {
+		cr.Spec.Client.Attributes = make(map[string]string)
+	}
+	for key, value := range ConsentClientAttributes(cr.Spec.Consent) {
+		cr.Spec.Client.Attributes[key] = value
+	}
+}
+
+// The client attributes keycloak keeps the consent settings in, unset settings are left out
+func ConsentClientAttributes(consent *kc.KeycloakClientConsent) map[string]string {
+	attributes := make(map[string]string)
+	if consent.DisplayOnConsentScreen != nil {
+		attributes[displayOnConsentScreenAttribute] = strconv.FormatBool(*consent.DisplayOnConsentScreen)
+	}
+	if consent.ConsentScreenText != "" {
+		attributes[consentScreenTextAttribute] = consent.ConsentScreenText
+	}
+	return attributes
+}
+
+// Keycloak keeps the attributes an update leaves out, so the text of a client that
+// no longer requires consent is cleared explicitly
+func clearConsentScreenText(cr *kc.KeycloakClient, client *kc.KeycloakAPIClient) {
+	if cr.Spec.Client.ConsentRequired || client == nil || client.Attributes[consentScreenTextAttribute] == "" {
+		return
+	}
+	if cr.Spec.Client.Attributes == nil {
+		cr.Spec.Client.Attributes = make(map[string]string)
+	}
+	cr.Spec.Client.Attributes[consentScreenTextAttribute] = ""
+}
@@ -0,0 +1,50 @@
+package common
+
+import (
+	"testing"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsentClientAttributes(t *testing.T) {
+	// given
+	display := true
+	cr := &kc.KeycloakClient{
+		Spec: kc.KeycloakClientSpec{
+			Client: &kc.KeycloakAPIClient{
+				ConsentRequired: true,
+				ProtocolMappers: []kc.KeycloakProtocolMapper{
+					{Name: "email", ConsentRequired: true, ConsentText: "${email}"},
+				},
+			},
+			Consent: &kc.KeycloakClientConsent{
+				DisplayOnConsentScreen: &display,
+				ConsentScreenText:      "ACME Reports",
+			},
+		},
+	}
+
+	// when
+	setConsentAttributes(cr)
+
+	// then
+	assert.Equal(t, map[string]string{
+		"display.on.consent.screen": "true",
+		"consent.screen.text":       "ACME Reports",
+	}, cr.Spec.Client.Attributes)
+	assert.Equal(t, "${email}", cr.Spec.Client.ProtocolMappers[0].ConsentText)
+
+	// when consent is no longer required
+	cr.Spec.Client.ConsentRequired = false
+	cr.Spec.Client.Attributes = nil
+	setConsentAttributes(cr)
+	clearConsentScreenText(cr, &kc.KeycloakAPIClient{
+		Attributes: map[string]string{"consent.screen.text": "ACME Reports"},
+	})
+
+	// then
+	assert.Equal(t, map[string]string{"consent.screen.text": ""}, cr.Spec.Client.Attributes)
+	assert.False(t, cr.Spec.Client.ProtocolMappers[0].ConsentRequired)
+	assert.Equal(t, "", cr.Spec.Client.ProtocolMappers[0].ConsentText)
+}
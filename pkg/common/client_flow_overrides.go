@@ -0,0 +1,44 @@
+package common
+
+import (
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+// Bindings of the realm flows that a client can override
+var clientFlowBindings = []string{"browser", "direct_grant"}
+
+// Sets the IDs of the flows overriding the ones of the realm. Bindings without an
+// override are sent empty, which keycloak removes the override for
+func setAuthFlowOverrides(cr *kc.KeycloakClient, flows []*kc.KeycloakAPIAuthenticationFlow) error {
+	overrides := make(map[string]string)
+	for _, binding := range clientFlowBindings {
+		overrides[binding] = ""
+	}
+
+	for binding, flow := range cr.Spec.AuthFlowOverrides {
+		if !containsString(clientFlowBindings, binding) {
+			return errors.Errorf("unknown authentication flow binding %v, expected one of %q", binding, clientFlowBindings)
+		}
+		if flow == "" {
+			continue
+		}
+		id := findFlowID(flows, flow)
+		if id == "" {
+			return errors.Errorf("authentication flow %v overriding the %v flow not found", flow, binding)
+		}
+		overrides[binding] = id
+	}
+
+	cr.Spec.Client.AuthenticationFlowBindingOverrides = overrides
+	return nil
+}
+
+func findFlowID(flows []*kc.KeycloakAPIAuthenticationFlow, aliasOrID string) string {
+	for _, flow := range flows {
+		if flow.Alias == aliasOrID || flow.ID == aliasOrID {
+			return flow.ID
+		}
+	}
+	return ""
+}
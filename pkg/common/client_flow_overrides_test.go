@@ -0,0 +1,55 @@
+package common
+
+import (
+	"testing"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAuthFlowOverrides(t *testing.T) {
+	// given
+	flows := []*kc.KeycloakAPIAuthenticationFlow{
+		{ID: "browser-id", Alias: "browser"},
+		{ID: "mfa-id", Alias: "browser with mfa"},
+		{ID: "grant-id", Alias: "direct grant with otp"},
+	}
+	cr := &kc.KeycloakClient{
+		Spec: kc.KeycloakClientSpec{
+			Client: &kc.KeycloakAPIClient{},
+			AuthFlowOverrides: map[string]string{
+				"browser": "browser with mfa",
+			},
+		},
+	}
+
+	// when
+	err := setAuthFlowOverrides(cr, flows)
+
+	// then
+	// the alias is resolved and the binding left out is removed
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"browser": "mfa-id", "direct_grant": ""}, cr.Spec.Client.AuthenticationFlowBindingOverrides)
+
+	// when the flow is given by its ID
+	cr.Spec.AuthFlowOverrides = map[string]string{"direct_grant": "grant-id"}
+	err = setAuthFlowOverrides(cr, flows)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"browser": "", "direct_grant": "grant-id"}, cr.Spec.Client.AuthenticationFlowBindingOverrides)
+
+	// when the flow doesn't exist
+	cr.Spec.AuthFlowOverrides = map[string]string{"browser": "missing"}
+	err = setAuthFlowOverrides(cr, flows)
+
+	// then
+	assert.EqualError(t, err, "authentication flow missing overriding the browser flow not found")
+
+	// when the binding can't be overridden
+	cr.Spec.AuthFlowOverrides = map[string]string{"registration": "browser"}
+	err = setAuthFlowOverrides(cr, flows)
+
+	// then
+	assert.Contains(t, err.Error(), "unknown authentication flow binding registration")
+}
@@ -0,0 +1,43 @@
+package common
+
+import (
+	"strconv"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+)
+
+// Sets the logout settings as client attributes. A front-channel logout URL only
+// takes effect with front-channel logout turned on
+func setLogoutAttributes(cr *kc.KeycloakClient) {
+	if cr.Spec.Logout == nil {
+		return
+	}
+	if cr.Spec.Client.Attributes == nil {
+		cr.Spec.Client.Attributes = make(map[string]string)
+	}
+	for key, value := range LogoutClientAttributes(cr.Spec.Logout) {
+		cr.Spec.Client.Attributes[key] = value
+	}
+	if cr.Spec.Logout.FrontchannelLogoutURL != "" {
+		cr.Spec.Client.FrontchannelLogout = &[]bool{true}[0]
+	}
+}
+
+// The client attributes keycloak keeps the logout settings in. Unset settings are
+// empty, which keycloak removes, so that clearing a setting drops it in keycloak
+func LogoutClientAttributes(logout *kc.KeycloakClientLogout) map[string]string {
+	return map[string]string{
+		"frontchannel.logout.url":                  logout.FrontchannelLogoutURL,
+		"frontchannel.logout.session.required":     boolAttribute(logout.FrontchannelLogoutSessionRequired),
+		"backchannel.logout.url":                   logout.BackchannelLogoutURL,
+		"backchannel.logout.session.required":      boolAttribute(logout.BackchannelLogoutSessionRequired),
+		"backchannel.logout.revoke.offline.tokens": boolAttribute(logout.BackchannelLogoutRevokeOfflineTokens),
+	}
+}
+
+func boolAttribute(value *bool) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatBool(*value)
+}
@@ -0,0 +1,50 @@
+package common
+
+import (
+	"testing"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogoutClientAttributes(t *testing.T) {
+	// given
+	sessionRequired := true
+	cr := &kc.KeycloakClient{
+		Spec: kc.KeycloakClientSpec{
+			Client: &kc.KeycloakAPIClient{
+				Attributes: map[string]string{
+					"backchannel.logout.url":              "https://old.example.com/logout",
+					"backchannel.logout.session.required": "true",
+					"pkce.code.challenge.method":          "S256",
+				},
+			},
+			Logout: &kc.KeycloakClientLogout{
+				FrontchannelLogoutURL:             "https://app.example.com/logout",
+				FrontchannelLogoutSessionRequired: &sessionRequired,
+			},
+		},
+	}
+
+	// when
+	setLogoutAttributes(cr)
+
+	// then
+	// the cleared back-channel settings are removed
+	assert.True(t, *cr.Spec.Client.FrontchannelLogout)
+	assert.Equal(t, "https://app.example.com/logout", cr.Spec.Client.Attributes["frontchannel.logout.url"])
+	assert.Equal(t, "true", cr.Spec.Client.Attributes["frontchannel.logout.session.required"])
+	assert.Equal(t, "", cr.Spec.Client.Attributes["backchannel.logout.url"])
+	assert.Equal(t, "", cr.Spec.Client.Attributes["backchannel.logout.session.required"])
+	assert.Equal(t, "S256", cr.Spec.Client.Attributes["pkce.code.challenge.method"])
+
+	// when the client has no logout settings
+	cr.Spec.Logout = nil
+	cr.Spec.Client.FrontchannelLogout = nil
+	cr.Spec.Client.Attributes = map[string]string{"backchannel.logout.url": "https://old.example.com/logout"}
+	setLogoutAttributes(cr)
+
+	// then
+	assert.Nil(t, cr.Spec.Client.FrontchannelLogout)
+	assert.Equal(t, map[string]string{"backchannel.logout.url": "https://old.example.com/logout"}, cr.Spec.Client.Attributes)
+}
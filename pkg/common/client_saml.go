@@ -0,0 +1,88 @@
+package common
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reads the certificates referenced by the SAML settings and sets the settings as
+// client attributes
+func (i *ClientState) readSAMLAttributes(context context.Context, cr *kc.KeycloakClient, controllerClient client.Client) error {
+	saml := cr.Spec.SAML
+	if saml == nil {
+		return nil
+	}
+
+	var signingCertificate, encryptionCertificate *string
+	var err error
+	if saml.SigningCertificateRef != nil {
+		signingCertificate, err = readSecretKey(context, controllerClient, cr.Namespace, saml.SigningCertificateRef)
+		if err != nil {
+			return err
+		}
+	}
+	if saml.EncryptionCertificateRef != nil {
+		encryptionCertificate, err = readSecretKey(context, controllerClient, cr.Namespace, saml.EncryptionCertificateRef)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.Client.Attributes == nil {
+		cr.Spec.Client.Attributes = make(map[string]string)
+	}
+	for key, value := range SAMLClientAttributes(saml, signingCertificate, encryptionCertificate) {
+		cr.Spec.Client.Attributes[key] = value
+	}
+	return nil
+}
+
+// The client attributes keycloak keeps the SAML settings in, unset settings are left out
+func SAMLClientAttributes(saml *kc.KeycloakClientSAML, signingCertificate, encryptionCertificate *string) map[string]string {
+	attributes := make(map[string]string)
+	setString := func(key, value string) {
+		if value != "" {
+			attributes[key] = value
+		}
+	}
+	setBool := func(key string, value *bool) {
+		if value != nil {
+			attributes[key] = strconv.FormatBool(*value)
+		}
+	}
+
+	if signingCertificate != nil {
+		attributes["saml.signing.certificate"] = certificateAttribute(*signingCertificate)
+	}
+	if encryptionCertificate != nil {
+		attributes["saml.encryption.certificate"] = certificateAttribute(*encryptionCertificate)
+	}
+	setBool("saml.server.signature", saml.ServerSignature)
+	setBool("saml.assertion.signature", saml.AssertionSignature)
+	setBool("saml.client.signature", saml.ClientSignature)
+	setBool("saml.encrypt", saml.EncryptAssertions)
+	setBool("saml.force.post.binding", saml.ForcePostBinding)
+	setBool("saml.authnstatement", saml.AuthnStatement)
+	setString("saml.signature.algorithm", saml.SignatureAlgorithm)
+	setString("saml_signature_canonicalization_method", saml.SignatureCanonicalizationMethod)
+	setString("saml_name_id_format", saml.NameIDFormat)
+	setBool("saml_force_name_id_format", saml.ForceNameIDFormat)
+	return attributes
+}
+
+// Keycloak expects the base64 DER of a certificate, without the PEM armor
+func certificateAttribute(certificate string) string {
+	var body []string
+	for _, line := range strings.Split(certificate, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-----") {
+			continue
+		}
+		body = append(body, line)
+	}
+	return strings.Join(body, "")
+}
@@ -0,0 +1,33 @@
+package common
+
+import (
+	"testing"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSAMLClientAttributes(t *testing.T) {
+	// given
+	enabled := true
+	disabled := false
+	saml := &kc.KeycloakClientSAML{
+		AssertionSignature: &enabled,
+		ClientSignature:    &disabled,
+		SignatureAlgorithm: "RSA_SHA256",
+		NameIDFormat:       "email",
+	}
+	certificate := "-----BEGIN CERTIFICATE-----\nMIIB\nAAAA\n-----END CERTIFICATE-----\n"
+
+	// when
+	attributes := SAMLClientAttributes(saml, &certificate, nil)
+
+	// then
+	assert.Equal(t, map[string]string{
+		"saml.signing.certificate": "MIIBAAAA",
+		"saml.assertion.signature": "true",
+		"saml.client.signature":    "false",
+		"saml.signature.algorithm": "RSA_SHA256",
+		"saml_name_id_format":      "email",
+	}, attributes)
+}
@@ -0,0 +1,37 @@
+package common
+
+import (
+	"context"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+)
+
+type ClientScopeState struct {
+	// Existing client scope of the same name, along with its protocol mappers
+	ClientScope *kc.KeycloakAPIClientScope
+	Context     context.Context
+	Realm       *kc.KeycloakRealm
+}
+
+func NewClientScopeState(context context.Context, realm *kc.KeycloakRealm) *ClientScopeState {
+	return &ClientScopeState{
+		Context: context,
+		Realm:   realm,
+	}
+}
+
+func (i *ClientScopeState) Read(cr *kc.KeycloakClientScope, realmClient KeycloakInterface) error {
+	// Scopes are keyed by name, the ID keycloak assigns is never stored in the CR
+	scopes, err := realmClient.ListClientScopes(i.Realm.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	for index := range scopes {
+		if scopes[index].Name == cr.Spec.ClientScope.Name {
+			i.ClientScope = &scopes[index]
+			break
+		}
+	}
+	return nil
+}
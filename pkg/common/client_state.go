@@ -2,9 +2,11 @@ package common
 
 import (
 	"context"
+	"strings"
 
 	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	"github.com/keycloak/keycloak-operator/pkg/model"
+	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -13,9 +15,25 @@ import (
 type ClientState struct {
 	Client       *kc.KeycloakAPIClient
 	ClientSecret *v1.Secret
-	Context      context.Context
-	Realm        *kc.KeycloakRealm
-	Roles        []kc.RoleRepresentation
+	// True if the client secret in keycloak differs from the one referenced by secretRef
+	ClientSecretDiverged bool
+	Context              context.Context
+	Realm                *kc.KeycloakRealm
+	Roles                []kc.RoleRepresentation
+	// Composites of the existing composite roles, keyed by role name
+	RoleComposites map[string]*kc.RoleRepresentationComposites
+	// Service account user of the client and its role mappings
+	ServiceAccount *UserState
+	// Client scopes currently assigned to the client
+	DefaultClientScopes  []kc.KeycloakAPIClientScope
+	OptionalClientScopes []kc.KeycloakAPIClientScope
+	// Current authorization settings, only read when managed by the CR
+	AuthorizationSettings *kc.ResourceServerRepresentation
+	// Roles in the scope of the client, only read when managed by the CR
+	ScopeMappings *kc.RoleRepresentationComposites
+	// Names of the roles of the client among the default roles of the realm, only read
+	// when managed by the CR
+	DefaultRoles []string
 }
 
 func NewClientState(context context.Context, realm *kc.KeycloakRealm) *ClientState {
@@ -26,6 +44,33 @@ func NewClientState(context context.Context, realm *kc.KeycloakRealm) *ClientSta
 }
 
 func (i *ClientState) Read(context context.Context, cr *kc.KeycloakClient, realmClient KeycloakInterface, controllerClient client.Client) error {
+	// The referenced secret is read first, so that new clients are created with it
+	referencedSecret, err := i.readReferencedSecret(context, cr, controllerClient)
+	if err != nil {
+		return err
+	}
+
+	// The SAML settings go into the attributes, which new clients are created with as well
+	err = i.readSAMLAttributes(context, cr, controllerClient)
+	if err != nil {
+		return err
+	}
+	setTokenSettingsAttributes(cr)
+	setConsentAttributes(cr)
+	setLogoutAttributes(cr)
+
+	// The overrides of new clients have to be resolved before creating them as well
+	if cr.Spec.AuthFlowOverrides != nil {
+		flows, err := realmClient.ListAuthenticationFlows(i.Realm.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+		err = setAuthFlowOverrides(cr, flows)
+		if err != nil {
+			return err
+		}
+	}
+
 	if cr.Spec.Client.ID == "" {
 		return nil
 	}
@@ -37,10 +82,26 @@ func (i *ClientState) Read(context context.Context, cr *kc.KeycloakClient, realm
 	}
 
 	i.Client = client
+	clearConsentScreenText(cr, i.Client)
+
+	// The client was deleted in keycloak, so there is nothing else to read. It will be
+	// re-created along with its roles, which get new IDs
+	if i.Client == nil {
+		for index := range cr.Spec.Roles {
+			cr.Spec.Roles[index].ID = ""
+		}
+		return i.readClientSecret(context, cr, i.Client, controllerClient)
+	}
 
-	// CR could have updated with new secret, so set saved secret to Spec only when empty
-	// Otherwise let reconcile loop to update secret with desired secret in CR
-	if cr.Spec.Client.Secret == "" {
+	if referencedSecret != nil {
+		clientSecret, err := realmClient.GetClientSecret(cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+		i.ClientSecretDiverged = clientSecret != *referencedSecret
+	} else if cr.Spec.Client.Secret == "" {
+		// CR could have updated with new secret, so set saved secret to Spec only when empty
+		// Otherwise let reconcile loop to update secret with desired secret in CR
 		clientSecret, err := realmClient.GetClientSecret(cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
 		if err != nil {
 			return err
@@ -54,8 +115,47 @@ func (i *ClientState) Read(context context.Context, cr *kc.KeycloakClient, realm
 		return err
 	}
 
-	if i.Client != nil {
-		i.Roles, err = realmClient.ListClientRoles(cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
+	i.Roles, err = realmClient.ListClientRoles(cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	err = i.readRoleComposites(cr, realmClient)
+	if err != nil {
+		return err
+	}
+
+	err = i.readServiceAccount(cr, realmClient)
+	if err != nil {
+		return err
+	}
+
+	i.DefaultClientScopes, err = realmClient.ListDefaultClientScopes(cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	i.OptionalClientScopes, err = realmClient.ListOptionalClientScopes(cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	if cr.Spec.ScopeMappings != nil {
+		err = i.readScopeMappings(cr, realmClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.DefaultRoles != nil {
+		err = i.readDefaultRoles(cr, realmClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	if IsTrue(i.Client.AuthorizationServicesEnabled) && cr.Spec.Client.AuthorizationSettings != nil {
+		i.AuthorizationSettings, err = realmClient.GetClientAuthorizationSettings(cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
 		if err != nil {
 			return err
 		}
@@ -64,6 +164,131 @@ func (i *ClientState) Read(context context.Context, cr *kc.KeycloakClient, realm
 	return nil
 }
 
+func (i *ClientState) readRoleComposites(cr *kc.KeycloakClient, realmClient KeycloakInterface) error {
+	listComposites := func(roleName string) ([]kc.RoleRepresentation, error) {
+		return realmClient.ListClientRoleComposites(cr.Spec.Client.ID, roleName, i.Realm.Spec.Realm.Realm)
+	}
+
+	var err error
+	i.RoleComposites, err = readRoleComposites(i.Roles, listComposites, realmClient, i.Realm.Spec.Realm.Realm)
+	return err
+}
+
+func (i *ClientState) readScopeMappings(cr *kc.KeycloakClient, realmClient KeycloakInterface) error {
+	mappings, err := realmClient.ListClientScopeMappings(cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	i.ScopeMappings = &kc.RoleRepresentationComposites{}
+	for _, role := range mappings.RealmMappings {
+		i.ScopeMappings.Realm = append(i.ScopeMappings.Realm, role.Name)
+	}
+	for clientID, clientMappings := range mappings.ClientMappings {
+		if i.ScopeMappings.Client == nil {
+			i.ScopeMappings.Client = make(map[string][]string)
+		}
+		for _, role := range clientMappings.Mappings {
+			i.ScopeMappings.Client[clientID] = append(i.ScopeMappings.Client[clientID], role.Name)
+		}
+	}
+	return nil
+}
+
+// Keycloak names the composite role holding the default roles after the realm when
+// creating it
+func RealmDefaultRolesName(realm string) string {
+	return "default-roles-" + strings.ToLower(realm)
+}
+
+// The default roles of the realm contain roles of other clients and realm roles as well.
+// Older versions of keycloak keep them in lists of the realm and its clients instead,
+// which aren't supported. The server info is only a hint, reading it may not be allowed
+func (i *ClientState) readDefaultRoles(cr *kc.KeycloakClient, realmClient KeycloakInterface) error {
+	if info, err := realmClient.GetServerInfo(); err == nil && !supportsDefaultRolesComposite(info) {
+		return errors.Errorf("defaultRoles need keycloak %v or later, found %v", defaultRolesCompositeVersion, info.Version)
+	}
+
+	composites, err := realmClient.ListRealmRoleComposites(RealmDefaultRolesName(i.Realm.Spec.Realm.Realm), i.Realm.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	i.DefaultRoles = []string{}
+	for _, role := range composites {
+		if role.ClientRole != nil && *role.ClientRole && role.ContainerID == cr.Spec.Client.ID {
+			i.DefaultRoles = append(i.DefaultRoles, role.Name)
+		}
+	}
+	return nil
+}
+
+func (i *ClientState) readServiceAccount(cr *kc.KeycloakClient, realmClient KeycloakInterface) error {
+	if !IsTrue(i.Client.ServiceAccountsEnabled) {
+		return nil
+	}
+
+	user, err := realmClient.GetServiceAccountUser(cr.Spec.Client.ID, i.Realm.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	i.ServiceAccount = NewUserState(kc.Keycloak{})
+	i.ServiceAccount.User = user
+
+	err = i.ServiceAccount.readRealmRoles(realmClient, nil, i.Realm.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	return i.ServiceAccount.readClientRoles(realmClient, nil, i.Realm.Spec.Realm.Realm)
+}
+
+// Returns the value of the secret referenced by the CR, if any, and uses it as the
+// desired client secret
+func (i *ClientState) readReferencedSecret(context context.Context, cr *kc.KeycloakClient, controllerClient client.Client) (*string, error) {
+	ref := cr.Spec.SecretRef
+	if ref == nil {
+		return nil, nil
+	}
+
+	referencedSecret, err := readSecretKey(context, controllerClient, cr.Namespace, ref)
+	if err != nil || referencedSecret == nil {
+		return nil, err
+	}
+
+	cr.Spec.Client.Secret = *referencedSecret
+	return referencedSecret, nil
+}
+
+// Returns nil for a missing optional secret or key
+func readSecretKey(context context.Context, controllerClient client.Client, namespace string, ref *v1.SecretKeySelector) (*string, error) {
+	optional := ref.Optional != nil && *ref.Optional
+
+	secret := &v1.Secret{}
+	err := controllerClient.Get(context, client.ObjectKey{Name: ref.Name, Namespace: namespace}, secret)
+	if err != nil {
+		if apiErrors.IsNotFound(err) && optional {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		if optional {
+			return nil, nil
+		}
+		return nil, errors.Errorf("key %v not found in secret %v/%v", ref.Key, namespace, ref.Name)
+	}
+
+	result := string(value)
+	return &result, nil
+}
+
 func (i *ClientState) readClientSecret(context context.Context, cr *kc.KeycloakClient, clientSpec *kc.KeycloakAPIClient, controllerClient client.Client) error {
 	key := model.ClientSecretSelector(cr)
 	secret := model.ClientSecret(cr)
@@ -77,5 +302,9 @@ func (i *ClientState) readClientSecret(context context.Context, cr *kc.KeycloakC
 		i.ClientSecret = secret.DeepCopy()
 		cr.UpdateStatusSecondaryResources(i.ClientSecret.Kind, i.ClientSecret.Name)
 	}
+
+	if rotatedSecret := model.RotatedClientSecret(cr, i.ClientSecret); rotatedSecret != nil {
+		cr.Spec.Client.Secret = *rotatedSecret
+	}
 	return nil
 }
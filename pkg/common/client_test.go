@@ -1,10 +1,13 @@
 package common
 
 import (
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
 
@@ -20,6 +23,7 @@ const (
 	UserDeletePath         = "/auth/admin/realms/%s/users/%s"
 	UserGetPath            = "/auth/admin/realms/%s/users/%s"
 	UserFindByUsernamePath = "/auth/admin/realms/%s/users?username=%s&max=-1"
+	GroupByPathPath        = "/auth/admin/realms/%s/group-by-path/%s"
 	TokenPath              = "/auth/realms/master/protocol/openid-connect/token" // nolint
 )
 
@@ -212,6 +216,35 @@ func TestClient_FindUserByUsername(t *testing.T) {
 	assert.Equal(t, user, userFound)
 }
 
+func TestClient_GetGroupByPath(t *testing.T) {
+	// given
+	realm := getDummyRealm()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, fmt.Sprintf(GroupByPathPath, realm.Spec.Realm.Realm, "parent/child%20group"), req.URL.EscapedPath())
+		json, err := jsoniter.Marshal(v1alpha1.KeycloakUserGroup{ID: "childID", Name: "child group", Path: "/parent/child group"})
+		assert.NoError(t, err)
+		_, err = w.Write(json)
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	group, err := client.GetGroupByPath("/parent/child group", realm.Spec.Realm.Realm)
+
+	// then
+	// correct path expected on httptest server
+	assert.NoError(t, err)
+	assert.Equal(t, "childID", group.ID)
+}
+
 func TestClient_GetRealm(t *testing.T) {
 	// given
 	realm := getDummyRealm()
@@ -322,3 +355,689 @@ func TestClient_login(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, client.token, "dummy")
 }
+
+func TestClient_login_Client_Credentials(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, TokenPath, req.URL.Path)
+		assert.NoError(t, req.ParseForm())
+		assert.Equal(t, "client_credentials", req.PostForm.Get("grant_type"))
+		assert.Equal(t, "operator", req.PostForm.Get("client_id"))
+		assert.Equal(t, "secret", req.PostForm.Get("client_secret"))
+		assert.Empty(t, req.PostForm.Get("username"))
+
+		json, err := jsoniter.Marshal(v1alpha1.TokenResponse{AccessToken: "dummy", ExpiresIn: 60})
+		assert.NoError(t, err)
+		_, err = w.Write(json)
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester:         server.Client(),
+		URL:               server.URL,
+		clientCredentials: true,
+	}
+
+	// when
+	err := client.authenticate("operator", "secret")
+
+	// then
+	// the token isn't shared with a user of the same name
+	assert.NoError(t, err)
+	assert.Equal(t, "dummy", client.token)
+	client.clientCredentials = false
+	_, cached := getCachedAdminToken(client.adminTokenKey("operator", "secret"))
+	assert.False(t, cached)
+}
+
+func TestClient_login_Realm_Client_Credentials(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// the client logs in to its own realm
+		assert.Equal(t, "/auth/realms/tenant/protocol/openid-connect/token", req.URL.Path)
+		json, err := jsoniter.Marshal(v1alpha1.TokenResponse{AccessToken: "tenant", ExpiresIn: 60})
+		assert.NoError(t, err)
+		_, err = w.Write(json)
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester:         server.Client(),
+		URL:               server.URL,
+		clientCredentials: true,
+		tokenRealm:        "tenant",
+	}
+
+	// when
+	err := client.authenticate("operator", "secret")
+
+	// then
+	// the token isn't shared with a client of the same name in the master realm
+	assert.NoError(t, err)
+	assert.Equal(t, "tenant", client.token)
+	client.tokenRealm = ""
+	_, cached := getCachedAdminToken(client.adminTokenKey("operator", "secret"))
+	assert.False(t, cached)
+}
+
+func TestClient_Token_Caching(t *testing.T) {
+	// given
+	realm := getDummyRealm()
+	logins := 0
+	refused := false
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == TokenPath {
+			logins++
+			json, err := jsoniter.Marshal(v1alpha1.TokenResponse{
+				AccessToken: fmt.Sprintf("token-%v", logins),
+				ExpiresIn:   60,
+			})
+			assert.NoError(t, err)
+			_, err = w.Write(json)
+			assert.NoError(t, err)
+			return
+		}
+
+		assert.Equal(t, RealmsCreatePath, req.URL.Path)
+		body, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), realm.Spec.Realm.Realm)
+		if refused && req.Header.Get("Authorization") == "Bearer token-1" {
+			w.WriteHeader(401)
+			return
+		}
+		w.WriteHeader(201)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	newClient := func() *Client {
+		client := &Client{URL: server.URL}
+		client.requester = &reauthenticatingRequester{
+			requester: server.Client(),
+			client:    client,
+			user:      "admin",
+			pass:      "password",
+		}
+		return client
+	}
+
+	// when
+	first := newClient()
+	assert.NoError(t, first.authenticate("admin", "password"))
+	second := newClient()
+	assert.NoError(t, second.authenticate("admin", "password"))
+
+	// then
+	// the token of the first login is reused
+	assert.Equal(t, 1, logins)
+	assert.Equal(t, "token-1", second.token)
+
+	// when
+	refused = true
+	_, err := second.CreateRealm(realm)
+
+	// then
+	// the refused token is replaced and the request sent again
+	assert.NoError(t, err)
+	assert.Equal(t, 2, logins)
+	assert.Equal(t, "token-2", second.token)
+
+	// when
+	third := newClient()
+	assert.NoError(t, third.authenticate("admin", "password"))
+	other := newClient()
+	assert.NoError(t, other.authenticate("admin", "other"))
+
+	// then
+	assert.Equal(t, "token-2", third.token)
+	assert.Equal(t, "token-3", other.token)
+}
+
+func TestClient_ListUsers_Paged(t *testing.T) {
+	// given
+	var pages []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, fmt.Sprintf(UserCreatePath, "dummy"), req.URL.Path)
+		pages = append(pages, req.URL.RawQuery)
+
+		users := `[{"username":"first"},{"username":"second"}]`
+		switch req.URL.Query().Get("first") {
+		case "2":
+			users = `[{"username":"third"},{"username":"fourth"}]`
+		case "4":
+			users = `[{"username":"last"}]`
+		}
+		_, err := w.Write([]byte(users))
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+		pageSize:  2,
+	}
+
+	// when
+	users, err := client.ListUsers("dummy")
+
+	// then
+	// pages are requested until one isn't full
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first=0&max=2", "first=2&max=2", "first=4&max=2"}, pages)
+	assert.Len(t, users, 5)
+	assert.Equal(t, "last", users[4].UserName)
+}
+
+func TestClient_ListUsers_Paging_Ignored(t *testing.T) {
+	// given
+	requests := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		_, err := w.Write([]byte(`[{"username":"first"},{"username":"second"}]`))
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+		pageSize:  2,
+	}
+
+	// when
+	users, err := client.ListUsers("dummy")
+
+	// then
+	// paging stops at the first repeated page instead of looping forever
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requests)
+	assert.Len(t, users, 2)
+}
+
+func TestClient_Retry_On_Server_Error(t *testing.T) {
+	// given
+	realm := getDummyRealm()
+	attempts := 0
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), realm.Spec.Realm.Realm)
+
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(503)
+			return
+		}
+		w.WriteHeader(204)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: &retryingRequester{
+			requester:  server.Client(),
+			maxRetries: 2,
+			backoff:    time.Millisecond,
+		},
+		URL:   server.URL,
+		token: "dummy",
+	}
+
+	// when
+	err := client.UpdateRealm(realm.Spec.Realm)
+
+	// then
+	// the request body is sent again with every attempt
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+
+	// when
+	attempts = 0
+	client.requester.(*retryingRequester).maxRetries = 1
+	err = client.UpdateRealm(realm.Spec.Realm)
+
+	// then
+	// retries are bounded
+	assert.Error(t, err)
+	assert.Equal(t, 2, attempts)
+
+	// when
+	attempts = 0
+	_, err = client.CreateRealm(realm)
+
+	// then
+	// a create isn't retried, keycloak may have created the realm before failing
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestClient_Ping_Does_Not_Retry(t *testing.T) {
+	// given
+	attempts := 0
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		w.WriteHeader(503)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: &retryingRequester{
+			requester:  server.Client(),
+			maxRetries: 3,
+			backoff:    time.Millisecond,
+		},
+		URL:   server.URL,
+		token: "dummy",
+	}
+
+	// when
+	err := client.Ping()
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+	assert.True(t, IsTransientError(err))
+}
+
+func TestClient_Ping_Permanent_Error(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(404)
+	})
+	server := httptest.NewServer(handler)
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	err := client.Ping()
+
+	// then
+	assert.Error(t, err)
+	assert.False(t, IsTransientError(err))
+
+	// when keycloak can't be reached at all
+	server.Close()
+	err = client.Ping()
+
+	// then
+	assert.True(t, IsTransientError(err))
+}
+
+func TestClient_ImportRealm(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/auth/admin/realms/dummy/partialImport", req.URL.Path)
+		assert.Equal(t, req.Method, http.MethodPost)
+		body, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+
+		var rep map[string]interface{}
+		assert.NoError(t, jsoniter.Unmarshal(body, &rep))
+		assert.Equal(t, "OVERWRITE", rep["ifResourceExists"])
+		assert.Len(t, rep["clients"], 1)
+		w.WriteHeader(200)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	err := client.ImportRealm([]byte(`{"realm":"dummy","clients":[{"clientId":"legacy"}]}`), "OVERWRITE", "dummy")
+
+	// then
+	assert.NoError(t, err)
+
+	// when
+	err = client.ImportRealm([]byte(`not json`), "OVERWRITE", "dummy")
+
+	// then
+	assert.Error(t, err)
+}
+
+func TestClient_ImportUsers(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/auth/admin/realms/dummy/partialImport", req.URL.Path)
+		assert.Equal(t, req.Method, http.MethodPost)
+		body, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+
+		var rep PartialImportRepresentation
+		assert.NoError(t, jsoniter.Unmarshal(body, &rep))
+		assert.Equal(t, "SKIP", rep.IfResourceExists)
+		assert.Len(t, rep.Users, 2)
+		w.WriteHeader(200)
+		_, err = w.Write([]byte(`{"added":1,"skipped":1,"overwritten":0,"results":[` +
+			`{"action":"ADDED","resourceType":"USER","resourceName":"alice","id":"1"},` +
+			`{"action":"SKIPPED","resourceType":"USER","resourceName":"bob","id":"2"}]}`))
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+	users := []*v1alpha1.KeycloakAPIUser{{UserName: "alice"}, {UserName: "Bob"}}
+
+	// when
+	response, err := client.ImportUsers(users, "dummy")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.Skipped)
+	assert.Len(t, response.Results, 2)
+
+	// when
+	results := userImportResults(users, response, nil)
+
+	// then
+	// keycloak answers with the usernames in lower case
+	assert.Equal(t, v1alpha1.UserImportAdded, results[0].Result)
+	assert.Equal(t, v1alpha1.UserImportSkipped, results[1].Result)
+	assert.Equal(t, "Bob", results[1].Username)
+}
+
+func TestClient_SendUserActionsEmail(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/auth/admin/realms/dummy/users/1/execute-actions-email", req.URL.Path)
+		assert.Equal(t, req.Method, http.MethodPut)
+		assert.Equal(t, "app", req.URL.Query().Get("client_id"))
+		assert.Equal(t, "3600", req.URL.Query().Get("lifespan"))
+		body, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+
+		var actions []string
+		assert.NoError(t, jsoniter.Unmarshal(body, &actions))
+		assert.Equal(t, []string{"VERIFY_EMAIL"}, actions)
+		w.WriteHeader(204)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+	lifespan := int32(3600)
+
+	// when
+	// the email asks to verify the email address by default
+	err := client.SendUserActionsEmail("1", &v1alpha1.KeycloakUserActionsEmail{ClientID: "app", Lifespan: &lifespan}, "dummy")
+
+	// then
+	assert.NoError(t, err)
+}
+
+func TestClient_ListThemes(t *testing.T) {
+	// given
+	requests := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		assert.Equal(t, "/auth/admin/serverinfo", req.URL.Path)
+		w.WriteHeader(200)
+		_, err := w.Write([]byte(`{"systemInfo":{},"themes":{"login":[{"name":"keycloak","locales":["en"]},{"name":"branded"}],"email":[{"name":"keycloak"}]}}`))
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	themes, err := client.ListThemes()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"keycloak", "branded"}, themes["login"])
+	assert.Equal(t, []string{"keycloak"}, themes["email"])
+
+	// when the version is read as well
+	_, err = client.GetServerInfo()
+
+	// then
+	// the server info is only read once
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestClient_ExportRealm(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/auth/admin/realms/dummy/partial-export", req.URL.Path)
+		assert.Equal(t, req.Method, http.MethodPost)
+		assert.Equal(t, "true", req.URL.Query().Get("exportClients"))
+		assert.Equal(t, "false", req.URL.Query().Get("exportGroupsAndRoles"))
+		w.WriteHeader(200)
+		_, err := w.Write([]byte(`{"realm":"dummy"}`))
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	realmJSON, err := client.ExportRealm("dummy", true, false)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, `{"realm":"dummy"}`, string(realmJSON))
+}
+
+func TestClient_UpdateRealmLocalizationText(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/auth/admin/realms/dummy/localization/en/loginTitle", req.URL.Path)
+		assert.Equal(t, req.Method, http.MethodPut)
+		assert.Equal(t, "text/plain", req.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "Sign in to ACME", string(body))
+		w.WriteHeader(204)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	err := client.UpdateRealmLocalizationText("en", "loginTitle", "Sign in to ACME", "dummy")
+
+	// then
+	assert.NoError(t, err)
+}
+
+func TestClient_Client_Role_Attributes(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			assert.Equal(t, "/auth/admin/realms/dummy/clients/clientID/roles", req.URL.Path)
+			assert.Equal(t, "false", req.URL.Query().Get("briefRepresentation"))
+			_, err := w.Write([]byte(`[{"id":"roleID","name":"role","attributes":{"tier":["silver"]}}]`))
+			assert.NoError(t, err)
+		case http.MethodPut:
+			assert.Equal(t, "/auth/admin/realms/dummy/clients/clientID/roles/role", req.URL.Path)
+			body, err := ioutil.ReadAll(req.Body)
+			assert.NoError(t, err)
+			assert.Contains(t, string(body), `"attributes":{"tier":["gold"]}`)
+			w.WriteHeader(204)
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	roles, err := client.ListClientRoles("clientID", "dummy")
+
+	// then
+	// the attributes are listed along with the roles
+	assert.NoError(t, err)
+	assert.Len(t, roles, 1)
+	assert.Equal(t, map[string][]string{"tier": {"silver"}}, roles[0].Attributes)
+
+	// when
+	role := roles[0].DeepCopy()
+	role.Attributes = map[string][]string{"tier": {"gold"}}
+	err = client.UpdateClientRole("clientID", role, &roles[0], "dummy")
+
+	// then
+	assert.NoError(t, err)
+}
+
+func TestClient_RegenerateRegistrationAccessToken(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/auth/admin/realms/dummy/clients/clientID/registration-access-token", req.URL.Path)
+		assert.Equal(t, req.Method, http.MethodPost)
+		_, err := w.Write([]byte(`{"id":"clientID","clientId":"client","registrationAccessToken":"token"}`))
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	token, err := client.RegenerateRegistrationAccessToken("clientID", "dummy")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "token", token)
+}
+
+func TestClient_Admin_Client_Proxy(t *testing.T) {
+	// given
+	kc := v1alpha1.Keycloak{}
+	kc.Spec.AdminClientProxy = &v1alpha1.KeycloakAdminClientProxy{
+		URL:     "socks5://proxy:1080",
+		NoProxy: []string{"keycloak.local", ".svc"},
+	}
+	proxy := adminClientProxy(kc)
+
+	// when
+	external, err := proxy(httptest.NewRequest(http.MethodGet, "https://sso.example.com/auth", nil))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "socks5://proxy:1080", external.String())
+
+	// when
+	internal, err := proxy(httptest.NewRequest(http.MethodGet, "https://keycloak.keycloak.svc:8443/auth", nil))
+	local, _ := proxy(httptest.NewRequest(http.MethodGet, "https://keycloak.local/auth", nil))
+
+	// then
+	assert.NoError(t, err)
+	assert.Nil(t, internal)
+	assert.Nil(t, local)
+}
+
+func TestClient_Admin_Client_CA_Certificates(t *testing.T) {
+	// given
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	kc := v1alpha1.Keycloak{}
+	kc.Spec.AdminClientCACertificate = &v1alpha1.KeycloakAdminClientCACertificate{}
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	external := v1alpha1.Keycloak{}
+	external.Spec.External.Enabled = true
+
+	// then
+	// certificates are only verified by default once CA certificates are given
+	assert.False(t, adminClientInsecureSkipVerify(kc))
+	assert.True(t, adminClientInsecureSkipVerify(v1alpha1.Keycloak{}))
+	assert.True(t, adminClientInsecureSkipVerify(external))
+
+	// when
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	_, err := requesterFor(kc, nil).Do(req)
+
+	// then
+	assert.Error(t, err)
+
+	// when
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	res, err := requesterFor(kc, pool).Do(req)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+}
+
+func TestClient_GetServerInfo(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/auth/admin/serverinfo", req.URL.Path)
+		w.WriteHeader(200)
+		_, err := w.Write([]byte(`{"systemInfo":{"version":"15.0.2"},"profileInfo":{"name":"community"},"themes":{}}`))
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+
+	// when
+	info, err := client.GetServerInfo()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, &KeycloakServerInfo{Version: "15.0.2", Profile: "community", Themes: map[string][]string{}}, info)
+}
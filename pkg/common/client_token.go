@@ -0,0 +1,116 @@
+package common
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Tokens are renewed a bit before keycloak lets them expire, so that they don't
+// run out in the middle of the actions of a reconcile
+const tokenExpiryMargin = 10 * time.Second
+
+type cachedToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// Admin tokens shared by all the clients of the operator, so that reconciles
+// don't log in again as long as the last token is valid
+var adminTokens = struct {
+	sync.Mutex
+	tokens map[string]cachedToken
+}{tokens: map[string]cachedToken{}}
+
+// Tokens are kept per credentials, a changed password never reuses the token
+// of the old one, nor does a client share the token of a user with the same name
+func adminTokenKey(endpoint string, clientCredentials bool, user, pass string) string {
+	return fmt.Sprintf("%v\x00%t\x00%v\x00%x", endpoint, clientCredentials, user, sha256.Sum256([]byte(pass)))
+}
+
+// Clients of realms with admin clients of their own log in to their realm
+func (c *Client) adminTokenKey(user, pass string) string {
+	return adminTokenKey(fmt.Sprintf("%v/%v", c.URL, c.tokenPath()), c.clientCredentials, user, pass)
+}
+
+func getCachedAdminToken(key string) (string, bool) {
+	adminTokens.Lock()
+	defer adminTokens.Unlock()
+	cached, ok := adminTokens.tokens[key]
+	if !ok || time.Now().Add(tokenExpiryMargin).After(cached.expiresAt) {
+		return "", false
+	}
+	return cached.token, true
+}
+
+func setCachedAdminToken(key string, token string, expiresAt time.Time) {
+	adminTokens.Lock()
+	defer adminTokens.Unlock()
+	adminTokens.tokens[key] = cachedToken{token: token, expiresAt: expiresAt}
+}
+
+func forgetCachedAdminToken(key string) {
+	adminTokens.Lock()
+	defer adminTokens.Unlock()
+	delete(adminTokens.tokens, key)
+}
+
+// authenticate reuses the cached token of the credentials when it is still valid,
+// and logs in otherwise. With client credentials, user and pass are the ID and
+// secret of the client
+func (c *Client) authenticate(user, pass string) error {
+	key := c.adminTokenKey(user, pass)
+	if token, ok := getCachedAdminToken(key); ok {
+		c.token = token
+		return nil
+	}
+	if err := c.login(user, pass); err != nil {
+		return err
+	}
+	setCachedAdminToken(key, c.token, c.tokenExpiresAt)
+	return nil
+}
+
+// reauthenticatingRequester logs in again and retries a request once when keycloak
+// refuses the token, e.g. because it was revoked or the clock of keycloak is ahead
+type reauthenticatingRequester struct {
+	requester Requester
+	client    *Client
+	user      string
+	pass      string
+}
+
+func (r *reauthenticatingRequester) Do(req *http.Request) (*http.Response, error) {
+	res, err := r.requester.Do(req)
+	// The token endpoint answers wrong credentials with a 401 as well
+	if err != nil || res.StatusCode != http.StatusUnauthorized || strings.HasSuffix(req.URL.Path, r.client.tokenPath()) {
+		return res, err
+	}
+
+	// The body of the request has been consumed and must be restored for the retry
+	if req.Body != nil {
+		if req.GetBody == nil {
+			return res, nil
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return res, nil
+		}
+		req.Body = body
+	}
+
+	forgetCachedAdminToken(r.client.adminTokenKey(r.user, r.pass))
+	if err := r.client.authenticate(r.user, r.pass); err != nil {
+		return res, nil
+	}
+	res.Body.Close()
+
+	logrus.Warnf("request %v %v was not authorized, retrying with a new token", req.Method, req.URL)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", r.client.token))
+	return r.requester.Do(req)
+}
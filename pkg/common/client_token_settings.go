@@ -0,0 +1,58 @@
+package common
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Sets the token settings as client attributes
+func setTokenSettingsAttributes(cr *kc.KeycloakClient) {
+	if cr.Spec.TokenSettings == nil {
+		return
+	}
+	if cr.Spec.Client.Attributes == nil {
+		cr.Spec.Client.Attributes = make(map[string]string)
+	}
+	for key, value := range TokenSettingsClientAttributes(cr.Spec.TokenSettings) {
+		cr.Spec.Client.Attributes[key] = value
+	}
+}
+
+// The client attributes set through the token settings, recorded in the status so
+// that they are cleared once the token settings are removed
+func ManagedTokenSettings(cr *kc.KeycloakClient) []string {
+	if cr.Spec.TokenSettings == nil {
+		return nil
+	}
+	var keys []string
+	for key := range TokenSettingsClientAttributes(cr.Spec.TokenSettings) {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// The client attributes keycloak keeps the token lifespans in, in seconds. Unset
+// settings are empty, which keycloak treats as the lifespan of the realm, so that
+// clearing a setting drops the value it had in keycloak
+func TokenSettingsClientAttributes(settings *kc.KeycloakClientTokenSettings) map[string]string {
+	return map[string]string{
+		"access.token.lifespan":               durationAttribute(settings.AccessTokenLifespan),
+		"client.session.idle.timeout":         durationAttribute(settings.ClientSessionIdleTimeout),
+		"client.session.max.lifespan":         durationAttribute(settings.ClientSessionMaxLifespan),
+		"client.offline.session.idle.timeout": durationAttribute(settings.ClientOfflineSessionIdleTimeout),
+		"client.offline.session.max.lifespan": durationAttribute(settings.ClientOfflineSessionMaxLifespan),
+	}
+}
+
+// Keycloak counts in whole seconds, anything below is dropped
+func durationAttribute(duration *metav1.Duration) string {
+	if duration == nil {
+		return ""
+	}
+	return strconv.FormatInt(int64(duration.Duration/time.Second), 10)
+}
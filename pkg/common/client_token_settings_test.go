@@ -0,0 +1,48 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTokenSettingsClientAttributes(t *testing.T) {
+	// given
+	cr := &kc.KeycloakClient{
+		Spec: kc.KeycloakClientSpec{
+			Client: &kc.KeycloakAPIClient{
+				Attributes: map[string]string{
+					"access.token.lifespan":       "3600",
+					"client.session.idle.timeout": "1800",
+					"pkce.code.challenge.method":  "S256",
+				},
+			},
+			TokenSettings: &kc.KeycloakClientTokenSettings{
+				AccessTokenLifespan: &metav1.Duration{Duration: 5*time.Minute + 500*time.Millisecond},
+			},
+		},
+	}
+
+	// when
+	setTokenSettingsAttributes(cr)
+
+	// then
+	// the cleared session idle timeout reverts to the one of the realm
+	assert.Equal(t, "300", cr.Spec.Client.Attributes["access.token.lifespan"])
+	assert.Equal(t, "", cr.Spec.Client.Attributes["client.session.idle.timeout"])
+	assert.Equal(t, "", cr.Spec.Client.Attributes["client.offline.session.max.lifespan"])
+	assert.Equal(t, "S256", cr.Spec.Client.Attributes["pkce.code.challenge.method"])
+	assert.Len(t, ManagedTokenSettings(cr), 5)
+
+	// when the client has no token settings
+	cr.Spec.TokenSettings = nil
+	cr.Spec.Client.Attributes = map[string]string{"access.token.lifespan": "3600"}
+	setTokenSettingsAttributes(cr)
+
+	// then
+	assert.Equal(t, map[string]string{"access.token.lifespan": "3600"}, cr.Spec.Client.Attributes)
+	assert.Nil(t, ManagedTokenSettings(cr))
+}
@@ -2,14 +2,24 @@ package common
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/model"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -24,27 +34,99 @@ type ActionRunner interface {
 	RunAll(desiredState DesiredClusterState) error
 	Create(obj runtime.Object) error
 	Update(obj runtime.Object) error
+	Delete(obj runtime.Object) error
 	CreateRealm(obj *v1alpha1.KeycloakRealm) error
 	DeleteRealm(obj *v1alpha1.KeycloakRealm) error
+	UpdateRealm(obj *v1alpha1.KeycloakRealm, realm *v1alpha1.KeycloakAPIRealm) error
+	ConfigureRealmEvents(obj *v1alpha1.KeycloakRealm, config *v1alpha1.KeycloakAPIRealmEventsConfig) error
+	ImportRealm(obj *v1alpha1.KeycloakRealm, realmJSON []byte, ifResourceExists string) error
+	ImportRealmUsers(obj *v1alpha1.KeycloakRealm, users []*v1alpha1.KeycloakAPIUser) (*PartialImportResponse, error)
+	UpdateRealmLocalization(obj *v1alpha1.KeycloakRealm, locale string, texts map[string]string, removed []string) error
+	UpdateRequiredAction(obj *v1alpha1.KeycloakRealm, action *v1alpha1.KeycloakAPIRequiredAction, register bool) error
+	UpdateClientProfiles(obj *v1alpha1.KeycloakRealm, profiles []v1alpha1.KeycloakAPIClientProfile) error
+	UpdateClientPolicies(obj *v1alpha1.KeycloakRealm, policies []v1alpha1.KeycloakAPIClientPolicy) error
+	CreateIdentityProvider(obj *v1alpha1.KeycloakRealm, provider *v1alpha1.KeycloakIdentityProvider) error
+	UpdateIdentityProvider(obj *v1alpha1.KeycloakRealm, provider *v1alpha1.KeycloakIdentityProvider) error
+	DeleteIdentityProvider(obj *v1alpha1.KeycloakRealm, alias string) error
+	CreateIdentityProviderMapper(obj *v1alpha1.KeycloakRealm, mapper *v1alpha1.KeycloakIdentityProviderMapper) error
+	UpdateIdentityProviderMapper(obj *v1alpha1.KeycloakRealm, mapper *v1alpha1.KeycloakIdentityProviderMapper) error
+	DeleteIdentityProviderMapper(obj *v1alpha1.KeycloakRealm, mapper *v1alpha1.KeycloakIdentityProviderMapper) error
+	CreateUserFederationProvider(obj *v1alpha1.KeycloakRealm, provider *v1alpha1.KeycloakAPIComponent) error
+	UpdateUserFederationProvider(obj *v1alpha1.KeycloakRealm, provider *v1alpha1.KeycloakAPIComponent) error
+	DeleteUserFederationProvider(obj *v1alpha1.KeycloakRealm, provider *v1alpha1.KeycloakAPIComponent) error
+	CreateUserFederationMapper(obj *v1alpha1.KeycloakRealm, mapper *v1alpha1.KeycloakAPIComponent, providerName string) error
+	UpdateUserFederationMapper(obj *v1alpha1.KeycloakRealm, mapper *v1alpha1.KeycloakAPIComponent) error
+	DeleteUserFederationMapper(obj *v1alpha1.KeycloakRealm, mapper *v1alpha1.KeycloakAPIComponent) error
+	SyncUserFederationProvider(obj *v1alpha1.KeycloakRealm, provider string, full bool) error
+	ExportRealm(obj *v1alpha1.KeycloakRealm, secretName string, exportClients, exportGroupsAndRoles bool) error
+	ReplaceAuthenticationFlow(obj *v1alpha1.KeycloakRealm, flow, existing *v1alpha1.KeycloakAPIAuthenticationFlow) error
+	DeleteAuthenticationFlow(obj *v1alpha1.KeycloakRealm, flow *v1alpha1.KeycloakAPIAuthenticationFlow) error
+	UpdateRealmFlowBindings(obj *v1alpha1.KeycloakRealm) error
+	CreateRealmRole(obj *v1alpha1.KeycloakRealm, role *v1alpha1.RoleRepresentation) error
+	UpdateRealmRole(obj *v1alpha1.KeycloakRealm, role, oldRole *v1alpha1.RoleRepresentation) error
+	DeleteRealmRole(obj *v1alpha1.KeycloakRealm, role string) error
+	AddRealmRoleComposites(obj *v1alpha1.KeycloakRealm, role *v1alpha1.RoleRepresentation, composites *v1alpha1.RoleRepresentationComposites) error
+	RemoveRealmRoleComposites(obj *v1alpha1.KeycloakRealm, role *v1alpha1.RoleRepresentation, composites *v1alpha1.RoleRepresentationComposites) error
+	CreateGroup(obj *v1alpha1.KeycloakRealm, group *v1alpha1.KeycloakAPIGroup, parentPath string) error
+	UpdateGroup(obj *v1alpha1.KeycloakRealm, group *v1alpha1.KeycloakAPIGroup) error
+	DeleteGroup(obj *v1alpha1.KeycloakRealm, group *v1alpha1.KeycloakAPIGroup) error
+	AddGroupRoleMappings(obj *v1alpha1.KeycloakRealm, path string, mappings *v1alpha1.RoleRepresentationComposites) error
+	RemoveGroupRoleMappings(obj *v1alpha1.KeycloakRealm, path string, mappings *v1alpha1.RoleRepresentationComposites) error
+	AddScopeMappings(obj *v1alpha1.KeycloakClient, mappings *v1alpha1.RoleRepresentationComposites, realm string) error
+	RemoveScopeMappings(obj *v1alpha1.KeycloakClient, mappings *v1alpha1.RoleRepresentationComposites, realm string) error
+	AddRealmDefaultRoles(obj *v1alpha1.KeycloakClient, roles []string, realm string) error
+	RemoveRealmDefaultRoles(obj *v1alpha1.KeycloakClient, roles []string, realm string) error
+	SetDefaultGroup(obj *v1alpha1.KeycloakRealm, path string) error
+	UnsetDefaultGroup(obj *v1alpha1.KeycloakRealm, group *v1alpha1.KeycloakUserGroup) error
+	AddRealmDefaultClientScope(obj *v1alpha1.KeycloakRealm, scope *v1alpha1.KeycloakAPIClientScope, optional bool) error
+	RemoveRealmDefaultClientScope(obj *v1alpha1.KeycloakRealm, scope *v1alpha1.KeycloakAPIClientScope, optional bool) error
 	CreateClient(keycloakClient *v1alpha1.KeycloakClient, Realm string) error
 	DeleteClient(keycloakClient *v1alpha1.KeycloakClient, Realm string) error
 	UpdateClient(keycloakClient *v1alpha1.KeycloakClient, Realm string) error
+	RegenerateClientSecret(keycloakClient *v1alpha1.KeycloakClient, secret *corev1.Secret, realm string) error
+	UpdateClientSecret(keycloakClient *v1alpha1.KeycloakClient, realm string) error
+	RegenerateRegistrationAccessToken(keycloakClient *v1alpha1.KeycloakClient, secret *corev1.Secret, realm string) error
 	CreateClientRole(keycloakClient *v1alpha1.KeycloakClient, role *v1alpha1.RoleRepresentation, realm string) error
+	CreateClientRoles(keycloakClient *v1alpha1.KeycloakClient, roles []v1alpha1.RoleRepresentation, realm string) error
 	UpdateClientRole(keycloakClient *v1alpha1.KeycloakClient, role, oldRole *v1alpha1.RoleRepresentation, realm string) error
 	DeleteClientRole(keycloakClient *v1alpha1.KeycloakClient, role, Realm string) error
+	AddClientRoleComposites(keycloakClient *v1alpha1.KeycloakClient, role *v1alpha1.RoleRepresentation, composites *v1alpha1.RoleRepresentationComposites, realm string) error
+	RemoveClientRoleComposites(keycloakClient *v1alpha1.KeycloakClient, role *v1alpha1.RoleRepresentation, composites *v1alpha1.RoleRepresentationComposites, realm string) error
+	CreateClientProtocolMapper(keycloakClient *v1alpha1.KeycloakClient, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error
+	UpdateClientProtocolMapper(keycloakClient *v1alpha1.KeycloakClient, mapper, oldMapper *v1alpha1.KeycloakProtocolMapper, realm string) error
+	DeleteClientProtocolMapper(keycloakClient *v1alpha1.KeycloakClient, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error
+	UpdateClientAuthorizationSettings(keycloakClient *v1alpha1.KeycloakClient, current *v1alpha1.ResourceServerRepresentation, realm string) error
+	AddDefaultClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakAPIClientScope, realm string) error
+	RemoveDefaultClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakAPIClientScope, realm string) error
+	AddOptionalClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakAPIClientScope, realm string) error
+	RemoveOptionalClientScope(keycloakClient *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakAPIClientScope, realm string) error
+	CreateClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error
+	UpdateClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error
+	DeleteClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error
+	CreateClientScopeProtocolMapper(obj *v1alpha1.KeycloakClientScope, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error
+	UpdateClientScopeProtocolMapper(obj *v1alpha1.KeycloakClientScope, mapper, oldMapper *v1alpha1.KeycloakProtocolMapper, realm string) error
+	DeleteClientScopeProtocolMapper(obj *v1alpha1.KeycloakClientScope, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error
 	CreateUser(obj *v1alpha1.KeycloakUser, realm string) error
 	UpdateUser(obj *v1alpha1.KeycloakUser, realm string) error
+	ResetUserPassword(obj *v1alpha1.KeycloakUser, secret *corev1.Secret, realm string) error
+	SendUserActionsEmail(obj *v1alpha1.KeycloakUser, realm string) error
 	DeleteUser(id, realm string) error
 	AssignRealmRole(obj *v1alpha1.KeycloakUserRole, userID, realm string) error
 	RemoveRealmRole(obj *v1alpha1.KeycloakUserRole, userID, realm string) error
 	AssignClientRole(obj *v1alpha1.KeycloakUserRole, clientID, userID, realm string) error
 	RemoveClientRole(obj *v1alpha1.KeycloakUserRole, clientID, userID, realm string) error
+	JoinGroup(path, userID, realm string) error
+	LeaveGroup(obj *v1alpha1.KeycloakUserGroup, userID, realm string) error
+	AddFederatedIdentity(obj *v1alpha1.FederatedIdentity, userID, realm string) error
+	RemoveFederatedIdentity(obj *v1alpha1.FederatedIdentity, userID, realm string) error
 	ApplyOverrides(obj *v1alpha1.KeycloakRealm) error
 	Ping() error
 }
 
 type ClusterAction interface {
 	Run(runner ActionRunner) (string, error)
+	// What the action does, reported when it runs, fails or would run
+	Message() string
 }
 
 type ClusterActionRunner struct {
@@ -53,6 +135,8 @@ type ClusterActionRunner struct {
 	context        context.Context
 	scheme         *runtime.Scheme
 	cr             runtime.Object
+	recorder       record.EventRecorder
+	dryRun         bool
 }
 
 // Create an action runner to run kubernetes actions
@@ -65,30 +149,131 @@ func NewClusterActionRunner(context context.Context, client client.Client, schem
 	}
 }
 
-// Create an action runner to run kubernetes and keycloak api actions
-func NewClusterAndKeycloakActionRunner(context context.Context, client client.Client, scheme *runtime.Scheme, cr runtime.Object, keycloakClient KeycloakInterface) ActionRunner {
+// Create an action runner that only logs the actions, nothing in the cluster or in
+// keycloak is changed
+func NewDryRunActionRunner(context context.Context, client client.Client, scheme *runtime.Scheme, cr runtime.Object) ActionRunner {
+	return &ClusterActionRunner{
+		client:  client,
+		context: context,
+		scheme:  scheme,
+		cr:      cr,
+		dryRun:  true,
+	}
+}
+
+// Create an action runner to run kubernetes and keycloak api actions. The changes
+// to clients, roles and secrets are recorded as events of the resource
+func NewClusterAndKeycloakActionRunner(context context.Context, client client.Client, scheme *runtime.Scheme, cr runtime.Object, keycloakClient KeycloakInterface, recorder record.EventRecorder) ActionRunner {
 	return &ClusterActionRunner{
 		client:         client,
 		context:        context,
 		scheme:         scheme,
 		cr:             cr,
 		keycloakClient: keycloakClient,
+		recorder:       recorder,
 	}
 }
 
 func (i *ClusterActionRunner) RunAll(desiredState DesiredClusterState) error {
+	setPendingActions(i.cr, len(desiredState))
+	if i.dryRun {
+		for index, action := range desiredState {
+			log.Info(fmt.Sprintf("(%5d) %10s %s", index, "DRY-RUN", action.Message()))
+		}
+		return nil
+	}
+
 	for index, action := range desiredState {
 		msg, err := action.Run(i)
 		if err != nil {
 			log.Info(fmt.Sprintf("(%5d) %10s %s", index, "FAILED", msg))
-			return err
+			i.recordEvent(corev1.EventTypeWarning, "ActionFailed", fmt.Sprintf("%v: %v", msg, err))
+			return &ActionError{Msg: action.Message(), err: err}
 		}
 		log.Info(fmt.Sprintf("(%5d) %10s %s", index, "SUCCESS", msg))
+		setPendingActions(i.cr, len(desiredState)-index-1)
+		if object, change := actionChange(action); change != "" {
+			countKeycloakChange(object, change)
+			i.recordEvent(corev1.EventTypeNormal, change, msg)
+		}
 	}
 
 	return nil
 }
 
+func (i *ClusterActionRunner) recordEvent(eventType, reason, message string) {
+	if i.recorder == nil {
+		return
+	}
+	i.recorder.Event(i.cr, eventType, reason, message)
+}
+
+// The object changed by an action and the change, also the reason of the event
+// recorded for it. The change is empty for the actions that aren't worth an event.
+// The client and its secret are updated on every reconcile, whether they changed or
+// not, and would flood the events of the resource. Actions are added both as values
+// and as pointers
+func actionChange(action ClusterAction) (string, string) {
+	switch a := action.(type) {
+	case CreateClientAction, *CreateClientAction:
+		return "client", "Created"
+	case DeleteClientAction, *DeleteClientAction:
+		return "client", "Deleted"
+	case CreateClientRoleAction, *CreateClientRoleAction, BulkClientRolesAction, *BulkClientRolesAction,
+		CreateRealmRoleAction, *CreateRealmRoleAction:
+		return "role", "Created"
+	case UpdateClientRoleAction, *UpdateClientRoleAction, UpdateRealmRoleAction, *UpdateRealmRoleAction:
+		return "role", "Updated"
+	case DeleteClientRoleAction, *DeleteClientRoleAction, DeleteRealmRoleAction, *DeleteRealmRoleAction:
+		return "role", "Deleted"
+	case RegenerateClientSecretAction, *RegenerateClientSecretAction, UpdateClientSecretAction, *UpdateClientSecretAction,
+		RegenerateRegistrationAccessTokenAction, *RegenerateRegistrationAccessTokenAction:
+		return "secret", "Updated"
+	case GenericCreateAction:
+		return secretChange(a.Ref, "Created")
+	case *GenericCreateAction:
+		return secretChange(a.Ref, "Created")
+	case GenericDeleteAction:
+		return secretChange(a.Ref, "Deleted")
+	case *GenericDeleteAction:
+		return secretChange(a.Ref, "Deleted")
+	}
+	return "", ""
+}
+
+// Only the Secrets among the kubernetes resources are worth an event
+func secretChange(ref runtime.Object, change string) (string, string) {
+	if _, ok := ref.(*corev1.Secret); ok {
+		return "secret", change
+	}
+	return "", ""
+}
+
+// The error of a failed action, along with the message of the action
+type ActionError struct {
+	Msg string
+	err error
+}
+
+func (e *ActionError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ActionError) Unwrap() error {
+	return e.err
+}
+
+// Status message of a resource whose actions were only logged
+func DryRunMessage(actions int) string {
+	return fmt.Sprintf("would reconcile %v actions", actions)
+}
+
+// Records in the status message how many actions a dry run only logged
+func ManageDryRun(ctx context.Context, controllerClient client.Client, obj runtime.Object, message *string, actions int) error {
+	*message = DryRunMessage(actions)
+	return controllerClient.Status().Update(ctx, obj)
+}
+
 func (i *ClusterActionRunner) Create(obj runtime.Object) error {
 	err := controllerutil.SetControllerReference(i.cr.(v1.Object), obj.(v1.Object), i.scheme)
 	if err != nil {
@@ -112,16 +297,133 @@ func (i *ClusterActionRunner) Update(obj runtime.Object) error {
 	return i.client.Update(i.context, obj)
 }
 
+func (i *ClusterActionRunner) Delete(obj runtime.Object) error {
+	err := i.client.Delete(i.context, obj)
+	if apiErrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
 // Create a new realm using the keycloak api
 func (i *ClusterActionRunner) CreateRealm(obj *v1alpha1.KeycloakRealm) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform realm create when client is nil")
 	}
 
-	_, err := i.keycloakClient.CreateRealm(obj)
+	// Identity providers, user federation providers and their mappers are created by
+	// their own actions, so that the secrets referenced by their config can be resolved
+	realm := obj.DeepCopy()
+	realm.Spec.Realm.IdentityProviders = nil
+	realm.Spec.Realm.IdentityProviderMappers = nil
+	realm.Spec.Realm.UserFederationProviders = nil
+	realm.Spec.Realm.UserFederationMappers = nil
+
+	_, err := i.keycloakClient.CreateRealm(realm)
 	return err
 }
 
+// Push a partial representation of the realm, attributes it doesn't set are kept
+func (i *ClusterActionRunner) UpdateRealm(obj *v1alpha1.KeycloakRealm, realm *v1alpha1.KeycloakAPIRealm) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm update when client is nil")
+	}
+	return i.keycloakClient.UpdateRealm(realm)
+}
+
+func (i *ClusterActionRunner) ConfigureRealmEvents(obj *v1alpha1.KeycloakRealm, config *v1alpha1.KeycloakAPIRealmEventsConfig) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm events configure when client is nil")
+	}
+	return i.keycloakClient.UpdateRealmEventsConfig(config, obj.Spec.Realm.Realm)
+}
+
+func (i *ClusterActionRunner) ImportRealm(obj *v1alpha1.KeycloakRealm, realmJSON []byte, ifResourceExists string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm import when client is nil")
+	}
+	return i.keycloakClient.ImportRealm(realmJSON, ifResourceExists, obj.Spec.Realm.Realm)
+}
+
+func (i *ClusterActionRunner) ImportRealmUsers(obj *v1alpha1.KeycloakRealm, users []*v1alpha1.KeycloakAPIUser) (*PartialImportResponse, error) {
+	if i.keycloakClient == nil {
+		return nil, errors.Errorf("cannot perform user import when client is nil")
+	}
+	return i.keycloakClient.ImportUsers(users, obj.Spec.Realm.Realm)
+}
+
+// Message overrides are set and removed one key at a time, as keycloak has no
+// endpoint to replace those of a locale
+func (i *ClusterActionRunner) UpdateRealmLocalization(obj *v1alpha1.KeycloakRealm, locale string, texts map[string]string, removed []string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm localization update when client is nil")
+	}
+
+	keys := make([]string, 0, len(texts))
+	for key := range texts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		err := i.keycloakClient.UpdateRealmLocalizationText(locale, key, texts[key], obj.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, key := range removed {
+		err := i.keycloakClient.DeleteRealmLocalizationText(locale, key, obj.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Required actions of providers keycloak doesn't have yet are registered first, the
+// update then applies the desired settings over the ones they were registered with
+func (i *ClusterActionRunner) UpdateRequiredAction(obj *v1alpha1.KeycloakRealm, action *v1alpha1.KeycloakAPIRequiredAction, register bool) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform required action update when client is nil")
+	}
+
+	if register {
+		name := action.Name
+		if name == "" {
+			name = action.Alias
+		}
+		err := i.keycloakClient.RegisterRequiredAction(action.Alias, name, obj.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+
+		current, err := i.keycloakClient.GetRequiredAction(action.Alias, obj.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return errors.Errorf("required action %v not found in realm %v after registering it", action.Alias, obj.Spec.Realm.Realm)
+		}
+		action = RequiredActionReconciled(action, current)
+	}
+
+	return i.keycloakClient.UpdateRequiredAction(action, obj.Spec.Realm.Realm)
+}
+
+func (i *ClusterActionRunner) UpdateClientProfiles(obj *v1alpha1.KeycloakRealm, profiles []v1alpha1.KeycloakAPIClientProfile) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client profiles update when client is nil")
+	}
+	return i.keycloakClient.UpdateClientProfiles(profiles, obj.Spec.Realm.Realm)
+}
+
+func (i *ClusterActionRunner) UpdateClientPolicies(obj *v1alpha1.KeycloakRealm, policies []v1alpha1.KeycloakAPIClientPolicy) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client policies update when client is nil")
+	}
+	return i.keycloakClient.UpdateClientPolicies(policies, obj.Spec.Realm.Realm)
+}
+
 func (i *ClusterActionRunner) CreateClient(obj *v1alpha1.KeycloakClient, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform client create when client is nil")
@@ -145,6 +447,45 @@ func (i *ClusterActionRunner) UpdateClient(obj *v1alpha1.KeycloakClient, realm s
 	return i.keycloakClient.UpdateClient(obj.Spec.Client, realm)
 }
 
+// Regenerates the secret in keycloak and writes it to the client Secret only. The
+// CR is left alone, the next reads take the secret from the client Secret instead
+func (i *ClusterActionRunner) RegenerateClientSecret(obj *v1alpha1.KeycloakClient, secret *corev1.Secret, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client secret regeneration when client is nil")
+	}
+
+	clientSecret, err := i.keycloakClient.RegenerateClientSecret(obj.Spec.Client.ID, realm)
+	if err != nil {
+		return err
+	}
+
+	obj.Spec.Client.Secret = clientSecret
+	// Updated in place, so that the token action after this one writes on top of it
+	model.ClientSecretRotated(obj, secret, time.Now()).DeepCopyInto(secret)
+	return i.Update(secret)
+}
+
+// The Secret is the one the actions before have created or updated, the cache
+// doesn't necessarily hold it yet
+func (i *ClusterActionRunner) RegenerateRegistrationAccessToken(obj *v1alpha1.KeycloakClient, secret *corev1.Secret, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform registration access token regeneration when client is nil")
+	}
+
+	token, err := i.keycloakClient.RegenerateRegistrationAccessToken(obj.Spec.Client.ID, realm)
+	if err != nil {
+		return err
+	}
+	return i.Update(model.ClientSecretWithRegistrationToken(obj, secret, token))
+}
+
+func (i *ClusterActionRunner) UpdateClientSecret(obj *v1alpha1.KeycloakClient, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client secret update when client is nil")
+	}
+	return i.keycloakClient.UpdateClientSecret(obj.Spec.Client.ID, obj.Spec.Client.Secret, realm)
+}
+
 func (i *ClusterActionRunner) CreateClientRole(obj *v1alpha1.KeycloakClient, role *v1alpha1.RoleRepresentation, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform client role create when client is nil")
@@ -153,6 +494,33 @@ func (i *ClusterActionRunner) CreateClientRole(obj *v1alpha1.KeycloakClient, rol
 	return err
 }
 
+// Creates all roles with a single partial import. Composites are added by their
+// own actions afterwards, same as for roles created one by one. Roles that exist
+// already, e.g. after an interrupted reconcile, are skipped and left as they are
+func (i *ClusterActionRunner) CreateClientRoles(obj *v1alpha1.KeycloakClient, roles []v1alpha1.RoleRepresentation, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client roles create when client is nil")
+	}
+
+	var newRoles v1alpha1.RoleRepresentationArray
+	for _, role := range roles {
+		newRole := role.DeepCopy()
+		newRole.ID = ""
+		newRole.Composite = nil
+		newRole.Composites = nil
+		newRoles = append(newRoles, *newRole)
+	}
+
+	return i.keycloakClient.PartialImport(&PartialImportRepresentation{
+		IfResourceExists: "SKIP",
+		Roles: &v1alpha1.RolesRepresentation{
+			Client: map[string]v1alpha1.RoleRepresentationArray{
+				obj.Spec.Client.ClientID: newRoles,
+			},
+		},
+	}, realm)
+}
+
 func (i *ClusterActionRunner) UpdateClientRole(obj *v1alpha1.KeycloakClient, role, oldRole *v1alpha1.RoleRepresentation, realm string) error {
 	if i.keycloakClient == nil {
 		return errors.Errorf("cannot perform client role update when client is nil")
@@ -167,162 +535,1259 @@ func (i *ClusterActionRunner) DeleteClientRole(obj *v1alpha1.KeycloakClient, rol
 	return i.keycloakClient.DeleteClientRole(obj.Spec.Client.ID, role, realm)
 }
 
-// Delete a realm using the keycloak api
-func (i *ClusterActionRunner) DeleteRealm(obj *v1alpha1.KeycloakRealm) error {
+func (i *ClusterActionRunner) AddClientRoleComposites(obj *v1alpha1.KeycloakClient, role *v1alpha1.RoleRepresentation, composites *v1alpha1.RoleRepresentationComposites, realm string) error {
 	if i.keycloakClient == nil {
-		return errors.Errorf("cannot perform realm delete when client is nil")
+		return errors.Errorf("cannot perform client role composites add when client is nil")
 	}
-	return i.keycloakClient.DeleteRealm(obj.Spec.Realm.Realm)
-}
 
-func (i *ClusterActionRunner) DeleteClient(obj *v1alpha1.KeycloakClient, realm string) error {
-	if i.keycloakClient == nil {
-		return errors.Errorf("cannot perform client delete when client is nil")
+	roles, err := i.resolveRoles(composites, realm)
+	if err != nil {
+		return err
 	}
-	return i.keycloakClient.DeleteClient(obj.Spec.Client.ID, realm)
+	return i.keycloakClient.CreateClientRoleComposites(obj.Spec.Client.ID, role.Name, roles, realm)
 }
 
-func (i *ClusterActionRunner) CreateUser(obj *v1alpha1.KeycloakUser, realm string) error {
+func (i *ClusterActionRunner) RemoveClientRoleComposites(obj *v1alpha1.KeycloakClient, role *v1alpha1.RoleRepresentation, composites *v1alpha1.RoleRepresentationComposites, realm string) error {
 	if i.keycloakClient == nil {
-		return errors.Errorf("cannot perform user create when client is nil")
+		return errors.Errorf("cannot perform client role composites remove when client is nil")
 	}
 
-	// Create the user
-	uid, err := i.keycloakClient.CreateUser(&obj.Spec.User, realm)
+	roles, err := i.resolveRoles(composites, realm)
 	if err != nil {
 		return err
 	}
+	return i.keycloakClient.DeleteClientRoleComposites(obj.Spec.Client.ID, role.Name, roles, realm)
+}
 
-	// Update newly created user with its uid
-	obj.Spec.User.ID = uid
-	return i.client.Update(i.context, obj)
+func (i *ClusterActionRunner) CreateIdentityProvider(obj *v1alpha1.KeycloakRealm, provider *v1alpha1.KeycloakIdentityProvider) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform identity provider create when client is nil")
+	}
+	_, err := i.keycloakClient.CreateIdentityProvider(provider, obj.Spec.Realm.Realm)
+	return err
 }
 
-func (i *ClusterActionRunner) UpdateUser(obj *v1alpha1.KeycloakUser, realm string) error {
+func (i *ClusterActionRunner) UpdateIdentityProvider(obj *v1alpha1.KeycloakRealm, provider *v1alpha1.KeycloakIdentityProvider) error {
 	if i.keycloakClient == nil {
-		return errors.Errorf("cannot perform user update when client is nil")
+		return errors.Errorf("cannot perform identity provider update when client is nil")
 	}
+	return i.keycloakClient.UpdateIdentityProvider(provider, obj.Spec.Realm.Realm)
+}
 
-	err := i.keycloakClient.UpdateUser(&obj.Spec.User, realm)
+func (i *ClusterActionRunner) DeleteIdentityProvider(obj *v1alpha1.KeycloakRealm, alias string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform identity provider delete when client is nil")
+	}
+	return i.keycloakClient.DeleteIdentityProvider(alias, obj.Spec.Realm.Realm)
+}
+
+// The identity provider is looked up first, as keycloak doesn't tell a missing
+// provider apart from other failures
+func (i *ClusterActionRunner) CreateIdentityProviderMapper(obj *v1alpha1.KeycloakRealm, mapper *v1alpha1.KeycloakIdentityProviderMapper) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform identity provider mapper create when client is nil")
+	}
+
+	provider, err := i.keycloakClient.GetIdentityProvider(mapper.IdentityProviderAlias, obj.Spec.Realm.Realm)
 	if err != nil {
 		return err
 	}
+	if provider == nil {
+		return errors.Errorf("identity provider %v of mapper %v not found in realm %v", mapper.IdentityProviderAlias, mapper.Name, obj.Spec.Realm.Realm)
+	}
 
-	return nil
+	_, err = i.keycloakClient.CreateIdentityProviderMapper(mapper, obj.Spec.Realm.Realm)
+	return err
 }
 
-func (i *ClusterActionRunner) DeleteUser(id, realm string) error {
+func (i *ClusterActionRunner) UpdateIdentityProviderMapper(obj *v1alpha1.KeycloakRealm, mapper *v1alpha1.KeycloakIdentityProviderMapper) error {
 	if i.keycloakClient == nil {
-		return errors.Errorf("cannot perform user delete when client is nil")
+		return errors.Errorf("cannot perform identity provider mapper update when client is nil")
 	}
-	return i.keycloakClient.DeleteUser(id, realm)
+	return i.keycloakClient.UpdateIdentityProviderMapper(mapper, obj.Spec.Realm.Realm)
 }
 
-// Check if Keycloak is available
-func (i *ClusterActionRunner) Ping() error {
+func (i *ClusterActionRunner) DeleteIdentityProviderMapper(obj *v1alpha1.KeycloakRealm, mapper *v1alpha1.KeycloakIdentityProviderMapper) error {
 	if i.keycloakClient == nil {
-		return errors.Errorf("cannot perform keycloak ping when client is nil")
+		return errors.Errorf("cannot perform identity provider mapper delete when client is nil")
 	}
-	return i.keycloakClient.Ping()
+	return i.keycloakClient.DeleteIdentityProviderMapper(mapper.IdentityProviderAlias, mapper.ID, obj.Spec.Realm.Realm)
 }
 
-func (i *ClusterActionRunner) AssignRealmRole(obj *v1alpha1.KeycloakUserRole, userID, realm string) error {
+// User federation providers are children of the realm, whose ID may only be known once it exists
+func (i *ClusterActionRunner) CreateUserFederationProvider(obj *v1alpha1.KeycloakRealm, provider *v1alpha1.KeycloakAPIComponent) error {
 	if i.keycloakClient == nil {
-		return errors.Errorf("cannot perform role assign when client is nil")
+		return errors.Errorf("cannot perform user federation provider create when client is nil")
 	}
 
-	_, err := i.keycloakClient.CreateUserRealmRole(obj, realm, userID)
+	component := provider.DeepCopy()
+	if component.ParentID == "" {
+		realmID, err := i.getRealmID(obj)
+		if err != nil {
+			return err
+		}
+		component.ParentID = realmID
+	}
+
+	_, err := i.keycloakClient.CreateComponent(component, obj.Spec.Realm.Realm)
 	return err
 }
 
-func (i *ClusterActionRunner) RemoveRealmRole(obj *v1alpha1.KeycloakUserRole, userID, realm string) error {
+func (i *ClusterActionRunner) UpdateUserFederationProvider(obj *v1alpha1.KeycloakRealm, provider *v1alpha1.KeycloakAPIComponent) error {
 	if i.keycloakClient == nil {
-		return errors.Errorf("cannot perform role remove when client is nil")
+		return errors.Errorf("cannot perform user federation provider update when client is nil")
 	}
-	return i.keycloakClient.DeleteUserRealmRole(obj, realm, userID)
+	return i.keycloakClient.UpdateComponent(provider, obj.Spec.Realm.Realm)
 }
 
-func (i *ClusterActionRunner) AssignClientRole(obj *v1alpha1.KeycloakUserRole, clientID, userID, realm string) error {
+func (i *ClusterActionRunner) DeleteUserFederationProvider(obj *v1alpha1.KeycloakRealm, provider *v1alpha1.KeycloakAPIComponent) error {
 	if i.keycloakClient == nil {
-		return errors.Errorf("cannot perform role assign when client is nil")
+		return errors.Errorf("cannot perform user federation provider delete when client is nil")
 	}
+	return i.keycloakClient.DeleteComponent(provider.ID, obj.Spec.Realm.Realm)
+}
 
-	_, err := i.keycloakClient.CreateUserClientRole(obj, realm, clientID, userID)
+// Keycloak adds default mappers to new LDAP providers, a mapper of the same name is
+// updated instead of created next to it
+func (i *ClusterActionRunner) CreateUserFederationMapper(obj *v1alpha1.KeycloakRealm, mapper *v1alpha1.KeycloakAPIComponent, providerName string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform user federation mapper create when client is nil")
+	}
+
+	realmID, err := i.getRealmID(obj)
+	if err != nil {
+		return err
+	}
+	providers, err := i.keycloakClient.ListComponents(realmID, model.UserStorageProviderType, obj.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+	provider := findComponent(providers, providerName)
+	if provider == nil {
+		return errors.Errorf("user federation provider %v of mapper %v not found in realm %v", providerName, mapper.Name, obj.Spec.Realm.Realm)
+	}
+
+	component := mapper.DeepCopy()
+	component.ParentID = provider.ID
+
+	mappers, err := i.keycloakClient.ListComponents(provider.ID, model.LDAPStorageMapperType, obj.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+	if existing := findComponent(mappers, mapper.Name); existing != nil {
+		component.ID = existing.ID
+		return i.keycloakClient.UpdateComponent(component, obj.Spec.Realm.Realm)
+	}
+
+	_, err = i.keycloakClient.CreateComponent(component, obj.Spec.Realm.Realm)
 	return err
 }
 
-func (i *ClusterActionRunner) RemoveClientRole(obj *v1alpha1.KeycloakUserRole, clientID, userID, realm string) error {
+func (i *ClusterActionRunner) UpdateUserFederationMapper(obj *v1alpha1.KeycloakRealm, mapper *v1alpha1.KeycloakAPIComponent) error {
 	if i.keycloakClient == nil {
-		return errors.Errorf("cannot perform role remove when client is nil")
+		return errors.Errorf("cannot perform user federation mapper update when client is nil")
 	}
-	return i.keycloakClient.DeleteUserClientRole(obj, realm, clientID, userID)
+	return i.keycloakClient.UpdateComponent(mapper, obj.Spec.Realm.Realm)
 }
 
-// Delete a realm using the keycloak api
-func (i *ClusterActionRunner) ApplyOverrides(obj *v1alpha1.KeycloakRealm) error {
+func (i *ClusterActionRunner) DeleteUserFederationMapper(obj *v1alpha1.KeycloakRealm, mapper *v1alpha1.KeycloakAPIComponent) error {
 	if i.keycloakClient == nil {
-		return errors.Errorf("cannot perform realm configure when client is nil")
+		return errors.Errorf("cannot perform user federation mapper delete when client is nil")
 	}
+	return i.keycloakClient.DeleteComponent(mapper.ID, obj.Spec.Realm.Realm)
+}
 
-	for _, override := range obj.Spec.RealmOverrides {
-		err := i.configureBrowserRedirector(override.IdentityProvider, override.ForFlow, obj)
-		if err != nil {
-			return err
-		}
+// Sync a user federation provider, given by display name or ID, then remove the
+// annotation that asked for it so that adding it again triggers another sync
+func (i *ClusterActionRunner) SyncUserFederationProvider(obj *v1alpha1.KeycloakRealm, provider string, full bool) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform user federation provider sync when client is nil")
 	}
 
-	return nil
-}
-
-func (i *ClusterActionRunner) configureBrowserRedirector(provider, flow string, obj *v1alpha1.KeycloakRealm) error {
-	realmName := obj.Spec.Realm.Realm
-	authenticationExecutionInfo, err := i.keycloakClient.ListAuthenticationExecutionsForFlow(flow, realmName)
+	realmID, err := i.getRealmID(obj)
 	if err != nil {
 		return err
 	}
-
-	authenticationConfigID := ""
-	redirectorExecutionID := ""
-	for _, execution := range authenticationExecutionInfo {
-		if execution.ProviderID == "identity-provider-redirector" {
-			authenticationConfigID = execution.AuthenticationConfig
-			redirectorExecutionID = execution.ID
+	providers, err := i.keycloakClient.ListComponents(realmID, model.UserStorageProviderType, obj.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+	component := findComponent(providers, provider)
+	if component == nil {
+		for index := range providers {
+			if providers[index].ID == provider {
+				component = &providers[index]
+			}
 		}
 	}
-	if redirectorExecutionID == "" {
-		return errors.Errorf("'identity-provider-redirector' was not found in the list of executions of the 'browser' flow")
+	if component == nil {
+		return errors.Errorf("user federation provider %v not found in realm %v", provider, obj.Spec.Realm.Realm)
 	}
 
-	var authenticatorConfig *v1alpha1.AuthenticatorConfig
-	if authenticationConfigID != "" {
-		authenticatorConfig, err = i.keycloakClient.GetAuthenticatorConfig(authenticationConfigID, realmName)
-		if err != nil {
-			return err
-		}
+	result, err := i.keycloakClient.SyncUserFederationProvider(component.ID, full, obj.Spec.Realm.Realm)
+	if err != nil {
+		return err
 	}
 
-	if authenticatorConfig == nil && provider != "" {
-		config := &v1alpha1.AuthenticatorConfig{
-			Alias:  authenticationConfigAlias,
-			Config: map[string]string{"defaultProvider": provider},
-		}
-
-		if _, err := i.keycloakClient.CreateAuthenticatorConfig(config, realmName, redirectorExecutionID); err != nil {
-			return err
-		}
-		return nil
+	err = i.removeAnnotations(obj, model.SyncUserFederationAnnotation, model.SyncUserFederationModeAnnotation)
+	if err != nil {
+		return err
 	}
 
+	if obj.Status.UserFederationSyncResults == nil {
+		obj.Status.UserFederationSyncResults = make(map[string]v1alpha1.KeycloakAPISynchronizationResult)
+	}
+	obj.Status.UserFederationSyncResults[component.Name] = *result
 	return nil
 }
 
-// An action to create generic kubernetes resources
-// (resources that don't require special treatment)
-type GenericCreateAction struct {
-	Ref runtime.Object
-	Msg string
-}
-
+// Export the realm to the given secret, then remove the annotations that asked for it
+// so that adding them again triggers another export. The secret isn't owned by the
+// realm, so that the export outlives it
+func (i *ClusterActionRunner) ExportRealm(obj *v1alpha1.KeycloakRealm, secretName string, exportClients, exportGroupsAndRoles bool) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm export when client is nil")
+	}
+
+	realmJSON, err := i.keycloakClient.ExportRealm(obj.Spec.Realm.Realm, exportClients, exportGroupsAndRoles)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{}
+	err = i.client.Get(i.context, model.RealmExportSecretSelector(obj, secretName), secret)
+	switch {
+	case apiErrors.IsNotFound(err):
+		err = i.client.Create(i.context, model.RealmExportSecret(obj, secretName, realmJSON))
+	case err == nil:
+		if !model.IsRealmExportSecretWritable(obj, secret) {
+			return errors.Errorf("secret %v/%v is not overwritten with the export of realm %v, it needs the label app=%v or to be owned by the KeycloakRealm",
+				secret.Namespace, secret.Name, obj.Spec.Realm.Realm, model.ApplicationName)
+		}
+		err = i.client.Update(i.context, model.RealmExportSecretReconciled(secret, realmJSON))
+	}
+	if err != nil {
+		return err
+	}
+
+	err = i.removeAnnotations(obj, model.ExportRealmAnnotation, model.ExportRealmClientsAnnotation, model.ExportRealmGroupsAndRolesAnnotation)
+	if err != nil {
+		return err
+	}
+
+	obj.Status.LastExportTime = time.Now().UTC().Format(time.RFC3339)
+	obj.Status.LastExportSecret = secretName
+	return nil
+}
+
+// Remove the given annotations with a merge patch, which leaves the rest of the CR
+// alone. The patch is applied to a copy, since its response carries the stored status
+// and would drop what the other actions recorded on obj
+func (i *ClusterActionRunner) removeAnnotations(obj runtime.Object, keys ...string) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	annotations := accessor.GetAnnotations()
+	removed := make(map[string]interface{})
+	for _, key := range keys {
+		if _, ok := annotations[key]; ok {
+			removed[key] = nil
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": removed},
+	})
+	if err != nil {
+		return err
+	}
+	err = i.client.Patch(i.context, obj.DeepCopyObject(), client.RawPatch(types.MergePatchType, patch))
+	if err != nil {
+		return err
+	}
+
+	for key := range removed {
+		delete(annotations, key)
+	}
+	accessor.SetAnnotations(annotations)
+	return nil
+}
+
+func (i *ClusterActionRunner) getRealmID(obj *v1alpha1.KeycloakRealm) (string, error) {
+	realm, err := i.keycloakClient.GetRealm(obj.Spec.Realm.Realm)
+	if err != nil {
+		return "", err
+	}
+	if realm == nil {
+		return "", errors.Errorf("realm %v not found", obj.Spec.Realm.Realm)
+	}
+	return realm.Spec.Realm.ID, nil
+}
+
+func findComponent(components []v1alpha1.KeycloakAPIComponent, name string) *v1alpha1.KeycloakAPIComponent {
+	for index := range components {
+		if components[index].Name == name {
+			return &components[index]
+		}
+	}
+	return nil
+}
+
+// Replace an authentication flow as a whole, or create it when there is no existing flow
+func (i *ClusterActionRunner) ReplaceAuthenticationFlow(obj *v1alpha1.KeycloakRealm, flow, existing *v1alpha1.KeycloakAPIAuthenticationFlow) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authentication flow replace when client is nil")
+	}
+
+	bindings := map[string]string{}
+	if existing != nil {
+		// Keycloak refuses to delete a bound flow, so its bindings are moved to the
+		// built in flows until the flow is created again
+		realm, err := i.keycloakClient.GetRealm(obj.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+		if realm != nil && realm.Spec.Realm.BrowserFlow == existing.Alias {
+			bindings["browserFlow"] = existing.Alias
+		}
+		if realm != nil && realm.Spec.Realm.DirectGrantFlow == existing.Alias {
+			bindings["directGrantFlow"] = existing.Alias
+		}
+		if len(bindings) > 0 {
+			err = i.keycloakClient.UpdateRealmFlowBindings(builtInFlowBindings(bindings), obj.Spec.Realm.Realm)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = i.keycloakClient.DeleteAuthenticationFlow(existing.ID, obj.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+	}
+
+	topLevel := flow.DeepCopy()
+	topLevel.ID = ""
+	topLevel.AuthenticationExecutions = nil
+	_, err := i.keycloakClient.CreateAuthenticationFlow(topLevel, obj.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	err = i.createAuthenticationExecutions(obj, flow)
+	if err != nil {
+		return err
+	}
+
+	if len(bindings) > 0 {
+		return i.keycloakClient.UpdateRealmFlowBindings(bindings, obj.Spec.Realm.Realm)
+	}
+	return nil
+}
+
+// Add the executions of a flow in priority order, then set their requirements and
+// configs, recursing into sub flows
+func (i *ClusterActionRunner) createAuthenticationExecutions(obj *v1alpha1.KeycloakRealm, flow *v1alpha1.KeycloakAPIAuthenticationFlow) error {
+	realmName := obj.Spec.Realm.Realm
+	executions := SortedAuthenticationExecutions(flow.AuthenticationExecutions)
+
+	for _, execution := range executions {
+		var err error
+		if execution.AuthenticatorFlow {
+			subFlow := FindAuthenticationFlow(obj.Spec.Realm.AuthenticationFlows, execution.FlowAlias)
+			if subFlow == nil {
+				return errors.Errorf("sub flow %v of authentication flow %v not found", execution.FlowAlias, flow.Alias)
+			}
+			_, err = i.keycloakClient.CreateAuthenticationSubFlow(flow.Alias, subFlow, realmName)
+		} else {
+			_, err = i.keycloakClient.CreateAuthenticationExecution(flow.Alias, execution.Authenticator, realmName)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	infos, err := i.keycloakClient.ListAuthenticationExecutionsForFlow(flow.Alias, realmName)
+	if err != nil {
+		return err
+	}
+	var created []*v1alpha1.AuthenticationExecutionInfo
+	for _, info := range infos {
+		if info.Level == 0 {
+			created = append(created, info)
+		}
+	}
+	if len(created) != len(executions) {
+		return errors.Errorf("expected %v executions in authentication flow %v, found %v", len(executions), flow.Alias, len(created))
+	}
+
+	for index, execution := range executions {
+		info := created[index]
+		if requirement := AuthenticationExecutionRequirement(execution); info.Requirement != requirement {
+			info.Requirement = requirement
+			err = i.keycloakClient.UpdateAuthenticationExecution(flow.Alias, info, realmName)
+			if err != nil {
+				return err
+			}
+		}
+
+		if execution.AuthenticatorConfig != "" {
+			config := FindAuthenticatorConfig(obj.Spec.Realm.AuthenticatorConfig, execution.AuthenticatorConfig)
+			if config == nil {
+				return errors.Errorf("authenticator config %v of authentication flow %v not found", execution.AuthenticatorConfig, flow.Alias)
+			}
+			_, err = i.keycloakClient.CreateAuthenticatorConfig(&v1alpha1.AuthenticatorConfig{
+				Alias:  config.Alias,
+				Config: config.Config,
+			}, realmName, info.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if execution.AuthenticatorFlow {
+			err = i.createAuthenticationExecutions(obj, FindAuthenticationFlow(obj.Spec.Realm.AuthenticationFlows, execution.FlowAlias))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (i *ClusterActionRunner) DeleteAuthenticationFlow(obj *v1alpha1.KeycloakRealm, flow *v1alpha1.KeycloakAPIAuthenticationFlow) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform authentication flow delete when client is nil")
+	}
+	return i.keycloakClient.DeleteAuthenticationFlow(flow.ID, obj.Spec.Realm.Realm)
+}
+
+// Bind the flows given in the CR to the realm
+func (i *ClusterActionRunner) UpdateRealmFlowBindings(obj *v1alpha1.KeycloakRealm) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm flow bindings update when client is nil")
+	}
+
+	bindings := map[string]string{}
+	if obj.Spec.Realm.BrowserFlow != "" {
+		bindings["browserFlow"] = obj.Spec.Realm.BrowserFlow
+	}
+	if obj.Spec.Realm.DirectGrantFlow != "" {
+		bindings["directGrantFlow"] = obj.Spec.Realm.DirectGrantFlow
+	}
+	return i.keycloakClient.UpdateRealmFlowBindings(bindings, obj.Spec.Realm.Realm)
+}
+
+func builtInFlowBindings(bindings map[string]string) map[string]string {
+	builtIn := map[string]string{}
+	for binding := range bindings {
+		switch binding {
+		case "browserFlow":
+			builtIn[binding] = "browser"
+		case "directGrantFlow":
+			builtIn[binding] = "direct grant"
+		}
+	}
+	return builtIn
+}
+
+func (i *ClusterActionRunner) CreateRealmRole(obj *v1alpha1.KeycloakRealm, role *v1alpha1.RoleRepresentation) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm role create when client is nil")
+	}
+	_, err := i.keycloakClient.CreateRealmRole(role, obj.Spec.Realm.Realm)
+	return err
+}
+
+func (i *ClusterActionRunner) UpdateRealmRole(obj *v1alpha1.KeycloakRealm, role, oldRole *v1alpha1.RoleRepresentation) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm role update when client is nil")
+	}
+	return i.keycloakClient.UpdateRealmRole(role, oldRole, obj.Spec.Realm.Realm)
+}
+
+func (i *ClusterActionRunner) DeleteRealmRole(obj *v1alpha1.KeycloakRealm, role string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm role delete when client is nil")
+	}
+	return i.keycloakClient.DeleteRealmRole(role, obj.Spec.Realm.Realm)
+}
+
+// Groups with an ID are moved under the parent, new groups are created there. The
+// parent is looked up by path, as it may have been created in the same pass
+func (i *ClusterActionRunner) CreateGroup(obj *v1alpha1.KeycloakRealm, group *v1alpha1.KeycloakAPIGroup, parentPath string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform group create when client is nil")
+	}
+
+	newGroup := groupWithoutChildren(group)
+	if parentPath == "" {
+		_, err := i.keycloakClient.CreateGroup(newGroup, obj.Spec.Realm.Realm)
+		return err
+	}
+
+	parent, err := i.resolveGroup(parentPath, obj.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+	_, err = i.keycloakClient.CreateChildGroup(newGroup, parent.ID, obj.Spec.Realm.Realm)
+	return err
+}
+
+func (i *ClusterActionRunner) UpdateGroup(obj *v1alpha1.KeycloakRealm, group *v1alpha1.KeycloakAPIGroup) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform group update when client is nil")
+	}
+	return i.keycloakClient.UpdateGroup(groupWithoutChildren(group), obj.Spec.Realm.Realm)
+}
+
+func (i *ClusterActionRunner) DeleteGroup(obj *v1alpha1.KeycloakRealm, group *v1alpha1.KeycloakAPIGroup) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform group delete when client is nil")
+	}
+	return i.keycloakClient.DeleteGroup(group.ID, obj.Spec.Realm.Realm)
+}
+
+func (i *ClusterActionRunner) AddGroupRoleMappings(obj *v1alpha1.KeycloakRealm, path string, mappings *v1alpha1.RoleRepresentationComposites) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform group role mappings add when client is nil")
+	}
+	return i.updateGroupRoleMappings(obj, path, mappings, i.keycloakClient.CreateGroupRealmRoleMappings, i.keycloakClient.CreateGroupClientRoleMappings)
+}
+
+func (i *ClusterActionRunner) RemoveGroupRoleMappings(obj *v1alpha1.KeycloakRealm, path string, mappings *v1alpha1.RoleRepresentationComposites) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform group role mappings remove when client is nil")
+	}
+	return i.updateGroupRoleMappings(obj, path, mappings, i.keycloakClient.DeleteGroupRealmRoleMappings, i.keycloakClient.DeleteGroupClientRoleMappings)
+}
+
+func (i *ClusterActionRunner) updateGroupRoleMappings(obj *v1alpha1.KeycloakRealm, path string, mappings *v1alpha1.RoleRepresentationComposites,
+	updateRealmRoles func(groupID string, roles []v1alpha1.RoleRepresentation, realmName string) error,
+	updateClientRoles func(groupID, clientID string, roles []v1alpha1.RoleRepresentation, realmName string) error) error {
+	realm := obj.Spec.Realm.Realm
+
+	group, err := i.resolveGroup(path, realm)
+	if err != nil {
+		return err
+	}
+	return i.updateRoleMappings(group.ID, mappings, realm, updateRealmRoles, updateClientRoles)
+}
+
+// The mappings of realm roles and of the roles of each client go to separate endpoints
+func (i *ClusterActionRunner) updateRoleMappings(id string, mappings *v1alpha1.RoleRepresentationComposites, realm string,
+	updateRealmRoles func(id string, roles []v1alpha1.RoleRepresentation, realmName string) error,
+	updateClientRoles func(id, clientID string, roles []v1alpha1.RoleRepresentation, realmName string) error) error {
+	roles, err := i.resolveRoles(mappings, realm)
+	if err != nil {
+		return err
+	}
+
+	var realmRoles []v1alpha1.RoleRepresentation
+	clientRoles := make(map[string][]v1alpha1.RoleRepresentation)
+	var clientIDs []string
+	for _, role := range roles {
+		if role.ClientRole == nil || !*role.ClientRole {
+			realmRoles = append(realmRoles, role)
+			continue
+		}
+		if _, ok := clientRoles[role.ContainerID]; !ok {
+			clientIDs = append(clientIDs, role.ContainerID)
+		}
+		clientRoles[role.ContainerID] = append(clientRoles[role.ContainerID], role)
+	}
+
+	if len(realmRoles) > 0 {
+		err = updateRealmRoles(id, realmRoles, realm)
+		if err != nil {
+			return err
+		}
+	}
+	for _, clientID := range clientIDs {
+		err = updateClientRoles(id, clientID, clientRoles[clientID], realm)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *ClusterActionRunner) AddScopeMappings(obj *v1alpha1.KeycloakClient, mappings *v1alpha1.RoleRepresentationComposites, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform scope mappings add when client is nil")
+	}
+	return i.updateRoleMappings(obj.Spec.Client.ID, mappings, realm, i.keycloakClient.CreateClientRealmScopeMappings, i.keycloakClient.CreateClientClientScopeMappings)
+}
+
+func (i *ClusterActionRunner) RemoveScopeMappings(obj *v1alpha1.KeycloakClient, mappings *v1alpha1.RoleRepresentationComposites, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform scope mappings remove when client is nil")
+	}
+	return i.updateRoleMappings(obj.Spec.Client.ID, mappings, realm, i.keycloakClient.DeleteClientRealmScopeMappings, i.keycloakClient.DeleteClientClientScopeMappings)
+}
+
+func (i *ClusterActionRunner) AddRealmDefaultRoles(obj *v1alpha1.KeycloakClient, roles []string, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm default roles add when client is nil")
+	}
+
+	composites, err := i.resolveRoles(&v1alpha1.RoleRepresentationComposites{
+		Client: map[string][]string{obj.Spec.Client.ClientID: roles},
+	}, realm)
+	if err != nil {
+		return err
+	}
+	return i.keycloakClient.CreateRealmRoleComposites(RealmDefaultRolesName(realm), composites, realm)
+}
+
+func (i *ClusterActionRunner) RemoveRealmDefaultRoles(obj *v1alpha1.KeycloakClient, roles []string, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm default roles remove when client is nil")
+	}
+
+	composites, err := i.resolveRoles(&v1alpha1.RoleRepresentationComposites{
+		Client: map[string][]string{obj.Spec.Client.ClientID: roles},
+	}, realm)
+	if err != nil {
+		return err
+	}
+	return i.keycloakClient.DeleteRealmRoleComposites(RealmDefaultRolesName(realm), composites, realm)
+}
+
+func (i *ClusterActionRunner) resolveGroup(path, realm string) (*v1alpha1.KeycloakUserGroup, error) {
+	group, err := i.keycloakClient.GetGroupByPath(path, realm)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, errors.Errorf("group %v not found in realm %v", path, realm)
+	}
+	return group, nil
+}
+
+// Sub groups and role mappings are managed by their own actions
+func groupWithoutChildren(group *v1alpha1.KeycloakAPIGroup) *v1alpha1.KeycloakAPIGroup {
+	result := group.DeepCopy()
+	result.SubGroups = nil
+	result.RealmRoles = nil
+	result.ClientRoles = nil
+	return result
+}
+
+func (i *ClusterActionRunner) SetDefaultGroup(obj *v1alpha1.KeycloakRealm, path string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform default group set when client is nil")
+	}
+
+	group, err := i.resolveGroup(path, obj.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+	return i.keycloakClient.AddDefaultGroup(group.ID, obj.Spec.Realm.Realm)
+}
+
+func (i *ClusterActionRunner) UnsetDefaultGroup(obj *v1alpha1.KeycloakRealm, group *v1alpha1.KeycloakUserGroup) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform default group unset when client is nil")
+	}
+	return i.keycloakClient.RemoveDefaultGroup(group.ID, obj.Spec.Realm.Realm)
+}
+
+// Scopes the realm didn't have when the state was read are looked up by name again,
+// they may have been created since
+func (i *ClusterActionRunner) AddRealmDefaultClientScope(obj *v1alpha1.KeycloakRealm, scope *v1alpha1.KeycloakAPIClientScope, optional bool) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm default client scope add when client is nil")
+	}
+
+	scopeID := scope.ID
+	if scopeID == "" {
+		scopes, err := i.keycloakClient.ListClientScopes(obj.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+		for _, existing := range scopes {
+			if existing.Name == scope.Name {
+				scopeID = existing.ID
+			}
+		}
+		if scopeID == "" {
+			return errors.Errorf("client scope %v not found in realm %v", scope.Name, obj.Spec.Realm.Realm)
+		}
+	}
+	return i.keycloakClient.AddRealmDefaultClientScope(scopeID, optional, obj.Spec.Realm.Realm)
+}
+
+func (i *ClusterActionRunner) RemoveRealmDefaultClientScope(obj *v1alpha1.KeycloakRealm, scope *v1alpha1.KeycloakAPIClientScope, optional bool) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm default client scope remove when client is nil")
+	}
+	return i.keycloakClient.RemoveRealmDefaultClientScope(scope.ID, optional, obj.Spec.Realm.Realm)
+}
+
+func (i *ClusterActionRunner) AddRealmRoleComposites(obj *v1alpha1.KeycloakRealm, role *v1alpha1.RoleRepresentation, composites *v1alpha1.RoleRepresentationComposites) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm role composites add when client is nil")
+	}
+
+	roles, err := i.resolveRoles(composites, obj.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+	return i.keycloakClient.CreateRealmRoleComposites(role.Name, roles, obj.Spec.Realm.Realm)
+}
+
+func (i *ClusterActionRunner) RemoveRealmRoleComposites(obj *v1alpha1.KeycloakRealm, role *v1alpha1.RoleRepresentation, composites *v1alpha1.RoleRepresentationComposites) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm role composites remove when client is nil")
+	}
+
+	roles, err := i.resolveRoles(composites, obj.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+	return i.keycloakClient.DeleteRealmRoleComposites(role.Name, roles, obj.Spec.Realm.Realm)
+}
+
+// Composites and role mappings are declared by name, but keycloak needs the role
+// IDs. They are only looked up when the action runs, so that roles created earlier
+// in the same reconcile pass can be referenced
+func (i *ClusterActionRunner) resolveRoles(composites *v1alpha1.RoleRepresentationComposites, realm string) ([]v1alpha1.RoleRepresentation, error) {
+	var roles []v1alpha1.RoleRepresentation
+
+	for _, name := range composites.Realm {
+		role, err := i.keycloakClient.GetRealmRole(name, realm)
+		if err != nil {
+			return nil, err
+		}
+		if role == nil {
+			return nil, errors.Errorf("realm role %v not found", name)
+		}
+		roles = append(roles, *role)
+	}
+
+	if len(composites.Client) == 0 {
+		return roles, nil
+	}
+
+	clients, err := i.keycloakClient.ListClients(realm)
+	if err != nil {
+		return nil, err
+	}
+
+	for clientID, names := range composites.Client {
+		var client *v1alpha1.KeycloakAPIClient
+		for _, c := range clients {
+			if c.ClientID == clientID {
+				client = c
+				break
+			}
+		}
+		if client == nil {
+			return nil, errors.Errorf("client %v of client roles not found", clientID)
+		}
+
+		for _, name := range names {
+			role, err := i.keycloakClient.GetClientRole(client.ID, name, realm)
+			if err != nil {
+				return nil, err
+			}
+			if role == nil {
+				return nil, errors.Errorf("client role %v/%v not found", clientID, name)
+			}
+			roles = append(roles, *role)
+		}
+	}
+
+	return roles, nil
+}
+
+func (i *ClusterActionRunner) CreateClientProtocolMapper(obj *v1alpha1.KeycloakClient, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client protocol mapper create when client is nil")
+	}
+	_, err := i.keycloakClient.CreateClientProtocolMapper(obj.Spec.Client.ID, mapper, realm)
+	return err
+}
+
+func (i *ClusterActionRunner) UpdateClientProtocolMapper(obj *v1alpha1.KeycloakClient, mapper, oldMapper *v1alpha1.KeycloakProtocolMapper, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client protocol mapper update when client is nil")
+	}
+	return i.keycloakClient.UpdateClientProtocolMapper(obj.Spec.Client.ID, mapper, oldMapper, realm)
+}
+
+func (i *ClusterActionRunner) DeleteClientProtocolMapper(obj *v1alpha1.KeycloakClient, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client protocol mapper delete when client is nil")
+	}
+	return i.keycloakClient.DeleteClientProtocolMapper(obj.Spec.Client.ID, mapper.ID, realm)
+}
+
+// Imports the desired settings and then removes whatever is left of the current ones,
+// so that the settings are replaced as a whole. Policies go first, as permissions
+// reference resources and scopes
+func (i *ClusterActionRunner) UpdateClientAuthorizationSettings(obj *v1alpha1.KeycloakClient, current *v1alpha1.ResourceServerRepresentation, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client authorization settings update when client is nil")
+	}
+
+	desired := obj.Spec.Client.AuthorizationSettings
+	err := i.keycloakClient.ImportClientAuthorizationSettings(obj.Spec.Client.ID, desired, realm)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		return nil
+	}
+
+	for _, policy := range current.Policies {
+		if !hasPolicy(desired.Policies, policy.Name) {
+			err = i.keycloakClient.DeleteClientAuthorizationPolicy(obj.Spec.Client.ID, policy.ID, realm)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, resource := range current.Resources {
+		if !hasResource(desired.Resources, resource.Name) {
+			err = i.keycloakClient.DeleteClientAuthorizationResource(obj.Spec.Client.ID, resource.ID, realm)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, scope := range current.Scopes {
+		if !hasScope(desired.Scopes, scope.Name) {
+			err = i.keycloakClient.DeleteClientAuthorizationScope(obj.Spec.Client.ID, scope.ID, realm)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func hasPolicy(policies []v1alpha1.PolicyRepresentation, name string) bool {
+	for _, policy := range policies {
+		if policy.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasResource(resources []v1alpha1.ResourceRepresentation, name string) bool {
+	for _, resource := range resources {
+		if resource.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasScope(scopes []v1alpha1.ScopeRepresentation, name string) bool {
+	for _, scope := range scopes {
+		if scope.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *ClusterActionRunner) AddDefaultClientScope(obj *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakAPIClientScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform default client scope add when client is nil")
+	}
+
+	scopeID, err := i.resolveClientScope(clientScope, realm)
+	if err != nil {
+		return err
+	}
+	return i.keycloakClient.AddDefaultClientScope(obj.Spec.Client.ID, scopeID, realm)
+}
+
+func (i *ClusterActionRunner) RemoveDefaultClientScope(obj *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakAPIClientScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform default client scope remove when client is nil")
+	}
+	return i.keycloakClient.RemoveDefaultClientScope(obj.Spec.Client.ID, clientScope.ID, realm)
+}
+
+func (i *ClusterActionRunner) AddOptionalClientScope(obj *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakAPIClientScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform optional client scope add when client is nil")
+	}
+
+	scopeID, err := i.resolveClientScope(clientScope, realm)
+	if err != nil {
+		return err
+	}
+	return i.keycloakClient.AddOptionalClientScope(obj.Spec.Client.ID, scopeID, realm)
+}
+
+func (i *ClusterActionRunner) RemoveOptionalClientScope(obj *v1alpha1.KeycloakClient, clientScope *v1alpha1.KeycloakAPIClientScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform optional client scope remove when client is nil")
+	}
+	return i.keycloakClient.RemoveOptionalClientScope(obj.Spec.Client.ID, clientScope.ID, realm)
+}
+
+// Client scopes are requested by name, so look up the ID in the realm. A scope
+// that does not exist is an error rather than being skipped, otherwise a typo
+// in the CR would go unnoticed
+func (i *ClusterActionRunner) resolveClientScope(clientScope *v1alpha1.KeycloakAPIClientScope, realm string) (string, error) {
+	scopes, err := i.keycloakClient.ListClientScopes(realm)
+	if err != nil {
+		return "", err
+	}
+
+	for _, scope := range scopes {
+		if scope.Name == clientScope.Name {
+			return scope.ID, nil
+		}
+	}
+	return "", errors.Errorf("client scope %v not found in realm %v", clientScope.Name, realm)
+}
+
+// New scopes are created along with their protocol mappers
+func (i *ClusterActionRunner) CreateClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client scope create when client is nil")
+	}
+
+	scope := obj.Spec.ClientScope.DeepCopy()
+	scope.ID = ""
+	_, err := i.keycloakClient.CreateClientScope(scope, realm)
+	return err
+}
+
+func (i *ClusterActionRunner) UpdateClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client scope update when client is nil")
+	}
+
+	scopeID, err := i.resolveClientScope(obj.Spec.ClientScope, realm)
+	if err != nil {
+		return err
+	}
+
+	scope := obj.Spec.ClientScope.DeepCopy()
+	scope.ID = scopeID
+	scope.ProtocolMappers = nil
+	return i.keycloakClient.UpdateClientScope(scope, realm)
+}
+
+func (i *ClusterActionRunner) DeleteClientScope(obj *v1alpha1.KeycloakClientScope, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client scope delete when client is nil")
+	}
+
+	scopeID, err := i.resolveClientScope(obj.Spec.ClientScope, realm)
+	if err != nil {
+		return err
+	}
+	return i.keycloakClient.DeleteClientScope(scopeID, realm)
+}
+
+func (i *ClusterActionRunner) CreateClientScopeProtocolMapper(obj *v1alpha1.KeycloakClientScope, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client scope protocol mapper create when client is nil")
+	}
+
+	scopeID, err := i.resolveClientScope(obj.Spec.ClientScope, realm)
+	if err != nil {
+		return err
+	}
+
+	newMapper := mapper.DeepCopy()
+	newMapper.ID = ""
+	_, err = i.keycloakClient.CreateClientScopeProtocolMapper(scopeID, newMapper, realm)
+	return err
+}
+
+func (i *ClusterActionRunner) UpdateClientScopeProtocolMapper(obj *v1alpha1.KeycloakClientScope, mapper, oldMapper *v1alpha1.KeycloakProtocolMapper, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client scope protocol mapper update when client is nil")
+	}
+
+	scopeID, err := i.resolveClientScope(obj.Spec.ClientScope, realm)
+	if err != nil {
+		return err
+	}
+	return i.keycloakClient.UpdateClientScopeProtocolMapper(scopeID, mapper, oldMapper, realm)
+}
+
+func (i *ClusterActionRunner) DeleteClientScopeProtocolMapper(obj *v1alpha1.KeycloakClientScope, mapper *v1alpha1.KeycloakProtocolMapper, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client scope protocol mapper delete when client is nil")
+	}
+
+	scopeID, err := i.resolveClientScope(obj.Spec.ClientScope, realm)
+	if err != nil {
+		return err
+	}
+	return i.keycloakClient.DeleteClientScopeProtocolMapper(scopeID, mapper.ID, realm)
+}
+
+// Delete a realm using the keycloak api
+func (i *ClusterActionRunner) DeleteRealm(obj *v1alpha1.KeycloakRealm) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm delete when client is nil")
+	}
+	return i.keycloakClient.DeleteRealm(obj.Spec.Realm.Realm)
+}
+
+func (i *ClusterActionRunner) DeleteClient(obj *v1alpha1.KeycloakClient, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform client delete when client is nil")
+	}
+	return i.keycloakClient.DeleteClient(obj.Spec.Client.ID, realm)
+}
+
+func (i *ClusterActionRunner) CreateUser(obj *v1alpha1.KeycloakUser, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform user create when client is nil")
+	}
+
+	// Create the user
+	uid, err := i.keycloakClient.CreateUser(&obj.Spec.User, realm)
+	if err != nil {
+		return err
+	}
+
+	// Update newly created user with its uid
+	obj.Spec.User.ID = uid
+	return i.client.Update(i.context, obj)
+}
+
+func (i *ClusterActionRunner) UpdateUser(obj *v1alpha1.KeycloakUser, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform user update when client is nil")
+	}
+
+	err := i.keycloakClient.UpdateUser(&obj.Spec.User, realm)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Only password credentials can be reset through the admin API. The credential
+// secret is updated afterwards, so that the reset happens only once
+func (i *ClusterActionRunner) ResetUserPassword(obj *v1alpha1.KeycloakUser, secret *corev1.Secret, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform user password reset when client is nil")
+	}
+
+	for _, credential := range obj.Spec.User.Credentials {
+		if credential.Type != "" && credential.Type != "password" {
+			continue
+		}
+
+		err := i.keycloakClient.ResetUserPassword(obj.Spec.User.ID, credential.DeepCopy(), realm)
+		if err != nil {
+			return err
+		}
+	}
+
+	return i.Update(model.UserCredentialSecretReset(obj, secret))
+}
+
+// The user ID is set on the CR by the time the email is sent, also when the user was
+// created in the same reconcile. The annotation that asked for the email is removed
+// before it is sent, so that a failing removal can't send it on every reconcile, and
+// adding it again sends another one
+func (i *ClusterActionRunner) SendUserActionsEmail(obj *v1alpha1.KeycloakUser, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform user actions email when client is nil")
+	}
+
+	err := i.removeAnnotations(obj, model.SendActionsEmailAnnotation)
+	if err != nil {
+		return err
+	}
+
+	err = i.keycloakClient.SendUserActionsEmail(obj.Spec.User.ID, obj.Spec.ActionsEmail, realm)
+	if err != nil {
+		return errors.Wrapf(err, "failed to send actions email to user %v, the user needs an email address and realm %v an SMTP server", obj.Spec.User.UserName, realm)
+	}
+
+	obj.Status.LastActionsEmailTime = time.Now().UTC().Format(time.RFC3339)
+	return nil
+}
+
+func (i *ClusterActionRunner) DeleteUser(id, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform user delete when client is nil")
+	}
+	return i.keycloakClient.DeleteUser(id, realm)
+}
+
+// Groups are resolved by path when the action runs, so that a missing group
+// fails the reconcile instead of being skipped
+func (i *ClusterActionRunner) JoinGroup(path, userID, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform group join when client is nil")
+	}
+
+	group, err := i.keycloakClient.GetGroupByPath(path, realm)
+	if err != nil {
+		return err
+	}
+	if group == nil {
+		return errors.Errorf("group %v not found in realm %v", path, realm)
+	}
+	return i.keycloakClient.AddUserToGroup(realm, userID, group.ID)
+}
+
+func (i *ClusterActionRunner) LeaveGroup(obj *v1alpha1.KeycloakUserGroup, userID, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform group leave when client is nil")
+	}
+	return i.keycloakClient.RemoveUserFromGroup(realm, userID, obj.ID)
+}
+
+func (i *ClusterActionRunner) AddFederatedIdentity(obj *v1alpha1.FederatedIdentity, userID, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform federated identity add when client is nil")
+	}
+
+	provider, err := i.keycloakClient.GetIdentityProvider(obj.IdentityProvider, realm)
+	if err != nil {
+		return err
+	}
+	if provider == nil {
+		return errors.Errorf("identity provider %v not found in realm %v", obj.IdentityProvider, realm)
+	}
+
+	_, err = i.keycloakClient.CreateFederatedIdentity(*obj, userID, realm)
+	return err
+}
+
+func (i *ClusterActionRunner) RemoveFederatedIdentity(obj *v1alpha1.FederatedIdentity, userID, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform federated identity remove when client is nil")
+	}
+	return i.keycloakClient.RemoveFederatedIdentity(*obj, userID, realm)
+}
+
+// Check if Keycloak is available
+func (i *ClusterActionRunner) Ping() error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform keycloak ping when client is nil")
+	}
+	return i.keycloakClient.Ping()
+}
+
+func (i *ClusterActionRunner) AssignRealmRole(obj *v1alpha1.KeycloakUserRole, userID, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform role assign when client is nil")
+	}
+
+	_, err := i.keycloakClient.CreateUserRealmRole(obj, realm, userID)
+	return err
+}
+
+func (i *ClusterActionRunner) RemoveRealmRole(obj *v1alpha1.KeycloakUserRole, userID, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform role remove when client is nil")
+	}
+	return i.keycloakClient.DeleteUserRealmRole(obj, realm, userID)
+}
+
+func (i *ClusterActionRunner) AssignClientRole(obj *v1alpha1.KeycloakUserRole, clientID, userID, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform role assign when client is nil")
+	}
+
+	_, err := i.keycloakClient.CreateUserClientRole(obj, realm, clientID, userID)
+	return err
+}
+
+func (i *ClusterActionRunner) RemoveClientRole(obj *v1alpha1.KeycloakUserRole, clientID, userID, realm string) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform role remove when client is nil")
+	}
+	return i.keycloakClient.DeleteUserClientRole(obj, realm, clientID, userID)
+}
+
+// Delete a realm using the keycloak api
+func (i *ClusterActionRunner) ApplyOverrides(obj *v1alpha1.KeycloakRealm) error {
+	if i.keycloakClient == nil {
+		return errors.Errorf("cannot perform realm configure when client is nil")
+	}
+
+	for _, override := range obj.Spec.RealmOverrides {
+		err := i.configureBrowserRedirector(override.IdentityProvider, override.ForFlow, obj)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *ClusterActionRunner) configureBrowserRedirector(provider, flow string, obj *v1alpha1.KeycloakRealm) error {
+	realmName := obj.Spec.Realm.Realm
+	authenticationExecutionInfo, err := i.keycloakClient.ListAuthenticationExecutionsForFlow(flow, realmName)
+	if err != nil {
+		return err
+	}
+
+	authenticationConfigID := ""
+	redirectorExecutionID := ""
+	for _, execution := range authenticationExecutionInfo {
+		if execution.ProviderID == "identity-provider-redirector" {
+			authenticationConfigID = execution.AuthenticationConfig
+			redirectorExecutionID = execution.ID
+		}
+	}
+	if redirectorExecutionID == "" {
+		return errors.Errorf("'identity-provider-redirector' was not found in the list of executions of the 'browser' flow")
+	}
+
+	var authenticatorConfig *v1alpha1.AuthenticatorConfig
+	if authenticationConfigID != "" {
+		authenticatorConfig, err = i.keycloakClient.GetAuthenticatorConfig(authenticationConfigID, realmName)
+		if err != nil {
+			return err
+		}
+	}
+
+	if authenticatorConfig == nil && provider != "" {
+		config := &v1alpha1.AuthenticatorConfig{
+			Alias:  authenticationConfigAlias,
+			Config: map[string]string{"defaultProvider": provider},
+		}
+
+		if _, err := i.keycloakClient.CreateAuthenticatorConfig(config, realmName, redirectorExecutionID); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// An action to create generic kubernetes resources
+// (resources that don't require special treatment)
+type GenericCreateAction struct {
+	Ref runtime.Object
+	Msg string
+}
+
 // An action to update generic kubernetes resources
 // (resources that don't require special treatment)
 type GenericUpdateAction struct {
@@ -330,143 +1795,1000 @@ type GenericUpdateAction struct {
 	Msg string
 }
 
-type CreateRealmAction struct {
+type GenericDeleteAction struct {
+	Ref runtime.Object
+	Msg string
+}
+
+type CreateRealmAction struct {
+	Ref *v1alpha1.KeycloakRealm
+	Msg string
+}
+
+type CreateClientAction struct {
+	Ref   *v1alpha1.KeycloakClient
+	Msg   string
+	Realm string
+}
+
+type UpdateClientAction struct {
+	Ref   *v1alpha1.KeycloakClient
+	Msg   string
+	Realm string
+}
+
+type DeleteRealmAction struct {
+	Ref *v1alpha1.KeycloakRealm
+	Msg string
+}
+
+type DeleteClientAction struct {
+	Ref   *v1alpha1.KeycloakClient
+	Realm string
+	Msg   string
+}
+
+type RegenerateClientSecretAction struct {
+	Ref    *v1alpha1.KeycloakClient
+	Secret *corev1.Secret
+	Msg    string
+	Realm  string
+}
+
+type UpdateClientSecretAction struct {
+	Ref   *v1alpha1.KeycloakClient
+	Msg   string
+	Realm string
+}
+
+type RegenerateRegistrationAccessTokenAction struct {
+	Ref    *v1alpha1.KeycloakClient
+	Secret *corev1.Secret
+	Msg    string
+	Realm  string
+}
+
+type CreateClientRoleAction struct {
+	Role  *v1alpha1.RoleRepresentation
+	Ref   *v1alpha1.KeycloakClient
+	Msg   string
+	Realm string
+}
+
+type BulkClientRolesAction struct {
+	Roles []v1alpha1.RoleRepresentation
+	Ref   *v1alpha1.KeycloakClient
+	Msg   string
+	Realm string
+}
+
+type AddScopeMappingAction struct {
+	Mappings *v1alpha1.RoleRepresentationComposites
+	Ref      *v1alpha1.KeycloakClient
+	Msg      string
+	Realm    string
+}
+
+type RemoveScopeMappingAction struct {
+	Mappings *v1alpha1.RoleRepresentationComposites
+	Ref      *v1alpha1.KeycloakClient
+	Msg      string
+	Realm    string
+}
+
+type AddRealmDefaultRolesAction struct {
+	Roles []string
+	Ref   *v1alpha1.KeycloakClient
+	Msg   string
+	Realm string
+}
+
+type RemoveRealmDefaultRolesAction struct {
+	Roles []string
+	Ref   *v1alpha1.KeycloakClient
+	Msg   string
+	Realm string
+}
+
+type UpdateClientRoleAction struct {
+	Role    *v1alpha1.RoleRepresentation
+	OldRole *v1alpha1.RoleRepresentation
+	Ref     *v1alpha1.KeycloakClient
+	Msg     string
+	Realm   string
+}
+
+type DeleteClientRoleAction struct {
+	Role  *v1alpha1.RoleRepresentation
+	Ref   *v1alpha1.KeycloakClient
+	Msg   string
+	Realm string
+}
+
+type AddRoleCompositesAction struct {
+	Role       *v1alpha1.RoleRepresentation
+	Composites *v1alpha1.RoleRepresentationComposites
+	Ref        *v1alpha1.KeycloakClient
+	Msg        string
+	Realm      string
+}
+
+type RemoveRoleCompositesAction struct {
+	Role       *v1alpha1.RoleRepresentation
+	Composites *v1alpha1.RoleRepresentationComposites
+	Ref        *v1alpha1.KeycloakClient
+	Msg        string
+	Realm      string
+}
+
+type CreateIdentityProviderAction struct {
+	IdentityProvider *v1alpha1.KeycloakIdentityProvider
+	Ref              *v1alpha1.KeycloakRealm
+	Msg              string
+}
+
+type UpdateIdentityProviderAction struct {
+	IdentityProvider *v1alpha1.KeycloakIdentityProvider
+	Ref              *v1alpha1.KeycloakRealm
+	Msg              string
+}
+
+type DeleteIdentityProviderAction struct {
+	IdentityProvider *v1alpha1.KeycloakIdentityProvider
+	Ref              *v1alpha1.KeycloakRealm
+	Msg              string
+}
+
+type CreateIdentityProviderMapperAction struct {
+	Mapper *v1alpha1.KeycloakIdentityProviderMapper
+	Ref    *v1alpha1.KeycloakRealm
+	Msg    string
+}
+
+type UpdateIdentityProviderMapperAction struct {
+	Mapper *v1alpha1.KeycloakIdentityProviderMapper
+	Ref    *v1alpha1.KeycloakRealm
+	Msg    string
+}
+
+type DeleteIdentityProviderMapperAction struct {
+	Mapper *v1alpha1.KeycloakIdentityProviderMapper
+	Ref    *v1alpha1.KeycloakRealm
+	Msg    string
+}
+
+type UpdateRealmAction struct {
+	Realm          *v1alpha1.KeycloakAPIRealm
+	SMTPServerHash string
+	Ref            *v1alpha1.KeycloakRealm
+	Msg            string
+}
+
+type ImportRealmAction struct {
+	RealmJSON        []byte
+	IfResourceExists string
+	Hash             string
+	Ref              *v1alpha1.KeycloakRealm
+	Msg              string
+}
+
+// Users listed as invalid are not sent to keycloak, only reported in the status
+type ImportRealmUsersAction struct {
+	Users   []*v1alpha1.KeycloakAPIUser
+	Invalid []v1alpha1.KeycloakRealmUserImportResult
+	Hash    string
+	Ref     *v1alpha1.KeycloakRealm
+	Msg     string
+}
+
+type UpdateRealmLocalizationAction struct {
+	Locale  string
+	Texts   map[string]string
+	Removed []string
+	Ref     *v1alpha1.KeycloakRealm
+	Msg     string
+}
+
+type UpdateRequiredActionAction struct {
+	Action   *v1alpha1.KeycloakAPIRequiredAction
+	Register bool
+	Ref      *v1alpha1.KeycloakRealm
+	Msg      string
+}
+
+type AddRealmDefaultClientScopeAction struct {
+	Scope    *v1alpha1.KeycloakAPIClientScope
+	Optional bool
+	Ref      *v1alpha1.KeycloakRealm
+	Msg      string
+}
+
+type RemoveRealmDefaultClientScopeAction struct {
+	Scope    *v1alpha1.KeycloakAPIClientScope
+	Optional bool
+	Ref      *v1alpha1.KeycloakRealm
+	Msg      string
+}
+
+type UpdateClientProfilesAction struct {
+	Profiles []v1alpha1.KeycloakAPIClientProfile
+	Ref      *v1alpha1.KeycloakRealm
+	Msg      string
+}
+
+type UpdateClientPoliciesAction struct {
+	Policies []v1alpha1.KeycloakAPIClientPolicy
+	Ref      *v1alpha1.KeycloakRealm
+	Msg      string
+}
+
+type ConfigureRealmEventsAction struct {
+	Config *v1alpha1.KeycloakAPIRealmEventsConfig
+	Ref    *v1alpha1.KeycloakRealm
+	Msg    string
+}
+
+type CreateUserFederationProviderAction struct {
+	Provider   *v1alpha1.KeycloakAPIComponent
+	SecretHash string
+	Ref        *v1alpha1.KeycloakRealm
+	Msg        string
+}
+
+type UpdateUserFederationProviderAction struct {
+	Provider   *v1alpha1.KeycloakAPIComponent
+	SecretHash string
+	Ref        *v1alpha1.KeycloakRealm
+	Msg        string
+}
+
+type DeleteUserFederationProviderAction struct {
+	Provider *v1alpha1.KeycloakAPIComponent
+	Ref      *v1alpha1.KeycloakRealm
+	Msg      string
+}
+
+type CreateUserFederationMapperAction struct {
+	Mapper       *v1alpha1.KeycloakAPIComponent
+	ProviderName string
+	Ref          *v1alpha1.KeycloakRealm
+	Msg          string
+}
+
+type UpdateUserFederationMapperAction struct {
+	Mapper *v1alpha1.KeycloakAPIComponent
+	Ref    *v1alpha1.KeycloakRealm
+	Msg    string
+}
+
+type DeleteUserFederationMapperAction struct {
+	Mapper *v1alpha1.KeycloakAPIComponent
+	Ref    *v1alpha1.KeycloakRealm
+	Msg    string
+}
+
+type SyncUserFederationAction struct {
+	Provider string
+	Full     bool
+	Ref      *v1alpha1.KeycloakRealm
+	Msg      string
+}
+
+type ExportRealmAction struct {
+	SecretName           string
+	ExportClients        bool
+	ExportGroupsAndRoles bool
+	Ref                  *v1alpha1.KeycloakRealm
+	Msg                  string
+}
+
+type ReplaceAuthenticationFlowAction struct {
+	Flow     *v1alpha1.KeycloakAPIAuthenticationFlow
+	Existing *v1alpha1.KeycloakAPIAuthenticationFlow
+	Ref      *v1alpha1.KeycloakRealm
+	Msg      string
+}
+
+type DeleteAuthenticationFlowAction struct {
+	Flow *v1alpha1.KeycloakAPIAuthenticationFlow
+	Ref  *v1alpha1.KeycloakRealm
+	Msg  string
+}
+
+type UpdateRealmFlowBindingsAction struct {
+	Ref *v1alpha1.KeycloakRealm
+	Msg string
+}
+
+type CreateRealmRoleAction struct {
+	Role *v1alpha1.RoleRepresentation
+	Ref  *v1alpha1.KeycloakRealm
+	Msg  string
+}
+
+type UpdateRealmRoleAction struct {
+	Role    *v1alpha1.RoleRepresentation
+	OldRole *v1alpha1.RoleRepresentation
+	Ref     *v1alpha1.KeycloakRealm
+	Msg     string
+}
+
+type CreateGroupAction struct {
+	Group      *v1alpha1.KeycloakAPIGroup
+	ParentPath string
+	Ref        *v1alpha1.KeycloakRealm
+	Msg        string
+}
+
+type UpdateGroupAction struct {
+	Group *v1alpha1.KeycloakAPIGroup
+	Ref   *v1alpha1.KeycloakRealm
+	Msg   string
+}
+
+type DeleteGroupAction struct {
+	Group *v1alpha1.KeycloakAPIGroup
+	Ref   *v1alpha1.KeycloakRealm
+	Msg   string
+}
+
+type AddGroupRoleMappingsAction struct {
+	Path     string
+	Mappings *v1alpha1.RoleRepresentationComposites
+	Ref      *v1alpha1.KeycloakRealm
+	Msg      string
+}
+
+type RemoveGroupRoleMappingsAction struct {
+	Path     string
+	Mappings *v1alpha1.RoleRepresentationComposites
+	Ref      *v1alpha1.KeycloakRealm
+	Msg      string
+}
+
+type SetDefaultGroupAction struct {
+	Path string
+	Ref  *v1alpha1.KeycloakRealm
+	Msg  string
+}
+
+type UnsetDefaultGroupAction struct {
+	Group *v1alpha1.KeycloakUserGroup
+	Ref   *v1alpha1.KeycloakRealm
+	Msg   string
+}
+
+type DeleteRealmRoleAction struct {
+	Role *v1alpha1.RoleRepresentation
+	Ref  *v1alpha1.KeycloakRealm
+	Msg  string
+}
+
+type AddRealmRoleCompositesAction struct {
+	Role       *v1alpha1.RoleRepresentation
+	Composites *v1alpha1.RoleRepresentationComposites
+	Ref        *v1alpha1.KeycloakRealm
+	Msg        string
+}
+
+type RemoveRealmRoleCompositesAction struct {
+	Role       *v1alpha1.RoleRepresentation
+	Composites *v1alpha1.RoleRepresentationComposites
+	Ref        *v1alpha1.KeycloakRealm
+	Msg        string
+}
+
+type CreateProtocolMapperAction struct {
+	Mapper *v1alpha1.KeycloakProtocolMapper
+	Ref    *v1alpha1.KeycloakClient
+	Msg    string
+	Realm  string
+}
+
+type UpdateProtocolMapperAction struct {
+	Mapper    *v1alpha1.KeycloakProtocolMapper
+	OldMapper *v1alpha1.KeycloakProtocolMapper
+	Ref       *v1alpha1.KeycloakClient
+	Msg       string
+	Realm     string
+}
+
+type DeleteProtocolMapperAction struct {
+	Mapper *v1alpha1.KeycloakProtocolMapper
+	Ref    *v1alpha1.KeycloakClient
+	Msg    string
+	Realm  string
+}
+
+type UpdateClientAuthorizationSettingsAction struct {
+	Current *v1alpha1.ResourceServerRepresentation
+	Ref     *v1alpha1.KeycloakClient
+	Msg     string
+	Realm   string
+}
+
+type AddDefaultClientScopeAction struct {
+	ClientScope *v1alpha1.KeycloakAPIClientScope
+	Ref         *v1alpha1.KeycloakClient
+	Msg         string
+	Realm       string
+}
+
+type RemoveDefaultClientScopeAction struct {
+	ClientScope *v1alpha1.KeycloakAPIClientScope
+	Ref         *v1alpha1.KeycloakClient
+	Msg         string
+	Realm       string
+}
+
+type AddOptionalClientScopeAction struct {
+	ClientScope *v1alpha1.KeycloakAPIClientScope
+	Ref         *v1alpha1.KeycloakClient
+	Msg         string
+	Realm       string
+}
+
+type RemoveOptionalClientScopeAction struct {
+	ClientScope *v1alpha1.KeycloakAPIClientScope
+	Ref         *v1alpha1.KeycloakClient
+	Msg         string
+	Realm       string
+}
+
+type CreateClientScopeAction struct {
+	Ref   *v1alpha1.KeycloakClientScope
+	Msg   string
+	Realm string
+}
+
+type UpdateClientScopeAction struct {
+	Ref   *v1alpha1.KeycloakClientScope
+	Msg   string
+	Realm string
+}
+
+type DeleteClientScopeAction struct {
+	Ref   *v1alpha1.KeycloakClientScope
+	Msg   string
+	Realm string
+}
+
+type CreateClientScopeProtocolMapperAction struct {
+	Mapper *v1alpha1.KeycloakProtocolMapper
+	Ref    *v1alpha1.KeycloakClientScope
+	Msg    string
+	Realm  string
+}
+
+type UpdateClientScopeProtocolMapperAction struct {
+	Mapper    *v1alpha1.KeycloakProtocolMapper
+	OldMapper *v1alpha1.KeycloakProtocolMapper
+	Ref       *v1alpha1.KeycloakClientScope
+	Msg       string
+	Realm     string
+}
+
+type DeleteClientScopeProtocolMapperAction struct {
+	Mapper *v1alpha1.KeycloakProtocolMapper
+	Ref    *v1alpha1.KeycloakClientScope
+	Msg    string
+	Realm  string
+}
+
+type ConfigureRealmAction struct {
 	Ref *v1alpha1.KeycloakRealm
 	Msg string
 }
 
-type CreateClientAction struct {
-	Ref   *v1alpha1.KeycloakClient
-	Msg   string
-	Realm string
+type PingAction struct {
+	Msg string
 }
 
-type UpdateClientAction struct {
-	Ref   *v1alpha1.KeycloakClient
+type CreateUserAction struct {
+	Ref   *v1alpha1.KeycloakUser
+	Realm string
 	Msg   string
+}
+
+type UpdateUserAction struct {
+	Ref   *v1alpha1.KeycloakUser
 	Realm string
+	Msg   string
 }
 
-type DeleteRealmAction struct {
-	Ref *v1alpha1.KeycloakRealm
-	Msg string
+type ResetUserPasswordAction struct {
+	Ref    *v1alpha1.KeycloakUser
+	Secret *corev1.Secret
+	Realm  string
+	Msg    string
 }
 
-type DeleteClientAction struct {
-	Ref   *v1alpha1.KeycloakClient
+type SendUserActionsEmailAction struct {
+	Ref   *v1alpha1.KeycloakUser
 	Realm string
 	Msg   string
 }
 
-type CreateClientRoleAction struct {
-	Role  *v1alpha1.RoleRepresentation
-	Ref   *v1alpha1.KeycloakClient
-	Msg   string
+type DeleteUserAction struct {
+	ID    string
 	Realm string
+	Msg   string
 }
 
-type UpdateClientRoleAction struct {
-	Role    *v1alpha1.RoleRepresentation
-	OldRole *v1alpha1.RoleRepresentation
-	Ref     *v1alpha1.KeycloakClient
-	Msg     string
-	Realm   string
+type JoinGroupAction struct {
+	UserID string
+	Path   string
+	Realm  string
+	Msg    string
 }
 
-type DeleteClientRoleAction struct {
-	Role  *v1alpha1.RoleRepresentation
-	Ref   *v1alpha1.KeycloakClient
-	Msg   string
-	Realm string
+type LeaveGroupAction struct {
+	UserID string
+	Ref    *v1alpha1.KeycloakUserGroup
+	Realm  string
+	Msg    string
+}
+
+type AddFederatedIdentityAction struct {
+	UserID string
+	Ref    *v1alpha1.FederatedIdentity
+	Realm  string
+	Msg    string
+}
+
+type RemoveFederatedIdentityAction struct {
+	UserID string
+	Ref    *v1alpha1.FederatedIdentity
+	Realm  string
+	Msg    string
+}
+
+type AssignRealmRoleAction struct {
+	UserID string
+	Ref    *v1alpha1.KeycloakUserRole
+	Realm  string
+	Msg    string
+}
+
+type RemoveRealmRoleAction struct {
+	UserID string
+	Ref    *v1alpha1.KeycloakUserRole
+	Realm  string
+	Msg    string
+}
+
+type AssignClientRoleAction struct {
+	UserID   string
+	ClientID string
+	Ref      *v1alpha1.KeycloakUserRole
+	Realm    string
+	Msg      string
+}
+
+type RemoveClientRoleAction struct {
+	UserID   string
+	ClientID string
+	Ref      *v1alpha1.KeycloakUserRole
+	Realm    string
+	Msg      string
+}
+
+// Assigns a realm role to the service account user of a client, or a
+// client role if ClientID is set
+type AssignServiceAccountRoleAction struct {
+	UserID   string
+	ClientID string
+	Ref      *v1alpha1.KeycloakUserRole
+	Realm    string
+	Msg      string
+}
+
+// Removes a realm role from the service account user of a client, or a
+// client role if ClientID is set
+type RemoveServiceAccountRoleAction struct {
+	UserID   string
+	ClientID string
+	Ref      *v1alpha1.KeycloakUserRole
+	Realm    string
+	Msg      string
+}
+
+func (i GenericCreateAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.Create(i.Ref)
+}
+
+func (i GenericUpdateAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.Update(i.Ref)
+}
+
+func (i GenericDeleteAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.Delete(i.Ref)
+}
+
+func (i CreateRealmAction) Run(runner ActionRunner) (string, error) {
+	err := runner.CreateRealm(i.Ref)
+	if err == nil {
+		// A new realm has none of the imports of the one it replaces
+		i.Ref.Status.RealmImportHash = ""
+		i.Ref.Status.UserImportHash = ""
+		i.Ref.Status.UserImportResults = nil
+	}
+	return i.Msg, err
+}
+
+func (i CreateClientAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateClient(i.Ref, i.Realm)
+}
+
+func (i UpdateClientAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateClient(i.Ref, i.Realm)
+}
+
+func (i RegenerateClientSecretAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.RegenerateClientSecret(i.Ref, i.Secret, i.Realm)
+}
+
+func (i RegenerateRegistrationAccessTokenAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.RegenerateRegistrationAccessToken(i.Ref, i.Secret, i.Realm)
+}
+
+func (i UpdateClientSecretAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateClientSecret(i.Ref, i.Realm)
+}
+
+func (i CreateClientRoleAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateClientRole(i.Ref, i.Role, i.Realm)
+}
+
+func (i BulkClientRolesAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateClientRoles(i.Ref, i.Roles, i.Realm)
+}
+
+func (i AddScopeMappingAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.AddScopeMappings(i.Ref, i.Mappings, i.Realm)
+}
+
+func (i RemoveScopeMappingAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.RemoveScopeMappings(i.Ref, i.Mappings, i.Realm)
+}
+
+func (i AddRealmDefaultRolesAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.AddRealmDefaultRoles(i.Ref, i.Roles, i.Realm)
+}
+
+func (i RemoveRealmDefaultRolesAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.RemoveRealmDefaultRoles(i.Ref, i.Roles, i.Realm)
+}
+
+func (i UpdateClientRoleAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateClientRole(i.Ref, i.Role, i.OldRole, i.Realm)
+}
+
+func (i DeleteClientRoleAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteClientRole(i.Ref, i.Role.Name, i.Realm)
+}
+
+func (i AddRoleCompositesAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.AddClientRoleComposites(i.Ref, i.Role, i.Composites, i.Realm)
+}
+
+func (i RemoveRoleCompositesAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.RemoveClientRoleComposites(i.Ref, i.Role, i.Composites, i.Realm)
+}
+
+func (i CreateIdentityProviderAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateIdentityProvider(i.Ref, i.IdentityProvider)
+}
+
+func (i UpdateIdentityProviderAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateIdentityProvider(i.Ref, i.IdentityProvider)
+}
+
+func (i DeleteIdentityProviderAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteIdentityProvider(i.Ref, i.IdentityProvider.Alias)
+}
+
+func (i CreateIdentityProviderMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateIdentityProviderMapper(i.Ref, i.Mapper)
+}
+
+func (i UpdateIdentityProviderMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateIdentityProviderMapper(i.Ref, i.Mapper)
+}
+
+func (i DeleteIdentityProviderMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteIdentityProviderMapper(i.Ref, i.Mapper)
+}
+
+func (i UpdateRealmAction) Run(runner ActionRunner) (string, error) {
+	err := runner.UpdateRealm(i.Ref, i.Realm)
+	if err == nil && i.SMTPServerHash != "" {
+		// recorded in the status, which is written once all actions succeeded
+		i.Ref.Status.SMTPServerHash = i.SMTPServerHash
+	}
+	return i.Msg, err
+}
+
+func (i ImportRealmAction) Run(runner ActionRunner) (string, error) {
+	err := runner.ImportRealm(i.Ref, i.RealmJSON, i.IfResourceExists)
+	if err == nil {
+		// recorded in the status, which is written once all actions succeeded
+		i.Ref.Status.RealmImportHash = i.Hash
+	}
+	return i.Msg, err
+}
+
+func (i ImportRealmUsersAction) Run(runner ActionRunner) (string, error) {
+	results := append([]v1alpha1.KeycloakRealmUserImportResult{}, i.Invalid...)
+	var err error
+	if len(i.Users) > 0 {
+		var response *PartialImportResponse
+		response, err = runner.ImportRealmUsers(i.Ref, i.Users)
+		if err != nil && len(i.Users) > 1 {
+			// Keycloak imports all users of a request or none, so the users are imported
+			// one at a time to fail only those that keycloak rejects
+			imported := importRealmUsersOneByOne(runner, i.Ref, i.Users)
+			results = append(results, imported...)
+			err = failedUserImports(imported)
+		} else {
+			results = append(results, userImportResults(i.Users, response, err)...)
+		}
+	}
+
+	// The results are recorded in the status also when the import failed, the hash
+	// only once it succeeded so that failed imports are retried
+	i.Ref.Status.UserImportResults = results
+	if err == nil {
+		i.Ref.Status.UserImportHash = i.Hash
+	}
+	return i.Msg, err
+}
+
+func importRealmUsersOneByOne(runner ActionRunner, obj *v1alpha1.KeycloakRealm, users []*v1alpha1.KeycloakAPIUser) []v1alpha1.KeycloakRealmUserImportResult {
+	results := make([]v1alpha1.KeycloakRealmUserImportResult, 0, len(users))
+	for _, user := range users {
+		batch := []*v1alpha1.KeycloakAPIUser{user}
+		response, err := runner.ImportRealmUsers(obj, batch)
+		results = append(results, userImportResults(batch, response, err)...)
+	}
+	return results
+}
+
+func failedUserImports(results []v1alpha1.KeycloakRealmUserImportResult) error {
+	var failed []string
+	for _, result := range results {
+		if result.Result == v1alpha1.UserImportFailed {
+			failed = append(failed, result.Username)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return errors.Errorf("failed to import users %v", strings.Join(failed, ", "))
+}
+
+func userImportResults(users []*v1alpha1.KeycloakAPIUser, response *PartialImportResponse, err error) []v1alpha1.KeycloakRealmUserImportResult {
+	// keycloak stores usernames in lower case
+	actions := map[string]string{}
+	if response != nil {
+		for _, result := range response.Results {
+			if result.ResourceType == "USER" {
+				actions[strings.ToLower(result.ResourceName)] = result.Action
+			}
+		}
+	}
+
+	results := make([]v1alpha1.KeycloakRealmUserImportResult, 0, len(users))
+	for _, user := range users {
+		result := v1alpha1.KeycloakRealmUserImportResult{Username: user.UserName}
+		switch {
+		case err != nil:
+			result.Result = v1alpha1.UserImportFailed
+			result.Message = err.Error()
+		case actions[strings.ToLower(user.UserName)] == "SKIPPED":
+			result.Result = v1alpha1.UserImportSkipped
+		default:
+			result.Result = v1alpha1.UserImportAdded
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func (i UpdateRealmLocalizationAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateRealmLocalization(i.Ref, i.Locale, i.Texts, i.Removed)
+}
+
+func (i UpdateRequiredActionAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateRequiredAction(i.Ref, i.Action, i.Register)
+}
+
+func (i AddRealmDefaultClientScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.AddRealmDefaultClientScope(i.Ref, i.Scope, i.Optional)
+}
+
+func (i RemoveRealmDefaultClientScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.RemoveRealmDefaultClientScope(i.Ref, i.Scope, i.Optional)
+}
+
+func (i UpdateClientProfilesAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateClientProfiles(i.Ref, i.Profiles)
+}
+
+func (i UpdateClientPoliciesAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateClientPolicies(i.Ref, i.Policies)
+}
+
+func (i ConfigureRealmEventsAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.ConfigureRealmEvents(i.Ref, i.Config)
+}
+
+func (i CreateUserFederationProviderAction) Run(runner ActionRunner) (string, error) {
+	err := runner.CreateUserFederationProvider(i.Ref, i.Provider)
+	if err == nil {
+		recordUserFederationSecretHash(i.Ref, i.Provider.Name, i.SecretHash)
+	}
+	return i.Msg, err
+}
+
+func (i UpdateUserFederationProviderAction) Run(runner ActionRunner) (string, error) {
+	err := runner.UpdateUserFederationProvider(i.Ref, i.Provider)
+	if err == nil {
+		recordUserFederationSecretHash(i.Ref, i.Provider.Name, i.SecretHash)
+	}
+	return i.Msg, err
+}
+
+// Recorded in the status, which is written once all actions succeeded
+func recordUserFederationSecretHash(obj *v1alpha1.KeycloakRealm, providerName, hash string) {
+	if hash == "" {
+		delete(obj.Status.UserFederationSecretHashes, providerName)
+		return
+	}
+	if obj.Status.UserFederationSecretHashes == nil {
+		obj.Status.UserFederationSecretHashes = make(map[string]string)
+	}
+	obj.Status.UserFederationSecretHashes[providerName] = hash
+}
+
+func (i DeleteUserFederationProviderAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteUserFederationProvider(i.Ref, i.Provider)
+}
+
+func (i CreateUserFederationMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateUserFederationMapper(i.Ref, i.Mapper, i.ProviderName)
+}
+
+func (i UpdateUserFederationMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateUserFederationMapper(i.Ref, i.Mapper)
+}
+
+func (i DeleteUserFederationMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteUserFederationMapper(i.Ref, i.Mapper)
+}
+
+func (i SyncUserFederationAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.SyncUserFederationProvider(i.Ref, i.Provider, i.Full)
+}
+
+func (i ExportRealmAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.ExportRealm(i.Ref, i.SecretName, i.ExportClients, i.ExportGroupsAndRoles)
+}
+
+func (i ReplaceAuthenticationFlowAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.ReplaceAuthenticationFlow(i.Ref, i.Flow, i.Existing)
+}
+
+func (i DeleteAuthenticationFlowAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteAuthenticationFlow(i.Ref, i.Flow)
+}
+
+func (i UpdateRealmFlowBindingsAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateRealmFlowBindings(i.Ref)
+}
+
+func (i CreateRealmRoleAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateRealmRole(i.Ref, i.Role)
+}
+
+func (i UpdateRealmRoleAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateRealmRole(i.Ref, i.Role, i.OldRole)
+}
+
+func (i DeleteRealmRoleAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteRealmRole(i.Ref, i.Role.Name)
+}
+
+func (i CreateGroupAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateGroup(i.Ref, i.Group, i.ParentPath)
+}
+
+func (i UpdateGroupAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateGroup(i.Ref, i.Group)
+}
+
+func (i DeleteGroupAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteGroup(i.Ref, i.Group)
+}
+
+func (i AddGroupRoleMappingsAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.AddGroupRoleMappings(i.Ref, i.Path, i.Mappings)
+}
+
+func (i RemoveGroupRoleMappingsAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.RemoveGroupRoleMappings(i.Ref, i.Path, i.Mappings)
+}
+
+func (i SetDefaultGroupAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.SetDefaultGroup(i.Ref, i.Path)
 }
 
-type ConfigureRealmAction struct {
-	Ref *v1alpha1.KeycloakRealm
-	Msg string
+func (i UnsetDefaultGroupAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UnsetDefaultGroup(i.Ref, i.Group)
 }
 
-type PingAction struct {
-	Msg string
+func (i AddRealmRoleCompositesAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.AddRealmRoleComposites(i.Ref, i.Role, i.Composites)
 }
 
-type CreateUserAction struct {
-	Ref   *v1alpha1.KeycloakUser
-	Realm string
-	Msg   string
+func (i RemoveRealmRoleCompositesAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.RemoveRealmRoleComposites(i.Ref, i.Role, i.Composites)
 }
 
-type UpdateUserAction struct {
-	Ref   *v1alpha1.KeycloakUser
-	Realm string
-	Msg   string
+func (i CreateProtocolMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateClientProtocolMapper(i.Ref, i.Mapper, i.Realm)
 }
 
-type DeleteUserAction struct {
-	ID    string
-	Realm string
-	Msg   string
+func (i UpdateProtocolMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateClientProtocolMapper(i.Ref, i.Mapper, i.OldMapper, i.Realm)
 }
 
-type AssignRealmRoleAction struct {
-	UserID string
-	Ref    *v1alpha1.KeycloakUserRole
-	Realm  string
-	Msg    string
+func (i DeleteProtocolMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteClientProtocolMapper(i.Ref, i.Mapper, i.Realm)
 }
 
-type RemoveRealmRoleAction struct {
-	UserID string
-	Ref    *v1alpha1.KeycloakUserRole
-	Realm  string
-	Msg    string
+func (i UpdateClientAuthorizationSettingsAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateClientAuthorizationSettings(i.Ref, i.Current, i.Realm)
 }
 
-type AssignClientRoleAction struct {
-	UserID   string
-	ClientID string
-	Ref      *v1alpha1.KeycloakUserRole
-	Realm    string
-	Msg      string
+func (i AddDefaultClientScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.AddDefaultClientScope(i.Ref, i.ClientScope, i.Realm)
 }
 
-type RemoveClientRoleAction struct {
-	UserID   string
-	ClientID string
-	Ref      *v1alpha1.KeycloakUserRole
-	Realm    string
-	Msg      string
+func (i RemoveDefaultClientScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.RemoveDefaultClientScope(i.Ref, i.ClientScope, i.Realm)
 }
 
-func (i GenericCreateAction) Run(runner ActionRunner) (string, error) {
-	return i.Msg, runner.Create(i.Ref)
+func (i AddOptionalClientScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.AddOptionalClientScope(i.Ref, i.ClientScope, i.Realm)
 }
 
-func (i GenericUpdateAction) Run(runner ActionRunner) (string, error) {
-	return i.Msg, runner.Update(i.Ref)
+func (i RemoveOptionalClientScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.RemoveOptionalClientScope(i.Ref, i.ClientScope, i.Realm)
 }
 
-func (i CreateRealmAction) Run(runner ActionRunner) (string, error) {
-	return i.Msg, runner.CreateRealm(i.Ref)
+func (i CreateClientScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateClientScope(i.Ref, i.Realm)
 }
 
-func (i CreateClientAction) Run(runner ActionRunner) (string, error) {
-	return i.Msg, runner.CreateClient(i.Ref, i.Realm)
+func (i UpdateClientScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateClientScope(i.Ref, i.Realm)
 }
 
-func (i UpdateClientAction) Run(runner ActionRunner) (string, error) {
-	return i.Msg, runner.UpdateClient(i.Ref, i.Realm)
+func (i DeleteClientScopeAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteClientScope(i.Ref, i.Realm)
 }
 
-func (i CreateClientRoleAction) Run(runner ActionRunner) (string, error) {
-	return i.Msg, runner.CreateClientRole(i.Ref, i.Role, i.Realm)
+func (i CreateClientScopeProtocolMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.CreateClientScopeProtocolMapper(i.Ref, i.Mapper, i.Realm)
 }
 
-func (i UpdateClientRoleAction) Run(runner ActionRunner) (string, error) {
-	return i.Msg, runner.UpdateClientRole(i.Ref, i.Role, i.OldRole, i.Realm)
+func (i UpdateClientScopeProtocolMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.UpdateClientScopeProtocolMapper(i.Ref, i.Mapper, i.OldMapper, i.Realm)
 }
 
-func (i DeleteClientRoleAction) Run(runner ActionRunner) (string, error) {
-	return i.Msg, runner.DeleteClientRole(i.Ref, i.Role.Name, i.Realm)
+func (i DeleteClientScopeProtocolMapperAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.DeleteClientScopeProtocolMapper(i.Ref, i.Mapper, i.Realm)
 }
 
 func (i DeleteRealmAction) Run(runner ActionRunner) (string, error) {
@@ -493,10 +2815,34 @@ func (i UpdateUserAction) Run(runner ActionRunner) (string, error) {
 	return i.Msg, runner.UpdateUser(i.Ref, i.Realm)
 }
 
+func (i ResetUserPasswordAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.ResetUserPassword(i.Ref, i.Secret, i.Realm)
+}
+
+func (i SendUserActionsEmailAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.SendUserActionsEmail(i.Ref, i.Realm)
+}
+
 func (i DeleteUserAction) Run(runner ActionRunner) (string, error) {
 	return i.Msg, runner.DeleteUser(i.ID, i.Realm)
 }
 
+func (i JoinGroupAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.JoinGroup(i.Path, i.UserID, i.Realm)
+}
+
+func (i LeaveGroupAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.LeaveGroup(i.Ref, i.UserID, i.Realm)
+}
+
+func (i AddFederatedIdentityAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.AddFederatedIdentity(i.Ref, i.UserID, i.Realm)
+}
+
+func (i RemoveFederatedIdentityAction) Run(runner ActionRunner) (string, error) {
+	return i.Msg, runner.RemoveFederatedIdentity(i.Ref, i.UserID, i.Realm)
+}
+
 func (i AssignRealmRoleAction) Run(runner ActionRunner) (string, error) {
 	return i.Msg, runner.AssignRealmRole(i.Ref, i.UserID, i.Realm)
 }
@@ -512,3 +2858,381 @@ func (i AssignClientRoleAction) Run(runner ActionRunner) (string, error) {
 func (i RemoveClientRoleAction) Run(runner ActionRunner) (string, error) {
 	return i.Msg, runner.RemoveClientRole(i.Ref, i.ClientID, i.UserID, i.Realm)
 }
+
+func (i AssignServiceAccountRoleAction) Run(runner ActionRunner) (string, error) {
+	if i.ClientID == "" {
+		return i.Msg, runner.AssignRealmRole(i.Ref, i.UserID, i.Realm)
+	}
+	return i.Msg, runner.AssignClientRole(i.Ref, i.ClientID, i.UserID, i.Realm)
+}
+
+func (i RemoveServiceAccountRoleAction) Run(runner ActionRunner) (string, error) {
+	if i.ClientID == "" {
+		return i.Msg, runner.RemoveRealmRole(i.Ref, i.UserID, i.Realm)
+	}
+	return i.Msg, runner.RemoveClientRole(i.Ref, i.ClientID, i.UserID, i.Realm)
+}
+
+func (i GenericCreateAction) Message() string {
+	return i.Msg
+}
+
+func (i GenericUpdateAction) Message() string {
+	return i.Msg
+}
+
+func (i GenericDeleteAction) Message() string {
+	return i.Msg
+}
+
+func (i CreateRealmAction) Message() string {
+	return i.Msg
+}
+
+func (i CreateClientAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateClientAction) Message() string {
+	return i.Msg
+}
+
+func (i DeleteRealmAction) Message() string {
+	return i.Msg
+}
+
+func (i DeleteClientAction) Message() string {
+	return i.Msg
+}
+
+func (i RegenerateClientSecretAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateClientSecretAction) Message() string {
+	return i.Msg
+}
+
+func (i RegenerateRegistrationAccessTokenAction) Message() string {
+	return i.Msg
+}
+
+func (i CreateClientRoleAction) Message() string {
+	return i.Msg
+}
+
+func (i BulkClientRolesAction) Message() string {
+	return i.Msg
+}
+
+func (i AddScopeMappingAction) Message() string {
+	return i.Msg
+}
+
+func (i RemoveScopeMappingAction) Message() string {
+	return i.Msg
+}
+
+func (i AddRealmDefaultRolesAction) Message() string {
+	return i.Msg
+}
+
+func (i RemoveRealmDefaultRolesAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateClientRoleAction) Message() string {
+	return i.Msg
+}
+
+func (i DeleteClientRoleAction) Message() string {
+	return i.Msg
+}
+
+func (i AddRoleCompositesAction) Message() string {
+	return i.Msg
+}
+
+func (i RemoveRoleCompositesAction) Message() string {
+	return i.Msg
+}
+
+func (i CreateIdentityProviderAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateIdentityProviderAction) Message() string {
+	return i.Msg
+}
+
+func (i DeleteIdentityProviderAction) Message() string {
+	return i.Msg
+}
+
+func (i CreateIdentityProviderMapperAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateIdentityProviderMapperAction) Message() string {
+	return i.Msg
+}
+
+func (i DeleteIdentityProviderMapperAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateRealmAction) Message() string {
+	return i.Msg
+}
+
+func (i ImportRealmAction) Message() string {
+	return i.Msg
+}
+
+func (i ImportRealmUsersAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateRealmLocalizationAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateRequiredActionAction) Message() string {
+	return i.Msg
+}
+
+func (i AddRealmDefaultClientScopeAction) Message() string {
+	return i.Msg
+}
+
+func (i RemoveRealmDefaultClientScopeAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateClientProfilesAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateClientPoliciesAction) Message() string {
+	return i.Msg
+}
+
+func (i ConfigureRealmEventsAction) Message() string {
+	return i.Msg
+}
+
+func (i CreateUserFederationProviderAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateUserFederationProviderAction) Message() string {
+	return i.Msg
+}
+
+func (i DeleteUserFederationProviderAction) Message() string {
+	return i.Msg
+}
+
+func (i CreateUserFederationMapperAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateUserFederationMapperAction) Message() string {
+	return i.Msg
+}
+
+func (i DeleteUserFederationMapperAction) Message() string {
+	return i.Msg
+}
+
+func (i SyncUserFederationAction) Message() string {
+	return i.Msg
+}
+
+func (i ExportRealmAction) Message() string {
+	return i.Msg
+}
+
+func (i ReplaceAuthenticationFlowAction) Message() string {
+	return i.Msg
+}
+
+func (i DeleteAuthenticationFlowAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateRealmFlowBindingsAction) Message() string {
+	return i.Msg
+}
+
+func (i CreateRealmRoleAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateRealmRoleAction) Message() string {
+	return i.Msg
+}
+
+func (i CreateGroupAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateGroupAction) Message() string {
+	return i.Msg
+}
+
+func (i DeleteGroupAction) Message() string {
+	return i.Msg
+}
+
+func (i AddGroupRoleMappingsAction) Message() string {
+	return i.Msg
+}
+
+func (i RemoveGroupRoleMappingsAction) Message() string {
+	return i.Msg
+}
+
+func (i SetDefaultGroupAction) Message() string {
+	return i.Msg
+}
+
+func (i UnsetDefaultGroupAction) Message() string {
+	return i.Msg
+}
+
+func (i DeleteRealmRoleAction) Message() string {
+	return i.Msg
+}
+
+func (i AddRealmRoleCompositesAction) Message() string {
+	return i.Msg
+}
+
+func (i RemoveRealmRoleCompositesAction) Message() string {
+	return i.Msg
+}
+
+func (i CreateProtocolMapperAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateProtocolMapperAction) Message() string {
+	return i.Msg
+}
+
+func (i DeleteProtocolMapperAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateClientAuthorizationSettingsAction) Message() string {
+	return i.Msg
+}
+
+func (i AddDefaultClientScopeAction) Message() string {
+	return i.Msg
+}
+
+func (i RemoveDefaultClientScopeAction) Message() string {
+	return i.Msg
+}
+
+func (i AddOptionalClientScopeAction) Message() string {
+	return i.Msg
+}
+
+func (i RemoveOptionalClientScopeAction) Message() string {
+	return i.Msg
+}
+
+func (i CreateClientScopeAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateClientScopeAction) Message() string {
+	return i.Msg
+}
+
+func (i DeleteClientScopeAction) Message() string {
+	return i.Msg
+}
+
+func (i CreateClientScopeProtocolMapperAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateClientScopeProtocolMapperAction) Message() string {
+	return i.Msg
+}
+
+func (i DeleteClientScopeProtocolMapperAction) Message() string {
+	return i.Msg
+}
+
+func (i ConfigureRealmAction) Message() string {
+	return i.Msg
+}
+
+func (i PingAction) Message() string {
+	return i.Msg
+}
+
+func (i CreateUserAction) Message() string {
+	return i.Msg
+}
+
+func (i UpdateUserAction) Message() string {
+	return i.Msg
+}
+
+func (i ResetUserPasswordAction) Message() string {
+	return i.Msg
+}
+
+func (i SendUserActionsEmailAction) Message() string {
+	return i.Msg
+}
+
+func (i DeleteUserAction) Message() string {
+	return i.Msg
+}
+
+func (i JoinGroupAction) Message() string {
+	return i.Msg
+}
+
+func (i LeaveGroupAction) Message() string {
+	return i.Msg
+}
+
+func (i AddFederatedIdentityAction) Message() string {
+	return i.Msg
+}
+
+func (i RemoveFederatedIdentityAction) Message() string {
+	return i.Msg
+}
+
+func (i AssignRealmRoleAction) Message() string {
+	return i.Msg
+}
+
+func (i RemoveRealmRoleAction) Message() string {
+	return i.Msg
+}
+
+func (i AssignClientRoleAction) Message() string {
+	return i.Msg
+}
+
+func (i RemoveClientRoleAction) Message() string {
+	return i.Msg
+}
+
+func (i AssignServiceAccountRoleAction) Message() string {
+	return i.Msg
+}
+
+func (i RemoveServiceAccountRoleAction) Message() string {
+	return i.Msg
+}
@@ -0,0 +1,173 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestClusterActionRunner_Test_Dry_Run(t *testing.T) {
+	// given
+	cr := &v1alpha1.KeycloakRealm{}
+	desiredState := DesiredClusterState{}
+	desiredState.AddAction(&PingAction{Msg: "check if keycloak is available"})
+	desiredState.AddAction(&CreateRealmAction{Ref: cr, Msg: "create realm"})
+
+	// when
+	err := NewDryRunActionRunner(context.TODO(), nil, nil, cr).RunAll(desiredState)
+
+	// then
+	// none of the actions ran, they would fail without a keycloak client
+	assert.NoError(t, err)
+	assert.Equal(t, "create realm", desiredState[1].Message())
+	assert.Equal(t, "would reconcile 2 actions", DryRunMessage(len(desiredState)))
+
+	// when
+	err = NewClusterActionRunner(context.TODO(), nil, nil, cr).RunAll(desiredState)
+
+	// then
+	assert.Error(t, err)
+}
+
+func TestClusterActionRunner_Test_Create_Realm(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(201)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	keycloakClient := &Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+	cr := &v1alpha1.KeycloakRealm{
+		Spec: v1alpha1.KeycloakRealmSpec{
+			Realm: &v1alpha1.KeycloakAPIRealm{Realm: "test"},
+		},
+		Status: v1alpha1.KeycloakRealmStatus{
+			RealmImportHash: "imported into the deleted realm",
+			UserImportHash:  "imported into the deleted realm",
+		},
+	}
+	desiredState := DesiredClusterState{}
+	desiredState.AddAction(&CreateRealmAction{Ref: cr, Msg: "create realm"})
+
+	// when
+	err := NewClusterAndKeycloakActionRunner(context.TODO(), nil, nil, cr, keycloakClient, nil).RunAll(desiredState)
+
+	// then
+	// the imports are applied to the new realm again
+	assert.NoError(t, err)
+	assert.Empty(t, cr.Status.RealmImportHash)
+	assert.Empty(t, cr.Status.UserImportHash)
+}
+
+func TestClusterActionRunner_Test_Events(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			w.WriteHeader(200)
+		case http.MethodDelete:
+			w.WriteHeader(204)
+		default:
+			w.WriteHeader(409)
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	keycloakClient := &Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+	cr := &v1alpha1.KeycloakClient{
+		Spec: v1alpha1.KeycloakClientSpec{
+			Client: &v1alpha1.KeycloakAPIClient{ID: "test", ClientID: "test"},
+		},
+	}
+	role := &v1alpha1.RoleRepresentation{Name: "test"}
+	desiredState := DesiredClusterState{}
+	desiredState.AddAction(&PingAction{Msg: "check if keycloak is available"})
+	desiredState.AddAction(&DeleteClientRoleAction{Ref: cr, Role: role, Msg: "delete client role test", Realm: "test"})
+	desiredState.AddAction(DeleteClientRoleAction{Ref: cr, Role: role, Msg: "delete client role again", Realm: "test"})
+	desiredState.AddAction(&CreateClientRoleAction{Ref: cr, Role: role, Msg: "create client role test", Realm: "test"})
+	recorder := record.NewFakeRecorder(10)
+
+	// when
+	err := NewClusterAndKeycloakActionRunner(context.TODO(), nil, nil, cr, keycloakClient, recorder).RunAll(desiredState)
+
+	// then
+	// the ping doesn't change anything and isn't recorded, actions added as values are
+	assert.Error(t, err)
+	assert.Len(t, recorder.Events, 3)
+	assert.Equal(t, "Normal Deleted delete client role test", <-recorder.Events)
+	assert.Equal(t, "Normal Deleted delete client role again", <-recorder.Events)
+	assert.Contains(t, <-recorder.Events, "Warning ActionFailed create client role test: ")
+}
+
+func TestClusterActionRunner_Test_Import_Realm_Users_Bad_Role(t *testing.T) {
+	// given
+	requests := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		var rep PartialImportRepresentation
+		err := json.NewDecoder(req.Body).Decode(&rep)
+		assert.NoError(t, err)
+		for _, user := range rep.Users {
+			if len(user.RealmRoles) > 0 && user.RealmRoles[0] == "missing" {
+				w.WriteHeader(404)
+				return
+			}
+		}
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte(`{"added": 1}`))
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	keycloakClient := &Client{
+		requester: server.Client(),
+		URL:       server.URL,
+		token:     "dummy",
+	}
+	cr := &v1alpha1.KeycloakRealm{
+		Spec: v1alpha1.KeycloakRealmSpec{
+			Realm: &v1alpha1.KeycloakAPIRealm{Realm: "test"},
+		},
+	}
+	desiredState := DesiredClusterState{}
+	desiredState.AddAction(&ImportRealmUsersAction{
+		Ref: cr,
+		Users: []*v1alpha1.KeycloakAPIUser{
+			{UserName: "alice", RealmRoles: []string{"user"}},
+			{UserName: "bob", RealmRoles: []string{"missing"}},
+			{UserName: "carol"},
+		},
+		Hash: "users",
+		Msg:  "import users",
+	})
+
+	// when
+	err := NewClusterAndKeycloakActionRunner(context.TODO(), nil, nil, cr, keycloakClient, nil).RunAll(desiredState)
+
+	// then
+	// the failed batch is imported one user at a time, so only bob fails
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "bob")
+	assert.Equal(t, 4, requests)
+	assert.Empty(t, cr.Status.UserImportHash)
+	assert.Len(t, cr.Status.UserImportResults, 3)
+	assert.Equal(t, v1alpha1.UserImportAdded, cr.Status.UserImportResults[0].Result)
+	assert.Equal(t, v1alpha1.UserImportFailed, cr.Status.UserImportResults[1].Result)
+	assert.Equal(t, v1alpha1.UserImportAdded, cr.Status.UserImportResults[2].Result)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"k8s.io/api/autoscaling/v2beta2"
 	v1beta12 "k8s.io/api/policy/v1beta1"
 
 	v13 "github.com/openshift/api/route/v1"
@@ -19,6 +20,7 @@ import (
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -52,6 +54,9 @@ type ClusterState struct {
 	KeycloakPrometheusRule          *monitoringv1.PrometheusRule
 	KeycloakGrafanaDashboard        *grafanav1alpha1.GrafanaDashboard
 	DatabaseSecret                  *v1.Secret
+	ExternalDatabaseCredentials     *v1.Secret
+	ExtraVolumeConfigMaps           []v1.ConfigMap
+	ExtraVolumeSecrets              []v1.Secret
 	PostgresqlPersistentVolumeClaim *v1.PersistentVolumeClaim
 	PostgresqlService               *v1.Service
 	PostgresqlDeployment            *v12.Deployment
@@ -61,10 +66,17 @@ type ClusterState struct {
 	KeycloakAdminSecret             *v1.Secret
 	KeycloakIngress                 *v1beta1.Ingress
 	KeycloakRoute                   *v13.Route
+	RouteDestinationCACertificate   *v1.ConfigMap
 	PostgresqlServiceEndpoints      *v1.Endpoints
 	PodDisruptionBudget             *v1beta12.PodDisruptionBudget
+	HorizontalPodAutoscaler         *v2beta2.HorizontalPodAutoscaler
 	KeycloakProbes                  *v1.ConfigMap
+	KeycloakStartupScripts          *v1.ConfigMap
+	LegacyRemoteCacheConfigMap      *v1.ConfigMap
 	KeycloakBackup                  *v1alpha1.KeycloakBackup
+	KeycloakRestoreInProgress       bool
+	// Name of a restore that failed while Keycloak was scaled down for it
+	KeycloakRestoreFailed string
 }
 
 func (i *ClusterState) Read(context context.Context, cr *kc.Keycloak, controllerClient client.Client) error {
@@ -96,11 +108,31 @@ func (i *ClusterState) Read(context context.Context, cr *kc.Keycloak, controller
 		return err
 	}
 
+	err = i.readExternalDatabaseCredentialsCurrentState(context, cr, controllerClient)
+	if err != nil {
+		return err
+	}
+
+	err = i.readRouteDestinationCACertificateCurrentState(context, cr, controllerClient)
+	if err != nil {
+		return err
+	}
+
+	err = i.readExtraVolumesCurrentState(context, cr, controllerClient)
+	if err != nil {
+		return err
+	}
+
 	err = i.readProbesCurrentState(context, cr, controllerClient)
 	if err != nil {
 		return err
 	}
 
+	err = i.readStartupScriptsCurrentState(context, cr, controllerClient)
+	if err != nil {
+		return err
+	}
+
 	err = i.readPostgresqlPersistentVolumeClaimCurrentState(context, cr, controllerClient)
 	if err != nil {
 		return err
@@ -141,6 +173,11 @@ func (i *ClusterState) Read(context context.Context, cr *kc.Keycloak, controller
 		return err
 	}
 
+	err = i.readHorizontalPodAutoscalerCurrentState(context, cr, controllerClient)
+	if err != nil {
+		return err
+	}
+
 	if keyExists && routeKindExists {
 		err = i.readKeycloakRouteCurrentState(context, cr, controllerClient)
 		if err != nil {
@@ -158,6 +195,11 @@ func (i *ClusterState) Read(context context.Context, cr *kc.Keycloak, controller
 		return err
 	}
 
+	err = i.readKeycloakRestoreCurrentState(context, cr, controllerClient)
+	if err != nil {
+		return err
+	}
+
 	// Read other things
 	return nil
 }
@@ -357,6 +399,84 @@ func (i *ClusterState) readDatabaseSecretCurrentState(context context.Context, c
 	return nil
 }
 
+// The credentials secret is provided by the user, so it is not
+// a secondary resource of the CR
+func (i *ClusterState) readExternalDatabaseCredentialsCurrentState(context context.Context, cr *kc.Keycloak, controllerClient client.Client) error {
+	if !model.IsExternalDatabaseConfigured(cr) || cr.Spec.ExternalDatabase.CredentialsSecret == "" {
+		i.ExternalDatabaseCredentials = nil
+		return nil
+	}
+
+	credentials := model.ExternalDatabaseCredentialsSecret(cr)
+	credentialsSelector := model.ExternalDatabaseCredentialsSecretSelector(cr)
+
+	err := controllerClient.Get(context, credentialsSelector, credentials)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			i.ExternalDatabaseCredentials = nil
+		} else {
+			return err
+		}
+	} else {
+		i.ExternalDatabaseCredentials = credentials.DeepCopy()
+	}
+	return nil
+}
+
+// The CA is provided by the user, so it is not a secondary resource of the CR
+func (i *ClusterState) readRouteDestinationCACertificateCurrentState(context context.Context, cr *kc.Keycloak, controllerClient client.Client) error {
+	if cr.Spec.ExternalAccess.Route == nil || cr.Spec.ExternalAccess.Route.DestinationCACertificate == nil {
+		i.RouteDestinationCACertificate = nil
+		return nil
+	}
+
+	destinationCA := model.RouteDestinationCAConfigMap(cr)
+	destinationCASelector := model.RouteDestinationCAConfigMapSelector(cr)
+
+	err := controllerClient.Get(context, destinationCASelector, destinationCA)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			i.RouteDestinationCACertificate = nil
+		} else {
+			return err
+		}
+	} else {
+		i.RouteDestinationCACertificate = destinationCA.DeepCopy()
+	}
+	return nil
+}
+
+// Sources that don't exist are left out, the Pods won't start without them anyway
+func (i *ClusterState) readExtraVolumesCurrentState(context context.Context, cr *kc.Keycloak, controllerClient client.Client) error {
+	i.ExtraVolumeConfigMaps = nil
+	i.ExtraVolumeSecrets = nil
+
+	configMaps, secrets := model.ExtraVolumeSources(cr)
+	for _, name := range configMaps {
+		configMap := v1.ConfigMap{}
+		err := controllerClient.Get(context, client.ObjectKey{Name: name, Namespace: cr.Namespace}, &configMap)
+		if err != nil {
+			if apiErrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		i.ExtraVolumeConfigMaps = append(i.ExtraVolumeConfigMaps, configMap)
+	}
+	for _, name := range secrets {
+		secret := v1.Secret{}
+		err := controllerClient.Get(context, client.ObjectKey{Name: name, Namespace: cr.Namespace}, &secret)
+		if err != nil {
+			if apiErrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		i.ExtraVolumeSecrets = append(i.ExtraVolumeSecrets, secret)
+	}
+	return nil
+}
+
 func (i *ClusterState) readProbesCurrentState(context context.Context, cr *kc.Keycloak, controllerClient client.Client) error {
 	probesConfigMap := model.KeycloakProbes(cr)
 	probesConfigMapSelector := model.KeycloakProbesSelector(cr)
@@ -377,6 +497,38 @@ func (i *ClusterState) readProbesCurrentState(context context.Context, cr *kc.Ke
 	return nil
 }
 
+func (i *ClusterState) readStartupScriptsCurrentState(context context.Context, cr *kc.Keycloak, controllerClient client.Client) error {
+	startupScriptsConfigMap := model.KeycloakStartupScripts(cr)
+	startupScriptsConfigMapSelector := model.KeycloakStartupScriptsSelector(cr)
+
+	err := controllerClient.Get(context, startupScriptsConfigMapSelector, startupScriptsConfigMap)
+
+	if err != nil {
+		// If the resource type doesn't exist on the cluster or does exist but is not found
+		if meta.IsNoMatchError(err) || apiErrors.IsNotFound(err) {
+			i.KeycloakStartupScripts = nil
+		} else {
+			return err
+		}
+	} else {
+		i.KeycloakStartupScripts = startupScriptsConfigMap.DeepCopy()
+		cr.UpdateStatusSecondaryResources(i.KeycloakStartupScripts.Kind, i.KeycloakStartupScripts.Name)
+	}
+
+	legacyRemoteCacheConfigMap := &v1.ConfigMap{}
+	err = controllerClient.Get(context, model.LegacyRemoteCacheConfigMapSelector(cr), legacyRemoteCacheConfigMap)
+	if err != nil {
+		if meta.IsNoMatchError(err) || apiErrors.IsNotFound(err) {
+			i.LegacyRemoteCacheConfigMap = nil
+		} else {
+			return err
+		}
+	} else {
+		i.LegacyRemoteCacheConfigMap = legacyRemoteCacheConfigMap.DeepCopy()
+	}
+	return nil
+}
+
 func (i *ClusterState) readKeycloakOrRHSSODeploymentCurrentState(context context.Context, cr *kc.Keycloak, controllerClient client.Client) error {
 	isRHSSO := model.Profiles.IsRHSSO(cr)
 
@@ -416,7 +568,7 @@ func (i *ClusterState) readKeycloakDiscoveryServiceCurrentState(context context.
 }
 
 func (i *ClusterState) readKeycloakRouteCurrentState(context context.Context, cr *kc.Keycloak, controllerClient client.Client) error {
-	keycloakRoute := model.KeycloakRoute(cr)
+	keycloakRoute := model.KeycloakRoute(cr, nil)
 	keycloakRouteSelector := model.KeycloakRouteSelector(cr)
 
 	err := controllerClient.Get(context, keycloakRouteSelector, keycloakRoute)
@@ -465,6 +617,26 @@ func (i *ClusterState) readPodDisruptionCurrentState(context context.Context, cr
 	return nil
 }
 
+func (i *ClusterState) readHorizontalPodAutoscalerCurrentState(context context.Context, cr *kc.Keycloak, controllerClient client.Client) error {
+	hpa := &v2beta2.HorizontalPodAutoscaler{}
+	hpaSelector := model.HorizontalPodAutoscalerSelector(cr)
+
+	err := controllerClient.Get(context, hpaSelector, hpa)
+	if err != nil {
+		if meta.IsNoMatchError(err) || apiErrors.IsNotFound(err) {
+			i.HorizontalPodAutoscaler = nil
+		} else {
+			return err
+		}
+	} else {
+		i.HorizontalPodAutoscaler = hpa.DeepCopy()
+		if cr.Spec.Autoscaling != nil {
+			cr.UpdateStatusSecondaryResources(i.HorizontalPodAutoscaler.Kind, i.HorizontalPodAutoscaler.Name)
+		}
+	}
+	return nil
+}
+
 func (i *ClusterState) IsResourcesReady(cr *kc.Keycloak) (bool, error) {
 	if cr.Spec.Unmanaged {
 		return true, nil
@@ -522,3 +694,38 @@ func (i *ClusterState) readKeycloakBackupCurrentState(context context.Context, c
 	}
 	return nil
 }
+
+// Restores that want Keycloak down while they load the backup. A failed one keeps it
+// down as well, the database may only be restored in part
+func isRestoreScalingDown(backup *v1alpha1.KeycloakBackup, cr *kc.Keycloak) bool {
+	if !model.IsRestoreScalingDownKeycloak(backup) || backup.Spec.InstanceSelector == nil {
+		return false
+	}
+	if backup.Status.Phase != v1alpha1.BackupPhaseRestoring && backup.Status.Phase != v1alpha1.BackupPhaseRestoreFailed {
+		return false
+	}
+	return labels.SelectorFromSet(backup.Spec.InstanceSelector.MatchLabels).Matches(labels.Set(cr.Labels))
+}
+
+func (i *ClusterState) readKeycloakRestoreCurrentState(context context.Context, cr *kc.Keycloak, controllerClient client.Client) error {
+	i.KeycloakRestoreInProgress = false
+	i.KeycloakRestoreFailed = ""
+
+	var backups v1alpha1.KeycloakBackupList
+	err := controllerClient.List(context, &backups, client.InNamespace(cr.Namespace))
+	if err != nil {
+		return err
+	}
+
+	for index := range backups.Items {
+		backup := &backups.Items[index]
+		if !isRestoreScalingDown(backup, cr) {
+			continue
+		}
+		i.KeycloakRestoreInProgress = true
+		if backup.Status.Phase == v1alpha1.BackupPhaseRestoreFailed {
+			i.KeycloakRestoreFailed = backup.Name
+		}
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestIsResourcesReady_Test_Route_Disabled_(t *testing.T) {
@@ -105,3 +106,34 @@ func prepareTestingClusterState() *ClusterState {
 	}
 	return clusterState
 }
+
+func TestIsRestoreScalingDown(t *testing.T) {
+	// given
+	cr := &v1alpha1.Keycloak{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "sso"}},
+	}
+	backup := &v1alpha1.KeycloakBackup{
+		Spec: v1alpha1.KeycloakBackupSpec{
+			Restore:          true,
+			RestoreOptions:   v1alpha1.KeycloakRestoreOptions{ScaleDownKeycloak: true},
+			InstanceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "sso"}},
+		},
+		Status: v1alpha1.KeycloakBackupStatus{Phase: v1alpha1.BackupPhaseRestoring},
+	}
+
+	// then
+	assert.True(t, isRestoreScalingDown(backup, cr))
+
+	// when the restore failed
+	backup.Status.Phase = v1alpha1.BackupPhaseRestoreFailed
+
+	// then
+	// the database may only be restored in part
+	assert.True(t, isRestoreScalingDown(backup, cr))
+
+	// when it succeeded
+	backup.Status.Phase = v1alpha1.BackupPhaseRestored
+
+	// then
+	assert.False(t, isRestoreScalingDown(backup, cr))
+}
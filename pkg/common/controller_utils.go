@@ -3,29 +3,35 @@ package common
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/pkg/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // These kinds are not provided by the openshift api
 const (
-	RouteKind                 = "Route"
-	JobKind                   = "Job"
-	CronJobKind               = "CronJob"
-	SecretKind                = "Secret"
-	StatefulSetKind           = "StatefulSet"
-	ServiceKind               = "Service"
-	IngressKind               = "Ingress"
-	DeploymentKind            = "Deployment"
-	PersistentVolumeClaimKind = "PersistentVolumeClaim"
-	PodDisruptionBudgetKind   = "PodDisruptionBudget"
-	OpenShiftAPIServerKind    = "OpenShiftAPIServer"
+	RouteKind                   = "Route"
+	JobKind                     = "Job"
+	CronJobKind                 = "CronJob"
+	SecretKind                  = "Secret"
+	StatefulSetKind             = "StatefulSet"
+	ServiceKind                 = "Service"
+	IngressKind                 = "Ingress"
+	DeploymentKind              = "Deployment"
+	PersistentVolumeClaimKind   = "PersistentVolumeClaim"
+	PodDisruptionBudgetKind     = "PodDisruptionBudget"
+	HorizontalPodAutoscalerKind = "HorizontalPodAutoscaler"
+	OpenShiftAPIServerKind      = "OpenShiftAPIServer"
 )
 
 func WatchSecondaryResource(c controller.Controller, controllerName string, resourceKind string, objectTypetoWatch runtime.Object, cr runtime.Object) error {
@@ -71,13 +77,137 @@ func GetMatchingKeycloaks(ctx context.Context, c client.Client, labelSelector *v
 	return list, err
 }
 
-// Try to get a list of keycloak instances that match the selector specified on the realm
+// The keycloak instances matching the instance selector of the realm. One that doesn't
+// accept realms from the namespace of the realm is an error rather than skipped, also
+// when the realm is deleted, so that the realm is neither reported ready nor released
+// from keycloak without being reconciled
+func GetRealmKeycloaks(ctx context.Context, c client.Client, realm *v1alpha1.KeycloakRealm) (v1alpha1.KeycloakList, error) {
+	list, err := GetMatchingKeycloaks(ctx, c, realm.Spec.InstanceSelector)
+	if err != nil {
+		return list, err
+	}
+
+	for _, keycloak := range list.Items {
+		if !AcceptsRealm(keycloak, realm) {
+			return list, errors.Errorf("keycloak %v/%v does not accept realms from namespace %v, it has to list the namespace in realmNamespaces",
+				keycloak.Namespace, keycloak.Name, realm.Namespace)
+		}
+	}
+	return list, nil
+}
+
+// Realms in other namespaces than the keycloak instance are accepted unless the
+// keycloak restricts them to the listed namespaces
+func AcceptsRealm(keycloak v1alpha1.Keycloak, realm *v1alpha1.KeycloakRealm) bool {
+	if keycloak.Namespace == realm.Namespace || keycloak.Spec.RealmNamespaces == nil {
+		return true
+	}
+	for _, namespace := range keycloak.Spec.RealmNamespaces {
+		if namespace == "*" || namespace == realm.Namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// Try to get a list of realms that match the selector specified on the client or user,
+// both the match labels and the match expressions
 func GetMatchingRealms(ctx context.Context, c client.Client, labelSelector *v1.LabelSelector) (v1alpha1.KeycloakRealmList, error) {
 	var list v1alpha1.KeycloakRealmList
+	selector, err := v1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return list, errors.Wrap(err, "invalid realm selector")
+	}
 	opts := []client.ListOption{
-		client.MatchingLabels(labelSelector.MatchLabels),
+		client.MatchingLabelsSelector{Selector: selector},
 	}
 
-	err := c.List(ctx, &list, opts...)
+	err = c.List(ctx, &list, opts...)
 	return list, err
 }
+
+// A client selecting several realms would be created in all of them, which is never
+// intended when templating clients for different environments
+func ValidateSingleRealm(cr *v1alpha1.KeycloakClient, realms []v1alpha1.KeycloakRealm) error {
+	if len(realms) <= 1 {
+		return nil
+	}
+	var names []string
+	for _, realm := range realms {
+		names = append(names, fmt.Sprintf("%v/%v", realm.Namespace, realm.Name))
+	}
+	sort.Strings(names)
+	return errors.Errorf("realm selector of keycloak client %v/%v matches %v realms (%v), it has to match exactly one",
+		cr.Namespace, cr.Name, len(realms), strings.Join(names, ", "))
+}
+
+// Find the KeycloakClient that owns the client ID of the given one in the realm, when
+// that is another resource. The oldest resource selecting the realm owns the client ID
+func GetClientOwner(ctx context.Context, c client.Client, cr *v1alpha1.KeycloakClient, realm v1alpha1.KeycloakRealm) (*v1alpha1.KeycloakClient, error) {
+	var list v1alpha1.KeycloakClientList
+	err := c.List(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	return FindClientOwner(cr, list.Items, realm), nil
+}
+
+func FindClientOwner(cr *v1alpha1.KeycloakClient, clients []v1alpha1.KeycloakClient, realm v1alpha1.KeycloakRealm) *v1alpha1.KeycloakClient {
+	var owner *v1alpha1.KeycloakClient
+	for index := range clients {
+		candidate := &clients[index]
+		if candidate.Spec.Client == nil || candidate.Spec.Client.ClientID != cr.Spec.Client.ClientID {
+			continue
+		}
+		if candidate.Namespace != cr.Namespace || candidate.Name != cr.Name {
+			if candidate.DeletionTimestamp != nil || !selectsRealm(candidate, realm) {
+				continue
+			}
+		}
+		if owner == nil || ownsBefore(candidate, owner) {
+			owner = candidate
+		}
+	}
+
+	if owner == nil || (owner.Namespace == cr.Namespace && owner.Name == cr.Name) {
+		return nil
+	}
+	return owner
+}
+
+// Same as GetMatchingRealms
+func selectsRealm(cr *v1alpha1.KeycloakClient, realm v1alpha1.KeycloakRealm) bool {
+	if cr.Spec.RealmSelector == nil {
+		return false
+	}
+	selector, err := v1.LabelSelectorAsSelector(cr.Spec.RealmSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(realm.Labels))
+}
+
+// Resources created in the same second are ordered by namespace and name
+func ownsBefore(cr, other *v1alpha1.KeycloakClient) bool {
+	if !cr.CreationTimestamp.Equal(&other.CreationTimestamp) {
+		return cr.CreationTimestamp.Before(&other.CreationTimestamp)
+	}
+	if cr.Namespace != other.Namespace {
+		return cr.Namespace < other.Namespace
+	}
+	return cr.Name < other.Name
+}
+
+// The result of a successful reconcile, which is requeued after the resync period
+// of the resource when it has one
+func ResyncResult(period *v1.Duration) reconcile.Result {
+	if period == nil || period.Duration <= 0 {
+		return reconcile.Result{Requeue: false}
+	}
+	return reconcile.Result{RequeueAfter: period.Duration}
+}
+
+// Optional flags left out of a CR count as false
+func IsTrue(value *bool) bool {
+	return value != nil && *value
+}
@@ -0,0 +1,132 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getClientForOwnership(name string, created time.Time) v1alpha1.KeycloakClient {
+	return v1alpha1.KeycloakClient{
+		ObjectMeta: v1.ObjectMeta{
+			Name:              name,
+			Namespace:         "test",
+			CreationTimestamp: v1.NewTime(created),
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v1.LabelSelector{
+				MatchLabels: map[string]string{"app": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID: "test",
+			},
+		},
+	}
+}
+
+func TestFindClientOwner(t *testing.T) {
+	// given
+	now := time.Now()
+	realm := v1alpha1.KeycloakRealm{
+		ObjectMeta: v1.ObjectMeta{
+			Labels: map[string]string{"app": "sso"},
+		},
+	}
+	first := getClientForOwnership("first", now.Add(-time.Hour))
+	second := getClientForOwnership("second", now)
+	otherRealm := getClientForOwnership("other_realm", now.Add(-2*time.Hour))
+	otherRealm.Spec.RealmSelector.MatchLabels = map[string]string{"app": "other"}
+	otherClient := getClientForOwnership("other_client", now.Add(-2*time.Hour))
+	otherClient.Spec.Client.ClientID = "other"
+	clients := []v1alpha1.KeycloakClient{second, otherRealm, otherClient, first}
+
+	// when
+	owner := FindClientOwner(&second, clients, realm)
+
+	// then
+	// the older resource for the same client ID and realm owns it
+	assert.NotNil(t, owner)
+	assert.Equal(t, "first", owner.Name)
+	assert.Nil(t, FindClientOwner(&first, clients, realm))
+
+	// when the owner is being deleted
+	deleted := v1.NewTime(now)
+	clients[3].DeletionTimestamp = &deleted
+
+	// then
+	assert.Nil(t, FindClientOwner(&second, clients, realm))
+}
+
+func TestResyncResult(t *testing.T) {
+	// given
+	period := &v1.Duration{Duration: 30 * time.Second}
+
+	// when
+	result := ResyncResult(period)
+
+	// then
+	assert.Equal(t, 30*time.Second, result.RequeueAfter)
+	assert.False(t, ResyncResult(nil).Requeue)
+	assert.Zero(t, ResyncResult(nil).RequeueAfter)
+}
+
+func TestAcceptsRealm(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{
+		ObjectMeta: v1.ObjectMeta{Name: "keycloak", Namespace: "sso"},
+	}
+	local := &v1alpha1.KeycloakRealm{ObjectMeta: v1.ObjectMeta{Namespace: "sso"}}
+	team := &v1alpha1.KeycloakRealm{ObjectMeta: v1.ObjectMeta{Namespace: "team-a"}}
+
+	// then
+	// realms of other namespaces are accepted unless the keycloak restricts them
+	assert.True(t, AcceptsRealm(keycloak, local))
+	assert.True(t, AcceptsRealm(keycloak, team))
+
+	// when
+	keycloak.Spec.RealmNamespaces = []string{"sso"}
+
+	// then
+	assert.True(t, AcceptsRealm(keycloak, local))
+	assert.False(t, AcceptsRealm(keycloak, team))
+
+	// when
+	keycloak.Spec.RealmNamespaces = []string{"team-a"}
+
+	// then
+	assert.True(t, AcceptsRealm(keycloak, team))
+
+	// when
+	keycloak.Spec.RealmNamespaces = []string{"*"}
+
+	// then
+	assert.True(t, AcceptsRealm(keycloak, &v1alpha1.KeycloakRealm{ObjectMeta: v1.ObjectMeta{Namespace: "team-b"}}))
+}
+
+func TestValidateSingleRealm(t *testing.T) {
+	// given
+	cr := getClientForOwnership("client", time.Now())
+	cr.Spec.RealmSelector = &v1.LabelSelector{
+		MatchExpressions: []v1.LabelSelectorRequirement{
+			{Key: "env", Operator: v1.LabelSelectorOpIn, Values: []string{"dev", "test"}},
+		},
+	}
+	dev := v1alpha1.KeycloakRealm{ObjectMeta: v1.ObjectMeta{Name: "dev", Namespace: "test", Labels: map[string]string{"env": "dev"}}}
+	prod := v1alpha1.KeycloakRealm{ObjectMeta: v1.ObjectMeta{Name: "prod", Namespace: "test", Labels: map[string]string{"env": "prod"}}}
+
+	// then
+	// the match expressions are used as well
+	assert.True(t, selectsRealm(&cr, dev))
+	assert.False(t, selectsRealm(&cr, prod))
+	assert.NoError(t, ValidateSingleRealm(&cr, []v1alpha1.KeycloakRealm{dev}))
+	assert.NoError(t, ValidateSingleRealm(&cr, nil))
+
+	// when
+	err := ValidateSingleRealm(&cr, []v1alpha1.KeycloakRealm{prod, dev})
+
+	// then
+	assert.EqualError(t, err, "realm selector of keycloak client test/client matches 2 realms (test/dev, test/prod), it has to match exactly one")
+}
@@ -0,0 +1,158 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// The default roles of realms and clients became a composite role of the realm,
+// named after it, with keycloak 13
+var defaultRolesCompositeVersion = KeycloakVersion{Major: 13}
+
+// Keycloak only comes as the Quarkus distribution since keycloak 20
+var quarkusOnlyVersion = KeycloakVersion{Major: 20}
+
+// Keycloak versions the builds of RHSSO are based on, by their product version
+var rhssoKeycloakVersions = map[KeycloakVersion]KeycloakVersion{
+	{Major: 7, Minor: 4}: {Major: 9},
+	{Major: 7, Minor: 5}: {Major: 15},
+	{Major: 7, Minor: 6}: {Major: 18},
+}
+
+// Builds of keycloak have a suffix, e.g. 18.0.6.redhat-00001, or 7.6.0.GA for RHSSO
+var keycloakVersionPattern = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?`)
+
+type KeycloakServerInfo struct {
+	Version string
+	// Profile of the server, product for builds of RHSSO
+	Profile string
+	// Names of the deployed themes keyed by type
+	Themes map[string][]string
+}
+
+type KeycloakVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+func (v KeycloakVersion) String() string {
+	return fmt.Sprintf("%v.%v.%v", v.Major, v.Minor, v.Patch)
+}
+
+func (v KeycloakVersion) AtLeast(other KeycloakVersion) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor > other.Minor
+	}
+	return v.Patch >= other.Patch
+}
+
+func ParseKeycloakVersion(version string) (KeycloakVersion, bool) {
+	match := keycloakVersionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return KeycloakVersion{}, false
+	}
+	major, _ := strconv.Atoi(match[1])
+	minor, _ := strconv.Atoi(match[2])
+	patch, _ := strconv.Atoi(match[3])
+	return KeycloakVersion{Major: major, Minor: minor, Patch: patch}, true
+}
+
+// The version of keycloak the server is, or is based on for RHSSO. Returns false
+// when that isn't known, in which case nothing should be assumed about it
+func (i *KeycloakServerInfo) KeycloakVersion() (KeycloakVersion, bool) {
+	version, ok := ParseKeycloakVersion(i.Version)
+	if !ok || i.Profile != "product" {
+		return version, ok
+	}
+	version, ok = rhssoKeycloakVersions[KeycloakVersion{Major: version.Major, Minor: version.Minor}]
+	return version, ok
+}
+
+// Servers whose version can't be read are taken to support them, as the ones the
+// operator deploys do
+func supportsDefaultRolesComposite(info *KeycloakServerInfo) bool {
+	if info == nil {
+		return true
+	}
+	version, ok := info.KeycloakVersion()
+	return !ok || version.AtLeast(defaultRolesCompositeVersion)
+}
+
+// Whether the version, as reported in the status of a Keycloak, has no WildFly
+// distribution. RHSSO reports its product version, which is below that of keycloak
+func IsQuarkusOnlyVersion(version string) bool {
+	parsed, ok := ParseKeycloakVersion(version)
+	return ok && parsed.AtLeast(quarkusOnlyVersion)
+}
+
+// Where keycloak serves, keyed by endpoint, so that it is detected once rather than
+// by every client of the operator
+var rootPaths = struct {
+	sync.Mutex
+	atRoot map[string]bool
+}{atRoot: map[string]bool{}}
+
+// The Quarkus distribution of keycloak serves at the root of its URL by default, the
+// WildFly one under /auth. Which one it is, is told by where the master realm is found.
+// Keycloak that can't be reached is taken to serve under /auth, and asked again by the
+// next client
+func servesAtRoot(requester Requester, endpoint string) bool {
+	rootPaths.Lock()
+	atRoot, ok := rootPaths.atRoot[endpoint]
+	rootPaths.Unlock()
+	if ok {
+		return atRoot
+	}
+
+	atRoot, ok = detectRootPath(requester, endpoint)
+	if ok {
+		rootPaths.Lock()
+		rootPaths.atRoot[endpoint] = atRoot
+		rootPaths.Unlock()
+	}
+	return atRoot
+}
+
+// Returns false as the second value when keycloak didn't tell
+func detectRootPath(requester Requester, endpoint string) (bool, bool) {
+	switch realmStatus(requester, endpoint+"/auth") {
+	case http.StatusOK:
+		return false, true
+	case http.StatusNotFound:
+	default:
+		return false, false
+	}
+	if realmStatus(requester, endpoint) != http.StatusOK {
+		return false, false
+	}
+	logrus.Infof("keycloak at %v serves at the root rather than under /auth", endpoint)
+	return true, true
+}
+
+func forgetCachedRootPath(endpoint string) {
+	rootPaths.Lock()
+	defer rootPaths.Unlock()
+	delete(rootPaths.atRoot, endpoint)
+}
+
+func realmStatus(requester Requester, baseURL string) int {
+	req, err := http.NewRequest("GET", baseURL+"/realms/master", nil)
+	if err != nil {
+		return 0
+	}
+	res, err := withoutRetries(requester).Do(req)
+	if err != nil {
+		return 0
+	}
+	defer res.Body.Close()
+	return res.StatusCode
+}
@@ -0,0 +1,120 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeycloakVersion_Test_Parse(t *testing.T) {
+	// given
+	community := &KeycloakServerInfo{Version: "18.0.6.redhat-00001", Profile: "community"}
+	rhsso := &KeycloakServerInfo{Version: "7.5.1.GA", Profile: "product"}
+	legacy := &KeycloakServerInfo{Version: "12.0.4", Profile: "community"}
+
+	// when
+	communityVersion, communityOk := community.KeycloakVersion()
+	rhssoVersion, rhssoOk := rhsso.KeycloakVersion()
+
+	// then
+	// RHSSO is compared by the keycloak version it is based on
+	assert.True(t, communityOk)
+	assert.Equal(t, KeycloakVersion{Major: 18, Minor: 0, Patch: 6}, communityVersion)
+	assert.True(t, rhssoOk)
+	assert.Equal(t, KeycloakVersion{Major: 15}, rhssoVersion)
+	assert.True(t, supportsDefaultRolesComposite(community))
+	assert.True(t, supportsDefaultRolesComposite(rhsso))
+	assert.False(t, supportsDefaultRolesComposite(legacy))
+
+	// when the version can't be read
+	_, ok := ParseKeycloakVersion("unknown")
+
+	// then
+	assert.False(t, ok)
+	assert.True(t, supportsDefaultRolesComposite(&KeycloakServerInfo{Version: "unknown"}))
+	assert.True(t, KeycloakVersion{Major: 13, Minor: 1}.AtLeast(KeycloakVersion{Major: 13}))
+	assert.False(t, KeycloakVersion{Major: 12, Minor: 9}.AtLeast(KeycloakVersion{Major: 13}))
+	assert.True(t, IsQuarkusOnlyVersion("21.1.2"))
+	assert.False(t, IsQuarkusOnlyVersion("18.0.6.redhat-00001"))
+	assert.False(t, IsQuarkusOnlyVersion("7.6.0.GA"))
+	assert.False(t, IsQuarkusOnlyVersion(""))
+}
+
+func TestKeycloakVersion_Test_Serves_At_Root(t *testing.T) {
+	// given
+	quarkus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/realms/master" {
+			w.WriteHeader(200)
+			return
+		}
+		w.WriteHeader(404)
+	}))
+	defer quarkus.Close()
+	wildfly := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/auth/realms/master" {
+			w.WriteHeader(200)
+			return
+		}
+		w.WriteHeader(404)
+	}))
+	defer wildfly.Close()
+
+	// when
+	atRoot := servesAtRoot(quarkus.Client(), quarkus.URL)
+
+	// then
+	// the admin API and the token endpoint are used without /auth
+	assert.True(t, atRoot)
+	client := Client{URL: quarkus.URL, rootPath: atRoot}
+	assert.Equal(t, quarkus.URL, client.baseURL())
+	assert.Equal(t, "realms/master/protocol/openid-connect/token", client.tokenPath())
+
+	// then
+	assert.False(t, servesAtRoot(wildfly.Client(), wildfly.URL))
+	assert.False(t, servesAtRoot(wildfly.Client(), "http://127.0.0.1:0"))
+}
+
+func TestKeycloakVersion_Test_Serves_At_Root_Cached(t *testing.T) {
+	// given
+	requests := 0
+	quarkus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		if req.URL.Path == "/realms/master" {
+			w.WriteHeader(200)
+			return
+		}
+		w.WriteHeader(404)
+	}))
+	defer quarkus.Close()
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(503)
+	}))
+	defer unavailable.Close()
+
+	// when
+	servesAtRoot(quarkus.Client(), quarkus.URL)
+	atRoot := servesAtRoot(quarkus.Client(), quarkus.URL)
+
+	// then
+	// keycloak is only asked by the first client
+	assert.True(t, atRoot)
+	assert.Equal(t, 2, requests)
+
+	// when it moved
+	forgetCachedRootPath(quarkus.URL)
+	servesAtRoot(quarkus.Client(), quarkus.URL)
+
+	// then
+	assert.Equal(t, 4, requests)
+
+	// when keycloak doesn't tell
+	servesAtRoot(unavailable.Client(), unavailable.URL)
+
+	// then
+	rootPaths.Lock()
+	_, cached := rootPaths.atRoot[unavailable.URL]
+	rootPaths.Unlock()
+	assert.False(t, cached)
+}
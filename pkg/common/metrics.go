@@ -0,0 +1,67 @@
+package common
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Metrics of the operator, served along with the ones of controller-runtime on the
+// metrics endpoint of the manager
+var (
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "keycloak_operator_reconcile_duration_seconds",
+		Help:    "Duration of the reconciles of the resources, by kind",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"kind"})
+
+	keycloakChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "keycloak_operator_changes_total",
+		Help: "Clients, roles and secrets changed by the actions of the reconciles, by the kind of object and the change",
+	}, []string{"object", "change"})
+
+	pendingActions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "keycloak_operator_pending_actions",
+		Help: "Actions of the last reconcile still to be run, or skipped after a failed action or in dry run, by resource",
+	}, []string{"kind", "namespace", "name"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileDuration, keycloakChanges, pendingActions)
+}
+
+// ObserveReconcileDuration is meant to be deferred at the start of a reconcile
+func ObserveReconcileDuration(kind string, start time.Time) {
+	reconcileDuration.WithLabelValues(kind).Observe(time.Since(start).Seconds())
+}
+
+func countKeycloakChange(object, change string) {
+	keycloakChanges.WithLabelValues(object, strings.ToLower(change)).Inc()
+}
+
+func setPendingActions(cr runtime.Object, actions int) {
+	accessor, err := meta.Accessor(cr)
+	if err != nil {
+		return
+	}
+	pendingActions.WithLabelValues(objectKind(cr), accessor.GetNamespace(), accessor.GetName()).Set(float64(actions))
+}
+
+// ForgetPendingActions drops the pending actions of a deleted resource, so that
+// they aren't reported forever
+func ForgetPendingActions(cr runtime.Object, namespace, name string) {
+	pendingActions.DeleteLabelValues(objectKind(cr), namespace, name)
+}
+
+// The kind of the resources isn't always set on the objects read from the cache
+func objectKind(cr runtime.Object) string {
+	if cr == nil {
+		return ""
+	}
+	return reflect.Indirect(reflect.ValueOf(cr)).Type().Name()
+}
@@ -0,0 +1,45 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func metricValue(t *testing.T, metric prometheus.Metric) float64 {
+	value := &dto.Metric{}
+	assert.NoError(t, metric.Write(value))
+	if value.Counter != nil {
+		return value.Counter.GetValue()
+	}
+	return value.Gauge.GetValue()
+}
+
+func TestMetrics_Test_Actions(t *testing.T) {
+	// given
+	cr := &v1alpha1.KeycloakClient{}
+	cr.Namespace = "keycloak"
+	cr.Name = "dummy"
+	deletedRoles := metricValue(t, keycloakChanges.WithLabelValues("role", "deleted"))
+
+	// when
+	setPendingActions(cr, 3)
+	countKeycloakChange(actionChange(&DeleteClientRoleAction{}))
+	object, change := actionChange(&UpdateClientAction{})
+
+	// then
+	// routine updates aren't counted
+	assert.Equal(t, float64(3), metricValue(t, pendingActions.WithLabelValues("KeycloakClient", "keycloak", "dummy")))
+	assert.Equal(t, deletedRoles+1, metricValue(t, keycloakChanges.WithLabelValues("role", "deleted")))
+	assert.Equal(t, "", object)
+	assert.Equal(t, "", change)
+
+	// when
+	ForgetPendingActions(cr, "keycloak", "dummy")
+
+	// then
+	assert.False(t, pendingActions.DeleteLabelValues("KeycloakClient", "keycloak", "dummy"))
+}
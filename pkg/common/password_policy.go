@@ -0,0 +1,59 @@
+package common
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Password policies known to keycloak, their values are optional and default as in keycloak
+var passwordPolicies = map[string]bool{
+	"length":                     true,
+	"maxLength":                  true,
+	"digits":                     true,
+	"lowerCase":                  true,
+	"upperCase":                  true,
+	"specialChars":               true,
+	"notUsername":                true,
+	"notEmail":                   true,
+	"regexPattern":               true,
+	"passwordHistory":            true,
+	"forceExpiredPasswordChange": true,
+	"hashAlgorithm":              true,
+	"hashIterations":             true,
+	"passwordBlacklist":          true,
+}
+
+var passwordPolicyPattern = regexp.MustCompile(`^(\w+)(?:\((.*)\))?$`)
+
+// Check a password policy in keycloak's format, e.g. length(12) and digits(1) and notUsername
+func ValidatePasswordPolicy(policy string) error {
+	if strings.TrimSpace(policy) == "" {
+		return nil
+	}
+
+	for _, part := range strings.Split(policy, " and ") {
+		part = strings.TrimSpace(part)
+		match := passwordPolicyPattern.FindStringSubmatch(part)
+		if match == nil {
+			return errors.Errorf("invalid password policy %q, expected policies like length(12) joined by \" and \"", part)
+		}
+
+		if !passwordPolicies[match[1]] {
+			return errors.Errorf("unknown password policy %v, known policies are %v", match[1], strings.Join(knownPasswordPolicies(), ", "))
+		}
+	}
+
+	return nil
+}
+
+func knownPasswordPolicies() []string {
+	var names []string
+	for name := range passwordPolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,31 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordPolicy_Test_Validate(t *testing.T) {
+	// given
+	valid := []string{
+		"",
+		"length(12) and digits(1) and notUsername",
+		"regexPattern(^[a-z](.*)$) and passwordHistory(3)",
+		"upperCase",
+	}
+	invalid := []string{
+		"length(12) and digit(1)",
+		"length(12) and",
+		"length 12",
+	}
+
+	// then
+	for _, policy := range valid {
+		assert.NoError(t, ValidatePasswordPolicy(policy), policy)
+	}
+	for _, policy := range invalid {
+		assert.Error(t, ValidatePasswordPolicy(policy), policy)
+	}
+	assert.Contains(t, ValidatePasswordPolicy("digit(1)").Error(), "unknown password policy digit")
+}
@@ -0,0 +1,62 @@
+package common
+
+import (
+	"reflect"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+)
+
+type ProtocolMapperUpdate struct {
+	Mapper    kc.KeycloakProtocolMapper
+	OldMapper kc.KeycloakProtocolMapper
+}
+
+// Changes needed to turn a list of existing protocol mappers into the desired
+// ones. Mappers are matched by name, unchanged ones are left alone
+type ProtocolMappersDiff struct {
+	Deleted []kc.KeycloakProtocolMapper
+	Updated []ProtocolMapperUpdate
+	Created []kc.KeycloakProtocolMapper
+}
+
+func DiffProtocolMappers(existing []kc.KeycloakProtocolMapper, desired []kc.KeycloakProtocolMapper) ProtocolMappersDiff {
+	diff := ProtocolMappersDiff{}
+
+	desiredByName := make(map[string]kc.KeycloakProtocolMapper)
+	for _, mapper := range desired {
+		desiredByName[mapper.Name] = mapper
+	}
+
+	existingByName := make(map[string]kc.KeycloakProtocolMapper)
+	for _, mapper := range existing {
+		existingByName[mapper.Name] = mapper
+
+		wanted, ok := desiredByName[mapper.Name]
+		// keycloak can't change the type of a mapper, so it is re-created instead
+		if !ok || wanted.Protocol != mapper.Protocol || wanted.ProtocolMapper != mapper.ProtocolMapper {
+			diff.Deleted = append(diff.Deleted, mapper)
+		}
+	}
+
+	for _, mapper := range desired {
+		oldMapper, ok := existingByName[mapper.Name]
+		switch {
+		case !ok || mapper.Protocol != oldMapper.Protocol || mapper.ProtocolMapper != oldMapper.ProtocolMapper:
+			diff.Created = append(diff.Created, mapper)
+		case !protocolMapperSettingsMatch(mapper, oldMapper):
+			diff.Updated = append(diff.Updated, ProtocolMapperUpdate{Mapper: mapper, OldMapper: oldMapper})
+		}
+	}
+
+	return diff
+}
+
+func protocolMapperSettingsMatch(a, b kc.KeycloakProtocolMapper) bool {
+	if a.ConsentRequired != b.ConsentRequired || a.ConsentText != b.ConsentText {
+		return false
+	}
+	if len(a.Config) == 0 && len(b.Config) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a.Config, b.Config)
+}
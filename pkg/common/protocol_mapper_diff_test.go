@@ -0,0 +1,38 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtocolMapperDiff_Test_Diff_By_Name(t *testing.T) {
+	// given
+	existing := []v1alpha1.KeycloakProtocolMapper{
+		{ID: "unchangedID", Name: "unchanged", ProtocolMapper: "oidc-audience-mapper"},
+		{ID: "changedID", Name: "changed", ProtocolMapper: "oidc-audience-mapper", Config: map[string]string{"a": "1"}},
+		{ID: "retypedID", Name: "retyped", ProtocolMapper: "oidc-audience-mapper"},
+		{ID: "removedID", Name: "removed", ProtocolMapper: "oidc-audience-mapper"},
+	}
+	desired := []v1alpha1.KeycloakProtocolMapper{
+		{Name: "unchanged", ProtocolMapper: "oidc-audience-mapper", Config: map[string]string{}},
+		{Name: "changed", ProtocolMapper: "oidc-audience-mapper", Config: map[string]string{"a": "2"}},
+		{Name: "retyped", ProtocolMapper: "oidc-hardcoded-claim-mapper"},
+		{Name: "new", ProtocolMapper: "oidc-audience-mapper"},
+	}
+
+	// when
+	diff := DiffProtocolMappers(existing, desired)
+
+	// then
+	assert.Len(t, diff.Deleted, 2)
+	assert.Equal(t, "retypedID", diff.Deleted[0].ID)
+	assert.Equal(t, "removedID", diff.Deleted[1].ID)
+	assert.Len(t, diff.Updated, 1)
+	assert.Equal(t, "changedID", diff.Updated[0].OldMapper.ID)
+	assert.Equal(t, "2", diff.Updated[0].Mapper.Config["a"])
+	assert.Len(t, diff.Created, 2)
+	assert.Equal(t, "retyped", diff.Created[0].Name)
+	assert.Equal(t, "new", diff.Created[1].Name)
+}
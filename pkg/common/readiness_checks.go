@@ -63,3 +63,7 @@ func IsJobReady(job *v13.Job) (bool, error) {
 
 	return job.Status.Succeeded == 1, nil
 }
+
+func IsJobFailed(job *v13.Job) bool {
+	return job != nil && job.Status.Failed > 0
+}
@@ -5,6 +5,8 @@ import (
 
 	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	"github.com/keycloak/keycloak-operator/pkg/model"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -15,6 +17,57 @@ type RealmState struct {
 	RealmUserSecrets map[string]*v1.Secret
 	Context          context.Context
 	Keycloak         *kc.Keycloak
+	// Realm roles, only read when managed by the CR
+	Roles []kc.RoleRepresentation
+	// Composites of the existing composite realm roles, keyed by role name
+	RoleComposites map[string]*kc.RoleRepresentationComposites
+	// Identity providers of the realm, only read when managed by the CR
+	IdentityProviders []*kc.KeycloakIdentityProvider
+	// Mappers of all identity providers of the realm, only read when managed by the CR
+	IdentityProviderMappers []kc.KeycloakIdentityProviderMapper
+	// Config values of the identity providers read from secrets, keyed by alias
+	IdentityProviderSecrets map[string]map[string]string
+	// SMTP server settings read from secrets
+	SMTPServerSecrets map[string]string
+	// User federation providers of the realm, only read when managed by the CR
+	UserFederationProviders []kc.KeycloakAPIComponent
+	// Mappers of all user federation providers of the realm, only read when managed by the CR
+	UserFederationMappers []kc.KeycloakAPIComponent
+	// Config values of the user federation providers read from secrets, keyed by display name
+	UserFederationSecrets map[string]map[string]string
+	// Events config of the realm, only read when managed by the CR
+	EventsConfig *kc.KeycloakAPIRealmEventsConfig
+	// Top level authentication flows of the realm, only read when managed by the CR
+	AuthenticationFlows []*kc.KeycloakAPIAuthenticationFlow
+	// Flattened executions of the existing flows listed in the CR, keyed by flow alias
+	AuthenticationExecutions map[string][]*kc.AuthenticationExecutionInfo
+	// Configs of these executions, keyed by config ID
+	AuthenticatorConfigs map[string]*kc.AuthenticatorConfig
+	// Group tree of the realm, only read when managed by the CR
+	Groups []*kc.KeycloakAPIGroup
+	// Current default groups of the realm, only read when managed by the CR
+	DefaultGroups []*kc.KeycloakUserGroup
+	// Groups listed as default groups in the CR, keyed by path. Nil for groups not found
+	DesiredDefaultGroups map[string]*kc.KeycloakUserGroup
+	// Realm JSON read from the ConfigMap or Secret referenced by realmImport
+	RealmImport []byte
+	// Users read from the ConfigMap referenced by userImport
+	UserImport []byte
+	// Names of the themes deployed to keycloak keyed by type, only read when managed by the CR.
+	// Nil when keycloak doesn't tell them
+	AvailableThemes map[string][]string
+	// Message overrides of the realm keyed by locale, only read when managed by the CR
+	Localization map[string]map[string]string
+	// Required actions of the realm, only read when managed by the CR
+	RequiredActions []kc.KeycloakAPIRequiredAction
+	// Client scopes of the realm, only read when its default client scopes are managed by the CR
+	ClientScopes []kc.KeycloakAPIClientScope
+	// Current default and optional client scopes of the realm, only read when managed by the CR
+	DefaultClientScopes  []kc.KeycloakAPIClientScope
+	OptionalClientScopes []kc.KeycloakAPIClientScope
+	// Client profiles and policies of the realm, each only read when managed by the CR
+	ClientProfiles []kc.KeycloakAPIClientProfile
+	ClientPolicies []kc.KeycloakAPIClientPolicy
 }
 
 func NewRealmState(context context.Context, keycloak kc.Keycloak) *RealmState {
@@ -31,11 +84,146 @@ func (i *RealmState) Read(cr *kc.KeycloakRealm, realmClient KeycloakInterface, c
 		return err
 	}
 
+	// Referenced secrets are read regardless, as new realms get their
+	// identity providers too
+	err = i.readIdentityProviderSecrets(cr, controllerClient)
+	if err != nil {
+		return err
+	}
+	err = i.readSMTPServerSecrets(cr, controllerClient)
+	if err != nil {
+		return err
+	}
+	err = i.readUserFederationSecrets(cr, controllerClient)
+	if err != nil {
+		return err
+	}
+
 	i.Realm = realm
-	if realm == nil || len(cr.Spec.Realm.Users) == 0 {
+	if realm == nil {
 		return nil
 	}
 
+	if cr.Spec.Realm.IdentityProviders != nil || cr.Spec.Realm.IdentityProviderMappers != nil {
+		i.IdentityProviders, err = realmClient.ListIdentityProviders(cr.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.Realm.IdentityProviderMappers != nil {
+		for _, provider := range i.IdentityProviders {
+			mappers, err := realmClient.ListIdentityProviderMappers(provider.Alias, cr.Spec.Realm.Realm)
+			if err != nil {
+				return err
+			}
+			i.IdentityProviderMappers = append(i.IdentityProviderMappers, mappers...)
+		}
+	}
+
+	if cr.Spec.Realm.UserFederationProviders != nil || cr.Spec.Realm.UserFederationMappers != nil {
+		err = i.readUserFederation(cr, realmClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.Events != nil {
+		i.EventsConfig, err = realmClient.GetRealmEventsConfig(cr.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.Realm.AuthenticationFlows != nil {
+		err = i.readAuthenticationFlows(cr, realmClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.Groups != nil {
+		i.Groups, err = realmClient.ListGroups(cr.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.DefaultGroups != nil {
+		err = i.readDefaultGroups(cr, realmClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.Roles != nil {
+		err = i.readRoles(cr, realmClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.Localization != nil {
+		err = i.readLocalization(cr, realmClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.DefaultClientScopes != nil || cr.Spec.OptionalClientScopes != nil {
+		err = i.readDefaultClientScopes(cr, realmClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.ClientProfiles != nil {
+		i.ClientProfiles, err = realmClient.ListClientProfiles(cr.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.ClientPolicies != nil {
+		i.ClientPolicies, err = realmClient.ListClientPolicies(cr.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.RequiredActions != nil {
+		i.RequiredActions, err = realmClient.ListRequiredActions(cr.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.RealmImport != nil {
+		err = i.readRealmImport(cr, controllerClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Themes are only checked when keycloak tells which ones it has, so that
+	// admins without access to the server info can still set them
+	i.AvailableThemes = nil
+	if cr.Spec.Themes != nil {
+		i.AvailableThemes, err = realmClient.ListThemes()
+		if err != nil {
+			logrus.Warnf("themes of realm %v/%v are not checked: %v", cr.Namespace, cr.Spec.Realm.Realm, err)
+			i.AvailableThemes = nil
+		}
+	}
+
+	i.UserImport = nil
+	if cr.Spec.UserImport != nil {
+		err = i.readUserImport(cr, controllerClient)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Get the state of the realm users
 	i.RealmUserSecrets = make(map[string]*v1.Secret)
 	for _, user := range cr.Spec.Realm.Users {
@@ -51,6 +239,260 @@ func (i *RealmState) Read(cr *kc.KeycloakRealm, realmClient KeycloakInterface, c
 	return nil
 }
 
+func (i *RealmState) readRoles(cr *kc.KeycloakRealm, realmClient KeycloakInterface) error {
+	var err error
+	i.Roles, err = realmClient.ListRealmRoles(cr.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	listComposites := func(roleName string) ([]kc.RoleRepresentation, error) {
+		return realmClient.ListRealmRoleComposites(roleName, cr.Spec.Realm.Realm)
+	}
+	i.RoleComposites, err = readRoleComposites(i.Roles, listComposites, realmClient, cr.Spec.Realm.Realm)
+	return err
+}
+
+func (i *RealmState) readLocalization(cr *kc.KeycloakRealm, realmClient KeycloakInterface) error {
+	locales, err := realmClient.ListRealmLocalizationLocales(cr.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	i.Localization = make(map[string]map[string]string)
+	for _, locale := range locales {
+		texts, err := realmClient.GetRealmLocalizationTexts(locale, cr.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+		i.Localization[locale] = texts
+	}
+	return nil
+}
+
+func (i *RealmState) readAuthenticationFlows(cr *kc.KeycloakRealm, realmClient KeycloakInterface) error {
+	var err error
+	i.AuthenticationFlows, err = realmClient.ListAuthenticationFlows(cr.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	i.AuthenticationExecutions = make(map[string][]*kc.AuthenticationExecutionInfo)
+	i.AuthenticatorConfigs = make(map[string]*kc.AuthenticatorConfig)
+	for _, flow := range i.AuthenticationFlows {
+		if !containsTopLevelFlow(cr.Spec.Realm.AuthenticationFlows, flow.Alias) {
+			continue
+		}
+
+		executions, err := realmClient.ListAuthenticationExecutionsForFlow(flow.Alias, cr.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+		i.AuthenticationExecutions[flow.Alias] = executions
+
+		for _, execution := range executions {
+			if execution.AuthenticationConfig == "" {
+				continue
+			}
+			config, err := realmClient.GetAuthenticatorConfig(execution.AuthenticationConfig, cr.Spec.Realm.Realm)
+			if err != nil {
+				return err
+			}
+			if config != nil {
+				i.AuthenticatorConfigs[execution.AuthenticationConfig] = config
+			}
+		}
+	}
+
+	return nil
+}
+
+func containsTopLevelFlow(flows []kc.KeycloakAPIAuthenticationFlow, alias string) bool {
+	for _, flow := range flows {
+		if flow.TopLevel && flow.Alias == alias {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *RealmState) readIdentityProviderSecrets(cr *kc.KeycloakRealm, controllerClient client.Client) error {
+	i.IdentityProviderSecrets = make(map[string]map[string]string)
+
+	for _, provider := range cr.Spec.Realm.IdentityProviders {
+		for key, ref := range provider.ConfigSecrets {
+			value, found, err := i.readSecretValue(cr, ref, controllerClient)
+			if err != nil {
+				return err
+			}
+			if !found {
+				continue
+			}
+
+			if i.IdentityProviderSecrets[provider.Alias] == nil {
+				i.IdentityProviderSecrets[provider.Alias] = make(map[string]string)
+			}
+			i.IdentityProviderSecrets[provider.Alias][key] = value
+		}
+	}
+
+	return nil
+}
+
+func (i *RealmState) readUserFederation(cr *kc.KeycloakRealm, realmClient KeycloakInterface) error {
+	var err error
+	i.UserFederationProviders, err = realmClient.ListComponents(i.Realm.Spec.Realm.ID, model.UserStorageProviderType, cr.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	if cr.Spec.Realm.UserFederationMappers == nil {
+		return nil
+	}
+	for _, provider := range i.UserFederationProviders {
+		mappers, err := realmClient.ListComponents(provider.ID, model.LDAPStorageMapperType, cr.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+		i.UserFederationMappers = append(i.UserFederationMappers, mappers...)
+	}
+	return nil
+}
+
+func (i *RealmState) readUserFederationSecrets(cr *kc.KeycloakRealm, controllerClient client.Client) error {
+	i.UserFederationSecrets = make(map[string]map[string]string)
+
+	for _, provider := range cr.Spec.Realm.UserFederationProviders {
+		for key, ref := range provider.ConfigSecrets {
+			value, found, err := i.readSecretValue(cr, ref, controllerClient)
+			if err != nil {
+				return err
+			}
+			if !found {
+				continue
+			}
+
+			if i.UserFederationSecrets[provider.DisplayName] == nil {
+				i.UserFederationSecrets[provider.DisplayName] = make(map[string]string)
+			}
+			i.UserFederationSecrets[provider.DisplayName][key] = value
+		}
+	}
+
+	return nil
+}
+
+func (i *RealmState) readSMTPServerSecrets(cr *kc.KeycloakRealm, controllerClient client.Client) error {
+	i.SMTPServerSecrets = make(map[string]string)
+
+	for key, ref := range cr.Spec.SMTPServerSecrets {
+		value, found, err := i.readSecretValue(cr, ref, controllerClient)
+		if err != nil {
+			return err
+		}
+		if found {
+			i.SMTPServerSecrets[key] = value
+		}
+	}
+
+	return nil
+}
+
+// Optional references to missing secrets or keys are reported as not found
+func (i *RealmState) readSecretValue(cr *kc.KeycloakRealm, ref v1.SecretKeySelector, controllerClient client.Client) (string, bool, error) {
+	optional := ref.Optional != nil && *ref.Optional
+
+	secret := &v1.Secret{}
+	err := controllerClient.Get(i.Context, client.ObjectKey{Name: ref.Name, Namespace: cr.Namespace}, secret)
+	if err != nil {
+		if errors.IsNotFound(err) && optional {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		if optional {
+			return "", false, nil
+		}
+		return "", false, pkgerrors.Errorf("key %v not found in secret %v/%v", ref.Key, cr.Namespace, ref.Name)
+	}
+
+	return string(value), true, nil
+}
+
+func (i *RealmState) readRealmImport(cr *kc.KeycloakRealm, controllerClient client.Client) error {
+	i.RealmImport = nil
+
+	if ref := cr.Spec.RealmImport.SecretKeyRef; ref != nil {
+		value, found, err := i.readSecretValue(cr, *ref, controllerClient)
+		if err != nil || !found {
+			return err
+		}
+		i.RealmImport = []byte(value)
+		return nil
+	}
+
+	ref := cr.Spec.RealmImport.ConfigMapKeyRef
+	if ref == nil {
+		return pkgerrors.Errorf("realmImport of realm %v/%v needs a configMapKeyRef or a secretKeyRef", cr.Namespace, cr.Spec.Realm.Realm)
+	}
+	value, found, err := i.readConfigMapValue(cr, *ref, controllerClient)
+	if err != nil || !found {
+		return err
+	}
+	i.RealmImport = value
+	return nil
+}
+
+func (i *RealmState) readUserImport(cr *kc.KeycloakRealm, controllerClient client.Client) error {
+	if ref := cr.Spec.UserImport.SecretKeyRef; ref != nil {
+		value, found, err := i.readSecretValue(cr, *ref, controllerClient)
+		if err != nil || !found {
+			return err
+		}
+		i.UserImport = []byte(value)
+		return nil
+	}
+
+	ref := cr.Spec.UserImport.ConfigMapKeyRef
+	if ref == nil {
+		return pkgerrors.Errorf("userImport of realm %v/%v needs a configMapKeyRef or a secretKeyRef", cr.Namespace, cr.Spec.Realm.Realm)
+	}
+	value, found, err := i.readConfigMapValue(cr, *ref, controllerClient)
+	if err != nil || !found {
+		return err
+	}
+	i.UserImport = value
+	return nil
+}
+
+// Optional references to missing config maps or keys are reported as not found
+func (i *RealmState) readConfigMapValue(cr *kc.KeycloakRealm, ref v1.ConfigMapKeySelector, controllerClient client.Client) ([]byte, bool, error) {
+	optional := ref.Optional != nil && *ref.Optional
+
+	configMap := &v1.ConfigMap{}
+	err := controllerClient.Get(i.Context, client.ObjectKey{Name: ref.Name, Namespace: cr.Namespace}, configMap)
+	if err != nil {
+		if errors.IsNotFound(err) && optional {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	if value, ok := configMap.Data[ref.Key]; ok {
+		return []byte(value), true, nil
+	}
+	if value, ok := configMap.BinaryData[ref.Key]; ok {
+		return value, true, nil
+	}
+	if optional {
+		return nil, false, nil
+	}
+	return nil, false, pkgerrors.Errorf("key %v not found in config map %v/%v", ref.Key, cr.Namespace, ref.Name)
+}
+
 func (i *RealmState) readRealmUserSecret(realm *kc.KeycloakRealm, user *kc.KeycloakAPIUser, controllerClient client.Client) (*v1.Secret, error) {
 	key := model.RealmCredentialSecretSelector(realm, user, i.Keycloak)
 	secret := &v1.Secret{}
@@ -66,3 +508,39 @@ func (i *RealmState) readRealmUserSecret(realm *kc.KeycloakRealm, user *kc.Keycl
 
 	return secret, err
 }
+
+// Default groups are compared by ID, so the paths in the CR are resolved first
+func (i *RealmState) readDefaultGroups(cr *kc.KeycloakRealm, realmClient KeycloakInterface) error {
+	var err error
+	i.DefaultGroups, err = realmClient.ListDefaultGroups(cr.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+
+	i.DesiredDefaultGroups = make(map[string]*kc.KeycloakUserGroup)
+	for _, path := range cr.Spec.DefaultGroups {
+		i.DesiredDefaultGroups[path], err = realmClient.GetGroupByPath(path, cr.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *RealmState) readDefaultClientScopes(cr *kc.KeycloakRealm, realmClient KeycloakInterface) error {
+	var err error
+	i.ClientScopes, err = realmClient.ListClientScopes(cr.Spec.Realm.Realm)
+	if err != nil {
+		return err
+	}
+	if cr.Spec.DefaultClientScopes != nil {
+		i.DefaultClientScopes, err = realmClient.ListRealmDefaultClientScopes(false, cr.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+	}
+	if cr.Spec.OptionalClientScopes != nil {
+		i.OptionalClientScopes, err = realmClient.ListRealmDefaultClientScopes(true, cr.Spec.Realm.Realm)
+	}
+	return err
+}
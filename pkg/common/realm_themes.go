@@ -0,0 +1,34 @@
+package common
+
+import (
+	"fmt"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+)
+
+// Themes set in the CR keyed by their type, as keycloak names them in the server info
+func RealmThemesByType(themes *kc.KeycloakRealmThemes) map[string]string {
+	return map[string]string{
+		"login":   themes.LoginTheme,
+		"account": themes.AccountTheme,
+		"email":   themes.EmailTheme,
+		"admin":   themes.AdminTheme,
+	}
+}
+
+// Returns a message for each theme set in the CR that keycloak doesn't have, keyed by
+// type. Nothing is reported when the available themes are unknown
+func UnavailableRealmThemes(themes *kc.KeycloakRealmThemes, available map[string][]string) map[string]string {
+	unavailable := make(map[string]string)
+	if themes == nil || available == nil {
+		return unavailable
+	}
+
+	for themeType, name := range RealmThemesByType(themes) {
+		if name == "" || containsString(available[themeType], name) {
+			continue
+		}
+		unavailable[themeType] = fmt.Sprintf("%v theme %v is not available in keycloak, it is not set", themeType, name)
+	}
+	return unavailable
+}
@@ -0,0 +1,71 @@
+package common
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+// Check redirect URIs the way keycloak matches them: a path relative to the root URL
+// or an absolute URL, either of which may end in a single wildcard
+func ValidateRedirectURIs(uris []string, validation string) error {
+	if validation == "" {
+		return nil
+	}
+
+	for _, uri := range uris {
+		err := validateRedirectURI(uri, validation == v1alpha1.RedirectURIValidationStrict)
+		if err != nil {
+			return NewInvalidSpecError(errors.Wrapf(err, "invalid redirect uri %q", uri))
+		}
+	}
+
+	return nil
+}
+
+func validateRedirectURI(uri string, strict bool) error {
+	if uri == "" || strings.IndexFunc(uri, unicode.IsSpace) >= 0 {
+		return errors.New("must not be empty or contain whitespace")
+	}
+
+	if uri == "*" {
+		if strict {
+			return errors.New("a lone wildcard allows redirects anywhere")
+		}
+		return nil
+	}
+
+	// keycloak only treats a trailing wildcard as such
+	trimmed := strings.TrimSuffix(uri, "*")
+	if strings.Contains(trimmed, "*") {
+		return errors.New("a wildcard is only allowed at the end")
+	}
+
+	parsed, err := url.Parse(trimmed)
+	if err != nil {
+		return errors.Errorf("does not parse as an URL: %v", err)
+	}
+
+	if parsed.Scheme == "" {
+		switch {
+		case strict:
+			return errors.New("must be an absolute http or https URL")
+		case !strings.HasPrefix(trimmed, "/"):
+			return errors.New("has no scheme, use an absolute URL or a path starting with /")
+		}
+		return nil
+	}
+
+	isHTTP := parsed.Scheme == "http" || parsed.Scheme == "https"
+	if strict && !isHTTP {
+		return errors.Errorf("scheme %v is not allowed, use http or https", parsed.Scheme)
+	}
+	if isHTTP && parsed.Host == "" {
+		return errors.New("has no host")
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirectURIs_Test_Validate(t *testing.T) {
+	// given
+	valid := []string{
+		"https://example.com/callback",
+		"https://example.com/*",
+		"http://localhost:8080/*",
+	}
+	lenientOnly := []string{
+		"*",
+		"/relative/*",
+		"com.example.app:/oauth2redirect",
+	}
+	invalid := []string{
+		"",
+		"example.com/callback",
+		"https://*.example.com/callback",
+		"https://example.com/a b",
+		"https:///callback",
+	}
+
+	// then
+	for _, uri := range valid {
+		assert.NoError(t, ValidateRedirectURIs([]string{uri}, v1alpha1.RedirectURIValidationStrict), uri)
+	}
+	for _, uri := range lenientOnly {
+		assert.NoError(t, ValidateRedirectURIs([]string{uri}, v1alpha1.RedirectURIValidationLenient), uri)
+		assert.Error(t, ValidateRedirectURIs([]string{uri}, v1alpha1.RedirectURIValidationStrict), uri)
+	}
+	for _, uri := range invalid {
+		err := ValidateRedirectURIs([]string{uri}, v1alpha1.RedirectURIValidationLenient)
+		assert.Error(t, err, uri)
+		// fixed by changing the spec, which is reconciled without a requeue
+		assert.True(t, IsInvalidSpecError(err), uri)
+		assert.False(t, IsTransientError(err), uri)
+	}
+	assert.NoError(t, ValidateRedirectURIs(invalid, ""))
+	assert.Contains(t, ValidateRedirectURIs([]string{"example.com"}, v1alpha1.RedirectURIValidationLenient).Error(), "invalid redirect uri \"example.com\"")
+}
@@ -0,0 +1,36 @@
+package common
+
+import (
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+)
+
+// The settings of the desired required action over the current ones, as keycloak
+// expects all of them when updating it
+func RequiredActionReconciled(desired, current *kc.KeycloakAPIRequiredAction) *kc.KeycloakAPIRequiredAction {
+	reconciled := current.DeepCopy()
+	if desired.Name != "" {
+		reconciled.Name = desired.Name
+	}
+	if desired.Enabled != nil {
+		reconciled.Enabled = desired.Enabled
+	}
+	if desired.DefaultAction != nil {
+		reconciled.DefaultAction = desired.DefaultAction
+	}
+	if desired.Priority != nil {
+		reconciled.Priority = desired.Priority
+	}
+	if desired.Config != nil {
+		reconciled.Config = desired.Config
+	}
+	return reconciled
+}
+
+func FindRequiredAction(actions []kc.KeycloakAPIRequiredAction, alias string) *kc.KeycloakAPIRequiredAction {
+	for i := range actions {
+		if actions[i].Alias == alias {
+			return &actions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package common
+
+import (
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+)
+
+type RoleUpdate struct {
+	Role    kc.RoleRepresentation
+	OldRole kc.RoleRepresentation
+}
+
+// Changes needed to turn a list of existing roles into the desired ones. Roles
+// are matched by ID when both have one, by name otherwise
+type RolesDiff struct {
+	Deleted []kc.RoleRepresentation
+	Updated []RoleUpdate
+	Created []kc.RoleRepresentation
+}
+
+func DiffRoles(existing []kc.RoleRepresentation, desired []kc.RoleRepresentation) RolesDiff {
+	diff := RolesDiff{}
+
+	// delete existing roles for which no desired role is found that (matches by ID OR has no ID but matches by name)
+	// this implies that specifying a role with matching name but different ID will result in deletion (and re-creation)
+	diff.Deleted, _ = RoleDifferenceIntersection(existing, desired)
+
+	// update with desired roles that can be matched to existing roles and have an ID set, this includes all renames
+	// note down all renames
+	existingRoleByID := make(map[string]kc.RoleRepresentation)
+	for _, role := range existing {
+		existingRoleByID[role.ID] = role
+	}
+	renamedRolesOldNames := make(map[string]bool)
+	_, rolesMatching := RoleDifferenceIntersection(desired, existing)
+	for _, role := range rolesMatching {
+		if role.ID != "" {
+			oldRole := existingRoleByID[role.ID]
+			diff.Updated = append(diff.Updated, RoleUpdate{Role: role, OldRole: oldRole})
+			if role.Name != oldRole.Name {
+				renamedRolesOldNames[oldRole.Name] = true
+			}
+		}
+	}
+
+	// seemingly matching roles without an ID can either be regular updates
+	// or re-creations after renames (not deletions)
+	// note that duplicate role names are impossible thanks to +listType=map
+	var rolesRecreated []kc.RoleRepresentation
+	for _, role := range rolesMatching {
+		if role.ID == "" {
+			if _, contains := renamedRolesOldNames[role.Name]; contains {
+				rolesRecreated = append(rolesRecreated, role)
+			} else {
+				diff.Updated = append(diff.Updated, RoleUpdate{Role: role, OldRole: role})
+			}
+		}
+	}
+
+	// always create roles that don't match any existing ones
+	rolesNew, _ := RoleDifferenceIntersection(desired, existing)
+	diff.Created = append(rolesRecreated, rolesNew...)
+
+	return diff
+}
+
+// returned roles are always from a. Matches the same roles as RoleMatches, but
+// indexes b once instead of scanning it for every role of a
+func RoleDifferenceIntersection(a []kc.RoleRepresentation, b []kc.RoleRepresentation) (d []kc.RoleRepresentation, i []kc.RoleRepresentation) {
+	index := newRoleIndex(b)
+	for _, role := range a {
+		if index.hasMatchingRole(role) {
+			i = append(i, role)
+		} else {
+			d = append(d, role)
+		}
+	}
+	return d, i
+}
+
+type roleIndex struct {
+	ids map[string]bool
+	// Names of all roles, and of those without an ID
+	names          map[string]bool
+	namesWithoutID map[string]bool
+}
+
+func newRoleIndex(roles []kc.RoleRepresentation) roleIndex {
+	index := roleIndex{
+		ids:            make(map[string]bool, len(roles)),
+		names:          make(map[string]bool, len(roles)),
+		namesWithoutID: make(map[string]bool),
+	}
+	for _, role := range roles {
+		index.names[role.Name] = true
+		if role.ID == "" {
+			index.namesWithoutID[role.Name] = true
+		} else {
+			index.ids[role.ID] = true
+		}
+	}
+	return index
+}
+
+// A role with an ID matches roles of the same ID, or of the same name if they have
+// no ID. A role without an ID matches any role of the same name
+func (i roleIndex) hasMatchingRole(role kc.RoleRepresentation) bool {
+	if role.ID == "" {
+		return i.names[role.Name]
+	}
+	return i.ids[role.ID] || i.namesWithoutID[role.Name]
+}
+
+func RoleMatches(a kc.RoleRepresentation, b kc.RoleRepresentation) bool {
+	if a.ID != "" && b.ID != "" {
+		return a.ID == b.ID
+	}
+	return a.Name == b.Name
+}
+
+// Returns the composites of a that are not part of b, or nil if there are none
+func CompositesDifference(a, b *kc.RoleRepresentationComposites) *kc.RoleRepresentationComposites {
+	if a == nil {
+		return nil
+	}
+	if b == nil {
+		b = &kc.RoleRepresentationComposites{}
+	}
+
+	d := &kc.RoleRepresentationComposites{}
+	empty := true
+	for _, role := range a.Realm {
+		if !containsString(b.Realm, role) {
+			d.Realm = append(d.Realm, role)
+			empty = false
+		}
+	}
+	for clientID, roles := range a.Client {
+		for _, role := range roles {
+			if !containsString(b.Client[clientID], role) {
+				if d.Client == nil {
+					d.Client = make(map[string][]string)
+				}
+				d.Client[clientID] = append(d.Client[clientID], role)
+				empty = false
+			}
+		}
+	}
+
+	if empty {
+		return nil
+	}
+	return d
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Composite roles reference client roles by client UUID, while CRs use the
+// human readable client ID, so the clients of the realm are only listed when
+// a client role composite is found
+func readRoleComposites(roles []kc.RoleRepresentation, listComposites func(roleName string) ([]kc.RoleRepresentation, error), realmClient KeycloakInterface, realm string) (map[string]*kc.RoleRepresentationComposites, error) {
+	result := make(map[string]*kc.RoleRepresentationComposites)
+
+	var clientIDs map[string]string
+	for _, role := range roles {
+		if role.Composite == nil || !*role.Composite {
+			continue
+		}
+
+		composites, err := listComposites(role.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		current := &kc.RoleRepresentationComposites{}
+		for _, composite := range composites {
+			if composite.ClientRole == nil || !*composite.ClientRole {
+				current.Realm = append(current.Realm, composite.Name)
+				continue
+			}
+
+			if clientIDs == nil {
+				clientIDs, err = readClientIDs(realmClient, realm)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if current.Client == nil {
+				current.Client = make(map[string][]string)
+			}
+			clientID := clientIDs[composite.ContainerID]
+			current.Client[clientID] = append(current.Client[clientID], composite.Name)
+		}
+		result[role.Name] = current
+	}
+
+	return result, nil
+}
+
+func readClientIDs(realmClient KeycloakInterface, realm string) (map[string]string, error) {
+	clients, err := realmClient.ListClients(realm)
+	if err != nil {
+		return nil, err
+	}
+
+	clientIDs := make(map[string]string)
+	for _, client := range clients {
+		clientIDs[client.ID] = client.ClientID
+	}
+	return clientIDs, nil
+}
@@ -0,0 +1,97 @@
+package common
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleDiff_Test_Role_DifferenceIntersection(t *testing.T) {
+	// given
+	a := []v1alpha1.RoleRepresentation{
+		{Name: "a"},
+		{ID: "ignored", Name: "b"},
+		{ID: "cID", Name: "c"},
+	}
+	b := []v1alpha1.RoleRepresentation{
+		{Name: "b"},
+		{ID: "cID", Name: "differentName"},
+		{Name: "d"},
+	}
+
+	// when
+	difference, intersection := RoleDifferenceIntersection(a, b)
+
+	// then
+	expectedDifference := []v1alpha1.RoleRepresentation{
+		{Name: "a"},
+	}
+	expectedIntersection := []v1alpha1.RoleRepresentation{
+		{ID: "ignored", Name: "b"},
+		{ID: "cID", Name: "c"},
+	}
+	assert.Equal(t, expectedDifference, difference)
+	assert.Equal(t, expectedIntersection, intersection)
+}
+
+// The quadratic implementation RoleDifferenceIntersection replaced, kept to prove
+// both match the same roles
+func naiveRoleDifferenceIntersection(a []v1alpha1.RoleRepresentation, b []v1alpha1.RoleRepresentation) (d []v1alpha1.RoleRepresentation, i []v1alpha1.RoleRepresentation) {
+	for _, role := range a {
+		matching := false
+		for _, other := range b {
+			if RoleMatches(other, role) {
+				matching = true
+				break
+			}
+		}
+		if matching {
+			i = append(i, role)
+		} else {
+			d = append(d, role)
+		}
+	}
+	return d, i
+}
+
+// Roles with and without IDs, whose IDs and names partly overlap
+func getMixedRoles(count, offset int) []v1alpha1.RoleRepresentation {
+	var roles []v1alpha1.RoleRepresentation
+	for index := 0; index < count; index++ {
+		n := index + offset
+		role := v1alpha1.RoleRepresentation{Name: fmt.Sprintf("role-%d", n%(count/2+1))}
+		if n%3 != 0 {
+			role.ID = fmt.Sprintf("id-%d", n%(count/3+1))
+		}
+		roles = append(roles, role)
+	}
+	return roles
+}
+
+func TestRoleDiff_Test_Role_DifferenceIntersection_Matches_Naive(t *testing.T) {
+	for _, sizes := range [][3]int{{0, 5, 0}, {5, 0, 0}, {7, 11, 2}, {20, 20, 5}, {30, 9, 13}, {100, 80, 17}} {
+		// given
+		a := getMixedRoles(sizes[0], 0)
+		b := getMixedRoles(sizes[1], sizes[2])
+
+		// when
+		difference, intersection := RoleDifferenceIntersection(a, b)
+
+		// then
+		expectedDifference, expectedIntersection := naiveRoleDifferenceIntersection(a, b)
+		assert.Equal(t, expectedDifference, difference)
+		assert.Equal(t, expectedIntersection, intersection)
+	}
+}
+
+func BenchmarkRoleDiff_Role_DifferenceIntersection(b *testing.B) {
+	existing := getMixedRoles(500, 0)
+	desired := getMixedRoles(500, 250)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		RoleDifferenceIntersection(existing, desired)
+	}
+}
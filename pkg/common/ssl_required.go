@@ -0,0 +1,17 @@
+package common
+
+import (
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+// The CRD only checks the value on clusters that validate its schema, keycloak
+// itself accepts any value and fails the logins of the realm then
+func ValidateSslRequired(sslRequired string) error {
+	switch sslRequired {
+	case "", v1alpha1.SslRequiredAll, v1alpha1.SslRequiredExternal, v1alpha1.SslRequiredNone:
+		return nil
+	}
+	return errors.Errorf("invalid sslRequired %q, expected one of %v, %v or %v", sslRequired,
+		v1alpha1.SslRequiredAll, v1alpha1.SslRequiredExternal, v1alpha1.SslRequiredNone)
+}
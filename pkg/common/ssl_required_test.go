@@ -0,0 +1,16 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSslRequired_Test_Validate(t *testing.T) {
+	// then
+	for _, value := range []string{"", v1alpha1.SslRequiredAll, v1alpha1.SslRequiredExternal, v1alpha1.SslRequiredNone} {
+		assert.NoError(t, ValidateSslRequired(value), value)
+	}
+	assert.Contains(t, ValidateSslRequired("External").Error(), "invalid sslRequired \"External\"")
+}
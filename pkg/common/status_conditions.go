@@ -0,0 +1,59 @@
+package common
+
+import (
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/pkg/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetSuccessConditions reports a resource whose desired state was reached
+func SetSuccessConditions(conditions *[]v1alpha1.Condition, generation int64) {
+	setConditions(conditions, generation, v1.ConditionTrue, v1.ConditionFalse, v1.ConditionFalse, v1alpha1.ReasonReconciled, "", "")
+}
+
+// SetErrorConditions reports a resource whose reconcile failed. When an action
+// failed its message tells what couldn't be done
+func SetErrorConditions(conditions *[]v1alpha1.Condition, generation int64, issue error) {
+	reason := v1alpha1.ReasonReconcileFailed
+	message := issue.Error()
+	var actionError *ActionError
+	if errors.As(issue, &actionError) {
+		reason = v1alpha1.ReasonActionFailed
+		message = actionError.Msg
+	}
+	setConditions(conditions, generation, v1.ConditionFalse, v1.ConditionFalse, v1.ConditionTrue, reason, message, issue.Error())
+}
+
+// SetTransientErrorConditions reports a resource that is retried until keycloak
+// is available again
+func SetTransientErrorConditions(conditions *[]v1alpha1.Condition, generation int64, issue error) {
+	setConditions(conditions, generation, v1.ConditionFalse, v1.ConditionTrue, v1.ConditionFalse, v1alpha1.ReasonKeycloakUnavailable, issue.Error(), "")
+}
+
+func setConditions(conditions *[]v1alpha1.Condition, generation int64, ready, reconciling, failed v1.ConditionStatus, reason, message, errorMessage string) {
+	now := v1.Now()
+	v1alpha1.SetCondition(conditions, v1alpha1.Condition{
+		Type:               v1alpha1.ConditionReady,
+		Status:             ready,
+		ObservedGeneration: generation,
+		LastTransitionTime: now,
+		Reason:             reason,
+		Message:            message,
+	})
+	v1alpha1.SetCondition(conditions, v1alpha1.Condition{
+		Type:               v1alpha1.ConditionReconciling,
+		Status:             reconciling,
+		ObservedGeneration: generation,
+		LastTransitionTime: now,
+		Reason:             reason,
+		Message:            message,
+	})
+	v1alpha1.SetCondition(conditions, v1alpha1.Condition{
+		Type:               v1alpha1.ConditionError,
+		Status:             failed,
+		ObservedGeneration: generation,
+		LastTransitionTime: now,
+		Reason:             reason,
+		Message:            errorMessage,
+	})
+}
@@ -0,0 +1,74 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStatusConditions_Test_Failed_Action(t *testing.T) {
+	// given
+	cr := &v1alpha1.KeycloakRealm{}
+	desiredState := DesiredClusterState{}
+	desiredState.AddAction(&CreateRealmAction{Ref: cr, Msg: "create realm"})
+	err := NewClusterActionRunner(context.TODO(), nil, nil, cr).RunAll(desiredState)
+	assert.Error(t, err)
+
+	// when
+	SetErrorConditions(&cr.Status.Conditions, 3, err)
+
+	// then
+	// the message of the failed action tells what couldn't be done
+	ready := v1alpha1.FindCondition(cr.Status.Conditions, v1alpha1.ConditionReady)
+	assert.NotNil(t, ready)
+	assert.Equal(t, v1.ConditionFalse, ready.Status)
+	assert.Equal(t, v1alpha1.ReasonActionFailed, ready.Reason)
+	assert.Equal(t, "create realm", ready.Message)
+	assert.Equal(t, int64(3), ready.ObservedGeneration)
+
+	failed := v1alpha1.FindCondition(cr.Status.Conditions, v1alpha1.ConditionError)
+	assert.Equal(t, v1.ConditionTrue, failed.Status)
+	assert.Equal(t, err.Error(), failed.Message)
+	assert.Equal(t, v1.ConditionFalse, v1alpha1.FindCondition(cr.Status.Conditions, v1alpha1.ConditionReconciling).Status)
+
+	// when
+	SetErrorConditions(&cr.Status.Conditions, 4, errors.New("invalid realm"))
+
+	// then
+	assert.Len(t, cr.Status.Conditions, 3)
+	assert.Equal(t, v1alpha1.ReasonReconcileFailed, ready.Reason)
+	assert.Equal(t, "invalid realm", ready.Message)
+}
+
+func TestStatusConditions_Test_Transitions(t *testing.T) {
+	// given
+	transition := v1.NewTime(time.Now().Add(-time.Hour))
+	conditions := []v1alpha1.Condition{
+		{Type: v1alpha1.ConditionReady, Status: v1.ConditionTrue, LastTransitionTime: transition},
+		{Type: v1alpha1.ConditionReconciling, Status: v1.ConditionFalse, LastTransitionTime: transition},
+	}
+
+	// when
+	SetSuccessConditions(&conditions, 2)
+
+	// then
+	// the transition time is kept as long as the status doesn't change
+	assert.Len(t, conditions, 3)
+	assert.Equal(t, transition, conditions[0].LastTransitionTime)
+	assert.Equal(t, v1alpha1.ReasonReconciled, conditions[0].Reason)
+	assert.Equal(t, v1.ConditionFalse, conditions[2].Status)
+
+	// when
+	SetTransientErrorConditions(&conditions, 2, NewTransientError(errors.New("connection refused")))
+
+	// then
+	assert.Equal(t, v1.ConditionFalse, conditions[0].Status)
+	assert.NotEqual(t, transition, conditions[0].LastTransitionTime)
+	assert.Equal(t, v1.ConditionTrue, conditions[1].Status)
+	assert.Equal(t, v1alpha1.ReasonKeycloakUnavailable, conditions[1].Reason)
+}
@@ -0,0 +1,144 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// An error that is expected to go away on its own, like keycloak refusing connections
+// or answering with a gateway error while it restarts
+type TransientError struct {
+	err error
+}
+
+func NewTransientError(err error) error {
+	return &TransientError{err: err}
+}
+
+func (e *TransientError) Error() string {
+	return e.err.Error()
+}
+
+func (e *TransientError) Unwrap() error {
+	return e.err
+}
+
+func IsTransientError(err error) bool {
+	var transient *TransientError
+	return errors.As(err, &transient)
+}
+
+// An error in the spec of a resource, which only a change of the spec fixes. That
+// change triggers a reconcile of its own, so the resource isn't requeued
+type InvalidSpecError struct {
+	err error
+}
+
+func NewInvalidSpecError(err error) error {
+	return &InvalidSpecError{err: err}
+}
+
+func (e *InvalidSpecError) Error() string {
+	return e.err.Error()
+}
+
+func (e *InvalidSpecError) Unwrap() error {
+	return e.err
+}
+
+func IsInvalidSpecError(err error) bool {
+	var invalid *InvalidSpecError
+	return errors.As(err, &invalid)
+}
+
+// Status codes of a proxy in front of keycloak, or of keycloak itself, while it isn't ready
+func isTransientStatus(statusCode int) bool {
+	switch statusCode {
+	case 502, 503, 504:
+		return true
+	}
+	return false
+}
+
+// Delays the requeue of resources failing on transient errors, doubling the delay on
+// every consecutive failure of the same resource up to a maximum
+type Backoff struct {
+	initial  time.Duration
+	max      time.Duration
+	mutex    sync.Mutex
+	failures map[string]int
+}
+
+func NewBackoff(initial, max time.Duration) *Backoff {
+	return &Backoff{
+		initial:  initial,
+		max:      max,
+		failures: make(map[string]int),
+	}
+}
+
+// Records another failure of the resource and returns the delay before its next attempt
+func (b *Backoff) Next(obj v1.Object) time.Duration {
+	key := backoffKey(obj)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delay := b.initial
+	for i := 0; i < b.failures[key] && delay < b.max; i++ {
+		delay *= 2
+	}
+	if delay > b.max {
+		delay = b.max
+	}
+	b.failures[key]++
+	return delay
+}
+
+func (b *Backoff) Reset(obj v1.Object) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	delete(b.failures, backoffKey(obj))
+}
+
+func backoffKey(obj v1.Object) string {
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// Status message of a resource waiting for keycloak to become available again
+func TransientErrorMessage(delay time.Duration, err error) string {
+	return fmt.Sprintf("keycloak is unavailable, retrying in %v: %v", delay, err)
+}
+
+// Resource of the operator whose status is updated while keycloak is unavailable
+type TransientErrorObject interface {
+	runtime.Object
+	v1.Object
+}
+
+// The phase is left alone, the resource isn't failing. setStatus records the message
+// in the status of the resource, which is updated. Consecutive attempts are spread out
+// further and further
+func ManageTransientError(ctx context.Context, controllerClient client.Client, backoff *Backoff, obj TransientErrorObject, issue error, setStatus func(message string)) (reconcile.Result, error) {
+	delay := backoff.Next(obj)
+	logrus.Infof("keycloak unavailable for %v/%v, retrying in %v: %v", obj.GetNamespace(), obj.GetName(), delay, issue)
+
+	setStatus(TransientErrorMessage(delay, issue))
+	err := controllerClient.Status().Update(ctx, obj)
+	if err != nil {
+		logrus.Errorf("unable to update status: %v", err)
+	}
+
+	return reconcile.Result{
+		RequeueAfter: delay,
+		Requeue:      true,
+	}, nil
+}
@@ -0,0 +1,43 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBackoff(t *testing.T) {
+	// given
+	backoff := NewBackoff(time.Second, 5*time.Second)
+	realm := &v1alpha1.KeycloakRealm{ObjectMeta: v1.ObjectMeta{Namespace: "test", Name: "realm"}}
+	other := &v1alpha1.KeycloakRealm{ObjectMeta: v1.ObjectMeta{Namespace: "test", Name: "other"}}
+
+	// then
+	// delays double up to the maximum, for each resource on its own
+	assert.Equal(t, time.Second, backoff.Next(realm))
+	assert.Equal(t, 2*time.Second, backoff.Next(realm))
+	assert.Equal(t, 4*time.Second, backoff.Next(realm))
+	assert.Equal(t, 5*time.Second, backoff.Next(realm))
+	assert.Equal(t, time.Second, backoff.Next(other))
+
+	// when
+	backoff.Reset(realm)
+
+	// then
+	assert.Equal(t, time.Second, backoff.Next(realm))
+}
+
+func TestIsTransientError(t *testing.T) {
+	// given
+	transient := NewTransientError(errors.New("connection refused"))
+
+	// then
+	assert.True(t, IsTransientError(transient))
+	assert.True(t, IsTransientError(errors.Wrap(transient, "error performing ping request")))
+	assert.False(t, IsTransientError(errors.New("realm not found")))
+	assert.Equal(t, "connection refused", transient.Error())
+}
@@ -16,6 +16,8 @@ type UserState struct {
 	RealmRoles           []*v1alpha1.KeycloakUserRole
 	AvailableClientRoles map[string][]*v1alpha1.KeycloakUserRole
 	AvailableRealmRoles  []*v1alpha1.KeycloakUserRole
+	Groups               []*v1alpha1.KeycloakUserGroup
+	FederatedIdentities  []v1alpha1.FederatedIdentity
 	Clients              []*v1alpha1.KeycloakAPIClient
 	Secret               *v1.Secret
 	Keycloak             v1alpha1.Keycloak
@@ -53,6 +55,22 @@ func (i *UserState) Read(keycloakClient KeycloakInterface, userClient client.Cli
 		return err
 	}
 
+	// Group memberships are only managed when requested
+	if user.Spec.User.Groups != nil {
+		i.Groups, err = keycloakClient.ListUserGroups(realm.Spec.Realm.Realm, i.User.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Same for the links to identity providers
+	if user.Spec.User.FederatedIdentities != nil {
+		i.FederatedIdentities, err = keycloakClient.GetUserFederatedIdentities(i.User.ID, realm.Spec.Realm.Realm)
+		if err != nil {
+			return err
+		}
+	}
+
 	return i.readSecretState(userClient, user, &realm)
 }
 
@@ -0,0 +1,51 @@
+package common
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Allows the origins of the redirect URIs of the client
+	WebOriginRedirectURIs = "+"
+	// Allows any origin
+	WebOriginAny = "*"
+)
+
+// Check web origins the way keycloak compares them with the Origin header of a
+// request: scheme, host and port, without a path or a trailing slash
+func ValidateWebOrigins(origins []string) error {
+	for _, origin := range origins {
+		err := validateWebOrigin(origin)
+		if err != nil {
+			return errors.Wrapf(err, "invalid web origin %q", origin)
+		}
+	}
+
+	return nil
+}
+
+func validateWebOrigin(origin string) error {
+	if origin == WebOriginRedirectURIs || origin == WebOriginAny {
+		return nil
+	}
+
+	if strings.Contains(origin, "*") {
+		return errors.New("wildcards are not supported, use \"*\" alone to allow any origin")
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return errors.Errorf("does not parse as an URL: %v", err)
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return errors.New("must be a scheme and a host, e.g. https://example.com")
+	}
+	if parsed.Path != "" || parsed.RawQuery != "" || parsed.Fragment != "" || parsed.User != nil {
+		return errors.New("must not have a path, query or trailing slash, the browser never sends them")
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebOrigins_Test_Validate(t *testing.T) {
+	// given
+	valid := []string{
+		WebOriginRedirectURIs,
+		WebOriginAny,
+		"https://example.com",
+		"http://localhost:3000",
+	}
+	invalid := []string{
+		"",
+		"example.com",
+		"https://example.com/",
+		"https://example.com/app",
+		"https://*.example.com",
+	}
+
+	// then
+	assert.NoError(t, ValidateWebOrigins(valid))
+	for _, origin := range invalid {
+		assert.Error(t, ValidateWebOrigins([]string{origin}), origin)
+	}
+	assert.Contains(t, ValidateWebOrigins([]string{"https://example.com/"}).Error(), "invalid web origin \"https://example.com/\"")
+}
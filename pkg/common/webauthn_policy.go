@@ -0,0 +1,40 @@
+package common
+
+import (
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+var webAuthnSignatureAlgorithms = []string{"ES256", "ES384", "ES512", "RS256", "RS384", "RS512", "RS1"}
+
+// The CRD only checks the values on clusters that validate its schema, keycloak
+// itself accepts any value and fails the registration of authenticators then
+func ValidateWebAuthnPolicy(name string, policy *v1alpha1.KeycloakRealmWebAuthnPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	settings := []struct {
+		field   string
+		value   string
+		allowed []string
+	}{
+		{"attestationConveyancePreference", policy.AttestationConveyancePreference, []string{"none", "indirect", "direct"}},
+		{"authenticatorAttachment", policy.AuthenticatorAttachment, []string{"platform", "cross-platform"}},
+		{"requireResidentKey", policy.RequireResidentKey, []string{"Yes", "No"}},
+		{"userVerificationRequirement", policy.UserVerificationRequirement, []string{"required", "preferred", "discouraged"}},
+	}
+	for _, setting := range settings {
+		allowed := append([]string{v1alpha1.WebAuthnNotSpecified}, setting.allowed...)
+		if setting.value != "" && !containsString(allowed, setting.value) {
+			return errors.Errorf("invalid %v.%v %q, expected one of %q", name, setting.field, setting.value, allowed)
+		}
+	}
+
+	for _, algorithm := range policy.SignatureAlgorithms {
+		if !containsString(webAuthnSignatureAlgorithms, algorithm) {
+			return errors.Errorf("invalid %v.signatureAlgorithms %q, expected any of %q", name, algorithm, webAuthnSignatureAlgorithms)
+		}
+	}
+	return nil
+}
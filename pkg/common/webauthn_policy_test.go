@@ -0,0 +1,35 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebAuthnPolicy_Test_Validate(t *testing.T) {
+	// given
+	policy := &v1alpha1.KeycloakRealmWebAuthnPolicy{
+		SignatureAlgorithms:         []string{"ES256", "RS256"},
+		AuthenticatorAttachment:     v1alpha1.WebAuthnNotSpecified,
+		RequireResidentKey:          "Yes",
+		UserVerificationRequirement: "required",
+	}
+
+	// then
+	assert.NoError(t, ValidateWebAuthnPolicy("webAuthnPolicy", policy))
+	assert.NoError(t, ValidateWebAuthnPolicy("webAuthnPolicy", nil))
+
+	// when
+	policy.RequireResidentKey = "yes"
+
+	// then
+	assert.Contains(t, ValidateWebAuthnPolicy("webAuthnPasswordlessPolicy", policy).Error(), "invalid webAuthnPasswordlessPolicy.requireResidentKey \"yes\"")
+
+	// when
+	policy.RequireResidentKey = ""
+	policy.SignatureAlgorithms = []string{"EdDSA"}
+
+	// then
+	assert.Contains(t, ValidateWebAuthnPolicy("webAuthnPolicy", policy).Error(), "invalid webAuthnPolicy.signatureAlgorithms \"EdDSA\"")
+}
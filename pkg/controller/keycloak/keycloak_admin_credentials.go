@@ -0,0 +1,104 @@
+package keycloak
+
+import (
+	"time"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/common"
+	"github.com/keycloak/keycloak-operator/pkg/model"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const adminRealm = "master"
+
+// Rotations are asked for with the annotation or are due once the period passed.
+// One that was interrupted is always finished. Referenced credentials are rotated
+// by whoever manages them
+func isAdminCredentialsRotationDue(cr *v1alpha1.Keycloak, secret *corev1.Secret) bool {
+	if secret == nil || cr.Spec.AdminCredentialsRef != nil {
+		return false
+	}
+	if len(secret.Data[model.AdminPasswordPendingProperty]) > 0 {
+		return true
+	}
+	if _, ok := cr.Annotations[model.RotateAdminCredentialsAnnotation]; ok {
+		return true
+	}
+	period := cr.Spec.AdminCredentialsRotationPeriod
+	if period == nil || period.Duration <= 0 {
+		return false
+	}
+	return time.Since(model.KeycloakAdminSecretLastRotation(secret)) >= period.Duration
+}
+
+// Time left until the next rotation of the admin credentials, zero when they aren't
+// rotated periodically
+func adminCredentialsRotationDueIn(cr *v1alpha1.Keycloak, secret *corev1.Secret) time.Duration {
+	period := cr.Spec.AdminCredentialsRotationPeriod
+	if secret == nil || cr.Spec.AdminCredentialsRef != nil || period == nil || period.Duration <= 0 {
+		return 0
+	}
+	due := period.Duration - time.Since(model.KeycloakAdminSecretLastRotation(secret))
+	if due <= 0 {
+		return time.Second
+	}
+	return due
+}
+
+// The new password is stored in the Secret first and only becomes the current one
+// once keycloak accepted it. Until then the admin clients fall back to it when the
+// current password is refused, so reconciles running meanwhile keep working
+func (r *ReconcileKeycloak) rotateAdminCredentials(instance *v1alpha1.Keycloak, secret *corev1.Secret) error {
+	if !instance.Status.Ready {
+		return nil
+	}
+
+	if len(secret.Data[model.AdminPasswordPendingProperty]) == 0 {
+		secret = model.KeycloakAdminSecretWithPendingPassword(secret)
+		if err := r.client.Update(r.context, secret); err != nil {
+			return errors.Wrap(err, "error storing the new admin password")
+		}
+	}
+
+	keycloakFactory := common.LocalConfigKeycloakFactory{}
+	authenticated, err := keycloakFactory.AuthenticatedClient(*instance)
+	if err != nil {
+		return errors.Wrap(err, "error logging in to rotate the admin password")
+	}
+
+	username := string(secret.Data[model.AdminUsernameProperty])
+	user, err := authenticated.FindUserByUsername(username, adminRealm)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.Errorf("admin user %v not found", username)
+	}
+
+	err = authenticated.ResetUserPassword(user.ID, &v1alpha1.KeycloakCredential{
+		Type:  "password",
+		Value: string(secret.Data[model.AdminPasswordPendingProperty]),
+	}, adminRealm)
+	if err != nil {
+		return errors.Wrap(err, "error setting the new admin password")
+	}
+
+	err = r.client.Update(r.context, model.KeycloakAdminSecretRotated(secret, time.Now()))
+	if err != nil {
+		return errors.Wrap(err, "error storing the rotated admin password")
+	}
+
+	if _, ok := instance.Annotations[model.RotateAdminCredentialsAnnotation]; ok {
+		status := instance.Status.DeepCopy()
+		delete(instance.Annotations, model.RotateAdminCredentialsAnnotation)
+		if err := r.client.Update(r.context, instance); err != nil {
+			return err
+		}
+		instance.Status = *status
+	}
+
+	log.Info("rotated the admin password", "Keycloak", instance.Name)
+	r.recorder.Event(instance, "Normal", "AdminCredentialsRotated", "The admin password was rotated")
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/keycloak/keycloak-operator/version"
 
+	"k8s.io/api/autoscaling/v2beta2"
 	v1beta12 "k8s.io/api/policy/v1beta1"
 
 	"github.com/keycloak/keycloak-operator/pkg/model"
@@ -41,7 +42,6 @@ import (
 var log = logf.Log.WithName("controller_keycloak")
 
 const (
-	RequeueDelay      = 30 * time.Second
 	RequeueDelayError = 5 * time.Second
 	ControllerName    = "keycloak-controller"
 )
@@ -109,6 +109,10 @@ func add(mgr manager.Manager, r reconcile.Reconciler, autodetectChannel chan sch
 		return err
 	}
 
+	if err := common.WatchSecondaryResource(c, ControllerName, common.HorizontalPodAutoscalerKind, &v2beta2.HorizontalPodAutoscaler{}, &kc.Keycloak{}); err != nil {
+		return err
+	}
+
 	// Setting up a listener for events on the channel from autodetect
 	go func() {
 		for gvk := range autodetectChannel {
@@ -154,6 +158,7 @@ type ReconcileKeycloak struct {
 func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling Keycloak")
+	defer common.ObserveReconcileDuration("Keycloak", time.Now())
 
 	// Fetch the Keycloak instance
 	instance := &keycloakv1alpha1.Keycloak{}
@@ -164,6 +169,7 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
+			common.ForgetPendingActions(instance, request.Namespace, request.Name)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -171,6 +177,10 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 	}
 	currentState := common.NewClusterState()
 
+	if model.IsExternalKeycloak(instance) {
+		return r.manageExternal(instance, currentState)
+	}
+
 	if instance.Spec.Unmanaged {
 		return r.ManageSuccess(instance, currentState)
 	}
@@ -185,6 +195,49 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 		return r.ManageError(instance, err)
 	}
 
+	err = model.ValidateAdminCredentials(instance, currentState.KeycloakAdminSecret)
+	if err != nil {
+		return r.ManageError(instance, err)
+	}
+
+	err = model.ValidateExternalDatabase(instance, currentState.ExternalDatabaseCredentials)
+	if err != nil {
+		return r.ManageError(instance, err)
+	}
+
+	err = model.ValidateRoute(instance, currentState.RouteDestinationCACertificate)
+	if err != nil {
+		return r.ManageError(instance, err)
+	}
+
+	err = model.ValidateAutoscaling(instance)
+	if err != nil {
+		return r.ManageError(instance, err)
+	}
+
+	err = model.ValidatePodDisruptionBudget(instance)
+	if err != nil {
+		return r.ManageError(instance, err)
+	}
+
+	// The hostname settings are WildFly ones, which the Quarkus distribution ignores
+	if instance.Spec.Hostname != nil && common.IsQuarkusOnlyVersion(instance.Status.KeycloakVersion) {
+		return r.ManageError(instance, errors.Errorf("spec.hostname is only supported by the WildFly distribution of keycloak, keycloak %v only comes as the Quarkus one", instance.Status.KeycloakVersion))
+	}
+
+	if model.IsPodDisruptionBudgetBlockingDrains(instance) {
+		message := "the PodDisruptionBudget keeps the only Keycloak instance from being evicted, node drains will be blocked"
+		log.Info(message)
+		r.recorder.Event(instance, "Warning", "PodDisruptionBudgetBlocksDrains", message)
+	}
+
+	// Older images are still deployed, they may just not work with everything the Operator does
+	if model.IsImageOlderThanSupported(instance) {
+		message := fmt.Sprintf("image %v is older than the supported version %v", instance.Spec.KeycloakDeploymentSpec.Image, model.Profiles.GetSupportedVersion(instance))
+		log.Info(message)
+		r.recorder.Event(instance, "Warning", "UnsupportedImage", message)
+	}
+
 	// Get Action to reconcile current state into desired state
 	reconciler := NewKeycloakReconciler()
 	desiredState := reconciler.Reconcile(currentState, instance)
@@ -206,6 +259,41 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 		return r.ManageError(instance, err)
 	}
 
+	// Keycloak has been scaled down by the actions, it isn't started on a database
+	// that may only be restored in part
+	if currentState.KeycloakRestoreFailed != "" {
+		return r.ManageError(instance, errors.Errorf("restore %v failed, keycloak stays scaled down until it succeeds or the KeycloakBackup is removed", currentState.KeycloakRestoreFailed))
+	}
+
+	if isAdminCredentialsRotationDue(instance, currentState.KeycloakAdminSecret) {
+		err = r.rotateAdminCredentials(instance, currentState.KeycloakAdminSecret)
+		if err != nil {
+			return r.ManageError(instance, err)
+		}
+	}
+
+	return r.ManageSuccess(instance, currentState)
+}
+
+// Nothing is deployed for an external instance. It is only made known to the other
+// controllers by its endpoint and admin credentials
+func (r *ReconcileKeycloak) manageExternal(instance *v1alpha1.Keycloak, currentState *common.ClusterState) (reconcile.Result, error) {
+	secret := &corev1.Secret{}
+	err := r.client.Get(r.context, model.KeycloakAdminSecretSelector(instance), secret)
+	if err != nil {
+		if !kubeerrors.IsNotFound(err) {
+			return r.ManageError(instance, err)
+		}
+		secret = nil
+	}
+
+	err = model.ValidateExternalKeycloak(instance, secret)
+	if err != nil {
+		return r.ManageError(instance, err)
+	}
+
+	currentState.KeycloakAdminSecret = secret
+	instance.Status.InternalURL = instance.Spec.External.URL
 	return r.ManageSuccess(instance, currentState)
 }
 
@@ -264,6 +352,9 @@ func (r *ReconcileKeycloak) ManageSuccess(instance *v1alpha1.Keycloak, currentSt
 	}
 
 	r.setVersion(instance)
+	if resourcesReady {
+		r.setKeycloakVersion(instance, currentState.KeycloakDeployment)
+	}
 
 	err = r.client.Status().Update(r.context, instance)
 	if err != nil {
@@ -275,9 +366,55 @@ func (r *ReconcileKeycloak) ManageSuccess(instance *v1alpha1.Keycloak, currentSt
 	}
 
 	log.Info("desired cluster state met")
-	return reconcile.Result{RequeueAfter: RequeueDelay}, nil
+	// changes to the owned resources trigger a reconcile, otherwise the --resync-period
+	// applies unless the admin credentials are rotated before
+	result := common.ResyncResult(nil)
+	if due := adminCredentialsRotationDueIn(instance, currentState.KeycloakAdminSecret); due > 0 {
+		result.RequeueAfter = due
+	}
+	return result, nil
 }
 
 func (r *ReconcileKeycloak) setVersion(instance *v1alpha1.Keycloak) {
 	instance.Status.Version = version.Version
 }
+
+// The version is read once per image of the deployment, so that reconciles don't
+// authenticate against keycloak each time. The last one read is kept when keycloak
+// can't tell it, and asked again on the next reconcile
+func (r *ReconcileKeycloak) setKeycloakVersion(instance *v1alpha1.Keycloak, deployment *appsv1.StatefulSet) {
+	image := keycloakImage(deployment)
+	if image != "" && image == instance.Status.KeycloakVersionImage {
+		return
+	}
+
+	keycloakFactory := common.LocalConfigKeycloakFactory{}
+	authenticated, err := keycloakFactory.AuthenticatedClient(*instance)
+	if err != nil {
+		log.Info(fmt.Sprintf("unable to read the keycloak version: %v", err))
+		return
+	}
+
+	info, err := authenticated.GetServerInfo()
+	if err != nil {
+		log.Info(fmt.Sprintf("unable to read the keycloak version: %v", err))
+		return
+	}
+	if info != nil {
+		instance.Status.KeycloakVersion = info.Version
+		instance.Status.KeycloakVersionImage = image
+	}
+}
+
+// The image of the keycloak container, empty when it isn't deployed yet
+func keycloakImage(deployment *appsv1.StatefulSet) string {
+	if deployment == nil {
+		return ""
+	}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == model.KeycloakDeploymentName {
+			return container.Image
+		}
+	}
+	return ""
+}
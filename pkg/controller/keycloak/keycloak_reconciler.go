@@ -1,6 +1,8 @@
 package keycloak
 
 import (
+	"reflect"
+
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	grafanav1alpha1 "github.com/integr8ly/grafana-operator/v3/pkg/apis/integreatly/v1alpha1"
 	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
@@ -41,17 +43,37 @@ func (i *KeycloakReconciler) Reconcile(clusterState *common.ClusterState, cr *kc
 	desired = desired.AddAction(i.getKeycloakServiceDesiredState(clusterState, cr))
 	desired = desired.AddAction(i.getKeycloakDiscoveryServiceDesiredState(clusterState, cr))
 	desired = desired.AddAction(i.GetKeycloakProbesDesiredState(clusterState, cr))
+	desired = desired.AddAction(i.getKeycloakStartupScriptsDesiredState(clusterState, cr))
+	desired = desired.AddAction(i.getLegacyRemoteCacheDesiredState(clusterState))
 	desired = desired.AddAction(i.getKeycloakDeploymentOrRHSSODesiredState(clusterState, cr))
 	i.reconcileExternalAccess(&desired, clusterState, cr)
 	desired = desired.AddAction(i.getPodDisruptionBudgetDesiredState(clusterState, cr))
+	desired = desired.AddAction(i.getHorizontalPodAutoscalerDesiredState(clusterState, cr))
 
 	if cr.Spec.Migration.Backups.Enabled {
 		desired = desired.AddAction(i.getKeycloakBackupDesiredState(clusterState, cr))
 	}
+
+	i.applyCustomMetadata(desired, cr)
 	return desired
 }
 
+func (i *KeycloakReconciler) applyCustomMetadata(desired common.DesiredClusterState, cr *kc.Keycloak) {
+	for _, action := range desired {
+		switch v := action.(type) {
+		case common.GenericCreateAction:
+			model.ApplyCustomMetadata(cr, v.Ref)
+		case common.GenericUpdateAction:
+			model.ApplyCustomMetadata(cr, v.Ref)
+		}
+	}
+}
+
 func (i *KeycloakReconciler) reconcileExternalDatabase(desired *common.DesiredClusterState, clusterState *common.ClusterState, cr *kc.Keycloak) {
+	if model.IsExternalDatabaseConfigured(cr) {
+		desired.AddAction(i.getExternalDatabaseSecretDesiredState(clusterState, cr))
+	}
+
 	// If the database secret does not exist we can't continue
 	if clusterState.DatabaseSecret == nil {
 		return
@@ -87,6 +109,10 @@ func (i *KeycloakReconciler) reconcileExternalAccess(desired *common.DesiredClus
 }
 
 func (i *KeycloakReconciler) GetKeycloakAdminSecretDesiredState(clusterState *common.ClusterState, cr *kc.Keycloak) common.ClusterAction {
+	// Referenced credentials are managed elsewhere
+	if cr.Spec.AdminCredentialsRef != nil {
+		return nil
+	}
 	keycloakAdminSecret := model.KeycloakAdminSecret(cr)
 
 	if clusterState.KeycloakAdminSecret == nil {
@@ -113,6 +139,41 @@ func (i *KeycloakReconciler) GetKeycloakProbesDesiredState(clusterState *common.
 	return nil
 }
 
+// Older versions of the operator kept the remote cache script in a ConfigMap of
+// its own, which the StatefulSet no longer mounts
+func (i *KeycloakReconciler) getLegacyRemoteCacheDesiredState(clusterState *common.ClusterState) common.ClusterAction {
+	if clusterState.LegacyRemoteCacheConfigMap == nil {
+		return nil
+	}
+	return common.GenericDeleteAction{
+		Ref: clusterState.LegacyRemoteCacheConfigMap,
+		Msg: "Delete legacy Keycloak remote cache configmap",
+	}
+}
+
+// The ConfigMap is removed once no script is left, e.g. when remoteCache is unset
+func (i *KeycloakReconciler) getKeycloakStartupScriptsDesiredState(clusterState *common.ClusterState, cr *kc.Keycloak) common.ClusterAction {
+	if !model.HasStartupScripts(cr) || model.Profiles.IsRHSSO(cr) {
+		if clusterState.KeycloakStartupScripts == nil {
+			return nil
+		}
+		return common.GenericDeleteAction{
+			Ref: clusterState.KeycloakStartupScripts,
+			Msg: "Delete Keycloak startup scripts configmap",
+		}
+	}
+	if clusterState.KeycloakStartupScripts == nil {
+		return common.GenericCreateAction{
+			Ref: model.KeycloakStartupScripts(cr),
+			Msg: "Create Keycloak startup scripts configmap",
+		}
+	}
+	return common.GenericUpdateAction{
+		Ref: model.KeycloakStartupScriptsReconciled(cr, clusterState.KeycloakStartupScripts),
+		Msg: "Update Keycloak startup scripts configmap",
+	}
+}
+
 func (i *KeycloakReconciler) getPostgresqlPersistentVolumeClaimDesiredState(clusterState *common.ClusterState, cr *kc.Keycloak) common.ClusterAction {
 	postgresqlPersistentVolume := model.PostgresqlPersistentVolumeClaim(cr)
 	if clusterState.PostgresqlPersistentVolumeClaim == nil {
@@ -224,6 +285,16 @@ func (i *KeycloakReconciler) GetKeycloakServiceMonitorDesiredState(clusterState
 
 	servicemonitor := model.ServiceMonitor(cr)
 
+	if !model.IsServiceMonitorEnabled(cr) {
+		if clusterState.KeycloakServiceMonitor == nil {
+			return nil
+		}
+		return common.GenericDeleteAction{
+			Ref: clusterState.KeycloakServiceMonitor,
+			Msg: "delete keycloak service monitor",
+		}
+	}
+
 	if clusterState.KeycloakServiceMonitor == nil {
 		return common.GenericCreateAction{
 			Ref: servicemonitor,
@@ -275,6 +346,28 @@ func (i *KeycloakReconciler) getDatabaseSecretDesiredState(clusterState *common.
 	}
 }
 
+func (i *KeycloakReconciler) getExternalDatabaseSecretDesiredState(clusterState *common.ClusterState, cr *kc.Keycloak) common.ClusterAction {
+	if clusterState.DatabaseSecret == nil {
+		return common.GenericCreateAction{
+			Ref: model.ExternalDatabaseSecret(cr, clusterState.ExternalDatabaseCredentials),
+			Msg: "Create External Database Secret",
+		}
+	}
+
+	// The secret is only written once it changed, every write of it triggers a reconcile
+	reconciled := model.ExternalDatabaseSecretReconciled(cr, clusterState.DatabaseSecret, clusterState.ExternalDatabaseCredentials)
+	model.ApplyCustomMetadata(cr, reconciled)
+	if reflect.DeepEqual(reconciled.Data, clusterState.DatabaseSecret.Data) &&
+		reflect.DeepEqual(reconciled.Labels, clusterState.DatabaseSecret.Labels) &&
+		reflect.DeepEqual(reconciled.Annotations, clusterState.DatabaseSecret.Annotations) {
+		return nil
+	}
+	return common.GenericUpdateAction{
+		Ref: reconciled,
+		Msg: "Update External Database Secret",
+	}
+}
+
 func (i *KeycloakReconciler) getKeycloakDeploymentOrRHSSODesiredState(clusterState *common.ClusterState, cr *kc.Keycloak) common.ClusterAction {
 	isRHSSO := model.Profiles.IsRHSSO(cr)
 
@@ -285,6 +378,17 @@ func (i *KeycloakReconciler) getKeycloakDeploymentOrRHSSODesiredState(clusterSta
 		deployment = model.RHSSODeployment(cr, clusterState.DatabaseSecret)
 		deploymentName = model.RHSSOProfile
 	}
+	extraVolumesHash := model.ExtraVolumesHash(clusterState.ExtraVolumeConfigMaps, clusterState.ExtraVolumeSecrets)
+	model.SetExtraVolumesHash(deployment, extraVolumesHash)
+	if !isRHSSO {
+		model.SetStartupScriptsHash(deployment, cr)
+	}
+
+	// Keycloak has to be down while a backup is loaded into its database
+	restoreReplicas := int32(0)
+	if clusterState.KeycloakRestoreInProgress {
+		deployment.Spec.Replicas = &restoreReplicas
+	}
 
 	if clusterState.KeycloakDeployment == nil {
 		return common.GenericCreateAction{
@@ -297,6 +401,13 @@ func (i *KeycloakReconciler) getKeycloakDeploymentOrRHSSODesiredState(clusterSta
 	if isRHSSO {
 		deploymentReconciled = model.RHSSODeploymentReconciled(cr, clusterState.KeycloakDeployment, clusterState.DatabaseSecret)
 	}
+	model.SetExtraVolumesHash(deploymentReconciled, extraVolumesHash)
+	if !isRHSSO {
+		model.SetStartupScriptsHash(deploymentReconciled, cr)
+	}
+	if clusterState.KeycloakRestoreInProgress {
+		deploymentReconciled.Spec.Replicas = &restoreReplicas
+	}
 
 	return common.GenericUpdateAction{
 		Ref: deploymentReconciled,
@@ -307,13 +418,13 @@ func (i *KeycloakReconciler) getKeycloakDeploymentOrRHSSODesiredState(clusterSta
 func (i *KeycloakReconciler) getKeycloakRouteDesiredState(clusterState *common.ClusterState, cr *kc.Keycloak) common.ClusterAction {
 	if clusterState.KeycloakRoute == nil {
 		return common.GenericCreateAction{
-			Ref: model.KeycloakRoute(cr),
+			Ref: model.KeycloakRoute(cr, clusterState.RouteDestinationCACertificate),
 			Msg: "Create Keycloak Route",
 		}
 	}
 
 	return common.GenericUpdateAction{
-		Ref: model.KeycloakRouteReconciled(cr, clusterState.KeycloakRoute),
+		Ref: model.KeycloakRouteReconciled(cr, clusterState.KeycloakRoute, clusterState.RouteDestinationCACertificate),
 		Msg: "Update Keycloak Route",
 	}
 }
@@ -359,6 +470,30 @@ func (i *KeycloakReconciler) getPodDisruptionBudgetDesiredState(clusterState *co
 	return nil
 }
 
+// Unlike the PodDisruptionBudget, the autoscaler has to go away when disabled, as it
+// would keep scaling the StatefulSet otherwise
+func (i *KeycloakReconciler) getHorizontalPodAutoscalerDesiredState(clusterState *common.ClusterState, cr *kc.Keycloak) common.ClusterAction {
+	if cr.Spec.Autoscaling == nil {
+		if clusterState.HorizontalPodAutoscaler == nil {
+			return nil
+		}
+		return common.GenericDeleteAction{
+			Ref: clusterState.HorizontalPodAutoscaler,
+			Msg: "Delete HorizontalPodAutoscaler",
+		}
+	}
+	if clusterState.HorizontalPodAutoscaler == nil {
+		return common.GenericCreateAction{
+			Ref: model.HorizontalPodAutoscaler(cr),
+			Msg: "Create HorizontalPodAutoscaler",
+		}
+	}
+	return common.GenericUpdateAction{
+		Ref: model.HorizontalPodAutoscalerReconciled(cr, clusterState.HorizontalPodAutoscaler),
+		Msg: "Update HorizontalPodAutoscaler",
+	}
+}
+
 func (i *KeycloakReconciler) getKeycloakBackupDesiredState(clusterState *common.ClusterState, cr *kc.Keycloak) common.ClusterAction {
 	backupCr := &v1alpha1.KeycloakBackup{}
 	backupCr.Namespace = cr.Namespace
@@ -4,9 +4,11 @@ import (
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 
+	"k8s.io/api/autoscaling/v2beta2"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -83,7 +85,7 @@ func TestKeycloakReconciler_Test_Creating_All(t *testing.T) {
 	assert.IsType(t, model.KeycloakDiscoveryService(cr), desiredState[9].(common.GenericCreateAction).Ref)
 	assert.IsType(t, model.KeycloakProbes(cr), desiredState[10].(common.GenericCreateAction).Ref)
 	assert.IsType(t, model.KeycloakDeployment(cr, model.DatabaseSecret(cr)), desiredState[11].(common.GenericCreateAction).Ref)
-	assert.IsType(t, model.KeycloakRoute(cr), desiredState[12].(common.GenericCreateAction).Ref)
+	assert.IsType(t, model.KeycloakRoute(cr, nil), desiredState[12].(common.GenericCreateAction).Ref)
 }
 
 func TestKeycloakReconciler_Test_Creating_RHSSO(t *testing.T) {
@@ -189,7 +191,7 @@ func TestKeycloakReconciler_Test_Updating_All(t *testing.T) {
 		KeycloakDiscoveryService:        model.KeycloakDiscoveryService(cr),
 		KeycloakDeployment:              model.KeycloakDeployment(cr, model.DatabaseSecret(cr)),
 		KeycloakAdminSecret:             model.KeycloakAdminSecret(cr),
-		KeycloakRoute:                   model.KeycloakRoute(cr),
+		KeycloakRoute:                   model.KeycloakRoute(cr, nil),
 		KeycloakProbes:                  model.KeycloakProbes(cr),
 	}
 
@@ -244,7 +246,7 @@ func TestKeycloakReconciler_Test_Updating_All(t *testing.T) {
 	assert.IsType(t, model.KeycloakService(cr), desiredState[8].(common.GenericUpdateAction).Ref)
 	assert.IsType(t, model.KeycloakDiscoveryService(cr), desiredState[9].(common.GenericUpdateAction).Ref)
 	assert.IsType(t, model.KeycloakDeployment(cr, model.DatabaseSecret(cr)), desiredState[10].(common.GenericUpdateAction).Ref)
-	assert.IsType(t, model.KeycloakRoute(cr), desiredState[11].(common.GenericUpdateAction).Ref)
+	assert.IsType(t, model.KeycloakRoute(cr, nil), desiredState[11].(common.GenericUpdateAction).Ref)
 }
 
 func TestKeycloakReconciler_Test_No_Action_When_Monitoring_Resources_Dont_Exist(t *testing.T) {
@@ -501,6 +503,94 @@ func TestKeycloakReconciler_Test_Recreate_Does_Not_Update_Existing_Credentials(t
 	assert.Equal(t, password, secret.Data[model.AdminPasswordProperty])
 }
 
+func TestKeycloakReconciler_Test_Admin_Credentials_Rotation(t *testing.T) {
+	// given
+	cr := &v1alpha1.Keycloak{}
+	secret := model.KeycloakAdminSecret(cr)
+	secret.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	password := secret.Data[model.AdminPasswordProperty]
+
+	// then
+	assert.False(t, isAdminCredentialsRotationDue(cr, secret))
+	assert.False(t, isAdminCredentialsRotationDue(cr, nil))
+	assert.Zero(t, adminCredentialsRotationDueIn(cr, secret))
+
+	// when
+	cr.Spec.AdminCredentialsRotationPeriod = &metav1.Duration{Duration: time.Hour}
+
+	// then
+	assert.True(t, isAdminCredentialsRotationDue(cr, secret))
+
+	// when
+	pending := model.KeycloakAdminSecretWithPendingPassword(secret)
+
+	// then
+	// an interrupted rotation keeps its new password
+	assert.NotEmpty(t, pending.Data[model.AdminPasswordPendingProperty])
+	assert.Equal(t, pending.Data[model.AdminPasswordPendingProperty], model.KeycloakAdminSecretWithPendingPassword(pending).Data[model.AdminPasswordPendingProperty])
+	assert.Equal(t, password, model.KeycloakAdminSecretReconciled(cr, pending).Data[model.AdminPasswordProperty])
+
+	// when
+	rotated := model.KeycloakAdminSecretRotated(pending, time.Now())
+
+	// then
+	assert.Equal(t, pending.Data[model.AdminPasswordPendingProperty], rotated.Data[model.AdminPasswordProperty])
+	assert.NotContains(t, rotated.Data, model.AdminPasswordPendingProperty)
+	assert.False(t, isAdminCredentialsRotationDue(cr, rotated))
+	assert.True(t, isAdminCredentialsRotationDue(cr, pending))
+	assert.InDelta(t, time.Hour, adminCredentialsRotationDueIn(cr, rotated), float64(time.Minute))
+
+	// when
+	cr.Annotations = map[string]string{model.RotateAdminCredentialsAnnotation: ""}
+
+	// then
+	assert.True(t, isAdminCredentialsRotationDue(cr, rotated))
+}
+
+func TestKeycloakReconciler_Test_Admin_Credentials_Ref(t *testing.T) {
+	// given
+	cr := &v1alpha1.Keycloak{}
+	cr.Name = "keycloak"
+	cr.Spec.AdminCredentialsRef = &v1alpha1.KeycloakAdminCredentialsRef{
+		Name:        "provisioned-admin",
+		PasswordKey: "password",
+	}
+	secret := &v1.Secret{
+		Data: map[string][]byte{
+			model.AdminUsernameProperty: []byte("admin"),
+		},
+	}
+	currentState := common.NewClusterState()
+
+	// when
+	reconciler := NewKeycloakReconciler()
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	for _, action := range desiredState {
+		if create, ok := action.(common.GenericCreateAction); ok {
+			assert.NotEqual(t, model.KeycloakAdminSecret(cr).Name, create.Ref.(metav1.Object).GetName())
+		}
+	}
+	assert.Equal(t, "provisioned-admin", model.KeycloakAdminSecretSelector(cr).Name)
+	assert.EqualError(t, model.ValidateAdminCredentials(cr, nil), "admin credentials secret provisioned-admin not found")
+	assert.EqualError(t, model.ValidateAdminCredentials(cr, secret), "admin credentials secret provisioned-admin has no password")
+	assert.False(t, isAdminCredentialsRotationDue(cr, secret))
+
+	// when
+	secret.Data["password"] = []byte("secret")
+	env := model.KeycloakDeployment(cr, nil).Spec.Template.Spec.Containers[0].Env
+
+	// then
+	assert.NoError(t, model.ValidateAdminCredentials(cr, secret))
+	for _, variable := range env {
+		if variable.Name == "KEYCLOAK_PASSWORD" {
+			assert.Equal(t, "provisioned-admin", variable.ValueFrom.SecretKeyRef.Name)
+			assert.Equal(t, "password", variable.ValueFrom.SecretKeyRef.Key)
+		}
+	}
+}
+
 func TestKeycloakReconciler_Test_Should_Create_PDB(t *testing.T) {
 	// given
 	cr := &v1alpha1.Keycloak{}
@@ -634,11 +724,31 @@ func TestKeycloakReconciler_Test_No_Resources_Specified(t *testing.T) {
 	assert.IsType(t, model.PostgresqlDeployment(cr, true), desiredState[6].(common.GenericCreateAction).Ref)
 	assert.IsType(t, model.KeycloakDeployment(cr, model.DatabaseSecret(cr)), desiredState[11].(common.GenericCreateAction).Ref)
 	keycloakContainer := desiredState[11].(common.GenericCreateAction).Ref.(*v13.StatefulSet).Spec.Template.Spec.Containers[0]
-	assert.Equal(t, 0, len(keycloakContainer.Resources.Requests), "Requests-List should be empty")
-	assert.Equal(t, 0, len(keycloakContainer.Resources.Limits), "Limits-List should be empty")
+	assert.Equal(t, model.KeycloakDefaultResources, keycloakContainer.Resources, "Keycloak Deployment should use the default resources")
 	postgresContainer := desiredState[6].(common.GenericCreateAction).Ref.(*v13.Deployment).Spec.Template.Spec.Containers[0]
-	assert.Equal(t, len(postgresContainer.Resources.Requests), 0, "Request-List should be empty")
-	assert.Equal(t, len(postgresContainer.Resources.Limits), 0, "Limits-List should be empty")
+	assert.Equal(t, model.PostgresqlDefaultResources, postgresContainer.Resources, "Postgres Deployment should use the default resources")
+}
+
+func TestKeycloakReconciler_Test_Partial_Resources_Specified(t *testing.T) {
+	// given
+	cr := &v1alpha1.Keycloak{}
+	resource4Gi := resource.MustParse("4Gi")
+	resource100m := resource.MustParse("100m")
+	cr.Spec.KeycloakDeploymentSpec.Resources = v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceMemory: resource4Gi},
+		Limits:   v1.ResourceList{v1.ResourceCPU: resource100m},
+	}
+
+	// when
+	deployment := model.KeycloakDeployment(cr, nil)
+
+	// then
+	// defaults fill the gaps without contradicting the values of the CR
+	resources := deployment.Spec.Template.Spec.Containers[0].Resources
+	assert.Equal(t, &resource4Gi, resources.Requests.Memory())
+	assert.Equal(t, &resource4Gi, resources.Limits.Memory())
+	assert.Equal(t, &resource100m, resources.Requests.Cpu())
+	assert.Equal(t, &resource100m, resources.Limits.Cpu())
 }
 
 func TestKeycloakReconciler_Test_Proxy_Settings(t *testing.T) {
@@ -694,3 +804,292 @@ func TestKeycloakReconciler_Test_Should_Create_Backup(t *testing.T) {
 	assert.IsType(t, common.GenericUpdateAction{}, desiredState[9])
 	assert.IsType(t, model.KeycloakMigrationOneTimeBackup(backupCr), desiredState[9].(common.GenericUpdateAction).Ref)
 }
+
+func TestKeycloakReconciler_Test_External_Database_From_CR(t *testing.T) {
+	// given
+	cr := &v1alpha1.Keycloak{}
+	cr.Spec.ExternalDatabase = v1alpha1.KeycloakExternalDatabase{
+		Enabled:           true,
+		Host:              "10.10.10.1",
+		Port:              6432,
+		CredentialsSecret: "db-credentials",
+	}
+
+	currentState := common.NewClusterState()
+	currentState.ExternalDatabaseCredentials = &v1.Secret{
+		Data: map[string][]byte{
+			model.ExternalDatabaseUsernameProperty: []byte("keycloak"),
+			model.ExternalDatabasePasswordProperty: []byte("secret"),
+		},
+	}
+
+	// when
+	reconciler := NewKeycloakReconciler()
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	// the database secret is created from the CR, no postgres is provisioned
+	var secret *v1.Secret
+	for _, v := range desiredState {
+		if action, ok := v.(common.GenericCreateAction); ok {
+			switch ref := action.Ref.(type) {
+			case *v1.Secret:
+				if ref.Name == model.DatabaseSecretName {
+					secret = ref
+				}
+			case *v1.PersistentVolumeClaim, *v13.Deployment:
+				assert.Fail(t, "postgres must not be provisioned for an external database")
+			}
+		}
+	}
+	assert.NotNil(t, secret)
+	assert.Equal(t, "10.10.10.1", model.GetExternalDatabaseHost(secret))
+	assert.Equal(t, int32(6432), model.GetExternalDatabasePort(secret))
+	assert.Equal(t, "keycloak", string(secret.Data[model.DatabaseSecretUsernameProperty]))
+
+	// when the secret is up to date
+	currentState.DatabaseSecret = secret
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	// it isn't written again
+	for _, v := range desiredState {
+		if action, ok := v.(common.GenericUpdateAction); ok {
+			assert.NotEqual(t, model.DatabaseSecretName, action.Ref.(metav1.Object).GetName())
+		}
+	}
+}
+
+func TestKeycloakReconciler_Test_Custom_Metadata(t *testing.T) {
+	// given
+	cr := &v1alpha1.Keycloak{}
+	cr.Spec.ResourceLabels = map[string]string{"cost-center": "sso", "app": "other"}
+	cr.Spec.ResourceAnnotations = map[string]string{"owner": "team-a"}
+	cr.Spec.PodLabels = map[string]string{"network-policy": "sso", "component": "other"}
+	cr.Spec.PodAnnotations = map[string]string{"sidecar.istio.io/inject": "false"}
+
+	currentState := common.NewClusterState()
+	currentState.DatabaseSecret = model.DatabaseSecret(cr)
+
+	// when
+	reconciler := NewKeycloakReconciler()
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	var statefulSet *v13.StatefulSet
+	var secret *v1.Secret
+	for _, v := range desiredState {
+		switch action := v.(type) {
+		case common.GenericCreateAction:
+			if s, ok := action.Ref.(*v13.StatefulSet); ok {
+				statefulSet = s
+			}
+		case common.GenericUpdateAction:
+			if s, ok := action.Ref.(*v1.Secret); ok && s.Name == model.DatabaseSecretName {
+				secret = s
+			}
+		}
+	}
+	assert.NotNil(t, statefulSet)
+	assert.NotNil(t, secret)
+
+	// labels of the operator win, they are used in selectors
+	assert.Equal(t, "sso", statefulSet.Labels["cost-center"])
+	assert.Equal(t, model.ApplicationName, statefulSet.Labels["app"])
+	assert.Equal(t, "team-a", statefulSet.Annotations["owner"])
+	assert.Equal(t, "sso", statefulSet.Spec.Template.Labels["network-policy"])
+	assert.Equal(t, model.KeycloakDeploymentComponent, statefulSet.Spec.Template.Labels["component"])
+	assert.Equal(t, "false", statefulSet.Spec.Template.Annotations["sidecar.istio.io/inject"])
+	assert.Equal(t, "sso", secret.Labels["cost-center"])
+	assert.Equal(t, "team-a", secret.Annotations["owner"])
+}
+
+func TestKeycloakReconciler_Test_Extra_Volumes_Roll_Pods(t *testing.T) {
+	// given
+	cr := &v1alpha1.Keycloak{}
+	cr.Spec.KeycloakDeploymentSpec.ExtraVolumes = []v1.Volume{
+		{
+			Name: "providers",
+			VolumeSource: v1.VolumeSource{
+				ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "providers"}},
+			},
+		},
+	}
+	cr.Spec.KeycloakDeploymentSpec.ExtraVolumeMounts = []v1.VolumeMount{{Name: "providers", MountPath: model.KeycloakExtensionPath + "/providers"}}
+
+	currentState := common.NewClusterState()
+	currentState.ExtraVolumeConfigMaps = []v1.ConfigMap{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "providers"},
+			BinaryData: map[string][]byte{"provider.jar": []byte("v1")},
+		},
+	}
+	reconciler := NewKeycloakReconciler()
+
+	// when
+	created := reconciler.getKeycloakDeploymentOrRHSSODesiredState(currentState, cr).(common.GenericCreateAction).Ref.(*v13.StatefulSet)
+	currentState.KeycloakDeployment = created
+	unchanged := reconciler.getKeycloakDeploymentOrRHSSODesiredState(currentState, cr).(common.GenericUpdateAction).Ref.(*v13.StatefulSet)
+	currentState.ExtraVolumeConfigMaps[0].BinaryData["provider.jar"] = []byte("v2")
+	changed := reconciler.getKeycloakDeploymentOrRHSSODesiredState(currentState, cr).(common.GenericUpdateAction).Ref.(*v13.StatefulSet)
+
+	// then
+	hash := created.Spec.Template.Annotations[model.ExtraVolumesHashAnnotation]
+	assert.NotEmpty(t, hash)
+	assert.Equal(t, hash, unchanged.Spec.Template.Annotations[model.ExtraVolumesHashAnnotation])
+	assert.NotEqual(t, hash, changed.Spec.Template.Annotations[model.ExtraVolumesHashAnnotation])
+}
+
+func TestKeycloakReconciler_Test_Startup_Scripts_Removed(t *testing.T) {
+	// given
+	cr := &v1alpha1.Keycloak{}
+	cr.Spec.RemoteCache = &v1alpha1.KeycloakRemoteCache{Hosts: []string{"infinispan:11222"}}
+	currentState := common.NewClusterState()
+	reconciler := NewKeycloakReconciler()
+
+	// when
+	configMap := reconciler.getKeycloakStartupScriptsDesiredState(currentState, cr).(common.GenericCreateAction).Ref.(*v1.ConfigMap)
+	currentState.KeycloakStartupScripts = configMap
+	cr.Spec.RemoteCache = nil
+
+	// then
+	deleted := reconciler.getKeycloakStartupScriptsDesiredState(currentState, cr).(common.GenericDeleteAction)
+	assert.Equal(t, configMap, deleted.Ref)
+
+	// when
+	currentState.KeycloakStartupScripts = nil
+
+	// then
+	assert.Nil(t, reconciler.getKeycloakStartupScriptsDesiredState(currentState, cr))
+}
+
+func TestKeycloakReconciler_Test_Legacy_Remote_Cache_Removed(t *testing.T) {
+	// given
+	cr := &v1alpha1.Keycloak{}
+	cr.Spec.RemoteCache = &v1alpha1.KeycloakRemoteCache{Hosts: []string{"infinispan:11222"}}
+	currentState := common.NewClusterState()
+	currentState.LegacyRemoteCacheConfigMap = &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: model.LegacyRemoteCacheConfigMapName},
+	}
+	reconciler := NewKeycloakReconciler()
+
+	// when
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	deleted := false
+	for _, action := range desiredState {
+		if v, ok := action.(common.GenericDeleteAction); ok && v.Ref == currentState.LegacyRemoteCacheConfigMap {
+			deleted = true
+		}
+	}
+	assert.True(t, deleted)
+}
+
+func TestKeycloakReconciler_Test_Autoscaling(t *testing.T) {
+	// given
+	cr := &v1alpha1.Keycloak{}
+	cr.Spec.Instances = 5
+	cr.Spec.Autoscaling = &v1alpha1.KeycloakAutoscaling{
+		MinReplicas: 2,
+		MaxReplicas: 6,
+	}
+	currentState := common.NewClusterState()
+	reconciler := NewKeycloakReconciler()
+
+	// when
+	hpaAction := reconciler.getHorizontalPodAutoscalerDesiredState(currentState, cr)
+	deployment := reconciler.getKeycloakDeploymentOrRHSSODesiredState(currentState, cr).(common.GenericCreateAction).Ref.(*v13.StatefulSet)
+
+	// then
+	// the statefulset starts at the lower limit, the autoscaler takes over from there
+	hpa := hpaAction.(common.GenericCreateAction).Ref.(*v2beta2.HorizontalPodAutoscaler)
+	assert.Equal(t, int32(2), *hpa.Spec.MinReplicas)
+	assert.Equal(t, int32(6), hpa.Spec.MaxReplicas)
+	assert.Equal(t, v1.ResourceCPU, hpa.Spec.Metrics[0].Resource.Name)
+	assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+
+	// when
+	scaled := int32(4)
+	deployment.Spec.Replicas = &scaled
+	currentState.KeycloakDeployment = deployment
+	currentState.HorizontalPodAutoscaler = hpa
+	reconciled := reconciler.getKeycloakDeploymentOrRHSSODesiredState(currentState, cr).(common.GenericUpdateAction).Ref.(*v13.StatefulSet)
+
+	// then
+	assert.Equal(t, int32(4), *reconciled.Spec.Replicas)
+	assert.IsType(t, common.GenericUpdateAction{}, reconciler.getHorizontalPodAutoscalerDesiredState(currentState, cr))
+
+	// when
+	cr.Spec.Autoscaling = nil
+
+	// then
+	assert.IsType(t, common.GenericDeleteAction{}, reconciler.getHorizontalPodAutoscalerDesiredState(currentState, cr))
+	assert.Equal(t, int32(5), *reconciler.getKeycloakDeploymentOrRHSSODesiredState(currentState, cr).(common.GenericUpdateAction).Ref.(*v13.StatefulSet).Spec.Replicas)
+}
+
+func TestKeycloakReconciler_Test_ServiceMonitor_Config(t *testing.T) {
+	// given
+	cr := &v1alpha1.Keycloak{}
+	cr.Spec.ServiceMonitor = &v1alpha1.ServiceMonitorConfig{
+		Interval: "30s",
+		Labels:   map[string]string{"release": "prometheus", "monitoring-key": "other"},
+	}
+	currentState := common.NewClusterState()
+	reconciler := NewKeycloakReconciler()
+
+	stateManager := common.GetStateManager()
+	stateManager.SetState(common.GetStateFieldName(ControllerName, monitoringv1.ServiceMonitorsKind), true)
+	defer stateManager.Clear()
+
+	// when
+	action := reconciler.GetKeycloakServiceMonitorDesiredState(currentState, cr)
+
+	// then
+	serviceMonitor := action.(common.GenericCreateAction).Ref.(*monitoringv1.ServiceMonitor)
+	assert.Equal(t, "30s", serviceMonitor.Spec.Endpoints[0].Interval)
+	assert.Equal(t, "prometheus", serviceMonitor.Labels["release"])
+	assert.Equal(t, model.MonitoringKey, serviceMonitor.Labels["monitoring-key"])
+
+	// when
+	disabled := false
+	cr.Spec.ServiceMonitor.Enabled = &disabled
+
+	// then
+	assert.Nil(t, reconciler.GetKeycloakServiceMonitorDesiredState(currentState, cr))
+
+	// when
+	currentState.KeycloakServiceMonitor = serviceMonitor
+
+	// then
+	assert.IsType(t, common.GenericDeleteAction{}, reconciler.GetKeycloakServiceMonitorDesiredState(currentState, cr))
+}
+
+func TestKeycloakReconciler_Test_Scaled_Down_During_Restore(t *testing.T) {
+	// given
+	cr := &v1alpha1.Keycloak{}
+	cr.Spec.Instances = 3
+	cr.Spec.Autoscaling = &v1alpha1.KeycloakAutoscaling{
+		MinReplicas: 2,
+		MaxReplicas: 6,
+	}
+	currentState := common.NewClusterState()
+	currentState.KeycloakDeployment = model.KeycloakDeployment(cr, nil)
+	currentState.KeycloakRestoreInProgress = true
+	reconciler := NewKeycloakReconciler()
+
+	// when
+	deployment := reconciler.getKeycloakDeploymentOrRHSSODesiredState(currentState, cr).(common.GenericUpdateAction).Ref.(*v13.StatefulSet)
+
+	// then
+	assert.Equal(t, int32(0), *deployment.Spec.Replicas)
+
+	// when
+	currentState.KeycloakDeployment = deployment
+	currentState.KeycloakRestoreInProgress = false
+	deployment = reconciler.getKeycloakDeploymentOrRHSSODesiredState(currentState, cr).(common.GenericUpdateAction).Ref.(*v13.StatefulSet)
+
+	// then
+	// the autoscaler doesn't scale up from 0, so the lower limit is restored
+	assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+}
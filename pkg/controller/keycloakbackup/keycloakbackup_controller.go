@@ -7,6 +7,7 @@ import (
 
 	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	"github.com/keycloak/keycloak-operator/pkg/common"
+	"github.com/keycloak/keycloak-operator/pkg/model"
 	errors "github.com/pkg/errors"
 	v1 "k8s.io/api/batch/v1"
 	"k8s.io/api/batch/v1beta1"
@@ -25,7 +26,6 @@ import (
 )
 
 const (
-	RequeueDelay      = 30 * time.Second
 	RequeueDelayError = 5 * time.Second
 	ControllerName    = "keycloakbackup-controller"
 )
@@ -95,6 +95,7 @@ type ReconcileKeycloakBackup struct {
 func (r *ReconcileKeycloakBackup) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling KeycloakBackup")
+	defer common.ObserveReconcileDuration("KeycloakBackup", time.Now())
 
 	// Fetch the KeycloakBackup instance
 	instance := &kc.KeycloakBackup{}
@@ -104,6 +105,7 @@ func (r *ReconcileKeycloakBackup) Reconcile(request reconcile.Request) (reconcil
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
+			common.ForgetPendingActions(instance, request.Namespace, request.Name)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -122,6 +124,10 @@ func (r *ReconcileKeycloakBackup) Reconcile(request reconcile.Request) (reconcil
 		return r.ManageError(instance, err)
 	}
 
+	if instance.Spec.Restore && instance.Spec.AWS != (kc.KeycloakAWSSpec{}) {
+		return r.ManageError(instance, errors.Errorf("only backups on a local persistent volume can be restored"))
+	}
+
 	// backups without instances to backup are treated as errors
 	if len(keycloaks.Items) == 0 {
 		return r.ManageError(instance, errors.Errorf("no instance to backup for %v/%v", instance.Namespace, instance.Name))
@@ -157,7 +163,11 @@ func (r *ReconcileKeycloakBackup) ManageError(instance *kc.KeycloakBackup, issue
 
 	instance.Status.Message = issue.Error()
 	instance.Status.Ready = false
-	instance.Status.Phase = kc.BackupPhaseFailing
+	// A failed restore stays failed, so that Keycloak is kept scaled down until it
+	// is restored or the backup is removed
+	if instance.Status.Phase != kc.BackupPhaseRestoreFailed {
+		instance.Status.Phase = kc.BackupPhaseFailing
+	}
 
 	err := r.client.Status().Update(r.context, instance)
 	if err != nil {
@@ -175,11 +185,19 @@ func (r *ReconcileKeycloakBackup) ManageSuccess(instance *kc.KeycloakBackup, cur
 	if err != nil {
 		return r.ManageError(instance, err)
 	}
+	if common.IsJobFailed(currentState.RestoreJob) {
+		instance.Status.Phase = kc.BackupPhaseRestoreFailed
+		return r.ManageError(instance, errors.Errorf("restore job %v failed", currentState.RestoreJob.Name))
+	}
 	instance.Status.Ready = resourcesReady
 	instance.Status.Message = ""
 
 	if resourcesReady {
 		instance.Status.Phase = kc.BackupPhaseCreated
+		instance.Status.Encrypted = model.IsBackupEncrypted(instance)
+		if instance.Spec.Restore {
+			instance.Status.Phase = getRestorePhase(currentState)
+		}
 	} else {
 		instance.Status.Phase = kc.BackupPhaseReconciling
 	}
@@ -194,5 +212,15 @@ func (r *ReconcileKeycloakBackup) ManageSuccess(instance *kc.KeycloakBackup, cur
 	}
 
 	log.Info("desired cluster state met")
-	return reconcile.Result{RequeueAfter: RequeueDelay}, nil
+	// changes to the owned resources trigger a reconcile, otherwise the --resync-period applies
+	return common.ResyncResult(nil), nil
+}
+
+// Keycloak stays scaled down for as long as the restore is in the restoring phase
+func getRestorePhase(currentState *common.BackupState) kc.BackupStatusPhase {
+	restored, err := common.IsJobReady(currentState.RestoreJob)
+	if err == nil && restored {
+		return kc.BackupPhaseRestored
+	}
+	return kc.BackupPhaseRestoring
 }
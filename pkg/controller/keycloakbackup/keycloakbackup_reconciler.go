@@ -34,9 +34,34 @@ func (i *KeycloakBackupReconciler) Reconcile(currentState *common.BackupState, c
 		desired = desired.AddAction(i.GetLocalBackupDesiredState(currentState, cr))
 	}
 
+	if cr.Spec.Restore {
+		desired = desired.AddAction(i.GetRestoreDesiredState(currentState, cr))
+	}
+
 	return desired
 }
 
+// The restore Job is left in place once it ran, so the backup is only restored once
+func (i *KeycloakBackupReconciler) GetRestoreDesiredState(currentState *common.BackupState, cr *kc.KeycloakBackup) common.ClusterAction {
+	if currentState.RestoreJob != nil {
+		return nil
+	}
+
+	backupReady, err := currentState.IsResourcesReady()
+	if err != nil || !backupReady {
+		return nil
+	}
+
+	if model.IsRestoreScalingDownKeycloak(cr) && !currentState.IsKeycloakScaledDown() {
+		return nil
+	}
+
+	return common.GenericCreateAction{
+		Ref: model.PostgresqlRestore(cr),
+		Msg: "Create Restore job",
+	}
+}
+
 func (i *KeycloakBackupReconciler) GetAwsPeriodicBackupDesiredState(currentState *common.BackupState, cr *kc.KeycloakBackup) common.ClusterAction {
 	if currentState.AwsPeriodicJob == nil {
 		return common.GenericCreateAction{
@@ -7,6 +7,7 @@ import (
 	"github.com/keycloak/keycloak-operator/pkg/common"
 	"github.com/keycloak/keycloak-operator/pkg/model"
 	"github.com/stretchr/testify/assert"
+	v13 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/batch/v1"
 	"k8s.io/api/batch/v1beta1"
 	v12 "k8s.io/api/core/v1"
@@ -144,3 +145,104 @@ func TestKeycloakBackupReconciler_Test_Updating_AWS_Periodic_Job(t *testing.T) {
 	assert.IsType(t, common.GenericUpdateAction{}, desiredState[0])
 	assert.IsType(t, model.PostgresqlAWSPeriodicBackup(cr), desiredState[0].(common.GenericUpdateAction).Ref)
 }
+
+func TestKeycloakBackupReconciler_Test_Encrypted_Local_Backup_Job(t *testing.T) {
+	// given
+	cr := &v1alpha1.KeycloakBackup{
+		Spec: v1alpha1.KeycloakBackupSpec{
+			EncryptionKeySecretName: "backup-key",
+		},
+	}
+	keycloak := v1alpha1.Keycloak{}
+
+	currentState := common.NewBackupState(keycloak)
+
+	// when
+	reconciler := NewKeycloakBackupReconciler(keycloak)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	job := desiredState[1].(common.GenericCreateAction).Ref.(*v1.Job)
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Contains(t, container.Args[0], "gpg --batch --yes")
+	assert.Contains(t, container.Args[0], "set -o pipefail")
+	assert.Contains(t, container.Env, v12.EnvVar{
+		Name: model.BackupEncryptionPublicKeyProperty,
+		ValueFrom: &v12.EnvVarSource{
+			SecretKeyRef: &v12.SecretKeySelector{
+				LocalObjectReference: v12.LocalObjectReference{Name: "backup-key"},
+				Key:                  model.BackupEncryptionPublicKeyProperty,
+			},
+		},
+	})
+	assert.True(t, model.IsBackupEncrypted(cr))
+
+	// when
+	cr.Spec.EncryptionKeySecretName = ""
+	reconciled := model.PostgresqlBackupReconciled(cr, job)
+
+	// then
+	assert.Equal(t, "pg_dump $POSTGRES_DB | tee /backup/backup.sql", reconciled.Spec.Template.Spec.Containers[0].Args[0])
+	assert.Len(t, reconciled.Spec.Template.Spec.Containers[0].Env, 5)
+	assert.False(t, model.IsBackupEncrypted(cr))
+}
+
+func TestKeycloakBackupReconciler_Test_Restore_Local_Backup(t *testing.T) {
+	// given
+	cr := &v1alpha1.KeycloakBackup{
+		Spec: v1alpha1.KeycloakBackupSpec{
+			Restore: true,
+			RestoreOptions: v1alpha1.KeycloakRestoreOptions{
+				ScaleDownKeycloak: true,
+			},
+		},
+	}
+	cr.Name = "backup"
+	keycloak := v1alpha1.Keycloak{}
+
+	currentState := common.NewBackupState(keycloak)
+	currentState.LocalPersistentVolumeJob = &v1.Job{}
+	currentState.LocalPersistentVolumeClaim = &v12.PersistentVolumeClaim{}
+	reconciler := NewKeycloakBackupReconciler(keycloak)
+
+	// then
+	// the backup has to be taken first
+	assert.Len(t, reconciler.Reconcile(currentState, cr), 2)
+
+	// when
+	currentState.LocalPersistentVolumeJob.Status.Succeeded = 1
+	currentState.KeycloakDeployment = &v13.StatefulSet{}
+	currentState.KeycloakDeployment.Status.Replicas = 1
+
+	// then
+	// keycloak is scaled down before the backup is loaded
+	assert.Len(t, reconciler.Reconcile(currentState, cr), 2)
+
+	// when
+	currentState.KeycloakDeployment.Status.Replicas = 0
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.Len(t, desiredState, 3)
+	job := desiredState[2].(common.GenericCreateAction).Ref.(*v1.Job)
+	assert.Equal(t, "backup-restore", job.Name)
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	assert.Contains(t, job.Spec.Template.Spec.Containers[0].Args[0], "is not empty")
+	assert.Contains(t, job.Spec.Template.Spec.Containers[0].Args[0], "-f /backup/backup.sql")
+
+	// when
+	cr.Spec.RestoreOptions.Force = true
+	cr.Spec.EncryptionKeySecretName = "backup-key"
+	job = model.PostgresqlRestore(cr)
+
+	// then
+	assert.Contains(t, job.Spec.Template.Spec.Containers[0].Args[0], "DROP SCHEMA public CASCADE")
+	assert.Contains(t, job.Spec.Template.Spec.Containers[0].Args[0], "--decrypt --output /tmp/backup.sql /backup/backup.sql.gpg")
+
+	// when
+	currentState.RestoreJob = job
+
+	// then
+	// the backup is only restored once
+	assert.Len(t, reconciler.Reconcile(currentState, cr), 2)
+}
@@ -0,0 +1,100 @@
+package keycloakclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+)
+
+// Keycloak answers with a masked secret from version 12 on
+const maskedClientSecret = "**********"
+
+// Fields of the client that have reconciles of their own
+var separatelyReconciledClientFields = map[string]bool{
+	"protocolMappers":       true,
+	"authorizationSettings": true,
+	"defaultClientScopes":   true,
+	"optionalClientScopes":  true,
+}
+
+// Keycloak keeps these as sets and returns them in any order
+var unorderedClientFields = map[string]bool{
+	"redirectUris": true,
+	"webOrigins":   true,
+	"defaultRoles": true,
+}
+
+// The update only sends the fields set in the CR, keycloak keeps the values it
+// filled in for the others. So the client is up to date when every field the update
+// would send already has the value of keycloak
+func clientUpToDate(desired, existing *kc.KeycloakAPIClient) bool {
+	desiredFields, err := clientFields(desired)
+	if err != nil {
+		return false
+	}
+	existingFields, err := clientFields(existing)
+	if err != nil {
+		return false
+	}
+
+	for name, value := range desiredFields {
+		if separatelyReconciledClientFields[name] {
+			continue
+		}
+		if name == "secret" && existingFields[name] == maskedClientSecret {
+			continue
+		}
+		if !clientFieldUpToDate(name, value, existingFields[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+func clientFields(client *kc.KeycloakAPIClient) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(client)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	err = json.Unmarshal(encoded, &fields)
+	return fields, err
+}
+
+func clientFieldUpToDate(name string, desired, existing interface{}) bool {
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		// Maps like the attributes are merged into the ones of keycloak, which has
+		// defaults of its own in there. An empty value is the same as a missing one
+		existingValue, _ := existing.(map[string]interface{})
+		for key, value := range desiredValue {
+			if isEmptyClientValue(value) && isEmptyClientValue(existingValue[key]) {
+				continue
+			}
+			if !reflect.DeepEqual(value, existingValue[key]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		if unorderedClientFields[name] {
+			existingValue, _ := existing.([]interface{})
+			return sameStringSet(clientStrings(desiredValue), clientStrings(existingValue))
+		}
+	}
+	return reflect.DeepEqual(desired, existing)
+}
+
+func isEmptyClientValue(value interface{}) bool {
+	return value == nil || value == ""
+}
+
+func clientStrings(values []interface{}) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, fmt.Sprint(value))
+	}
+	return result
+}
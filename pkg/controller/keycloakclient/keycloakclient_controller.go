@@ -3,6 +3,7 @@ package keycloakclient
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
@@ -12,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -27,7 +29,13 @@ var log = logf.Log.WithName("controller_keycloakclient")
 const (
 	ClientFinalizer   = "client.cleanup"
 	RequeueDelayError = 5 * time.Second
-	ControllerName    = "keycloakclient-controller"
+	// Upper bound of the backoff while keycloak is unavailable
+	RequeueDelayTransientMax = 5 * time.Minute
+	// Only to pick up the client once its owner is gone
+	RequeueDelayConflict = 60 * time.Second
+	// Until the finalizer is removed even though the client is still in keycloak
+	DefaultDeletionGracePeriod = 15 * time.Minute
+	ControllerName             = "keycloakclient-controller"
 )
 
 // Add creates a new KeycloakClient Controller and adds it to the Manager. The Manager will set fields on the Controller
@@ -47,6 +55,7 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 		cancel:   cancel,
 		context:  ctx,
 		recorder: mgr.GetEventRecorderFor(ControllerName),
+		backoff:  common.NewBackoff(RequeueDelayError, RequeueDelayTransientMax),
 	}
 }
 
@@ -73,9 +82,39 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Also watch secrets referenced by secretRef, these are not owned by the client
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+			return referencingClients(mgr.GetClient(), a.Meta.GetNamespace(), a.Meta.GetName())
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// Returns requests for all clients in the namespace that reference the given secret
+func referencingClients(c client.Client, namespace, name string) []reconcile.Request {
+	clients := &kc.KeycloakClientList{}
+	err := c.List(context.TODO(), clients, client.InNamespace(namespace))
+	if err != nil {
+		log.Error(err, "unable to list clients referencing secret")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, item := range clients.Items {
+		if item.Spec.SecretRef != nil && item.Spec.SecretRef.Name == name {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: item.Namespace, Name: item.Name},
+			})
+		}
+	}
+	return requests
+}
+
 // blank assignment to verify that ReconcileKeycloakClient implements reconcile.Reconciler
 var _ reconcile.Reconciler = &ReconcileKeycloakClient{}
 
@@ -88,6 +127,7 @@ type ReconcileKeycloakClient struct {
 	context  context.Context
 	cancel   context.CancelFunc
 	recorder record.EventRecorder
+	backoff  *common.Backoff
 }
 
 // Reconcile reads that state of the cluster for a KeycloakClient object and makes changes based on the state read
@@ -95,6 +135,7 @@ type ReconcileKeycloakClient struct {
 func (r *ReconcileKeycloakClient) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling KeycloakClient")
+	defer common.ObserveReconcileDuration("KeycloakClient", time.Now())
 
 	// Fetch the KeycloakClient instance
 	instance := &kc.KeycloakClient{}
@@ -104,6 +145,7 @@ func (r *ReconcileKeycloakClient) Reconcile(request reconcile.Request) (reconcil
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
+			common.ForgetPendingActions(instance, request.Namespace, request.Name)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -112,15 +154,59 @@ func (r *ReconcileKeycloakClient) Reconcile(request reconcile.Request) (reconcil
 
 	r.adjustCrDefaults(instance)
 
-	// The client may be applicable to multiple keycloak instances,
-	// process all of them
+	// A client that can't be removed from keycloak must not keep the resource forever
+	if isDeletionGracePeriodOver(instance, time.Now()) {
+		message := fmt.Sprintf("client %v/%v could not be removed from keycloak in time, it is left in place", instance.Namespace, instance.Spec.Client.ClientID)
+		r.recorder.Event(instance, "Warning", "DeletionGracePeriodOver", message)
+		log.Info(message)
+		return reconcile.Result{Requeue: false}, r.manageSuccess(instance, true)
+	}
+
+	// Keycloak accepts any redirect uri, typos only show once logins fail
+	if instance.DeletionTimestamp == nil {
+		err = common.ValidateRedirectURIs(instance.Spec.Client.RedirectUris, instance.Spec.RedirectURIValidation)
+		if err != nil {
+			return r.ManageError(instance, err)
+		}
+
+		// Origins keycloak can't match never allow a request, CORS just fails silently
+		err = common.ValidateWebOrigins(instance.Spec.Client.WebOrigins)
+		if err != nil {
+			return r.ManageError(instance, err)
+		}
+	}
+
 	realms, err := common.GetMatchingRealms(r.context, r.client, instance.Spec.RealmSelector)
 	if err != nil {
 		return r.ManageError(instance, err)
 	}
 	log.Info(fmt.Sprintf("found %v matching realm(s) for client %v/%v", len(realms.Items), instance.Namespace, instance.Name))
+
+	// Clients created in several realms before are still removed from all of them
+	if instance.DeletionTimestamp == nil {
+		err = common.ValidateSingleRealm(instance, realms.Items)
+		if err != nil {
+			return r.ManageError(instance, err)
+		}
+	}
+	// Instances in dry run mode only log the actions for this resource
+	dryRun := false
+	dryRunActions := 0
 	for _, realm := range realms.Items {
-		keycloaks, err := common.GetMatchingKeycloaks(r.context, r.client, realm.Spec.InstanceSelector)
+		// Two resources managing the same client would keep undoing each other's changes
+		owner, err := common.GetClientOwner(r.context, r.client, instance, realm)
+		if err != nil {
+			return r.ManageError(instance, err)
+		}
+		if owner != nil {
+			// The client in keycloak belongs to the owner, it must not be removed along with this resource
+			if instance.DeletionTimestamp != nil {
+				continue
+			}
+			return r.manageConflict(instance, owner, realm)
+		}
+
+		keycloaks, err := common.GetRealmKeycloaks(r.context, r.client, &realm)
 		if err != nil {
 			return r.ManageError(instance, err)
 		}
@@ -129,7 +215,7 @@ func (r *ReconcileKeycloakClient) Reconcile(request reconcile.Request) (reconcil
 		for _, keycloak := range keycloaks.Items {
 			// Get an authenticated keycloak api client for the instance
 			keycloakFactory := common.LocalConfigKeycloakFactory{}
-			authenticated, err := keycloakFactory.AuthenticatedClient(keycloak)
+			authenticated, err := keycloakFactory.AuthenticatedRealmClient(keycloak, realm)
 			if err != nil {
 				return r.ManageError(instance, err)
 			}
@@ -155,17 +241,62 @@ func (r *ReconcileKeycloakClient) Reconcile(request reconcile.Request) (reconcil
 			// the desired state
 			reconciler := NewKeycloakClientReconciler(keycloak)
 			desiredState := reconciler.Reconcile(clientState, instance)
-			actionRunner := common.NewClusterAndKeycloakActionRunner(r.context, r.client, r.scheme, instance, authenticated)
+			if missing := reconciler.MissingServiceAccountRoles(clientState, instance); len(missing) > 0 && instance.DeletionTimestamp == nil {
+				message := fmt.Sprintf("service account roles %v of client %v don't exist in realm %v, they are not assigned", strings.Join(missing, ", "), instance.Spec.Client.ClientID, clientState.Realm.Spec.Realm.Realm)
+				log.Info(message)
+				r.recorder.Event(instance, "Warning", "MissingRoles", message)
+			}
+			actionRunner := common.NewClusterAndKeycloakActionRunner(r.context, r.client, r.scheme, instance, authenticated, r.recorder)
+			if keycloak.Spec.DryRun {
+				actionRunner = common.NewDryRunActionRunner(r.context, r.client, r.scheme, instance)
+				dryRun = true
+				dryRunActions += len(desiredState)
+			}
 
 			// Run all actions to keep the realms updated
 			err = actionRunner.RunAll(desiredState)
+			if !keycloak.Spec.DryRun && instance.DeletionTimestamp == nil {
+				r.updateManagedRoles(instance, clientState, authenticated)
+			}
 			if err != nil {
 				return r.ManageError(instance, err)
 			}
 		}
 	}
 
-	return reconcile.Result{Requeue: false}, r.manageSuccess(instance, instance.DeletionTimestamp != nil)
+	if dryRun {
+		return reconcile.Result{Requeue: false}, common.ManageDryRun(r.context, r.client, instance, &instance.Status.Message, dryRunActions)
+	}
+
+	// Only recorded once every action succeeded, so that a failed update is retried
+	instance.Status.ManagedTokenSettings = common.ManagedTokenSettings(instance)
+	return common.ResyncResult(instance.Spec.ResyncPeriod), r.manageSuccess(instance, instance.DeletionTimestamp != nil)
+}
+
+// Reports the roles as they are in keycloak, whether or not all actions succeeded.
+// The status is kept as it was when the roles can't be read
+func (r *ReconcileKeycloakClient) updateManagedRoles(cr *kc.KeycloakClient, state *common.ClientState, authenticated common.KeycloakInterface) {
+	var roles []kc.RoleRepresentation
+	if cr.Spec.Client.ID != "" {
+		var err error
+		roles, err = authenticated.ListClientRoles(cr.Spec.Client.ID, state.Realm.Spec.Realm.Realm)
+		if err != nil {
+			log.Error(err, "unable to read client roles for status")
+			return
+		}
+	}
+	cr.Status.ManagedRoles = GetManagedRoles(state.Roles, roles, cr.Spec.Roles)
+}
+
+func isDeletionGracePeriodOver(cr *kc.KeycloakClient, now time.Time) bool {
+	if cr.DeletionTimestamp == nil {
+		return false
+	}
+	gracePeriod := DefaultDeletionGracePeriod
+	if cr.Spec.DeletionGracePeriod != nil {
+		gracePeriod = cr.Spec.DeletionGracePeriod.Duration
+	}
+	return now.Sub(cr.DeletionTimestamp.Time) > gracePeriod
 }
 
 // Fills the CR with default values. Nils are not acceptable for Kubernetes.
@@ -178,7 +309,11 @@ func (r *ReconcileKeycloakClient) adjustCrDefaults(cr *kc.KeycloakClient) {
 	}
 }
 
+// Only the message is reported, the phase and the finalizer are left alone as
+// nothing was changed
 func (r *ReconcileKeycloakClient) manageSuccess(client *kc.KeycloakClient, deleted bool) error {
+	r.backoff.Reset(client)
+	common.SetSuccessConditions(&client.Status.Conditions, client.Generation)
 	client.Status.Ready = true
 	client.Status.Message = ""
 	client.Status.Phase = v1alpha1.PhaseReconciling
@@ -229,18 +364,58 @@ func (r *ReconcileKeycloakClient) manageSuccess(client *kc.KeycloakClient, delet
 	return r.client.Update(r.context, client)
 }
 
+func (r *ReconcileKeycloakClient) manageConflict(client, owner *kc.KeycloakClient, realm kc.KeycloakRealm) (reconcile.Result, error) {
+	issue := fmt.Errorf("client %v in realm %v/%v is already managed by keycloak client %v/%v",
+		client.Spec.Client.ClientID,
+		realm.Namespace,
+		realm.Name,
+		owner.Namespace,
+		owner.Name)
+	message := issue.Error()
+	r.recorder.Event(client, "Warning", "Conflicting", message)
+
+	client.Status.Message = message
+	client.Status.Ready = false
+	client.Status.Phase = v1alpha1.PhaseConflicting
+	common.SetErrorConditions(&client.Status.Conditions, client.Generation, issue)
+
+	err := r.client.Status().Update(r.context, client)
+	if err != nil {
+		log.Error(err, "unable to update status")
+	}
+
+	return reconcile.Result{
+		RequeueAfter: RequeueDelayConflict,
+		Requeue:      true,
+	}, nil
+}
+
 func (r *ReconcileKeycloakClient) ManageError(realm *kc.KeycloakClient, issue error) (reconcile.Result, error) {
+	// Keycloak is expected to be unavailable for a while during rollouts
+	if common.IsTransientError(issue) {
+		return common.ManageTransientError(r.context, r.client, r.backoff, realm, issue, func(message string) {
+			realm.Status.Message = message
+			common.SetTransientErrorConditions(&realm.Status.Conditions, realm.Generation, issue)
+			realm.Status.Ready = false
+		})
+	}
+
 	r.recorder.Event(realm, "Warning", "ProcessingError", issue.Error())
 
 	realm.Status.Message = issue.Error()
 	realm.Status.Ready = false
 	realm.Status.Phase = v1alpha1.PhaseFailing
+	common.SetErrorConditions(&realm.Status.Conditions, realm.Generation, issue)
 
 	err := r.client.Status().Update(r.context, realm)
 	if err != nil {
 		log.Error(err, "unable to update status")
 	}
 
+	if err == nil && common.IsInvalidSpecError(issue) {
+		return reconcile.Result{}, nil
+	}
+
 	return reconcile.Result{
 		RequeueAfter: RequeueDelayError,
 		Requeue:      true,
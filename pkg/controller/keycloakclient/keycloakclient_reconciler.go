@@ -2,12 +2,20 @@ package keycloakclient
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	"github.com/keycloak/keycloak-operator/pkg/common"
 	"github.com/keycloak/keycloak-operator/pkg/model"
+	v1 "k8s.io/api/core/v1"
 )
 
+// Above this many roles to create, they are imported in one request
+const BulkRoleCreateThreshold = 10
+
 type Reconciler interface {
 	Reconcile(cr *kc.KeycloakClient) error
 }
@@ -27,101 +35,558 @@ func (i *KeycloakClientReconciler) Reconcile(state *common.ClientState, cr *kc.K
 
 	desired.AddAction(i.pingKeycloak())
 	if cr.DeletionTimestamp != nil {
-		desired.AddAction(i.getDeletedClientState(state, cr))
+		i.ReconcileDeletion(state, cr, &desired)
 		return desired
 	}
 
 	if state.Client == nil {
 		desired.AddAction(i.getCreatedClientState(state, cr))
 	} else {
-		desired.AddAction(i.getUpdatedClientState(state, cr))
+		updated := clientWithKeycloakWebOrigins(state, clientWithUnmanagedSettingsCleared(state, cr))
+		if !clientUpToDate(updated.Spec.Client, state.Client) {
+			desired.AddAction(i.getUpdatedClientState(state, updated))
+		}
+		if state.ClientSecretDiverged && hasClientSecret(cr) {
+			desired.AddAction(i.getUpdatedClientSecretInKeycloakState(state, cr))
+		}
 	}
 
-	if state.ClientSecret == nil {
-		desired.AddAction(i.getCreatedClientSecretState(state, cr))
+	// The client Secret as the actions leave it, for the token to be added to
+	var secret *v1.Secret
+	if !hasClientSecret(cr) {
+		// A secret left over from before the client type changed would never match keycloak
+		if state.ClientSecret != nil {
+			desired.AddAction(i.getDeletedClientSecretState(state, cr))
+		}
+	} else if state.ClientSecret == nil {
+		secret = model.ClientSecret(cr)
+		desired.AddAction(i.getCreatedClientSecretState(state, cr, secret))
+	} else if i.isClientSecretRotationDue(state, cr) {
+		secret = state.ClientSecret.DeepCopy()
+		desired.AddAction(i.getRegeneratedClientSecretState(state, cr, secret))
 	} else {
-		desired.AddAction(i.getUpdatedClientSecretState(state, cr))
+		secret = model.ClientSecretReconciled(cr, state.ClientSecret)
+		desired.AddAction(i.getUpdatedClientSecretState(state, cr, secret))
+	}
+	if i.isRegistrationAccessTokenDue(state, cr) {
+		desired.AddAction(i.getRegeneratedRegistrationAccessTokenState(state, cr, secret))
 	}
 
 	i.ReconcileRoles(state, cr, &desired)
+	i.ReconcileServiceAccountRoles(state, cr, &desired)
+	i.ReconcileScopeMappings(state, cr, &desired)
+	i.ReconcileDefaultRoles(state, cr, &desired)
+	i.ReconcileClientScopes(state, cr, &desired)
+	i.ReconcileProtocolMappers(state, cr, &desired)
+	i.ReconcileAuthorizationSettings(state, cr, &desired)
 
 	return desired
 }
 
+// Children go before the client, so that a deletion interrupted halfway leaves the
+// client to be found again on the next attempt. Keycloak and kubernetes both accept
+// deleting what is already gone
+func (i *KeycloakClientReconciler) ReconcileDeletion(state *common.ClientState, cr *kc.KeycloakClient, desired *common.DesiredClusterState) {
+	if state.Client != nil {
+		for index := range state.Client.ProtocolMappers {
+			desired.AddAction(i.getDeletedProtocolMapperState(state, cr, state.Client.ProtocolMappers[index].DeepCopy()))
+		}
+		for index := range state.Roles {
+			desired.AddAction(i.getDeletedClientRoleState(state, cr, state.Roles[index].DeepCopy()))
+		}
+	}
+	desired.AddAction(i.getDeletedClientState(state, cr))
+	if state.ClientSecret != nil {
+		desired.AddAction(i.getDeletedClientSecretState(state, cr))
+	}
+}
+
+// Public and bearer-only clients don't authenticate themselves, so no client secret
+// is managed for them
+func hasClientSecret(cr *kc.KeycloakClient) bool {
+	return !cr.Spec.Client.PublicClient && !common.IsTrue(cr.Spec.Client.BearerOnly)
+}
+
+// Externally managed secrets are rotated elsewhere, and the secret of a new client
+// can only be rotated once it exists
+func (i *KeycloakClientReconciler) isClientSecretRotationDue(state *common.ClientState, cr *kc.KeycloakClient) bool {
+	period := cr.Spec.SecretRotationPeriod
+	if period == nil || period.Duration <= 0 || cr.Spec.SecretRef != nil || state.Client == nil {
+		return false
+	}
+	return time.Since(model.ClientSecretLastRotation(state.ClientSecret)) >= period.Duration
+}
+
+// A token is issued for new clients, clients whose Secret has none yet, and along
+// with a regenerated client secret
+func (i *KeycloakClientReconciler) isRegistrationAccessTokenDue(state *common.ClientState, cr *kc.KeycloakClient) bool {
+	if !cr.Spec.RegistrationAccessToken || !hasClientSecret(cr) {
+		return false
+	}
+	if state.Client == nil || state.ClientSecret == nil {
+		return true
+	}
+	return len(state.ClientSecret.Data[model.ClientSecretRegistrationTokenProperty]) == 0 || i.isClientSecretRotationDue(state, cr)
+}
+
 func (i *KeycloakClientReconciler) ReconcileRoles(state *common.ClientState, cr *kc.KeycloakClient, desired *common.DesiredClusterState) {
-	// delete existing roles for which no desired role is found that (matches by ID OR has no ID but matches by name)
-	// this implies that specifying a role with matching name but different ID will result in deletion (and re-creation)
-	rolesDeleted, _ := roleDifferenceIntersection(state.Roles, cr.Spec.Roles)
-	for _, role := range rolesDeleted {
+	// a missing client (never created or deleted in keycloak) has no roles, so any
+	// roles still known with an ID are phantoms that must not be updated or deleted
+	if state.Client == nil {
+		var created []kc.RoleRepresentation
+		for _, role := range cr.Spec.Roles {
+			newRole := role.DeepCopy()
+			newRole.ID = ""
+			created = append(created, *newRole)
+		}
+		rolesCreated := i.reconcileCreatedRoles(state, cr, created, desired)
+		i.ReconcileRoleComposites(state, cr, rolesCreated, desired)
+		return
+	}
+
+	diff := common.DiffRoles(state.Roles, cr.Spec.Roles)
+	for _, role := range diff.Deleted {
 		desired.AddAction(i.getDeletedClientRoleState(state, cr, role.DeepCopy()))
 	}
+	previous := rolesByName(state.Roles)
+	for _, update := range diff.Updated {
+		if roleUpToDate(&update.Role, roleBeforeUpdate(previous, update)) {
+			continue
+		}
+		desired.AddAction(i.getUpdatedClientRoleState(state, cr, update.Role.DeepCopy(), update.OldRole.DeepCopy()))
+	}
+	rolesCreated := i.reconcileCreatedRoles(state, cr, diff.Created, desired)
+
+	// composites go last, as they may reference any of the roles created above
+	i.ReconcileRoleComposites(state, cr, rolesCreated, desired)
+}
+
+// The many roles of a new client are created with a single partial import rather
+// than one request each. Once the client exists, roles are created one by one, as
+// updates and deletes always are
+func (i *KeycloakClientReconciler) reconcileCreatedRoles(state *common.ClientState, cr *kc.KeycloakClient, roles []kc.RoleRepresentation, desired *common.DesiredClusterState) map[string]bool {
+	rolesCreated := make(map[string]bool)
+	for _, role := range roles {
+		rolesCreated[role.Name] = true
+	}
+
+	if state.Client == nil && len(state.Roles) == 0 && len(roles) > BulkRoleCreateThreshold {
+		desired.AddAction(i.getCreatedClientRolesState(state, cr, roles))
+		return rolesCreated
+	}
+	for index := range roles {
+		desired.AddAction(i.getCreatedClientRoleState(state, cr, roles[index].DeepCopy()))
+	}
+	return rolesCreated
+}
+
+// The roles of the client as keycloak has them after the actions ran, set against
+// what ReconcileRoles planned for the roles it was given. A role that doesn't look
+// as desired afterwards, or a deleted one that is still there, has failed
+func GetManagedRoles(before, after, desired []kc.RoleRepresentation) []kc.ManagedRole {
+	planned := common.DiffRoles(before, desired)
+	actions := make(map[string]string)
+	for _, role := range planned.Created {
+		actions[role.Name] = kc.ManagedRoleCreated
+	}
+	previous := rolesByName(before)
+	for _, update := range planned.Updated {
+		oldRole := roleBeforeUpdate(previous, update)
+		switch {
+		case update.Role.Name != oldRole.Name:
+			actions[update.Role.Name] = kc.ManagedRoleRenamed
+		case roleUpToDate(&update.Role, oldRole):
+			actions[update.Role.Name] = kc.ManagedRoleUnchanged
+		default:
+			actions[update.Role.Name] = kc.ManagedRoleUpdated
+		}
+	}
+
+	current := make(map[string]*kc.RoleRepresentation)
+	currentIDs := make(map[string]bool)
+	for index := range after {
+		current[after[index].Name] = &after[index]
+		currentIDs[after[index].ID] = true
+	}
+
+	var managedRoles []kc.ManagedRole
+	for index := range desired {
+		role := &desired[index]
+		managedRole := kc.ManagedRole{Name: role.Name, LastAction: actions[role.Name]}
+		if existing, ok := current[role.Name]; ok {
+			managedRole.ID = existing.ID
+			if !roleUpToDate(role, existing) {
+				managedRole.LastAction = kc.ManagedRoleFailed
+			}
+		} else {
+			managedRole.LastAction = kc.ManagedRoleFailed
+		}
+		managedRoles = append(managedRoles, managedRole)
+	}
+	for _, role := range planned.Deleted {
+		managedRole := kc.ManagedRole{Name: role.Name, ID: role.ID, LastAction: kc.ManagedRoleDeleted}
+		if currentIDs[role.ID] {
+			managedRole.LastAction = kc.ManagedRoleFailed
+		}
+		managedRoles = append(managedRoles, managedRole)
+	}
+	return managedRoles
+}
+
+func rolesByName(roles []kc.RoleRepresentation) map[string]*kc.RoleRepresentation {
+	byName := make(map[string]*kc.RoleRepresentation)
+	for index := range roles {
+		byName[roles[index].Name] = &roles[index]
+	}
+	return byName
+}
+
+// Roles matched by name only come without their old state
+func roleBeforeUpdate(previous map[string]*kc.RoleRepresentation, update common.RoleUpdate) *kc.RoleRepresentation {
+	if update.Role.ID == "" && previous[update.Role.Name] != nil {
+		return previous[update.Role.Name]
+	}
+	return &update.OldRole
+}
+
+// Attributes are only compared when set in the CR
+func roleUpToDate(desired, existing *kc.RoleRepresentation) bool {
+	if desired.Name != existing.Name || desired.Description != existing.Description {
+		return false
+	}
+	if len(desired.Attributes) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(desired.Attributes, existing.Attributes)
+}
+
+func (i *KeycloakClientReconciler) ReconcileRoleComposites(state *common.ClientState, cr *kc.KeycloakClient, rolesCreated map[string]bool, desired *common.DesiredClusterState) {
+	for _, role := range cr.Spec.Roles {
+		// (re-)created roles start out without any composites
+		var current *kc.RoleRepresentationComposites
+		if !rolesCreated[role.Name] {
+			for _, existingRole := range state.Roles {
+				if common.RoleMatches(role, existingRole) {
+					current = state.RoleComposites[existingRole.Name]
+					break
+				}
+			}
+		}
+
+		if removed := common.CompositesDifference(current, role.Composites); removed != nil {
+			desired.AddAction(i.getRemovedClientRoleCompositesState(state, cr, role.DeepCopy(), removed))
+		}
+		if added := common.CompositesDifference(role.Composites, current); added != nil {
+			desired.AddAction(i.getAddedClientRoleCompositesState(state, cr, role.DeepCopy(), added))
+		}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Scope mappings only take effect once the full scope is disallowed, all roles are
+// in scope otherwise, and keycloak allows full scope by default
+func (i *KeycloakClientReconciler) ReconcileScopeMappings(state *common.ClientState, cr *kc.KeycloakClient, desired *common.DesiredClusterState) {
+	if cr.Spec.ScopeMappings == nil {
+		return
+	}
+
+	fullScopeAllowed := cr.Spec.Client.FullScopeAllowed
+	if fullScopeAllowed == nil || *fullScopeAllowed {
+		log.Info(fmt.Sprintf("full scope is allowed for client %v/%v, ignoring scope mappings", cr.Namespace, cr.Spec.Client.ClientID))
+		return
+	}
+
+	if removed := common.CompositesDifference(state.ScopeMappings, cr.Spec.ScopeMappings); removed != nil {
+		desired.AddAction(i.getRemovedScopeMappingsState(state, cr, removed))
+	}
+	if added := common.CompositesDifference(cr.Spec.ScopeMappings, state.ScopeMappings); added != nil {
+		desired.AddAction(i.getAddedScopeMappingsState(state, cr, added))
+	}
+}
+
+// Roles created in this pass are resolved when the action runs, deleted roles leave the
+// default roles on their own
+func (i *KeycloakClientReconciler) ReconcileDefaultRoles(state *common.ClientState, cr *kc.KeycloakClient, desired *common.DesiredClusterState) {
+	if cr.Spec.DefaultRoles == nil {
+		return
+	}
+
+	roles := make(map[string]bool)
+	for _, role := range cr.Spec.Roles {
+		roles[role.Name] = true
+	}
+
+	var removed []string
+	for _, name := range state.DefaultRoles {
+		if !containsString(cr.Spec.DefaultRoles, name) && roles[name] {
+			removed = append(removed, name)
+		}
+	}
+	var added []string
+	for _, name := range cr.Spec.DefaultRoles {
+		if !containsString(state.DefaultRoles, name) {
+			added = append(added, name)
+		}
+	}
+
+	if len(removed) > 0 {
+		desired.AddAction(i.getRemovedRealmDefaultRolesState(state, cr, removed))
+	}
+	if len(added) > 0 {
+		desired.AddAction(i.getAddedRealmDefaultRolesState(state, cr, added))
+	}
+}
+
+// Only realm roles are managed when set, and client roles for the clients listed in the CR,
+// so that the default roles of service accounts stay untouched otherwise
+func (i *KeycloakClientReconciler) ReconcileServiceAccountRoles(state *common.ClientState, cr *kc.KeycloakClient, desired *common.DesiredClusterState) {
+	if cr.Spec.ServiceAccountRealmRoles == nil && cr.Spec.ServiceAccountClientRoles == nil {
+		return
+	}
+
+	if !common.IsTrue(cr.Spec.Client.ServiceAccountsEnabled) {
+		log.Info(fmt.Sprintf("service accounts are not enabled for client %v/%v, ignoring service account roles", cr.Namespace, cr.Spec.Client.ClientID))
+		return
+	}
 
-	// update with desired roles that can be matched to existing roles and have an ID set, this includes all renames
-	// note down all renames
-	existingRoleByID := make(map[string]kc.RoleRepresentation)
-	for _, role := range state.Roles {
-		existingRoleByID[role.ID] = role
-	}
-	renamedRolesOldNames := make(map[string]bool)
-	_, rolesMatching := roleDifferenceIntersection(cr.Spec.Roles, state.Roles)
-	for _, role := range rolesMatching {
-		if role.ID != "" {
-			oldRole := existingRoleByID[role.ID]
-			desired.AddAction(i.getUpdatedClientRoleState(state, cr, role.DeepCopy(), oldRole.DeepCopy()))
-			if role.Name != oldRole.Name {
-				renamedRolesOldNames[oldRole.Name] = true
+	// The service account user only exists once the client has been created
+	serviceAccount := state.ServiceAccount
+	if serviceAccount == nil {
+		return
+	}
+
+	if cr.Spec.ServiceAccountRealmRoles != nil {
+		for _, role := range cr.Spec.ServiceAccountRealmRoles {
+			roleRef := serviceAccount.GetAvailableRealmRole(role)
+			if roleRef == nil || hasUserRole(serviceAccount.RealmRoles, role) {
+				continue
+			}
+			desired.AddAction(i.getAssignedServiceAccountRoleState(state, cr, roleRef, nil))
+		}
+
+		for _, role := range serviceAccount.RealmRoles {
+			if !containsString(cr.Spec.ServiceAccountRealmRoles, role.Name) {
+				desired.AddAction(i.getRemovedServiceAccountRoleState(state, cr, role, nil))
+			}
+		}
+	}
+
+	clientIDs := make([]string, 0, len(cr.Spec.ServiceAccountClientRoles))
+	for clientID := range cr.Spec.ServiceAccountClientRoles {
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Strings(clientIDs)
+
+	for _, clientID := range clientIDs {
+		client := serviceAccount.GetClientByID(clientID)
+		if client == nil {
+			continue
+		}
+
+		roles := cr.Spec.ServiceAccountClientRoles[clientID]
+		for _, role := range roles {
+			roleRef := serviceAccount.GetAvailableClientRole(role, clientID)
+			if roleRef == nil || hasUserRole(serviceAccount.ClientRoles[clientID], role) {
+				continue
+			}
+			desired.AddAction(i.getAssignedServiceAccountRoleState(state, cr, roleRef, client))
+		}
+
+		for _, role := range serviceAccount.ClientRoles[clientID] {
+			if !containsString(roles, role.Name) {
+				desired.AddAction(i.getRemovedServiceAccountRoleState(state, cr, role, client))
 			}
 		}
 	}
+}
+
+// Service account roles that neither exist in Keycloak nor are assigned already, as
+// "role" for realm roles and "clientID/role" for client roles. They are skipped when
+// reconciling, the controller warns about them instead
+func (i *KeycloakClientReconciler) MissingServiceAccountRoles(state *common.ClientState, cr *kc.KeycloakClient) []string {
+	serviceAccount := state.ServiceAccount
+	if serviceAccount == nil || !common.IsTrue(cr.Spec.Client.ServiceAccountsEnabled) {
+		return nil
+	}
+
+	var missing []string
+	for _, role := range cr.Spec.ServiceAccountRealmRoles {
+		if serviceAccount.GetAvailableRealmRole(role) == nil && !hasUserRole(serviceAccount.RealmRoles, role) {
+			missing = append(missing, role)
+		}
+	}
+	for clientID, roles := range cr.Spec.ServiceAccountClientRoles {
+		known := serviceAccount.GetClientByID(clientID) != nil
+		for _, role := range roles {
+			if !known || serviceAccount.GetAvailableClientRole(role, clientID) == nil && !hasUserRole(serviceAccount.ClientRoles[clientID], role) {
+				missing = append(missing, fmt.Sprintf("%v/%v", clientID, role))
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// Scopes are only managed for lists that are set in the CR. New clients get
+// their scopes on creation, so there is nothing to diff against until then
+func (i *KeycloakClientReconciler) ReconcileClientScopes(state *common.ClientState, cr *kc.KeycloakClient, desired *common.DesiredClusterState) {
+	if state.Client == nil {
+		return
+	}
+
+	defaultScopes := cr.Spec.Client.DefaultClientScopes
+	optionalScopes := cr.Spec.Client.OptionalClientScopes
 
-	// seemingly matching roles without an ID can either be regular updates
-	// or re-creations after renames (not deletions)
-	// note that duplicate role names are impossible thanks to +listType=map
-	for _, role := range rolesMatching {
-		if role.ID == "" {
-			if _, contains := renamedRolesOldNames[role.Name]; contains {
-				desired.AddAction(i.getCreatedClientRoleState(state, cr, role.DeepCopy()))
+	// A scope can't be default and optional at the same time, so removals go
+	// first to allow moving a scope from one list to the other
+	if defaultScopes != nil {
+		for _, scope := range state.DefaultClientScopes {
+			if !containsString(defaultScopes, scope.Name) {
+				desired.AddAction(i.getRemovedDefaultClientScopeState(state, cr, scope.DeepCopy()))
+			}
+		}
+	}
+	if optionalScopes != nil {
+		for _, scope := range state.OptionalClientScopes {
+			if !containsString(optionalScopes, scope.Name) {
+				desired.AddAction(i.getRemovedOptionalClientScopeState(state, cr, scope.DeepCopy()))
+			}
+		}
+	}
+
+	for _, name := range defaultScopes {
+		if !hasClientScope(state.DefaultClientScopes, name) {
+			desired.AddAction(i.getAddedDefaultClientScopeState(state, cr, &kc.KeycloakAPIClientScope{Name: name}))
+		}
+	}
+	for _, name := range optionalScopes {
+		if !hasClientScope(state.OptionalClientScopes, name) {
+			desired.AddAction(i.getAddedOptionalClientScopeState(state, cr, &kc.KeycloakAPIClientScope{Name: name}))
+		}
+	}
+}
+
+// Mirrors ReconcileRoles: mappers are matched by ID when set and by name otherwise.
+// Mappers are only managed when set in the CR, so that mappers added through
+// the admin console of existing clients are not wiped. New clients get their
+// mappers on creation
+func (i *KeycloakClientReconciler) ReconcileProtocolMappers(state *common.ClientState, cr *kc.KeycloakClient, desired *common.DesiredClusterState) {
+	if state.Client == nil || cr.Spec.Client.ProtocolMappers == nil {
+		return
+	}
+
+	existingMappers := state.Client.ProtocolMappers
+	desiredMappers := cr.Spec.Client.ProtocolMappers
+
+	mappersDeleted, _ := protocolMapperDifferenceIntersection(existingMappers, desiredMappers)
+	for _, mapper := range mappersDeleted {
+		desired.AddAction(i.getDeletedProtocolMapperState(state, cr, mapper.DeepCopy()))
+	}
+
+	existingMapperByID := make(map[string]kc.KeycloakProtocolMapper)
+	existingMapperByName := make(map[string]kc.KeycloakProtocolMapper)
+	for _, mapper := range existingMappers {
+		existingMapperByID[mapper.ID] = mapper
+		existingMapperByName[mapper.Name] = mapper
+	}
+	renamedMappersOldNames := make(map[string]bool)
+	_, mappersMatching := protocolMapperDifferenceIntersection(desiredMappers, existingMappers)
+	for _, mapper := range mappersMatching {
+		if mapper.ID != "" {
+			oldMapper := existingMapperByID[mapper.ID]
+			desired.AddAction(i.getUpdatedProtocolMapperState(state, cr, mapper.DeepCopy(), oldMapper.DeepCopy()))
+			if mapper.Name != oldMapper.Name {
+				renamedMappersOldNames[oldMapper.Name] = true
+			}
+		}
+	}
+
+	for _, mapper := range mappersMatching {
+		if mapper.ID == "" {
+			if _, contains := renamedMappersOldNames[mapper.Name]; contains {
+				desired.AddAction(i.getCreatedProtocolMapperState(state, cr, mapper.DeepCopy()))
 			} else {
-				desired.AddAction(i.getUpdatedClientRoleState(state, cr, role.DeepCopy(), role.DeepCopy()))
+				oldMapper := existingMapperByName[mapper.Name]
+				desired.AddAction(i.getUpdatedProtocolMapperState(state, cr, mapper.DeepCopy(), oldMapper.DeepCopy()))
 			}
 		}
 	}
 
-	// always create roles that don't match any existing ones
-	rolesNew, _ := roleDifferenceIntersection(cr.Spec.Roles, state.Roles)
-	for _, role := range rolesNew {
-		desired.AddAction(i.getCreatedClientRoleState(state, cr, role.DeepCopy()))
+	mappersNew, _ := protocolMapperDifferenceIntersection(desiredMappers, existingMappers)
+	for _, mapper := range mappersNew {
+		desired.AddAction(i.getCreatedProtocolMapperState(state, cr, mapper.DeepCopy()))
+	}
+}
+
+// The authorization settings are replaced as a whole on every pass. New clients
+// import them on creation already
+func (i *KeycloakClientReconciler) ReconcileAuthorizationSettings(state *common.ClientState, cr *kc.KeycloakClient, desired *common.DesiredClusterState) {
+	if state.Client == nil || cr.Spec.Client.AuthorizationSettings == nil {
+		return
+	}
+
+	if !common.IsTrue(cr.Spec.Client.AuthorizationServicesEnabled) {
+		log.Info(fmt.Sprintf("authorization services are not enabled for client %v/%v, ignoring authorization settings", cr.Namespace, cr.Spec.Client.ClientID))
+		return
 	}
+
+	desired.AddAction(i.getUpdatedAuthorizationSettingsState(state, cr))
 }
 
-// returned roles are always from a
-func roleDifferenceIntersection(a []kc.RoleRepresentation, b []kc.RoleRepresentation) (d []kc.RoleRepresentation, i []kc.RoleRepresentation) {
-	for _, role := range a {
-		if hasMatchingRole(b, role) {
-			i = append(i, role)
+// returned mappers are always from a
+func protocolMapperDifferenceIntersection(a []kc.KeycloakProtocolMapper, b []kc.KeycloakProtocolMapper) (d []kc.KeycloakProtocolMapper, i []kc.KeycloakProtocolMapper) {
+	for _, mapper := range a {
+		if hasMatchingProtocolMapper(b, mapper) {
+			i = append(i, mapper)
 		} else {
-			d = append(d, role)
+			d = append(d, mapper)
 		}
 	}
 	return d, i
 }
 
-func hasMatchingRole(roles []kc.RoleRepresentation, otherRole kc.RoleRepresentation) bool {
-	for _, role := range roles {
-		if roleMatches(role, otherRole) {
+func hasMatchingProtocolMapper(mappers []kc.KeycloakProtocolMapper, otherMapper kc.KeycloakProtocolMapper) bool {
+	for _, mapper := range mappers {
+		if protocolMapperMatches(mapper, otherMapper) {
 			return true
 		}
 	}
 	return false
 }
 
-func roleMatches(a kc.RoleRepresentation, b kc.RoleRepresentation) bool {
+func protocolMapperMatches(a kc.KeycloakProtocolMapper, b kc.KeycloakProtocolMapper) bool {
 	if a.ID != "" && b.ID != "" {
 		return a.ID == b.ID
 	}
 	return a.Name == b.Name
 }
 
+func hasClientScope(scopes []kc.KeycloakAPIClientScope, name string) bool {
+	for _, scope := range scopes {
+		if scope.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasUserRole(roles []*kc.KeycloakUserRole, name string) bool {
+	for _, role := range roles {
+		if role.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (i *KeycloakClientReconciler) pingKeycloak() common.ClusterAction {
 	return common.PingAction{
 		Msg: "check if keycloak is available",
@@ -144,13 +609,46 @@ func (i *KeycloakClientReconciler) getCreatedClientState(state *common.ClientSta
 	}
 }
 
-func (i *KeycloakClientReconciler) getUpdatedClientSecretState(state *common.ClientState, cr *kc.KeycloakClient) common.ClusterAction {
+func (i *KeycloakClientReconciler) getUpdatedClientSecretState(state *common.ClientState, cr *kc.KeycloakClient, secret *v1.Secret) common.ClusterAction {
 	return common.GenericUpdateAction{
-		Ref: model.ClientSecretReconciled(cr, state.ClientSecret),
+		Ref: secret,
 		Msg: fmt.Sprintf("update client secret %v/%v", cr.Namespace, cr.Spec.Client.ClientID),
 	}
 }
 
+func (i *KeycloakClientReconciler) getDeletedClientSecretState(state *common.ClientState, cr *kc.KeycloakClient) common.ClusterAction {
+	return common.GenericDeleteAction{
+		Ref: state.ClientSecret,
+		Msg: fmt.Sprintf("delete client secret %v/%v", cr.Namespace, cr.Spec.Client.ClientID),
+	}
+}
+
+func (i *KeycloakClientReconciler) getRegeneratedClientSecretState(state *common.ClientState, cr *kc.KeycloakClient, secret *v1.Secret) common.ClusterAction {
+	return common.RegenerateClientSecretAction{
+		Ref:    cr,
+		Secret: secret,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("regenerate client secret %v/%v", cr.Namespace, cr.Spec.Client.ClientID),
+	}
+}
+
+func (i *KeycloakClientReconciler) getRegeneratedRegistrationAccessTokenState(state *common.ClientState, cr *kc.KeycloakClient, secret *v1.Secret) common.ClusterAction {
+	return common.RegenerateRegistrationAccessTokenAction{
+		Ref:    cr,
+		Secret: secret,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("regenerate registration access token of client %v/%v", cr.Namespace, cr.Spec.Client.ClientID),
+	}
+}
+
+func (i *KeycloakClientReconciler) getUpdatedClientSecretInKeycloakState(state *common.ClientState, cr *kc.KeycloakClient) common.ClusterAction {
+	return common.UpdateClientSecretAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("update client secret of %v/%v from secret %v", cr.Namespace, cr.Spec.Client.ClientID, cr.Spec.SecretRef.Name),
+	}
+}
+
 func (i *KeycloakClientReconciler) getUpdatedClientState(state *common.ClientState, cr *kc.KeycloakClient) common.ClusterAction {
 	return common.UpdateClientAction{
 		Ref:   cr,
@@ -159,9 +657,53 @@ func (i *KeycloakClientReconciler) getUpdatedClientState(state *common.ClientSta
 	}
 }
 
-func (i *KeycloakClientReconciler) getCreatedClientSecretState(state *common.ClientState, cr *kc.KeycloakClient) common.ClusterAction {
+// Keycloak keeps web origins as a set and returns them in any order. When they
+// are the ones of the CR, the update sends them in the order of keycloak, so that
+// a reordering never counts as a change
+func clientWithKeycloakWebOrigins(state *common.ClientState, cr *kc.KeycloakClient) *kc.KeycloakClient {
+	if state.Client == nil || !sameStringSet(cr.Spec.Client.WebOrigins, state.Client.WebOrigins) {
+		return cr
+	}
+	updated := cr.DeepCopy()
+	updated.Spec.Client.WebOrigins = state.Client.WebOrigins
+	return updated
+}
+
+// Keycloak keeps the attributes an update leaves out, so the token settings the
+// operator set last time and the CR doesn't manage any more are sent empty for as
+// long as the client still has them. Other lifespans are left alone
+func clientWithUnmanagedSettingsCleared(state *common.ClientState, cr *kc.KeycloakClient) *kc.KeycloakClient {
+	cleared := make(map[string]string)
+	for _, key := range cr.Status.ManagedTokenSettings {
+		if _, managed := cr.Spec.Client.Attributes[key]; !managed && state.Client.Attributes[key] != "" {
+			cleared[key] = ""
+		}
+	}
+	if len(cleared) == 0 {
+		return cr
+	}
+
+	updated := cr.DeepCopy()
+	if updated.Spec.Client.Attributes == nil {
+		updated.Spec.Client.Attributes = make(map[string]string)
+	}
+	for key, value := range cleared {
+		updated.Spec.Client.Attributes[key] = value
+	}
+	return updated
+}
+
+func sameStringSet(a, b []string) bool {
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	return reflect.DeepEqual(sortedA, sortedB)
+}
+
+func (i *KeycloakClientReconciler) getCreatedClientSecretState(state *common.ClientState, cr *kc.KeycloakClient, secret *v1.Secret) common.ClusterAction {
 	return common.GenericCreateAction{
-		Ref: model.ClientSecret(cr),
+		Ref: secret,
 		Msg: fmt.Sprintf("create client secret %v/%v", cr.Namespace, cr.Spec.Client.ClientID),
 	}
 }
@@ -175,6 +717,15 @@ func (i *KeycloakClientReconciler) getCreatedClientRoleState(state *common.Clien
 	}
 }
 
+func (i *KeycloakClientReconciler) getCreatedClientRolesState(state *common.ClientState, cr *kc.KeycloakClient, roles []kc.RoleRepresentation) common.ClusterAction {
+	return common.BulkClientRolesAction{
+		Roles: roles,
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("create %v client roles %v/%v", len(roles), cr.Namespace, cr.Spec.Client.ClientID),
+	}
+}
+
 func (i *KeycloakClientReconciler) getUpdatedClientRoleState(state *common.ClientState, cr *kc.KeycloakClient, role, oldRole *kc.RoleRepresentation) common.ClusterAction {
 	return common.UpdateClientRoleAction{
 		Role:    role,
@@ -193,3 +744,160 @@ func (i *KeycloakClientReconciler) getDeletedClientRoleState(state *common.Clien
 		Msg:   fmt.Sprintf("delete client role %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, role.Name),
 	}
 }
+
+func (i *KeycloakClientReconciler) getAddedScopeMappingsState(state *common.ClientState, cr *kc.KeycloakClient, mappings *kc.RoleRepresentationComposites) common.ClusterAction {
+	return common.AddScopeMappingAction{
+		Mappings: mappings,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("add scope mappings to client %v/%v", cr.Namespace, cr.Spec.Client.ClientID),
+	}
+}
+
+func (i *KeycloakClientReconciler) getRemovedScopeMappingsState(state *common.ClientState, cr *kc.KeycloakClient, mappings *kc.RoleRepresentationComposites) common.ClusterAction {
+	return common.RemoveScopeMappingAction{
+		Mappings: mappings,
+		Ref:      cr,
+		Realm:    state.Realm.Spec.Realm.Realm,
+		Msg:      fmt.Sprintf("remove scope mappings from client %v/%v", cr.Namespace, cr.Spec.Client.ClientID),
+	}
+}
+
+func (i *KeycloakClientReconciler) getAddedRealmDefaultRolesState(state *common.ClientState, cr *kc.KeycloakClient, roles []string) common.ClusterAction {
+	return common.AddRealmDefaultRolesAction{
+		Roles: roles,
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("add roles %v of client %v/%v to the default roles of realm %v", strings.Join(roles, ", "), cr.Namespace, cr.Spec.Client.ClientID, state.Realm.Spec.Realm.Realm),
+	}
+}
+
+func (i *KeycloakClientReconciler) getRemovedRealmDefaultRolesState(state *common.ClientState, cr *kc.KeycloakClient, roles []string) common.ClusterAction {
+	return common.RemoveRealmDefaultRolesAction{
+		Roles: roles,
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("remove roles %v of client %v/%v from the default roles of realm %v", strings.Join(roles, ", "), cr.Namespace, cr.Spec.Client.ClientID, state.Realm.Spec.Realm.Realm),
+	}
+}
+
+func (i *KeycloakClientReconciler) getAddedClientRoleCompositesState(state *common.ClientState, cr *kc.KeycloakClient, role *kc.RoleRepresentation, composites *kc.RoleRepresentationComposites) common.ClusterAction {
+	return common.AddRoleCompositesAction{
+		Role:       role,
+		Composites: composites,
+		Ref:        cr,
+		Realm:      state.Realm.Spec.Realm.Realm,
+		Msg:        fmt.Sprintf("add composites to client role %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, role.Name),
+	}
+}
+
+func (i *KeycloakClientReconciler) getRemovedClientRoleCompositesState(state *common.ClientState, cr *kc.KeycloakClient, role *kc.RoleRepresentation, composites *kc.RoleRepresentationComposites) common.ClusterAction {
+	return common.RemoveRoleCompositesAction{
+		Role:       role,
+		Composites: composites,
+		Ref:        cr,
+		Realm:      state.Realm.Spec.Realm.Realm,
+		Msg:        fmt.Sprintf("remove composites from client role %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, role.Name),
+	}
+}
+
+func (i *KeycloakClientReconciler) getAssignedServiceAccountRoleState(state *common.ClientState, cr *kc.KeycloakClient, role *kc.KeycloakUserRole, client *kc.KeycloakAPIClient) common.ClusterAction {
+	action := common.AssignServiceAccountRoleAction{
+		UserID: state.ServiceAccount.User.ID,
+		Ref:    role,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("assign realm role %v to service account of client %v/%v", role.Name, cr.Namespace, cr.Spec.Client.ClientID),
+	}
+	if client != nil {
+		action.ClientID = client.ID
+		action.Msg = fmt.Sprintf("assign role %v of client %v to service account of client %v/%v", role.Name, client.ClientID, cr.Namespace, cr.Spec.Client.ClientID)
+	}
+	return action
+}
+
+func (i *KeycloakClientReconciler) getRemovedServiceAccountRoleState(state *common.ClientState, cr *kc.KeycloakClient, role *kc.KeycloakUserRole, client *kc.KeycloakAPIClient) common.ClusterAction {
+	action := common.RemoveServiceAccountRoleAction{
+		UserID: state.ServiceAccount.User.ID,
+		Ref:    role,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("remove realm role %v from service account of client %v/%v", role.Name, cr.Namespace, cr.Spec.Client.ClientID),
+	}
+	if client != nil {
+		action.ClientID = client.ID
+		action.Msg = fmt.Sprintf("remove role %v of client %v from service account of client %v/%v", role.Name, client.ClientID, cr.Namespace, cr.Spec.Client.ClientID)
+	}
+	return action
+}
+
+func (i *KeycloakClientReconciler) getAddedDefaultClientScopeState(state *common.ClientState, cr *kc.KeycloakClient, clientScope *kc.KeycloakAPIClientScope) common.ClusterAction {
+	return common.AddDefaultClientScopeAction{
+		ClientScope: clientScope,
+		Ref:         cr,
+		Realm:       state.Realm.Spec.Realm.Realm,
+		Msg:         fmt.Sprintf("add default client scope %v to client %v/%v", clientScope.Name, cr.Namespace, cr.Spec.Client.ClientID),
+	}
+}
+
+func (i *KeycloakClientReconciler) getRemovedDefaultClientScopeState(state *common.ClientState, cr *kc.KeycloakClient, clientScope *kc.KeycloakAPIClientScope) common.ClusterAction {
+	return common.RemoveDefaultClientScopeAction{
+		ClientScope: clientScope,
+		Ref:         cr,
+		Realm:       state.Realm.Spec.Realm.Realm,
+		Msg:         fmt.Sprintf("remove default client scope %v from client %v/%v", clientScope.Name, cr.Namespace, cr.Spec.Client.ClientID),
+	}
+}
+
+func (i *KeycloakClientReconciler) getAddedOptionalClientScopeState(state *common.ClientState, cr *kc.KeycloakClient, clientScope *kc.KeycloakAPIClientScope) common.ClusterAction {
+	return common.AddOptionalClientScopeAction{
+		ClientScope: clientScope,
+		Ref:         cr,
+		Realm:       state.Realm.Spec.Realm.Realm,
+		Msg:         fmt.Sprintf("add optional client scope %v to client %v/%v", clientScope.Name, cr.Namespace, cr.Spec.Client.ClientID),
+	}
+}
+
+func (i *KeycloakClientReconciler) getRemovedOptionalClientScopeState(state *common.ClientState, cr *kc.KeycloakClient, clientScope *kc.KeycloakAPIClientScope) common.ClusterAction {
+	return common.RemoveOptionalClientScopeAction{
+		ClientScope: clientScope,
+		Ref:         cr,
+		Realm:       state.Realm.Spec.Realm.Realm,
+		Msg:         fmt.Sprintf("remove optional client scope %v from client %v/%v", clientScope.Name, cr.Namespace, cr.Spec.Client.ClientID),
+	}
+}
+
+func (i *KeycloakClientReconciler) getCreatedProtocolMapperState(state *common.ClientState, cr *kc.KeycloakClient, mapper *kc.KeycloakProtocolMapper) common.ClusterAction {
+	return common.CreateProtocolMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("create protocol mapper %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, mapper.Name),
+	}
+}
+
+func (i *KeycloakClientReconciler) getUpdatedProtocolMapperState(state *common.ClientState, cr *kc.KeycloakClient, mapper, oldMapper *kc.KeycloakProtocolMapper) common.ClusterAction {
+	return common.UpdateProtocolMapperAction{
+		Mapper:    mapper,
+		OldMapper: oldMapper,
+		Ref:       cr,
+		Realm:     state.Realm.Spec.Realm.Realm,
+		Msg:       fmt.Sprintf("update protocol mapper %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, oldMapper.Name),
+	}
+}
+
+func (i *KeycloakClientReconciler) getDeletedProtocolMapperState(state *common.ClientState, cr *kc.KeycloakClient, mapper *kc.KeycloakProtocolMapper) common.ClusterAction {
+	return common.DeleteProtocolMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("delete protocol mapper %v/%v/%v", cr.Namespace, cr.Spec.Client.ClientID, mapper.Name),
+	}
+}
+
+func (i *KeycloakClientReconciler) getUpdatedAuthorizationSettingsState(state *common.ClientState, cr *kc.KeycloakClient) common.ClusterAction {
+	return common.UpdateClientAuthorizationSettingsAction{
+		Current: state.AuthorizationSettings,
+		Ref:     cr,
+		Realm:   state.Realm.Spec.Realm.Realm,
+		Msg:     fmt.Sprintf("update authorization settings of client %v/%v", cr.Namespace, cr.Spec.Client.ClientID),
+	}
+}
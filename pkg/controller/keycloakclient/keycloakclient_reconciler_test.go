@@ -2,6 +2,7 @@ package keycloakclient
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -144,6 +145,77 @@ func TestKeycloakClientReconciler_Test_Delete_Client(t *testing.T) {
 	assert.IsType(t, common.DeleteClientAction{}, desiredState[1])
 }
 
+func TestKeycloakClientReconciler_Test_Delete_Client_Children_First(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:              "test",
+			Namespace:         "test",
+			DeletionTimestamp: &v13.Time{Time: time.Now()},
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID: "test",
+				Secret:   "test",
+			},
+		},
+	}
+
+	currentState := &common.ClientState{
+		Client: &v1alpha1.KeycloakAPIClient{
+			ProtocolMappers: []v1alpha1.KeycloakProtocolMapper{{ID: "mapperID", Name: "mapper"}},
+		},
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		Roles: []v1alpha1.RoleRepresentation{{ID: "roleID", Name: "role"}},
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.Len(t, desiredState, 5)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	assert.IsType(t, common.DeleteProtocolMapperAction{}, desiredState[1])
+	assert.IsType(t, common.DeleteClientRoleAction{}, desiredState[2])
+	assert.IsType(t, common.DeleteClientAction{}, desiredState[3])
+	assert.IsType(t, common.GenericDeleteAction{}, desiredState[4])
+}
+
+func TestKeycloakClientController_Test_Deletion_Grace_Period(t *testing.T) {
+	// given
+	now := time.Now()
+	cr := &v1alpha1.KeycloakClient{}
+
+	// then
+	// resources that aren't being deleted are never given up on
+	assert.False(t, isDeletionGracePeriodOver(cr, now))
+
+	// when keycloak has been unreachable since the deletion started
+	cr.DeletionTimestamp = &v13.Time{Time: now.Add(-time.Minute)}
+
+	// then
+	assert.False(t, isDeletionGracePeriodOver(cr, now))
+	assert.True(t, isDeletionGracePeriodOver(cr, now.Add(DefaultDeletionGracePeriod)))
+
+	// when the grace period is configured
+	cr.Spec.DeletionGracePeriod = &v13.Duration{Duration: 30 * time.Second}
+
+	// then
+	assert.True(t, isDeletionGracePeriodOver(cr, now))
+}
+
 func TestKeycloakClientReconciler_Test_Update_Client(t *testing.T) {
 	// given
 	keycloakCr := v1alpha1.Keycloak{}
@@ -254,30 +326,1398 @@ func TestKeycloakClientReconciler_Test_Marshal_Client(t *testing.T) {
 	assert.True(t, strings.Contains(s, "\"publicClient\":false"), "Element publicClient should not be omitted if false, as keycloaks default is true")
 }
 
-func TestKeycloakClientReconciler_Test_Role_DifferenceIntersection(t *testing.T) {
+func TestKeycloakClientReconciler_Test_Role_Composites(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID: "test",
+				Secret:   "test",
+			},
+			Roles: []v1alpha1.RoleRepresentation{
+				{ID: "existingID", Name: "existing", Description: "changed", Composites: &v1alpha1.RoleRepresentationComposites{
+					Realm:  []string{"kept", "added"},
+					Client: map[string][]string{"test": {"new"}},
+				}},
+				{Name: "new"},
+				{Name: "new_composite", Composites: &v1alpha1.RoleRepresentationComposites{
+					Client: map[string][]string{"test": {"new"}},
+				}},
+			},
+		},
+	}
+
+	currentState := &common.ClientState{
+		Client:       &v1alpha1.KeycloakAPIClient{},
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		Roles: []v1alpha1.RoleRepresentation{
+			{ID: "existingID", Name: "existing"},
+		},
+		RoleComposites: map[string]*v1alpha1.RoleRepresentationComposites{
+			"existing": {Realm: []string{"kept", "removed"}},
+		},
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.IsType(t, common.UpdateClientRoleAction{}, desiredState[3])
+	assert.IsType(t, common.CreateClientRoleAction{}, desiredState[4])
+	assert.IsType(t, common.CreateClientRoleAction{}, desiredState[5])
+
+	// composites are only reconciled after all roles have been created
+	assert.IsType(t, common.RemoveRoleCompositesAction{}, desiredState[6])
+	assert.Equal(t, "existing", desiredState[6].(common.RemoveRoleCompositesAction).Role.Name)
+	assert.Equal(t, &v1alpha1.RoleRepresentationComposites{Realm: []string{"removed"}}, desiredState[6].(common.RemoveRoleCompositesAction).Composites)
+	assert.IsType(t, common.AddRoleCompositesAction{}, desiredState[7])
+	assert.Equal(t, "existing", desiredState[7].(common.AddRoleCompositesAction).Role.Name)
+	assert.Equal(t, &v1alpha1.RoleRepresentationComposites{
+		Realm:  []string{"added"},
+		Client: map[string][]string{"test": {"new"}},
+	}, desiredState[7].(common.AddRoleCompositesAction).Composites)
+	assert.IsType(t, common.AddRoleCompositesAction{}, desiredState[8])
+	assert.Equal(t, "new_composite", desiredState[8].(common.AddRoleCompositesAction).Role.Name)
+	assert.Equal(t, 9, len(desiredState))
+}
+
+func TestKeycloakClientReconciler_Test_Bulk_Role_Create(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID: "test",
+				Secret:   "test",
+			},
+			Roles: []v1alpha1.RoleRepresentation{
+				{ID: "existingID", Name: "existing", Description: "changed"},
+			},
+		},
+	}
+	for index := 0; index < BulkRoleCreateThreshold; index++ {
+		cr.Spec.Roles = append(cr.Spec.Roles, v1alpha1.RoleRepresentation{Name: fmt.Sprintf("role%v", index)})
+	}
+
+	currentState := &common.ClientState{
+		Client:       &v1alpha1.KeycloakAPIClient{},
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		Roles: []v1alpha1.RoleRepresentation{
+			{ID: "existingID", Name: "existing"},
+		},
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	// up to the threshold, roles are created one by one
+	assert.IsType(t, common.UpdateClientRoleAction{}, desiredState[3])
+	assert.IsType(t, common.CreateClientRoleAction{}, desiredState[4])
+	assert.Equal(t, 4+BulkRoleCreateThreshold, len(desiredState))
+
+	// when
+	cr.Spec.Roles = append(cr.Spec.Roles, v1alpha1.RoleRepresentation{Name: "one_more"})
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	// the client exists already, so the roles are still created one by one
+	assert.IsType(t, common.UpdateClientRoleAction{}, desiredState[3])
+	assert.IsType(t, common.CreateClientRoleAction{}, desiredState[4])
+	assert.Equal(t, 5+BulkRoleCreateThreshold, len(desiredState))
+
+	// when the client doesn't exist yet
+	currentState.Client = nil
+	currentState.Roles = nil
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	// above the threshold, all of them are created at once, the existing role along
+	// with the others
+	assert.IsType(t, common.BulkClientRolesAction{}, desiredState[3])
+	assert.Len(t, desiredState[3].(common.BulkClientRolesAction).Roles, BulkRoleCreateThreshold+2)
+	assert.Equal(t, "", desiredState[3].(common.BulkClientRolesAction).Roles[0].ID)
+}
+
+func TestKeycloakClientReconciler_Test_Managed_Roles(t *testing.T) {
+	// given
+	before := []v1alpha1.RoleRepresentation{
+		{ID: "keptID", Name: "kept"},
+		{ID: "changedID", Name: "changed"},
+		{ID: "renamedID", Name: "old_name"},
+		{ID: "deletedID", Name: "deleted"},
+		{ID: "stuckID", Name: "stuck"},
+	}
+	desired := []v1alpha1.RoleRepresentation{
+		{Name: "kept"},
+		{Name: "changed", Description: "new"},
+		{ID: "renamedID", Name: "new_name"},
+		{Name: "created"},
+		{Name: "missing"},
+	}
+	after := []v1alpha1.RoleRepresentation{
+		{ID: "keptID", Name: "kept"},
+		{ID: "changedID", Name: "changed", Description: "new"},
+		{ID: "renamedID", Name: "new_name"},
+		{ID: "createdID", Name: "created"},
+		{ID: "stuckID", Name: "stuck"},
+	}
+
+	// when
+	managedRoles := GetManagedRoles(before, after, desired)
+
+	// then
+	assert.Equal(t, []v1alpha1.ManagedRole{
+		{Name: "kept", ID: "keptID", LastAction: v1alpha1.ManagedRoleUnchanged},
+		{Name: "changed", ID: "changedID", LastAction: v1alpha1.ManagedRoleUpdated},
+		{Name: "new_name", ID: "renamedID", LastAction: v1alpha1.ManagedRoleRenamed},
+		{Name: "created", ID: "createdID", LastAction: v1alpha1.ManagedRoleCreated},
+		{Name: "missing", LastAction: v1alpha1.ManagedRoleFailed},
+		{Name: "deleted", ID: "deletedID", LastAction: v1alpha1.ManagedRoleDeleted},
+		{Name: "stuck", ID: "stuckID", LastAction: v1alpha1.ManagedRoleFailed},
+	}, managedRoles)
+}
+
+func TestKeycloakClientReconciler_Test_ServiceAccount_Roles(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID:               "test",
+				Secret:                 "test",
+				ServiceAccountsEnabled: &[]bool{true}[0],
+			},
+			ServiceAccountRealmRoles: []string{"kept", "added"},
+			ServiceAccountClientRoles: map[string][]string{
+				"other": {"added"},
+			},
+		},
+	}
+
+	serviceAccount := common.NewUserState(keycloakCr)
+	serviceAccount.User = &v1alpha1.KeycloakAPIUser{ID: "serviceAccountID"}
+	serviceAccount.RealmRoles = []*v1alpha1.KeycloakUserRole{{ID: "keptID", Name: "kept"}, {ID: "removedID", Name: "removed"}}
+	serviceAccount.AvailableRealmRoles = []*v1alpha1.KeycloakUserRole{{ID: "addedID", Name: "added"}}
+	serviceAccount.Clients = []*v1alpha1.KeycloakAPIClient{{ID: "otherID", ClientID: "other"}, {ID: "ignoredID", ClientID: "ignored"}}
+	serviceAccount.ClientRoles["other"] = []*v1alpha1.KeycloakUserRole{{ID: "otherRemovedID", Name: "removed"}}
+	serviceAccount.AvailableClientRoles["other"] = []*v1alpha1.KeycloakUserRole{{ID: "otherAddedID", Name: "added"}}
+	serviceAccount.ClientRoles["ignored"] = []*v1alpha1.KeycloakUserRole{{ID: "ignoredRoleID", Name: "ignored"}}
+
+	currentState := &common.ClientState{
+		Client:       &v1alpha1.KeycloakAPIClient{},
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		ServiceAccount: serviceAccount,
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.IsType(t, common.AssignServiceAccountRoleAction{}, desiredState[3])
+	assert.Equal(t, "addedID", desiredState[3].(common.AssignServiceAccountRoleAction).Ref.ID)
+	assert.Equal(t, "serviceAccountID", desiredState[3].(common.AssignServiceAccountRoleAction).UserID)
+	assert.Equal(t, "", desiredState[3].(common.AssignServiceAccountRoleAction).ClientID)
+	assert.IsType(t, common.RemoveServiceAccountRoleAction{}, desiredState[4])
+	assert.Equal(t, "removedID", desiredState[4].(common.RemoveServiceAccountRoleAction).Ref.ID)
+	assert.IsType(t, common.AssignServiceAccountRoleAction{}, desiredState[5])
+	assert.Equal(t, "otherAddedID", desiredState[5].(common.AssignServiceAccountRoleAction).Ref.ID)
+	assert.Equal(t, "otherID", desiredState[5].(common.AssignServiceAccountRoleAction).ClientID)
+	assert.IsType(t, common.RemoveServiceAccountRoleAction{}, desiredState[6])
+	assert.Equal(t, "otherRemovedID", desiredState[6].(common.RemoveServiceAccountRoleAction).Ref.ID)
+	assert.Equal(t, 7, len(desiredState))
+	assert.Empty(t, reconciler.MissingServiceAccountRoles(currentState, cr))
+
+	// when
+	cr.Spec.ServiceAccountRealmRoles = append(cr.Spec.ServiceAccountRealmRoles, "unknown")
+	cr.Spec.ServiceAccountClientRoles["other"] = append(cr.Spec.ServiceAccountClientRoles["other"], "unknown")
+	cr.Spec.ServiceAccountClientRoles["unknown"] = []string{"role"}
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	// missing roles are skipped and reported
+	assert.Equal(t, 7, len(desiredState))
+	assert.Equal(t, []string{"other/unknown", "unknown", "unknown/role"}, reconciler.MissingServiceAccountRoles(currentState, cr))
+}
+
+func TestKeycloakClientReconciler_Test_ServiceAccount_Roles_Disabled(t *testing.T) {
 	// given
-	a := []v1alpha1.RoleRepresentation{
-		{Name: "a"},
-		{ID: "ignored", Name: "b"},
-		{ID: "cID", Name: "c"},
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID: "test",
+				Secret:   "test",
+			},
+			ServiceAccountRealmRoles: []string{"added"},
+		},
 	}
-	b := []v1alpha1.RoleRepresentation{
-		{Name: "b"},
-		{ID: "cID", Name: "differentName"},
-		{Name: "d"},
+
+	serviceAccount := common.NewUserState(keycloakCr)
+	serviceAccount.User = &v1alpha1.KeycloakAPIUser{ID: "serviceAccountID"}
+	serviceAccount.AvailableRealmRoles = []*v1alpha1.KeycloakUserRole{{ID: "addedID", Name: "added"}}
+
+	currentState := &common.ClientState{
+		Client:       &v1alpha1.KeycloakAPIClient{},
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		ServiceAccount: serviceAccount,
 	}
 
 	// when
-	difference, intersection := roleDifferenceIntersection(a, b)
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
 
 	// then
-	expectedDifference := []v1alpha1.RoleRepresentation{
-		{Name: "a"},
+	assert.Equal(t, 3, len(desiredState))
+}
+
+func TestKeycloakClientReconciler_Test_Scope_Mappings(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	fullScopeAllowed := false
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID:         "test",
+				Secret:           "test",
+				FullScopeAllowed: &fullScopeAllowed,
+			},
+			ScopeMappings: &v1alpha1.RoleRepresentationComposites{
+				Realm:  []string{"kept", "added"},
+				Client: map[string][]string{"other": {"added"}},
+			},
+		},
+	}
+
+	currentState := &common.ClientState{
+		Client:       &v1alpha1.KeycloakAPIClient{},
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		ScopeMappings: &v1alpha1.RoleRepresentationComposites{
+			Realm:  []string{"kept", "removed"},
+			Client: map[string][]string{"other": {"removed"}},
+		},
 	}
-	expectedIntersection := []v1alpha1.RoleRepresentation{
-		{ID: "ignored", Name: "b"},
-		{ID: "cID", Name: "c"},
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - update client
+	// 2 - update client secret
+	// 3 - remove scope mappings
+	// 4 - add scope mappings
+	assert.Len(t, desiredState, 5)
+	assert.Equal(t, &v1alpha1.RoleRepresentationComposites{
+		Realm:  []string{"removed"},
+		Client: map[string][]string{"other": {"removed"}},
+	}, desiredState[3].(common.RemoveScopeMappingAction).Mappings)
+	assert.Equal(t, &v1alpha1.RoleRepresentationComposites{
+		Realm:  []string{"added"},
+		Client: map[string][]string{"other": {"added"}},
+	}, desiredState[4].(common.AddScopeMappingAction).Mappings)
+
+	// when the full scope is allowed
+	cr.Spec.Client.FullScopeAllowed = nil
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.Len(t, desiredState, 3)
+}
+
+func TestKeycloakClientReconciler_Test_Default_Roles(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID: "test",
+				Secret:   "test",
+			},
+			Roles: []v1alpha1.RoleRepresentation{
+				{ID: "kept", Name: "kept"},
+				{ID: "detached", Name: "detached"},
+				{Name: "added"},
+			},
+			DefaultRoles: []string{"kept", "added"},
+		},
+	}
+
+	currentState := &common.ClientState{
+		Client:       &v1alpha1.KeycloakAPIClient{},
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		Roles: []v1alpha1.RoleRepresentation{
+			{ID: "kept", Name: "kept"},
+			{ID: "detached", Name: "detached"},
+			{ID: "deleted", Name: "deleted"},
+		},
+		DefaultRoles: []string{"kept", "detached", "deleted"},
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - update client
+	// 2 - update client secret
+	// 3 - delete role
+	// 4 - create role
+	// 5 - remove default roles
+	// 6 - add default roles
+	// the deleted role leaves the default roles along with the role itself
+	assert.Len(t, desiredState, 7)
+	assert.Equal(t, []string{"detached"}, desiredState[5].(common.RemoveRealmDefaultRolesAction).Roles)
+	assert.Equal(t, []string{"added"}, desiredState[6].(common.AddRealmDefaultRolesAction).Roles)
+
+	// when the default roles aren't managed
+	cr.Spec.DefaultRoles = nil
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.Len(t, desiredState, 5)
+}
+
+func TestKeycloakClientReconciler_Test_Role_Attributes(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID: "test",
+				Secret:   "test",
+			},
+			Roles: []v1alpha1.RoleRepresentation{
+				{Name: "changed", Attributes: map[string][]string{"tier": {"gold"}}},
+				{Name: "unchanged", Attributes: map[string][]string{"tier": {"silver"}}},
+				{Name: "unmanaged"},
+			},
+		},
+	}
+
+	currentState := &common.ClientState{
+		Client:       &v1alpha1.KeycloakAPIClient{},
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		Roles: []v1alpha1.RoleRepresentation{
+			{ID: "changedID", Name: "changed", Attributes: map[string][]string{"tier": {"silver"}}},
+			{ID: "unchangedID", Name: "unchanged", Attributes: map[string][]string{"tier": {"silver"}}},
+			{ID: "unmanagedID", Name: "unmanaged", Attributes: map[string][]string{"tier": {"bronze"}}},
+		},
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	// only the role whose attribute changed is updated, roles without attributes in
+	// the CR keep the ones they have
+	assert.Len(t, desiredState, 4)
+	assert.IsType(t, common.UpdateClientRoleAction{}, desiredState[3])
+	assert.Equal(t, "changed", desiredState[3].(common.UpdateClientRoleAction).Role.Name)
+	assert.Equal(t, map[string][]string{"tier": {"gold"}}, desiredState[3].(common.UpdateClientRoleAction).Role.Attributes)
+}
+
+func TestKeycloakClientReconciler_Test_Client_Scopes(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID:             "test",
+				Secret:               "test",
+				DefaultClientScopes:  []string{"profile", "email"},
+				OptionalClientScopes: []string{"phone"},
+			},
+		},
+	}
+
+	currentState := &common.ClientState{
+		Client:       &v1alpha1.KeycloakAPIClient{},
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		DefaultClientScopes: []v1alpha1.KeycloakAPIClientScope{
+			{ID: "profileID", Name: "profile"},
+			{ID: "phoneID", Name: "phone"},
+		},
+		OptionalClientScopes: []v1alpha1.KeycloakAPIClientScope{
+			{ID: "addressID", Name: "address"},
+		},
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - update client
+	// 2 - update client secret
+	// 3 - remove default scope phone
+	// 4 - remove optional scope address
+	// 5 - add default scope email
+	// 6 - add optional scope phone
+	assert.Equal(t, 7, len(desiredState))
+	assert.IsType(t, common.RemoveDefaultClientScopeAction{}, desiredState[3])
+	assert.Equal(t, "phoneID", desiredState[3].(common.RemoveDefaultClientScopeAction).ClientScope.ID)
+	assert.IsType(t, common.RemoveOptionalClientScopeAction{}, desiredState[4])
+	assert.Equal(t, "addressID", desiredState[4].(common.RemoveOptionalClientScopeAction).ClientScope.ID)
+	assert.IsType(t, common.AddDefaultClientScopeAction{}, desiredState[5])
+	assert.Equal(t, "email", desiredState[5].(common.AddDefaultClientScopeAction).ClientScope.Name)
+	assert.IsType(t, common.AddOptionalClientScopeAction{}, desiredState[6])
+	assert.Equal(t, "phone", desiredState[6].(common.AddOptionalClientScopeAction).ClientScope.Name)
+}
+
+func TestKeycloakClientReconciler_Test_Protocol_Mappers(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID: "test",
+				Secret:   "test",
+				ProtocolMappers: []v1alpha1.KeycloakProtocolMapper{
+					{ID: "renameID", Name: "rename_new"},
+					{Name: "update", Config: map[string]string{"claim.name": "updated"}},
+					{Name: "rename"},
+					{Name: "new"},
+				},
+			},
+		},
+	}
+
+	currentState := &common.ClientState{
+		Client: &v1alpha1.KeycloakAPIClient{
+			ProtocolMappers: []v1alpha1.KeycloakProtocolMapper{
+				{ID: "deleteID", Name: "delete"},
+				{ID: "updateID", Name: "update"},
+				{ID: "renameID", Name: "rename"},
+			},
+		},
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.IsType(t, common.DeleteProtocolMapperAction{}, desiredState[3])
+	assert.Equal(t, "deleteID", desiredState[3].(common.DeleteProtocolMapperAction).Mapper.ID)
+	assert.IsType(t, common.UpdateProtocolMapperAction{}, desiredState[4])
+	assert.Equal(t, "rename_new", desiredState[4].(common.UpdateProtocolMapperAction).Mapper.Name)
+	assert.Equal(t, "rename", desiredState[4].(common.UpdateProtocolMapperAction).OldMapper.Name)
+	assert.IsType(t, common.UpdateProtocolMapperAction{}, desiredState[5])
+	assert.Equal(t, "updated", desiredState[5].(common.UpdateProtocolMapperAction).Mapper.Config["claim.name"])
+	assert.Equal(t, "updateID", desiredState[5].(common.UpdateProtocolMapperAction).OldMapper.ID)
+	assert.IsType(t, common.CreateProtocolMapperAction{}, desiredState[6])
+	assert.Equal(t, "rename", desiredState[6].(common.CreateProtocolMapperAction).Mapper.Name)
+	assert.IsType(t, common.CreateProtocolMapperAction{}, desiredState[7])
+	assert.Equal(t, "new", desiredState[7].(common.CreateProtocolMapperAction).Mapper.Name)
+	assert.Equal(t, 8, len(desiredState))
+}
+
+func TestKeycloakClientReconciler_Test_Secret_Rotation(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID: "test",
+				Secret:   "test",
+			},
+			SecretRotationPeriod: &v13.Duration{Duration: 24 * time.Hour},
+		},
+	}
+
+	currentState := &common.ClientState{
+		Client: &v1alpha1.KeycloakAPIClient{},
+		ClientSecret: &v1.Secret{
+			ObjectMeta: v13.ObjectMeta{
+				Annotations: map[string]string{
+					model.ClientSecretLastRotationAnnotation: time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339),
+				},
+			},
+		},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.Equal(t, 3, len(desiredState))
+	assert.IsType(t, common.RegenerateClientSecretAction{}, desiredState[2])
+	assert.Equal(t, currentState.ClientSecret, desiredState[2].(common.RegenerateClientSecretAction).Secret)
+
+	// when the secret was rotated recently
+	currentState.ClientSecret.Annotations[model.ClientSecretLastRotationAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.IsType(t, common.GenericUpdateAction{}, desiredState[2])
+
+	// when the client is public
+	currentState.ClientSecret.Annotations[model.ClientSecretLastRotationAnnotation] = time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	cr.Spec.Client.PublicClient = true
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	// public clients have no secret to rotate
+	assert.IsType(t, common.GenericDeleteAction{}, desiredState[2])
+}
+
+func TestKeycloakClientReconciler_Test_Registration_Access_Token(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID: "test",
+				Secret:   "test",
+			},
+			SecretRotationPeriod:    &v13.Duration{Duration: 24 * time.Hour},
+			RegistrationAccessToken: true,
+		},
+	}
+
+	currentState := &common.ClientState{
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	// the token of a new client is issued once the client and its Secret exist
+	assert.IsType(t, common.CreateClientAction{}, desiredState[1])
+	assert.IsType(t, common.GenericCreateAction{}, desiredState[2])
+	assert.IsType(t, common.RegenerateRegistrationAccessTokenAction{}, desiredState[3])
+	// it is written to the Secret just created, which the cache may not hold yet
+	assert.Same(t, desiredState[2].(common.GenericCreateAction).Ref, desiredState[3].(common.RegenerateRegistrationAccessTokenAction).Secret)
+
+	// when the Secret holds a token
+	currentState.Client = &v1alpha1.KeycloakAPIClient{}
+	currentState.ClientSecret = &v1.Secret{
+		ObjectMeta: v13.ObjectMeta{
+			Annotations: map[string]string{
+				model.ClientSecretLastRotationAnnotation: time.Now().UTC().Format(time.RFC3339),
+			},
+		},
+		Data: map[string][]byte{
+			model.ClientSecretRegistrationTokenProperty: []byte("token"),
+		},
+	}
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	// it is kept by the routine update of the Secret
+	assert.IsType(t, common.GenericUpdateAction{}, desiredState[2])
+	secret := desiredState[2].(common.GenericUpdateAction).Ref.(*v1.Secret)
+	assert.Equal(t, []byte("token"), secret.Data[model.ClientSecretRegistrationTokenProperty])
+	assert.Len(t, desiredState, 3)
+
+	// when the client secret is regenerated
+	currentState.ClientSecret.Annotations[model.ClientSecretLastRotationAnnotation] = time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.IsType(t, common.RegenerateClientSecretAction{}, desiredState[2])
+	assert.IsType(t, common.RegenerateRegistrationAccessTokenAction{}, desiredState[3])
+	assert.Same(t, desiredState[2].(common.RegenerateClientSecretAction).Secret, desiredState[3].(common.RegenerateRegistrationAccessTokenAction).Secret)
+
+	// when the token isn't wanted anymore
+	cr.Spec.RegistrationAccessToken = false
+	cr.Spec.SecretRotationPeriod = nil
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	secret = desiredState[2].(common.GenericUpdateAction).Ref.(*v1.Secret)
+	assert.NotContains(t, secret.Data, model.ClientSecretRegistrationTokenProperty)
+	assert.Len(t, desiredState, 3)
+}
+
+func TestKeycloakClientReconciler_Test_Secret_Ref(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID: "test",
+				Secret:   "referenced",
+			},
+			SecretRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "vault-secret"},
+				Key:                  "secret",
+			},
+			SecretRotationPeriod: &v13.Duration{Duration: time.Hour},
+		},
+	}
+
+	currentState := &common.ClientState{
+		Client:               &v1alpha1.KeycloakAPIClient{},
+		ClientSecret:         &v1.Secret{},
+		ClientSecretDiverged: true,
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	// secrets managed elsewhere are never rotated
+	assert.Equal(t, 4, len(desiredState))
+	assert.IsType(t, common.UpdateClientAction{}, desiredState[1])
+	assert.IsType(t, common.UpdateClientSecretAction{}, desiredState[2])
+	assert.IsType(t, common.GenericUpdateAction{}, desiredState[3])
+	assert.Equal(t, []byte("referenced"), desiredState[3].(common.GenericUpdateAction).Ref.(*v1.Secret).Data[model.ClientSecretClientSecretProperty])
+
+	// when the secret in keycloak is up to date
+	currentState.ClientSecretDiverged = false
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.Equal(t, 3, len(desiredState))
+}
+
+func TestKeycloakClientReconciler_Test_Authorization_Settings(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID:                     "test",
+				Secret:                       "test",
+				AuthorizationServicesEnabled: &[]bool{true}[0],
+				AuthorizationSettings: &v1alpha1.ResourceServerRepresentation{
+					PolicyEnforcementMode: "ENFORCING",
+					Resources:             []v1alpha1.ResourceRepresentation{{Name: "resource"}},
+				},
+			},
+		},
+	}
+
+	currentSettings := &v1alpha1.ResourceServerRepresentation{
+		Resources: []v1alpha1.ResourceRepresentation{{ID: "defaultID", Name: "Default Resource"}},
+	}
+	currentState := &common.ClientState{
+		Client:       &v1alpha1.KeycloakAPIClient{AuthorizationServicesEnabled: &[]bool{true}[0]},
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		AuthorizationSettings: currentSettings,
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.Equal(t, 4, len(desiredState))
+	assert.IsType(t, common.UpdateClientAuthorizationSettingsAction{}, desiredState[3])
+	assert.Equal(t, currentSettings, desiredState[3].(common.UpdateClientAuthorizationSettingsAction).Current)
+
+	// when authorization services are disabled
+	cr.Spec.Client.AuthorizationServicesEnabled = &[]bool{false}[0]
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.Equal(t, 3, len(desiredState))
+}
+
+func TestKeycloakClientReconciler_Test_Client_Deleted_In_Keycloak(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ID:       "oldClientID",
+				ClientID: "test",
+				Secret:   "test",
+			},
+			Roles: []v1alpha1.RoleRepresentation{
+				{ID: "oldRoleID", Name: "role"},
+				{Name: "other"},
+			},
+		},
+		Status: v1alpha1.KeycloakClientStatus{
+			SecondaryResources: map[string][]string{"Secret": {"keycloak-client-secret-test"}},
+		},
+	}
+
+	// the client is gone, but the state still knows about its old roles
+	currentState := &common.ClientState{
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+		Roles: []v1alpha1.RoleRepresentation{
+			{ID: "oldRoleID", Name: "role"},
+			{ID: "otherRoleID", Name: "other"},
+		},
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - create client
+	// 2 - update client secret
+	// 3 - create role
+	// 4 - create other
+	assert.Equal(t, 5, len(desiredState))
+	assert.IsType(t, common.CreateClientAction{}, desiredState[1])
+	assert.IsType(t, common.CreateClientRoleAction{}, desiredState[3])
+	assert.Equal(t, "role", desiredState[3].(common.CreateClientRoleAction).Role.Name)
+	assert.Equal(t, "", desiredState[3].(common.CreateClientRoleAction).Role.ID)
+	assert.IsType(t, common.CreateClientRoleAction{}, desiredState[4])
+	assert.Equal(t, "other", desiredState[4].(common.CreateClientRoleAction).Role.Name)
+}
+
+func TestKeycloakClientReconciler_Test_Public_Client_Secret(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			RealmSelector: &v13.LabelSelector{
+				MatchLabels: map[string]string{"application": "sso"},
+			},
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID: "test",
+				Secret:   "test",
+			},
+		},
+	}
+
+	currentState := &common.ClientState{
+		Client:       &v1alpha1.KeycloakAPIClient{},
+		ClientSecret: model.ClientSecret(cr),
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+	}
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+
+	// when
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.Equal(t, 3, len(desiredState))
+	assert.IsType(t, common.GenericUpdateAction{}, desiredState[2])
+
+	// when the client is flipped to public
+	cr.Spec.Client.PublicClient = true
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	// the orphaned secret is deleted
+	assert.Equal(t, 3, len(desiredState))
+	assert.IsType(t, common.GenericDeleteAction{}, desiredState[2])
+	assert.Equal(t, currentState.ClientSecret, desiredState[2].(common.GenericDeleteAction).Ref)
+
+	// when the secret is gone
+	currentState.ClientSecret = nil
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	// no new secret is created
+	assert.Equal(t, 2, len(desiredState))
+
+	// when the client is bearer-only
+	cr.Spec.Client.PublicClient = false
+	cr.Spec.Client.BearerOnly = &[]bool{true}[0]
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.Equal(t, 2, len(desiredState))
+}
+
+func TestKeycloakClientReconciler_Test_Web_Origins_Order(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID:     "test",
+				PublicClient: true,
+				WebOrigins:   []string{"+", "https://example.com"},
+			},
+		},
+	}
+
+	currentState := &common.ClientState{
+		Client: &v1alpha1.KeycloakAPIClient{
+			ClientID:   "test",
+			WebOrigins: []string{"https://example.com", "+"},
+		},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	// the origins are sent in the order of keycloak, the CR is left as it is
+	update := desiredState[1].(common.UpdateClientAction)
+	assert.Equal(t, []string{"https://example.com", "+"}, update.Ref.Spec.Client.WebOrigins)
+	assert.Equal(t, []string{"+", "https://example.com"}, cr.Spec.Client.WebOrigins)
+
+	// when an origin changed
+	cr.Spec.Client.WebOrigins = []string{"+", "https://app.example.com"}
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	update = desiredState[1].(common.UpdateClientAction)
+	assert.Equal(t, cr, update.Ref)
+}
+
+func TestKeycloakClientReconciler_Test_Unmanaged_Token_Settings(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID:     "test",
+				PublicClient: true,
+				Attributes: map[string]string{
+					"client.session.max.lifespan": "7200",
+				},
+			},
+		},
+		Status: v1alpha1.KeycloakClientStatus{
+			ManagedTokenSettings: []string{"access.token.lifespan", "client.session.max.lifespan"},
+		},
+	}
+
+	// as keycloak still has the lifespans the token settings of the CR had set
+	currentState := &common.ClientState{
+		Client: &v1alpha1.KeycloakAPIClient{
+			ClientID:     "test",
+			PublicClient: true,
+			Attributes: map[string]string{
+				"access.token.lifespan":       "300",
+				"client.session.max.lifespan": "7200",
+				"client.session.idle.timeout": "600",
+			},
+		},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	// the lifespan left to the realm is cleared, the one the attributes set is kept,
+	// and the one the operator never set is left alone
+	update := desiredState[1].(common.UpdateClientAction)
+	assert.Equal(t, "", update.Ref.Spec.Client.Attributes["access.token.lifespan"])
+	assert.Equal(t, "7200", update.Ref.Spec.Client.Attributes["client.session.max.lifespan"])
+	assert.NotContains(t, update.Ref.Spec.Client.Attributes, "client.session.idle.timeout")
+	assert.NotContains(t, cr.Spec.Client.Attributes, "access.token.lifespan")
+
+	// when keycloak cleared it
+	delete(currentState.Client.Attributes, "access.token.lifespan")
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	for _, action := range desiredState {
+		_, updated := action.(common.UpdateClientAction)
+		assert.False(t, updated)
+	}
+}
+
+func TestKeycloakClientReconciler_Test_Unmanaged_Logout_Settings(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID:     "test",
+				PublicClient: true,
+			},
+		},
+	}
+
+	// as the logout settings were set by hand in keycloak
+	currentState := &common.ClientState{
+		Client: &v1alpha1.KeycloakAPIClient{
+			ClientID:     "test",
+			PublicClient: true,
+			Attributes: map[string]string{
+				"frontchannel.logout.url":                  "https://app.example.com/logout",
+				"backchannel.logout.url":                   "https://app.example.com/backchannel",
+				"backchannel.logout.session.required":      "true",
+				"backchannel.logout.revoke.offline.tokens": "true",
+			},
+		},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	// they are left alone without logout in the CR
+	for _, action := range desiredState {
+		if update, ok := action.(common.UpdateClientAction); ok {
+			assert.Nil(t, update.Ref.Spec.Client.Attributes)
+		}
+	}
+}
+
+func TestKeycloakClientReconciler_Test_Unmanaged_Flow_Overrides(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID:     "test",
+				PublicClient: true,
+			},
+		},
+	}
+
+	// as the override was set by hand in keycloak, e.g. for a second factor
+	currentState := &common.ClientState{
+		Client: &v1alpha1.KeycloakAPIClient{
+			ClientID:     "test",
+			PublicClient: true,
+			AuthenticationFlowBindingOverrides: map[string]string{
+				"browser":      "flowID",
+				"direct_grant": "",
+			},
+		},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	// it is left alone without authFlowOverrides in the CR
+	for _, action := range desiredState {
+		if update, ok := action.(common.UpdateClientAction); ok {
+			assert.Nil(t, update.Ref.Spec.Client.AuthenticationFlowBindingOverrides)
+		}
+	}
+}
+
+func TestKeycloakClientReconciler_Test_Client_Up_To_Date(t *testing.T) {
+	// given
+	keycloakCr := v1alpha1.Keycloak{}
+	enabled := true
+	cr := &v1alpha1.KeycloakClient{
+		ObjectMeta: v13.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.KeycloakClientSpec{
+			Client: &v1alpha1.KeycloakAPIClient{
+				ID:                  "testID",
+				ClientID:            "test",
+				Secret:              "test",
+				StandardFlowEnabled: true,
+				RedirectUris:        []string{"https://example.com/*", "https://app.example.com/*"},
+				WebOrigins:          []string{"+", "https://example.com"},
+				Attributes: map[string]string{
+					"pkce.code.challenge.method": "S256",
+					"post.logout.redirect.uris":  "",
+				},
+			},
+		},
+	}
+
+	// as keycloak returns the client, with its own defaults filled in
+	currentState := &common.ClientState{
+		Client: &v1alpha1.KeycloakAPIClient{
+			ID:                        "testID",
+			ClientID:                  "test",
+			Secret:                    "**********",
+			Enabled:                   &enabled,
+			ClientAuthenticatorType:   "client-secret",
+			Protocol:                  "openid-connect",
+			StandardFlowEnabled:       true,
+			RedirectUris:              []string{"https://app.example.com/*", "https://example.com/*"},
+			WebOrigins:                []string{"https://example.com", "+"},
+			NodeReRegistrationTimeout: -1,
+			Attributes: map[string]string{
+				"pkce.code.challenge.method":          "S256",
+				"backchannel.logout.session.required": "true",
+			},
+			Access: map[string]bool{"view": true, "configure": true, "manage": true},
+		},
+		ClientSecret: &v1.Secret{},
+		Realm: &v1alpha1.KeycloakRealm{
+			Spec: v1alpha1.KeycloakRealmSpec{
+				Realm: &v1alpha1.KeycloakAPIRealm{
+					Realm: "test",
+				},
+			},
+		},
+	}
+
+	// when
+	reconciler := NewKeycloakClientReconciler(keycloakCr)
+	desiredState := reconciler.Reconcile(currentState, cr)
+
+	// then
+	// neither the defaults of keycloak nor the order of the lists count as a change
+	for _, action := range desiredState {
+		_, updated := action.(common.UpdateClientAction)
+		assert.False(t, updated)
+	}
+
+	// when an attribute changed
+	cr.Spec.Client.Attributes["pkce.code.challenge.method"] = "plain"
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.IsType(t, common.UpdateClientAction{}, desiredState[1])
+
+	// when a flow is disabled
+	cr.Spec.Client.Attributes["pkce.code.challenge.method"] = "S256"
+	cr.Spec.Client.StandardFlowEnabled = false
+	desiredState = reconciler.Reconcile(currentState, cr)
+
+	// then
+	assert.IsType(t, common.UpdateClientAction{}, desiredState[1])
+}
+
+func TestKeycloakClientReconciler_Test_Client_Flags(t *testing.T) {
+	flags := []struct {
+		name string
+		set  func(client *v1alpha1.KeycloakAPIClient, value bool)
+		// Optional flags are left out of the update when the CR doesn't set them
+		unset func(client *v1alpha1.KeycloakAPIClient)
+	}{
+		{"surrogateAuthRequired", func(client *v1alpha1.KeycloakAPIClient, value bool) { client.SurrogateAuthRequired = &value }, func(client *v1alpha1.KeycloakAPIClient) { client.SurrogateAuthRequired = nil }},
+		{"enabled", func(client *v1alpha1.KeycloakAPIClient, value bool) { client.Enabled = &value }, func(client *v1alpha1.KeycloakAPIClient) { client.Enabled = nil }},
+		{"bearerOnly", func(client *v1alpha1.KeycloakAPIClient, value bool) { client.BearerOnly = &value }, func(client *v1alpha1.KeycloakAPIClient) { client.BearerOnly = nil }},
+		{"consentRequired", func(client *v1alpha1.KeycloakAPIClient, value bool) { client.ConsentRequired = value }, nil},
+		{"standardFlowEnabled", func(client *v1alpha1.KeycloakAPIClient, value bool) { client.StandardFlowEnabled = value }, nil},
+		{"implicitFlowEnabled", func(client *v1alpha1.KeycloakAPIClient, value bool) { client.ImplicitFlowEnabled = value }, nil},
+		{"directAccessGrantsEnabled", func(client *v1alpha1.KeycloakAPIClient, value bool) { client.DirectAccessGrantsEnabled = value }, nil},
+		{"serviceAccountsEnabled", func(client *v1alpha1.KeycloakAPIClient, value bool) { client.ServiceAccountsEnabled = &value }, func(client *v1alpha1.KeycloakAPIClient) { client.ServiceAccountsEnabled = nil }},
+		{"publicClient", func(client *v1alpha1.KeycloakAPIClient, value bool) { client.PublicClient = value }, nil},
+		{"frontchannelLogout", func(client *v1alpha1.KeycloakAPIClient, value bool) { client.FrontchannelLogout = &value }, func(client *v1alpha1.KeycloakAPIClient) { client.FrontchannelLogout = nil }},
+		{"authorizationServicesEnabled", func(client *v1alpha1.KeycloakAPIClient, value bool) { client.AuthorizationServicesEnabled = &value }, func(client *v1alpha1.KeycloakAPIClient) { client.AuthorizationServicesEnabled = nil }},
+	}
+
+	for _, flag := range flags {
+		t.Run(flag.name, func(t *testing.T) {
+			// given
+			cr := &v1alpha1.KeycloakClient{
+				ObjectMeta: v13.ObjectMeta{
+					Name:      "test",
+					Namespace: "test",
+				},
+				Spec: v1alpha1.KeycloakClientSpec{
+					Client: &v1alpha1.KeycloakAPIClient{
+						ID:       "testID",
+						ClientID: "test",
+						Secret:   "test",
+					},
+				},
+			}
+			currentState := &common.ClientState{
+				Client: cr.Spec.Client.DeepCopy(),
+				Realm: &v1alpha1.KeycloakRealm{
+					Spec: v1alpha1.KeycloakRealmSpec{
+						Realm: &v1alpha1.KeycloakAPIRealm{
+							Realm: "test",
+						},
+					},
+				},
+			}
+			flag.set(currentState.Client, true)
+			flag.set(cr.Spec.Client, false)
+
+			// when
+			reconciler := NewKeycloakClientReconciler(v1alpha1.Keycloak{})
+			desiredState := reconciler.Reconcile(currentState, cr)
+
+			// then
+			// turning the flag off is both noticed and sent to keycloak
+			assert.IsType(t, common.UpdateClientAction{}, desiredState[1])
+			update, err := json.Marshal(desiredState[1].(common.UpdateClientAction).Ref.Spec.Client)
+			assert.NoError(t, err)
+			assert.Contains(t, string(update), fmt.Sprintf(`"%v":false`, flag.name))
+
+			// when
+			flag.set(cr.Spec.Client, true)
+			desiredState = reconciler.Reconcile(currentState, cr)
+
+			// then
+			for _, action := range desiredState {
+				_, updated := action.(common.UpdateClientAction)
+				assert.False(t, updated)
+			}
+
+			if flag.unset == nil {
+				return
+			}
+
+			// when
+			flag.unset(cr.Spec.Client)
+			cr.Spec.Client.RootURL = "https://changed.example.com"
+			desiredState = reconciler.Reconcile(currentState, cr)
+
+			// then
+			// an unset flag is not sent, keycloak keeps its value
+			assert.IsType(t, common.UpdateClientAction{}, desiredState[1])
+			update, err = json.Marshal(desiredState[1].(common.UpdateClientAction).Ref.Spec.Client)
+			assert.NoError(t, err)
+			assert.NotContains(t, string(update), fmt.Sprintf(`"%v"`, flag.name))
+		})
 	}
-	assert.Equal(t, expectedDifference, difference)
-	assert.Equal(t, expectedIntersection, intersection)
 }
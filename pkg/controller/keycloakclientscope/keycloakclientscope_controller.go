@@ -0,0 +1,249 @@
+package keycloakclientscope
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/common"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_keycloakclientscope")
+
+const (
+	ClientScopeFinalizer = "clientscope.cleanup"
+	RequeueDelayError    = 5 * time.Second
+	// Upper bound of the backoff while keycloak is unavailable
+	RequeueDelayTransientMax = 5 * time.Minute
+	ControllerName           = "keycloakclientscope-controller"
+)
+
+// Add creates a new KeycloakClientScope Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, _ chan schema.GroupVersionKind) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &ReconcileKeycloakClientScope{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		cancel:   cancel,
+		context:  ctx,
+		recorder: mgr.GetEventRecorderFor(ControllerName),
+		backoff:  common.NewBackoff(RequeueDelayError, RequeueDelayTransientMax),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(ControllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource KeycloakClientScope
+	return c.Watch(&source.Kind{Type: &kc.KeycloakClientScope{}}, &handler.EnqueueRequestForObject{})
+}
+
+// blank assignment to verify that ReconcileKeycloakClientScope implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileKeycloakClientScope{}
+
+// ReconcileKeycloakClientScope reconciles a KeycloakClientScope object
+type ReconcileKeycloakClientScope struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client   client.Client
+	scheme   *runtime.Scheme
+	context  context.Context
+	cancel   context.CancelFunc
+	recorder record.EventRecorder
+	backoff  *common.Backoff
+}
+
+// Reconcile reads that state of the cluster for a KeycloakClientScope object and makes changes based on the state read
+// and what is in the KeycloakClientScope.Spec
+func (r *ReconcileKeycloakClientScope) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling KeycloakClientScope")
+	defer common.ObserveReconcileDuration("KeycloakClientScope", time.Now())
+
+	// Fetch the KeycloakClientScope instance
+	instance := &kc.KeycloakClientScope{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			common.ForgetPendingActions(instance, request.Namespace, request.Name)
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// The scope may be shared by clients of multiple keycloak instances,
+	// process all of them
+	realms, err := common.GetMatchingRealms(r.context, r.client, instance.Spec.RealmSelector)
+	if err != nil {
+		return r.ManageError(instance, err)
+	}
+	log.Info(fmt.Sprintf("found %v matching realm(s) for client scope %v/%v", len(realms.Items), instance.Namespace, instance.Name))
+	// Instances in dry run mode only log the actions for this resource
+	dryRun := false
+	dryRunActions := 0
+	for _, realm := range realms.Items {
+		keycloaks, err := common.GetRealmKeycloaks(r.context, r.client, &realm)
+		if err != nil {
+			return r.ManageError(instance, err)
+		}
+		log.Info(fmt.Sprintf("found %v matching keycloak(s) for realm %v/%v", len(keycloaks.Items), realm.Namespace, realm.Name))
+
+		for _, keycloak := range keycloaks.Items {
+			// Get an authenticated keycloak api client for the instance
+			keycloakFactory := common.LocalConfigKeycloakFactory{}
+			authenticated, err := keycloakFactory.AuthenticatedRealmClient(keycloak, realm)
+			if err != nil {
+				return r.ManageError(instance, err)
+			}
+
+			// Compute the current state of the client scope
+			log.Info(fmt.Sprintf("got authenticated client for keycloak at %v", authenticated.Endpoint()))
+			clientScopeState := common.NewClientScopeState(r.context, realm.DeepCopy())
+
+			log.Info(fmt.Sprintf("read client scope state for keycloak %v/%v, realm %v/%v, client scope %v/%v",
+				keycloak.Namespace,
+				keycloak.Name,
+				realm.Namespace,
+				realm.Name,
+				instance.Namespace,
+				instance.Name))
+
+			err = clientScopeState.Read(instance, authenticated)
+			if err != nil {
+				return r.ManageError(instance, err)
+			}
+
+			// Figure out the actions to keep the client scope up to date with
+			// the desired state
+			reconciler := NewKeycloakClientScopeReconciler(keycloak)
+			desiredState := reconciler.Reconcile(clientScopeState, instance)
+			actionRunner := common.NewClusterAndKeycloakActionRunner(r.context, r.client, r.scheme, instance, authenticated, r.recorder)
+			if keycloak.Spec.DryRun {
+				actionRunner = common.NewDryRunActionRunner(r.context, r.client, r.scheme, instance)
+				dryRun = true
+				dryRunActions += len(desiredState)
+			}
+
+			// Run all actions to keep the client scope updated
+			err = actionRunner.RunAll(desiredState)
+			if err != nil {
+				return r.ManageError(instance, err)
+			}
+		}
+	}
+
+	if dryRun {
+		return reconcile.Result{Requeue: false}, common.ManageDryRun(r.context, r.client, instance, &instance.Status.Message, dryRunActions)
+	}
+
+	return reconcile.Result{Requeue: false}, r.manageSuccess(instance, instance.DeletionTimestamp != nil)
+}
+
+// Only the message is reported, the phase and the finalizer are left alone as
+// nothing was changed
+func (r *ReconcileKeycloakClientScope) manageSuccess(clientScope *kc.KeycloakClientScope, deleted bool) error {
+	r.backoff.Reset(clientScope)
+	clientScope.Status.Ready = true
+	clientScope.Status.Message = ""
+	clientScope.Status.Phase = kc.PhaseReconciling
+
+	err := r.client.Status().Update(r.context, clientScope)
+	if err != nil {
+		log.Error(err, "unable to update status")
+	}
+
+	// Finalizer already set?
+	finalizerExists := false
+	for _, finalizer := range clientScope.Finalizers {
+		if finalizer == ClientScopeFinalizer {
+			finalizerExists = true
+			break
+		}
+	}
+
+	// Resource created and finalizer exists: nothing to do
+	if !deleted && finalizerExists {
+		return nil
+	}
+
+	// Resource created and finalizer does not exist: add finalizer
+	if !deleted && !finalizerExists {
+		clientScope.Finalizers = append(clientScope.Finalizers, ClientScopeFinalizer)
+		log.Info(fmt.Sprintf("added finalizer to keycloak client scope %v/%v",
+			clientScope.Namespace,
+			clientScope.Spec.ClientScope.Name))
+
+		return r.client.Update(r.context, clientScope)
+	}
+
+	// Otherwise remove the finalizer
+	newFinalizers := []string{}
+	for _, finalizer := range clientScope.Finalizers {
+		if finalizer == ClientScopeFinalizer {
+			log.Info(fmt.Sprintf("removed finalizer from keycloak client scope %v/%v",
+				clientScope.Namespace,
+				clientScope.Spec.ClientScope.Name))
+
+			continue
+		}
+		newFinalizers = append(newFinalizers, finalizer)
+	}
+
+	clientScope.Finalizers = newFinalizers
+	return r.client.Update(r.context, clientScope)
+}
+
+func (r *ReconcileKeycloakClientScope) ManageError(clientScope *kc.KeycloakClientScope, issue error) (reconcile.Result, error) {
+	// Keycloak is expected to be unavailable for a while during rollouts
+	if common.IsTransientError(issue) {
+		return common.ManageTransientError(r.context, r.client, r.backoff, clientScope, issue, func(message string) {
+			clientScope.Status.Message = message
+			clientScope.Status.Ready = false
+		})
+	}
+
+	r.recorder.Event(clientScope, "Warning", "ProcessingError", issue.Error())
+
+	clientScope.Status.Message = issue.Error()
+	clientScope.Status.Ready = false
+	clientScope.Status.Phase = kc.PhaseFailing
+
+	err := r.client.Status().Update(r.context, clientScope)
+	if err != nil {
+		log.Error(err, "unable to update status")
+	}
+
+	return reconcile.Result{
+		RequeueAfter: RequeueDelayError,
+		Requeue:      true,
+	}, nil
+}
@@ -0,0 +1,139 @@
+package keycloakclientscope
+
+import (
+	"fmt"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/common"
+)
+
+type Reconciler interface {
+	Reconcile(cr *kc.KeycloakClientScope) error
+}
+
+type KeycloakClientScopeReconciler struct { // nolint
+	Keycloak kc.Keycloak
+}
+
+func NewKeycloakClientScopeReconciler(keycloak kc.Keycloak) *KeycloakClientScopeReconciler {
+	return &KeycloakClientScopeReconciler{
+		Keycloak: keycloak,
+	}
+}
+
+func (i *KeycloakClientScopeReconciler) Reconcile(state *common.ClientScopeState, cr *kc.KeycloakClientScope) common.DesiredClusterState {
+	desired := common.DesiredClusterState{}
+
+	desired.AddAction(i.pingKeycloak())
+	if cr.DeletionTimestamp != nil {
+		// The scope may have been removed in keycloak already
+		if state.ClientScope != nil {
+			desired.AddAction(i.getDeletedClientScopeState(state, cr))
+		}
+		return desired
+	}
+
+	// New scopes are created along with their protocol mappers
+	if state.ClientScope == nil {
+		desired.AddAction(i.getCreatedClientScopeState(state, cr))
+		return desired
+	}
+
+	if !clientScopeMatches(cr.Spec.ClientScope, state.ClientScope) {
+		desired.AddAction(i.getUpdatedClientScopeState(state, cr))
+	}
+	i.ReconcileProtocolMappers(state, cr, &desired)
+
+	return desired
+}
+
+// Protocol mappers are only managed when listed in the CR, so that mappers added
+// in the admin console are not wiped
+func (i *KeycloakClientScopeReconciler) ReconcileProtocolMappers(state *common.ClientScopeState, cr *kc.KeycloakClientScope, desired *common.DesiredClusterState) {
+	if cr.Spec.ClientScope.ProtocolMappers == nil {
+		return
+	}
+
+	diff := common.DiffProtocolMappers(state.ClientScope.ProtocolMappers, cr.Spec.ClientScope.ProtocolMappers)
+	for _, mapper := range diff.Deleted {
+		desired.AddAction(i.getDeletedProtocolMapperState(state, cr, mapper.DeepCopy()))
+	}
+	for _, update := range diff.Updated {
+		desired.AddAction(i.getUpdatedProtocolMapperState(state, cr, update.Mapper.DeepCopy(), update.OldMapper.DeepCopy()))
+	}
+	for _, mapper := range diff.Created {
+		desired.AddAction(i.getCreatedProtocolMapperState(state, cr, mapper.DeepCopy()))
+	}
+}
+
+// Keycloak keeps attributes missing from an update, so only the ones set in
+// the CR are compared
+func clientScopeMatches(desired, existing *kc.KeycloakAPIClientScope) bool {
+	if desired.Description != existing.Description || desired.Protocol != existing.Protocol {
+		return false
+	}
+	for key, value := range desired.Attributes {
+		if current, ok := existing.Attributes[key]; !ok || current != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (i *KeycloakClientScopeReconciler) pingKeycloak() common.ClusterAction {
+	return common.PingAction{
+		Msg: "check if keycloak is available",
+	}
+}
+
+func (i *KeycloakClientScopeReconciler) getCreatedClientScopeState(state *common.ClientScopeState, cr *kc.KeycloakClientScope) common.ClusterAction {
+	return common.CreateClientScopeAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("create client scope %v/%v", cr.Namespace, cr.Spec.ClientScope.Name),
+	}
+}
+
+func (i *KeycloakClientScopeReconciler) getUpdatedClientScopeState(state *common.ClientScopeState, cr *kc.KeycloakClientScope) common.ClusterAction {
+	return common.UpdateClientScopeAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("update client scope %v/%v", cr.Namespace, cr.Spec.ClientScope.Name),
+	}
+}
+
+func (i *KeycloakClientScopeReconciler) getDeletedClientScopeState(state *common.ClientScopeState, cr *kc.KeycloakClientScope) common.ClusterAction {
+	return common.DeleteClientScopeAction{
+		Ref:   cr,
+		Realm: state.Realm.Spec.Realm.Realm,
+		Msg:   fmt.Sprintf("removing client scope %v/%v", cr.Namespace, cr.Spec.ClientScope.Name),
+	}
+}
+
+func (i *KeycloakClientScopeReconciler) getCreatedProtocolMapperState(state *common.ClientScopeState, cr *kc.KeycloakClientScope, mapper *kc.KeycloakProtocolMapper) common.ClusterAction {
+	return common.CreateClientScopeProtocolMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("create protocol mapper %v/%v/%v", cr.Namespace, cr.Spec.ClientScope.Name, mapper.Name),
+	}
+}
+
+func (i *KeycloakClientScopeReconciler) getUpdatedProtocolMapperState(state *common.ClientScopeState, cr *kc.KeycloakClientScope, mapper, oldMapper *kc.KeycloakProtocolMapper) common.ClusterAction {
+	return common.UpdateClientScopeProtocolMapperAction{
+		Mapper:    mapper,
+		OldMapper: oldMapper,
+		Ref:       cr,
+		Realm:     state.Realm.Spec.Realm.Realm,
+		Msg:       fmt.Sprintf("update protocol mapper %v/%v/%v", cr.Namespace, cr.Spec.ClientScope.Name, oldMapper.Name),
+	}
+}
+
+func (i *KeycloakClientScopeReconciler) getDeletedProtocolMapperState(state *common.ClientScopeState, cr *kc.KeycloakClientScope, mapper *kc.KeycloakProtocolMapper) common.ClusterAction {
+	return common.DeleteClientScopeProtocolMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Realm:  state.Realm.Spec.Realm.Realm,
+		Msg:    fmt.Sprintf("delete protocol mapper %v/%v/%v", cr.Namespace, cr.Spec.ClientScope.Name, mapper.Name),
+	}
+}
@@ -0,0 +1,131 @@
+package keycloakclientscope
+
+import (
+	"context"
+	"testing"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/common"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getDummyClientScope() *v1alpha1.KeycloakClientScope {
+	return &v1alpha1.KeycloakClientScope{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "dummy",
+			Namespace: "dummy",
+		},
+		Spec: v1alpha1.KeycloakClientScopeSpec{
+			RealmSelector: &v1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": "keycloak",
+				},
+			},
+			ClientScope: &v1alpha1.KeycloakAPIClientScope{
+				Name:        "dummy",
+				Description: "dummy",
+				Protocol:    "openid-connect",
+				Attributes: map[string]string{
+					"include.in.token.scope": "true",
+				},
+				ProtocolMappers: []v1alpha1.KeycloakProtocolMapper{
+					{Name: "audience", Protocol: "openid-connect", ProtocolMapper: "oidc-audience-mapper"},
+				},
+			},
+		},
+	}
+}
+
+func getDummyState() *common.ClientScopeState {
+	return common.NewClientScopeState(context.Background(), &v1alpha1.KeycloakRealm{
+		Spec: v1alpha1.KeycloakRealmSpec{
+			Realm: &v1alpha1.KeycloakAPIRealm{
+				ID:    "dummy",
+				Realm: "dummy",
+			},
+		},
+	})
+}
+
+func TestKeycloakClientScopeReconciler_Test_Creating_Client_Scope(t *testing.T) {
+	// given
+	reconciler := NewKeycloakClientScopeReconciler(v1alpha1.Keycloak{})
+	cr := getDummyClientScope()
+
+	// when
+	desiredState := reconciler.Reconcile(getDummyState(), cr)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - create client scope, including its protocol mappers
+	assert.Len(t, desiredState, 2)
+	assert.IsType(t, common.PingAction{}, desiredState[0])
+	assert.IsType(t, common.CreateClientScopeAction{}, desiredState[1])
+}
+
+func TestKeycloakClientScopeReconciler_Test_Updating_Client_Scope(t *testing.T) {
+	// given
+	reconciler := NewKeycloakClientScopeReconciler(v1alpha1.Keycloak{})
+	cr := getDummyClientScope()
+	state := getDummyState()
+	state.ClientScope = cr.Spec.ClientScope.DeepCopy()
+	state.ClientScope.ID = "dummyID"
+	state.ClientScope.Attributes["display.on.consent.screen"] = "true"
+	state.ClientScope.ProtocolMappers = []v1alpha1.KeycloakProtocolMapper{
+		{ID: "audienceID", Name: "audience", Protocol: "openid-connect", ProtocolMapper: "oidc-audience-mapper"},
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, cr)
+
+	// then
+	// attributes not set in the CR are ignored, so nothing changes
+	assert.Len(t, desiredState, 1)
+
+	// when the scope and its mappers change
+	cr.Spec.ClientScope.Description = "changed"
+	cr.Spec.ClientScope.ProtocolMappers = []v1alpha1.KeycloakProtocolMapper{
+		{Name: "groups", Protocol: "openid-connect", ProtocolMapper: "oidc-group-membership-mapper"},
+	}
+	desiredState = reconciler.Reconcile(state, cr)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - update client scope
+	// 2 - delete audience mapper
+	// 3 - create groups mapper
+	assert.Len(t, desiredState, 4)
+	assert.IsType(t, common.UpdateClientScopeAction{}, desiredState[1])
+	assert.Equal(t, "audienceID", desiredState[2].(common.DeleteClientScopeProtocolMapperAction).Mapper.ID)
+	assert.Equal(t, "groups", desiredState[3].(common.CreateClientScopeProtocolMapperAction).Mapper.Name)
+
+	// when the mappers are not managed
+	cr.Spec.ClientScope.ProtocolMappers = nil
+	desiredState = reconciler.Reconcile(state, cr)
+
+	// then
+	assert.Len(t, desiredState, 2)
+}
+
+func TestKeycloakClientScopeReconciler_Test_Deleting_Client_Scope(t *testing.T) {
+	// given
+	reconciler := NewKeycloakClientScopeReconciler(v1alpha1.Keycloak{})
+	cr := getDummyClientScope()
+	cr.DeletionTimestamp = &v1.Time{}
+	state := getDummyState()
+
+	// when the scope is already gone
+	desiredState := reconciler.Reconcile(state, cr)
+
+	// then
+	assert.Len(t, desiredState, 1)
+
+	// when the scope exists
+	state.ClientScope = &v1alpha1.KeycloakAPIClientScope{ID: "dummyID", Name: "dummy"}
+	desiredState = reconciler.Reconcile(state, cr)
+
+	// then
+	assert.Len(t, desiredState, 2)
+	assert.IsType(t, common.DeleteClientScopeAction{}, desiredState[1])
+}
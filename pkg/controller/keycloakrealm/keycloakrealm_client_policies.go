@@ -0,0 +1,109 @@
+package keycloakrealm
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/common"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Client profiles and policies are each pushed as a whole, whenever they differ from
+// the ones of the realm. Profiles come first, as policies refer to them
+func (i *KeycloakRealmReconciler) ReconcileClientPolicies(state *common.RealmState, cr *kc.KeycloakRealm, desired *common.DesiredClusterState) {
+	if state.Realm == nil || cr.DeletionTimestamp != nil {
+		return
+	}
+
+	if cr.Spec.ClientProfiles != nil && !sameClientProfiles(cr.Spec.ClientProfiles, state.ClientProfiles) {
+		desired.AddAction(&common.UpdateClientProfilesAction{
+			Profiles: cr.Spec.ClientProfiles,
+			Ref:      cr,
+			Msg:      fmt.Sprintf("update client profiles of realm %v/%v", cr.Namespace, cr.Spec.Realm.Realm),
+		})
+	}
+
+	if cr.Spec.ClientPolicies != nil && !sameClientPolicies(cr.Spec.ClientPolicies, state.ClientPolicies) {
+		desired.AddAction(&common.UpdateClientPoliciesAction{
+			Policies: cr.Spec.ClientPolicies,
+			Ref:      cr,
+			Msg:      fmt.Sprintf("update client policies of realm %v/%v", cr.Namespace, cr.Spec.Realm.Realm),
+		})
+	}
+}
+
+func sameClientProfiles(desired, current []kc.KeycloakAPIClientProfile) bool {
+	if len(desired) != len(current) {
+		return false
+	}
+	for i := range desired {
+		if desired[i].Name != current[i].Name || desired[i].Description != current[i].Description {
+			return false
+		}
+		if len(desired[i].Executors) != len(current[i].Executors) {
+			return false
+		}
+		for j, executor := range desired[i].Executors {
+			if executor.Executor != current[i].Executors[j].Executor ||
+				!sameClientPolicyConfiguration(executor.Configuration, current[i].Executors[j].Configuration) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Policies the CR doesn't enable or disable keep what keycloak has
+func sameClientPolicies(desired, current []kc.KeycloakAPIClientPolicy) bool {
+	if len(desired) != len(current) {
+		return false
+	}
+	for i := range desired {
+		if desired[i].Name != current[i].Name || desired[i].Description != current[i].Description {
+			return false
+		}
+		if desired[i].Enabled != nil && *desired[i].Enabled != (current[i].Enabled != nil && *current[i].Enabled) {
+			return false
+		}
+		if len(desired[i].Profiles) != len(current[i].Profiles) {
+			return false
+		}
+		for j, profile := range desired[i].Profiles {
+			if profile != current[i].Profiles[j] {
+				return false
+			}
+		}
+		if len(desired[i].Conditions) != len(current[i].Conditions) {
+			return false
+		}
+		for j, condition := range desired[i].Conditions {
+			if condition.Condition != current[i].Conditions[j].Condition ||
+				!sameClientPolicyConfiguration(condition.Configuration, current[i].Conditions[j].Configuration) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Configurations are compared decoded, keycloak doesn't keep the order of the keys
+func sameClientPolicyConfiguration(desired, current *runtime.RawExtension) bool {
+	return reflect.DeepEqual(decodeClientPolicyConfiguration(desired), decodeClientPolicyConfiguration(current))
+}
+
+func decodeClientPolicyConfiguration(configuration *runtime.RawExtension) interface{} {
+	decoded := map[string]interface{}{}
+	if configuration == nil || len(configuration.Raw) == 0 {
+		return decoded
+	}
+	var value interface{}
+	if err := json.Unmarshal(configuration.Raw, &value); err != nil {
+		return string(configuration.Raw)
+	}
+	if value == nil {
+		return decoded
+	}
+	return value
+}
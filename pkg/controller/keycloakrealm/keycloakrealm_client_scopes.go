@@ -0,0 +1,86 @@
+package keycloakrealm
+
+import (
+	"fmt"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/common"
+)
+
+// Default and optional client scopes of the realm are diffed by ID. Removals come
+// first, as keycloak doesn't add a scope as optional while it is a default one
+func (i *KeycloakRealmReconciler) ReconcileDefaultClientScopes(state *common.RealmState, cr *kc.KeycloakRealm, desired *common.DesiredClusterState) {
+	var added []common.ClusterAction
+	if cr.Spec.DefaultClientScopes != nil {
+		added = append(added, i.reconcileRealmDefaultClientScopes(state, cr, cr.Spec.DefaultClientScopes, state.DefaultClientScopes, false, desired)...)
+	}
+	if cr.Spec.OptionalClientScopes != nil {
+		added = append(added, i.reconcileRealmDefaultClientScopes(state, cr, cr.Spec.OptionalClientScopes, state.OptionalClientScopes, true, desired)...)
+	}
+	for _, action := range added {
+		desired.AddAction(action)
+	}
+}
+
+// Adds the removals to the desired state and returns the additions
+func (i *KeycloakRealmReconciler) reconcileRealmDefaultClientScopes(state *common.RealmState, cr *kc.KeycloakRealm, names []string, current []kc.KeycloakAPIClientScope, optional bool, desired *common.DesiredClusterState) []common.ClusterAction {
+	currentIDs := make(map[string]bool)
+	for _, scope := range current {
+		currentIDs[scope.ID] = true
+	}
+
+	wanted := make(map[string]bool)
+	var added []common.ClusterAction
+	for _, name := range names {
+		scope := findClientScopeByName(state.ClientScopes, name)
+		if scope == nil {
+			added = append(added, i.getAddedRealmDefaultClientScopeState(cr, &kc.KeycloakAPIClientScope{Name: name}, optional))
+			continue
+		}
+		wanted[scope.ID] = true
+		if !currentIDs[scope.ID] {
+			added = append(added, i.getAddedRealmDefaultClientScopeState(cr, scope, optional))
+		}
+	}
+
+	for index := range current {
+		if !wanted[current[index].ID] {
+			desired.AddAction(i.getRemovedRealmDefaultClientScopeState(cr, &current[index], optional))
+		}
+	}
+	return added
+}
+
+func findClientScopeByName(scopes []kc.KeycloakAPIClientScope, name string) *kc.KeycloakAPIClientScope {
+	for index := range scopes {
+		if scopes[index].Name == name {
+			return &scopes[index]
+		}
+	}
+	return nil
+}
+
+func realmDefaultClientScopeKind(optional bool) string {
+	if optional {
+		return "optional"
+	}
+	return "default"
+}
+
+func (i *KeycloakRealmReconciler) getAddedRealmDefaultClientScopeState(cr *kc.KeycloakRealm, scope *kc.KeycloakAPIClientScope, optional bool) common.ClusterAction {
+	return &common.AddRealmDefaultClientScopeAction{
+		Scope:    scope,
+		Optional: optional,
+		Ref:      cr,
+		Msg:      fmt.Sprintf("add %v client scope %v to realm %v/%v", realmDefaultClientScopeKind(optional), scope.Name, cr.Namespace, cr.Spec.Realm.Realm),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getRemovedRealmDefaultClientScopeState(cr *kc.KeycloakRealm, scope *kc.KeycloakAPIClientScope, optional bool) common.ClusterAction {
+	return &common.RemoveRealmDefaultClientScopeAction{
+		Scope:    scope,
+		Optional: optional,
+		Ref:      cr,
+		Msg:      fmt.Sprintf("remove %v client scope %v from realm %v/%v", realmDefaultClientScopeKind(optional), scope.Name, cr.Namespace, cr.Spec.Realm.Realm),
+	}
+}
@@ -10,10 +10,12 @@ import (
 	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	"github.com/keycloak/keycloak-operator/pkg/common"
+	"github.com/keycloak/keycloak-operator/pkg/model"
 	corev1 "k8s.io/api/core/v1"
 	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -27,7 +29,9 @@ import (
 const (
 	RealmFinalizer    = "realm.cleanup"
 	RequeueDelayError = 5 * time.Second
-	ControllerName    = "controller_keycloakrealm"
+	// Upper bound of the backoff while keycloak is unavailable
+	RequeueDelayTransientMax = 5 * time.Minute
+	ControllerName           = "controller_keycloakrealm"
 )
 
 var log = logf.Log.WithName(ControllerName)
@@ -54,6 +58,7 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 		cancel:   cancel,
 		context:  ctx,
 		recorder: mgr.GetEventRecorderFor(ControllerName),
+		backoff:  common.NewBackoff(RequeueDelayError, RequeueDelayTransientMax),
 	}
 }
 
@@ -80,9 +85,62 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Also watch secrets referenced by the realm config, these are not owned by the realm
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+			return referencingRealms(mgr.GetClient(), a.Meta.GetNamespace(), a.Meta.GetName())
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// Returns requests for all realms in the namespace whose config references the given secret
+func referencingRealms(c client.Client, namespace, name string) []reconcile.Request {
+	realms := &kc.KeycloakRealmList{}
+	err := c.List(context.TODO(), realms, client.InNamespace(namespace))
+	if err != nil {
+		log.Error(err, "unable to list realms referencing secret")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, item := range realms.Items {
+		if referencesSecret(&item, name) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: item.Namespace, Name: item.Name},
+			})
+		}
+	}
+	return requests
+}
+
+func referencesSecret(realm *kc.KeycloakRealm, name string) bool {
+	for _, ref := range realm.Spec.SMTPServerSecrets {
+		if ref.Name == name {
+			return true
+		}
+	}
+	for _, provider := range realm.Spec.Realm.UserFederationProviders {
+		for _, ref := range provider.ConfigSecrets {
+			if ref.Name == name {
+				return true
+			}
+		}
+	}
+	for _, provider := range realm.Spec.Realm.IdentityProviders {
+		for _, ref := range provider.ConfigSecrets {
+			if ref.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // blank assignment to verify that ReconcileKeycloakRealm implements reconcile.Reconciler
 var _ reconcile.Reconciler = &ReconcileKeycloakRealm{}
 
@@ -95,6 +153,7 @@ type ReconcileKeycloakRealm struct {
 	context  context.Context
 	cancel   context.CancelFunc
 	recorder record.EventRecorder
+	backoff  *common.Backoff
 }
 
 // Reconcile reads that state of the cluster for a KeycloakRealm object and makes changes based on the state read
@@ -102,6 +161,7 @@ type ReconcileKeycloakRealm struct {
 func (r *ReconcileKeycloakRealm) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling KeycloakRealm")
+	defer common.ObserveReconcileDuration("KeycloakRealm", time.Now())
 
 	// Fetch the KeycloakRealm instance
 	instance := &kc.KeycloakRealm{}
@@ -111,6 +171,7 @@ func (r *ReconcileKeycloakRealm) Reconcile(request reconcile.Request) (reconcile
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
+			common.ForgetPendingActions(instance, request.Namespace, request.Name)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -128,20 +189,45 @@ func (r *ReconcileKeycloakRealm) Reconcile(request reconcile.Request) (reconcile
 		return reconcile.Result{Requeue: false}, nil
 	}
 
-	keycloaks, err := common.GetMatchingKeycloaks(r.context, r.client, instance.Spec.InstanceSelector)
+	// Keycloak rejects the whole realm update on an invalid policy, with a less helpful error
+	err = common.ValidatePasswordPolicy(instance.Spec.Realm.PasswordPolicy)
+	if err != nil {
+		return r.ManageError(instance, err)
+	}
+
+	err = common.ValidateSslRequired(instance.Spec.Realm.SslRequired)
+	if err != nil {
+		return r.ManageError(instance, err)
+	}
+
+	err = common.ValidateWebAuthnPolicy("webAuthnPolicy", instance.Spec.WebAuthnPolicy)
+	if err != nil {
+		return r.ManageError(instance, err)
+	}
+
+	err = common.ValidateWebAuthnPolicy("webAuthnPasswordlessPolicy", instance.Spec.WebAuthnPasswordlessPolicy)
+	if err != nil {
+		return r.ManageError(instance, err)
+	}
+
+	keycloaks, err := common.GetRealmKeycloaks(r.context, r.client, instance)
 	if err != nil {
 		return r.ManageError(instance, err)
 	}
 
 	log.Info(fmt.Sprintf("found %v matching keycloak(s) for realm %v/%v", len(keycloaks.Items), instance.Namespace, instance.Name))
 
+	// Instances in dry run mode only log the actions for this resource
+	dryRun := false
+	dryRunActions := 0
 	// The realm may be applicable to multiple keycloak instances,
 	// process all of them
 	for _, keycloak := range keycloaks.Items {
 		// Get an authenticated keycloak api client for the instance
 		keycloakFactory := common.LocalConfigKeycloakFactory{}
 
-		if keycloak.Spec.Unmanaged {
+		// External instances are unmanaged as well, but still have their realms managed
+		if keycloak.Spec.Unmanaged && !model.IsExternalKeycloak(&keycloak) {
 			return r.ManageError(instance, errors.Errorf("realms cannot be created for unmanaged keycloak instances"))
 		}
 
@@ -166,11 +252,21 @@ func (r *ReconcileKeycloakRealm) Reconcile(request reconcile.Request) (reconcile
 			return r.ManageError(instance, err)
 		}
 
+		// Keycloak would fall back to its default theme without telling
+		for _, message := range common.UnavailableRealmThemes(instance.Spec.Themes, realmState.AvailableThemes) {
+			r.recorder.Event(instance, "Warning", "ThemeNotAvailable", message)
+		}
+
 		// Figure out the actions to keep the realms up to date with
 		// the desired state
 		reconciler := NewKeycloakRealmReconciler(keycloak)
 		desiredState := reconciler.Reconcile(realmState, instance)
-		actionRunner := common.NewClusterAndKeycloakActionRunner(r.context, r.client, r.scheme, instance, authenticated)
+		actionRunner := common.NewClusterAndKeycloakActionRunner(r.context, r.client, r.scheme, instance, authenticated, r.recorder)
+		if keycloak.Spec.DryRun {
+			actionRunner = common.NewDryRunActionRunner(r.context, r.client, r.scheme, instance)
+			dryRun = true
+			dryRunActions += len(desiredState)
+		}
 
 		// Run all actions to keep the realms updated
 		err = actionRunner.RunAll(desiredState)
@@ -179,10 +275,18 @@ func (r *ReconcileKeycloakRealm) Reconcile(request reconcile.Request) (reconcile
 		}
 	}
 
-	return reconcile.Result{Requeue: false}, r.manageSuccess(instance, instance.DeletionTimestamp != nil)
+	if dryRun {
+		return reconcile.Result{Requeue: false}, common.ManageDryRun(r.context, r.client, instance, &instance.Status.Message, dryRunActions)
+	}
+
+	return common.ResyncResult(instance.Spec.ResyncPeriod), r.manageSuccess(instance, instance.DeletionTimestamp != nil)
 }
 
+// Only the message is reported, the phase and the finalizer are left alone as
+// nothing was changed
 func (r *ReconcileKeycloakRealm) manageSuccess(realm *kc.KeycloakRealm, deleted bool) error {
+	r.backoff.Reset(realm)
+	common.SetSuccessConditions(&realm.Status.Conditions, realm.Generation)
 	realm.Status.Ready = true
 	realm.Status.Message = ""
 	realm.Status.Phase = v1alpha1.PhaseReconciling
@@ -234,11 +338,21 @@ func (r *ReconcileKeycloakRealm) manageSuccess(realm *kc.KeycloakRealm, deleted
 }
 
 func (r *ReconcileKeycloakRealm) ManageError(realm *kc.KeycloakRealm, issue error) (reconcile.Result, error) {
+	// Keycloak is expected to be unavailable for a while during rollouts
+	if common.IsTransientError(issue) {
+		return common.ManageTransientError(r.context, r.client, r.backoff, realm, issue, func(message string) {
+			realm.Status.Message = message
+			common.SetTransientErrorConditions(&realm.Status.Conditions, realm.Generation, issue)
+			realm.Status.Ready = false
+		})
+	}
+
 	r.recorder.Event(realm, "Warning", "ProcessingError", issue.Error())
 
 	realm.Status.Message = issue.Error()
 	realm.Status.Ready = false
 	realm.Status.Phase = v1alpha1.PhaseFailing
+	common.SetErrorConditions(&realm.Status.Conditions, realm.Generation, issue)
 
 	err := r.client.Status().Update(r.context, realm)
 	if err != nil {
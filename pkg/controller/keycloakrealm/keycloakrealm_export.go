@@ -0,0 +1,30 @@
+package keycloakrealm
+
+import (
+	"fmt"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/common"
+	"github.com/keycloak/keycloak-operator/pkg/model"
+)
+
+// An export is asked for with the keycloak.org/export annotation, giving the name of
+// the secret to write it to. An existing secret is only overwritten when it has the
+// app=keycloak label or is owned by the realm. Clients and groups along with roles
+// are exported unless keycloak.org/export-clients or keycloak.org/export-groups-and-roles
+// are set to false.
+// It comes after all other actions, so the export has the reconciled realm
+func (i *KeycloakRealmReconciler) getRealmExportState(state *common.RealmState, cr *kc.KeycloakRealm) common.ClusterAction {
+	secretName, ok := cr.Annotations[model.ExportRealmAnnotation]
+	if !ok || secretName == "" || state.Realm == nil {
+		return nil
+	}
+
+	return &common.ExportRealmAction{
+		SecretName:           secretName,
+		ExportClients:        cr.Annotations[model.ExportRealmClientsAnnotation] != "false",
+		ExportGroupsAndRoles: cr.Annotations[model.ExportRealmGroupsAndRolesAnnotation] != "false",
+		Ref:                  cr,
+		Msg:                  fmt.Sprintf("export realm %v/%v to secret %v", cr.Namespace, cr.Spec.Realm.Realm, secretName),
+	}
+}
@@ -0,0 +1,212 @@
+package keycloakrealm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/common"
+)
+
+// A group of the realm along with the path keycloak has it at
+type existingGroup struct {
+	Group      *kc.KeycloakAPIGroup
+	Path       string
+	ParentPath string
+}
+
+// Where the parent of a level of desired groups currently is in keycloak. Groups
+// below a parent that doesn't exist yet can only be matched by ID
+type groupParent struct {
+	Path         string
+	ExistingPath string
+	Exists       bool
+}
+
+type groupsDiff struct {
+	byID    map[string]*existingGroup
+	byPath  map[string]*existingGroup
+	matched map[string]bool
+}
+
+// Groups are created top down, so that every parent exists by the time its sub
+// groups are created. They are matched by ID when given, by path otherwise, and
+// groups left unmatched are removed last
+func (i *KeycloakRealmReconciler) ReconcileGroups(state *common.RealmState, cr *kc.KeycloakRealm, desired *common.DesiredClusterState) {
+	if cr.Spec.Groups == nil {
+		return
+	}
+
+	existing := flattenGroups(state.Groups, "")
+	diff := &groupsDiff{
+		byID:    make(map[string]*existingGroup),
+		byPath:  make(map[string]*existingGroup),
+		matched: make(map[string]bool),
+	}
+	for _, group := range existing {
+		diff.byID[group.Group.ID] = group
+		diff.byPath[group.Path] = group
+	}
+
+	i.reconcileGroupLevel(cr, cr.Spec.Groups, groupParent{Exists: true}, diff, desired)
+
+	// Sub groups are removed along with their parent
+	var deletedPaths []string
+	for _, group := range existing {
+		if diff.matched[group.Group.ID] || hasGroupAncestor(deletedPaths, group.Path) {
+			continue
+		}
+		desired.AddAction(i.getDeletedGroupState(cr, group))
+		deletedPaths = append(deletedPaths, group.Path)
+	}
+}
+
+func (i *KeycloakRealmReconciler) reconcileGroupLevel(cr *kc.KeycloakRealm, groups []kc.KeycloakAPIGroup, parent groupParent, diff *groupsDiff, desired *common.DesiredClusterState) {
+	for index := range groups {
+		group := &groups[index]
+		path := parent.Path + "/" + group.Name
+
+		current := diff.byID[group.ID]
+		if current == nil && parent.Exists {
+			current = diff.byPath[parent.ExistingPath+"/"+group.Name]
+		}
+		if current != nil && diff.matched[current.Group.ID] {
+			current = nil
+		}
+
+		if current == nil {
+			newGroup := group.DeepCopy()
+			newGroup.ID = ""
+			desired.AddAction(i.getCreatedGroupState(cr, newGroup, parent.Path))
+			if added := common.CompositesDifference(getGroupRoleMappings(group, nil), nil); added != nil {
+				desired.AddAction(i.getAddedGroupRoleMappingsState(cr, path, added))
+			}
+			i.reconcileGroupLevel(cr, group.SubGroups, groupParent{Path: path}, diff, desired)
+			continue
+		}
+
+		diff.matched[current.Group.ID] = true
+		updated := group.DeepCopy()
+		updated.ID = current.Group.ID
+
+		// Keycloak moves a group, keeping its members, when it is posted to another parent
+		if !parent.Exists || current.ParentPath != parent.ExistingPath {
+			desired.AddAction(i.getCreatedGroupState(cr, updated, parent.Path))
+		} else if !groupMatches(group, current.Group) {
+			desired.AddAction(i.getUpdatedGroupState(cr, updated))
+		}
+
+		currentMappings := getGroupRoleMappings(current.Group, nil)
+		desiredMappings := getGroupRoleMappings(group, current.Group)
+		if removed := common.CompositesDifference(currentMappings, desiredMappings); removed != nil {
+			desired.AddAction(i.getRemovedGroupRoleMappingsState(cr, path, removed))
+		}
+		if added := common.CompositesDifference(desiredMappings, currentMappings); added != nil {
+			desired.AddAction(i.getAddedGroupRoleMappingsState(cr, path, added))
+		}
+
+		i.reconcileGroupLevel(cr, group.SubGroups, groupParent{Path: path, ExistingPath: current.Path, Exists: true}, diff, desired)
+	}
+}
+
+// Depth first, so that parents come before their sub groups
+func flattenGroups(groups []*kc.KeycloakAPIGroup, parentPath string) []*existingGroup {
+	var result []*existingGroup
+	for _, group := range groups {
+		path := parentPath + "/" + group.Name
+		result = append(result, &existingGroup{Group: group, Path: path, ParentPath: parentPath})
+
+		var subGroups []*kc.KeycloakAPIGroup
+		for index := range group.SubGroups {
+			subGroups = append(subGroups, &group.SubGroups[index])
+		}
+		result = append(result, flattenGroups(subGroups, path)...)
+	}
+	return result
+}
+
+func hasGroupAncestor(paths []string, path string) bool {
+	for _, ancestor := range paths {
+		if strings.HasPrefix(path, ancestor+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Attributes are only managed when set in the CR, keycloak keeps them otherwise
+func groupMatches(desired, existing *kc.KeycloakAPIGroup) bool {
+	if desired.Name != existing.Name {
+		return false
+	}
+	if desired.Attributes == nil || (len(desired.Attributes) == 0 && len(existing.Attributes) == 0) {
+		return true
+	}
+	return reflect.DeepEqual(desired.Attributes, existing.Attributes)
+}
+
+// Role mappings of a group in the shape of role composites, so they can be diffed
+// the same way. Mappings not set in the CR are taken from the current group
+func getGroupRoleMappings(group, current *kc.KeycloakAPIGroup) *kc.RoleRepresentationComposites {
+	mappings := &kc.RoleRepresentationComposites{
+		Realm:  group.RealmRoles,
+		Client: group.ClientRoles,
+	}
+	if current != nil {
+		if group.RealmRoles == nil {
+			mappings.Realm = current.RealmRoles
+		}
+		if group.ClientRoles == nil {
+			mappings.Client = current.ClientRoles
+		}
+	}
+	return mappings
+}
+
+func (i *KeycloakRealmReconciler) getCreatedGroupState(cr *kc.KeycloakRealm, group *kc.KeycloakAPIGroup, parentPath string) common.ClusterAction {
+	verb := "create"
+	if group.ID != "" {
+		verb = "move"
+	}
+	return &common.CreateGroupAction{
+		Group:      group,
+		ParentPath: parentPath,
+		Ref:        cr,
+		Msg:        fmt.Sprintf("%v group %v/%v at %v/%v", verb, cr.Namespace, cr.Spec.Realm.Realm, parentPath, group.Name),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getUpdatedGroupState(cr *kc.KeycloakRealm, group *kc.KeycloakAPIGroup) common.ClusterAction {
+	return &common.UpdateGroupAction{
+		Group: group,
+		Ref:   cr,
+		Msg:   fmt.Sprintf("update group %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, group.Name),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getDeletedGroupState(cr *kc.KeycloakRealm, group *existingGroup) common.ClusterAction {
+	return &common.DeleteGroupAction{
+		Group: group.Group,
+		Ref:   cr,
+		Msg:   fmt.Sprintf("delete group %v/%v at %v", cr.Namespace, cr.Spec.Realm.Realm, group.Path),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getAddedGroupRoleMappingsState(cr *kc.KeycloakRealm, path string, mappings *kc.RoleRepresentationComposites) common.ClusterAction {
+	return &common.AddGroupRoleMappingsAction{
+		Path:     path,
+		Mappings: mappings,
+		Ref:      cr,
+		Msg:      fmt.Sprintf("add role mappings to group %v/%v at %v", cr.Namespace, cr.Spec.Realm.Realm, path),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getRemovedGroupRoleMappingsState(cr *kc.KeycloakRealm, path string, mappings *kc.RoleRepresentationComposites) common.ClusterAction {
+	return &common.RemoveGroupRoleMappingsAction{
+		Path:     path,
+		Mappings: mappings,
+		Ref:      cr,
+		Msg:      fmt.Sprintf("remove role mappings from group %v/%v at %v", cr.Namespace, cr.Spec.Realm.Realm, path),
+	}
+}
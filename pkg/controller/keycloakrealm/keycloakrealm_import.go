@@ -0,0 +1,45 @@
+package keycloakrealm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/common"
+)
+
+// The realm JSON is imported into the existing realm whenever its content or the
+// policy changes, as told by the hash in the status. The hash is cleared when the
+// operator creates the realm again, e.g. after it was deleted in keycloak. Settings
+// managed through the CR are reconciled after the import, so they take precedence
+func (i *KeycloakRealmReconciler) getRealmImportState(state *common.RealmState, cr *kc.KeycloakRealm) common.ClusterAction {
+	if cr.Spec.RealmImport == nil || state.Realm == nil || state.RealmImport == nil {
+		return nil
+	}
+
+	ifResourceExists := "SKIP"
+	if cr.Spec.RealmImport.Policy == kc.RealmImportOverwriteOnChange {
+		ifResourceExists = "OVERWRITE"
+	}
+
+	hash := realmImportHash(state.RealmImport, ifResourceExists)
+	if hash == cr.Status.RealmImportHash {
+		return nil
+	}
+
+	return &common.ImportRealmAction{
+		RealmJSON:        state.RealmImport,
+		IfResourceExists: ifResourceExists,
+		Hash:             hash,
+		Ref:              cr,
+		Msg:              fmt.Sprintf("import realm JSON into realm %v/%v", cr.Namespace, cr.Spec.Realm.Realm),
+	}
+}
+
+func realmImportHash(realmJSON []byte, ifResourceExists string) string {
+	hash := sha256.New()
+	hash.Write(realmJSON)
+	hash.Write([]byte(ifResourceExists))
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,65 @@
+package keycloakrealm
+
+import (
+	"fmt"
+	"sort"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/common"
+)
+
+// Message overrides are diffed per locale, so that only the locales with changed
+// or removed keys are touched. Locales keycloak has that the CR doesn't list lose
+// all their overrides
+func (i *KeycloakRealmReconciler) ReconcileLocalization(state *common.RealmState, cr *kc.KeycloakRealm, desired *common.DesiredClusterState) {
+	if cr.Spec.Localization == nil || state.Realm == nil || state.Localization == nil {
+		return
+	}
+
+	locales := make(map[string]bool)
+	for locale := range cr.Spec.Localization {
+		locales[locale] = true
+	}
+	for locale := range state.Localization {
+		locales[locale] = true
+	}
+
+	sorted := make([]string, 0, len(locales))
+	for locale := range locales {
+		sorted = append(sorted, locale)
+	}
+	sort.Strings(sorted)
+
+	for _, locale := range sorted {
+		desired.AddAction(i.getUpdatedLocalizationState(cr, locale, cr.Spec.Localization[locale], state.Localization[locale]))
+	}
+}
+
+func (i *KeycloakRealmReconciler) getUpdatedLocalizationState(cr *kc.KeycloakRealm, locale string, texts, existing map[string]string) common.ClusterAction {
+	changed := make(map[string]string)
+	for key, text := range texts {
+		if current, ok := existing[key]; !ok || current != text {
+			changed[key] = text
+		}
+	}
+
+	var removed []string
+	for key := range existing {
+		if _, ok := texts[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+
+	if len(changed) == 0 && len(removed) == 0 {
+		return nil
+	}
+
+	return &common.UpdateRealmLocalizationAction{
+		Locale:  locale,
+		Texts:   changed,
+		Removed: removed,
+		Ref:     cr,
+		Msg:     fmt.Sprintf("update localization %v of realm %v/%v", locale, cr.Namespace, cr.Spec.Realm.Realm),
+	}
+}
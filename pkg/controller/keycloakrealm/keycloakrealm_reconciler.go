@@ -2,6 +2,7 @@ package keycloakrealm
 
 import (
 	"fmt"
+	"strings"
 
 	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	"github.com/keycloak/keycloak-operator/pkg/common"
@@ -34,12 +35,34 @@ func (i *KeycloakRealmReconciler) ReconcileRealmCreate(state *common.RealmState,
 
 	desired.AddAction(i.getKeycloakDesiredState())
 	desired.AddAction(i.getDesiredRealmState(state, cr))
+	desired.AddAction(i.getRealmImportState(state, cr))
+	desired.AddAction(i.getDesiredRealmSettingsState(state, cr))
+	desired.AddAction(i.getDesiredRealmEventsState(state, cr))
+
+	i.ReconcileIdentityProviders(state, cr, &desired)
+	i.ReconcileIdentityProviderMappers(state, cr, &desired)
+	i.ReconcileUserFederationProviders(state, cr, &desired)
+	i.ReconcileUserFederationMappers(state, cr, &desired)
+	i.ReconcileAuthenticationFlows(state, cr, &desired)
+	i.ReconcileRequiredActions(state, cr, &desired)
+	i.ReconcileClientPolicies(state, cr, &desired)
+	i.ReconcileRoles(state, cr, &desired)
+	i.ReconcileGroups(state, cr, &desired)
+	i.ReconcileDefaultGroups(state, cr, &desired)
+	i.ReconcileDefaultClientScopes(state, cr, &desired)
+	i.ReconcileLocalization(state, cr, &desired)
 
 	for _, user := range cr.Spec.Realm.Users {
 		desired.AddAction(i.getDesiredUserSate(state, cr, user))
 	}
+	// after the roles and groups the imported users may refer to
+	desired.AddAction(i.getUserImportState(state, cr))
 
 	desired.AddAction(i.getBrowserRedirectorDesiredState(state, cr))
+	// last, so that the annotations asking for them are only removed once the rest
+	// of the realm is reconciled
+	desired.AddAction(i.getUserFederationSyncState(state, cr))
+	desired.AddAction(i.getRealmExportState(state, cr))
 
 	return desired
 }
@@ -51,6 +74,274 @@ func (i *KeycloakRealmReconciler) ReconcileRealmDelete(state *common.RealmState,
 	return desired
 }
 
+// Identity providers are matched by alias and always updated in place, as the
+// secrets in their config can't be compared with what keycloak returns
+func (i *KeycloakRealmReconciler) ReconcileIdentityProviders(state *common.RealmState, cr *kc.KeycloakRealm, desired *common.DesiredClusterState) {
+	if cr.Spec.Realm.IdentityProviders == nil {
+		return
+	}
+
+	for _, provider := range state.IdentityProviders {
+		if getIdentityProvider(cr.Spec.Realm.IdentityProviders, provider.Alias) == nil {
+			desired.AddAction(i.getDeletedIdentityProviderState(cr, provider))
+		}
+	}
+
+	for _, provider := range cr.Spec.Realm.IdentityProviders {
+		resolved := getResolvedIdentityProvider(state, provider)
+
+		existing := getIdentityProvider(state.IdentityProviders, provider.Alias)
+		if existing == nil {
+			desired.AddAction(i.getCreatedIdentityProviderState(cr, resolved))
+			continue
+		}
+
+		// keycloak looks up the provider to update by its internal ID
+		resolved.InternalID = existing.InternalID
+		desired.AddAction(i.getUpdatedIdentityProviderState(cr, resolved))
+	}
+}
+
+// Mappers go after the identity providers, which may be created in the same pass
+func (i *KeycloakRealmReconciler) ReconcileIdentityProviderMappers(state *common.RealmState, cr *kc.KeycloakRealm, desired *common.DesiredClusterState) {
+	if cr.Spec.Realm.IdentityProviderMappers == nil {
+		return
+	}
+
+	for _, mapper := range state.IdentityProviderMappers {
+		// mappers of removed identity providers are removed along with them
+		if cr.Spec.Realm.IdentityProviders != nil && getIdentityProvider(cr.Spec.Realm.IdentityProviders, mapper.IdentityProviderAlias) == nil {
+			continue
+		}
+		if getIdentityProviderMapper(cr.Spec.Realm.IdentityProviderMappers, mapper.IdentityProviderAlias, mapper.Name) == nil {
+			desired.AddAction(i.getDeletedIdentityProviderMapperState(cr, mapper.DeepCopy()))
+		}
+	}
+
+	for _, mapper := range cr.Spec.Realm.IdentityProviderMappers {
+		existing := getIdentityProviderMapper(state.IdentityProviderMappers, mapper.IdentityProviderAlias, mapper.Name)
+		if existing == nil {
+			newMapper := mapper.DeepCopy()
+			newMapper.ID = ""
+			desired.AddAction(i.getCreatedIdentityProviderMapperState(cr, newMapper))
+			continue
+		}
+		if identityProviderMapperMatches(&mapper, existing) {
+			continue
+		}
+
+		updated := mapper.DeepCopy()
+		updated.ID = existing.ID
+		desired.AddAction(i.getUpdatedIdentityProviderMapperState(cr, updated))
+	}
+}
+
+// New realms get their flows and bindings through the import. Once the realm exists,
+// changed flows are replaced as a whole, built in flows are left untouched
+func (i *KeycloakRealmReconciler) ReconcileAuthenticationFlows(state *common.RealmState, cr *kc.KeycloakRealm, desired *common.DesiredClusterState) {
+	if state.Realm == nil {
+		return
+	}
+
+	if cr.Spec.Realm.AuthenticationFlows != nil {
+		for index := range cr.Spec.Realm.AuthenticationFlows {
+			flow := &cr.Spec.Realm.AuthenticationFlows[index]
+			if !flow.TopLevel {
+				continue
+			}
+
+			existing := getAuthenticationFlow(state.AuthenticationFlows, flow.Alias)
+			if existing == nil {
+				desired.AddAction(i.getCreatedAuthenticationFlowState(cr, flow))
+				continue
+			}
+			if existing.BuiltIn {
+				continue
+			}
+			if !common.AuthenticationFlowMatches(flow, cr.Spec.Realm, state.AuthenticationExecutions[flow.Alias], state.AuthenticatorConfigs) {
+				desired.AddAction(i.getReplacedAuthenticationFlowState(cr, flow, existing))
+			}
+		}
+	}
+
+	// flows have to exist before they are bound, and be unbound before they are deleted
+	current := state.Realm.Spec.Realm
+	if (cr.Spec.Realm.BrowserFlow != "" && cr.Spec.Realm.BrowserFlow != current.BrowserFlow) ||
+		(cr.Spec.Realm.DirectGrantFlow != "" && cr.Spec.Realm.DirectGrantFlow != current.DirectGrantFlow) {
+		desired.AddAction(i.getRealmFlowBindingsState(cr))
+	}
+
+	if cr.Spec.Realm.AuthenticationFlows != nil {
+		for _, flow := range state.AuthenticationFlows {
+			if flow.BuiltIn || !flow.TopLevel {
+				continue
+			}
+			if common.FindAuthenticationFlow(cr.Spec.Realm.AuthenticationFlows, flow.Alias) == nil {
+				desired.AddAction(i.getDeletedAuthenticationFlowState(cr, flow))
+			}
+		}
+	}
+}
+
+func getAuthenticationFlow(flows []*kc.KeycloakAPIAuthenticationFlow, alias string) *kc.KeycloakAPIAuthenticationFlow {
+	for _, flow := range flows {
+		if flow.Alias == alias {
+			return flow
+		}
+	}
+	return nil
+}
+
+func getIdentityProviderMapper(mappers []kc.KeycloakIdentityProviderMapper, alias, name string) *kc.KeycloakIdentityProviderMapper {
+	for index := range mappers {
+		if mappers[index].IdentityProviderAlias == alias && mappers[index].Name == name {
+			return &mappers[index]
+		}
+	}
+	return nil
+}
+
+// Keycloak adds defaults to the config of some mappers, only the values given in the
+// CR are compared
+func identityProviderMapperMatches(desired, existing *kc.KeycloakIdentityProviderMapper) bool {
+	if desired.IdentityProviderMapper != existing.IdentityProviderMapper {
+		return false
+	}
+	for key, value := range desired.Config {
+		if existing.Config[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func getIdentityProvider(providers []*kc.KeycloakIdentityProvider, alias string) *kc.KeycloakIdentityProvider {
+	for _, provider := range providers {
+		if provider.Alias == alias {
+			return provider
+		}
+	}
+	return nil
+}
+
+// Returns the provider as sent to keycloak, with the config values read from
+// secrets merged into its config
+func getResolvedIdentityProvider(state *common.RealmState, provider *kc.KeycloakIdentityProvider) *kc.KeycloakIdentityProvider {
+	resolved := provider.DeepCopy()
+	resolved.ConfigSecrets = nil
+
+	for key, value := range state.IdentityProviderSecrets[provider.Alias] {
+		if resolved.Config == nil {
+			resolved.Config = make(map[string]string)
+		}
+		resolved.Config[key] = value
+	}
+	return resolved
+}
+
+// Realm roles are only managed when set in the CR, so that roles created through
+// the admin console are left alone otherwise
+func (i *KeycloakRealmReconciler) ReconcileRoles(state *common.RealmState, cr *kc.KeycloakRealm, desired *common.DesiredClusterState) {
+	if cr.Spec.Roles == nil {
+		return
+	}
+
+	// a missing realm has no roles, so any roles still known with an ID are
+	// phantoms that must not be updated or deleted
+	if state.Realm == nil {
+		rolesCreated := make(map[string]bool)
+		for _, role := range cr.Spec.Roles {
+			newRole := role.DeepCopy()
+			newRole.ID = ""
+			desired.AddAction(i.getCreatedRealmRoleState(cr, newRole))
+			rolesCreated[role.Name] = true
+		}
+		i.ReconcileRoleComposites(state, cr, rolesCreated, desired)
+		return
+	}
+
+	diff := common.DiffRoles(state.Roles, cr.Spec.Roles)
+	for _, role := range diff.Deleted {
+		if isDefaultRealmRole(cr, role) {
+			continue
+		}
+		desired.AddAction(i.getDeletedRealmRoleState(cr, role.DeepCopy()))
+	}
+	for _, update := range diff.Updated {
+		desired.AddAction(i.getUpdatedRealmRoleState(cr, update.Role.DeepCopy(), update.OldRole.DeepCopy()))
+	}
+	rolesCreated := make(map[string]bool)
+	for _, role := range diff.Created {
+		desired.AddAction(i.getCreatedRealmRoleState(cr, role.DeepCopy()))
+		rolesCreated[role.Name] = true
+	}
+
+	// composites go last, as they may reference any of the roles created above
+	i.ReconcileRoleComposites(state, cr, rolesCreated, desired)
+}
+
+func (i *KeycloakRealmReconciler) ReconcileRoleComposites(state *common.RealmState, cr *kc.KeycloakRealm, rolesCreated map[string]bool, desired *common.DesiredClusterState) {
+	for _, role := range cr.Spec.Roles {
+		// (re-)created roles start out without any composites
+		var current *kc.RoleRepresentationComposites
+		if !rolesCreated[role.Name] {
+			for _, existingRole := range state.Roles {
+				if common.RoleMatches(role, existingRole) {
+					current = state.RoleComposites[existingRole.Name]
+					break
+				}
+			}
+		}
+
+		if removed := common.CompositesDifference(current, role.Composites); removed != nil {
+			desired.AddAction(i.getRemovedRealmRoleCompositesState(cr, role.DeepCopy(), removed))
+		}
+		if added := common.CompositesDifference(role.Composites, current); added != nil {
+			desired.AddAction(i.getAddedRealmRoleCompositesState(cr, role.DeepCopy(), added))
+		}
+	}
+}
+
+// Default groups are compared by ID, so that a path given differently in the CR
+// or a group renamed since doesn't cause it to be unset and set again. Paths that
+// don't resolve are set anyway, for the action to report the missing group
+func (i *KeycloakRealmReconciler) ReconcileDefaultGroups(state *common.RealmState, cr *kc.KeycloakRealm, desired *common.DesiredClusterState) {
+	if cr.Spec.DefaultGroups == nil {
+		return
+	}
+
+	current := make(map[string]bool)
+	for _, group := range state.DefaultGroups {
+		current[group.ID] = true
+	}
+
+	wanted := make(map[string]bool)
+	for _, path := range cr.Spec.DefaultGroups {
+		group := state.DesiredDefaultGroups[path]
+		if group == nil || !current[group.ID] {
+			desired.AddAction(i.getSetDefaultGroupState(cr, path))
+		}
+		if group != nil {
+			wanted[group.ID] = true
+		}
+	}
+
+	for _, group := range state.DefaultGroups {
+		if !wanted[group.ID] {
+			desired.AddAction(i.getUnsetDefaultGroupState(cr, group))
+		}
+	}
+}
+
+// Keycloak creates these roles along with every realm and relies on them
+func isDefaultRealmRole(cr *kc.KeycloakRealm, role kc.RoleRepresentation) bool {
+	switch role.Name {
+	case "offline_access", "uma_authorization", "default-roles-" + strings.ToLower(cr.Spec.Realm.Realm):
+		return true
+	}
+	return false
+}
+
 // Always make sure keycloak is able to respond
 func (i *KeycloakRealmReconciler) getKeycloakDesiredState() common.ClusterAction {
 	return &common.PingAction{
@@ -104,3 +395,142 @@ func (i *KeycloakRealmReconciler) getDesiredUserSate(state *common.RealmState, c
 
 	return nil
 }
+
+func (i *KeycloakRealmReconciler) getCreatedRealmRoleState(cr *kc.KeycloakRealm, role *kc.RoleRepresentation) common.ClusterAction {
+	return &common.CreateRealmRoleAction{
+		Role: role,
+		Ref:  cr,
+		Msg:  fmt.Sprintf("create realm role %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, role.Name),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getUpdatedRealmRoleState(cr *kc.KeycloakRealm, role, oldRole *kc.RoleRepresentation) common.ClusterAction {
+	return &common.UpdateRealmRoleAction{
+		Role:    role,
+		OldRole: oldRole,
+		Ref:     cr,
+		Msg:     fmt.Sprintf("update realm role %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, oldRole.Name),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getDeletedRealmRoleState(cr *kc.KeycloakRealm, role *kc.RoleRepresentation) common.ClusterAction {
+	return &common.DeleteRealmRoleAction{
+		Role: role,
+		Ref:  cr,
+		Msg:  fmt.Sprintf("delete realm role %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, role.Name),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getSetDefaultGroupState(cr *kc.KeycloakRealm, path string) common.ClusterAction {
+	return &common.SetDefaultGroupAction{
+		Path: path,
+		Ref:  cr,
+		Msg:  fmt.Sprintf("set default group %v of realm %v/%v", path, cr.Namespace, cr.Spec.Realm.Realm),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getUnsetDefaultGroupState(cr *kc.KeycloakRealm, group *kc.KeycloakUserGroup) common.ClusterAction {
+	return &common.UnsetDefaultGroupAction{
+		Group: group,
+		Ref:   cr,
+		Msg:   fmt.Sprintf("unset default group %v of realm %v/%v", group.Path, cr.Namespace, cr.Spec.Realm.Realm),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getAddedRealmRoleCompositesState(cr *kc.KeycloakRealm, role *kc.RoleRepresentation, composites *kc.RoleRepresentationComposites) common.ClusterAction {
+	return &common.AddRealmRoleCompositesAction{
+		Role:       role,
+		Composites: composites,
+		Ref:        cr,
+		Msg:        fmt.Sprintf("add composites to realm role %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, role.Name),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getRemovedRealmRoleCompositesState(cr *kc.KeycloakRealm, role *kc.RoleRepresentation, composites *kc.RoleRepresentationComposites) common.ClusterAction {
+	return &common.RemoveRealmRoleCompositesAction{
+		Role:       role,
+		Composites: composites,
+		Ref:        cr,
+		Msg:        fmt.Sprintf("remove composites from realm role %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, role.Name),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getCreatedIdentityProviderState(cr *kc.KeycloakRealm, provider *kc.KeycloakIdentityProvider) common.ClusterAction {
+	return &common.CreateIdentityProviderAction{
+		IdentityProvider: provider,
+		Ref:              cr,
+		Msg:              fmt.Sprintf("create identity provider %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, provider.Alias),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getUpdatedIdentityProviderState(cr *kc.KeycloakRealm, provider *kc.KeycloakIdentityProvider) common.ClusterAction {
+	return &common.UpdateIdentityProviderAction{
+		IdentityProvider: provider,
+		Ref:              cr,
+		Msg:              fmt.Sprintf("update identity provider %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, provider.Alias),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getDeletedIdentityProviderState(cr *kc.KeycloakRealm, provider *kc.KeycloakIdentityProvider) common.ClusterAction {
+	return &common.DeleteIdentityProviderAction{
+		IdentityProvider: provider,
+		Ref:              cr,
+		Msg:              fmt.Sprintf("delete identity provider %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, provider.Alias),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getCreatedIdentityProviderMapperState(cr *kc.KeycloakRealm, mapper *kc.KeycloakIdentityProviderMapper) common.ClusterAction {
+	return &common.CreateIdentityProviderMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Msg:    fmt.Sprintf("create identity provider mapper %v/%v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, mapper.IdentityProviderAlias, mapper.Name),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getUpdatedIdentityProviderMapperState(cr *kc.KeycloakRealm, mapper *kc.KeycloakIdentityProviderMapper) common.ClusterAction {
+	return &common.UpdateIdentityProviderMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Msg:    fmt.Sprintf("update identity provider mapper %v/%v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, mapper.IdentityProviderAlias, mapper.Name),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getDeletedIdentityProviderMapperState(cr *kc.KeycloakRealm, mapper *kc.KeycloakIdentityProviderMapper) common.ClusterAction {
+	return &common.DeleteIdentityProviderMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Msg:    fmt.Sprintf("delete identity provider mapper %v/%v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, mapper.IdentityProviderAlias, mapper.Name),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getCreatedAuthenticationFlowState(cr *kc.KeycloakRealm, flow *kc.KeycloakAPIAuthenticationFlow) common.ClusterAction {
+	return &common.ReplaceAuthenticationFlowAction{
+		Flow: flow,
+		Ref:  cr,
+		Msg:  fmt.Sprintf("create authentication flow %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, flow.Alias),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getReplacedAuthenticationFlowState(cr *kc.KeycloakRealm, flow, existing *kc.KeycloakAPIAuthenticationFlow) common.ClusterAction {
+	return &common.ReplaceAuthenticationFlowAction{
+		Flow:     flow,
+		Existing: existing,
+		Ref:      cr,
+		Msg:      fmt.Sprintf("replace authentication flow %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, flow.Alias),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getDeletedAuthenticationFlowState(cr *kc.KeycloakRealm, flow *kc.KeycloakAPIAuthenticationFlow) common.ClusterAction {
+	return &common.DeleteAuthenticationFlowAction{
+		Flow: flow,
+		Ref:  cr,
+		Msg:  fmt.Sprintf("delete authentication flow %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, flow.Alias),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getRealmFlowBindingsState(cr *kc.KeycloakRealm) common.ClusterAction {
+	return &common.UpdateRealmFlowBindingsAction{
+		Ref: cr,
+		Msg: fmt.Sprintf("update flow bindings of realm %v/%v", cr.Namespace, cr.Spec.Realm.Realm),
+	}
+}
@@ -2,13 +2,16 @@ package keycloakrealm
 
 import (
 	"testing"
+	"time"
 
 	v12 "k8s.io/api/core/v1"
 
 	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	"github.com/keycloak/keycloak-operator/pkg/common"
+	"github.com/keycloak/keycloak-operator/pkg/model"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func getDummyRealm() *v1alpha1.KeycloakRealm {
@@ -164,3 +167,1509 @@ func TestKeycloakRealmReconciler_Update(t *testing.T) {
 	assert.IsType(t, &common.PingAction{}, desiredState[0])
 	assert.Len(t, desiredState, 1)
 }
+
+func TestKeycloakRealmReconciler_ReconcileRoles(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Roles = []v1alpha1.RoleRepresentation{
+		{ID: "aID", Name: "renamed"},
+		{Name: "b", Composites: &v1alpha1.RoleRepresentationComposites{
+			Realm:  []string{"renamed"},
+			Client: map[string][]string{"client": {"client-role"}},
+		}},
+		{Name: "new"},
+	}
+
+	state := getDummyState()
+	state.Realm = realm
+	state.Roles = []v1alpha1.RoleRepresentation{
+		{ID: "aID", Name: "a"},
+		{ID: "bID", Name: "b", Composite: &[]bool{true}[0]},
+		{ID: "removedID", Name: "removed"},
+		{ID: "offlineID", Name: "offline_access"},
+		{ID: "umaID", Name: "uma_authorization"},
+		{ID: "defaultID", Name: "default-roles-dummy"},
+	}
+	state.RoleComposites = map[string]*v1alpha1.RoleRepresentationComposites{
+		"b": {Realm: []string{"removed"}},
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - delete role removed, default roles are kept
+	// 2 - rename role a
+	// 3 - update role b
+	// 4 - create role new
+	// 5 - remove composite removed from role b
+	// 6 - add composites to role b
+	assert.Len(t, desiredState, 7)
+	assert.IsType(t, &common.PingAction{}, desiredState[0])
+	assert.Equal(t, "removed", desiredState[1].(*common.DeleteRealmRoleAction).Role.Name)
+	assert.Equal(t, "a", desiredState[2].(*common.UpdateRealmRoleAction).OldRole.Name)
+	assert.Equal(t, "renamed", desiredState[2].(*common.UpdateRealmRoleAction).Role.Name)
+	assert.Equal(t, "b", desiredState[3].(*common.UpdateRealmRoleAction).Role.Name)
+	assert.Equal(t, "new", desiredState[4].(*common.CreateRealmRoleAction).Role.Name)
+	assert.Equal(t, []string{"removed"}, desiredState[5].(*common.RemoveRealmRoleCompositesAction).Composites.Realm)
+	addedComposites := desiredState[6].(*common.AddRealmRoleCompositesAction).Composites
+	assert.Equal(t, []string{"renamed"}, addedComposites.Realm)
+	assert.Equal(t, map[string][]string{"client": {"client-role"}}, addedComposites.Client)
+}
+
+func TestKeycloakRealmReconciler_ReconcileGroups(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Groups = []v1alpha1.KeycloakAPIGroup{
+		{Name: "parent", RealmRoles: []string{"kept", "added"}, SubGroups: []v1alpha1.KeycloakAPIGroup{
+			{Name: "unchanged"},
+			{ID: "renamedID", Name: "renamed"},
+		}},
+		{Name: "new", ClientRoles: map[string][]string{"client": {"client-role"}}, SubGroups: []v1alpha1.KeycloakAPIGroup{
+			{ID: "movedID", Name: "moved"},
+		}},
+	}
+
+	state := getDummyState()
+	state.Realm = realm
+	state.Groups = []*v1alpha1.KeycloakAPIGroup{
+		{ID: "parentID", Name: "parent", RealmRoles: []string{"kept", "removed"}, SubGroups: []v1alpha1.KeycloakAPIGroup{
+			{ID: "unchangedID", Name: "unchanged", RealmRoles: []string{"unmanaged"}},
+			{ID: "renamedID", Name: "old"},
+			{ID: "movedID", Name: "moved"},
+		}},
+		{ID: "removedID", Name: "removed", SubGroups: []v1alpha1.KeycloakAPIGroup{
+			{ID: "removedChildID", Name: "child"},
+		}},
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - remove realm role mapping removed from /parent
+	// 2 - add realm role mapping added to /parent
+	// 3 - rename /parent/old
+	// 4 - create /new
+	// 5 - add client role mappings to /new
+	// 6 - move /parent/moved to /new
+	// 7 - delete /removed along with its sub group
+	assert.Len(t, desiredState, 8)
+	assert.Equal(t, []string{"removed"}, desiredState[1].(*common.RemoveGroupRoleMappingsAction).Mappings.Realm)
+	assert.Equal(t, []string{"added"}, desiredState[2].(*common.AddGroupRoleMappingsAction).Mappings.Realm)
+	assert.Equal(t, "renamedID", desiredState[3].(*common.UpdateGroupAction).Group.ID)
+	assert.Equal(t, "renamed", desiredState[3].(*common.UpdateGroupAction).Group.Name)
+	assert.Equal(t, "", desiredState[4].(*common.CreateGroupAction).Group.ID)
+	assert.Equal(t, "", desiredState[4].(*common.CreateGroupAction).ParentPath)
+	assert.Equal(t, "/new", desiredState[5].(*common.AddGroupRoleMappingsAction).Path)
+	assert.Equal(t, map[string][]string{"client": {"client-role"}}, desiredState[5].(*common.AddGroupRoleMappingsAction).Mappings.Client)
+	assert.Equal(t, "movedID", desiredState[6].(*common.CreateGroupAction).Group.ID)
+	assert.Equal(t, "/new", desiredState[6].(*common.CreateGroupAction).ParentPath)
+	assert.Equal(t, "removedID", desiredState[7].(*common.DeleteGroupAction).Group.ID)
+}
+
+func TestKeycloakRealmReconciler_ReconcileDefaultGroups(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.DefaultGroups = []string{"kept", "/parent/new", "/missing"}
+
+	state := getDummyState()
+	state.Realm = realm
+	state.DefaultGroups = []*v1alpha1.KeycloakUserGroup{
+		{ID: "keptID", Name: "kept", Path: "/kept"},
+		{ID: "removedID", Name: "removed", Path: "/removed"},
+	}
+	state.DesiredDefaultGroups = map[string]*v1alpha1.KeycloakUserGroup{
+		"kept":        {ID: "keptID", Name: "kept", Path: "/kept"},
+		"/parent/new": {ID: "newID", Name: "new", Path: "/parent/new"},
+		"/missing":    nil,
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - set default group /parent/new
+	// 2 - set default group /missing, which reports the missing group
+	// 3 - unset default group /removed
+	assert.Len(t, desiredState, 4)
+	assert.Equal(t, "/parent/new", desiredState[1].(*common.SetDefaultGroupAction).Path)
+	assert.Equal(t, "/missing", desiredState[2].(*common.SetDefaultGroupAction).Path)
+	assert.Equal(t, "removedID", desiredState[3].(*common.UnsetDefaultGroupAction).Group.ID)
+}
+
+func TestKeycloakRealmReconciler_ReconcileDefaultClientScopes(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.DefaultClientScopes = []string{"profile", "email"}
+	realm.Spec.OptionalClientScopes = []string{"roles", "custom"}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.ClientScopes = []v1alpha1.KeycloakAPIClientScope{
+		{ID: "1", Name: "profile"},
+		{ID: "2", Name: "email"},
+		{ID: "3", Name: "roles"},
+		{ID: "4", Name: "phone"},
+	}
+	state.DefaultClientScopes = []v1alpha1.KeycloakAPIClientScope{{ID: "1", Name: "profile"}, {ID: "3", Name: "roles"}}
+	state.OptionalClientScopes = []v1alpha1.KeycloakAPIClientScope{{ID: "4", Name: "phone"}}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - remove default client scope roles
+	// 2 - remove optional client scope phone
+	// 3 - add default client scope email
+	// 4 - add optional client scope roles
+	// 5 - add optional client scope custom, looked up by name as it doesn't exist yet
+	assert.Len(t, desiredState, 6)
+	assert.Equal(t, "3", desiredState[1].(*common.RemoveRealmDefaultClientScopeAction).Scope.ID)
+	assert.False(t, desiredState[1].(*common.RemoveRealmDefaultClientScopeAction).Optional)
+	assert.Equal(t, "4", desiredState[2].(*common.RemoveRealmDefaultClientScopeAction).Scope.ID)
+	assert.True(t, desiredState[2].(*common.RemoveRealmDefaultClientScopeAction).Optional)
+	assert.Equal(t, "2", desiredState[3].(*common.AddRealmDefaultClientScopeAction).Scope.ID)
+	assert.Equal(t, "3", desiredState[4].(*common.AddRealmDefaultClientScopeAction).Scope.ID)
+	assert.True(t, desiredState[4].(*common.AddRealmDefaultClientScopeAction).Optional)
+	assert.Equal(t, "", desiredState[5].(*common.AddRealmDefaultClientScopeAction).Scope.ID)
+	assert.Equal(t, "custom", desiredState[5].(*common.AddRealmDefaultClientScopeAction).Scope.Name)
+}
+
+func TestKeycloakRealmReconciler_ReconcileRoles_Realm_Missing(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Roles = []v1alpha1.RoleRepresentation{
+		{ID: "phantomID", Name: "a", Composites: &v1alpha1.RoleRepresentationComposites{
+			Realm: []string{"offline_access"},
+		}},
+	}
+
+	state := getDummyState()
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - create realm
+	// 2 - create role a without its stale ID
+	// 3 - add composites to role a
+	assert.Len(t, desiredState, 4)
+	assert.IsType(t, &common.CreateRealmAction{}, desiredState[1])
+	assert.Equal(t, "", desiredState[2].(*common.CreateRealmRoleAction).Role.ID)
+	assert.Equal(t, []string{"offline_access"}, desiredState[3].(*common.AddRealmRoleCompositesAction).Composites.Realm)
+	assert.Equal(t, "phantomID", realm.Spec.Roles[0].ID)
+}
+
+func TestKeycloakRealmReconciler_ReconcileRoles_Unmanaged(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+
+	state := getDummyState()
+	state.Realm = realm
+	state.Roles = []v1alpha1.RoleRepresentation{
+		{ID: "aID", Name: "a"},
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 1)
+	assert.IsType(t, &common.PingAction{}, desiredState[0])
+}
+
+func TestKeycloakRealmReconciler_ReconcileIdentityProviders(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Realm.IdentityProviders = []*v1alpha1.KeycloakIdentityProvider{
+		{
+			Alias:      "github",
+			ProviderID: "github",
+			Config:     map[string]string{"clientId": "github", "clientSecret": "ignored"},
+			ConfigSecrets: map[string]v12.SecretKeySelector{
+				"clientSecret": {LocalObjectReference: v12.LocalObjectReference{Name: "github"}, Key: "secret"},
+			},
+		},
+		{Alias: "google", ProviderID: "google"},
+	}
+
+	state := getDummyState()
+	state.Realm = realm
+	state.IdentityProviders = []*v1alpha1.KeycloakIdentityProvider{
+		{Alias: "github", InternalID: "githubID", ProviderID: "github"},
+		{Alias: "removed", InternalID: "removedID", ProviderID: "oidc"},
+	}
+	state.IdentityProviderSecrets = map[string]map[string]string{
+		"github": {"clientSecret": "s3cr3t"},
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - delete identity provider removed
+	// 2 - update identity provider github with the referenced secret
+	// 3 - create identity provider google
+	assert.Len(t, desiredState, 4)
+	assert.Equal(t, "removed", desiredState[1].(*common.DeleteIdentityProviderAction).IdentityProvider.Alias)
+	updated := desiredState[2].(*common.UpdateIdentityProviderAction).IdentityProvider
+	assert.Equal(t, "githubID", updated.InternalID)
+	assert.Equal(t, map[string]string{"clientId": "github", "clientSecret": "s3cr3t"}, updated.Config)
+	assert.Nil(t, updated.ConfigSecrets)
+	assert.Equal(t, "google", desiredState[3].(*common.CreateIdentityProviderAction).IdentityProvider.Alias)
+
+	// the secret value must not end up in the CR
+	assert.Equal(t, "ignored", realm.Spec.Realm.IdentityProviders[0].Config["clientSecret"])
+}
+
+func TestKeycloakRealmReconciler_ReconcileIdentityProviders_Realm_Missing(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Realm.IdentityProviders = []*v1alpha1.KeycloakIdentityProvider{
+		{Alias: "google", ProviderID: "google"},
+	}
+
+	state := getDummyState()
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - create realm
+	// 2 - create identity provider google
+	assert.Len(t, desiredState, 3)
+	assert.IsType(t, &common.CreateRealmAction{}, desiredState[1])
+	assert.IsType(t, &common.CreateIdentityProviderAction{}, desiredState[2])
+}
+
+func TestKeycloakRealmReconciler_ReconcileIdentityProviderMappers(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Realm.IdentityProviderMappers = []v1alpha1.KeycloakIdentityProviderMapper{
+		{Name: "role", IdentityProviderAlias: "github", IdentityProviderMapper: "hardcoded-role-idp-mapper", Config: map[string]string{"role": "user"}},
+		{Name: "role", IdentityProviderAlias: "google", IdentityProviderMapper: "hardcoded-role-idp-mapper"},
+	}
+
+	state := getDummyState()
+	state.Realm = realm
+	state.IdentityProviders = []*v1alpha1.KeycloakIdentityProvider{
+		{Alias: "github"},
+		{Alias: "google"},
+	}
+	state.IdentityProviderMappers = []v1alpha1.KeycloakIdentityProviderMapper{
+		{ID: "githubRoleID", Name: "role", IdentityProviderAlias: "github", IdentityProviderMapper: "hardcoded-role-idp-mapper"},
+		{ID: "githubRemovedID", Name: "removed", IdentityProviderAlias: "github", IdentityProviderMapper: "hardcoded-role-idp-mapper"},
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - delete mapper removed of github
+	// 2 - update mapper role of github
+	// 3 - create mapper role of google
+	assert.Len(t, desiredState, 4)
+	assert.Equal(t, "githubRemovedID", desiredState[1].(*common.DeleteIdentityProviderMapperAction).Mapper.ID)
+	updated := desiredState[2].(*common.UpdateIdentityProviderMapperAction).Mapper
+	assert.Equal(t, "githubRoleID", updated.ID)
+	assert.Equal(t, map[string]string{"role": "user"}, updated.Config)
+	assert.Equal(t, "google", desiredState[3].(*common.CreateIdentityProviderMapperAction).Mapper.IdentityProviderAlias)
+
+	// when the mapper is up to date, apart from the defaults keycloak added
+	state.IdentityProviderMappers[0].Config = map[string]string{"role": "user", "syncMode": "INHERIT"}
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - delete mapper removed of github
+	// 2 - create mapper role of google
+	assert.Len(t, desiredState, 3)
+	assert.IsType(t, &common.CreateIdentityProviderMapperAction{}, desiredState[2])
+}
+
+func TestKeycloakRealmReconciler_ReconcileIdentityProviderMappers_Provider_Removed(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Realm.IdentityProviders = []*v1alpha1.KeycloakIdentityProvider{}
+	realm.Spec.Realm.IdentityProviderMappers = []v1alpha1.KeycloakIdentityProviderMapper{}
+
+	state := getDummyState()
+	state.Realm = realm
+	state.IdentityProviders = []*v1alpha1.KeycloakIdentityProvider{
+		{Alias: "github"},
+	}
+	state.IdentityProviderMappers = []v1alpha1.KeycloakIdentityProviderMapper{
+		{ID: "githubRoleID", Name: "role", IdentityProviderAlias: "github"},
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - delete identity provider github, which takes its mappers along
+	assert.Len(t, desiredState, 2)
+	assert.IsType(t, &common.DeleteIdentityProviderAction{}, desiredState[1])
+}
+
+func TestKeycloakRealmReconciler_ReconcileAuthenticationFlows(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Realm.BrowserFlow = "changed"
+	realm.Spec.Realm.AuthenticationFlows = []v1alpha1.KeycloakAPIAuthenticationFlow{
+		{
+			Alias:      "unchanged",
+			ProviderID: "basic-flow",
+			TopLevel:   true,
+			AuthenticationExecutions: []v1alpha1.KeycloakAPIAuthenticationExecution{
+				{Authenticator: "auth-cookie", Requirement: "ALTERNATIVE", Priority: 10},
+			},
+		},
+		{
+			Alias:      "changed",
+			ProviderID: "basic-flow",
+			TopLevel:   true,
+			AuthenticationExecutions: []v1alpha1.KeycloakAPIAuthenticationExecution{
+				{Authenticator: "auth-cookie", Requirement: "ALTERNATIVE", Priority: 10},
+				{AuthenticatorFlow: true, FlowAlias: "forms", Requirement: "ALTERNATIVE", Priority: 20},
+			},
+		},
+		{
+			Alias:      "forms",
+			ProviderID: "basic-flow",
+			AuthenticationExecutions: []v1alpha1.KeycloakAPIAuthenticationExecution{
+				{Authenticator: "auth-username-password-form", Requirement: "REQUIRED", Priority: 10},
+			},
+		},
+		{
+			Alias:      "created",
+			ProviderID: "basic-flow",
+			TopLevel:   true,
+		},
+		{
+			Alias:    "browser",
+			TopLevel: true,
+		},
+	}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.Realm.Spec.Realm.BrowserFlow = "browser"
+	state.AuthenticationFlows = []*v1alpha1.KeycloakAPIAuthenticationFlow{
+		{ID: "browserID", Alias: "browser", TopLevel: true, BuiltIn: true},
+		{ID: "unchangedID", Alias: "unchanged", TopLevel: true},
+		{ID: "changedID", Alias: "changed", TopLevel: true},
+		{ID: "removedID", Alias: "removed", TopLevel: true},
+	}
+	state.AuthenticationExecutions = map[string][]*v1alpha1.AuthenticationExecutionInfo{
+		"unchanged": {
+			{ProviderID: "auth-cookie", Requirement: "ALTERNATIVE"},
+		},
+		"changed": {
+			{ProviderID: "auth-cookie", Requirement: "ALTERNATIVE"},
+			{AuthenticationFlow: true, DisplayName: "forms", Requirement: "ALTERNATIVE"},
+			{ProviderID: "auth-username-password-form", Requirement: "OPTIONAL", Level: 1},
+		},
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - replace flow changed, the requirement of a sub flow execution differs
+	// 2 - create flow created
+	// 3 - bind flow changed to browser authentication
+	// 4 - delete flow removed
+	assert.Len(t, desiredState, 5)
+	replaced := desiredState[1].(*common.ReplaceAuthenticationFlowAction)
+	assert.Equal(t, "changed", replaced.Flow.Alias)
+	assert.Equal(t, "changedID", replaced.Existing.ID)
+	created := desiredState[2].(*common.ReplaceAuthenticationFlowAction)
+	assert.Equal(t, "created", created.Flow.Alias)
+	assert.Nil(t, created.Existing)
+	assert.IsType(t, &common.UpdateRealmFlowBindingsAction{}, desiredState[3])
+	assert.Equal(t, "removedID", desiredState[4].(*common.DeleteAuthenticationFlowAction).Flow.ID)
+}
+
+func TestKeycloakRealmReconciler_ReconcileAuthenticationFlows_Realm_Missing(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Realm.BrowserFlow = "created"
+	realm.Spec.Realm.AuthenticationFlows = []v1alpha1.KeycloakAPIAuthenticationFlow{
+		{Alias: "created", ProviderID: "basic-flow", TopLevel: true},
+	}
+
+	state := getDummyState()
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - create realm, which imports its flows and bindings
+	assert.Len(t, desiredState, 2)
+	assert.IsType(t, &common.CreateRealmAction{}, desiredState[1])
+}
+
+func TestKeycloakRealmReconciler_ReconcileSMTPServer(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Realm.SMTPServer = map[string]string{"host": "smtp.example.com", "from": "sso@example.com"}
+	realm.Spec.SMTPServerSecrets = map[string]v12.SecretKeySelector{
+		"user":     {LocalObjectReference: v12.LocalObjectReference{Name: "smtp"}, Key: "user"},
+		"password": {LocalObjectReference: v12.LocalObjectReference{Name: "smtp"}, Key: "password"},
+	}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.Realm.Spec.Realm.SMTPServer = map[string]string{"host": "smtp.example.com", "from": "sso@example.com", "user": "sso", "password": "**********"}
+	state.SMTPServerSecrets = map[string]string{"user": "sso", "password": "secret"}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - update smtp server, the password was never pushed
+	assert.Len(t, desiredState, 2)
+	action := desiredState[1].(*common.UpdateRealmAction)
+	assert.Equal(t, "secret", action.Realm.SMTPServer["password"])
+	assert.Equal(t, "sso", action.Realm.SMTPServer["user"])
+	assert.True(t, action.Realm.Enabled)
+
+	// when the update was applied
+	realm.Status.SMTPServerHash = action.SMTPServerHash
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 1)
+
+	// when the password changes
+	state.SMTPServerSecrets["password"] = "changed"
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 2)
+	assert.Equal(t, "changed", desiredState[1].(*common.UpdateRealmAction).Realm.SMTPServer["password"])
+
+	// when
+	otherRealm := realm.DeepCopy()
+	otherRealm.UID = "other-uid"
+	otherHash := reconciler.Reconcile(state, otherRealm)[1].(*common.UpdateRealmAction).SMTPServerHash
+
+	// then
+	// the hash of the same settings is different in another realm
+	assert.NotEqual(t, desiredState[1].(*common.UpdateRealmAction).SMTPServerHash, otherHash)
+}
+
+func TestKeycloakRealmReconciler_ReconcileSMTPServer_Realm_Missing(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.SMTPServerSecrets = map[string]v12.SecretKeySelector{
+		"password": {LocalObjectReference: v12.LocalObjectReference{Name: "smtp"}, Key: "password"},
+	}
+
+	state := getDummyState()
+	state.SMTPServerSecrets = map[string]string{"password": "secret"}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - create realm
+	// 2 - update smtp server with the settings from secrets
+	assert.Len(t, desiredState, 3)
+	assert.IsType(t, &common.CreateRealmAction{}, desiredState[1])
+	assert.Equal(t, "secret", desiredState[2].(*common.UpdateRealmAction).Realm.SMTPServer["password"])
+}
+
+func TestKeycloakRealmReconciler_ReconcilePasswordAndOTPPolicies(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Realm.PasswordPolicy = "length(12) and notUsername"
+	realm.Spec.Realm.OTPPolicyType = "totp"
+	realm.Spec.Realm.OTPPolicyDigits = &[]int32{8}[0]
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.Realm.Spec.Realm.PasswordPolicy = "length(8)"
+	state.Realm.Spec.Realm.OTPPolicyType = "totp"
+	state.Realm.Spec.Realm.OTPPolicyDigits = &[]int32{6}[0]
+	state.Realm.Spec.Realm.OTPPolicyPeriod = &[]int32{30}[0]
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - update the changed policies only
+	assert.Len(t, desiredState, 2)
+	update := desiredState[1].(*common.UpdateRealmAction).Realm
+	assert.Equal(t, "length(12) and notUsername", update.PasswordPolicy)
+	assert.Equal(t, int32(8), *update.OTPPolicyDigits)
+	assert.Equal(t, "", update.OTPPolicyType)
+	assert.Nil(t, update.OTPPolicyPeriod)
+
+	// when keycloak is up to date
+	state.Realm.Spec.Realm.PasswordPolicy = realm.Spec.Realm.PasswordPolicy
+	state.Realm.Spec.Realm.OTPPolicyDigits = &[]int32{8}[0]
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 1)
+}
+
+func TestKeycloakRealmReconciler_ReconcileSslRequired(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Realm.SslRequired = v1alpha1.SslRequiredNone
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.Realm.Spec.Realm.SslRequired = v1alpha1.SslRequiredExternal
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - update ssl required
+	assert.Len(t, desiredState, 2)
+	assert.Equal(t, v1alpha1.SslRequiredNone, desiredState[1].(*common.UpdateRealmAction).Realm.SslRequired)
+
+	// when keycloak is up to date
+	state.Realm.Spec.Realm.SslRequired = v1alpha1.SslRequiredNone
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 1)
+}
+
+func TestKeycloakRealmReconciler_ReconcileBruteForceDetection(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Realm.BruteForceProtected = &[]bool{true}[0]
+	realm.Spec.Realm.FailureFactor = &[]int32{5}[0]
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.Realm.Spec.Realm.BruteForceProtected = &[]bool{false}[0]
+	state.Realm.Spec.Realm.FailureFactor = &[]int32{30}[0]
+	state.Realm.Spec.Realm.MaxFailureWaitSeconds = &[]int32{900}[0]
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - turn on brute force detection, unset settings are left as they are
+	assert.Len(t, desiredState, 2)
+	update := desiredState[1].(*common.UpdateRealmAction).Realm
+	assert.True(t, *update.BruteForceProtected)
+	assert.Equal(t, int32(5), *update.FailureFactor)
+	assert.Nil(t, update.MaxFailureWaitSeconds)
+
+	// when keycloak returns the applied settings along with its own values for the others
+	state.Realm.Spec.Realm = &v1alpha1.KeycloakAPIRealm{
+		ID:                           "dummy",
+		Realm:                        "dummy",
+		BruteForceProtected:          &[]bool{true}[0],
+		PermanentLockout:             &[]bool{false}[0],
+		FailureFactor:                &[]int32{5}[0],
+		WaitIncrementSeconds:         &[]int32{60}[0],
+		QuickLoginCheckMilliSeconds:  &[]int64{1000}[0],
+		MinimumQuickLoginWaitSeconds: &[]int32{60}[0],
+		MaxFailureWaitSeconds:        &[]int32{900}[0],
+		MaxDeltaTimeSeconds:          &[]int32{43200}[0],
+	}
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 1)
+
+	// when brute force detection is turned off
+	realm.Spec.Realm.BruteForceProtected = &[]bool{false}[0]
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	// 1 - turn off brute force detection and reset the failure factor to the default
+	assert.Len(t, desiredState, 2)
+	update = desiredState[1].(*common.UpdateRealmAction).Realm
+	assert.False(t, *update.BruteForceProtected)
+	assert.Equal(t, int32(30), *update.FailureFactor)
+
+	// when keycloak applied it
+	state.Realm.Spec.Realm.BruteForceProtected = &[]bool{false}[0]
+	state.Realm.Spec.Realm.FailureFactor = &[]int32{30}[0]
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 1)
+}
+
+func TestKeycloakRealmReconciler_ReconcileTokenSettings(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.TokenSettings = &v1alpha1.KeycloakRealmTokenSettings{
+		AccessTokenLifespan:   &v1.Duration{Duration: 5 * time.Minute},
+		SSOSessionMaxLifespan: &v1.Duration{Duration: 10 * time.Hour},
+		RevokeRefreshToken:    &[]bool{true}[0],
+	}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.Realm.Spec.Realm.AccessTokenLifespan = &[]int32{60}[0]
+	state.Realm.Spec.Realm.SsoSessionIdleTimeout = &[]int32{1800}[0]
+	state.Realm.Spec.Realm.SsoSessionMaxLifespan = &[]int32{36000}[0]
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - update the changed token settings, in seconds
+	assert.Len(t, desiredState, 2)
+	update := desiredState[1].(*common.UpdateRealmAction).Realm
+	assert.Equal(t, int32(300), *update.AccessTokenLifespan)
+	assert.True(t, *update.RevokeRefreshToken)
+	assert.Nil(t, update.SsoSessionMaxLifespan)
+	assert.Nil(t, update.SsoSessionIdleTimeout)
+}
+
+func TestKeycloakRealmReconciler_ReconcileTokenSettings_Realm_Missing(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.TokenSettings = &v1alpha1.KeycloakRealmTokenSettings{
+		AccessTokenLifespan: &v1.Duration{Duration: 5 * time.Minute},
+	}
+
+	state := getDummyState()
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - create realm
+	// 2 - update token settings, which aren't imported
+	assert.Len(t, desiredState, 3)
+	assert.Equal(t, int32(300), *desiredState[2].(*common.UpdateRealmAction).Realm.AccessTokenLifespan)
+}
+
+func TestKeycloakRealmReconciler_ReconcileLoginSettings(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.LoginSettings = &v1alpha1.KeycloakRealmLoginSettings{
+		RegistrationAllowed: &[]bool{false}[0],
+		VerifyEmail:         &[]bool{true}[0],
+	}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.Realm.Spec.Realm.RegistrationAllowed = &[]bool{true}[0]
+	state.Realm.Spec.Realm.VerifyEmail = &[]bool{true}[0]
+	state.Realm.Spec.Realm.RememberMe = &[]bool{true}[0]
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - turn off the registration, unset settings are left as they are
+	assert.Len(t, desiredState, 2)
+	update := desiredState[1].(*common.UpdateRealmAction).Realm
+	assert.False(t, *update.RegistrationAllowed)
+	assert.Nil(t, update.VerifyEmail)
+	assert.Nil(t, update.RememberMe)
+
+	// when the settings match
+	state.Realm.Spec.Realm.RegistrationAllowed = &[]bool{false}[0]
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 1)
+}
+
+func TestKeycloakRealmReconciler_ReconcileThemes(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Themes = &v1alpha1.KeycloakRealmThemes{
+		LoginTheme: "branded",
+		EmailTheme: "missing",
+	}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.Realm.Spec.Realm.LoginTheme = "keycloak"
+	state.AvailableThemes = map[string][]string{
+		"login": {"keycloak", "branded"},
+		"email": {"keycloak"},
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - set the login theme, the missing email theme is left out
+	assert.Len(t, desiredState, 2)
+	update := desiredState[1].(*common.UpdateRealmAction).Realm
+	assert.Equal(t, "branded", update.LoginTheme)
+	assert.Empty(t, update.EmailTheme)
+	assert.Equal(t, map[string]string{"email": "email theme missing is not available in keycloak, it is not set"},
+		common.UnavailableRealmThemes(realm.Spec.Themes, state.AvailableThemes))
+
+	// when keycloak doesn't tell which themes it has
+	state.AvailableThemes = nil
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Equal(t, "missing", desiredState[1].(*common.UpdateRealmAction).Realm.EmailTheme)
+}
+
+func TestKeycloakRealmReconciler_ReconcileEvents(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Events = &v1alpha1.KeycloakAPIRealmEventsConfig{
+		EventsEnabled:     &[]bool{true}[0],
+		EventsListeners:   []string{"jboss-logging"},
+		EnabledEventTypes: []string{"LOGIN", "LOGIN_ERROR", "LOGOUT"},
+	}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.EventsConfig = &v1alpha1.KeycloakAPIRealmEventsConfig{
+		EventsEnabled:      &[]bool{true}[0],
+		EventsListeners:    []string{"jboss-logging"},
+		EnabledEventTypes:  []string{"LOGOUT", "LOGIN", "LOGIN_ERROR"},
+		AdminEventsEnabled: &[]bool{false}[0],
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available, the event types only differ in order
+	assert.Len(t, desiredState, 1)
+
+	// when an event type is added
+	realm.Spec.Events.EnabledEventTypes = append(realm.Spec.Events.EnabledEventTypes, "REGISTER")
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	// 1 - configure events, admin events are left as they are
+	assert.Len(t, desiredState, 2)
+	config := desiredState[1].(*common.ConfigureRealmEventsAction).Config
+	assert.Equal(t, realm.Spec.Events.EnabledEventTypes, config.EnabledEventTypes)
+	assert.False(t, *config.AdminEventsEnabled)
+}
+
+func TestKeycloakRealmReconciler_ReconcileEvents_Partial(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Events = &v1alpha1.KeycloakAPIRealmEventsConfig{
+		AdminEventsEnabled: &[]bool{true}[0],
+	}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.EventsConfig = &v1alpha1.KeycloakAPIRealmEventsConfig{
+		EventsEnabled:      &[]bool{true}[0],
+		EventsExpiration:   &[]int64{3600}[0],
+		EventsListeners:    []string{"jboss-logging"},
+		EnabledEventTypes:  []string{"LOGIN"},
+		AdminEventsEnabled: &[]bool{false}[0],
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - configure events, the settings the CR leaves out keep their current values
+	assert.Len(t, desiredState, 2)
+	config := desiredState[1].(*common.ConfigureRealmEventsAction).Config
+	assert.True(t, *config.AdminEventsEnabled)
+	assert.True(t, *config.EventsEnabled)
+	assert.Equal(t, int64(3600), *config.EventsExpiration)
+	assert.Equal(t, []string{"jboss-logging"}, config.EventsListeners)
+	assert.Equal(t, []string{"LOGIN"}, config.EnabledEventTypes)
+	assert.False(t, *state.EventsConfig.AdminEventsEnabled)
+}
+
+func TestKeycloakRealmReconciler_ReconcileUserFederationProviders(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Realm.UserFederationProviders = []v1alpha1.KeycloakAPIUserFederationProvider{
+		{
+			DisplayName:  "ldap",
+			ProviderName: "ldap",
+			Config:       map[string]string{"connectionUrl": "ldap://ldap:389", "bindDn": "cn=admin"},
+			ConfigSecrets: map[string]v12.SecretKeySelector{
+				"bindCredential": {LocalObjectReference: v12.LocalObjectReference{Name: "ldap"}, Key: "password"},
+			},
+		},
+		{
+			DisplayName:  "kerberos",
+			ProviderName: "kerberos",
+		},
+	}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.UserFederationSecrets = map[string]map[string]string{"ldap": {"bindCredential": "secret"}}
+	state.UserFederationProviders = []v1alpha1.KeycloakAPIComponent{
+		{ID: "ldapID", Name: "ldap", ProviderID: "ldap", ParentID: "dummy", Config: map[string][]string{
+			"connectionUrl":  {"ldap://ldap:389"},
+			"bindDn":         {"cn=admin"},
+			"bindCredential": {"**********"},
+			"cachePolicy":    {"DEFAULT"},
+		}},
+		{ID: "removedID", Name: "removed", ProviderID: "ldap", ParentID: "dummy"},
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - delete provider removed
+	// 2 - update provider ldap, its bind credential was never pushed
+	// 3 - create provider kerberos
+	assert.Len(t, desiredState, 4)
+	assert.Equal(t, "removedID", desiredState[1].(*common.DeleteUserFederationProviderAction).Provider.ID)
+	updated := desiredState[2].(*common.UpdateUserFederationProviderAction)
+	assert.Equal(t, "ldapID", updated.Provider.ID)
+	assert.Equal(t, []string{"secret"}, updated.Provider.Config["bindCredential"])
+	assert.Equal(t, []string{"DEFAULT"}, updated.Provider.Config["cachePolicy"])
+	created := desiredState[3].(*common.CreateUserFederationProviderAction)
+	assert.Equal(t, "dummy", created.Provider.ParentID)
+	assert.Equal(t, "", created.SecretHash)
+
+	// when the bind credential was applied
+	realm.Status.UserFederationSecretHashes = map[string]string{"ldap": updated.SecretHash}
+	state.UserFederationProviders = state.UserFederationProviders[:1]
+	state.UserFederationProviders = append(state.UserFederationProviders, v1alpha1.KeycloakAPIComponent{ID: "kerberosID", Name: "kerberos", ProviderID: "kerberos", ParentID: "dummy"})
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 1)
+}
+
+func TestKeycloakRealmReconciler_ReconcileUserFederationMappers(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Realm.UserFederationMappers = []v1alpha1.KeycloakAPIUserFederationMapper{
+		{Name: "email", FederationProviderDisplayName: "ldap", FederationMapperType: "user-attribute-ldap-mapper", Config: map[string]string{"ldap.attribute": "mail"}},
+		{Name: "groups", FederationProviderDisplayName: "ldap", FederationMapperType: "group-ldap-mapper"},
+	}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.UserFederationProviders = []v1alpha1.KeycloakAPIComponent{
+		{ID: "ldapID", Name: "ldap", ProviderID: "ldap"},
+	}
+	state.UserFederationMappers = []v1alpha1.KeycloakAPIComponent{
+		{ID: "emailID", Name: "email", ProviderID: "user-attribute-ldap-mapper", ParentID: "ldapID", Config: map[string][]string{"ldap.attribute": {"email"}}},
+		{ID: "modifyDateID", Name: "modify date", ProviderID: "user-attribute-ldap-mapper", ParentID: "ldapID"},
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - delete mapper modify date
+	// 2 - update mapper email
+	// 3 - create mapper groups
+	assert.Len(t, desiredState, 4)
+	assert.Equal(t, "modifyDateID", desiredState[1].(*common.DeleteUserFederationMapperAction).Mapper.ID)
+	updated := desiredState[2].(*common.UpdateUserFederationMapperAction).Mapper
+	assert.Equal(t, "emailID", updated.ID)
+	assert.Equal(t, []string{"mail"}, updated.Config["ldap.attribute"])
+	created := desiredState[3].(*common.CreateUserFederationMapperAction)
+	assert.Equal(t, "ldap", created.ProviderName)
+	assert.Equal(t, "groups", created.Mapper.Name)
+}
+
+func TestKeycloakRealmReconciler_ReconcileUserFederationSync(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Annotations = map[string]string{model.SyncUserFederationAnnotation: "ldap"}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - sync all users of provider ldap
+	assert.Len(t, desiredState, 2)
+	action := desiredState[1].(*common.SyncUserFederationAction)
+	assert.Equal(t, "ldap", action.Provider)
+	assert.True(t, action.Full)
+
+	// when only changed users are asked for
+	realm.Annotations[model.SyncUserFederationModeAnnotation] = "changed"
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.False(t, desiredState[1].(*common.SyncUserFederationAction).Full)
+
+	// when the annotation was removed
+	realm.Annotations = nil
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 1)
+}
+
+func TestKeycloakRealmReconciler_ReconcileRequiredActions(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.RequiredActions = []v1alpha1.KeycloakAPIRequiredAction{
+		{Alias: "CONFIGURE_TOTP", Enabled: &[]bool{true}[0], DefaultAction: &[]bool{true}[0]},
+		{Alias: "VERIFY_EMAIL", Enabled: &[]bool{true}[0]},
+		{Alias: "custom-action", Name: "Custom Action", Priority: &[]int32{100}[0]},
+	}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.RequiredActions = []v1alpha1.KeycloakAPIRequiredAction{
+		{Alias: "CONFIGURE_TOTP", Name: "Configure OTP", Enabled: &[]bool{true}[0], DefaultAction: &[]bool{false}[0], Priority: &[]int32{10}[0]},
+		{Alias: "VERIFY_EMAIL", Name: "Verify Email", Enabled: &[]bool{true}[0], DefaultAction: &[]bool{false}[0], Priority: &[]int32{50}[0]},
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - make configuring OTP a default action, keeping the other settings
+	// 2 - register the custom required action
+	assert.Len(t, desiredState, 3)
+	update := desiredState[1].(*common.UpdateRequiredActionAction)
+	assert.False(t, update.Register)
+	assert.Equal(t, "Configure OTP", update.Action.Name)
+	assert.True(t, *update.Action.DefaultAction)
+	assert.Equal(t, int32(10), *update.Action.Priority)
+	register := desiredState[2].(*common.UpdateRequiredActionAction)
+	assert.True(t, register.Register)
+	assert.Equal(t, "custom-action", register.Action.Alias)
+
+	// when keycloak applied them
+	state.RequiredActions[0].DefaultAction = &[]bool{true}[0]
+	state.RequiredActions = append(state.RequiredActions, v1alpha1.KeycloakAPIRequiredAction{
+		Alias: "custom-action", Name: "Custom Action", Enabled: &[]bool{true}[0], DefaultAction: &[]bool{false}[0], Priority: &[]int32{100}[0],
+	})
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 1)
+}
+
+func TestKeycloakRealmReconciler_ReconcileClientPolicies(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.ClientProfiles = []v1alpha1.KeycloakAPIClientProfile{{
+		Name: "fapi",
+		Executors: []v1alpha1.KeycloakAPIClientPolicyExecutor{{
+			Executor:      "pkce-enforcer",
+			Configuration: &runtime.RawExtension{Raw: []byte(`{"auto-configure":"true"}`)},
+		}},
+	}}
+	realm.Spec.ClientPolicies = []v1alpha1.KeycloakAPIClientPolicy{{
+		Name:     "open-banking",
+		Enabled:  &[]bool{true}[0],
+		Profiles: []string{"fapi"},
+		Conditions: []v1alpha1.KeycloakAPIClientPolicyCondition{{
+			Condition:     "client-roles",
+			Configuration: &runtime.RawExtension{Raw: []byte(`{"roles":["open-banking"]}`)},
+		}},
+	}}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - replace the client profiles
+	// 2 - replace the client policies
+	assert.Len(t, desiredState, 3)
+	profiles := desiredState[1].(*common.UpdateClientProfilesAction)
+	assert.Equal(t, "fapi", profiles.Profiles[0].Name)
+	policies := desiredState[2].(*common.UpdateClientPoliciesAction)
+	assert.Equal(t, "open-banking", policies.Policies[0].Name)
+
+	// when keycloak has them, with the configuration formatted differently
+	state.ClientProfiles = []v1alpha1.KeycloakAPIClientProfile{{
+		Name: "fapi",
+		Executors: []v1alpha1.KeycloakAPIClientPolicyExecutor{{
+			Executor:      "pkce-enforcer",
+			Configuration: &runtime.RawExtension{Raw: []byte(`{ "auto-configure": "true" }`)},
+		}},
+	}}
+	state.ClientPolicies = []v1alpha1.KeycloakAPIClientPolicy{{
+		Name:     "open-banking",
+		Enabled:  &[]bool{true}[0],
+		Profiles: []string{"fapi"},
+		Conditions: []v1alpha1.KeycloakAPIClientPolicyCondition{{
+			Condition:     "client-roles",
+			Configuration: &runtime.RawExtension{Raw: []byte(`{"roles":["open-banking"]}`)},
+		}},
+	}}
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 1)
+
+	// when keycloak lost the profiles, e.g. as the realm was recreated
+	state.ClientProfiles = nil
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 2)
+	assert.IsType(t, &common.UpdateClientProfilesAction{}, desiredState[1])
+	state.ClientProfiles = profiles.Profiles
+
+	// when a policy changes
+	realm.Spec.ClientPolicies[0].Enabled = &[]bool{false}[0]
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 2)
+	assert.IsType(t, &common.UpdateClientPoliciesAction{}, desiredState[1])
+}
+
+func TestKeycloakRealmReconciler_ReconcileLocalization(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.Realm.InternationalizationEnabled = &[]bool{true}[0]
+	realm.Spec.Realm.SupportedLocales = []string{"en", "de"}
+	realm.Spec.Localization = map[string]map[string]string{
+		"en": {"loginTitle": "Sign in to ACME", "doLogIn": "Sign in"},
+		"de": {"loginTitle": "Bei ACME anmelden"},
+	}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.Realm.Spec.Realm.InternationalizationEnabled = &[]bool{true}[0]
+	state.Realm.Spec.Realm.SupportedLocales = []string{"de", "en"}
+	state.Localization = map[string]map[string]string{
+		"en": {"loginTitle": "Sign in to ACME", "doLogIn": "Log in", "legacy": "Old text"},
+		"de": {"loginTitle": "Bei ACME anmelden"},
+		"fr": {"loginTitle": "Connexion"},
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - update and remove the changed keys of en, de is left as it is
+	// 2 - remove the overrides of fr, which isn't listed
+	assert.Len(t, desiredState, 3)
+	en := desiredState[1].(*common.UpdateRealmLocalizationAction)
+	assert.Equal(t, "en", en.Locale)
+	assert.Equal(t, map[string]string{"doLogIn": "Sign in"}, en.Texts)
+	assert.Equal(t, []string{"legacy"}, en.Removed)
+	fr := desiredState[2].(*common.UpdateRealmLocalizationAction)
+	assert.Equal(t, "fr", fr.Locale)
+	assert.Empty(t, fr.Texts)
+	assert.Equal(t, []string{"loginTitle"}, fr.Removed)
+
+	// when keycloak applied them
+	state.Localization = realm.Spec.Localization
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 1)
+
+	// when another locale is supported
+	realm.Spec.Realm.SupportedLocales = []string{"en", "de", "fr"}
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 2)
+	update := desiredState[1].(*common.UpdateRealmAction).Realm
+	assert.Equal(t, []string{"en", "de", "fr"}, update.SupportedLocales)
+	assert.Nil(t, update.InternationalizationEnabled)
+}
+
+func TestKeycloakRealmReconciler_ReconcileRealmExport(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Annotations = map[string]string{model.ExportRealmAnnotation: "dummy-export"}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - export the realm with clients, groups and roles
+	assert.Len(t, desiredState, 2)
+	action := desiredState[1].(*common.ExportRealmAction)
+	assert.Equal(t, "dummy-export", action.SecretName)
+	assert.True(t, action.ExportClients)
+	assert.True(t, action.ExportGroupsAndRoles)
+
+	// when clients are left out
+	realm.Annotations[model.ExportRealmClientsAnnotation] = "false"
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.False(t, desiredState[1].(*common.ExportRealmAction).ExportClients)
+	assert.True(t, desiredState[1].(*common.ExportRealmAction).ExportGroupsAndRoles)
+
+	// when the annotation was removed
+	realm.Annotations = nil
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 1)
+}
+
+func TestKeycloakRealmReconciler_ReconcileRealmImport(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.RealmImport = &v1alpha1.KeycloakRealmImport{
+		ConfigMapKeyRef: &v12.ConfigMapKeySelector{
+			LocalObjectReference: v12.LocalObjectReference{Name: "realm-export"},
+			Key:                  "realm.json",
+		},
+	}
+
+	state := getDummyState()
+	state.RealmImport = []byte(`{"realm":"dummy","clients":[{"clientId":"legacy"}]}`)
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// the realm has to exist before anything is imported into it
+	assert.Len(t, desiredState, 2)
+	assert.IsType(t, &common.CreateRealmAction{}, desiredState[1])
+
+	// when
+	state.Realm = getDummyRealm()
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - import the realm JSON, skipping what exists
+	assert.Len(t, desiredState, 2)
+	action := desiredState[1].(*common.ImportRealmAction)
+	assert.Equal(t, "SKIP", action.IfResourceExists)
+	assert.Equal(t, state.RealmImport, action.RealmJSON)
+
+	// when the import was applied
+	realm.Status.RealmImportHash = action.Hash
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 1)
+
+	// when the policy changes
+	realm.Spec.RealmImport.Policy = v1alpha1.RealmImportOverwriteOnChange
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 2)
+	assert.Equal(t, "OVERWRITE", desiredState[1].(*common.ImportRealmAction).IfResourceExists)
+}
+
+func TestKeycloakRealmReconciler_ReconcileUserImport(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.UserImport = &v1alpha1.KeycloakRealmUserImport{
+		ConfigMapKeyRef: &v12.ConfigMapKeySelector{
+			LocalObjectReference: v12.LocalObjectReference{Name: "users"},
+			Key:                  "users.csv",
+		},
+	}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.UserImport = []byte("username,email,enabled,realmRoles,clientRoles,groups\n" +
+		"alice,alice@example.com,,user;admin,app:viewer;app:editor,/staff\n" +
+		"bob,bob@example.com,false,,,\n" +
+		",nobody@example.com,,,,\n" +
+		"carol,carol@example.com,,,viewer,\n")
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - import the users that could be read
+	assert.Len(t, desiredState, 2)
+	action := desiredState[1].(*common.ImportRealmUsersAction)
+	assert.Len(t, action.Users, 2)
+	assert.Equal(t, "alice", action.Users[0].UserName)
+	assert.True(t, action.Users[0].Enabled)
+	assert.Equal(t, []string{"user", "admin"}, action.Users[0].RealmRoles)
+	assert.Equal(t, map[string][]string{"app": {"viewer", "editor"}}, action.Users[0].ClientRoles)
+	assert.Equal(t, []string{"/staff"}, action.Users[0].Groups)
+	assert.False(t, action.Users[1].Enabled)
+
+	// rows without a username or with invalid values are reported as failed
+	assert.Len(t, action.Invalid, 2)
+	assert.Equal(t, "line 4: no username", action.Invalid[0].Message)
+	assert.Equal(t, "carol", action.Invalid[1].Username)
+	assert.Equal(t, v1alpha1.UserImportFailed, action.Invalid[1].Result)
+
+	// when the import was applied
+	realm.Status.UserImportHash = action.Hash
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 1)
+
+	// when the users are given as JSON
+	realm.Spec.UserImport.Format = v1alpha1.UserImportJSON
+	state.UserImport = []byte(`[{"username":"dave","enabled":true},{"email":"nobody@example.com"}]`)
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 2)
+	action = desiredState[1].(*common.ImportRealmUsersAction)
+	assert.Len(t, action.Users, 1)
+	assert.Equal(t, "dave", action.Users[0].UserName)
+	assert.Len(t, action.Invalid, 1)
+
+	// when a column is unknown
+	realm.Spec.UserImport.Format = ""
+	state.UserImport = []byte("username,password\nalice,secret\n")
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	// nothing is imported
+	action = desiredState[1].(*common.ImportRealmUsersAction)
+	assert.Empty(t, action.Users)
+	assert.Equal(t, "unknown CSV column password", action.Invalid[0].Message)
+}
+
+func TestKeycloakRealmReconciler_ReconcileWebAuthnPolicy(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	reconciler := NewKeycloakRealmReconciler(keycloak)
+
+	realm := getDummyRealm()
+	realm.Spec.RealmOverrides = nil
+	realm.Spec.Realm.Users = nil
+	realm.Spec.WebAuthnPasswordlessPolicy = &v1alpha1.KeycloakRealmWebAuthnPolicy{
+		RpEntityName:        "example",
+		SignatureAlgorithms: []string{"RS256", "ES256"},
+		RequireResidentKey:  "Yes",
+		CreateTimeout:       &[]int32{60}[0],
+	}
+
+	state := getDummyState()
+	state.Realm = getDummyRealm()
+	state.Realm.Spec.Realm.WebAuthnPolicyPasswordlessRpEntityName = "example"
+	state.Realm.Spec.Realm.WebAuthnPolicyPasswordlessSignatureAlgorithms = []string{"ES256"}
+	state.Realm.Spec.Realm.WebAuthnPolicyPasswordlessRequireResidentKey = v1alpha1.WebAuthnNotSpecified
+	state.Realm.Spec.Realm.WebAuthnPolicyRequireResidentKey = v1alpha1.WebAuthnNotSpecified
+
+	// when
+	desiredState := reconciler.Reconcile(state, realm)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - update the passwordless policy, the other policy is left as it is
+	assert.Len(t, desiredState, 2)
+	update := desiredState[1].(*common.UpdateRealmAction).Realm
+	assert.Equal(t, "", update.WebAuthnPolicyPasswordlessRpEntityName)
+	assert.ElementsMatch(t, []string{"ES256", "RS256"}, update.WebAuthnPolicyPasswordlessSignatureAlgorithms)
+	assert.Equal(t, "Yes", update.WebAuthnPolicyPasswordlessRequireResidentKey)
+	assert.Equal(t, int32(60), *update.WebAuthnPolicyPasswordlessCreateTimeout)
+	assert.Equal(t, "", update.WebAuthnPolicyRequireResidentKey)
+
+	// when the policy matches
+	state.Realm.Spec.Realm.WebAuthnPolicyPasswordlessSignatureAlgorithms = []string{"ES256", "RS256"}
+	state.Realm.Spec.Realm.WebAuthnPolicyPasswordlessRequireResidentKey = "Yes"
+	state.Realm.Spec.Realm.WebAuthnPolicyPasswordlessCreateTimeout = &[]int32{60}[0]
+	desiredState = reconciler.Reconcile(state, realm)
+
+	// then
+	assert.Len(t, desiredState, 1)
+}
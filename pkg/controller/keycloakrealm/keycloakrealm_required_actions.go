@@ -0,0 +1,48 @@
+package keycloakrealm
+
+import (
+	"fmt"
+	"reflect"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/common"
+)
+
+// Required actions are matched by alias. Those keycloak doesn't know are registered,
+// which only works for actions of installed providers
+func (i *KeycloakRealmReconciler) ReconcileRequiredActions(state *common.RealmState, cr *kc.KeycloakRealm, desired *common.DesiredClusterState) {
+	if cr.Spec.RequiredActions == nil || state.Realm == nil {
+		return
+	}
+
+	for index := range cr.Spec.RequiredActions {
+		action := &cr.Spec.RequiredActions[index]
+		existing := common.FindRequiredAction(state.RequiredActions, action.Alias)
+		if existing == nil {
+			desired.AddAction(i.getRegisteredRequiredActionState(cr, action))
+			continue
+		}
+
+		reconciled := common.RequiredActionReconciled(action, existing)
+		if !reflect.DeepEqual(reconciled, existing) {
+			desired.AddAction(i.getUpdatedRequiredActionState(cr, reconciled))
+		}
+	}
+}
+
+func (i *KeycloakRealmReconciler) getRegisteredRequiredActionState(cr *kc.KeycloakRealm, action *kc.KeycloakAPIRequiredAction) common.ClusterAction {
+	return &common.UpdateRequiredActionAction{
+		Action:   action,
+		Register: true,
+		Ref:      cr,
+		Msg:      fmt.Sprintf("register required action %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, action.Alias),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getUpdatedRequiredActionState(cr *kc.KeycloakRealm, action *kc.KeycloakAPIRequiredAction) common.ClusterAction {
+	return &common.UpdateRequiredActionAction{
+		Action: action,
+		Ref:    cr,
+		Msg:    fmt.Sprintf("update required action %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, action.Alias),
+	}
+}
@@ -0,0 +1,416 @@
+package keycloakrealm
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/common"
+	"github.com/keycloak/keycloak-operator/pkg/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Push the realm settings set in the CR that differ from keycloak. New realms get
+// them through the import, except for the ones that come from secrets
+func (i *KeycloakRealmReconciler) getDesiredRealmSettingsState(state *common.RealmState, cr *kc.KeycloakRealm) common.ClusterAction {
+	if cr.DeletionTimestamp != nil {
+		return nil
+	}
+
+	// enabled and display name aren't omitted when empty, so they are sent as they are
+	current := cr.Spec.Realm
+	if state.Realm != nil {
+		current = state.Realm.Spec.Realm
+	}
+	update := &kc.KeycloakAPIRealm{
+		ID:          current.ID,
+		Realm:       cr.Spec.Realm.Realm,
+		Enabled:     current.Enabled,
+		DisplayName: current.DisplayName,
+	}
+
+	changed := false
+	if state.Realm != nil {
+		changed = diffRealmSettings(update, cr.Spec.Realm, current)
+	}
+
+	// Token settings aren't part of the import
+	if cr.Spec.TokenSettings != nil {
+		existing := &kc.KeycloakAPIRealm{}
+		if state.Realm != nil {
+			existing = current
+		}
+		changed = diffTokenSettings(update, cr.Spec.TokenSettings, existing) || changed
+	}
+
+	// Neither are the login settings
+	if cr.Spec.LoginSettings != nil {
+		existing := &kc.KeycloakAPIRealm{}
+		if state.Realm != nil {
+			existing = current
+		}
+		changed = diffLoginSettings(update, cr.Spec.LoginSettings, existing) || changed
+	}
+
+	// Nor are the themes, the ones keycloak doesn't have are left out
+	if cr.Spec.Themes != nil {
+		existing := &kc.KeycloakAPIRealm{}
+		if state.Realm != nil {
+			existing = current
+		}
+		unavailable := common.UnavailableRealmThemes(cr.Spec.Themes, state.AvailableThemes)
+		changed = diffThemes(update, cr.Spec.Themes, unavailable, existing) || changed
+	}
+
+	// Nor are the WebAuthn policies
+	for _, passwordless := range []bool{false, true} {
+		policy := cr.Spec.WebAuthnPolicy
+		if passwordless {
+			policy = cr.Spec.WebAuthnPasswordlessPolicy
+		}
+		if policy == nil {
+			continue
+		}
+		existing := &kc.KeycloakAPIRealm{}
+		if state.Realm != nil {
+			existing = current
+		}
+		changed = diffWebAuthnPolicy(webAuthnPolicyOf(update, passwordless), policy, webAuthnPolicyOf(existing, passwordless)) || changed
+	}
+
+	// The password keycloak returns is masked, so its changes are told by the hash in the status
+	var hash string
+	if cr.Spec.SMTPServerSecrets != nil || (cr.Spec.Realm.SMTPServer != nil && state.Realm != nil) {
+		smtpServer := getResolvedSMTPServer(state, cr)
+		hash = smtpServerHash(cr, smtpServer)
+		if state.Realm == nil || !smtpServerMatches(smtpServer, current.SMTPServer) || hash != cr.Status.SMTPServerHash {
+			update.SMTPServer = smtpServer
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return &common.UpdateRealmAction{
+		Realm:          update,
+		SMTPServerHash: hash,
+		Ref:            cr,
+		Msg:            fmt.Sprintf("update settings of realm %v/%v", cr.Namespace, cr.Spec.Realm.Realm),
+	}
+}
+
+// Copy the settings set in desired that differ from current to update, reporting
+// whether there were any. Unset settings are left as they are in keycloak
+func diffRealmSettings(update, desired, current *kc.KeycloakAPIRealm) bool {
+	changed := diffString(&update.PasswordPolicy, desired.PasswordPolicy, current.PasswordPolicy)
+	changed = diffString(&update.OTPPolicyType, desired.OTPPolicyType, current.OTPPolicyType) || changed
+	changed = diffString(&update.OTPPolicyAlgorithm, desired.OTPPolicyAlgorithm, current.OTPPolicyAlgorithm) || changed
+	changed = diffInt32(&update.OTPPolicyDigits, desired.OTPPolicyDigits, current.OTPPolicyDigits) || changed
+	changed = diffInt32(&update.OTPPolicyPeriod, desired.OTPPolicyPeriod, current.OTPPolicyPeriod) || changed
+	changed = diffInt32(&update.OTPPolicyLookAheadWindow, desired.OTPPolicyLookAheadWindow, current.OTPPolicyLookAheadWindow) || changed
+	changed = diffInt32(&update.OTPPolicyInitialCounter, desired.OTPPolicyInitialCounter, current.OTPPolicyInitialCounter) || changed
+	changed = diffBruteForceDetection(update, desired, current) || changed
+	changed = diffBool(&update.InternationalizationEnabled, desired.InternationalizationEnabled, current.InternationalizationEnabled) || changed
+	changed = diffStringSet(&update.SupportedLocales, desired.SupportedLocales, current.SupportedLocales) || changed
+	changed = diffString(&update.DefaultLocale, desired.DefaultLocale, current.DefaultLocale) || changed
+	changed = diffString(&update.SslRequired, desired.SslRequired, current.SslRequired) || changed
+	return changed
+}
+
+func diffTokenSettings(update *kc.KeycloakAPIRealm, settings *kc.KeycloakRealmTokenSettings, current *kc.KeycloakAPIRealm) bool {
+	changed := diffInt32(&update.AccessTokenLifespan, durationSeconds(settings.AccessTokenLifespan), current.AccessTokenLifespan)
+	changed = diffInt32(&update.SsoSessionIdleTimeout, durationSeconds(settings.SSOSessionIdleTimeout), current.SsoSessionIdleTimeout) || changed
+	changed = diffInt32(&update.SsoSessionMaxLifespan, durationSeconds(settings.SSOSessionMaxLifespan), current.SsoSessionMaxLifespan) || changed
+	changed = diffInt32(&update.OfflineSessionIdleTimeout, durationSeconds(settings.OfflineSessionIdleTimeout), current.OfflineSessionIdleTimeout) || changed
+	changed = diffBool(&update.RevokeRefreshToken, settings.RevokeRefreshToken, current.RevokeRefreshToken) || changed
+	changed = diffInt32(&update.RefreshTokenMaxReuse, settings.RefreshTokenMaxReuse, current.RefreshTokenMaxReuse) || changed
+	return changed
+}
+
+func diffLoginSettings(update *kc.KeycloakAPIRealm, settings *kc.KeycloakRealmLoginSettings, current *kc.KeycloakAPIRealm) bool {
+	changed := diffBool(&update.RegistrationAllowed, settings.RegistrationAllowed, current.RegistrationAllowed)
+	changed = diffBool(&update.RegistrationEmailAsUsername, settings.RegistrationEmailAsUsername, current.RegistrationEmailAsUsername) || changed
+	changed = diffBool(&update.EditUsernameAllowed, settings.EditUsernameAllowed, current.EditUsernameAllowed) || changed
+	changed = diffBool(&update.ResetPasswordAllowed, settings.ResetPasswordAllowed, current.ResetPasswordAllowed) || changed
+	changed = diffBool(&update.RememberMe, settings.RememberMe, current.RememberMe) || changed
+	changed = diffBool(&update.VerifyEmail, settings.VerifyEmail, current.VerifyEmail) || changed
+	changed = diffBool(&update.LoginWithEmailAllowed, settings.LoginWithEmailAllowed, current.LoginWithEmailAllowed) || changed
+	return changed
+}
+
+func diffThemes(update *kc.KeycloakAPIRealm, themes *kc.KeycloakRealmThemes, unavailable map[string]string, current *kc.KeycloakAPIRealm) bool {
+	available := func(themeType, name string) string {
+		if _, ok := unavailable[themeType]; ok {
+			return ""
+		}
+		return name
+	}
+	changed := diffString(&update.LoginTheme, available("login", themes.LoginTheme), current.LoginTheme)
+	changed = diffString(&update.AccountTheme, available("account", themes.AccountTheme), current.AccountTheme) || changed
+	changed = diffString(&update.EmailTheme, available("email", themes.EmailTheme), current.EmailTheme) || changed
+	changed = diffString(&update.AdminTheme, available("admin", themes.AdminTheme), current.AdminTheme) || changed
+	return changed
+}
+
+// The fields of one of the two WebAuthn policies of a realm representation
+type webAuthnPolicyFields struct {
+	rpEntityName                    *string
+	signatureAlgorithms             *[]string
+	rpID                            *string
+	attestationConveyancePreference *string
+	authenticatorAttachment         *string
+	requireResidentKey              *string
+	userVerificationRequirement     *string
+	createTimeout                   **int32
+	avoidSameAuthenticatorRegister  **bool
+	acceptableAaguids               *[]string
+}
+
+func webAuthnPolicyOf(realm *kc.KeycloakAPIRealm, passwordless bool) webAuthnPolicyFields {
+	if passwordless {
+		return webAuthnPolicyFields{
+			rpEntityName:                    &realm.WebAuthnPolicyPasswordlessRpEntityName,
+			signatureAlgorithms:             &realm.WebAuthnPolicyPasswordlessSignatureAlgorithms,
+			rpID:                            &realm.WebAuthnPolicyPasswordlessRpID,
+			attestationConveyancePreference: &realm.WebAuthnPolicyPasswordlessAttestationConveyancePreference,
+			authenticatorAttachment:         &realm.WebAuthnPolicyPasswordlessAuthenticatorAttachment,
+			requireResidentKey:              &realm.WebAuthnPolicyPasswordlessRequireResidentKey,
+			userVerificationRequirement:     &realm.WebAuthnPolicyPasswordlessUserVerificationRequirement,
+			createTimeout:                   &realm.WebAuthnPolicyPasswordlessCreateTimeout,
+			avoidSameAuthenticatorRegister:  &realm.WebAuthnPolicyPasswordlessAvoidSameAuthenticatorRegister,
+			acceptableAaguids:               &realm.WebAuthnPolicyPasswordlessAcceptableAaguids,
+		}
+	}
+	return webAuthnPolicyFields{
+		rpEntityName:                    &realm.WebAuthnPolicyRpEntityName,
+		signatureAlgorithms:             &realm.WebAuthnPolicySignatureAlgorithms,
+		rpID:                            &realm.WebAuthnPolicyRpID,
+		attestationConveyancePreference: &realm.WebAuthnPolicyAttestationConveyancePreference,
+		authenticatorAttachment:         &realm.WebAuthnPolicyAuthenticatorAttachment,
+		requireResidentKey:              &realm.WebAuthnPolicyRequireResidentKey,
+		userVerificationRequirement:     &realm.WebAuthnPolicyUserVerificationRequirement,
+		createTimeout:                   &realm.WebAuthnPolicyCreateTimeout,
+		avoidSameAuthenticatorRegister:  &realm.WebAuthnPolicyAvoidSameAuthenticatorRegister,
+		acceptableAaguids:               &realm.WebAuthnPolicyAcceptableAaguids,
+	}
+}
+
+func diffWebAuthnPolicy(update webAuthnPolicyFields, policy *kc.KeycloakRealmWebAuthnPolicy, current webAuthnPolicyFields) bool {
+	changed := diffString(update.rpEntityName, policy.RpEntityName, *current.rpEntityName)
+	changed = diffStringSet(update.signatureAlgorithms, policy.SignatureAlgorithms, *current.signatureAlgorithms) || changed
+	changed = diffString(update.rpID, policy.RpID, *current.rpID) || changed
+	changed = diffString(update.attestationConveyancePreference, policy.AttestationConveyancePreference, *current.attestationConveyancePreference) || changed
+	changed = diffString(update.authenticatorAttachment, policy.AuthenticatorAttachment, *current.authenticatorAttachment) || changed
+	changed = diffString(update.requireResidentKey, policy.RequireResidentKey, *current.requireResidentKey) || changed
+	changed = diffString(update.userVerificationRequirement, policy.UserVerificationRequirement, *current.userVerificationRequirement) || changed
+	changed = diffInt32(update.createTimeout, policy.CreateTimeout, *current.createTimeout) || changed
+	changed = diffBool(update.avoidSameAuthenticatorRegister, policy.AvoidSameAuthenticatorRegister, *current.avoidSameAuthenticatorRegister) || changed
+	changed = diffStringSet(update.acceptableAaguids, policy.AcceptableAaguids, *current.acceptableAaguids) || changed
+	return changed
+}
+
+// Keycloak counts in whole seconds, anything below is dropped
+func durationSeconds(duration *metav1.Duration) *int32 {
+	if duration == nil {
+		return nil
+	}
+	seconds := int32(duration.Duration / time.Second)
+	return &seconds
+}
+
+// Brute force detection settings keycloak starts new realms with
+var defaultBruteForceDetection = kc.KeycloakAPIRealm{
+	PermanentLockout:             &[]bool{false}[0],
+	FailureFactor:                &[]int32{30}[0],
+	WaitIncrementSeconds:         &[]int32{60}[0],
+	QuickLoginCheckMilliSeconds:  &[]int64{1000}[0],
+	MinimumQuickLoginWaitSeconds: &[]int32{60}[0],
+	MaxFailureWaitSeconds:        &[]int32{900}[0],
+	MaxDeltaTimeSeconds:          &[]int32{43200}[0],
+}
+
+// Settings of a disabled brute force detection are reset to the defaults, so that
+// turning it on again doesn't bring back stale values
+func diffBruteForceDetection(update, desired, current *kc.KeycloakAPIRealm) bool {
+	changed := diffBool(&update.BruteForceProtected, desired.BruteForceProtected, current.BruteForceProtected)
+
+	settings := desired
+	if desired.BruteForceProtected != nil && !*desired.BruteForceProtected {
+		settings = &defaultBruteForceDetection
+	}
+	changed = diffBool(&update.PermanentLockout, settings.PermanentLockout, current.PermanentLockout) || changed
+	changed = diffInt32(&update.FailureFactor, settings.FailureFactor, current.FailureFactor) || changed
+	changed = diffInt32(&update.WaitIncrementSeconds, settings.WaitIncrementSeconds, current.WaitIncrementSeconds) || changed
+	changed = diffInt64(&update.QuickLoginCheckMilliSeconds, settings.QuickLoginCheckMilliSeconds, current.QuickLoginCheckMilliSeconds) || changed
+	changed = diffInt32(&update.MinimumQuickLoginWaitSeconds, settings.MinimumQuickLoginWaitSeconds, current.MinimumQuickLoginWaitSeconds) || changed
+	changed = diffInt32(&update.MaxFailureWaitSeconds, settings.MaxFailureWaitSeconds, current.MaxFailureWaitSeconds) || changed
+	changed = diffInt32(&update.MaxDeltaTimeSeconds, settings.MaxDeltaTimeSeconds, current.MaxDeltaTimeSeconds) || changed
+	return changed
+}
+
+func diffString(update *string, desired, current string) bool {
+	if desired == "" || desired == current {
+		return false
+	}
+	*update = desired
+	return true
+}
+
+// The order of the values doesn't matter to keycloak
+func diffStringSet(update *[]string, desired, current []string) bool {
+	if desired == nil {
+		return false
+	}
+	sortedDesired := append([]string(nil), desired...)
+	sortedCurrent := append([]string(nil), current...)
+	sort.Strings(sortedDesired)
+	sort.Strings(sortedCurrent)
+	if reflect.DeepEqual(sortedDesired, sortedCurrent) {
+		return false
+	}
+	*update = desired
+	return true
+}
+
+func diffInt32(update **int32, desired, current *int32) bool {
+	if desired == nil || (current != nil && *desired == *current) {
+		return false
+	}
+	*update = desired
+	return true
+}
+
+func diffInt64(update **int64, desired, current *int64) bool {
+	if desired == nil || (current != nil && *desired == *current) {
+		return false
+	}
+	*update = desired
+	return true
+}
+
+func diffBool(update **bool, desired, current *bool) bool {
+	if desired == nil || (current != nil && *desired == *current) {
+		return false
+	}
+	*update = desired
+	return true
+}
+
+// The events config has its own endpoint, it is pushed whole when anything set differs
+func (i *KeycloakRealmReconciler) getDesiredRealmEventsState(state *common.RealmState, cr *kc.KeycloakRealm) common.ClusterAction {
+	if cr.DeletionTimestamp != nil || cr.Spec.Events == nil {
+		return nil
+	}
+
+	if state.Realm != nil && state.EventsConfig != nil && eventsConfigMatches(cr.Spec.Events, state.EventsConfig) {
+		return nil
+	}
+
+	return &common.ConfigureRealmEventsAction{
+		Config: desiredEventsConfig(cr.Spec.Events, state.EventsConfig),
+		Ref:    cr,
+		Msg:    fmt.Sprintf("configure events of realm %v/%v", cr.Namespace, cr.Spec.Realm.Realm),
+	}
+}
+
+// Keycloak resets the settings that the update of the events config leaves out, so
+// the ones not set in the CR are sent with their current values
+func desiredEventsConfig(desired, current *kc.KeycloakAPIRealmEventsConfig) *kc.KeycloakAPIRealmEventsConfig {
+	if current == nil {
+		return desired
+	}
+
+	config := current.DeepCopy()
+	if desired.EventsEnabled != nil {
+		config.EventsEnabled = desired.EventsEnabled
+	}
+	if desired.EventsExpiration != nil {
+		config.EventsExpiration = desired.EventsExpiration
+	}
+	if desired.EventsListeners != nil {
+		config.EventsListeners = desired.EventsListeners
+	}
+	if desired.EnabledEventTypes != nil {
+		config.EnabledEventTypes = desired.EnabledEventTypes
+	}
+	if desired.AdminEventsEnabled != nil {
+		config.AdminEventsEnabled = desired.AdminEventsEnabled
+	}
+	if desired.AdminEventsDetailsEnabled != nil {
+		config.AdminEventsDetailsEnabled = desired.AdminEventsDetailsEnabled
+	}
+	return config
+}
+
+func eventsConfigMatches(desired, current *kc.KeycloakAPIRealmEventsConfig) bool {
+	update := &kc.KeycloakAPIRealmEventsConfig{}
+	changed := diffBool(&update.EventsEnabled, desired.EventsEnabled, current.EventsEnabled)
+	changed = diffInt64(&update.EventsExpiration, desired.EventsExpiration, current.EventsExpiration) || changed
+	changed = diffBool(&update.AdminEventsEnabled, desired.AdminEventsEnabled, current.AdminEventsEnabled) || changed
+	changed = diffBool(&update.AdminEventsDetailsEnabled, desired.AdminEventsDetailsEnabled, current.AdminEventsDetailsEnabled) || changed
+	if desired.EventsListeners != nil && !sameStrings(desired.EventsListeners, current.EventsListeners) {
+		changed = true
+	}
+	if desired.EnabledEventTypes != nil && !sameStrings(desired.EnabledEventTypes, current.EnabledEventTypes) {
+		changed = true
+	}
+	return !changed
+}
+
+// Compares two lists regardless of their order
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int)
+	for _, value := range a {
+		counts[value]++
+	}
+	for _, value := range b {
+		if counts[value] == 0 {
+			return false
+		}
+		counts[value]--
+	}
+	return true
+}
+
+func getResolvedSMTPServer(state *common.RealmState, cr *kc.KeycloakRealm) map[string]string {
+	smtpServer := map[string]string{}
+	for key, value := range cr.Spec.Realm.SMTPServer {
+		smtpServer[key] = value
+	}
+	for key, value := range state.SMTPServerSecrets {
+		smtpServer[key] = value
+	}
+	return smtpServer
+}
+
+// Compares all settings but the password, which keycloak doesn't return
+func smtpServerMatches(desired, current map[string]string) bool {
+	for key, value := range desired {
+		if key == "password" {
+			continue
+		}
+		if current[key] != value {
+			return false
+		}
+	}
+	for key := range current {
+		if _, ok := desired[key]; !ok && key != "password" {
+			return false
+		}
+	}
+	return true
+}
+
+func smtpServerHash(cr *kc.KeycloakRealm, smtpServer map[string]string) string {
+	// Marshalling a map of strings can't fail, and sorts the keys
+	value, _ := json.Marshal(smtpServer)
+	return model.SecretHash(string(cr.UID), value)
+}
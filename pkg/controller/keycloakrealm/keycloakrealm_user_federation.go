@@ -0,0 +1,276 @@
+package keycloakrealm
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/common"
+	"github.com/keycloak/keycloak-operator/pkg/model"
+	v1 "k8s.io/api/core/v1"
+)
+
+// User federation providers are stored by keycloak as components of the realm, matched
+// here by display name. Config values from secrets come back masked, their changes are
+// told by the hashes in the status
+func (i *KeycloakRealmReconciler) ReconcileUserFederationProviders(state *common.RealmState, cr *kc.KeycloakRealm, desired *common.DesiredClusterState) {
+	if cr.Spec.Realm.UserFederationProviders == nil {
+		return
+	}
+
+	for index := range state.UserFederationProviders {
+		provider := &state.UserFederationProviders[index]
+		if getUserFederationProvider(cr.Spec.Realm.UserFederationProviders, provider.Name) == nil {
+			desired.AddAction(i.getDeletedUserFederationProviderState(cr, provider))
+		}
+	}
+
+	for index := range cr.Spec.Realm.UserFederationProviders {
+		provider := &cr.Spec.Realm.UserFederationProviders[index]
+		component := getUserFederationProviderComponent(state, provider)
+		hash := userFederationSecretHash(cr, state.UserFederationSecrets[provider.DisplayName])
+
+		existing := getComponent(state.UserFederationProviders, "", provider.DisplayName)
+		if existing == nil {
+			if state.Realm != nil {
+				component.ParentID = state.Realm.Spec.Realm.ID
+			}
+			desired.AddAction(i.getCreatedUserFederationProviderState(cr, component, hash))
+			continue
+		}
+
+		if componentMatches(component, existing, provider.ConfigSecrets) && hash == cr.Status.UserFederationSecretHashes[provider.DisplayName] {
+			continue
+		}
+		desired.AddAction(i.getUpdatedUserFederationProviderState(cr, getUpdatedComponent(component, existing), hash))
+	}
+}
+
+// Mappers go after the providers, which may be created in the same pass
+func (i *KeycloakRealmReconciler) ReconcileUserFederationMappers(state *common.RealmState, cr *kc.KeycloakRealm, desired *common.DesiredClusterState) {
+	if cr.Spec.Realm.UserFederationMappers == nil {
+		return
+	}
+
+	for index := range state.UserFederationMappers {
+		mapper := &state.UserFederationMappers[index]
+		provider := getComponentByID(state.UserFederationProviders, mapper.ParentID)
+		if provider == nil {
+			continue
+		}
+		// mappers of removed providers are removed along with them
+		if cr.Spec.Realm.UserFederationProviders != nil && getUserFederationProvider(cr.Spec.Realm.UserFederationProviders, provider.Name) == nil {
+			continue
+		}
+		if getUserFederationMapper(cr.Spec.Realm.UserFederationMappers, provider.Name, mapper.Name) == nil {
+			desired.AddAction(i.getDeletedUserFederationMapperState(cr, provider.Name, mapper))
+		}
+	}
+
+	for index := range cr.Spec.Realm.UserFederationMappers {
+		mapper := &cr.Spec.Realm.UserFederationMappers[index]
+		component := getUserFederationMapperComponent(mapper)
+
+		var existing *kc.KeycloakAPIComponent
+		if provider := getComponent(state.UserFederationProviders, "", mapper.FederationProviderDisplayName); provider != nil {
+			existing = getComponent(state.UserFederationMappers, provider.ID, mapper.Name)
+		}
+		if existing == nil {
+			desired.AddAction(i.getCreatedUserFederationMapperState(cr, mapper.FederationProviderDisplayName, component))
+			continue
+		}
+
+		if !componentMatches(component, existing, nil) {
+			desired.AddAction(i.getUpdatedUserFederationMapperState(cr, mapper.FederationProviderDisplayName, getUpdatedComponent(component, existing)))
+		}
+	}
+}
+
+// A sync is asked for with the keycloak.org/sync-federation annotation, giving the display
+// name or ID of the provider. Only changed users are synced when keycloak.org/sync-federation-mode
+// is set to changed
+func (i *KeycloakRealmReconciler) getUserFederationSyncState(state *common.RealmState, cr *kc.KeycloakRealm) common.ClusterAction {
+	provider, ok := cr.Annotations[model.SyncUserFederationAnnotation]
+	if !ok || provider == "" || state.Realm == nil {
+		return nil
+	}
+
+	full := cr.Annotations[model.SyncUserFederationModeAnnotation] != "changed"
+	mode := "full"
+	if !full {
+		mode = "changed users"
+	}
+
+	return &common.SyncUserFederationAction{
+		Provider: provider,
+		Full:     full,
+		Ref:      cr,
+		Msg:      fmt.Sprintf("sync %v of user federation provider %v/%v/%v", mode, cr.Namespace, cr.Spec.Realm.Realm, provider),
+	}
+}
+
+func getUserFederationProvider(providers []kc.KeycloakAPIUserFederationProvider, displayName string) *kc.KeycloakAPIUserFederationProvider {
+	for index := range providers {
+		if providers[index].DisplayName == displayName {
+			return &providers[index]
+		}
+	}
+	return nil
+}
+
+func getUserFederationMapper(mappers []kc.KeycloakAPIUserFederationMapper, providerName, name string) *kc.KeycloakAPIUserFederationMapper {
+	for index := range mappers {
+		if mappers[index].FederationProviderDisplayName == providerName && mappers[index].Name == name {
+			return &mappers[index]
+		}
+	}
+	return nil
+}
+
+// Components are looked up by name, within the given parent unless it is empty
+func getComponent(components []kc.KeycloakAPIComponent, parentID, name string) *kc.KeycloakAPIComponent {
+	for index := range components {
+		if components[index].Name == name && (parentID == "" || components[index].ParentID == parentID) {
+			return &components[index]
+		}
+	}
+	return nil
+}
+
+func getComponentByID(components []kc.KeycloakAPIComponent, id string) *kc.KeycloakAPIComponent {
+	for index := range components {
+		if components[index].ID == id {
+			return &components[index]
+		}
+	}
+	return nil
+}
+
+func getUserFederationProviderComponent(state *common.RealmState, provider *kc.KeycloakAPIUserFederationProvider) *kc.KeycloakAPIComponent {
+	config := map[string][]string{}
+	for key, value := range provider.Config {
+		config[key] = []string{value}
+	}
+	if provider.Priority != nil {
+		config["priority"] = []string{strconv.Itoa(int(*provider.Priority))}
+	}
+	if provider.FullSyncPeriod != nil {
+		config["fullSyncPeriod"] = []string{strconv.Itoa(int(*provider.FullSyncPeriod))}
+	}
+	for key, value := range state.UserFederationSecrets[provider.DisplayName] {
+		config[key] = []string{value}
+	}
+
+	return &kc.KeycloakAPIComponent{
+		Name:         provider.DisplayName,
+		ProviderID:   provider.ProviderName,
+		ProviderType: model.UserStorageProviderType,
+		Config:       config,
+	}
+}
+
+func getUserFederationMapperComponent(mapper *kc.KeycloakAPIUserFederationMapper) *kc.KeycloakAPIComponent {
+	config := map[string][]string{}
+	for key, value := range mapper.Config {
+		config[key] = []string{value}
+	}
+
+	return &kc.KeycloakAPIComponent{
+		Name:         mapper.Name,
+		ProviderID:   mapper.FederationMapperType,
+		ProviderType: model.LDAPStorageMapperType,
+		Config:       config,
+	}
+}
+
+// Keycloak adds defaults to the config, only the values given in the CR are compared.
+// Values from secrets are skipped, keycloak masks them
+func componentMatches(desired, existing *kc.KeycloakAPIComponent, secrets map[string]v1.SecretKeySelector) bool {
+	if desired.Name != existing.Name || desired.ProviderID != existing.ProviderID {
+		return false
+	}
+	for key, value := range desired.Config {
+		if _, ok := secrets[key]; ok {
+			continue
+		}
+		if !reflect.DeepEqual(value, existing.Config[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+// The desired config is laid over the existing one, keycloak drops config values
+// missing from an update
+func getUpdatedComponent(desired, existing *kc.KeycloakAPIComponent) *kc.KeycloakAPIComponent {
+	updated := existing.DeepCopy()
+	if updated.Config == nil {
+		updated.Config = map[string][]string{}
+	}
+	for key, value := range desired.Config {
+		updated.Config[key] = value
+	}
+	return updated
+}
+
+func userFederationSecretHash(cr *kc.KeycloakRealm, secrets map[string]string) string {
+	if len(secrets) == 0 {
+		return ""
+	}
+	// Marshalling a map of strings can't fail, and sorts the keys
+	value, _ := json.Marshal(secrets)
+	return model.SecretHash(string(cr.UID), value)
+}
+
+func (i *KeycloakRealmReconciler) getCreatedUserFederationProviderState(cr *kc.KeycloakRealm, provider *kc.KeycloakAPIComponent, hash string) common.ClusterAction {
+	return &common.CreateUserFederationProviderAction{
+		Provider:   provider,
+		SecretHash: hash,
+		Ref:        cr,
+		Msg:        fmt.Sprintf("create user federation provider %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, provider.Name),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getUpdatedUserFederationProviderState(cr *kc.KeycloakRealm, provider *kc.KeycloakAPIComponent, hash string) common.ClusterAction {
+	return &common.UpdateUserFederationProviderAction{
+		Provider:   provider,
+		SecretHash: hash,
+		Ref:        cr,
+		Msg:        fmt.Sprintf("update user federation provider %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, provider.Name),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getDeletedUserFederationProviderState(cr *kc.KeycloakRealm, provider *kc.KeycloakAPIComponent) common.ClusterAction {
+	return &common.DeleteUserFederationProviderAction{
+		Provider: provider,
+		Ref:      cr,
+		Msg:      fmt.Sprintf("delete user federation provider %v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, provider.Name),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getCreatedUserFederationMapperState(cr *kc.KeycloakRealm, providerName string, mapper *kc.KeycloakAPIComponent) common.ClusterAction {
+	return &common.CreateUserFederationMapperAction{
+		Mapper:       mapper,
+		ProviderName: providerName,
+		Ref:          cr,
+		Msg:          fmt.Sprintf("create user federation mapper %v/%v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, providerName, mapper.Name),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getUpdatedUserFederationMapperState(cr *kc.KeycloakRealm, providerName string, mapper *kc.KeycloakAPIComponent) common.ClusterAction {
+	return &common.UpdateUserFederationMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Msg:    fmt.Sprintf("update user federation mapper %v/%v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, providerName, mapper.Name),
+	}
+}
+
+func (i *KeycloakRealmReconciler) getDeletedUserFederationMapperState(cr *kc.KeycloakRealm, providerName string, mapper *kc.KeycloakAPIComponent) common.ClusterAction {
+	return &common.DeleteUserFederationMapperAction{
+		Mapper: mapper,
+		Ref:    cr,
+		Msg:    fmt.Sprintf("delete user federation mapper %v/%v/%v/%v", cr.Namespace, cr.Spec.Realm.Realm, providerName, mapper.Name),
+	}
+}
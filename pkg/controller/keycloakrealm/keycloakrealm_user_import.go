@@ -0,0 +1,201 @@
+package keycloakrealm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/common"
+)
+
+// Separates the values of the list columns of the CSV
+const userImportListSeparator = ";"
+
+// The users are imported into the existing realm whenever their content or the
+// format changes, as told by the hash in the status, which is cleared along with the
+// one of the realm import when the realm is created again. Rows that can't be read
+// are reported as failed, the others are still imported
+func (i *KeycloakRealmReconciler) getUserImportState(state *common.RealmState, cr *kc.KeycloakRealm) common.ClusterAction {
+	if cr.Spec.UserImport == nil || state.Realm == nil || state.UserImport == nil {
+		return nil
+	}
+
+	format := cr.Spec.UserImport.Format
+	if format == "" {
+		format = kc.UserImportCSV
+	}
+
+	hash := userImportHash(state.UserImport, format)
+	if hash == cr.Status.UserImportHash {
+		return nil
+	}
+
+	var users []*kc.KeycloakAPIUser
+	var invalid []kc.KeycloakRealmUserImportResult
+	if format == kc.UserImportJSON {
+		users, invalid = parseUserImportJSON(state.UserImport)
+	} else {
+		users, invalid = parseUserImportCSV(state.UserImport)
+	}
+
+	return &common.ImportRealmUsersAction{
+		Users:   users,
+		Invalid: invalid,
+		Hash:    hash,
+		Ref:     cr,
+		Msg:     fmt.Sprintf("import %v users into realm %v/%v", len(users), cr.Namespace, cr.Spec.Realm.Realm),
+	}
+}
+
+func userImportHash(users []byte, format kc.UserImportFormat) string {
+	hash := sha256.New()
+	hash.Write(users)
+	hash.Write([]byte(format))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func parseUserImportJSON(data []byte) ([]*kc.KeycloakAPIUser, []kc.KeycloakRealmUserImportResult) {
+	var users []*kc.KeycloakAPIUser
+	err := json.Unmarshal(data, &users)
+	if err != nil {
+		return nil, []kc.KeycloakRealmUserImportResult{invalidUserImport("", fmt.Sprintf("users are not a JSON list of users: %v", err))}
+	}
+
+	var valid []*kc.KeycloakAPIUser
+	var invalid []kc.KeycloakRealmUserImportResult
+	for index, user := range users {
+		if user == nil || user.UserName == "" {
+			invalid = append(invalid, invalidUserImport("", fmt.Sprintf("user %v has no username", index+1)))
+			continue
+		}
+		valid = append(valid, user)
+	}
+	return valid, invalid
+}
+
+func parseUserImportCSV(data []byte) ([]*kc.KeycloakAPIUser, []kc.KeycloakRealmUserImportResult) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, []kc.KeycloakRealmUserImportResult{invalidUserImport("", fmt.Sprintf("users have no CSV header: %v", err))}
+	}
+	for index, column := range header {
+		header[index] = strings.TrimSpace(column)
+		if !isUserImportColumn(header[index]) {
+			return nil, []kc.KeycloakRealmUserImportResult{invalidUserImport("", fmt.Sprintf("unknown CSV column %v", header[index]))}
+		}
+	}
+
+	var users []*kc.KeycloakAPIUser
+	var invalid []kc.KeycloakRealmUserImportResult
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			invalid = append(invalid, invalidUserImport("", err.Error()))
+			continue
+		}
+
+		user, err := parseUserImportRow(header, row)
+		if err != nil {
+			invalid = append(invalid, invalidUserImport(user.UserName, fmt.Sprintf("line %v: %v", line, err)))
+			continue
+		}
+		users = append(users, user)
+	}
+	return users, invalid
+}
+
+func isUserImportColumn(column string) bool {
+	switch column {
+	case "username", "email", "firstName", "lastName", "enabled", "emailVerified", "realmRoles", "clientRoles", "groups":
+		return true
+	}
+	return false
+}
+
+// The user is returned also on errors, as far as it could be read
+func parseUserImportRow(header []string, row []string) (*kc.KeycloakAPIUser, error) {
+	user := &kc.KeycloakAPIUser{Enabled: true}
+	var err error
+	for index, column := range header {
+		value := strings.TrimSpace(row[index])
+		switch column {
+		case "username":
+			user.UserName = value
+		case "email":
+			user.Email = value
+		case "firstName":
+			user.FirstName = value
+		case "lastName":
+			user.LastName = value
+		case "enabled":
+			if value != "" {
+				user.Enabled, err = strconv.ParseBool(value)
+			}
+		case "emailVerified":
+			if value != "" {
+				user.EmailVerified, err = strconv.ParseBool(value)
+			}
+		case "realmRoles":
+			user.RealmRoles = splitUserImportList(value)
+		case "groups":
+			user.Groups = splitUserImportList(value)
+		case "clientRoles":
+			user.ClientRoles, err = parseUserImportClientRoles(value)
+		}
+		if err != nil {
+			return user, fmt.Errorf("invalid %v: %v", column, err)
+		}
+	}
+
+	if user.UserName == "" {
+		return user, fmt.Errorf("no username")
+	}
+	return user, nil
+}
+
+func splitUserImportList(value string) []string {
+	var values []string
+	for _, item := range strings.Split(value, userImportListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+func parseUserImportClientRoles(value string) (map[string][]string, error) {
+	var clientRoles map[string][]string
+	for _, item := range splitUserImportList(value) {
+		separator := strings.Index(item, ":")
+		if separator <= 0 || separator == len(item)-1 {
+			return nil, fmt.Errorf("%v is not given as client:role", item)
+		}
+		if clientRoles == nil {
+			clientRoles = map[string][]string{}
+		}
+		client := item[:separator]
+		clientRoles[client] = append(clientRoles[client], item[separator+1:])
+	}
+	return clientRoles, nil
+}
+
+func invalidUserImport(username string, message string) kc.KeycloakRealmUserImportResult {
+	return kc.KeycloakRealmUserImportResult{
+		Username: username,
+		Result:   kc.UserImportFailed,
+		Message:  message,
+	}
+}
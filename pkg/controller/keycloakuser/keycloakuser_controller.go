@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/keycloak/keycloak-operator/pkg/common"
+	"github.com/keycloak/keycloak-operator/pkg/model"
 
 	"k8s.io/client-go/tools/record"
 
@@ -29,6 +30,8 @@ import (
 const (
 	ControllerName    = "controller_keycloakuser"
 	RequeueDelayError = 5 * time.Second
+	// Upper bound of the backoff while keycloak is unavailable
+	RequeueDelayTransientMax = 5 * time.Minute
 )
 
 var log = logf.Log.WithName("controller_keycloakuser")
@@ -50,6 +53,7 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 		context:  ctx,
 		cancel:   cancel,
 		recorder: mgr.GetEventRecorderFor(ControllerName),
+		backoff:  common.NewBackoff(RequeueDelayError, RequeueDelayTransientMax),
 	}
 }
 
@@ -100,6 +104,7 @@ type ReconcileKeycloakUser struct {
 	context  context.Context
 	cancel   context.CancelFunc
 	recorder record.EventRecorder
+	backoff  *common.Backoff
 }
 
 // Reconcile reads that state of the cluster for a KeycloakUser object and makes changes based on the state read
@@ -112,6 +117,7 @@ type ReconcileKeycloakUser struct {
 func (r *ReconcileKeycloakUser) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling KeycloakUser")
+	defer common.ObserveReconcileDuration("KeycloakUser", time.Now())
 
 	// Fetch the KeycloakUser instance
 	instance := &kc.KeycloakUser{}
@@ -121,6 +127,7 @@ func (r *ReconcileKeycloakUser) Reconcile(request reconcile.Request) (reconcile.
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
+			common.ForgetPendingActions(instance, request.Namespace, request.Name)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -142,24 +149,27 @@ func (r *ReconcileKeycloakUser) Reconcile(request reconcile.Request) (reconcile.
 
 	log.Info(fmt.Sprintf("found %v matching realm(s) for user %v/%v", len(realms.Items), instance.Namespace, instance.Name))
 
+	// Instances in dry run mode only log the actions for this resource
+	dryRun := false
+	dryRunActions := 0
 	for _, realm := range realms.Items {
 		if realm.Spec.Unmanaged {
 			return r.ManageError(instance, errors.Errorf("users cannot be created for unmanaged keycloak realms"))
 		}
 
-		keycloaks, err := common.GetMatchingKeycloaks(r.context, r.client, realm.Spec.InstanceSelector)
+		keycloaks, err := common.GetRealmKeycloaks(r.context, r.client, &realm)
 		if err != nil {
 			return r.ManageError(instance, err)
 		}
 
 		for _, keycloak := range keycloaks.Items {
-			if keycloak.Spec.Unmanaged {
+			if keycloak.Spec.Unmanaged && !model.IsExternalKeycloak(&keycloak) {
 				return r.ManageError(instance, errors.Errorf("users cannot be created for unmanaged keycloak instances"))
 			}
 
 			// Get an authenticated keycloak api client for the instance
 			keycloakFactory := common.LocalConfigKeycloakFactory{}
-			authenticated, err := keycloakFactory.AuthenticatedClient(keycloak)
+			authenticated, err := keycloakFactory.AuthenticatedRealmClient(keycloak, realm)
 			if err != nil {
 				return r.ManageError(instance, err)
 			}
@@ -181,7 +191,12 @@ func (r *ReconcileKeycloakUser) Reconcile(request reconcile.Request) (reconcile.
 			reconciler := NewKeycloakuserReconciler(keycloak, realm)
 			desiredState := reconciler.Reconcile(userState, instance)
 
-			actionRunner := common.NewClusterAndKeycloakActionRunner(r.context, r.client, r.scheme, instance, authenticated)
+			actionRunner := common.NewClusterAndKeycloakActionRunner(r.context, r.client, r.scheme, instance, authenticated, r.recorder)
+			if keycloak.Spec.DryRun {
+				actionRunner = common.NewDryRunActionRunner(r.context, r.client, r.scheme, instance)
+				dryRun = true
+				dryRunActions += len(desiredState)
+			}
 			err = actionRunner.RunAll(desiredState)
 			if err != nil {
 				return r.ManageError(instance, err)
@@ -189,10 +204,18 @@ func (r *ReconcileKeycloakUser) Reconcile(request reconcile.Request) (reconcile.
 		}
 	}
 
-	return reconcile.Result{Requeue: false}, r.manageSuccess(instance, instance.DeletionTimestamp != nil)
+	if dryRun {
+		return reconcile.Result{Requeue: false}, common.ManageDryRun(r.context, r.client, instance, &instance.Status.Message, dryRunActions)
+	}
+
+	return common.ResyncResult(instance.Spec.ResyncPeriod), r.manageSuccess(instance, instance.DeletionTimestamp != nil)
 }
 
+// Only the message is reported, the phase and the finalizer are left alone as
+// nothing was changed
 func (r *ReconcileKeycloakUser) manageSuccess(user *kc.KeycloakUser, deleted bool) error {
+	r.backoff.Reset(user)
+	common.SetSuccessConditions(&user.Status.Conditions, user.Generation)
 	user.Status.Phase = kc.UserPhaseReconciled
 
 	err := r.client.Status().Update(r.context, user)
@@ -236,10 +259,19 @@ func (r *ReconcileKeycloakUser) manageSuccess(user *kc.KeycloakUser, deleted boo
 }
 
 func (r *ReconcileKeycloakUser) ManageError(user *kc.KeycloakUser, issue error) (reconcile.Result, error) {
+	// Keycloak is expected to be unavailable for a while during rollouts
+	if common.IsTransientError(issue) {
+		return common.ManageTransientError(r.context, r.client, r.backoff, user, issue, func(message string) {
+			user.Status.Message = message
+			common.SetTransientErrorConditions(&user.Status.Conditions, user.Generation, issue)
+		})
+	}
+
 	r.recorder.Event(user, "Warning", "ProcessingError", issue.Error())
 
 	user.Status.Phase = kc.UserPhaseFailing
 	user.Status.Message = issue.Error()
+	common.SetErrorConditions(&user.Status.Conditions, user.Generation, issue)
 
 	err := r.client.Status().Update(r.context, user)
 	if err != nil {
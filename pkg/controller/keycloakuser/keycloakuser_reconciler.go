@@ -2,6 +2,7 @@ package keycloakuser
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/keycloak/keycloak-operator/pkg/model"
 
@@ -38,6 +39,7 @@ func (i *KeycloakuserReconciler) reconcileUser(state *common.UserState, cr *v1al
 	desired.AddAction(i.getKeycloakDesiredState())
 	desired.AddActions(i.getKeycloakUserDesiredState(state, cr))
 	desired.AddAction(i.getUserSecretDesiredState(state, cr))
+	desired.AddAction(i.getUserActionsEmailDesiredState(cr))
 
 	return desired
 }
@@ -80,7 +82,7 @@ func (i *KeycloakuserReconciler) getKeycloakUserDesiredState(state *common.UserS
 		})
 	} else {
 		actions = append(actions, &common.UpdateUserAction{
-			Ref:   cr,
+			Ref:   userWithMergedAttributes(state, cr),
 			Realm: i.Realm.Spec.Realm.Realm,
 			Msg:   fmt.Sprintf("update user %v", cr.Spec.User.UserName),
 		})
@@ -88,11 +90,36 @@ func (i *KeycloakuserReconciler) getKeycloakUserDesiredState(state *common.UserS
 		// Sync the requested roles
 		actions = append(actions, i.getUserRealmRolesDesiredState(state, cr)...)
 		actions = append(actions, i.getUserClientRolesDesiredState(state, cr)...)
+		actions = append(actions, i.getUserGroupsDesiredState(state, cr)...)
+		actions = append(actions, i.getUserFederatedIdentitiesDesiredState(state, cr)...)
 	}
 
 	return actions
 }
 
+// Keycloak replaces all attributes of the user with the ones of an update. When
+// merging, the update carries the attributes of keycloak with the listed ones
+// applied on top, the CR is left as it is
+func userWithMergedAttributes(state *common.UserState, cr *v1alpha1.KeycloakUser) *v1alpha1.KeycloakUser {
+	if cr.Spec.AttributesPolicy != v1alpha1.UserAttributesPolicyMerge || cr.Spec.User.Attributes == nil {
+		return cr
+	}
+
+	merged := cr.DeepCopy()
+	merged.Spec.User.Attributes = make(map[string][]string)
+	for key, values := range state.User.Attributes {
+		merged.Spec.User.Attributes[key] = values
+	}
+	for key, values := range cr.Spec.User.Attributes {
+		if len(values) == 0 {
+			delete(merged.Spec.User.Attributes, key)
+			continue
+		}
+		merged.Spec.User.Attributes[key] = values
+	}
+	return merged
+}
+
 func (i *KeycloakuserReconciler) getUserRealmRolesDesiredState(state *common.UserState, cr *v1alpha1.KeycloakUser) []common.ClusterAction {
 	var assignRoles []common.ClusterAction
 	var removeRoles []common.ClusterAction
@@ -140,23 +167,152 @@ func (i *KeycloakuserReconciler) getUserClientRolesDesiredState(state *common.Us
 	return actions
 }
 
+// Groups are only synced when set, so that memberships managed in keycloak
+// are left alone otherwise
+func (i *KeycloakuserReconciler) getUserGroupsDesiredState(state *common.UserState, cr *v1alpha1.KeycloakUser) []common.ClusterAction {
+	if cr.Spec.User.Groups == nil {
+		return nil
+	}
+
+	var joinGroups []common.ClusterAction
+	var leaveGroups []common.ClusterAction
+
+	for _, path := range cr.Spec.User.Groups {
+		// Group requested but not joined?
+		if !containsGroup(state.Groups, path) {
+			joinGroups = append(joinGroups, &common.JoinGroupAction{
+				UserID: state.User.ID,
+				Path:   normalizeGroupPath(path),
+				Realm:  i.Realm.Spec.Realm.Realm,
+				Msg:    fmt.Sprintf("add user %v to group %v", state.User.UserName, path),
+			})
+		}
+	}
+
+	for _, group := range state.Groups {
+		// Group joined but not requested?
+		if !containsGroupPath(cr.Spec.User.Groups, group.Path) {
+			leaveGroups = append(leaveGroups, &common.LeaveGroupAction{
+				UserID: state.User.ID,
+				Ref:    group,
+				Realm:  i.Realm.Spec.Realm.Realm,
+				Msg:    fmt.Sprintf("remove user %v from group %v", state.User.UserName, group.Path),
+			})
+		}
+	}
+
+	return append(joinGroups, leaveGroups...)
+}
+
+// A user has at most one link per identity provider, and links can't be
+// updated in place. Changed links are therefore removed before they are added
+// again
+func (i *KeycloakuserReconciler) getUserFederatedIdentitiesDesiredState(state *common.UserState, cr *v1alpha1.KeycloakUser) []common.ClusterAction {
+	if cr.Spec.User.FederatedIdentities == nil {
+		return nil
+	}
+
+	var removeIdentities []common.ClusterAction
+	var addIdentities []common.ClusterAction
+
+	for _, identity := range state.FederatedIdentities {
+		// Link exists but is not requested as is?
+		if !containsFederatedIdentity(cr.Spec.User.FederatedIdentities, identity) {
+			removeIdentities = append(removeIdentities, &common.RemoveFederatedIdentityAction{
+				UserID: state.User.ID,
+				Ref:    identity.DeepCopy(),
+				Realm:  i.Realm.Spec.Realm.Realm,
+				Msg:    fmt.Sprintf("remove identity provider link %v from user %v", identity.IdentityProvider, state.User.UserName),
+			})
+		}
+	}
+
+	for _, identity := range cr.Spec.User.FederatedIdentities {
+		// Link requested but missing?
+		if !containsFederatedIdentity(state.FederatedIdentities, identity) {
+			addIdentities = append(addIdentities, &common.AddFederatedIdentityAction{
+				UserID: state.User.ID,
+				Ref:    identity.DeepCopy(),
+				Realm:  i.Realm.Spec.Realm.Realm,
+				Msg:    fmt.Sprintf("add identity provider link %v to user %v", identity.IdentityProvider, state.User.UserName),
+			})
+		}
+	}
+
+	return append(removeIdentities, addIdentities...)
+}
+
 func (i *KeycloakuserReconciler) getUserSecretDesiredState(state *common.UserState, cr *v1alpha1.KeycloakUser) common.ClusterAction {
-	// Only ever create the secret, because we can't know when the
-	// users change their credentials in keycloak. Also the owner
+	// The secret is never reconciled with keycloak, because we can't
+	// know when the users change their credentials there. Also the owner
 	// reference ensures that it gets deleted once the User CR is
 	// deleted
 	if state.Secret == nil {
 		return &common.GenericCreateAction{
-			Ref: model.RealmCredentialSecret(&i.Realm, &cr.Spec.User, &i.Keycloak),
+			Ref: model.UserCredentialSecretApplied(cr, model.RealmCredentialSecret(&i.Realm, &cr.Spec.User, &i.Keycloak)),
 			Msg: fmt.Sprintf("create credential secret for user %v in realm %v/%v",
 				cr.Spec.User.UserName,
 				cr.Namespace,
 				i.Realm.Spec.Realm.Realm),
 		}
 	}
+
+	if state.User == nil {
+		return nil
+	}
+
+	// Secrets created before credential resets were supported, or holding the
+	// unkeyed hash of older versions, take the current credentials as applied
+	hash, ok := state.Secret.Annotations[model.UserCredentialsHashAnnotation]
+	if !ok {
+		return &common.GenericUpdateAction{
+			Ref: model.UserCredentialSecretApplied(cr, state.Secret),
+			Msg: fmt.Sprintf("record credentials of user %v in realm %v/%v",
+				cr.Spec.User.UserName,
+				cr.Namespace,
+				i.Realm.Spec.Realm.Realm),
+		}
+	}
+
+	// Only reset the credentials when they or the rotation marker change,
+	// so that users aren't logged out on every reconcile
+	if hash != model.UserCredentialsHash(cr) {
+		return &common.ResetUserPasswordAction{
+			Ref:    cr,
+			Secret: state.Secret,
+			Realm:  i.Realm.Spec.Realm.Realm,
+			Msg: fmt.Sprintf("reset credentials of user %v in realm %v/%v",
+				cr.Spec.User.UserName,
+				cr.Namespace,
+				i.Realm.Spec.Realm.Realm),
+		}
+	}
+
 	return nil
 }
 
+// The email is sent until it went out once, which is usually along with the creation
+// of the user, and whenever the annotation asks for it, never on other reconciles
+func (i *KeycloakuserReconciler) getUserActionsEmailDesiredState(cr *v1alpha1.KeycloakUser) common.ClusterAction {
+	if cr.Spec.ActionsEmail == nil {
+		return nil
+	}
+
+	_, requested := cr.Annotations[model.SendActionsEmailAnnotation]
+	if cr.Status.LastActionsEmailTime != "" && !requested {
+		return nil
+	}
+
+	return &common.SendUserActionsEmailAction{
+		Ref:   cr,
+		Realm: i.Realm.Spec.Realm.Realm,
+		Msg: fmt.Sprintf("send actions email to user %v in realm %v/%v",
+			cr.Spec.User.UserName,
+			cr.Namespace,
+			i.Realm.Spec.Realm.Realm),
+	}
+}
+
 func (i *KeycloakuserReconciler) syncRolesForClient(state *common.UserState, cr *v1alpha1.KeycloakUser, clientID string) []common.ClusterAction {
 	var assignRoles []common.ClusterAction
 	var removeRoles []common.ClusterAction
@@ -225,3 +381,35 @@ func containsRoleID(list []string, id string) bool {
 	}
 	return false
 }
+
+func containsGroup(list []*v1alpha1.KeycloakUserGroup, path string) bool {
+	for _, item := range list {
+		if item.Path == normalizeGroupPath(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsGroupPath(list []string, path string) bool {
+	for _, item := range list {
+		if normalizeGroupPath(item) == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Keycloak reports group paths with a leading slash, which is optional in the CR
+func normalizeGroupPath(path string) string {
+	return "/" + strings.Trim(path, "/")
+}
+
+func containsFederatedIdentity(list []v1alpha1.FederatedIdentity, identity v1alpha1.FederatedIdentity) bool {
+	for _, item := range list {
+		if item == identity {
+			return true
+		}
+	}
+	return false
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	"github.com/keycloak/keycloak-operator/pkg/common"
+	"github.com/keycloak/keycloak-operator/pkg/model"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -117,3 +118,257 @@ func TestKeycloakRealmReconciler_Reconcile(t *testing.T) {
 	assert.IsType(t, &common.UpdateUserAction{}, desiredState[1])
 	assert.IsType(t, &common.AssignRealmRoleAction{}, desiredState[2])
 }
+
+func TestKeycloakUserReconciler_Test_Credential_Reset(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	realm := getDummyRealm()
+	reconciler := NewKeycloakuserReconciler(keycloak, realm)
+	user := getDummyUser()
+	user.Spec.User.RealmRoles = nil
+
+	state := getDummyState(keycloak)
+	state.User = &user.Spec.User
+	state.Secret = model.UserCredentialSecretApplied(user, &v12.Secret{})
+
+	// when
+	desiredState := reconciler.Reconcile(state, user)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - update user, credentials are unchanged
+	assert.Len(t, desiredState, 2)
+	assert.IsType(t, &common.UpdateUserAction{}, desiredState[1])
+
+	// when the rotation marker changes
+	user.Annotations = map[string]string{model.UserCredentialsRotationAnnotation: "1"}
+	desiredState = reconciler.Reconcile(state, user)
+
+	// then
+	assert.Len(t, desiredState, 3)
+	assert.IsType(t, &common.ResetUserPasswordAction{}, desiredState[2])
+
+	// when the credentials change
+	user.Annotations = nil
+	user.Spec.User.Credentials[0].Temporary = true
+	desiredState = reconciler.Reconcile(state, user)
+
+	// then
+	assert.Len(t, desiredState, 3)
+	assert.IsType(t, &common.ResetUserPasswordAction{}, desiredState[2])
+
+	// when the reset was applied
+	state.Secret = model.UserCredentialSecretReset(user, state.Secret)
+	desiredState = reconciler.Reconcile(state, user)
+
+	// then
+	assert.Len(t, desiredState, 2)
+	assert.Equal(t, []byte("12345"), state.Secret.Data["password"])
+}
+
+func TestKeycloakUserReconciler_Test_Actions_Email(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	realm := getDummyRealm()
+	reconciler := NewKeycloakuserReconciler(keycloak, realm)
+	user := getDummyUser()
+	user.Spec.User.RealmRoles = nil
+	user.Spec.ActionsEmail = &v1alpha1.KeycloakUserActionsEmail{
+		Actions: []string{"VERIFY_EMAIL", "UPDATE_PASSWORD"},
+	}
+
+	state := getDummyState(keycloak)
+
+	// when
+	desiredState := reconciler.Reconcile(state, user)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - create user
+	// 2 - create user secret
+	// 3 - send the actions email to the new user
+	assert.Len(t, desiredState, 4)
+	assert.IsType(t, &common.SendUserActionsEmailAction{}, desiredState[3])
+
+	// when the user exists
+	state.User = &user.Spec.User
+	state.Secret = model.UserCredentialSecretApplied(user, &v12.Secret{})
+	desiredState = reconciler.Reconcile(state, user)
+
+	// then
+	// the email is sent as long as it never went out
+	assert.Len(t, desiredState, 3)
+	assert.IsType(t, &common.SendUserActionsEmailAction{}, desiredState[2])
+
+	// when the email was sent
+	user.Status.LastActionsEmailTime = "2021-01-01T00:00:00Z"
+	desiredState = reconciler.Reconcile(state, user)
+
+	// then
+	// the email isn't sent again
+	assert.Len(t, desiredState, 2)
+
+	// when the annotation asks for another email
+	user.Annotations = map[string]string{model.SendActionsEmailAnnotation: ""}
+	desiredState = reconciler.Reconcile(state, user)
+
+	// then
+	assert.Len(t, desiredState, 3)
+	assert.IsType(t, &common.SendUserActionsEmailAction{}, desiredState[2])
+}
+
+func TestKeycloakUserReconciler_Test_Credential_Reset_Legacy_Secret(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	realm := getDummyRealm()
+	reconciler := NewKeycloakuserReconciler(keycloak, realm)
+	user := getDummyUser()
+	user.Spec.User.RealmRoles = nil
+
+	state := getDummyState(keycloak)
+	state.User = &user.Spec.User
+	state.Secret = &v12.Secret{}
+
+	// when
+	desiredState := reconciler.Reconcile(state, user)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - update user
+	// 2 - record the current credentials as applied, without a reset
+	assert.Len(t, desiredState, 3)
+	secret := desiredState[2].(*common.GenericUpdateAction).Ref.(*v12.Secret)
+	assert.Equal(t, model.UserCredentialsHash(user), secret.Annotations[model.UserCredentialsHashAnnotation])
+}
+
+func TestKeycloakUserReconciler_Test_Groups(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	realm := getDummyRealm()
+	reconciler := NewKeycloakuserReconciler(keycloak, realm)
+	user := getDummyUser()
+	user.Spec.User.RealmRoles = nil
+	user.Spec.User.Groups = []string{"/parent/child", "kept"}
+
+	state := getDummyState(keycloak)
+	state.User = &user.Spec.User
+	state.Secret = model.UserCredentialSecretApplied(user, &v12.Secret{})
+	state.Groups = []*v1alpha1.KeycloakUserGroup{
+		{ID: "keptID", Name: "kept", Path: "/kept"},
+		{ID: "removedID", Name: "removed", Path: "/parent/removed"},
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, user)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - update user
+	// 2 - join group /parent/child
+	// 3 - leave group /parent/removed
+	assert.Len(t, desiredState, 4)
+	assert.Equal(t, "/parent/child", desiredState[2].(*common.JoinGroupAction).Path)
+	assert.Equal(t, "removedID", desiredState[3].(*common.LeaveGroupAction).Ref.ID)
+}
+
+func TestKeycloakUserReconciler_Test_Groups_Unmanaged(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	realm := getDummyRealm()
+	reconciler := NewKeycloakuserReconciler(keycloak, realm)
+	user := getDummyUser()
+	user.Spec.User.RealmRoles = nil
+
+	state := getDummyState(keycloak)
+	state.User = &user.Spec.User
+	state.Secret = model.UserCredentialSecretApplied(user, &v12.Secret{})
+	state.Groups = []*v1alpha1.KeycloakUserGroup{
+		{ID: "keptID", Name: "kept", Path: "/kept"},
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, user)
+
+	// then
+	assert.Len(t, desiredState, 2)
+}
+
+func TestKeycloakUserReconciler_Test_Federated_Identities(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	realm := getDummyRealm()
+	reconciler := NewKeycloakuserReconciler(keycloak, realm)
+	user := getDummyUser()
+	user.Spec.User.RealmRoles = nil
+	user.Spec.User.FederatedIdentities = []v1alpha1.FederatedIdentity{
+		{IdentityProvider: "github", UserID: "1", UserName: "dummy"},
+		{IdentityProvider: "google", UserID: "new", UserName: "dummy"},
+		{IdentityProvider: "gitlab", UserID: "3", UserName: "dummy"},
+	}
+
+	state := getDummyState(keycloak)
+	state.User = &user.Spec.User
+	state.Secret = model.UserCredentialSecretApplied(user, &v12.Secret{})
+	state.FederatedIdentities = []v1alpha1.FederatedIdentity{
+		{IdentityProvider: "github", UserID: "1", UserName: "dummy"},
+		{IdentityProvider: "google", UserID: "old", UserName: "dummy"},
+		{IdentityProvider: "facebook", UserID: "4", UserName: "dummy"},
+	}
+
+	// when
+	desiredState := reconciler.Reconcile(state, user)
+
+	// then
+	// 0 - check keycloak available
+	// 1 - update user
+	// 2 - remove changed google link
+	// 3 - remove facebook link
+	// 4 - add changed google link
+	// 5 - add gitlab link
+	assert.Len(t, desiredState, 6)
+	assert.Equal(t, "old", desiredState[2].(*common.RemoveFederatedIdentityAction).Ref.UserID)
+	assert.Equal(t, "facebook", desiredState[3].(*common.RemoveFederatedIdentityAction).Ref.IdentityProvider)
+	assert.Equal(t, "new", desiredState[4].(*common.AddFederatedIdentityAction).Ref.UserID)
+	assert.Equal(t, "gitlab", desiredState[5].(*common.AddFederatedIdentityAction).Ref.IdentityProvider)
+}
+
+func TestKeycloakUserReconciler_Test_Attributes_Merge(t *testing.T) {
+	// given
+	keycloak := v1alpha1.Keycloak{}
+	realm := getDummyRealm()
+	reconciler := NewKeycloakuserReconciler(keycloak, realm)
+	user := getDummyUser()
+	user.Spec.User.RealmRoles = nil
+	user.Spec.User.Attributes = map[string][]string{
+		"department": {"sales"},
+		"legacy":     {},
+	}
+
+	state := getDummyState(keycloak)
+	state.User = user.Spec.User.DeepCopy()
+	state.User.Attributes = map[string][]string{
+		"department": {"support"},
+		"legacy":     {"true"},
+		"LDAP_ID":    {"1234"},
+	}
+	state.Secret = model.UserCredentialSecretApplied(user, &v12.Secret{})
+
+	// when
+	desiredState := reconciler.Reconcile(state, user)
+
+	// then
+	// by default, the attributes of the CR replace the ones of keycloak
+	assert.Equal(t, user, desiredState[1].(*common.UpdateUserAction).Ref)
+
+	// when
+	user.Spec.AttributesPolicy = v1alpha1.UserAttributesPolicyMerge
+	desiredState = reconciler.Reconcile(state, user)
+
+	// then
+	// only the listed attributes are set or removed, the CR is left as it is
+	assert.Equal(t, map[string][]string{
+		"department": {"sales"},
+		"LDAP_ID":    {"1234"},
+	}, desiredState[1].(*common.UpdateUserAction).Ref.Spec.User.Attributes)
+	assert.Len(t, user.Spec.User.Attributes, 2)
+}
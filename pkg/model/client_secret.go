@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -37,5 +39,56 @@ func ClientSecretReconciled(cr *v1alpha1.KeycloakClient, currentState *v1.Secret
 		ClientSecretClientIDProperty:     []byte(cr.Spec.Client.ClientID),
 		ClientSecretClientSecretProperty: []byte(cr.Spec.Client.Secret),
 	}
+	// ...except for the registration access token, which only keycloak issues
+	if token := currentState.Data[ClientSecretRegistrationTokenProperty]; cr.Spec.RegistrationAccessToken && len(token) > 0 {
+		reconciled.Data[ClientSecretRegistrationTokenProperty] = token
+	}
+	return reconciled
+}
+
+// Same as ClientSecretReconciled, with a newly issued registration access token
+func ClientSecretWithRegistrationToken(cr *v1alpha1.KeycloakClient, currentState *v1.Secret, token string) *v1.Secret {
+	reconciled := ClientSecretReconciled(cr, currentState)
+	reconciled.Data[ClientSecretRegistrationTokenProperty] = []byte(token)
+	return reconciled
+}
+
+// Same as ClientSecretReconciled, but also records the time of the rotation
+func ClientSecretRotated(cr *v1alpha1.KeycloakClient, currentState *v1.Secret, rotatedAt time.Time) *v1.Secret {
+	reconciled := ClientSecretReconciled(cr, currentState)
+	if reconciled.Annotations == nil {
+		reconciled.Annotations = map[string]string{}
+	}
+	reconciled.Annotations[ClientSecretLastRotationAnnotation] = rotatedAt.UTC().Format(time.RFC3339)
 	return reconciled
 }
+
+// Returns when the client secret was last rotated. Secrets that were never
+// rotated count from their creation
+func ClientSecretLastRotation(currentState *v1.Secret) time.Time {
+	if value, ok := currentState.Annotations[ClientSecretLastRotationAnnotation]; ok {
+		rotatedAt, err := time.Parse(time.RFC3339, value)
+		if err == nil {
+			return rotatedAt
+		}
+	}
+	return currentState.CreationTimestamp.Time
+}
+
+// Returns the secret a rotation has left in the client Secret, if any. It takes
+// the place of client.secret of the CR, which only sets the secret until the
+// first rotation, so that the rotated one is never written to the CR
+func RotatedClientSecret(cr *v1alpha1.KeycloakClient, currentState *v1.Secret) *string {
+	if cr.Spec.SecretRotationPeriod == nil || cr.Spec.SecretRef != nil || currentState == nil {
+		return nil
+	}
+	if _, ok := currentState.Annotations[ClientSecretLastRotationAnnotation]; !ok {
+		return nil
+	}
+	value := currentState.Data[ClientSecretClientSecretProperty]
+	if len(value) == 0 {
+		return nil
+	}
+	secret := string(value)
+	return &secret
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClientSecret_Test_Rotated_Secret(t *testing.T) {
+	// given
+	cr := &v1alpha1.KeycloakClient{
+		Spec: v1alpha1.KeycloakClientSpec{
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID: "test",
+				Secret:   "initial",
+			},
+			SecretRotationPeriod: &v12.Duration{Duration: 24 * time.Hour},
+		},
+	}
+	secret := &v1.Secret{
+		Data: map[string][]byte{
+			ClientSecretClientSecretProperty: []byte("initial"),
+		},
+	}
+
+	// when
+	// then
+	// a secret that was never rotated still comes from the CR
+	assert.Nil(t, RotatedClientSecret(cr, secret))
+
+	// when
+	rotated := ClientSecretRotated(cr, secret, time.Now())
+	rotated.Data[ClientSecretClientSecretProperty] = []byte("rotated")
+
+	// then
+	assert.Equal(t, "rotated", *RotatedClientSecret(cr, rotated))
+
+	// when
+	cr.Spec.SecretRotationPeriod = nil
+
+	// then
+	assert.Nil(t, RotatedClientSecret(cr, rotated))
+}
@@ -30,6 +30,11 @@ const (
 	DatabaseSecretVersionProperty         = "POSTGRES_VERSION"          // nolint
 	DatabaseSecretExternalAddressProperty = "POSTGRES_EXTERNAL_ADDRESS" // nolint
 	DatabaseSecretExternalPortProperty    = "POSTGRES_EXTERNAL_PORT"    // nolint
+	ExternalDatabaseUsernameProperty      = "username"                  // nolint
+	ExternalDatabasePasswordProperty      = "password"                  // nolint
+	DatabaseSSLRootCertificateVolumeName  = ApplicationName + "-db-ssl-root-cert"
+	DatabaseSSLRootCertificateMountPath   = "/etc/db-ssl"
+	DatabaseSSLRootCertificateFile        = "root.crt"
 	KeycloakServicePort                   = 8443
 	PostgresDefaultPort                   = 5432
 	AdminUsernameProperty                 = "ADMIN_USERNAME"
@@ -47,7 +52,46 @@ const (
 	ClientSecretName                      = ApplicationName + "-client-secret"
 	ClientSecretClientIDProperty          = "CLIENT_ID"
 	ClientSecretClientSecretProperty      = "CLIENT_SECRET"
+	ClientSecretRegistrationTokenProperty = "REGISTRATION_ACCESS_TOKEN"
+	ClientSecretLastRotationAnnotation    = "keycloak.org/last-secret-rotation"
+	UserCredentialsRotationAnnotation     = "keycloak.org/rotate-credentials"
+	UserCredentialsHashAnnotation         = "keycloak.org/credentials-hmac"
+	LegacyUserCredentialsHashAnnotation   = "keycloak.org/credentials-hash" // unkeyed, treated as missing
+	SendActionsEmailAnnotation            = "keycloak.org/send-actions-email"
+	SyncUserFederationAnnotation          = "keycloak.org/sync-federation"
+	SyncUserFederationModeAnnotation      = "keycloak.org/sync-federation-mode"
+	ExtraVolumesHashAnnotation            = "keycloak.org/extra-volumes-hash"
+	UserStorageProviderType               = "org.keycloak.storage.UserStorageProvider"
+	LDAPStorageMapperType                 = "org.keycloak.storage.ldap.mappers.LDAPStorageMapper"
 	MaxUnavailableNumberOfPods            = 1
 	ServiceMonitorName                    = ApplicationName + "-service-monitor"
 	MigrateBackupName                     = "migrate-backup"
+	// Lists the Ingress annotations taken from the CR, so that the ones removed from it are removed too
+	IngressManagedAnnotationsAnnotation = "keycloak.org/managed-annotations"
+	// Same keys as the encryption secret of the Integreately Backup Image
+	BackupEncryptionPublicKeyProperty  = "GPG_PUBLIC_KEY"  // nolint
+	BackupEncryptionTrustModelProperty = "GPG_TRUST_MODEL" // nolint
+	BackupEncryptionRecipientProperty  = "GPG_RECIPIENT"   // nolint
+	BackupEncryptionPrivateKeyProperty = "GPG_PRIVATE_KEY" // nolint
+	PostgresqlRestoreComponent         = "database-restore"
+	// The value of the export annotation names the Secret the realm is written to
+	ExportRealmAnnotation               = "keycloak.org/export"
+	ExportRealmClientsAnnotation        = "keycloak.org/export-clients"
+	ExportRealmGroupsAndRolesAnnotation = "keycloak.org/export-groups-and-roles"
+	RealmExportSecretRealmProperty      = "realm.json"
+	KeycloakStartupScriptsName          = ApplicationName + "-startup-scripts"
+	RemoteCacheScriptProperty           = "remote-cache.cli"
+	RemoteCacheUsernameProperty         = "username"
+	RemoteCachePasswordProperty         = "password" // nolint
+	// Keeps the key from when the scripts only configured the remote cache, so
+	// that no stale hash is left on the Pods
+	StartupScriptsHashAnnotation = "keycloak.org/remote-cache-hash"
+	// Name the startup scripts ConfigMap had while it only held the remote cache
+	LegacyRemoteCacheConfigMapName = ApplicationName + "-remote-cache"
+	KeycloakStartupScriptsPath     = "/opt/jboss/startup-scripts"
+	// Holds the new admin password while it is being rotated
+	AdminPasswordPendingProperty           = "ADMIN_PASSWORD_PENDING" // nolint
+	RotateAdminCredentialsAnnotation       = "keycloak.org/rotate-admin-credentials"
+	AdminCredentialsLastRotationAnnotation = "keycloak.org/last-admin-credentials-rotation"
+	HostnameScriptProperty                 = "hostname.cli"
 )
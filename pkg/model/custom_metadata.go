@@ -0,0 +1,77 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	v13 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Labels the Operator relies on, selectors among others, are never overwritten
+var operatorLabels = map[string]bool{
+	"app":            true,
+	"component":      true,
+	"monitoring-key": true,
+	"prometheus":     true,
+	"role":           true,
+}
+
+var operatorAnnotations = map[string]bool{
+	"description": true,
+	"service.alpha.openshift.io/serving-cert-secret-name": true,
+	"haproxy.router.openshift.io/balance":                 true,
+	"nginx.ingress.kubernetes.io/backend-protocol":        true,
+	"nginx.ingress.kubernetes.io/server-snippet":          true,
+}
+
+func isOperatorAnnotation(key string) bool {
+	return operatorAnnotations[key] || strings.HasPrefix(key, "keycloak.org/")
+}
+
+// Adds the custom labels and annotations of the CR to a resource created for it, and
+// to the Pod template of the workloads
+func ApplyCustomMetadata(cr *v1alpha1.Keycloak, obj runtime.Object) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	accessor.SetLabels(mergeCustomMetadata(accessor.GetLabels(), cr.Spec.ResourceLabels, isOperatorLabel))
+	accessor.SetAnnotations(mergeCustomMetadata(accessor.GetAnnotations(), cr.Spec.ResourceAnnotations, isOperatorAnnotation))
+
+	switch workload := obj.(type) {
+	case *v13.StatefulSet:
+		template := &workload.Spec.Template.ObjectMeta
+		template.Labels = mergeCustomMetadata(template.Labels, cr.Spec.PodLabels, isOperatorLabel)
+		template.Annotations = mergeCustomMetadata(template.Annotations, cr.Spec.PodAnnotations, isOperatorAnnotation)
+	case *v13.Deployment:
+		template := &workload.Spec.Template.ObjectMeta
+		template.Labels = mergeCustomMetadata(template.Labels, cr.Spec.PodLabels, isOperatorLabel)
+		template.Annotations = mergeCustomMetadata(template.Annotations, cr.Spec.PodAnnotations, isOperatorAnnotation)
+	}
+}
+
+func isOperatorLabel(key string) bool {
+	return operatorLabels[key]
+}
+
+// Keys owned by the Operator keep their value only where the Operator set them, so that
+// the custom values are still applied to resources the Operator doesn't label that way
+func mergeCustomMetadata(current map[string]string, custom map[string]string, isOwned func(string) bool) map[string]string {
+	if len(custom) == 0 {
+		return current
+	}
+
+	merged := make(map[string]string, len(current)+len(custom))
+	for key, value := range current {
+		merged[key] = value
+	}
+	for key, value := range custom {
+		if _, exists := current[key]; exists && isOwned(key) {
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
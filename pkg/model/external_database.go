@@ -0,0 +1,194 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The external database is configured in the CR rather than through a user provided
+// keycloak-db-secret
+func IsExternalDatabaseConfigured(cr *v1alpha1.Keycloak) bool {
+	db := cr.Spec.ExternalDatabase
+	return db.Enabled && (db.Host != "" || db.CredentialsSecret != "" || db.Database != "" || db.Port != 0)
+}
+
+func ValidateExternalDatabase(cr *v1alpha1.Keycloak, credentials *v1.Secret) error {
+	err := validateExternalDatabaseSSL(cr)
+	if err != nil || !IsExternalDatabaseConfigured(cr) {
+		return err
+	}
+
+	db := cr.Spec.ExternalDatabase
+	if db.Host == "" {
+		return errors.Errorf("externalDatabase.host is required when the external database is configured in the CR")
+	}
+	if db.CredentialsSecret == "" {
+		return errors.Errorf("externalDatabase.credentialsSecret is required when the external database is configured in the CR")
+	}
+	if credentials == nil {
+		return errors.Errorf("external database credentials secret %v not found", db.CredentialsSecret)
+	}
+	for _, key := range []string{ExternalDatabaseUsernameProperty, ExternalDatabasePasswordProperty} {
+		if len(credentials.Data[key]) == 0 {
+			return errors.Errorf("external database credentials secret %v has no %v", db.CredentialsSecret, key)
+		}
+	}
+	return nil
+}
+
+// Only the verify modes check the server certificate, require works without one
+func validateExternalDatabaseSSL(cr *v1alpha1.Keycloak) error {
+	db := cr.Spec.ExternalDatabase
+	if !db.Enabled {
+		return nil
+	}
+
+	if db.SSLRootCertificate == nil {
+		if db.SSLMode == "verify-ca" || db.SSLMode == "verify-full" {
+			return errors.Errorf("externalDatabase.sslRootCertificate is required for sslMode %v", db.SSLMode)
+		}
+		return nil
+	}
+	if (db.SSLRootCertificate.ConfigMapKeyRef == nil) == (db.SSLRootCertificate.SecretKeyRef == nil) {
+		return errors.Errorf("externalDatabase.sslRootCertificate needs exactly one of configMapKeyRef and secretKeyRef")
+	}
+	return nil
+}
+
+func ExternalDatabaseCredentialsSecret(cr *v1alpha1.Keycloak) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      cr.Spec.ExternalDatabase.CredentialsSecret,
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func ExternalDatabaseCredentialsSecretSelector(cr *v1alpha1.Keycloak) client.ObjectKey {
+	return client.ObjectKey{
+		Name:      cr.Spec.ExternalDatabase.CredentialsSecret,
+		Namespace: cr.Namespace,
+	}
+}
+
+// The database secret for an external database configured in the CR. It is the
+// same secret users provide otherwise, so the service and deployment wiring stays
+// the same
+func ExternalDatabaseSecret(cr *v1alpha1.Keycloak, credentials *v1.Secret) *v1.Secret {
+	return ExternalDatabaseSecretReconciled(cr, &v1.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      DatabaseSecretName,
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"app": ApplicationName,
+			},
+		},
+	}, credentials)
+}
+
+func ExternalDatabaseSecretReconciled(cr *v1alpha1.Keycloak, currentState *v1.Secret, credentials *v1.Secret) *v1.Secret {
+	reconciled := currentState.DeepCopy()
+	if reconciled.Data == nil {
+		reconciled.Data = make(map[string][]byte)
+	}
+
+	db := cr.Spec.ExternalDatabase
+	database := db.Database
+	if database == "" {
+		database = PostgresqlDatabase
+	}
+	port := db.Port
+	if port == 0 {
+		port = PostgresDefaultPort
+	}
+
+	reconciled.Data[DatabaseSecretExternalAddressProperty] = []byte(db.Host)
+	reconciled.Data[DatabaseSecretExternalPortProperty] = []byte(fmt.Sprintf("%v", port))
+	reconciled.Data[DatabaseSecretHostProperty] = []byte(db.Host)
+	reconciled.Data[DatabaseSecretDatabaseProperty] = []byte(database)
+	if _, ok := reconciled.Data[DatabaseSecretVersionProperty]; !ok {
+		reconciled.Data[DatabaseSecretVersionProperty] = []byte("10")
+	}
+	if credentials != nil {
+		reconciled.Data[DatabaseSecretUsernameProperty] = credentials.Data[ExternalDatabaseUsernameProperty]
+		reconciled.Data[DatabaseSecretPasswordProperty] = credentials.Data[ExternalDatabasePasswordProperty]
+	}
+	return reconciled
+}
+
+func hasExternalDatabaseSSLRootCertificate(cr *v1alpha1.Keycloak) bool {
+	return cr.Spec.ExternalDatabase.Enabled && cr.Spec.ExternalDatabase.SSLRootCertificate != nil
+}
+
+func externalDatabaseSSLRootCertificatePath() string {
+	return DatabaseSSLRootCertificateMountPath + "/" + DatabaseSSLRootCertificateFile
+}
+
+// Parameters of the JDBC connection URL, the Postgres driver takes the SSL settings from there
+func GetExternalDatabaseJDBCParams(cr *v1alpha1.Keycloak) string {
+	if !cr.Spec.ExternalDatabase.Enabled {
+		return ""
+	}
+
+	var params []string
+	if cr.Spec.ExternalDatabase.SSLMode != "" {
+		params = append(params, "sslmode="+cr.Spec.ExternalDatabase.SSLMode)
+	}
+	if hasExternalDatabaseSSLRootCertificate(cr) {
+		params = append(params, "sslrootcert="+externalDatabaseSSLRootCertificatePath())
+	}
+	return strings.Join(params, "&")
+}
+
+func getExternalDatabaseSSLVolumes(cr *v1alpha1.Keycloak) []v1.Volume {
+	if !hasExternalDatabaseSSLRootCertificate(cr) {
+		return nil
+	}
+
+	certificate := cr.Spec.ExternalDatabase.SSLRootCertificate
+	volume := v1.Volume{
+		Name: DatabaseSSLRootCertificateVolumeName,
+	}
+	if certificate.SecretKeyRef != nil {
+		volume.VolumeSource.Secret = &v1.SecretVolumeSource{
+			SecretName: certificate.SecretKeyRef.Name,
+			Items: []v1.KeyToPath{
+				{
+					Key:  certificate.SecretKeyRef.Key,
+					Path: DatabaseSSLRootCertificateFile,
+				},
+			},
+		}
+	} else {
+		volume.VolumeSource.ConfigMap = &v1.ConfigMapVolumeSource{
+			LocalObjectReference: certificate.ConfigMapKeyRef.LocalObjectReference,
+			Items: []v1.KeyToPath{
+				{
+					Key:  certificate.ConfigMapKeyRef.Key,
+					Path: DatabaseSSLRootCertificateFile,
+				},
+			},
+		}
+	}
+	return []v1.Volume{volume}
+}
+
+func getExternalDatabaseSSLVolumeMounts(cr *v1alpha1.Keycloak) []v1.VolumeMount {
+	if !hasExternalDatabaseSSLRootCertificate(cr) {
+		return nil
+	}
+
+	return []v1.VolumeMount{
+		{
+			Name:      DatabaseSSLRootCertificateVolumeName,
+			ReadOnly:  true,
+			MountPath: DatabaseSSLRootCertificateMountPath,
+		},
+	}
+}
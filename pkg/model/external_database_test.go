@@ -0,0 +1,129 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestExternalDatabase_Test_Validation(t *testing.T) {
+	// given
+	cr := &v1alpha1.Keycloak{}
+	cr.Spec.ExternalDatabase.Enabled = true
+	credentials := &v1.Secret{
+		Data: map[string][]byte{
+			ExternalDatabaseUsernameProperty: []byte("keycloak"),
+			ExternalDatabasePasswordProperty: []byte("secret"),
+		},
+	}
+
+	// then
+	// a user provided database secret needs no validation
+	assert.False(t, IsExternalDatabaseConfigured(cr))
+	assert.NoError(t, ValidateExternalDatabase(cr, nil))
+
+	// when
+	cr.Spec.ExternalDatabase.CredentialsSecret = "db-credentials"
+
+	// then
+	assert.Error(t, ValidateExternalDatabase(cr, credentials))
+
+	// when
+	cr.Spec.ExternalDatabase.Host = "db.example.com"
+
+	// then
+	assert.NoError(t, ValidateExternalDatabase(cr, credentials))
+	assert.Error(t, ValidateExternalDatabase(cr, nil))
+	assert.Error(t, ValidateExternalDatabase(cr, &v1.Secret{}))
+
+	// when
+	cr.Spec.ExternalDatabase.CredentialsSecret = ""
+
+	// then
+	assert.Error(t, ValidateExternalDatabase(cr, credentials))
+}
+
+func TestExternalDatabase_Test_Secret(t *testing.T) {
+	// given
+	cr := &v1alpha1.Keycloak{}
+	cr.Spec.ExternalDatabase = v1alpha1.KeycloakExternalDatabase{
+		Enabled:           true,
+		Host:              "db.example.com",
+		CredentialsSecret: "db-credentials",
+	}
+	credentials := &v1.Secret{
+		Data: map[string][]byte{
+			ExternalDatabaseUsernameProperty: []byte("keycloak"),
+			ExternalDatabasePasswordProperty: []byte("secret"),
+		},
+	}
+
+	// when
+	secret := ExternalDatabaseSecret(cr, credentials)
+
+	// then
+	assert.Equal(t, DatabaseSecretName, secret.Name)
+	assert.Equal(t, "db.example.com", GetExternalDatabaseHost(secret))
+	assert.Equal(t, int32(PostgresDefaultPort), GetExternalDatabasePort(secret))
+	assert.Equal(t, PostgresqlDatabase, GetExternalDatabaseName(secret))
+	assert.Equal(t, "keycloak", string(secret.Data[DatabaseSecretUsernameProperty]))
+	assert.Equal(t, "secret", string(secret.Data[DatabaseSecretPasswordProperty]))
+
+	// when
+	cr.Spec.ExternalDatabase.Port = 6432
+	cr.Spec.ExternalDatabase.Database = "keycloak"
+	credentials.Data[ExternalDatabasePasswordProperty] = []byte("rotated")
+	secret = ExternalDatabaseSecretReconciled(cr, secret, credentials)
+
+	// then
+	assert.Equal(t, int32(6432), GetExternalDatabasePort(secret))
+	assert.Equal(t, "keycloak", GetExternalDatabaseName(secret))
+	assert.Equal(t, "rotated", string(secret.Data[DatabaseSecretPasswordProperty]))
+}
+
+func TestExternalDatabase_Test_SSL(t *testing.T) {
+	// given
+	cr := &v1alpha1.Keycloak{}
+	cr.Spec.ExternalDatabase = v1alpha1.KeycloakExternalDatabase{
+		Enabled: true,
+		SSLMode: "verify-full",
+	}
+
+	// then
+	assert.Error(t, ValidateExternalDatabase(cr, nil))
+
+	// when
+	cr.Spec.ExternalDatabase.SSLRootCertificate = &v1alpha1.KeycloakExternalDatabaseCertificate{
+		SecretKeyRef: &v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "db-ca"},
+			Key:                  "ca.crt",
+		},
+	}
+	deployment := KeycloakDeployment(cr, nil)
+
+	// then
+	assert.NoError(t, ValidateExternalDatabase(cr, nil))
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Env, v1.EnvVar{
+		Name:  "JDBC_PARAMS",
+		Value: "sslmode=verify-full&sslrootcert=" + DatabaseSSLRootCertificateMountPath + "/" + DatabaseSSLRootCertificateFile,
+	})
+	assert.Contains(t, deployment.Spec.Template.Spec.Volumes, getExternalDatabaseSSLVolumes(cr)[0])
+	assert.Equal(t, "db-ca", getExternalDatabaseSSLVolumes(cr)[0].Secret.SecretName)
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].VolumeMounts, getExternalDatabaseSSLVolumeMounts(cr)[0])
+
+	// when
+	cr.Spec.ExternalDatabase.SSLMode = "require"
+	cr.Spec.ExternalDatabase.SSLRootCertificate = nil
+	deployment = KeycloakDeployment(cr, nil)
+
+	// then
+	// no certificate is needed to only encrypt the connection
+	assert.NoError(t, ValidateExternalDatabase(cr, nil))
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Env, v1.EnvVar{
+		Name:  "JDBC_PARAMS",
+		Value: "sslmode=require",
+	})
+	assert.Nil(t, getExternalDatabaseSSLVolumes(cr))
+}
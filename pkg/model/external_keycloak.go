@@ -0,0 +1,26 @@
+package model
+
+import (
+	"net/url"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+)
+
+func IsExternalKeycloak(cr *v1alpha1.Keycloak) bool {
+	return cr.Spec.Unmanaged && cr.Spec.External.Enabled
+}
+
+// Nothing is deployed for an external Keycloak, the other controllers only need
+// to be able to log in to it
+func ValidateExternalKeycloak(cr *v1alpha1.Keycloak, credentials *v1.Secret) error {
+	if cr.Spec.External.URL == "" {
+		return errors.Errorf("external.url is required when external.enabled is true")
+	}
+	endpoint, err := url.Parse(cr.Spec.External.URL)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return errors.Errorf("external.url %v is not an absolute URL", cr.Spec.External.URL)
+	}
+	return validateAdminCredentialsSecret(cr, credentials)
+}
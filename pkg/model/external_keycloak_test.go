@@ -0,0 +1,41 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestExternalKeycloak_Test_Validation(t *testing.T) {
+	// given
+	cr := &v1alpha1.Keycloak{}
+	cr.Name = "external"
+	cr.Spec.Unmanaged = true
+	cr.Spec.External.Enabled = true
+	credentials := &v1.Secret{
+		Data: map[string][]byte{
+			AdminUsernameProperty: []byte("admin"),
+			AdminPasswordProperty: []byte("secret"),
+		},
+	}
+
+	// then
+	assert.True(t, IsExternalKeycloak(cr))
+	assert.EqualError(t, ValidateExternalKeycloak(cr, credentials), "external.url is required when external.enabled is true")
+
+	// when
+	cr.Spec.External.URL = "sso.example.com"
+
+	// then
+	assert.Error(t, ValidateExternalKeycloak(cr, credentials))
+
+	// when
+	cr.Spec.External.URL = "https://sso.example.com"
+
+	// then
+	assert.NoError(t, ValidateExternalKeycloak(cr, credentials))
+	assert.EqualError(t, ValidateExternalKeycloak(cr, nil), "admin credentials secret credential-external not found")
+	assert.EqualError(t, ValidateExternalKeycloak(cr, &v1.Secret{}), "admin credentials secret credential-external has no ADMIN_USERNAME")
+}
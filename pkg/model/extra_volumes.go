@@ -0,0 +1,85 @@
+package model
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	v13 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Names of the ConfigMaps and Secrets backing the extra volumes of the Keycloak Pods
+func ExtraVolumeSources(cr *v1alpha1.Keycloak) (configMaps []string, secrets []string) {
+	for _, volume := range cr.Spec.KeycloakDeploymentSpec.ExtraVolumes {
+		if volume.ConfigMap != nil {
+			configMaps = append(configMaps, volume.ConfigMap.Name)
+		}
+		if volume.Secret != nil {
+			secrets = append(secrets, volume.Secret.SecretName)
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					configMaps = append(configMaps, source.ConfigMap.Name)
+				}
+				if source.Secret != nil {
+					secrets = append(secrets, source.Secret.Name)
+				}
+			}
+		}
+	}
+	return configMaps, secrets
+}
+
+// A hash of the content of the extra volumes. Providers and themes are only loaded when
+// Keycloak starts, so a change of the hash has to roll the Pods
+func ExtraVolumesHash(configMaps []v1.ConfigMap, secrets []v1.Secret) string {
+	if len(configMaps) == 0 && len(secrets) == 0 {
+		return ""
+	}
+
+	hash := sha256.New()
+	for _, configMap := range configMaps {
+		writeHashEntries(hash, "configmap/"+configMap.Name, configMap.Data, configMap.BinaryData)
+	}
+	for _, secret := range secrets {
+		writeHashEntries(hash, "secret/"+secret.Name, nil, secret.Data)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+func writeHashEntries(hash interface{ Write([]byte) (int, error) }, prefix string, data map[string]string, binaryData map[string][]byte) {
+	entries := make(map[string][]byte, len(data)+len(binaryData))
+	for key, value := range data {
+		entries[key] = []byte(value)
+	}
+	for key, value := range binaryData {
+		entries[key] = value
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		_, _ = hash.Write([]byte(prefix + "/" + key + "\x00"))
+		_, _ = hash.Write(entries[key])
+		_, _ = hash.Write([]byte{0})
+	}
+}
+
+func SetExtraVolumesHash(deployment *v13.StatefulSet, hash string) {
+	annotations := deployment.Spec.Template.Annotations
+	if hash == "" {
+		delete(annotations, ExtraVolumesHashAnnotation)
+		return
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ExtraVolumesHashAnnotation] = hash
+	deployment.Spec.Template.Annotations = annotations
+}
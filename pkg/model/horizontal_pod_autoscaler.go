@@ -0,0 +1,111 @@
+package model
+
+import (
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/api/autoscaling/v2beta2"
+	v1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const DefaultTargetCPUUtilizationPercentage = 80
+
+func ValidateAutoscaling(cr *v1alpha1.Keycloak) error {
+	autoscaling := cr.Spec.Autoscaling
+	if autoscaling == nil {
+		return nil
+	}
+	if autoscaling.MaxReplicas < getMinReplicas(autoscaling) {
+		return errors.Errorf("autoscaling.maxReplicas must not be lower than autoscaling.minReplicas")
+	}
+	return nil
+}
+
+func getMinReplicas(autoscaling *v1alpha1.KeycloakAutoscaling) int32 {
+	if autoscaling.MinReplicas < 1 {
+		return 1
+	}
+	return autoscaling.MinReplicas
+}
+
+// With autoscaling the Keycloak StatefulSet starts at the lower limit and keeps the
+// replicas the autoscaler sets afterwards
+func getKeycloakReplicas(cr *v1alpha1.Keycloak, current *int32) *int32 {
+	isCreate := current == nil
+	if cr.Spec.Autoscaling == nil {
+		return SanitizeNumberOfReplicas(cr.Spec.Instances, isCreate)
+	}
+	// The autoscaler doesn't scale up from 0, e.g. after a migration or a restore
+	if isCreate || *current == 0 {
+		return &[]int32{getMinReplicas(cr.Spec.Autoscaling)}[0]
+	}
+	return current
+}
+
+func HorizontalPodAutoscaler(cr *v1alpha1.Keycloak) *v2beta2.HorizontalPodAutoscaler {
+	return &v2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      KeycloakDeploymentName,
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"app": ApplicationName,
+			},
+		},
+		Spec: getHorizontalPodAutoscalerSpec(cr),
+	}
+}
+
+func HorizontalPodAutoscalerReconciled(cr *v1alpha1.Keycloak, currentState *v2beta2.HorizontalPodAutoscaler) *v2beta2.HorizontalPodAutoscaler {
+	reconciled := currentState.DeepCopy()
+	reconciled.Spec = getHorizontalPodAutoscalerSpec(cr)
+	return reconciled
+}
+
+func HorizontalPodAutoscalerSelector(cr *v1alpha1.Keycloak) client.ObjectKey {
+	return client.ObjectKey{
+		Name:      KeycloakDeploymentName,
+		Namespace: cr.Namespace,
+	}
+}
+
+func getHorizontalPodAutoscalerSpec(cr *v1alpha1.Keycloak) v2beta2.HorizontalPodAutoscalerSpec {
+	autoscaling := cr.Spec.Autoscaling
+	minReplicas := getMinReplicas(autoscaling)
+
+	var metrics []v2beta2.MetricSpec
+	cpu := autoscaling.TargetCPUUtilizationPercentage
+	if cpu == nil && autoscaling.TargetMemoryUtilizationPercentage == nil {
+		cpu = &[]int32{DefaultTargetCPUUtilizationPercentage}[0]
+	}
+	if cpu != nil {
+		metrics = append(metrics, getUtilizationMetric(v1.ResourceCPU, *cpu))
+	}
+	if autoscaling.TargetMemoryUtilizationPercentage != nil {
+		metrics = append(metrics, getUtilizationMetric(v1.ResourceMemory, *autoscaling.TargetMemoryUtilizationPercentage))
+	}
+
+	return v2beta2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: v2beta2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "StatefulSet",
+			Name:       KeycloakDeploymentName,
+		},
+		MinReplicas: &minReplicas,
+		MaxReplicas: autoscaling.MaxReplicas,
+		Metrics:     metrics,
+	}
+}
+
+func getUtilizationMetric(name v1.ResourceName, utilization int32) v2beta2.MetricSpec {
+	return v2beta2.MetricSpec{
+		Type: v2beta2.ResourceMetricSourceType,
+		Resource: &v2beta2.ResourceMetricSource{
+			Name: name,
+			Target: v2beta2.MetricTarget{
+				Type:               v2beta2.UtilizationMetricType,
+				AverageUtilization: &utilization,
+			},
+		},
+	}
+}
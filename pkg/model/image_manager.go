@@ -3,6 +3,10 @@ package model
 import (
 	"os"
 	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 )
 
 const (
@@ -22,6 +26,10 @@ const (
 	DefaultRHSSOInitContainer    = "registry.redhat.io/rh-sso-7-tech-preview/sso74-init-container-rhel8:7.4"
 	DefaultRHMIBackupContainer   = "quay.io/integreatly/backup-container:1.0.16"
 	DefaultPostgresqlImage       = "registry.access.redhat.com/rhscl/postgresql-10-rhel7:1"
+
+	// Oldest versions the Operator is known to work with
+	SupportedKeycloakVersion = "12.0.1"
+	SupportedRHSSOVersion    = "7.4"
 )
 
 var Images = NewImageManager()
@@ -68,3 +76,57 @@ func (p *ImageManager) getDefaultRHSSOImageForCurrentArchitecture() string {
 		return p.getImage(RHSSOImageOpenJDK, DefaultRHSSOImageOpenJDK)
 	}
 }
+
+// An image set in the CR is older than the version the Operator supports. Tags that are
+// not versions, like latest, and digests can't be compared and are assumed to be fine
+func IsImageOlderThanSupported(cr *v1alpha1.Keycloak) bool {
+	if cr.Spec.KeycloakDeploymentSpec.Image == "" {
+		return false
+	}
+
+	tag := GetImageTag(cr.Spec.KeycloakDeploymentSpec.Image)
+	version := parseVersion(tag)
+	if version == nil {
+		return false
+	}
+	// A shorter tag like 12.0 floats over the patch releases, so only the components
+	// it has are compared
+	supported := parseVersion(Profiles.GetSupportedVersion(cr))
+	for i := 0; i < len(version) && i < len(supported); i++ {
+		if version[i] != supported[i] {
+			return version[i] < supported[i]
+		}
+	}
+	return false
+}
+
+func GetImageTag(image string) string {
+	if strings.Contains(image, "@") {
+		return ""
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	if index := strings.LastIndex(name, ":"); index >= 0 {
+		return name[index+1:]
+	}
+	return ""
+}
+
+// The numeric components a version starts with, e.g. 12.0.1 for 12.0.1-legacy
+func parseVersion(version string) []int {
+	var parsed []int
+	for _, part := range strings.Split(version, ".") {
+		digits := part
+		if index := strings.IndexFunc(part, func(r rune) bool { return r < '0' || r > '9' }); index >= 0 {
+			digits = part[:index]
+		}
+		number, err := strconv.Atoi(digits)
+		if err != nil {
+			break
+		}
+		parsed = append(parsed, number)
+		if len(digits) != len(part) {
+			break
+		}
+	}
+	return parsed
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -64,3 +65,28 @@ func TestImageManager_test_overriding_multiple_images_using_environment_variable
 	assert.Equal(t, "RHSSOImageOpenJ9", imageChooser.Images[RHSSOImageOpenJ9])
 	assert.Equal(t, "RHSSOImageOpenJDK", imageChooser.Images[RHSSOImageOpenJDK])
 }
+
+func TestImageManager_test_image_older_than_supported(t *testing.T) {
+	//given
+	cr := &v1alpha1.Keycloak{}
+
+	//then
+	assert.False(t, IsImageOlderThanSupported(cr))
+
+	for image, older := range map[string]bool{
+		"registry.example.com:5000/keycloak/keycloak:11.0.3":   true,
+		"registry.example.com/keycloak/keycloak:12.0.1":        false,
+		"registry.example.com/keycloak/keycloak:12.0":          false,
+		"registry.example.com/keycloak/keycloak:13.0.0-mirror": false,
+		"registry.example.com/keycloak/keycloak:9-custom":      true,
+		"registry.example.com/keycloak/keycloak:latest":        false,
+		"registry.example.com:5000/keycloak/keycloak":          false,
+		"registry.example.com/keycloak/keycloak@sha256:1234":   false,
+	} {
+		//when
+		cr.Spec.KeycloakDeploymentSpec.Image = image
+
+		//then
+		assert.Equal(t, older, IsImageOlderThanSupported(cr), image)
+	}
+}
@@ -7,6 +7,11 @@ import (
 	v1 "k8s.io/api/core/v1"
 )
 
+// The init containers of the CR run after the extensions are in place
+func KeycloakInitContainers(cr *v1alpha1.Keycloak) []v1.Container {
+	return append(KeycloakExtensionsInitContainers(cr), cr.Spec.KeycloakDeploymentSpec.InitContainers...)
+}
+
 func KeycloakExtensionsInitContainers(cr *v1alpha1.Keycloak) []v1.Container {
 	return []v1.Container{
 		{
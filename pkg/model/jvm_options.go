@@ -0,0 +1,61 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Options are appended to the ones of the image, the JVM uses the last heap size it is given
+// and an explicit heap size takes precedence over any percentage based sizing
+func getJVMOptionsAppend(options *v1alpha1.KeycloakJVMOptions) string {
+	var appended []string
+	if options.InitialHeapSize != "" {
+		appended = append(appended, "-Xms"+options.InitialHeapSize)
+	}
+	if options.MaxHeapSize != "" {
+		appended = append(appended, "-Xmx"+options.MaxHeapSize)
+	}
+	appended = append(appended, options.Options...)
+	return strings.Join(appended, " ")
+}
+
+func getKeycloakJVMEnv(cr *v1alpha1.Keycloak) []v1.EnvVar {
+	options := cr.Spec.KeycloakDeploymentSpec.JVMOptions
+	if options == nil {
+		return nil
+	}
+
+	var env []v1.EnvVar
+	if appended := getJVMOptionsAppend(options); appended != "" {
+		env = append(env, v1.EnvVar{
+			Name:  "JAVA_OPTS_APPEND",
+			Value: appended,
+		})
+	}
+	return env
+}
+
+// The RH-SSO image sizes the heap from the container memory unless the ratios are 0
+func getRHSSOJVMEnv(cr *v1alpha1.Keycloak) []v1.EnvVar {
+	options := cr.Spec.KeycloakDeploymentSpec.JVMOptions
+	if options == nil {
+		return nil
+	}
+
+	env := getKeycloakJVMEnv(cr)
+	if options.InitialHeapSize != "" {
+		env = append(env, v1.EnvVar{
+			Name:  "JAVA_INITIAL_MEM_RATIO",
+			Value: "0",
+		})
+	}
+	if options.MaxHeapSize != "" {
+		env = append(env, v1.EnvVar{
+			Name:  "JAVA_MAX_MEM_RATIO",
+			Value: "0",
+		})
+	}
+	return env
+}
@@ -1,7 +1,10 @@
 package model
 
 import (
+	"time"
+
 	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -25,13 +28,66 @@ func KeycloakAdminSecret(cr *v1alpha1.Keycloak) *v1.Secret {
 	}
 }
 
+// The referenced Secret stands in for the one the Operator would create
 func KeycloakAdminSecretSelector(cr *v1alpha1.Keycloak) client.ObjectKey {
+	name := "credential-" + cr.Name
+	if cr.Spec.AdminCredentialsRef != nil {
+		name = cr.Spec.AdminCredentialsRef.Name
+	}
 	return client.ObjectKey{
-		Name:      "credential-" + cr.Name,
+		Name:      name,
 		Namespace: cr.Namespace,
 	}
 }
 
+func AdminUsernameKey(cr *v1alpha1.Keycloak) string {
+	if cr.Spec.AdminCredentialsRef != nil && cr.Spec.AdminCredentialsRef.UsernameKey != "" {
+		return cr.Spec.AdminCredentialsRef.UsernameKey
+	}
+	return AdminUsernameProperty
+}
+
+func AdminPasswordKey(cr *v1alpha1.Keycloak) string {
+	if cr.Spec.AdminCredentialsRef != nil && cr.Spec.AdminCredentialsRef.PasswordKey != "" {
+		return cr.Spec.AdminCredentialsRef.PasswordKey
+	}
+	return AdminPasswordProperty
+}
+
+func AdminClientIDKey(ref *v1alpha1.KeycloakAdminClientCredentialsRef) string {
+	if ref.ClientIDKey != "" {
+		return ref.ClientIDKey
+	}
+	return ClientSecretClientIDProperty
+}
+
+func AdminClientSecretKey(ref *v1alpha1.KeycloakAdminClientCredentialsRef) string {
+	if ref.ClientSecretKey != "" {
+		return ref.ClientSecretKey
+	}
+	return ClientSecretClientSecretProperty
+}
+
+func ValidateAdminCredentials(cr *v1alpha1.Keycloak, credentials *v1.Secret) error {
+	if cr.Spec.AdminCredentialsRef == nil {
+		return nil
+	}
+	return validateAdminCredentialsSecret(cr, credentials)
+}
+
+func validateAdminCredentialsSecret(cr *v1alpha1.Keycloak, credentials *v1.Secret) error {
+	name := KeycloakAdminSecretSelector(cr).Name
+	if credentials == nil {
+		return errors.Errorf("admin credentials secret %v not found", name)
+	}
+	for _, key := range []string{AdminUsernameKey(cr), AdminPasswordKey(cr)} {
+		if len(credentials.Data[key]) == 0 {
+			return errors.Errorf("admin credentials secret %v has no %v", name, key)
+		}
+	}
+	return nil
+}
+
 func KeycloakAdminSecretReconciled(cr *v1alpha1.Keycloak, currentState *v1.Secret) *v1.Secret {
 	reconciled := currentState.DeepCopy()
 	if val, ok := reconciled.Data[AdminUsernameProperty]; !ok || len(val) == 0 {
@@ -42,3 +98,37 @@ func KeycloakAdminSecretReconciled(cr *v1alpha1.Keycloak, currentState *v1.Secre
 	}
 	return reconciled
 }
+
+// The new password is stored next to the current one before Keycloak is told
+// about it, so that it is not lost when the rotation is interrupted
+func KeycloakAdminSecretWithPendingPassword(currentState *v1.Secret) *v1.Secret {
+	reconciled := currentState.DeepCopy()
+	if val, ok := reconciled.Data[AdminPasswordPendingProperty]; !ok || len(val) == 0 {
+		reconciled.Data[AdminPasswordPendingProperty] = []byte(GenerateRandomString(10))
+	}
+	return reconciled
+}
+
+// Makes the pending password the current one and records the time of the rotation
+func KeycloakAdminSecretRotated(currentState *v1.Secret, rotatedAt time.Time) *v1.Secret {
+	reconciled := currentState.DeepCopy()
+	reconciled.Data[AdminPasswordProperty] = reconciled.Data[AdminPasswordPendingProperty]
+	delete(reconciled.Data, AdminPasswordPendingProperty)
+	if reconciled.Annotations == nil {
+		reconciled.Annotations = map[string]string{}
+	}
+	reconciled.Annotations[AdminCredentialsLastRotationAnnotation] = rotatedAt.UTC().Format(time.RFC3339)
+	return reconciled
+}
+
+// Returns when the admin password was last rotated. Secrets that were never
+// rotated count from their creation
+func KeycloakAdminSecretLastRotation(currentState *v1.Secret) time.Time {
+	if value, ok := currentState.Annotations[AdminCredentialsLastRotationAnnotation]; ok {
+		rotatedAt, err := time.Parse(time.RFC3339, value)
+		if err == nil {
+			return rotatedAt
+		}
+	}
+	return currentState.CreationTimestamp.Time
+}
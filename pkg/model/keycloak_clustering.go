@@ -0,0 +1,101 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+const defaultCacheOwners = 2
+
+func getDiscoveryProtocol(cr *v1alpha1.Keycloak) v1alpha1.DiscoveryProtocol {
+	if cr.Spec.Clustering != nil && cr.Spec.Clustering.DiscoveryProtocol != "" {
+		return cr.Spec.Clustering.DiscoveryProtocol
+	}
+	return v1alpha1.DNSPingDiscoveryProtocol
+}
+
+// KUBE_PING only looks at the Keycloak Pods of the namespace
+func getKubePingLabels() string {
+	return "component=" + KeycloakDeploymentComponent
+}
+
+func getKeycloakDiscoveryEnv(cr *v1alpha1.Keycloak) []v1.EnvVar {
+	properties := "dns_query=" + KeycloakDiscoveryServiceName + "." + cr.Namespace
+	if getDiscoveryProtocol(cr) == v1alpha1.KubePingDiscoveryProtocol {
+		properties = fmt.Sprintf("namespace=%v,labels=%v", cr.Namespace, getKubePingLabels())
+	}
+	return []v1.EnvVar{
+		{
+			Name:  "NAMESPACE",
+			Value: cr.Namespace,
+		},
+		{
+			Name:  "JGROUPS_DISCOVERY_PROTOCOL",
+			Value: string(getDiscoveryProtocol(cr)),
+		},
+		{
+			Name:  "JGROUPS_DISCOVERY_PROPERTIES",
+			Value: properties,
+		},
+	}
+}
+
+func getRHSSODiscoveryEnv(cr *v1alpha1.Keycloak) []v1.EnvVar {
+	if getDiscoveryProtocol(cr) == v1alpha1.KubePingDiscoveryProtocol {
+		return []v1.EnvVar{
+			{
+				Name:  "JGROUPS_PING_PROTOCOL",
+				Value: string(v1alpha1.KubePingDiscoveryProtocol),
+			},
+			{
+				Name:  "KUBERNETES_NAMESPACE",
+				Value: cr.Namespace,
+			},
+			{
+				Name:  "KUBERNETES_LABELS",
+				Value: getKubePingLabels(),
+			},
+		}
+	}
+	return []v1.EnvVar{
+		{
+			Name:  "JGROUPS_PING_PROTOCOL",
+			Value: string(v1alpha1.DNSPingDiscoveryProtocol),
+		},
+		{
+			Name:  "OPENSHIFT_DNS_PING_SERVICE_NAME",
+			Value: KeycloakDiscoveryServiceName + "." + cr.Namespace + ".svc.cluster.local",
+		},
+	}
+}
+
+func getKeycloakCacheEnv(cr *v1alpha1.Keycloak) []v1.EnvVar {
+	owners, authSessionsOwners := int32(defaultCacheOwners), int32(defaultCacheOwners)
+	if cr.Spec.Clustering != nil {
+		if cr.Spec.Clustering.CacheOwners != nil {
+			owners = *cr.Spec.Clustering.CacheOwners
+		}
+		if cr.Spec.Clustering.AuthSessionsCacheOwners != nil {
+			authSessionsOwners = *cr.Spec.Clustering.AuthSessionsCacheOwners
+		}
+	}
+	return []v1.EnvVar{
+		{
+			Name:  "CACHE_OWNERS_COUNT",
+			Value: fmt.Sprintf("%v", owners),
+		},
+		{
+			Name:  "CACHE_OWNERS_AUTH_SESSIONS_COUNT",
+			Value: fmt.Sprintf("%v", authSessionsOwners),
+		},
+	}
+}
+
+func getServiceAccountName(cr *v1alpha1.Keycloak) string {
+	if cr.Spec.Clustering != nil {
+		return cr.Spec.Clustering.ServiceAccountName
+	}
+	return ""
+}
@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"strings"
 
-	"k8s.io/apimachinery/pkg/api/resource"
-
 	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	v13 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
@@ -16,10 +14,13 @@ import (
 const (
 	LivenessProbeInitialDelay  = 30
 	ReadinessProbeInitialDelay = 40
+	StartupProbeInitialDelay   = 30
 	//10s (curl) + 10s (curl) + 2s (just in case)
 	ProbeTimeoutSeconds         = 22
 	ProbeTimeBetweenRunsSeconds = 30
 	ProbeFailureThreshold       = 10
+	// Gives a cold JVM about 10 minutes to start
+	StartupProbeFailureThreshold = 20
 )
 
 func GetServiceEnvVar(suffix string) string {
@@ -29,30 +30,7 @@ func GetServiceEnvVar(suffix string) string {
 }
 
 func getResources(cr *v1alpha1.Keycloak) v1.ResourceRequirements {
-	requirements := v1.ResourceRequirements{}
-	requirements.Limits = v1.ResourceList{}
-	requirements.Requests = v1.ResourceList{}
-
-	cpu, err := resource.ParseQuantity(cr.Spec.KeycloakDeploymentSpec.Resources.Requests.Cpu().String())
-	if err == nil && cpu.String() != "0" {
-		requirements.Requests[v1.ResourceCPU] = cpu
-	}
-
-	memory, err := resource.ParseQuantity(cr.Spec.KeycloakDeploymentSpec.Resources.Requests.Memory().String())
-	if err == nil && memory.String() != "0" {
-		requirements.Requests[v1.ResourceMemory] = memory
-	}
-
-	cpu, err = resource.ParseQuantity(cr.Spec.KeycloakDeploymentSpec.Resources.Limits.Cpu().String())
-	if err == nil && cpu.String() != "0" {
-		requirements.Limits[v1.ResourceCPU] = cpu
-	}
-	memory, err = resource.ParseQuantity(cr.Spec.KeycloakDeploymentSpec.Resources.Limits.Memory().String())
-	if err == nil && memory.String() != "0" {
-		requirements.Limits[v1.ResourceMemory] = memory
-	}
-
-	return requirements
+	return getResourceRequirements(cr.Spec.KeycloakDeploymentSpec.Resources, KeycloakDefaultResources)
 }
 
 func getKeycloakEnv(cr *v1alpha1.Keycloak, dbSecret *v1.Secret) []v1.EnvVar {
@@ -100,36 +78,20 @@ func getKeycloakEnv(cr *v1alpha1.Keycloak, dbSecret *v1.Secret) []v1.EnvVar {
 				},
 			},
 		},
-		// Discovery settings
-		{
-			Name:  "NAMESPACE",
-			Value: cr.Namespace,
-		},
-		{
-			Name:  "JGROUPS_DISCOVERY_PROTOCOL",
-			Value: "dns.DNS_PING",
-		},
-		{
-			Name:  "JGROUPS_DISCOVERY_PROPERTIES",
-			Value: "dns_query=" + KeycloakDiscoveryServiceName + "." + cr.Namespace,
-		},
-		// Cache settings
-		{
-			Name:  "CACHE_OWNERS_COUNT",
-			Value: "2",
-		},
-		{
-			Name:  "CACHE_OWNERS_AUTH_SESSIONS_COUNT",
-			Value: "2",
-		},
+	}
+	env = append(env, getKeycloakDiscoveryEnv(cr)...)
+	env = append(env, getKeycloakCacheEnv(cr)...)
+	env = append(env, getRemoteCacheEnv(cr)...)
+	env = append(env, getHostnameEnv(cr)...)
+	env = append(env, []v1.EnvVar{
 		{
 			Name: "KEYCLOAK_USER",
 			ValueFrom: &v1.EnvVarSource{
 				SecretKeyRef: &v1.SecretKeySelector{
 					LocalObjectReference: v1.LocalObjectReference{
-						Name: "credential-" + cr.Name,
+						Name: KeycloakAdminSecretSelector(cr).Name,
 					},
-					Key: AdminUsernameProperty,
+					Key: AdminUsernameKey(cr),
 				},
 			},
 		},
@@ -138,9 +100,9 @@ func getKeycloakEnv(cr *v1alpha1.Keycloak, dbSecret *v1.Secret) []v1.EnvVar {
 			ValueFrom: &v1.EnvVarSource{
 				SecretKeyRef: &v1.SecretKeySelector{
 					LocalObjectReference: v1.LocalObjectReference{
-						Name: "credential-" + cr.Name,
+						Name: KeycloakAdminSecretSelector(cr).Name,
 					},
-					Key: AdminPasswordProperty,
+					Key: AdminPasswordKey(cr),
 				},
 			},
 		},
@@ -152,7 +114,7 @@ func getKeycloakEnv(cr *v1alpha1.Keycloak, dbSecret *v1.Secret) []v1.EnvVar {
 			Name:  "PROXY_ADDRESS_FORWARDING",
 			Value: "true",
 		},
-	}
+	}...)
 
 	if cr.Spec.ExternalDatabase.Enabled {
 		env = append(env, v1.EnvVar{
@@ -165,6 +127,15 @@ func getKeycloakEnv(cr *v1alpha1.Keycloak, dbSecret *v1.Secret) []v1.EnvVar {
 		})
 	}
 
+	if params := GetExternalDatabaseJDBCParams(cr); params != "" {
+		env = append(env, v1.EnvVar{
+			Name:  "JDBC_PARAMS",
+			Value: params,
+		})
+	}
+
+	env = append(env, getKeycloakJVMEnv(cr)...)
+
 	if len(cr.Spec.KeycloakDeploymentSpec.Experimental.Env) > 0 {
 		// We override Keycloak pre-defined envs with what user specified. Not the other way around.
 		env = MergeEnvs(cr.Spec.KeycloakDeploymentSpec.Experimental.Env, env)
@@ -184,7 +155,7 @@ func KeycloakDeployment(cr *v1alpha1.Keycloak, dbSecret *v1.Secret) *v13.Statefu
 			},
 		},
 		Spec: v13.StatefulSetSpec{
-			Replicas: SanitizeNumberOfReplicas(cr.Spec.Instances, true),
+			Replicas: getKeycloakReplicas(cr, nil),
 			Selector: &v12.LabelSelector{
 				MatchLabels: map[string]string{
 					"app":       ApplicationName,
@@ -201,12 +172,17 @@ func KeycloakDeployment(cr *v1alpha1.Keycloak, dbSecret *v1.Secret) *v13.Statefu
 					},
 				},
 				Spec: v1.PodSpec{
-					InitContainers: KeycloakExtensionsInitContainers(cr),
-					Volumes:        KeycloakVolumes(cr),
-					Containers: []v1.Container{
+					InitContainers:     KeycloakInitContainers(cr),
+					Volumes:            append(KeycloakVolumes(cr), getStartupScriptsVolumes(cr)...),
+					NodeSelector:       cr.Spec.KeycloakDeploymentSpec.NodeSelector,
+					Tolerations:        cr.Spec.KeycloakDeploymentSpec.Tolerations,
+					Affinity:           KeycloakAffinity(cr),
+					ImagePullSecrets:   cr.Spec.KeycloakDeploymentSpec.ImagePullSecrets,
+					ServiceAccountName: getServiceAccountName(cr),
+					Containers: append([]v1.Container{
 						{
 							Name:  KeycloakDeploymentName,
-							Image: Images.Images[KeycloakImage],
+							Image: Profiles.GetKeycloakOrRHSSOImage(cr),
 							Ports: []v1.ContainerPort{
 								{
 									ContainerPort: KeycloakServicePort,
@@ -221,15 +197,17 @@ func KeycloakDeployment(cr *v1alpha1.Keycloak, dbSecret *v1.Secret) *v13.Statefu
 									Protocol:      "TCP",
 								},
 							},
-							VolumeMounts:   KeycloakVolumeMounts(cr, KeycloakExtensionPath),
-							LivenessProbe:  livenessProbe(),
-							ReadinessProbe: readinessProbe(),
-							Env:            getKeycloakEnv(cr, dbSecret),
-							Args:           cr.Spec.KeycloakDeploymentSpec.Experimental.Args,
-							Command:        cr.Spec.KeycloakDeploymentSpec.Experimental.Command,
-							Resources:      getResources(cr),
+							VolumeMounts:    append(KeycloakVolumeMounts(cr, KeycloakExtensionPath), getStartupScriptsVolumeMounts(cr)...),
+							LivenessProbe:   livenessProbe(cr),
+							StartupProbe:    startupProbe(cr),
+							ReadinessProbe:  readinessProbe(cr),
+							Env:             getKeycloakEnv(cr, dbSecret),
+							Args:            cr.Spec.KeycloakDeploymentSpec.Experimental.Args,
+							Command:         cr.Spec.KeycloakDeploymentSpec.Experimental.Command,
+							Resources:       getResources(cr),
+							ImagePullPolicy: getImagePullPolicy(cr, ""),
 						},
-					},
+					}, cr.Spec.KeycloakDeploymentSpec.Sidecars...),
 				},
 			},
 		},
@@ -246,12 +224,12 @@ func KeycloakDeploymentSelector(cr *v1alpha1.Keycloak) client.ObjectKey {
 func KeycloakDeploymentReconciled(cr *v1alpha1.Keycloak, currentState *v13.StatefulSet, dbSecret *v1.Secret) *v13.StatefulSet {
 	reconciled := currentState.DeepCopy()
 	reconciled.ResourceVersion = currentState.ResourceVersion
-	reconciled.Spec.Replicas = SanitizeNumberOfReplicas(cr.Spec.Instances, false)
-	reconciled.Spec.Template.Spec.Volumes = KeycloakVolumes(cr)
+	reconciled.Spec.Replicas = getKeycloakReplicas(cr, currentState.Spec.Replicas)
+	reconciled.Spec.Template.Spec.Volumes = append(KeycloakVolumes(cr), getStartupScriptsVolumes(cr)...)
 	reconciled.Spec.Template.Spec.Containers = []v1.Container{
 		{
 			Name:    KeycloakDeploymentName,
-			Image:   Images.Images[KeycloakImage],
+			Image:   Profiles.GetKeycloakOrRHSSOImage(cr),
 			Args:    cr.Spec.KeycloakDeploymentSpec.Experimental.Args,
 			Command: cr.Spec.KeycloakDeploymentSpec.Experimental.Command,
 			Ports: []v1.ContainerPort{
@@ -268,17 +246,72 @@ func KeycloakDeploymentReconciled(cr *v1alpha1.Keycloak, currentState *v13.State
 					Protocol:      "TCP",
 				},
 			},
-			VolumeMounts:   KeycloakVolumeMounts(cr, KeycloakExtensionPath),
-			LivenessProbe:  livenessProbe(),
-			ReadinessProbe: readinessProbe(),
-			Env:            getKeycloakEnv(cr, dbSecret),
-			Resources:      getResources(cr),
+			VolumeMounts:    append(KeycloakVolumeMounts(cr, KeycloakExtensionPath), getStartupScriptsVolumeMounts(cr)...),
+			LivenessProbe:   livenessProbe(cr),
+			StartupProbe:    startupProbe(cr),
+			ReadinessProbe:  readinessProbe(cr),
+			Env:             getKeycloakEnv(cr, dbSecret),
+			Resources:       getResources(cr),
+			ImagePullPolicy: getImagePullPolicy(cr, ""),
 		},
 	}
-	reconciled.Spec.Template.Spec.InitContainers = KeycloakExtensionsInitContainers(cr)
+	reconciled.Spec.Template.Spec.InitContainers = KeycloakInitContainers(cr)
+	reconcileKeycloakPodSpec(cr, reconciled)
 	return reconciled
 }
 
+// Unless told otherwise, the scheduler is asked to keep replicas off the same
+// node and zone, without making it a hard requirement
+func KeycloakAffinity(cr *v1alpha1.Keycloak) *v1.Affinity {
+	if cr.Spec.KeycloakDeploymentSpec.Affinity != nil {
+		return cr.Spec.KeycloakDeploymentSpec.Affinity
+	}
+
+	selector := &v12.LabelSelector{
+		MatchLabels: map[string]string{
+			"app":       ApplicationName,
+			"component": KeycloakDeploymentComponent,
+		},
+	}
+	return &v1.Affinity{
+		PodAntiAffinity: &v1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{
+				{
+					Weight: 100,
+					PodAffinityTerm: v1.PodAffinityTerm{
+						LabelSelector: selector,
+						TopologyKey:   "kubernetes.io/hostname",
+					},
+				},
+				{
+					Weight: 50,
+					PodAffinityTerm: v1.PodAffinityTerm{
+						LabelSelector: selector,
+						TopologyKey:   "topology.kubernetes.io/zone",
+					},
+				},
+			},
+		},
+	}
+}
+
+func reconcileKeycloakPodSpec(cr *v1alpha1.Keycloak, reconciled *v13.StatefulSet) {
+	reconciled.Spec.Template.Spec.NodeSelector = cr.Spec.KeycloakDeploymentSpec.NodeSelector
+	reconciled.Spec.Template.Spec.Tolerations = cr.Spec.KeycloakDeploymentSpec.Tolerations
+	reconciled.Spec.Template.Spec.Affinity = KeycloakAffinity(cr)
+	reconciled.Spec.Template.Spec.ImagePullSecrets = cr.Spec.KeycloakDeploymentSpec.ImagePullSecrets
+	reconciled.Spec.Template.Spec.ServiceAccountName = getServiceAccountName(cr)
+	// The Keycloak container always comes first
+	reconciled.Spec.Template.Spec.Containers = append(reconciled.Spec.Template.Spec.Containers[:1], cr.Spec.KeycloakDeploymentSpec.Sidecars...)
+}
+
+func getImagePullPolicy(cr *v1alpha1.Keycloak, defaultPolicy v1.PullPolicy) v1.PullPolicy {
+	if cr.Spec.KeycloakDeploymentSpec.ImagePullPolicy != "" {
+		return cr.Spec.KeycloakDeploymentSpec.ImagePullPolicy
+	}
+	return defaultPolicy
+}
+
 func KeycloakVolumeMounts(cr *v1alpha1.Keycloak, extensionsPath string) []v1.VolumeMount {
 	mountedVolumes := []v1.VolumeMount{
 		{
@@ -296,7 +329,9 @@ func KeycloakVolumeMounts(cr *v1alpha1.Keycloak, extensionsPath string) []v1.Vol
 		},
 	}
 
+	mountedVolumes = append(mountedVolumes, getExternalDatabaseSSLVolumeMounts(cr)...)
 	mountedVolumes = addVolumeMountsFromKeycloakCR(cr, mountedVolumes)
+	mountedVolumes = append(mountedVolumes, cr.Spec.KeycloakDeploymentSpec.ExtraVolumeMounts...)
 
 	return mountedVolumes
 }
@@ -346,7 +381,9 @@ func KeycloakVolumes(cr *v1alpha1.Keycloak) []v1.Volume {
 		},
 	}
 
+	volumes = append(volumes, getExternalDatabaseSSLVolumes(cr)...)
 	volumes = addVolumesFromKeycloakCR(cr, volumes)
+	volumes = append(volumes, cr.Spec.KeycloakDeploymentSpec.ExtraVolumes...)
 
 	return volumes
 }
@@ -382,38 +419,55 @@ func addVolumesFromKeycloakCR(cr *v1alpha1.Keycloak, volumes []v1.Volume) []v1.V
 	return volumes
 }
 
-func livenessProbe() *v1.Probe {
+func livenessProbe(cr *v1alpha1.Keycloak) *v1.Probe {
+	probe := execProbe(LivenessProbeProperty, LivenessProbeInitialDelay, ProbeFailureThreshold)
+	return withProbeSettings(probe, cr.Spec.KeycloakDeploymentSpec.LivenessProbe)
+}
+
+func readinessProbe(cr *v1alpha1.Keycloak) *v1.Probe {
+	probe := execProbe(ReadinessProbeProperty, ReadinessProbeInitialDelay, ProbeFailureThreshold)
+	return withProbeSettings(probe, cr.Spec.KeycloakDeploymentSpec.ReadinessProbe)
+}
+
+// Keycloak is started once it answers the liveness probe
+func startupProbe(cr *v1alpha1.Keycloak) *v1.Probe {
+	probe := execProbe(LivenessProbeProperty, StartupProbeInitialDelay, StartupProbeFailureThreshold)
+	return withProbeSettings(probe, cr.Spec.KeycloakDeploymentSpec.StartupProbe)
+}
+
+func execProbe(script string, initialDelay int32, failureThreshold int32) *v1.Probe {
 	return &v1.Probe{
 		Handler: v1.Handler{
 			Exec: &v1.ExecAction{
 				Command: []string{
 					"/bin/sh",
 					"-c",
-					"/probes/" + LivenessProbeProperty,
+					"/probes/" + script,
 				},
 			},
 		},
-		InitialDelaySeconds: LivenessProbeInitialDelay,
+		InitialDelaySeconds: initialDelay,
 		TimeoutSeconds:      ProbeTimeoutSeconds,
 		PeriodSeconds:       ProbeTimeBetweenRunsSeconds,
-		FailureThreshold:    ProbeFailureThreshold,
+		FailureThreshold:    failureThreshold,
 	}
 }
 
-func readinessProbe() *v1.Probe {
-	return &v1.Probe{
-		Handler: v1.Handler{
-			Exec: &v1.ExecAction{
-				Command: []string{
-					"/bin/sh",
-					"-c",
-					"/probes/" + ReadinessProbeProperty,
-				},
-			},
-		},
-		InitialDelaySeconds: ReadinessProbeInitialDelay,
-		TimeoutSeconds:      ProbeTimeoutSeconds,
-		PeriodSeconds:       ProbeTimeBetweenRunsSeconds,
-		FailureThreshold:    ProbeFailureThreshold,
+func withProbeSettings(probe *v1.Probe, settings *v1alpha1.KeycloakProbe) *v1.Probe {
+	if settings == nil {
+		return probe
+	}
+	if settings.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *settings.InitialDelaySeconds
+	}
+	if settings.PeriodSeconds != nil {
+		probe.PeriodSeconds = *settings.PeriodSeconds
+	}
+	if settings.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *settings.TimeoutSeconds
+	}
+	if settings.FailureThreshold != nil {
+		probe.FailureThreshold = *settings.FailureThreshold
 	}
+	return probe
 }
@@ -212,3 +212,294 @@ func getEnvValueByName(envs []v1.EnvVar, name string) string {
 	}
 	return ""
 }
+
+func TestKeycloakDeployment_testScheduling(t *testing.T) {
+	testScheduling(t, KeycloakDeployment)
+}
+
+func testScheduling(t *testing.T, deploymentFunction createDeploymentStatefulSet) {
+	//given
+	cr := &v1alpha1.Keycloak{}
+
+	//when
+	podSpec := deploymentFunction(cr, nil).Spec.Template.Spec
+
+	//then
+	// replicas are spread softly by default
+	assert.Nil(t, podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	assert.Len(t, podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, 2)
+
+	//given
+	affinity := &v1.Affinity{
+		NodeAffinity: &v1.NodeAffinity{},
+	}
+	cr.Spec.KeycloakDeploymentSpec.NodeSelector = map[string]string{"node-role": "sso"}
+	cr.Spec.KeycloakDeploymentSpec.Tolerations = []v1.Toleration{{Key: "dedicated", Operator: v1.TolerationOpExists}}
+	cr.Spec.KeycloakDeploymentSpec.Affinity = affinity
+
+	//when
+	podSpec = deploymentFunction(cr, nil).Spec.Template.Spec
+
+	//then
+	assert.Equal(t, affinity, podSpec.Affinity)
+	assert.Equal(t, cr.Spec.KeycloakDeploymentSpec.NodeSelector, podSpec.NodeSelector)
+	assert.Equal(t, cr.Spec.KeycloakDeploymentSpec.Tolerations, podSpec.Tolerations)
+}
+
+func TestKeycloakDeployment_testImageOverride(t *testing.T) {
+	testImageOverride(t, KeycloakDeployment)
+}
+
+func testImageOverride(t *testing.T, deploymentFunction createDeploymentStatefulSet) {
+	//given
+	cr := &v1alpha1.Keycloak{}
+	cr.Spec.KeycloakDeploymentSpec.Image = "registry.example.com/keycloak/keycloak:12.0.1"
+	cr.Spec.KeycloakDeploymentSpec.ImagePullPolicy = v1.PullIfNotPresent
+	cr.Spec.KeycloakDeploymentSpec.ImagePullSecrets = []v1.LocalObjectReference{{Name: "mirror"}}
+
+	//when
+	podSpec := deploymentFunction(cr, nil).Spec.Template.Spec
+
+	//then
+	assert.Equal(t, cr.Spec.KeycloakDeploymentSpec.Image, podSpec.Containers[0].Image)
+	assert.Equal(t, v1.PullIfNotPresent, podSpec.Containers[0].ImagePullPolicy)
+	assert.Equal(t, cr.Spec.KeycloakDeploymentSpec.ImagePullSecrets, podSpec.ImagePullSecrets)
+}
+
+func TestKeycloakDeployment_testJVMOptions(t *testing.T) {
+	//given
+	cr := &v1alpha1.Keycloak{}
+	cr.Spec.KeycloakDeploymentSpec.JVMOptions = &v1alpha1.KeycloakJVMOptions{
+		InitialHeapSize: "512m",
+		MaxHeapSize:     "2g",
+		Options:         []string{"-XX:+UseG1GC"},
+	}
+
+	//when
+	envs := KeycloakDeployment(cr, nil).Spec.Template.Spec.Containers[0].Env
+
+	//then
+	assert.Contains(t, envs, v1.EnvVar{Name: "JAVA_OPTS_APPEND", Value: "-Xms512m -Xmx2g -XX:+UseG1GC"})
+
+	//when
+	envs = RHSSODeployment(cr, nil).Spec.Template.Spec.Containers[0].Env
+
+	//then
+	// percentage based sizing is turned off for an explicit heap
+	assert.Contains(t, envs, v1.EnvVar{Name: "JAVA_OPTS_APPEND", Value: "-Xms512m -Xmx2g -XX:+UseG1GC"})
+	assert.Contains(t, envs, v1.EnvVar{Name: "JAVA_INITIAL_MEM_RATIO", Value: "0"})
+	assert.Contains(t, envs, v1.EnvVar{Name: "JAVA_MAX_MEM_RATIO", Value: "0"})
+
+	//given
+	cr.Spec.KeycloakDeploymentSpec.JVMOptions = &v1alpha1.KeycloakJVMOptions{
+		Options: []string{"-XX:+UseG1GC"},
+	}
+
+	//when
+	envs = RHSSODeployment(cr, nil).Spec.Template.Spec.Containers[0].Env
+
+	//then
+	assert.NotContains(t, envs, v1.EnvVar{Name: "JAVA_MAX_MEM_RATIO", Value: "0"})
+}
+
+func TestKeycloakDeployment_testSidecars(t *testing.T) {
+	testSidecars(t, KeycloakDeployment, KeycloakDeploymentReconciled)
+}
+
+func testSidecars(t *testing.T, deploymentFunction createDeploymentStatefulSet, reconcileFunction func(*v1alpha1.Keycloak, *v13.StatefulSet, *v1.Secret) *v13.StatefulSet) {
+	//given
+	cr := &v1alpha1.Keycloak{}
+	shared := v1.VolumeMount{Name: "shared", MountPath: "/shared"}
+	cr.Spec.KeycloakDeploymentSpec.InitContainers = []v1.Container{{Name: "vault-init", VolumeMounts: []v1.VolumeMount{shared}}}
+	cr.Spec.KeycloakDeploymentSpec.Sidecars = []v1.Container{{Name: "log-shipper", VolumeMounts: []v1.VolumeMount{shared}}}
+	cr.Spec.KeycloakDeploymentSpec.ExtraVolumes = []v1.Volume{{Name: "shared", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}}}
+	cr.Spec.KeycloakDeploymentSpec.ExtraVolumeMounts = []v1.VolumeMount{shared}
+
+	//when
+	created := deploymentFunction(cr, nil)
+	reconciled := reconcileFunction(cr, created, nil)
+
+	//then
+	for _, deployment := range []*v13.StatefulSet{created, reconciled} {
+		podSpec := deployment.Spec.Template.Spec
+		assert.Len(t, podSpec.InitContainers, 2)
+		assert.Equal(t, "vault-init", podSpec.InitContainers[1].Name)
+		assert.Len(t, podSpec.Containers, 2)
+		assert.Equal(t, KeycloakDeploymentName, podSpec.Containers[0].Name)
+		assert.Equal(t, "log-shipper", podSpec.Containers[1].Name)
+		assert.Contains(t, podSpec.Volumes, cr.Spec.KeycloakDeploymentSpec.ExtraVolumes[0])
+		assert.Contains(t, podSpec.Containers[0].VolumeMounts, shared)
+	}
+}
+
+func TestKeycloakDeployment_testProbes(t *testing.T) {
+	testProbes(t, KeycloakDeployment, KeycloakDeploymentReconciled)
+}
+
+func testProbes(t *testing.T, deploymentFunction createDeploymentStatefulSet, reconcileFunction func(*v1alpha1.Keycloak, *v13.StatefulSet, *v1.Secret) *v13.StatefulSet) {
+	//given
+	cr := &v1alpha1.Keycloak{}
+
+	//when
+	container := deploymentFunction(cr, nil).Spec.Template.Spec.Containers[0]
+
+	//then
+	assert.Equal(t, int32(LivenessProbeInitialDelay), container.LivenessProbe.InitialDelaySeconds)
+	assert.Equal(t, int32(ReadinessProbeInitialDelay), container.ReadinessProbe.InitialDelaySeconds)
+	assert.Equal(t, int32(StartupProbeFailureThreshold), container.StartupProbe.FailureThreshold)
+	assert.Equal(t, container.LivenessProbe.Exec, container.StartupProbe.Exec)
+
+	//given
+	initialDelay := int32(0)
+	failureThreshold := int32(60)
+	cr.Spec.KeycloakDeploymentSpec.LivenessProbe = &v1alpha1.KeycloakProbe{InitialDelaySeconds: &initialDelay}
+	cr.Spec.KeycloakDeploymentSpec.StartupProbe = &v1alpha1.KeycloakProbe{FailureThreshold: &failureThreshold}
+
+	//when
+	created := deploymentFunction(cr, nil)
+	reconciled := reconcileFunction(cr, created, nil)
+
+	//then
+	// settings not given keep their defaults
+	for _, deployment := range []*v13.StatefulSet{created, reconciled} {
+		container = deployment.Spec.Template.Spec.Containers[0]
+		assert.Equal(t, int32(0), container.LivenessProbe.InitialDelaySeconds)
+		assert.Equal(t, int32(ProbeTimeBetweenRunsSeconds), container.LivenessProbe.PeriodSeconds)
+		assert.Equal(t, int32(60), container.StartupProbe.FailureThreshold)
+		assert.Equal(t, int32(StartupProbeInitialDelay), container.StartupProbe.InitialDelaySeconds)
+		assert.Equal(t, int32(ReadinessProbeInitialDelay), container.ReadinessProbe.InitialDelaySeconds)
+	}
+}
+
+func TestKeycloakDeployment_testClustering(t *testing.T) {
+	//given
+	cr := &v1alpha1.Keycloak{}
+	cr.Namespace = "keycloak"
+
+	//when
+	envs := KeycloakDeployment(cr, nil).Spec.Template.Spec.Containers[0].Env
+
+	//then
+	assert.Equal(t, "dns.DNS_PING", getEnvValueByName(envs, "JGROUPS_DISCOVERY_PROTOCOL"))
+	assert.Equal(t, "dns_query="+KeycloakDiscoveryServiceName+".keycloak", getEnvValueByName(envs, "JGROUPS_DISCOVERY_PROPERTIES"))
+	assert.Equal(t, "2", getEnvValueByName(envs, "CACHE_OWNERS_COUNT"))
+	assert.Equal(t, "2", getEnvValueByName(envs, "CACHE_OWNERS_AUTH_SESSIONS_COUNT"))
+
+	//given
+	owners := int32(3)
+	cr.Spec.Clustering = &v1alpha1.KeycloakClustering{
+		DiscoveryProtocol:  v1alpha1.KubePingDiscoveryProtocol,
+		CacheOwners:        &owners,
+		ServiceAccountName: "keycloak",
+	}
+
+	//when
+	created := KeycloakDeployment(cr, nil)
+	reconciled := KeycloakDeploymentReconciled(cr, created, nil)
+
+	//then
+	for _, deployment := range []*v13.StatefulSet{created, reconciled} {
+		envs = deployment.Spec.Template.Spec.Containers[0].Env
+		assert.Equal(t, "kubernetes.KUBE_PING", getEnvValueByName(envs, "JGROUPS_DISCOVERY_PROTOCOL"))
+		assert.Equal(t, "namespace=keycloak,labels=component="+KeycloakDeploymentComponent, getEnvValueByName(envs, "JGROUPS_DISCOVERY_PROPERTIES"))
+		assert.Equal(t, "3", getEnvValueByName(envs, "CACHE_OWNERS_COUNT"))
+		assert.Equal(t, "2", getEnvValueByName(envs, "CACHE_OWNERS_AUTH_SESSIONS_COUNT"))
+		assert.Equal(t, "keycloak", deployment.Spec.Template.Spec.ServiceAccountName)
+	}
+}
+
+func TestKeycloakDeployment_testRemoteCache(t *testing.T) {
+	//given
+	cr := &v1alpha1.Keycloak{}
+	cr.Namespace = "keycloak"
+
+	//when
+	deployment := KeycloakDeployment(cr, nil)
+
+	//then
+	assert.Equal(t, "", getEnvValueByName(deployment.Spec.Template.Spec.Containers[0].Env, "REMOTE_CACHE_USERNAME"))
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		assert.NotEqual(t, KeycloakStartupScriptsName, volume.Name)
+	}
+
+	//given
+	cr.Spec.RemoteCache = &v1alpha1.KeycloakRemoteCache{
+		Hosts:             []string{"infinispan-0.infinispan:11322", "infinispan-1.infinispan"},
+		CredentialsSecret: "infinispan-credentials",
+		SNIHostName:       "infinispan.example.com",
+	}
+
+	//when
+	created := KeycloakDeployment(cr, nil)
+	reconciled := KeycloakDeploymentReconciled(cr, created, nil)
+	script := KeycloakStartupScripts(cr).Data[RemoteCacheScriptProperty]
+
+	//then
+	assert.Contains(t, script, "remote-destination-outbound-socket-binding=remote-cache-0:add(host=infinispan-0.infinispan, port=11322)")
+	assert.Contains(t, script, "remote-destination-outbound-socket-binding=remote-cache-1:add(host=infinispan-1.infinispan, port=11222)")
+	assert.Contains(t, script, "distributed-cache=sessions/store=remote:add(cache=sessions, remote-servers=[remote-cache-0, remote-cache-1]")
+	assert.Contains(t, script, `"infinispan.client.hotrod.auth_password"="${env.REMOTE_CACHE_PASSWORD}"`)
+	assert.Contains(t, script, `"infinispan.client.hotrod.use_ssl"=true`)
+	assert.Contains(t, script, `"infinispan.client.hotrod.sni_host_name"=infinispan.example.com`)
+	for _, deployment := range []*v13.StatefulSet{created, reconciled} {
+		envs := deployment.Spec.Template.Spec.Containers[0].Env
+		assert.Equal(t, "tcp", getEnvValueByName(envs, "JGROUPS_TRANSPORT_STACK"))
+		for _, env := range envs {
+			if env.Name == "REMOTE_CACHE_USERNAME" {
+				assert.Equal(t, "infinispan-credentials", env.ValueFrom.SecretKeyRef.Name)
+				assert.Equal(t, RemoteCacheUsernameProperty, env.ValueFrom.SecretKeyRef.Key)
+			}
+		}
+		assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].VolumeMounts, v1.VolumeMount{
+			Name:      KeycloakStartupScriptsName,
+			MountPath: KeycloakStartupScriptsPath + "/" + RemoteCacheScriptProperty,
+			SubPath:   RemoteCacheScriptProperty,
+		})
+	}
+
+	//when
+	SetStartupScriptsHash(created, cr)
+	hash := created.Spec.Template.Annotations[StartupScriptsHashAnnotation]
+	cr.Spec.RemoteCache.Hosts = cr.Spec.RemoteCache.Hosts[:1]
+	SetStartupScriptsHash(created, cr)
+
+	//then
+	assert.NotEmpty(t, hash)
+	assert.NotEqual(t, hash, created.Spec.Template.Annotations[StartupScriptsHashAnnotation])
+}
+
+func TestKeycloakDeployment_testHostname(t *testing.T) {
+	//given
+	cr := &v1alpha1.Keycloak{}
+	cr.Spec.Hostname = &v1alpha1.KeycloakHostname{
+		Hostname: "sso.example.com",
+	}
+
+	//when
+	deployment := KeycloakDeployment(cr, nil)
+
+	//then
+	assert.Equal(t, "https://sso.example.com/auth", getEnvValueByName(deployment.Spec.Template.Spec.Containers[0].Env, "KEYCLOAK_FRONTEND_URL"))
+	assert.False(t, HasStartupScripts(cr))
+
+	//given
+	cr.Spec.Hostname.HostnameAdmin = "http://keycloak.internal:8080/auth/"
+	cr.Spec.Hostname.Strict = true
+
+	//when
+	created := KeycloakDeployment(cr, nil)
+	reconciled := KeycloakDeploymentReconciled(cr, created, nil)
+	script := KeycloakStartupScripts(cr).Data[HostnameScriptProperty]
+
+	//then
+	assert.Contains(t, script, `key=adminUrl, value="http://keycloak.internal:8080/auth")`)
+	assert.Contains(t, script, "key=forceBackendUrlToFrontendUrl, value=true")
+	for _, deployment := range []*v13.StatefulSet{created, reconciled} {
+		assert.Equal(t, "https://sso.example.com/auth", getEnvValueByName(deployment.Spec.Template.Spec.Containers[0].Env, "KEYCLOAK_FRONTEND_URL"))
+		assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].VolumeMounts, v1.VolumeMount{
+			Name:      KeycloakStartupScriptsName,
+			MountPath: KeycloakStartupScriptsPath + "/" + HostnameScriptProperty,
+			SubPath:   HostnameScriptProperty,
+		})
+	}
+}
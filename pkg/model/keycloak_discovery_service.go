@@ -29,6 +29,8 @@ func KeycloakDiscoveryService(cr *v1alpha1.Keycloak) *v1.Service {
 				},
 			},
 			ClusterIP: "None",
+			// Instances have to find each other before they are ready
+			PublishNotReadyAddresses: true,
 		},
 	}
 }
@@ -48,5 +50,6 @@ func KeycloakDiscoveryServiceReconciled(cr *v1alpha1.Keycloak, currentState *v1.
 			TargetPort: intstr.FromInt(8080),
 		},
 	}
+	reconciled.Spec.PublishNotReadyAddresses = true
 	return reconciled
 }
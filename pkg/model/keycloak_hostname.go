@@ -0,0 +1,58 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+func isHostnameEnabled(cr *v1alpha1.Keycloak) bool {
+	return cr.Spec.Hostname != nil && cr.Spec.Hostname.Hostname != ""
+}
+
+// Host names without a scheme stand for the default https URL of Keycloak
+func getHostnameURL(hostname string) string {
+	if strings.Contains(hostname, "://") {
+		return strings.TrimSuffix(hostname, "/")
+	}
+	return fmt.Sprintf("https://%v/auth", strings.TrimSuffix(hostname, "/"))
+}
+
+func getHostnameEnv(cr *v1alpha1.Keycloak) []v1.EnvVar {
+	if !isHostnameEnabled(cr) {
+		return nil
+	}
+	return []v1.EnvVar{
+		{
+			Name:  "KEYCLOAK_FRONTEND_URL",
+			Value: getHostnameURL(cr.Spec.Hostname.Hostname),
+		},
+	}
+}
+
+// The image only has an environment variable for the frontend URL, the admin URL
+// and the strict mode are properties of the hostname provider
+func getHostnameScript(cr *v1alpha1.Keycloak) string {
+	if !isHostnameEnabled(cr) {
+		return ""
+	}
+	hostname := cr.Spec.Hostname
+	if hostname.HostnameAdmin == "" && !hostname.Strict {
+		return ""
+	}
+
+	var script strings.Builder
+	script.WriteString("embed-server --server-config=standalone-ha.xml --std-out=echo\n")
+	script.WriteString("batch\n")
+	if hostname.HostnameAdmin != "" {
+		script.WriteString(fmt.Sprintf("/subsystem=keycloak-server/spi=hostname/provider=default:map-put(name=properties, key=adminUrl, value=\"%v\")\n", getHostnameURL(hostname.HostnameAdmin)))
+	}
+	if hostname.Strict {
+		script.WriteString("/subsystem=keycloak-server/spi=hostname/provider=default:map-put(name=properties, key=forceBackendUrlToFrontendUrl, value=true)\n")
+	}
+	script.WriteString("run-batch\n")
+	script.WriteString("stop-embedded-server\n")
+	return script.String()
+}
@@ -1,6 +1,9 @@
 package model
 
 import (
+	"sort"
+	"strings"
+
 	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	"k8s.io/api/extensions/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -9,10 +12,7 @@ import (
 )
 
 func KeycloakIngress(cr *kc.Keycloak) *v1beta1.Ingress {
-	ingressHost := cr.Spec.ExternalAccess.Host
-	if ingressHost == "" {
-		ingressHost = IngressDefaultHost
-	}
+	ingressHost := getIngressHost(cr)
 
 	return &v1beta1.Ingress{
 		ObjectMeta: v1.ObjectMeta{
@@ -21,15 +21,17 @@ func KeycloakIngress(cr *kc.Keycloak) *v1beta1.Ingress {
 			Labels: map[string]string{
 				"app": ApplicationName,
 			},
-			Annotations: map[string]string{
+			Annotations: getIngressAnnotations(cr, map[string]string{
 				"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS",
 				"nginx.ingress.kubernetes.io/server-snippet": `
                       location ~* "^/auth/realms/master/metrics" {
                           return 301 /auth/realms/master;
                         }`,
-			},
+			}),
 		},
 		Spec: v1beta1.IngressSpec{
+			IngressClassName: getIngressClassName(cr),
+			TLS:              getIngressTLS(cr, ingressHost),
 			Rules: []v1beta1.IngressRule{
 				{
 					Host: ingressHost,
@@ -55,9 +57,22 @@ func KeycloakIngress(cr *kc.Keycloak) *v1beta1.Ingress {
 func KeycloakIngressReconciled(cr *kc.Keycloak, currentState *v1beta1.Ingress) *v1beta1.Ingress {
 	reconciled := currentState.DeepCopy()
 	reconciledHost := currentState.Spec.Rules[0].Host
+	if cr.Spec.ExternalAccess.Ingress != nil && cr.Spec.ExternalAccess.Ingress.Host != "" {
+		reconciledHost = cr.Spec.ExternalAccess.Ingress.Host
+	}
+	// TLS is left to the user unless a secret is given in the CR
 	reconciledSpecTLS := currentState.Spec.TLS
+	if tls := getIngressTLS(cr, reconciledHost); tls != nil {
+		reconciledSpecTLS = tls
+	}
+	reconciledClassName := currentState.Spec.IngressClassName
+	if className := getIngressClassName(cr); className != nil {
+		reconciledClassName = className
+	}
+	reconciled.Annotations = getIngressAnnotations(cr, reconciled.Annotations)
 	reconciled.Spec = v1beta1.IngressSpec{
-		TLS: reconciledSpecTLS,
+		IngressClassName: reconciledClassName,
+		TLS:              reconciledSpecTLS,
 		Rules: []v1beta1.IngressRule{
 			{
 				Host: reconciledHost,
@@ -87,3 +102,69 @@ func KeycloakIngressSelector(cr *kc.Keycloak) client.ObjectKey {
 		Namespace: cr.Namespace,
 	}
 }
+
+func getIngressHost(cr *kc.Keycloak) string {
+	if cr.Spec.ExternalAccess.Ingress != nil && cr.Spec.ExternalAccess.Ingress.Host != "" {
+		return cr.Spec.ExternalAccess.Ingress.Host
+	}
+	if cr.Spec.ExternalAccess.Host != "" {
+		return cr.Spec.ExternalAccess.Host
+	}
+	return IngressDefaultHost
+}
+
+func getIngressClassName(cr *kc.Keycloak) *string {
+	if cr.Spec.ExternalAccess.Ingress == nil || cr.Spec.ExternalAccess.Ingress.ClassName == "" {
+		return nil
+	}
+	className := cr.Spec.ExternalAccess.Ingress.ClassName
+	return &className
+}
+
+func getIngressTLS(cr *kc.Keycloak, host string) []v1beta1.IngressTLS {
+	if cr.Spec.ExternalAccess.Ingress == nil || cr.Spec.ExternalAccess.Ingress.TLSSecret == "" {
+		return nil
+	}
+	return []v1beta1.IngressTLS{
+		{
+			Hosts:      []string{host},
+			SecretName: cr.Spec.ExternalAccess.Ingress.TLSSecret,
+		},
+	}
+}
+
+func getIngressAnnotations(cr *kc.Keycloak, annotations map[string]string) map[string]string {
+	var desired map[string]string
+	if cr.Spec.ExternalAccess.Ingress != nil {
+		desired = cr.Spec.ExternalAccess.Ingress.Annotations
+	}
+	managed, tracked := annotations[IngressManagedAnnotationsAnnotation]
+	if len(desired) == 0 && !tracked {
+		return annotations
+	}
+
+	merged := make(map[string]string, len(annotations)+len(desired))
+	for key, value := range annotations {
+		merged[key] = value
+	}
+	// Annotations set from the CR before but removed from it since, the defaults of the
+	// Operator are kept in place
+	for _, key := range strings.Split(managed, ",") {
+		if _, ok := desired[key]; !ok && !operatorAnnotations[key] {
+			delete(merged, key)
+		}
+	}
+	delete(merged, IngressManagedAnnotationsAnnotation)
+	if len(desired) == 0 {
+		return merged
+	}
+
+	keys := make([]string, 0, len(desired))
+	for key, value := range desired {
+		merged[key] = value
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	merged[IngressManagedAnnotationsAnnotation] = strings.Join(keys, ",")
+	return merged
+}
@@ -132,3 +132,125 @@ func TestKeycloakIngress_testHostOverrideReconciled(t *testing.T) {
 	//then
 	assert.Equal(t, "host-override", reconciledIngress.Spec.Rules[0].Host)
 }
+
+func TestKeycloakIngress_testIngressConfig(t *testing.T) {
+	//given
+	cr := &v1alpha1.Keycloak{
+		Spec: v1alpha1.KeycloakSpec{
+			ExternalAccess: v1alpha1.KeycloakExternalAccess{
+				Enabled: true,
+				Host:    "host-override",
+				Ingress: &v1alpha1.KeycloakIngressConfig{
+					ClassName:   "nginx",
+					Annotations: map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"},
+					Host:        "sso.example.com",
+					TLSSecret:   "sso-tls",
+				},
+			},
+		},
+	}
+
+	//when
+	ingress := KeycloakIngress(cr)
+
+	//then
+	assert.Equal(t, "nginx", *ingress.Spec.IngressClassName)
+	assert.Equal(t, "letsencrypt", ingress.Annotations["cert-manager.io/cluster-issuer"])
+	assert.Equal(t, "HTTPS", ingress.Annotations["nginx.ingress.kubernetes.io/backend-protocol"])
+	assert.Equal(t, "sso.example.com", ingress.Spec.Rules[0].Host)
+	assert.Equal(t, []v1beta1.IngressTLS{{Hosts: []string{"sso.example.com"}, SecretName: "sso-tls"}}, ingress.Spec.TLS)
+}
+
+func TestKeycloakIngress_testIngressConfigReconciled(t *testing.T) {
+	//given
+	currentState := &v1beta1.Ingress{
+		Spec: v1beta1.IngressSpec{
+			TLS: []v1beta1.IngressTLS{
+				{
+					Hosts:      []string{IngressDefaultHost},
+					SecretName: "keycloak-secret",
+				},
+			},
+			Rules: []v1beta1.IngressRule{
+				{
+					Host: IngressDefaultHost,
+				},
+			},
+		},
+	}
+	currentState.Annotations = map[string]string{"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS"}
+	cr := &v1alpha1.Keycloak{
+		Spec: v1alpha1.KeycloakSpec{
+			ExternalAccess: v1alpha1.KeycloakExternalAccess{
+				Enabled: true,
+				Ingress: &v1alpha1.KeycloakIngressConfig{
+					ClassName:   "nginx",
+					Annotations: map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"},
+					Host:        "sso.example.com",
+					TLSSecret:   "sso-tls",
+				},
+			},
+		},
+	}
+
+	//when
+	reconciledIngress := KeycloakIngressReconciled(cr, currentState)
+
+	//then
+	assert.Equal(t, "nginx", *reconciledIngress.Spec.IngressClassName)
+	assert.Equal(t, "letsencrypt", reconciledIngress.Annotations["cert-manager.io/cluster-issuer"])
+	assert.Equal(t, "HTTPS", reconciledIngress.Annotations["nginx.ingress.kubernetes.io/backend-protocol"])
+	assert.Equal(t, "sso.example.com", reconciledIngress.Spec.Rules[0].Host)
+	assert.Equal(t, []v1beta1.IngressTLS{{Hosts: []string{"sso.example.com"}, SecretName: "sso-tls"}}, reconciledIngress.Spec.TLS)
+}
+
+func TestKeycloakIngress_testRemovedAnnotationsReconciled(t *testing.T) {
+	//given
+	currentState := &v1beta1.Ingress{
+		Spec: v1beta1.IngressSpec{
+			Rules: []v1beta1.IngressRule{
+				{
+					Host: IngressDefaultHost,
+				},
+			},
+		},
+	}
+	currentState.Annotations = map[string]string{
+		"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS",
+		"cert-manager.io/cluster-issuer":               "letsencrypt",
+		"kubernetes.io/tls-acme":                       "true",
+		"external-dns.alpha.kubernetes.io/hostname":    "sso.example.com",
+		IngressManagedAnnotationsAnnotation:            "cert-manager.io/cluster-issuer,kubernetes.io/tls-acme",
+	}
+	cr := &v1alpha1.Keycloak{
+		Spec: v1alpha1.KeycloakSpec{
+			ExternalAccess: v1alpha1.KeycloakExternalAccess{
+				Enabled: true,
+				Ingress: &v1alpha1.KeycloakIngressConfig{
+					Annotations: map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt-staging"},
+				},
+			},
+		},
+	}
+
+	//when
+	reconciledIngress := KeycloakIngressReconciled(cr, currentState)
+
+	//then
+	assert.Equal(t, map[string]string{
+		"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS",
+		"cert-manager.io/cluster-issuer":               "letsencrypt-staging",
+		"external-dns.alpha.kubernetes.io/hostname":    "sso.example.com",
+		IngressManagedAnnotationsAnnotation:            "cert-manager.io/cluster-issuer",
+	}, reconciledIngress.Annotations)
+
+	//when
+	cr.Spec.ExternalAccess.Ingress = nil
+	reconciledIngress = KeycloakIngressReconciled(cr, reconciledIngress)
+
+	//then
+	assert.Equal(t, map[string]string{
+		"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS",
+		"external-dns.alpha.kubernetes.io/hostname":    "sso.example.com",
+	}, reconciledIngress.Annotations)
+}
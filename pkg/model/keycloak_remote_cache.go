@@ -0,0 +1,131 @@
+package model
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+const defaultRemoteCachePort = "11222"
+
+// Caches of Keycloak that can be backed by a remote Infinispan, by their type
+var remoteCaches = []struct {
+	Type string
+	Name string
+}{
+	{"replicated-cache", "work"},
+	{"distributed-cache", "sessions"},
+	{"distributed-cache", "authenticationSessions"},
+	{"distributed-cache", "offlineSessions"},
+	{"distributed-cache", "clientSessions"},
+	{"distributed-cache", "offlineClientSessions"},
+	{"distributed-cache", "loginFailures"},
+	{"distributed-cache", "actionTokens"},
+}
+
+func isRemoteCacheEnabled(cr *v1alpha1.Keycloak) bool {
+	return cr.Spec.RemoteCache != nil && len(cr.Spec.RemoteCache.Hosts) > 0
+}
+
+// The credentials are left as expressions, resolved from the environment of the
+// container, so that they never end up in the ConfigMap
+func getRemoteCacheScript(cr *v1alpha1.Keycloak) string {
+	if !isRemoteCacheEnabled(cr) {
+		return ""
+	}
+	remoteCache := cr.Spec.RemoteCache
+
+	var script strings.Builder
+	script.WriteString("embed-server --server-config=standalone-ha.xml --std-out=echo\n")
+	script.WriteString("batch\n")
+
+	var servers []string
+	for index, address := range remoteCache.Hosts {
+		host, port := splitRemoteCacheHost(address)
+		server := fmt.Sprintf("remote-cache-%v", index)
+		servers = append(servers, server)
+		script.WriteString(fmt.Sprintf("/socket-binding-group=standard-sockets/remote-destination-outbound-socket-binding=%v:add(host=%v, port=%v)\n", server, host, port))
+	}
+
+	properties := []string{
+		"rawValues=true",
+		"marshaller=org.keycloak.cluster.infinispan.KeycloakHotRodMarshallerFactory",
+	}
+	if remoteCache.CredentialsSecret != "" {
+		properties = append(properties,
+			`"infinispan.client.hotrod.auth_username"="${env.REMOTE_CACHE_USERNAME}"`,
+			`"infinispan.client.hotrod.auth_password"="${env.REMOTE_CACHE_PASSWORD}"`,
+			`"infinispan.client.hotrod.auth_realm"=default`,
+			`"infinispan.client.hotrod.auth_server_name"=infinispan`,
+			`"infinispan.client.hotrod.sasl_mechanism"=SCRAM-SHA-512`,
+		)
+	}
+	if remoteCache.TLSEnabled || remoteCache.SNIHostName != "" {
+		properties = append(properties, `"infinispan.client.hotrod.use_ssl"=true`)
+	}
+	if remoteCache.SNIHostName != "" {
+		properties = append(properties, fmt.Sprintf(`"infinispan.client.hotrod.sni_host_name"=%v`, remoteCache.SNIHostName))
+	}
+
+	for _, cache := range remoteCaches {
+		script.WriteString(fmt.Sprintf("/subsystem=infinispan/cache-container=keycloak/%v=%v/store=remote:add(cache=%v, remote-servers=[%v], passivation=false, fetch-state=false, purge=false, preload=false, shared=true, properties={%v})\n",
+			cache.Type, cache.Name, cache.Name, strings.Join(servers, ", "), strings.Join(properties, ", ")))
+	}
+
+	script.WriteString("run-batch\n")
+	script.WriteString("stop-embedded-server\n")
+	return script.String()
+}
+
+func splitRemoteCacheHost(address string) (string, string) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return address, defaultRemoteCachePort
+	}
+	return host, port
+}
+
+func getRemoteCacheEnv(cr *v1alpha1.Keycloak) []v1.EnvVar {
+	if !isRemoteCacheEnabled(cr) {
+		return nil
+	}
+	// The remote store is reached over HotRod, which doesn't go through JGroups. The
+	// pods are clustered over TCP rather than UDP multicast, as is usual for the
+	// cross-site setups a remote store is used in
+	env := []v1.EnvVar{
+		{
+			Name:  "JGROUPS_TRANSPORT_STACK",
+			Value: "tcp",
+		},
+	}
+	if cr.Spec.RemoteCache.CredentialsSecret == "" {
+		return env
+	}
+	return append(env,
+		v1.EnvVar{
+			Name: "REMOTE_CACHE_USERNAME",
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: cr.Spec.RemoteCache.CredentialsSecret,
+					},
+					Key: RemoteCacheUsernameProperty,
+				},
+			},
+		},
+		v1.EnvVar{
+			Name: "REMOTE_CACHE_PASSWORD",
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: cr.Spec.RemoteCache.CredentialsSecret,
+					},
+					Key: RemoteCachePasswordProperty,
+				},
+			},
+		},
+	)
+}
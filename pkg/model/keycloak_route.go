@@ -3,12 +3,14 @@ package model
 import (
 	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	v1 "github.com/openshift/api/route/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func KeycloakRoute(cr *kc.Keycloak) *v1.Route {
+func KeycloakRoute(cr *kc.Keycloak, destinationCA *corev1.ConfigMap) *v1.Route {
 	return &v1.Route{
 		ObjectMeta: v12.ObjectMeta{
 			Name:      ApplicationName,
@@ -16,18 +18,16 @@ func KeycloakRoute(cr *kc.Keycloak) *v1.Route {
 			Labels: map[string]string{
 				"app": ApplicationName,
 			},
-			Annotations: map[string]string{
+			Annotations: getRouteAnnotations(cr, map[string]string{
 				"haproxy.router.openshift.io/balance": RouteLoadBalancingStrategy,
-			},
+			}),
 		},
 		Spec: v1.RouteSpec{
-			Host: cr.Spec.ExternalAccess.Host,
+			Host: getRouteHost(cr),
 			Port: &v1.RoutePort{
 				TargetPort: intstr.FromString(ApplicationName),
 			},
-			TLS: &v1.TLSConfig{
-				Termination: getTLSTerminationType(cr),
-			},
+			TLS: getRouteTLSConfig(cr, destinationCA),
 			To: v1.RouteTargetReference{
 				Kind: "Service",
 				Name: ApplicationName,
@@ -36,16 +36,15 @@ func KeycloakRoute(cr *kc.Keycloak) *v1.Route {
 	}
 }
 
-func KeycloakRouteReconciled(cr *kc.Keycloak, currentState *v1.Route) *v1.Route {
+func KeycloakRouteReconciled(cr *kc.Keycloak, currentState *v1.Route, destinationCA *corev1.ConfigMap) *v1.Route {
 	reconciled := currentState.DeepCopy()
+	reconciled.Annotations = getRouteAnnotations(cr, reconciled.Annotations)
 	reconciled.Spec = v1.RouteSpec{
-		Host: cr.Spec.ExternalAccess.Host,
+		Host: getRouteHost(cr),
 		Port: &v1.RoutePort{
 			TargetPort: intstr.FromString(ApplicationName),
 		},
-		TLS: &v1.TLSConfig{
-			Termination: getTLSTerminationType(cr),
-		},
+		TLS: getRouteTLSConfig(cr, destinationCA),
 		To: v1.RouteTargetReference{
 			Kind: "Service",
 			Name: ApplicationName,
@@ -56,10 +55,90 @@ func KeycloakRouteReconciled(cr *kc.Keycloak, currentState *v1.Route) *v1.Route
 }
 
 func getTLSTerminationType(cr *kc.Keycloak) v1.TLSTerminationType {
-	if cr.Spec.ExternalAccess.TLSTermination == kc.PassthroughTLSTerminationType {
+	termination := cr.Spec.ExternalAccess.TLSTermination
+	if cr.Spec.ExternalAccess.Route != nil && cr.Spec.ExternalAccess.Route.Termination != kc.DefaultTLSTermintation {
+		termination = cr.Spec.ExternalAccess.Route.Termination
+	}
+
+	switch termination {
+	case kc.PassthroughTLSTerminationType:
 		return "passthrough"
+	default:
+		return "reencrypt"
+	}
+}
+
+func getRouteTLSConfig(cr *kc.Keycloak, destinationCA *corev1.ConfigMap) *v1.TLSConfig {
+	tls := &v1.TLSConfig{
+		Termination: getTLSTerminationType(cr),
+	}
+	if ref := getRouteDestinationCACertificateRef(cr); ref != nil && destinationCA != nil {
+		tls.DestinationCACertificate = destinationCA.Data[ref.Key]
+	}
+	return tls
+}
+
+func getRouteHost(cr *kc.Keycloak) string {
+	if cr.Spec.ExternalAccess.Route != nil && cr.Spec.ExternalAccess.Route.Host != "" {
+		return cr.Spec.ExternalAccess.Route.Host
+	}
+	return cr.Spec.ExternalAccess.Host
+}
+
+func getRouteAnnotations(cr *kc.Keycloak, annotations map[string]string) map[string]string {
+	if cr.Spec.ExternalAccess.Route == nil || len(cr.Spec.ExternalAccess.Route.Annotations) == 0 {
+		return annotations
+	}
+	merged := make(map[string]string, len(annotations)+len(cr.Spec.ExternalAccess.Route.Annotations))
+	for key, value := range annotations {
+		merged[key] = value
+	}
+	for key, value := range cr.Spec.ExternalAccess.Route.Annotations {
+		merged[key] = value
+	}
+	return merged
+}
+
+func getRouteDestinationCACertificateRef(cr *kc.Keycloak) *corev1.ConfigMapKeySelector {
+	if cr.Spec.ExternalAccess.Route == nil {
+		return nil
+	}
+	return cr.Spec.ExternalAccess.Route.DestinationCACertificate
+}
+
+// The router only verifies the destination when re-encrypting, the other
+// terminations don't connect to Keycloak with TLS of their own
+func ValidateRoute(cr *kc.Keycloak, destinationCA *corev1.ConfigMap) error {
+	ref := getRouteDestinationCACertificateRef(cr)
+	if ref == nil {
+		return nil
+	}
+	if getTLSTerminationType(cr) != "reencrypt" {
+		return errors.Errorf("externalAccess.route.destinationCACertificate requires reencrypt termination")
+	}
+	if destinationCA == nil {
+		return errors.Errorf("route destination CA config map %v not found", ref.Name)
+	}
+	if destinationCA.Data[ref.Key] == "" {
+		return errors.Errorf("route destination CA config map %v has no %v", ref.Name, ref.Key)
+	}
+	return nil
+}
+
+func RouteDestinationCAConfigMap(cr *kc.Keycloak) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      cr.Spec.ExternalAccess.Route.DestinationCACertificate.Name,
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func RouteDestinationCAConfigMapSelector(cr *kc.Keycloak) client.ObjectKey {
+	return client.ObjectKey{
+		Name:      cr.Spec.ExternalAccess.Route.DestinationCACertificate.Name,
+		Namespace: cr.Namespace,
 	}
-	return "reencrypt"
 }
 
 func KeycloakRouteSelector(cr *kc.Keycloak) client.ObjectKey {
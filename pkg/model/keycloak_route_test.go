@@ -6,6 +6,8 @@ import (
 	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	v1 "github.com/openshift/api/route/v1"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func TestKeycloakRoute_testHost(t *testing.T) {
@@ -19,7 +21,7 @@ func TestKeycloakRoute_testHost(t *testing.T) {
 	}
 
 	//when
-	route := KeycloakRoute(cr)
+	route := KeycloakRoute(cr, nil)
 
 	//then
 	assert.Equal(t, "", route.Spec.Host)
@@ -40,7 +42,7 @@ func TestKeycloakRoute_testHostReconciled(t *testing.T) {
 	}
 
 	//when
-	reconciledRoute := KeycloakRouteReconciled(cr, currentState)
+	reconciledRoute := KeycloakRouteReconciled(cr, currentState, nil)
 
 	//then
 	assert.Equal(t, "", reconciledRoute.Spec.Host)
@@ -58,7 +60,7 @@ func TestKeycloakRoute_testHostOverride(t *testing.T) {
 	}
 
 	//when
-	route := KeycloakRoute(cr)
+	route := KeycloakRoute(cr, nil)
 
 	//then
 	assert.Equal(t, "host-override", route.Spec.Host)
@@ -80,8 +82,70 @@ func TestKeycloakRoute_testHostOverrideReconciled(t *testing.T) {
 	}
 
 	//when
-	reconciledRoute := KeycloakRouteReconciled(cr, currentState)
+	reconciledRoute := KeycloakRouteReconciled(cr, currentState, nil)
 
 	//then
 	assert.Equal(t, "host-override", reconciledRoute.Spec.Host)
 }
+
+func TestKeycloakRoute_testRouteConfig(t *testing.T) {
+	//given
+	cr := &v1alpha1.Keycloak{
+		Spec: v1alpha1.KeycloakSpec{
+			ExternalAccess: v1alpha1.KeycloakExternalAccess{
+				Enabled:        true,
+				Host:           "host-override",
+				TLSTermination: v1alpha1.PassthroughTLSTerminationType,
+				Route: &v1alpha1.KeycloakRouteConfig{
+					Termination: v1alpha1.ReencryptTLSTerminationType,
+					Host:        "sso.apps.example.com",
+					Annotations: map[string]string{"haproxy.router.openshift.io/timeout": "60s"},
+					DestinationCACertificate: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "internal-ca"},
+						Key:                  "ca.crt",
+					},
+				},
+			},
+		},
+	}
+	destinationCA := &corev1.ConfigMap{
+		Data: map[string]string{"ca.crt": "-----BEGIN CERTIFICATE-----"},
+	}
+
+	//when
+	route := KeycloakRoute(cr, destinationCA)
+	reconciledRoute := KeycloakRouteReconciled(cr, &v1.Route{}, destinationCA)
+
+	//then
+	for _, r := range []*v1.Route{route, reconciledRoute} {
+		assert.Equal(t, "sso.apps.example.com", r.Spec.Host)
+		assert.Equal(t, v1.TLSTerminationReencrypt, r.Spec.TLS.Termination)
+		assert.Equal(t, "-----BEGIN CERTIFICATE-----", r.Spec.TLS.DestinationCACertificate)
+		assert.Equal(t, "60s", r.Annotations["haproxy.router.openshift.io/timeout"])
+	}
+	assert.Equal(t, RouteLoadBalancingStrategy, route.Annotations["haproxy.router.openshift.io/balance"])
+	assert.NoError(t, ValidateRoute(cr, destinationCA))
+	assert.Error(t, ValidateRoute(cr, nil))
+	assert.Error(t, ValidateRoute(cr, &corev1.ConfigMap{}))
+
+	//given
+	cr.Spec.ExternalAccess.Route.Termination = v1alpha1.PassthroughTLSTerminationType
+
+	//when
+	route = KeycloakRoute(cr, nil)
+
+	//then
+	assert.Equal(t, v1.TLSTerminationPassthrough, route.Spec.TLS.Termination)
+	assert.Error(t, ValidateRoute(cr, destinationCA))
+
+	//given
+	// edge termination would send plain HTTP to the HTTPS port of Keycloak
+	cr.Spec.ExternalAccess.Route.Termination = "edge"
+
+	//when
+	route = KeycloakRoute(cr, nil)
+
+	//then
+	assert.Equal(t, v1.TLSTerminationReencrypt, route.Spec.TLS.Termination)
+	assert.Equal(t, intstr.FromString(ApplicationName), route.Spec.Port.TargetPort)
+}
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	v13 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The startup scripts of the Keycloak image run against the embedded server before
+// it boots, they configure what the image has no environment variables for
+func getStartupScripts(cr *v1alpha1.Keycloak) map[string]string {
+	scripts := map[string]string{}
+	if script := getRemoteCacheScript(cr); script != "" {
+		scripts[RemoteCacheScriptProperty] = script
+	}
+	if script := getHostnameScript(cr); script != "" {
+		scripts[HostnameScriptProperty] = script
+	}
+	return scripts
+}
+
+func HasStartupScripts(cr *v1alpha1.Keycloak) bool {
+	return len(getStartupScripts(cr)) > 0
+}
+
+func KeycloakStartupScripts(cr *v1alpha1.Keycloak) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      KeycloakStartupScriptsName,
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"app":           ApplicationName,
+				ApplicationName: cr.Name,
+			},
+		},
+		Data: getStartupScripts(cr),
+	}
+}
+
+func KeycloakStartupScriptsSelector(cr *v1alpha1.Keycloak) client.ObjectKey {
+	return client.ObjectKey{
+		Name:      KeycloakStartupScriptsName,
+		Namespace: cr.Namespace,
+	}
+}
+
+func LegacyRemoteCacheConfigMapSelector(cr *v1alpha1.Keycloak) client.ObjectKey {
+	return client.ObjectKey{
+		Name:      LegacyRemoteCacheConfigMapName,
+		Namespace: cr.Namespace,
+	}
+}
+
+func KeycloakStartupScriptsReconciled(cr *v1alpha1.Keycloak, currentState *v1.ConfigMap) *v1.ConfigMap {
+	reconciled := currentState.DeepCopy()
+	reconciled.Data = KeycloakStartupScripts(cr).Data
+	return reconciled
+}
+
+func getStartupScriptsVolumes(cr *v1alpha1.Keycloak) []v1.Volume {
+	if !HasStartupScripts(cr) {
+		return nil
+	}
+	return []v1.Volume{
+		{
+			Name: KeycloakStartupScriptsName,
+			VolumeSource: v1.VolumeSource{
+				ConfigMap: &v1.ConfigMapVolumeSource{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: KeycloakStartupScriptsName,
+					},
+				},
+			},
+		},
+	}
+}
+
+// Every script is mounted as a single file, so that the startup scripts directory
+// stays usable for extra volumes
+func getStartupScriptsVolumeMounts(cr *v1alpha1.Keycloak) []v1.VolumeMount {
+	var mounts []v1.VolumeMount
+	for _, name := range getStartupScriptNames(cr) {
+		mounts = append(mounts, v1.VolumeMount{
+			Name:      KeycloakStartupScriptsName,
+			MountPath: KeycloakStartupScriptsPath + "/" + name,
+			SubPath:   name,
+		})
+	}
+	return mounts
+}
+
+func getStartupScriptNames(cr *v1alpha1.Keycloak) []string {
+	var names []string
+	for name := range getStartupScripts(cr) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// The scripts only run when Keycloak starts, so a change of their hash has to roll
+// the Pods
+func SetStartupScriptsHash(deployment *v13.StatefulSet, cr *v1alpha1.Keycloak) {
+	annotations := deployment.Spec.Template.Annotations
+	if !HasStartupScripts(cr) {
+		delete(annotations, StartupScriptsHashAnnotation)
+		return
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	scripts := getStartupScripts(cr)
+	hash := sha256.New()
+	for _, name := range getStartupScriptNames(cr) {
+		hash.Write([]byte(name))
+		hash.Write([]byte(scripts[name]))
+	}
+	annotations[StartupScriptsHashAnnotation] = fmt.Sprintf("%x", hash.Sum(nil))
+	deployment.Spec.Template.Annotations = annotations
+}
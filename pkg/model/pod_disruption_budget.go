@@ -2,6 +2,7 @@ package model
 
 import (
 	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/pkg/errors"
 	"k8s.io/api/policy/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -17,24 +18,57 @@ func PodDisruptionBudget(cr *v1alpha1.Keycloak) *v1beta1.PodDisruptionBudget {
 				"app": ApplicationName,
 			},
 		},
-		Spec: v1beta1.PodDisruptionBudgetSpec{
-			MaxUnavailable: &intstr.IntOrString{IntVal: MaxUnavailableNumberOfPods},
-			Selector: &v1.LabelSelector{
-				MatchLabels: map[string]string{"component": KeycloakDeploymentComponent},
-			},
-		},
+		Spec: getPodDisruptionBudgetSpec(cr),
 	}
 }
 
 func PodDisruptionBudgetReconciled(cr *v1alpha1.Keycloak, currentState *v1beta1.PodDisruptionBudget) *v1beta1.PodDisruptionBudget {
 	reconciled := currentState.DeepCopy()
-	reconciled.Spec.MaxUnavailable = &intstr.IntOrString{IntVal: MaxUnavailableNumberOfPods}
-	reconciled.Spec.Selector = &v1.LabelSelector{
-		MatchLabels: map[string]string{"component": KeycloakDeploymentComponent},
-	}
+	reconciled.Spec = getPodDisruptionBudgetSpec(cr)
 	return reconciled
 }
 
+func getPodDisruptionBudgetSpec(cr *v1alpha1.Keycloak) v1beta1.PodDisruptionBudgetSpec {
+	spec := v1beta1.PodDisruptionBudgetSpec{
+		MinAvailable:   cr.Spec.PodDisruptionBudget.MinAvailable,
+		MaxUnavailable: cr.Spec.PodDisruptionBudget.MaxUnavailable,
+		Selector: &v1.LabelSelector{
+			MatchLabels: map[string]string{"component": KeycloakDeploymentComponent},
+		},
+	}
+	if spec.MinAvailable == nil && spec.MaxUnavailable == nil {
+		spec.MaxUnavailable = &intstr.IntOrString{IntVal: MaxUnavailableNumberOfPods}
+	}
+	return spec
+}
+
+func ValidatePodDisruptionBudget(cr *v1alpha1.Keycloak) error {
+	config := cr.Spec.PodDisruptionBudget
+	if config.Enabled && config.MinAvailable != nil && config.MaxUnavailable != nil {
+		return errors.Errorf("podDisruptionBudget.minAvailable and podDisruptionBudget.maxUnavailable are mutually exclusive")
+	}
+	return nil
+}
+
+// With a single Keycloak Pod, any minAvailable above 0 never allows the Pod to be
+// evicted, which blocks node drains
+func IsPodDisruptionBudgetBlockingDrains(cr *v1alpha1.Keycloak) bool {
+	config := cr.Spec.PodDisruptionBudget
+	if !config.Enabled || config.MinAvailable == nil {
+		return false
+	}
+
+	replicas := int32(cr.Spec.Instances)
+	if cr.Spec.Autoscaling != nil {
+		replicas = getMinReplicas(cr.Spec.Autoscaling)
+	}
+	if replicas > 1 {
+		return false
+	}
+	minAvailable, err := intstr.GetValueFromIntOrPercent(config.MinAvailable, 1, true)
+	return err == nil && minAvailable >= 1
+}
+
 func PodDisruptionBudgetSelector(cr *v1alpha1.Keycloak) client.ObjectKey {
 	return client.ObjectKey{
 		Name:      ApplicationName,
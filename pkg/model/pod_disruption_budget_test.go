@@ -0,0 +1,49 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestPodDisruptionBudget_test_min_available(t *testing.T) {
+	//given
+	cr := &v1alpha1.Keycloak{}
+	cr.Spec.PodDisruptionBudget.Enabled = true
+
+	//when
+	pdb := PodDisruptionBudget(cr)
+
+	//then
+	assert.Nil(t, pdb.Spec.MinAvailable)
+	assert.Equal(t, intstr.FromInt(MaxUnavailableNumberOfPods), *pdb.Spec.MaxUnavailable)
+	assert.False(t, IsPodDisruptionBudgetBlockingDrains(cr))
+
+	//given
+	minAvailable := intstr.FromInt(1)
+	cr.Spec.PodDisruptionBudget.MinAvailable = &minAvailable
+
+	//when
+	pdb = PodDisruptionBudgetReconciled(cr, pdb)
+
+	//then
+	assert.Equal(t, minAvailable, *pdb.Spec.MinAvailable)
+	assert.Nil(t, pdb.Spec.MaxUnavailable)
+	assert.NoError(t, ValidatePodDisruptionBudget(cr))
+	assert.True(t, IsPodDisruptionBudgetBlockingDrains(cr))
+
+	//when
+	cr.Spec.Instances = 3
+
+	//then
+	assert.False(t, IsPodDisruptionBudgetBlockingDrains(cr))
+
+	//when
+	maxUnavailable := intstr.FromString("50%")
+	cr.Spec.PodDisruptionBudget.MaxUnavailable = &maxUnavailable
+
+	//then
+	assert.Error(t, ValidatePodDisruptionBudget(cr))
+}
@@ -36,8 +36,8 @@ func PostgresqlBackup(cr *v1alpha1.KeycloakBackup) *v13.Job {
 							Name:    cr.Name,
 							Image:   Images.Images[PostgresqlImage],
 							Command: []string{"/bin/sh", "-c"},
-							Args:    []string{"pg_dump $POSTGRES_DB | tee /backup/backup.sql"},
-							Env: []v1.EnvVar{
+							Args:    []string{getPostgresqlBackupCommand(cr)},
+							Env: append([]v1.EnvVar{
 								{
 									Name: "POSTGRES_USER",
 									ValueFrom: &v1.EnvVarSource{
@@ -79,7 +79,7 @@ func PostgresqlBackup(cr *v1alpha1.KeycloakBackup) *v13.Job {
 									Name:  "PGHOST",
 									Value: PostgresqlServiceName,
 								},
-							},
+							}, getPostgresqlBackupEncryptionEnv(cr)...),
 							VolumeMounts: []v1.VolumeMount{
 								{
 									Name:      PostgresqlBackupPersistentVolumeName + "-" + cr.Name,
@@ -120,8 +120,8 @@ func PostgresqlBackupReconciled(cr *v1alpha1.KeycloakBackup, currentState *v13.J
 			Name:    cr.Name,
 			Image:   Images.Images[PostgresqlImage],
 			Command: []string{"/bin/sh", "-c"},
-			Args:    []string{"pg_dump $POSTGRES_DB | tee /backup/backup.sql"},
-			Env: []v1.EnvVar{
+			Args:    []string{getPostgresqlBackupCommand(cr)},
+			Env: append([]v1.EnvVar{
 				{
 					Name: "POSTGRES_USER",
 					ValueFrom: &v1.EnvVarSource{
@@ -163,7 +163,7 @@ func PostgresqlBackupReconciled(cr *v1alpha1.KeycloakBackup, currentState *v13.J
 					Name:  "PGHOST",
 					Value: PostgresqlServiceName,
 				},
-			},
+			}, getPostgresqlBackupEncryptionEnv(cr)...),
 			VolumeMounts: []v1.VolumeMount{
 				{
 					Name:      PostgresqlBackupPersistentVolumeName + "-" + cr.Name,
@@ -176,3 +176,50 @@ func PostgresqlBackupReconciled(cr *v1alpha1.KeycloakBackup, currentState *v13.J
 	reconciled.Spec.Template.Spec.ServiceAccountName = PostgresqlBackupServiceAccountName
 	return reconciled
 }
+
+// AWS backups are encrypted by the backup container with its own key secret
+func IsBackupEncrypted(cr *v1alpha1.KeycloakBackup) bool {
+	if cr.Spec.AWS != (v1alpha1.KeycloakAWSSpec{}) {
+		return cr.Spec.AWS.EncryptionKeySecretName != ""
+	}
+	return cr.Spec.EncryptionKeySecretName != ""
+}
+
+// Encrypted dumps are only ever written to the volume encrypted, pg_dump is piped straight into gpg.
+// The pipe fails along with pg_dump, so that a failed dump isn't taken for a backup
+func getPostgresqlBackupCommand(cr *v1alpha1.KeycloakBackup) string {
+	if !IsBackupEncrypted(cr) {
+		return "pg_dump $POSTGRES_DB | tee /backup/backup.sql"
+	}
+	return "set -o pipefail && mkdir -p -m 700 $GNUPGHOME && " +
+		"echo \"$" + BackupEncryptionPublicKeyProperty + "\" | gpg --batch --import && " +
+		"pg_dump $POSTGRES_DB | gpg --batch --yes --trust-model \"$" + BackupEncryptionTrustModelProperty + "\" " +
+		"--recipient \"$" + BackupEncryptionRecipientProperty + "\" --encrypt --output /backup/backup.sql.gpg"
+}
+
+func getPostgresqlBackupEncryptionEnv(cr *v1alpha1.KeycloakBackup) []v1.EnvVar {
+	if !IsBackupEncrypted(cr) {
+		return nil
+	}
+
+	env := []v1.EnvVar{
+		{
+			Name:  "GNUPGHOME",
+			Value: "/tmp/.gnupg",
+		},
+	}
+	for _, key := range []string{BackupEncryptionPublicKeyProperty, BackupEncryptionTrustModelProperty, BackupEncryptionRecipientProperty} {
+		env = append(env, v1.EnvVar{
+			Name: key,
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: cr.Spec.EncryptionKeySecretName,
+					},
+					Key: key,
+				},
+			},
+		})
+	}
+	return env
+}
@@ -4,7 +4,6 @@ import (
 	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	v13 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
@@ -12,28 +11,7 @@ import (
 )
 
 func getPostgresResources(cr *v1alpha1.Keycloak) v1.ResourceRequirements {
-	requirements := v1.ResourceRequirements{}
-	requirements.Limits = v1.ResourceList{}
-	requirements.Requests = v1.ResourceList{}
-
-	cpu, err := resource.ParseQuantity(cr.Spec.PostgresDeploymentSpec.Resources.Requests.Cpu().String())
-	if err == nil && cpu.String() != "0" {
-		requirements.Requests[v1.ResourceCPU] = cpu
-	}
-	memory, err := resource.ParseQuantity(cr.Spec.PostgresDeploymentSpec.Resources.Requests.Memory().String())
-	if err == nil && memory.String() != "0" {
-		requirements.Requests[v1.ResourceMemory] = memory
-	}
-
-	cpu, err = resource.ParseQuantity(cr.Spec.PostgresDeploymentSpec.Resources.Limits.Cpu().String())
-	if err == nil && cpu.String() != "0" {
-		requirements.Limits[v1.ResourceCPU] = cpu
-	}
-	memory, err = resource.ParseQuantity(cr.Spec.PostgresDeploymentSpec.Resources.Limits.Memory().String())
-	if err == nil && memory.String() != "0" {
-		requirements.Limits[v1.ResourceMemory] = memory
-	}
-	return requirements
+	return getResourceRequirements(cr.Spec.PostgresDeploymentSpec.Resources, PostgresqlDefaultResources)
 }
 
 func PostgresqlDeployment(cr *v1alpha1.Keycloak, isOpenshift bool) *v13.Deployment {
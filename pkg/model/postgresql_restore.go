@@ -0,0 +1,153 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	v13 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// A failed restore leaves the database half loaded, so the Job isn't retried
+func PostgresqlRestore(cr *v1alpha1.KeycloakBackup) *v13.Job {
+	return &v13.Job{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      cr.Name + "-restore",
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"app":       ApplicationName,
+				"component": PostgresqlRestoreComponent,
+			},
+		},
+		Spec: v13.JobSpec{
+			BackoffLimit: &[]int32{0}[0],
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Volumes: []v1.Volume{
+						{
+							Name: PostgresqlBackupPersistentVolumeName + "-" + cr.Name,
+							VolumeSource: v1.VolumeSource{
+								PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+									ClaimName: PostgresqlBackupPersistentVolumeName + "-" + cr.Name,
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					Containers: []v1.Container{
+						{
+							Name:    cr.Name + "-restore",
+							Image:   Images.Images[PostgresqlImage],
+							Command: []string{"/bin/sh", "-c"},
+							Args:    []string{getPostgresqlRestoreCommand(cr)},
+							Env:     getPostgresqlRestoreEnv(cr),
+							VolumeMounts: []v1.VolumeMount{
+								{
+									Name:      PostgresqlBackupPersistentVolumeName + "-" + cr.Name,
+									MountPath: "/backup",
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					RestartPolicy:      v1.RestartPolicyNever,
+					ServiceAccountName: PostgresqlBackupServiceAccountName,
+				},
+			},
+		},
+	}
+}
+
+func PostgresqlRestoreSelector(cr *v1alpha1.KeycloakBackup) client.ObjectKey {
+	return client.ObjectKey{
+		Name:      cr.Name + "-restore",
+		Namespace: cr.Namespace,
+	}
+}
+
+func IsRestoreScalingDownKeycloak(cr *v1alpha1.KeycloakBackup) bool {
+	return cr.Spec.Restore && cr.Spec.RestoreOptions.ScaleDownKeycloak
+}
+
+func getPostgresqlRestoreCommand(cr *v1alpha1.KeycloakBackup) string {
+	psql := "psql -v ON_ERROR_STOP=1 -d $POSTGRES_DB"
+	commands := []string{
+		"set -e",
+		"tables=$(" + psql + " -tAc \"SELECT count(*) FROM information_schema.tables WHERE table_schema = 'public'\")",
+	}
+
+	if cr.Spec.RestoreOptions.Force {
+		commands = append(commands, "if [ \"$tables\" != \"0\" ]; then "+psql+" -c \"DROP SCHEMA public CASCADE; CREATE SCHEMA public;\"; fi")
+	} else {
+		commands = append(commands, "if [ \"$tables\" != \"0\" ]; then echo \"database $POSTGRES_DB is not empty, set restoreOptions.force to restore into it\" >&2; exit 1; fi")
+	}
+
+	// The dump is decrypted to a file first, a failing gpg would go unnoticed in a pipe
+	if IsBackupEncrypted(cr) {
+		commands = append(commands,
+			"mkdir -p -m 700 $GNUPGHOME",
+			"echo \"$"+BackupEncryptionPrivateKeyProperty+"\" | gpg --batch --import",
+			"gpg --batch --yes --decrypt --output /tmp/backup.sql /backup/backup.sql.gpg",
+			psql+" -f /tmp/backup.sql")
+	} else {
+		commands = append(commands, psql+" -f /backup/backup.sql")
+	}
+	return strings.Join(commands, "; ")
+}
+
+func getPostgresqlRestoreEnv(cr *v1alpha1.KeycloakBackup) []v1.EnvVar {
+	env := []v1.EnvVar{
+		{
+			Name: "PGUSER",
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: DatabaseSecretName,
+					},
+					Key: DatabaseSecretUsernameProperty,
+				},
+			},
+		},
+		{
+			Name: "PGPASSWORD",
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: DatabaseSecretName,
+					},
+					Key: DatabaseSecretPasswordProperty,
+				},
+			},
+		},
+		{
+			Name:  "POSTGRES_DB",
+			Value: PostgresqlDatabase,
+		},
+		{
+			Name:  "PGHOST",
+			Value: PostgresqlServiceName,
+		},
+	}
+	if !IsBackupEncrypted(cr) {
+		return env
+	}
+
+	return append(env,
+		v1.EnvVar{
+			Name:  "GNUPGHOME",
+			Value: "/tmp/.gnupg",
+		},
+		v1.EnvVar{
+			Name: BackupEncryptionPrivateKeyProperty,
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: cr.Spec.EncryptionKeySecretName,
+					},
+					Key: BackupEncryptionPrivateKeyProperty,
+				},
+			},
+		})
+}
@@ -37,12 +37,22 @@ func (p *ProfileManager) IsRHSSO(cr *v1alpha1.Keycloak) bool {
 }
 
 func (p *ProfileManager) GetKeycloakOrRHSSOImage(cr *v1alpha1.Keycloak) string {
+	if cr != nil && cr.Spec.KeycloakDeploymentSpec.Image != "" {
+		return cr.Spec.KeycloakDeploymentSpec.Image
+	}
 	if p.IsRHSSO(cr) {
 		return Images.Images[RHSSOImage]
 	}
 	return Images.Images[KeycloakImage]
 }
 
+func (p *ProfileManager) GetSupportedVersion(cr *v1alpha1.Keycloak) string {
+	if p.IsRHSSO(cr) {
+		return SupportedRHSSOVersion
+	}
+	return SupportedKeycloakVersion
+}
+
 func (p *ProfileManager) GetInitContainerImage(cr *v1alpha1.Keycloak) string {
 	if p.IsRHSSO(cr) {
 		return Images.Images[RHSSOInitContainer]
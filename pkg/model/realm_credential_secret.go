@@ -1,6 +1,8 @@
 package model
 
 import (
+	"encoding/json"
+
 	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,3 +36,42 @@ func RealmCredentialSecretSelector(cr *v1alpha1.KeycloakRealm, user *v1alpha1.Ke
 		Namespace: cr.Namespace,
 	}
 }
+
+// Hash of the desired credentials of a user and of its rotation marker, keyed by
+// the user. The credentials of existing users are only reset when it changes
+func UserCredentialsHash(cr *v1alpha1.KeycloakUser) string {
+	desired := struct {
+		Credentials []v1alpha1.KeycloakCredential `json:"credentials"`
+		Rotation    string                        `json:"rotation"`
+	}{
+		Credentials: cr.Spec.User.Credentials,
+		Rotation:    cr.Annotations[UserCredentialsRotationAnnotation],
+	}
+
+	// Marshalling a struct of strings and bools can't fail
+	value, _ := json.Marshal(desired)
+	return SecretHash(string(cr.UID), value)
+}
+
+// Records the current credentials of the user as applied on its credential secret
+func UserCredentialSecretApplied(cr *v1alpha1.KeycloakUser, currentState *v1.Secret) *v1.Secret {
+	reconciled := currentState.DeepCopy()
+	if reconciled.Annotations == nil {
+		reconciled.Annotations = map[string]string{}
+	}
+	reconciled.Annotations[UserCredentialsHashAnnotation] = UserCredentialsHash(cr)
+	delete(reconciled.Annotations, LegacyUserCredentialsHashAnnotation)
+	return reconciled
+}
+
+// Same as UserCredentialSecretApplied, but also stores the new credential values
+func UserCredentialSecretReset(cr *v1alpha1.KeycloakUser, currentState *v1.Secret) *v1.Secret {
+	reconciled := UserCredentialSecretApplied(cr, currentState)
+	if reconciled.Data == nil {
+		reconciled.Data = map[string][]byte{}
+	}
+	for _, credential := range cr.Spec.User.Credentials {
+		reconciled.Data[credential.Type] = []byte(credential.Value)
+	}
+	return reconciled
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func RealmExportSecret(cr *v1alpha1.KeycloakRealm, name string, realmJSON []byte) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      name,
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"app": ApplicationName,
+			},
+		},
+		Data: map[string][]byte{
+			RealmExportSecretRealmProperty: realmJSON,
+		},
+	}
+}
+
+func RealmExportSecretSelector(cr *v1alpha1.KeycloakRealm, name string) client.ObjectKey {
+	return client.ObjectKey{
+		Name:      name,
+		Namespace: cr.Namespace,
+	}
+}
+
+// Every export replaces the previous one
+func RealmExportSecretReconciled(currentState *v1.Secret, realmJSON []byte) *v1.Secret {
+	reconciled := currentState.DeepCopy()
+	if reconciled.Data == nil {
+		reconciled.Data = map[string][]byte{}
+	}
+	reconciled.Data[RealmExportSecretRealmProperty] = realmJSON
+	return reconciled
+}
+
+// An export only overwrites Secrets of the operator or owned by the realm, so that
+// the annotation can't be used to replace any other Secret of the namespace
+func IsRealmExportSecretWritable(cr *v1alpha1.KeycloakRealm, secret *v1.Secret) bool {
+	if secret.Labels["app"] == ApplicationName {
+		return true
+	}
+	for _, owner := range secret.OwnerReferences {
+		if owner.Kind == "KeycloakRealm" && owner.Name == cr.Name && owner.UID == cr.UID {
+			return true
+		}
+	}
+	return false
+}
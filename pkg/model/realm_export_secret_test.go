@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRealmExportSecret_Test_Writable(t *testing.T) {
+	// given
+	cr := &v1alpha1.KeycloakRealm{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "realm",
+			Namespace: "keycloak",
+			UID:       "realm-uid",
+		},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "tls",
+			Namespace: "keycloak",
+		},
+	}
+
+	// then
+	// any other Secret of the namespace is left alone
+	assert.False(t, IsRealmExportSecretWritable(cr, secret))
+	assert.True(t, IsRealmExportSecretWritable(cr, RealmExportSecret(cr, "export", nil)))
+
+	// when the realm owns it
+	secret.OwnerReferences = []v12.OwnerReference{{Kind: "KeycloakRealm", Name: "realm", UID: "realm-uid"}}
+
+	// then
+	assert.True(t, IsRealmExportSecretWritable(cr, secret))
+
+	// when another realm of the same name owned it
+	secret.OwnerReferences[0].UID = "other-uid"
+
+	// then
+	assert.False(t, IsRealmExportSecretWritable(cr, secret))
+}
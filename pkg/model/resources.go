@@ -0,0 +1,70 @@
+package model
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Used for any request or limit not set in the CR, so that the pods can be scheduled in
+// namespaces with a ResourceQuota
+var (
+	KeycloakDefaultResources = v1.ResourceRequirements{
+		Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("500m"),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+		Limits: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("2"),
+			v1.ResourceMemory: resource.MustParse("2Gi"),
+		},
+	}
+	PostgresqlDefaultResources = v1.ResourceRequirements{
+		Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("250m"),
+			v1.ResourceMemory: resource.MustParse("256Mi"),
+		},
+		Limits: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1"),
+			v1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+	}
+)
+
+// Merge the CPU and memory settings of the CR with the defaults. A default never ends up
+// contradicting a value of the CR: a defaulted limit is raised to the request set in the
+// CR and a defaulted request is lowered to the limit set in the CR
+func getResourceRequirements(resources v1.ResourceRequirements, defaults v1.ResourceRequirements) v1.ResourceRequirements {
+	requirements := v1.ResourceRequirements{}
+	requirements.Limits = v1.ResourceList{}
+	requirements.Requests = v1.ResourceList{}
+
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		request, requestSet := getResourceQuantity(resources.Requests, name)
+		if !requestSet {
+			request = defaults.Requests[name]
+		}
+		limit, limitSet := getResourceQuantity(resources.Limits, name)
+		if !limitSet {
+			limit = defaults.Limits[name]
+		}
+
+		if request.Cmp(limit) > 0 {
+			if !limitSet {
+				limit = request
+			} else if !requestSet {
+				request = limit
+			}
+		}
+		requirements.Requests[name] = request
+		requirements.Limits[name] = limit
+	}
+	return requirements
+}
+
+func getResourceQuantity(list v1.ResourceList, name v1.ResourceName) (resource.Quantity, bool) {
+	quantity, ok := list[name]
+	if !ok || quantity.IsZero() {
+		return resource.Quantity{}, false
+	}
+	return quantity, true
+}
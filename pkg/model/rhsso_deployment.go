@@ -55,23 +55,17 @@ func getRHSSOEnv(cr *v1alpha1.Keycloak, dbSecret *v1.Secret) []v1.EnvVar {
 			Name:  "DB_DATABASE",
 			Value: GetExternalDatabaseName(dbSecret),
 		},
-		// Discovery settings
-		{
-			Name:  "JGROUPS_PING_PROTOCOL",
-			Value: "dns.DNS_PING",
-		},
-		{
-			Name:  "OPENSHIFT_DNS_PING_SERVICE_NAME",
-			Value: KeycloakDiscoveryServiceName + "." + cr.Namespace + ".svc.cluster.local",
-		},
+	}
+	env = append(env, getRHSSODiscoveryEnv(cr)...)
+	env = append(env, []v1.EnvVar{
 		{
 			Name: "SSO_ADMIN_USERNAME",
 			ValueFrom: &v1.EnvVarSource{
 				SecretKeyRef: &v1.SecretKeySelector{
 					LocalObjectReference: v1.LocalObjectReference{
-						Name: "credential-" + cr.Name,
+						Name: KeycloakAdminSecretSelector(cr).Name,
 					},
-					Key: AdminUsernameProperty,
+					Key: AdminUsernameKey(cr),
 				},
 			},
 		},
@@ -80,9 +74,9 @@ func getRHSSOEnv(cr *v1alpha1.Keycloak, dbSecret *v1.Secret) []v1.EnvVar {
 			ValueFrom: &v1.EnvVarSource{
 				SecretKeyRef: &v1.SecretKeySelector{
 					LocalObjectReference: v1.LocalObjectReference{
-						Name: "credential-" + cr.Name,
+						Name: KeycloakAdminSecretSelector(cr).Name,
 					},
-					Key: AdminPasswordProperty,
+					Key: AdminPasswordKey(cr),
 				},
 			},
 		},
@@ -90,7 +84,7 @@ func getRHSSOEnv(cr *v1alpha1.Keycloak, dbSecret *v1.Secret) []v1.EnvVar {
 			Name:  "X509_CA_BUNDLE",
 			Value: "/var/run/secrets/kubernetes.io/serviceaccount/*.crt",
 		},
-	}
+	}...)
 
 	if cr.Spec.ExternalDatabase.Enabled {
 		env = append(env, v1.EnvVar{
@@ -103,6 +97,22 @@ func getRHSSOEnv(cr *v1alpha1.Keycloak, dbSecret *v1.Secret) []v1.EnvVar {
 		})
 	}
 
+	// The datasource of RH-SSO takes the JDBC parameters as connection properties
+	if cr.Spec.ExternalDatabase.Enabled && cr.Spec.ExternalDatabase.SSLMode != "" {
+		env = append(env, v1.EnvVar{
+			Name:  "DB_CONNECTION_PROPERTY_sslmode",
+			Value: cr.Spec.ExternalDatabase.SSLMode,
+		})
+	}
+	if hasExternalDatabaseSSLRootCertificate(cr) {
+		env = append(env, v1.EnvVar{
+			Name:  "DB_CONNECTION_PROPERTY_sslrootcert",
+			Value: externalDatabaseSSLRootCertificatePath(),
+		})
+	}
+
+	env = append(env, getRHSSOJVMEnv(cr)...)
+
 	if len(cr.Spec.KeycloakDeploymentSpec.Experimental.Env) > 0 {
 		// We override Keycloak pre-defined envs with what user specified. Not the other way around.
 		env = MergeEnvs(cr.Spec.KeycloakDeploymentSpec.Experimental.Env, env)
@@ -122,7 +132,7 @@ func RHSSODeployment(cr *v1alpha1.Keycloak, dbSecret *v1.Secret) *v13.StatefulSe
 			},
 		},
 		Spec: v13.StatefulSetSpec{
-			Replicas: SanitizeNumberOfReplicas(cr.Spec.Instances, true),
+			Replicas: getKeycloakReplicas(cr, nil),
 			Selector: &v12.LabelSelector{
 				MatchLabels: map[string]string{
 					"app":       ApplicationName,
@@ -139,12 +149,17 @@ func RHSSODeployment(cr *v1alpha1.Keycloak, dbSecret *v1.Secret) *v13.StatefulSe
 					},
 				},
 				Spec: v1.PodSpec{
-					Volumes:        KeycloakVolumes(cr),
-					InitContainers: KeycloakExtensionsInitContainers(cr),
-					Containers: []v1.Container{
+					Volumes:            KeycloakVolumes(cr),
+					InitContainers:     KeycloakInitContainers(cr),
+					NodeSelector:       cr.Spec.KeycloakDeploymentSpec.NodeSelector,
+					Tolerations:        cr.Spec.KeycloakDeploymentSpec.Tolerations,
+					Affinity:           KeycloakAffinity(cr),
+					ImagePullSecrets:   cr.Spec.KeycloakDeploymentSpec.ImagePullSecrets,
+					ServiceAccountName: getServiceAccountName(cr),
+					Containers: append([]v1.Container{
 						{
 							Name:  KeycloakDeploymentName,
-							Image: Images.Images[RHSSOImage],
+							Image: Profiles.GetKeycloakOrRHSSOImage(cr),
 							Ports: []v1.ContainerPort{
 								{
 									ContainerPort: KeycloakServicePort,
@@ -163,16 +178,17 @@ func RHSSODeployment(cr *v1alpha1.Keycloak, dbSecret *v1.Secret) *v13.StatefulSe
 									Protocol:      "TCP",
 								},
 							},
-							LivenessProbe:   livenessProbe(),
-							ReadinessProbe:  readinessProbe(),
+							LivenessProbe:   livenessProbe(cr),
+							StartupProbe:    startupProbe(cr),
+							ReadinessProbe:  readinessProbe(cr),
 							Env:             getRHSSOEnv(cr, dbSecret),
 							Args:            cr.Spec.KeycloakDeploymentSpec.Experimental.Args,
 							Command:         cr.Spec.KeycloakDeploymentSpec.Experimental.Command,
 							VolumeMounts:    KeycloakVolumeMounts(cr, RhssoExtensionPath),
 							Resources:       getResources(cr),
-							ImagePullPolicy: "Always",
+							ImagePullPolicy: getImagePullPolicy(cr, v1.PullAlways),
 						},
-					},
+					}, cr.Spec.KeycloakDeploymentSpec.Sidecars...),
 				},
 			},
 		},
@@ -189,12 +205,12 @@ func RHSSODeploymentSelector(cr *v1alpha1.Keycloak) client.ObjectKey {
 func RHSSODeploymentReconciled(cr *v1alpha1.Keycloak, currentState *v13.StatefulSet, dbSecret *v1.Secret) *v13.StatefulSet {
 	reconciled := currentState.DeepCopy()
 	reconciled.ResourceVersion = currentState.ResourceVersion
-	reconciled.Spec.Replicas = SanitizeNumberOfReplicas(cr.Spec.Instances, false)
+	reconciled.Spec.Replicas = getKeycloakReplicas(cr, currentState.Spec.Replicas)
 	reconciled.Spec.Template.Spec.Volumes = KeycloakVolumes(cr)
 	reconciled.Spec.Template.Spec.Containers = []v1.Container{
 		{
 			Name:    KeycloakDeploymentName,
-			Image:   Images.Images[RHSSOImage],
+			Image:   Profiles.GetKeycloakOrRHSSOImage(cr),
 			Args:    cr.Spec.KeycloakDeploymentSpec.Experimental.Args,
 			Command: cr.Spec.KeycloakDeploymentSpec.Experimental.Command,
 			Ports: []v1.ContainerPort{
@@ -216,14 +232,16 @@ func RHSSODeploymentReconciled(cr *v1alpha1.Keycloak, currentState *v13.Stateful
 				},
 			},
 			VolumeMounts:    KeycloakVolumeMounts(cr, RhssoExtensionPath),
-			LivenessProbe:   livenessProbe(),
-			ReadinessProbe:  readinessProbe(),
+			LivenessProbe:   livenessProbe(cr),
+			StartupProbe:    startupProbe(cr),
+			ReadinessProbe:  readinessProbe(cr),
 			Env:             getRHSSOEnv(cr, dbSecret),
 			Resources:       getResources(cr),
-			ImagePullPolicy: "Always",
+			ImagePullPolicy: getImagePullPolicy(cr, v1.PullAlways),
 		},
 	}
-	reconciled.Spec.Template.Spec.InitContainers = KeycloakExtensionsInitContainers(cr)
+	reconciled.Spec.Template.Spec.InitContainers = KeycloakInitContainers(cr)
+	reconcileKeycloakPodSpec(cr, reconciled)
 
 	return reconciled
 }
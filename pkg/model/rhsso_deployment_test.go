@@ -2,6 +2,10 @@ package model
 
 import (
 	"testing"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v13 "k8s.io/api/apps/v1"
 )
 
 func TestRHSSODeployment_testExperimentalEnvs(t *testing.T) {
@@ -19,3 +23,43 @@ func TestRHSSODeployment_testExperimentalCommand(t *testing.T) {
 func TestRHSSODeployment_testExperimentalVolumesWithConfigMaps(t *testing.T) {
 	testExperimentalVolumesWithConfigMaps(t, RHSSODeployment)
 }
+
+func TestRHSSODeployment_testScheduling(t *testing.T) {
+	testScheduling(t, RHSSODeployment)
+}
+
+func TestRHSSODeployment_testImageOverride(t *testing.T) {
+	testImageOverride(t, RHSSODeployment)
+}
+
+func TestRHSSODeployment_testSidecars(t *testing.T) {
+	testSidecars(t, RHSSODeployment, RHSSODeploymentReconciled)
+}
+
+func TestRHSSODeployment_testProbes(t *testing.T) {
+	testProbes(t, RHSSODeployment, RHSSODeploymentReconciled)
+}
+
+func TestRHSSODeployment_testClustering(t *testing.T) {
+	//given
+	cr := &v1alpha1.Keycloak{}
+	cr.Namespace = "keycloak"
+	cr.Spec.Clustering = &v1alpha1.KeycloakClustering{
+		DiscoveryProtocol:  v1alpha1.KubePingDiscoveryProtocol,
+		ServiceAccountName: "keycloak",
+	}
+
+	//when
+	created := RHSSODeployment(cr, nil)
+	reconciled := RHSSODeploymentReconciled(cr, created, nil)
+
+	//then
+	for _, deployment := range []*v13.StatefulSet{created, reconciled} {
+		envs := deployment.Spec.Template.Spec.Containers[0].Env
+		assert.Equal(t, "kubernetes.KUBE_PING", getEnvValueByName(envs, "JGROUPS_PING_PROTOCOL"))
+		assert.Equal(t, "keycloak", getEnvValueByName(envs, "KUBERNETES_NAMESPACE"))
+		assert.Equal(t, "component="+KeycloakDeploymentComponent, getEnvValueByName(envs, "KUBERNETES_LABELS"))
+		assert.Equal(t, "", getEnvValueByName(envs, "OPENSHIFT_DNS_PING_SERVICE_NAME"))
+		assert.Equal(t, "keycloak", deployment.Spec.Template.Spec.ServiceAccountName)
+	}
+}
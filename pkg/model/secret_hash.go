@@ -0,0 +1,17 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Hash of a value holding secrets, e.g. passwords, to tell its changes from the
+// status or an annotation. It is keyed by the resource the value belongs to, so
+// that the hash can't be compared against the hashes of guessed passwords, and the
+// same value hashes differently in every resource
+func SecretHash(key string, value []byte) string {
+	hash := hmac.New(sha256.New, []byte(key))
+	hash.Write(value)
+	return hex.EncodeToString(hash.Sum(nil))
+}
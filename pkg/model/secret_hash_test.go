@@ -0,0 +1,23 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretHash_Test_Keyed(t *testing.T) {
+	// given
+	value := []byte(`{"password":"secret"}`)
+	plain := sha256.Sum256(value)
+
+	// when
+	hash := SecretHash("realm-uid", value)
+
+	// then
+	assert.Equal(t, hash, SecretHash("realm-uid", value))
+	assert.NotEqual(t, hash, SecretHash("other-uid", value))
+	assert.NotEqual(t, hex.EncodeToString(plain[:]), hash)
+}
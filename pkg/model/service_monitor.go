@@ -13,15 +13,14 @@ func ServiceMonitor(cr *v1alpha1.Keycloak) *monitoringv1.ServiceMonitor {
 		ObjectMeta: v12.ObjectMeta{
 			Name:      ServiceMonitorName,
 			Namespace: cr.Namespace,
-			Labels: map[string]string{
-				"monitoring-key": MonitoringKey,
-			},
+			Labels:    getServiceMonitorLabels(cr),
 		},
 		Spec: monitoringv1.ServiceMonitorSpec{
 			Endpoints: []monitoringv1.Endpoint{{
-				Path:   "/auth/realms/master/metrics",
-				Port:   ApplicationName,
-				Scheme: "https",
+				Path:     "/auth/realms/master/metrics",
+				Port:     ApplicationName,
+				Scheme:   "https",
+				Interval: getServiceMonitorInterval(cr),
 				TLSConfig: &monitoringv1.TLSConfig{
 					InsecureSkipVerify: true,
 				},
@@ -41,3 +40,30 @@ func ServiceMonitorSelector(cr *v1alpha1.Keycloak) client.ObjectKey {
 		Namespace: cr.Namespace,
 	}
 }
+
+// The ServiceMonitor is created unless disabled explicitly
+func IsServiceMonitorEnabled(cr *v1alpha1.Keycloak) bool {
+	if cr.Spec.ServiceMonitor == nil || cr.Spec.ServiceMonitor.Enabled == nil {
+		return true
+	}
+	return *cr.Spec.ServiceMonitor.Enabled
+}
+
+func getServiceMonitorInterval(cr *v1alpha1.Keycloak) string {
+	if cr.Spec.ServiceMonitor == nil {
+		return ""
+	}
+	return cr.Spec.ServiceMonitor.Interval
+}
+
+// The monitoring key is what the rules and dashboards select on, so it can't be overridden
+func getServiceMonitorLabels(cr *v1alpha1.Keycloak) map[string]string {
+	labels := map[string]string{}
+	if cr.Spec.ServiceMonitor != nil {
+		for key, value := range cr.Spec.ServiceMonitor.Labels {
+			labels[key] = value
+		}
+	}
+	labels["monitoring-key"] = MonitoringKey
+	return labels
+}
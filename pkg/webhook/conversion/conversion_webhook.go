@@ -0,0 +1,131 @@
+package conversion
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1beta1"
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// Path the CRDs send their conversion reviews to
+const ConvertPath = "/convert"
+
+var log = logf.Log.WithName("webhook_conversion")
+
+// Add registers the conversion of KeycloakClients and KeycloakRealms with the
+// webhook server of the Manager
+func Add(mgr manager.Manager) error {
+	mgr.GetWebhookServer().Register(ConvertPath, &Webhook{})
+	return nil
+}
+
+// Webhook converts between the versions of the API. The ConversionReview of
+// apiextensions v1beta1 has the same fields as the one of v1, so both are served
+type Webhook struct{}
+
+func (w *Webhook) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	review := &apiextensionsv1.ConversionReview{}
+	if err := json.NewDecoder(req.Body).Decode(review); err != nil || review.Request == nil {
+		http.Error(rw, "expected a conversion review", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = convertReview(review.Request)
+	review.Request = nil
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(review); err != nil {
+		log.Error(err, "error writing the conversion response")
+	}
+}
+
+func convertReview(request *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	response := &apiextensionsv1.ConversionResponse{UID: request.UID}
+	for _, object := range request.Objects {
+		converted, err := Convert(object.Raw, request.DesiredAPIVersion)
+		if err != nil {
+			log.Error(err, "error converting", "apiVersion", request.DesiredAPIVersion)
+			response.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+			return response
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+	response.Result = metav1.Status{Status: metav1.StatusSuccess}
+	return response
+}
+
+// Convert converts a KeycloakClient or KeycloakRealm in JSON to the given API version
+func Convert(object []byte, apiVersion string) ([]byte, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(object, &typeMeta); err != nil {
+		return nil, err
+	}
+	if typeMeta.APIVersion == apiVersion {
+		return object, nil
+	}
+
+	toAlpha := apiVersion == v1alpha1.SchemeGroupVersion.String() && typeMeta.APIVersion == v1beta1.SchemeGroupVersion.String()
+	toBeta := apiVersion == v1beta1.SchemeGroupVersion.String() && typeMeta.APIVersion == v1alpha1.SchemeGroupVersion.String()
+	if !toAlpha && !toBeta {
+		return nil, errors.Errorf("cannot convert %v %v to %v", typeMeta.APIVersion, typeMeta.Kind, apiVersion)
+	}
+
+	switch typeMeta.Kind {
+	case "KeycloakClient":
+		return convertKeycloakClient(object, toBeta)
+	case "KeycloakRealm":
+		return convertKeycloakRealm(object, toBeta)
+	}
+	return nil, errors.Errorf("cannot convert %v, only KeycloakClient and KeycloakRealm have more than one version", typeMeta.Kind)
+}
+
+func convertKeycloakClient(object []byte, toBeta bool) ([]byte, error) {
+	alpha := &v1alpha1.KeycloakClient{}
+	beta := &v1beta1.KeycloakClient{}
+	if toBeta {
+		if err := json.Unmarshal(object, alpha); err != nil {
+			return nil, err
+		}
+		if err := beta.ConvertFrom(alpha); err != nil {
+			return nil, err
+		}
+		return json.Marshal(beta)
+	}
+
+	if err := json.Unmarshal(object, beta); err != nil {
+		return nil, err
+	}
+	if err := beta.ConvertTo(alpha); err != nil {
+		return nil, err
+	}
+	return json.Marshal(alpha)
+}
+
+func convertKeycloakRealm(object []byte, toBeta bool) ([]byte, error) {
+	alpha := &v1alpha1.KeycloakRealm{}
+	beta := &v1beta1.KeycloakRealm{}
+	if toBeta {
+		if err := json.Unmarshal(object, alpha); err != nil {
+			return nil, err
+		}
+		if err := beta.ConvertFrom(alpha); err != nil {
+			return nil, err
+		}
+		return json.Marshal(beta)
+	}
+
+	if err := json.Unmarshal(object, beta); err != nil {
+		return nil, err
+	}
+	if err := beta.ConvertTo(alpha); err != nil {
+		return nil, err
+	}
+	return json.Marshal(alpha)
+}
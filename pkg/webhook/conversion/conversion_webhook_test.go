@@ -0,0 +1,52 @@
+package conversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const alphaClient = `{"apiVersion":"keycloak.org/v1alpha1","kind":"KeycloakClient","metadata":{"name":"test"},` +
+	`"spec":{"client":{"clientId":"test"},"roles":[{"name":"admin","clientRole":true}]}}`
+
+func TestConversionWebhook_Test_Convert(t *testing.T) {
+	// given
+	review := `{"apiVersion":"apiextensions.k8s.io/v1","kind":"ConversionReview","request":{"uid":"1",` +
+		`"desiredAPIVersion":"keycloak.org/v1beta1","objects":[` + alphaClient + `]}}`
+	recorder := httptest.NewRecorder()
+
+	// when
+	(&Webhook{}).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, ConvertPath, bytes.NewBufferString(review)))
+
+	// then
+	response := &apiextensionsv1.ConversionReview{}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), response))
+	assert.Equal(t, "1", string(response.Response.UID))
+	assert.Equal(t, metav1.StatusSuccess, response.Response.Result.Status)
+	assert.Len(t, response.Response.ConvertedObjects, 1)
+
+	converted := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(response.Response.ConvertedObjects[0].Raw, &converted))
+	assert.Equal(t, "keycloak.org/v1beta1", converted["apiVersion"])
+	roles := converted["spec"].(map[string]interface{})["roles"].([]interface{})
+	assert.Equal(t, map[string]interface{}{"name": "admin"}, roles[0])
+
+	// when converting back
+	back, err := Convert(response.Response.ConvertedObjects[0].Raw, "keycloak.org/v1alpha1")
+
+	// then
+	assert.NoError(t, err)
+	assert.Contains(t, string(back), `"roles":[{"clientRole":true,"name":"admin"}]`)
+
+	// when the kind has a single version
+	_, err = Convert([]byte(`{"apiVersion":"keycloak.org/v1alpha1","kind":"Keycloak"}`), "keycloak.org/v1beta1")
+
+	// then
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+package keycloakclient
+
+import (
+	"context"
+	"net/http"
+
+	kc "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/common"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Path the ValidatingWebhookConfiguration sends the KeycloakClients to
+const ValidatePath = "/validate-keycloak-org-v1alpha1-keycloakclient"
+
+var log = logf.Log.WithName("webhook_keycloakclient")
+
+// Add registers the validation of KeycloakClients with the webhook server of the Manager
+func Add(mgr manager.Manager) error {
+	mgr.GetWebhookServer().Register(ValidatePath, &webhook.Admission{Handler: &Validator{}})
+	return nil
+}
+
+// Validator rejects KeycloakClients that would only fail once they are reconciled
+type Validator struct {
+	decoder *admission.Decoder
+}
+
+func (v *Validator) InjectDecoder(decoder *admission.Decoder) error {
+	v.decoder = decoder
+	return nil
+}
+
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	cr := &kc.KeycloakClient{}
+	if err := v.decoder.Decode(req, cr); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// A client that became invalid must still be deletable, which updates it to
+	// remove the finalizer
+	if cr.DeletionTimestamp != nil {
+		return admission.Allowed("")
+	}
+
+	if errs := ValidateKeycloakClient(cr); len(errs) > 0 {
+		log.Info("rejected keycloak client", "namespace", cr.Namespace, "name", cr.Name, "errors", errs.ToAggregate().Error())
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
+
+// ValidateKeycloakClient checks the spec the way the reconcile would fail on it
+func ValidateKeycloakClient(cr *kc.KeycloakClient) field.ErrorList {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+	clientPath := specPath.Child("client")
+
+	client := cr.Spec.Client
+	if client == nil {
+		return append(errs, field.Required(clientPath, "the client to manage must be set"))
+	}
+
+	if client.ClientID == "" {
+		errs = append(errs, field.Required(clientPath.Child("clientId"), "keycloak identifies clients by their client ID"))
+	}
+
+	switch client.Protocol {
+	case "", "openid-connect", "saml":
+	default:
+		errs = append(errs, field.NotSupported(clientPath.Child("protocol"), client.Protocol, []string{"openid-connect", "saml"}))
+	}
+
+	// Public clients authenticate without a secret, keycloak drops it
+	if client.PublicClient {
+		if client.Secret != "" {
+			errs = append(errs, field.Forbidden(clientPath.Child("secret"), "public clients have no secret"))
+		}
+		if cr.Spec.SecretRef != nil {
+			errs = append(errs, field.Forbidden(specPath.Child("secretRef"), "public clients have no secret"))
+		}
+	}
+
+	// ReconcileRoles matches roles by name, as the listType of the roles says
+	names := make(map[string]bool)
+	for index, role := range cr.Spec.Roles {
+		rolePath := specPath.Child("roles").Index(index).Child("name")
+		if role.Name == "" {
+			errs = append(errs, field.Required(rolePath, "roles are matched by name"))
+		} else if names[role.Name] {
+			errs = append(errs, field.Duplicate(rolePath, role.Name))
+		}
+		names[role.Name] = true
+	}
+
+	if err := common.ValidateRedirectURIs(client.RedirectUris, cr.Spec.RedirectURIValidation); err != nil {
+		errs = append(errs, field.Invalid(clientPath.Child("redirectUris"), client.RedirectUris, err.Error()))
+	}
+	if err := common.ValidateWebOrigins(client.WebOrigins); err != nil {
+		errs = append(errs, field.Invalid(clientPath.Child("webOrigins"), client.WebOrigins, err.Error()))
+	}
+
+	return errs
+}
@@ -0,0 +1,51 @@
+package keycloakclient
+
+import (
+	"testing"
+
+	"github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestKeycloakClientWebhook_Test_Validate(t *testing.T) {
+	// given
+	cr := &v1alpha1.KeycloakClient{
+		Spec: v1alpha1.KeycloakClientSpec{
+			Client: &v1alpha1.KeycloakAPIClient{
+				ClientID:     "test",
+				Protocol:     "openid-connect",
+				WebOrigins:   []string{"+"},
+				RedirectUris: []string{"https://example.com/*"},
+			},
+			Roles: []v1alpha1.RoleRepresentation{{Name: "admin"}, {Name: "user"}},
+		},
+	}
+
+	// then
+	assert.Empty(t, ValidateKeycloakClient(cr))
+
+	// when the spec is invalid
+	cr.Spec.Client.ClientID = ""
+	cr.Spec.Client.Protocol = "oidc"
+	cr.Spec.Client.PublicClient = true
+	cr.Spec.SecretRef = &v1.SecretKeySelector{Key: "secret"}
+	cr.Spec.Roles = append(cr.Spec.Roles, v1alpha1.RoleRepresentation{Name: "admin"})
+	cr.Spec.Client.WebOrigins = []string{"https://example.com/"}
+	errs := ValidateKeycloakClient(cr)
+
+	// then
+	assert.Len(t, errs, 5)
+	message := errs.ToAggregate().Error()
+	assert.Contains(t, message, "spec.client.clientId: Required value")
+	assert.Contains(t, message, "spec.client.protocol: Unsupported value: \"oidc\"")
+	assert.Contains(t, message, "spec.secretRef: Forbidden: public clients have no secret")
+	assert.Contains(t, message, "spec.roles[2].name: Duplicate value: \"admin\"")
+	assert.Contains(t, message, "spec.client.webOrigins: Invalid value")
+
+	// when the client is missing
+	cr.Spec.Client = nil
+
+	// then
+	assert.Len(t, ValidateKeycloakClient(cr), 1)
+}
@@ -52,6 +52,7 @@ func getKeycloakClientCR(namespace string, external bool) *keycloakv1alpha1.Keyc
 		id = externalClientName
 		labels = CreateExternalLabel(namespace)
 	}
+	enabled := true
 
 	return &keycloakv1alpha1.KeycloakClient{
 		ObjectMeta: metav1.ObjectMeta{
@@ -67,21 +68,21 @@ func getKeycloakClientCR(namespace string, external bool) *keycloakv1alpha1.Keyc
 				ID:                        id,
 				ClientID:                  id,
 				Name:                      id,
-				SurrogateAuthRequired:     false,
-				Enabled:                   true,
+				SurrogateAuthRequired:     &[]bool{false}[0],
+				Enabled:                   &enabled,
 				BaseURL:                   "https://operator-test.url/client-base-url",
 				AdminURL:                  "https://operator-test.url/client-admin-url",
 				RootURL:                   "https://operator-test.url/client-root-url",
 				Description:               "Client used within operator tests",
 				WebOrigins:                []string{"https://operator-test.url"},
-				BearerOnly:                false,
+				BearerOnly:                &[]bool{false}[0],
 				ConsentRequired:           false,
 				StandardFlowEnabled:       true,
 				ImplicitFlowEnabled:       false,
 				DirectAccessGrantsEnabled: true,
-				ServiceAccountsEnabled:    false,
+				ServiceAccountsEnabled:    &[]bool{false}[0],
 				PublicClient:              true,
-				FrontchannelLogout:        false,
+				FrontchannelLogout:        &[]bool{false}[0],
 				Protocol:                  "openid-connect",
 				FullScopeAllowed:          &[]bool{true}[0],
 				NodeReRegistrationTimeout: -1,
@@ -174,7 +174,7 @@ func keycloakRealmWithClientScopesTest(t *testing.T, framework *test.Framework,
 	}
 
 	keycloakRealmCR.Spec.Realm.IdentityProviders = []*keycloakv1alpha1.KeycloakIdentityProvider{identityProvider}
-	keycloakRealmCR.Spec.Realm.ClientScopes = []keycloakv1alpha1.KeycloakClientScope{
+	keycloakRealmCR.Spec.Realm.ClientScopes = []keycloakv1alpha1.KeycloakAPIClientScope{
 		{
 			Name:        "profile",
 			Description: "subset of the built in profile scope, for e2e testing",